@@ -0,0 +1,275 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/khekrn/core/response"
+)
+
+// Default locations an API key is read from
+const (
+	DefaultAPIKeyHeader = "X-API-Key"
+)
+
+// API key errors
+var (
+	ErrAPIKeyNotFound = errors.New("auth: API key not found")
+	ErrAPIKeyExpired  = errors.New("auth: API key expired")
+	ErrAPIKeyRevoked  = errors.New("auth: API key revoked")
+)
+
+// APIKey describes a stored API key. Only the hash of the key is kept;
+// the plaintext is shown once when the key is generated.
+type APIKey struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	Scopes    []string  `json:"scopes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	Revoked   bool      `json:"revoked,omitempty"`
+}
+
+// HasScope reports whether the key grants scope. The "*" scope grants everything.
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope) || slices.Contains(k.Scopes, "*")
+}
+
+// Matches compares plaintext against the stored hash in constant time
+func (k *APIKey) Matches(plaintext string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIKey(plaintext)), []byte(k.Hash)) == 1
+}
+
+// valid checks the expiry and revocation state of the key
+func (k *APIKey) valid() error {
+	if k.Revoked {
+		return ErrAPIKeyRevoked
+	}
+	if !k.ExpiresAt.IsZero() && time.Now().After(k.ExpiresAt) {
+		return ErrAPIKeyExpired
+	}
+	return nil
+}
+
+// HashAPIKey returns the hex SHA-256 hash used to store and look up a key.
+// API keys are high-entropy random values, so a fast hash is sufficient.
+func HashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateAPIKey creates a new random key with the given prefix (e.g. "sk_live")
+// and returns the plaintext together with the record to store
+func GenerateAPIKey(prefix, name string, scopes ...string) (string, *APIKey, error) {
+	id := make([]byte, 8)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	plaintext := hex.EncodeToString(secret)
+	if prefix != "" {
+		plaintext = prefix + "_" + plaintext
+	}
+
+	return plaintext, &APIKey{
+		ID:        hex.EncodeToString(id),
+		Name:      name,
+		Hash:      HashAPIKey(plaintext),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// APIKeyStore looks up API keys by hash
+type APIKeyStore interface {
+	// Lookup returns the key with the given hash or ErrAPIKeyNotFound
+	Lookup(ctx context.Context, hash string) (*APIKey, error)
+}
+
+// MemoryAPIKeyStore is an in-memory APIKeyStore, suitable for keys loaded from configuration
+type MemoryAPIKeyStore struct {
+	mu   sync.RWMutex
+	keys map[string]*APIKey
+}
+
+// NewMemoryAPIKeyStore creates a store holding the given keys
+func NewMemoryAPIKeyStore(keys ...*APIKey) *MemoryAPIKeyStore {
+	s := &MemoryAPIKeyStore{keys: make(map[string]*APIKey)}
+	for _, key := range keys {
+		s.Add(key)
+	}
+	return s
+}
+
+// Add stores a key, replacing any key with the same hash
+func (s *MemoryAPIKeyStore) Add(key *APIKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key.Hash] = key
+}
+
+// Remove deletes the key with the given ID
+func (s *MemoryAPIKeyStore) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for hash, key := range s.keys {
+		if key.ID == id {
+			delete(s.keys, hash)
+		}
+	}
+}
+
+// Lookup returns the key with the given hash
+func (s *MemoryAPIKeyStore) Lookup(ctx context.Context, hash string) (*APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[hash]
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+// StaticAPIKeys builds a store from plaintext keys mapped to their scopes,
+// for services configured with a handful of shared keys
+func StaticAPIKeys(keys map[string][]string) *MemoryAPIKeyStore {
+	s := NewMemoryAPIKeyStore()
+	for plaintext, scopes := range keys {
+		hash := HashAPIKey(plaintext)
+		s.Add(&APIKey{ID: hash[:16], Hash: hash, Scopes: scopes})
+	}
+	return s
+}
+
+// APIKeyConfig holds configuration for the API key middleware
+type APIKeyConfig struct {
+	Header     string
+	QueryParam string
+	Scopes     []string
+}
+
+// APIKeyOption is a function type for configuring the API key middleware
+type APIKeyOption func(*APIKeyConfig)
+
+// WithAPIKeyHeader sets the header the key is read from
+func WithAPIKeyHeader(header string) APIKeyOption {
+	return func(config *APIKeyConfig) {
+		config.Header = header
+	}
+}
+
+// WithAPIKeyQueryParam also reads the key from a query parameter.
+// Query parameters end up in access logs, so prefer headers where possible.
+func WithAPIKeyQueryParam(param string) APIKeyOption {
+	return func(config *APIKeyConfig) {
+		config.QueryParam = param
+	}
+}
+
+// WithRequiredScopes requires the key to grant all of the given scopes
+func WithRequiredScopes(scopes ...string) APIKeyOption {
+	return func(config *APIKeyConfig) {
+		config.Scopes = scopes
+	}
+}
+
+// apiKeyContextKey is the context key for the authenticated API key
+type apiKeyContextKey struct{}
+
+// APIKeyFromContext returns the API key authenticated by APIKeyMiddleware
+func APIKeyFromContext(ctx context.Context) (*APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(*APIKey)
+	return key, ok
+}
+
+// Authenticate resolves a plaintext key against the store and checks its state
+func Authenticate(ctx context.Context, store APIKeyStore, plaintext string) (*APIKey, error) {
+	key, err := store.Lookup(ctx, HashAPIKey(plaintext))
+	if err != nil {
+		return nil, err
+	}
+	// Guard against stores that match on something other than the exact hash
+	if !key.Matches(plaintext) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err := key.valid(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// APIKeyMiddleware authenticates requests by API key and stores the key in
+// the request context. Missing or invalid keys get 401, missing scopes 403.
+func APIKeyMiddleware(store APIKeyStore, options ...APIKeyOption) func(http.Handler) http.Handler {
+	config := APIKeyConfig{Header: DefaultAPIKeyHeader}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			plaintext := r.Header.Get(config.Header)
+			if plaintext == "" && config.QueryParam != "" {
+				plaintext = r.URL.Query().Get(config.QueryParam)
+			}
+			if plaintext == "" {
+				response.WriteError(w, r, response.NewAPIError(response.CodeUnauthorized, "API key is required", nil))
+				return
+			}
+
+			key, err := Authenticate(r.Context(), store, plaintext)
+			if err != nil {
+				if errors.Is(err, ErrAPIKeyNotFound) || errors.Is(err, ErrAPIKeyExpired) || errors.Is(err, ErrAPIKeyRevoked) {
+					response.WriteError(w, r, response.NewAPIError(response.CodeUnauthorized, "Invalid API key", err))
+					return
+				}
+				response.WriteError(w, r, response.NewAPIError(response.CodeInternal, "Failed to verify API key", err))
+				return
+			}
+
+			for _, scope := range config.Scopes {
+				if !key.HasScope(scope) {
+					response.WriteError(w, r, response.NewAPIError(response.CodeForbidden, "API key is missing required scope: "+scope, nil))
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+		})
+	}
+}
+
+// RequireScopes rejects requests whose API key lacks any of the given scopes.
+// Use it on individual routes behind APIKeyMiddleware.
+func RequireScopes(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := APIKeyFromContext(r.Context())
+			if !ok {
+				response.WriteError(w, r, response.NewAPIError(response.CodeUnauthorized, "API key is required", nil))
+				return
+			}
+			for _, scope := range scopes {
+				if !key.HasScope(scope) {
+					response.WriteError(w, r, response.NewAPIError(response.CodeForbidden, "API key is missing required scope: "+scope, nil))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
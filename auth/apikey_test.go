@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	plaintext, key, err := GenerateAPIKey("sk_test", "billing", "invoices:read")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewMemoryAPIKeyStore(key)
+
+	handler := APIKeyMiddleware(store, WithAPIKeyQueryParam("api_key"))(
+		RequireScopes("invoices:read")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authenticated, ok := APIKeyFromContext(r.Context())
+			if !ok || authenticated.ID != key.ID {
+				t.Errorf("Expected key %s in context", key.ID)
+			}
+			w.WriteHeader(http.StatusOK)
+		})),
+	)
+
+	tests := []struct {
+		name     string
+		target   string
+		header   string
+		expected int
+	}{
+		{"header", "/", plaintext, http.StatusOK},
+		{"query", "/?api_key=" + plaintext, "", http.StatusOK},
+		{"missing", "/", "", http.StatusUnauthorized},
+		{"invalid", "/", "sk_test_wrong", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set(DefaultAPIKeyHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, rec.Code)
+			}
+		})
+	}
+}
+
+func TestAPIKeyMiddleware_MissingScope(t *testing.T) {
+	store := StaticAPIKeys(map[string][]string{"shared-key": {"orders:read"}})
+	handler := APIKeyMiddleware(store, WithRequiredScopes("orders:write"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler should not be called")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(DefaultAPIKeyHeader, "shared-key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"code":"ERR_FORBIDDEN"`) {
+		t.Errorf("Expected error code ERR_FORBIDDEN, got %s", rec.Body.String())
+	}
+}
+
+func TestAuthenticate_ExpiredAndRevoked(t *testing.T) {
+	plaintext, key, _ := GenerateAPIKey("", "legacy")
+	key.ExpiresAt = time.Now().Add(-time.Minute)
+	store := NewMemoryAPIKeyStore(key)
+
+	if _, err := Authenticate(context.Background(), store, plaintext); !errors.Is(err, ErrAPIKeyExpired) {
+		t.Errorf("Expected ErrAPIKeyExpired, got %v", err)
+	}
+
+	key.ExpiresAt = time.Time{}
+	key.Revoked = true
+	if _, err := Authenticate(context.Background(), store, plaintext); !errors.Is(err, ErrAPIKeyRevoked) {
+		t.Errorf("Expected ErrAPIKeyRevoked, got %v", err)
+	}
+}
@@ -0,0 +1,352 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+)
+
+// JWK is a single JSON Web Key
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// JWKSet is a JSON Web Key Set as served from a JWKS endpoint
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicKey decodes the JWK into an *rsa.PublicKey or *ecdsa.PublicKey
+func (k JWK) PublicKey() (any, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeSegment(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := decodeSegment(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+
+	case "EC":
+		if k.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x coordinate: %w", err)
+		}
+		y, err := decodeSegment(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y coordinate: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+// NewJWK encodes a public key as a JWK. Symmetric keys cannot be published.
+func NewJWK(kid, algorithm string, key any) (JWK, error) {
+	switch k := publicKey(key).(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			KeyType:   "RSA",
+			KeyID:     kid,
+			Algorithm: algorithm,
+			Use:       "sig",
+			N:         encodeSegment(k.N.Bytes()),
+			E:         encodeSegment(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+
+	case *ecdsa.PublicKey:
+		x := make([]byte, 32)
+		y := make([]byte, 32)
+		k.X.FillBytes(x)
+		k.Y.FillBytes(y)
+		return JWK{
+			KeyType:   "EC",
+			KeyID:     kid,
+			Algorithm: algorithm,
+			Use:       "sig",
+			Curve:     "P-256",
+			X:         encodeSegment(x),
+			Y:         encodeSegment(y),
+		}, nil
+
+	default:
+		return JWK{}, fmt.Errorf("%w: cannot publish %T as a JWK", ErrUnsupportedAlg, key)
+	}
+}
+
+// JWKS returns the public keys of the current and previous signing keys.
+// HMAC keys are never published.
+func (i *TokenIssuer) JWKS() JWKSet {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range append([]SigningKey{i.current}, i.previous...) {
+		if key.Algorithm == HS256 {
+			continue
+		}
+		jwk, err := NewJWK(key.ID, key.Algorithm, key.Key)
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// JWKSHandler serves the issuer's public keys, typically at /.well-known/jwks.json
+func (i *TokenIssuer) JWKSHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_ = json.NewEncoder(w).Encode(i.JWKS())
+	})
+}
+
+// JWKSConfig holds configuration for a JWKSCache
+type JWKSConfig struct {
+	HTTPClient      *http.Client
+	RefreshInterval time.Duration
+	MinRefreshDelay time.Duration
+}
+
+// JWKSOption is a function type for configuring a JWKSCache
+type JWKSOption func(*JWKSConfig)
+
+// WithHTTPClient sets the HTTP client used to fetch the key set
+func WithHTTPClient(httpClient *http.Client) JWKSOption {
+	return func(config *JWKSConfig) {
+		config.HTTPClient = httpClient
+	}
+}
+
+// WithRefreshInterval sets how often the key set is refreshed in the background
+func WithRefreshInterval(interval time.Duration) JWKSOption {
+	return func(config *JWKSConfig) {
+		config.RefreshInterval = interval
+	}
+}
+
+// WithMinRefreshDelay sets the minimum delay between refreshes triggered by unknown key IDs
+func WithMinRefreshDelay(delay time.Duration) JWKSOption {
+	return func(config *JWKSConfig) {
+		config.MinRefreshDelay = delay
+	}
+}
+
+// JWKSCache is a KeySource backed by a remote JWKS endpoint. Keys are
+// refreshed in the background, and an unknown kid triggers an immediate
+// (rate limited) refresh so newly rotated keys are picked up quickly.
+// Failed refreshes count towards the rate limit, so unknown kids cannot
+// flood an unavailable endpoint.
+type JWKSCache struct {
+	url    string
+	config JWKSConfig
+
+	mu          sync.RWMutex
+	keys        map[string]any
+	lastRefresh time.Time    // Start of the last refresh attempt, successful or not
+	refreshing  *jwksRefresh // Refresh in progress, shared by concurrent callers
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// jwksRefresh is a Refresh call in progress
+type jwksRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// NewJWKSCache creates a cache for the key set at url and starts the background refresh
+func NewJWKSCache(url string, options ...JWKSOption) *JWKSCache {
+	config := JWKSConfig{
+		HTTPClient:      &http.Client{Timeout: 10 * time.Second},
+		RefreshInterval: 15 * time.Minute,
+		MinRefreshDelay: 30 * time.Second,
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	c := &JWKSCache{
+		url:    url,
+		config: config,
+		keys:   make(map[string]any),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go c.refreshLoop()
+	return c
+}
+
+// Key returns the key for kid, refreshing the key set if it is unknown
+func (c *JWKSCache) Key(ctx context.Context, kid, algorithm string) (any, error) {
+	if key, ok := c.lookup(kid); ok {
+		return key, nil
+	}
+
+	c.mu.RLock()
+	recent := !c.lastRefresh.IsZero() && time.Since(c.lastRefresh) < c.config.MinRefreshDelay
+	pending := c.refreshing != nil
+	c.mu.RUnlock()
+
+	// Join a refresh in progress rather than rejecting a key it may bring in
+	if !recent || pending {
+		if err := c.Refresh(ctx); err != nil {
+			return nil, err
+		}
+		if key, ok := c.lookup(kid); ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+}
+
+// Refresh fetches the key set immediately. Concurrent calls share one
+// fetch, which runs with the context of the first caller.
+func (c *JWKSCache) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	if pending := c.refreshing; pending != nil {
+		c.mu.Unlock()
+		select {
+		case <-pending.done:
+			return pending.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	pending := &jwksRefresh{done: make(chan struct{})}
+	c.refreshing = pending
+	c.lastRefresh = time.Now()
+	c.mu.Unlock()
+
+	pending.err = c.fetch(ctx)
+
+	c.mu.Lock()
+	c.refreshing = nil
+	c.mu.Unlock()
+	close(pending.done)
+	return pending.err
+}
+
+// fetch downloads the key set and replaces the cached keys
+func (c *JWKSCache) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.config.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var set JWKSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := jwk.PublicKey()
+		if err != nil {
+			// Skip keys we cannot use rather than rejecting the whole set
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+
+	c.mu.Lock()
+	c.keys = keys
+	c.mu.Unlock()
+	return nil
+}
+
+// Close stops the background refresh
+func (c *JWKSCache) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		<-c.done
+	})
+	return nil
+}
+
+// lookup returns a cached key
+func (c *JWKSCache) lookup(kid string) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+// refreshLoop refreshes the key set until Close is called
+func (c *JWKSCache) refreshLoop() {
+	defer close(c.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.stop
+		cancel()
+	}()
+
+	c.refreshAndLog(ctx)
+
+	ticker := time.NewTicker(c.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.refreshAndLog(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refreshAndLog refreshes the key set, logging failures. Stale keys are kept on error.
+func (c *JWKSCache) refreshAndLog(ctx context.Context) {
+	if err := c.Refresh(ctx); err != nil && ctx.Err() == nil {
+		logger.Warn("Failed to refresh JWKS", zap.String("url", c.url), zap.Error(err))
+	}
+}
@@ -0,0 +1,530 @@
+// Package auth provides authentication primitives shared by inbound
+// middleware and outbound clients: JWT issuing and verification with
+// key rotation and JWKS caching, and static API keys between services.
+//
+// Tokens are signed with RS256, ES256, or HS256. Every signing key has a key
+// ID (kid) so keys can be rotated without invalidating tokens that are still
+// in flight; verifiers resolve the kid through a KeySource such as a static
+// key set or a remote JWKS endpoint that is refreshed in the background.
+//
+// Example usage:
+//
+//	type UserClaims struct {
+//		auth.RegisteredClaims
+//		Roles []string `json:"roles"`
+//	}
+//
+//	issuer := auth.NewTokenIssuer(auth.SigningKey{ID: "2024-01", Algorithm: auth.RS256, Key: privateKey},
+//		auth.WithIssuer("https://auth.example.com"),
+//		auth.WithTokenTTL(15*time.Minute),
+//	)
+//	token, err := issuer.Issue(&UserClaims{
+//		RegisteredClaims: auth.RegisteredClaims{Subject: "user-123"},
+//		Roles:            []string{"admin"},
+//	})
+//
+//	jwks := auth.NewJWKSCache("https://auth.example.com/.well-known/jwks.json")
+//	defer jwks.Close()
+//
+//	verifier := auth.NewVerifier(jwks, auth.WithExpectedIssuer("https://auth.example.com"))
+//	claims, err := auth.Verify[UserClaims](ctx, verifier, token)
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Supported signing algorithms
+const (
+	RS256 = "RS256" // RSASSA-PKCS1-v1_5 with SHA-256
+	ES256 = "ES256" // ECDSA P-256 with SHA-256
+	HS256 = "HS256" // HMAC with SHA-256
+)
+
+// Token validation errors
+var (
+	ErrInvalidToken     = errors.New("auth: invalid token")
+	ErrInvalidSignature = errors.New("auth: invalid token signature")
+	ErrTokenExpired     = errors.New("auth: token expired")
+	ErrTokenNotYetValid = errors.New("auth: token not yet valid")
+	ErrInvalidIssuer    = errors.New("auth: invalid token issuer")
+	ErrInvalidAudience  = errors.New("auth: invalid token audience")
+	ErrUnknownKey       = errors.New("auth: unknown signing key")
+	ErrUnsupportedAlg   = errors.New("auth: unsupported signing algorithm")
+)
+
+// Audience holds the "aud" claim, which may be encoded as a string or an array
+type Audience []string
+
+// UnmarshalJSON accepts both a single string and an array of strings
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("invalid audience: %w", err)
+	}
+	*a = multiple
+	return nil
+}
+
+// MarshalJSON encodes a single audience as a plain string
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// RegisteredClaims holds the standard JWT claims. Embed it in custom claim structs.
+type RegisteredClaims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+// Registered returns the standard claims
+func (c *RegisteredClaims) Registered() *RegisteredClaims {
+	return c
+}
+
+// Claims is implemented by any struct embedding RegisteredClaims
+type Claims interface {
+	Registered() *RegisteredClaims
+}
+
+// SigningKey is a private (or shared secret) key used to sign tokens.
+// Key must be []byte for HS256, *rsa.PrivateKey for RS256, and
+// *ecdsa.PrivateKey for ES256.
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	Key       any
+}
+
+// header is the JOSE header of a token
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// IssuerConfig holds configuration for a TokenIssuer
+type IssuerConfig struct {
+	Issuer   string
+	Audience []string
+	TTL      time.Duration
+}
+
+// IssuerOption is a function type for configuring a TokenIssuer
+type IssuerOption func(*IssuerConfig)
+
+// WithIssuer sets the "iss" claim of issued tokens
+func WithIssuer(issuer string) IssuerOption {
+	return func(config *IssuerConfig) {
+		config.Issuer = issuer
+	}
+}
+
+// WithAudience sets the default "aud" claim of issued tokens
+func WithAudience(audience ...string) IssuerOption {
+	return func(config *IssuerConfig) {
+		config.Audience = audience
+	}
+}
+
+// WithTokenTTL sets the lifetime of issued tokens
+func WithTokenTTL(ttl time.Duration) IssuerOption {
+	return func(config *IssuerConfig) {
+		config.TTL = ttl
+	}
+}
+
+// TokenIssuer signs tokens with the current key and keeps previous
+// public keys available for verification during rotation
+type TokenIssuer struct {
+	mu       sync.RWMutex
+	config   IssuerConfig
+	current  SigningKey
+	previous []SigningKey
+}
+
+// NewTokenIssuer creates an issuer that signs with the given key
+func NewTokenIssuer(key SigningKey, options ...IssuerOption) *TokenIssuer {
+	config := IssuerConfig{TTL: time.Hour}
+	for _, opt := range options {
+		opt(&config)
+	}
+	return &TokenIssuer{config: config, current: key}
+}
+
+// Rotate makes key the signing key. The previous key remains published in
+// the JWKS until it is removed with Retire.
+func (i *TokenIssuer) Rotate(key SigningKey) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.previous = append(i.previous, i.current)
+	i.current = key
+}
+
+// Retire removes a previous key from the published key set
+func (i *TokenIssuer) Retire(kid string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.previous = slices.DeleteFunc(i.previous, func(k SigningKey) bool { return k.ID == kid })
+}
+
+// Issue fills in the issuer, audience, and time claims that are not already
+// set and returns the signed token
+func (i *TokenIssuer) Issue(claims Claims) (string, error) {
+	i.mu.RLock()
+	key := i.current
+	config := i.config
+	i.mu.RUnlock()
+
+	now := time.Now()
+	registered := claims.Registered()
+	if registered.Issuer == "" {
+		registered.Issuer = config.Issuer
+	}
+	if len(registered.Audience) == 0 {
+		registered.Audience = config.Audience
+	}
+	if registered.IssuedAt == 0 {
+		registered.IssuedAt = now.Unix()
+	}
+	if registered.ExpiresAt == 0 && config.TTL > 0 {
+		registered.ExpiresAt = now.Add(config.TTL).Unix()
+	}
+
+	return Sign(key, claims)
+}
+
+// Sign encodes and signs arbitrary claims with the given key
+func Sign(key SigningKey, claims any) (string, error) {
+	headerJSON, err := json.Marshal(header{Algorithm: key.Algorithm, Type: "JWT", KeyID: key.ID})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal header: %w", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal claims: %w", err)
+	}
+
+	signingInput := encodeSegment(headerJSON) + "." + encodeSegment(claimsJSON)
+	signature, err := sign(key, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + encodeSegment(signature), nil
+}
+
+// sign produces the raw signature for the signing input
+func sign(key SigningKey, input []byte) ([]byte, error) {
+	digest := sha256.Sum256(input)
+
+	switch key.Algorithm {
+	case HS256:
+		secret, ok := key.Key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%w: HS256 requires a []byte key", ErrUnsupportedAlg)
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+
+	case RS256:
+		private, ok := key.Key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: RS256 requires an *rsa.PrivateKey", ErrUnsupportedAlg)
+		}
+		return rsa.SignPKCS1v15(rand.Reader, private, crypto.SHA256, digest[:])
+
+	case ES256:
+		private, ok := key.Key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: ES256 requires an *ecdsa.PrivateKey", ErrUnsupportedAlg)
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, private, digest[:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign token: %w", err)
+		}
+		// JWS encodes ECDSA signatures as fixed-size r || s
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		return signature, nil
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlg, key.Algorithm)
+	}
+}
+
+// KeySource resolves verification keys by key ID
+type KeySource interface {
+	// Key returns the public key (or HMAC secret) for kid and algorithm
+	Key(ctx context.Context, kid, algorithm string) (any, error)
+}
+
+// StaticKeys is a KeySource backed by a fixed map of key ID to public key or secret
+type StaticKeys map[string]any
+
+// Key returns the key registered under kid
+func (s StaticKeys) Key(ctx context.Context, kid, algorithm string) (any, error) {
+	key, ok := s[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
+
+// KeySource returns the issuer's current and previous keys for local verification
+func (i *TokenIssuer) KeySource() KeySource {
+	return issuerKeys{issuer: i}
+}
+
+// issuerKeys resolves keys from a TokenIssuer, including rotated-out keys
+type issuerKeys struct {
+	issuer *TokenIssuer
+}
+
+// Key returns the verification key for kid
+func (k issuerKeys) Key(ctx context.Context, kid, algorithm string) (any, error) {
+	k.issuer.mu.RLock()
+	defer k.issuer.mu.RUnlock()
+
+	for _, key := range append([]SigningKey{k.issuer.current}, k.issuer.previous...) {
+		if key.ID == kid {
+			return publicKey(key.Key), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+}
+
+// publicKey returns the public half of a private key, or the key unchanged
+func publicKey(key any) any {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey
+	case *ecdsa.PrivateKey:
+		return &k.PublicKey
+	default:
+		return key
+	}
+}
+
+// VerifierConfig holds configuration for a Verifier
+type VerifierConfig struct {
+	Issuer     string
+	Audience   string
+	Leeway     time.Duration
+	Algorithms []string
+}
+
+// VerifierOption is a function type for configuring a Verifier
+type VerifierOption func(*VerifierConfig)
+
+// WithExpectedIssuer requires the "iss" claim to match
+func WithExpectedIssuer(issuer string) VerifierOption {
+	return func(config *VerifierConfig) {
+		config.Issuer = issuer
+	}
+}
+
+// WithExpectedAudience requires the "aud" claim to contain audience
+func WithExpectedAudience(audience string) VerifierOption {
+	return func(config *VerifierConfig) {
+		config.Audience = audience
+	}
+}
+
+// WithLeeway allows for clock skew when checking time claims
+func WithLeeway(leeway time.Duration) VerifierOption {
+	return func(config *VerifierConfig) {
+		config.Leeway = leeway
+	}
+}
+
+// WithAlgorithms restricts the accepted signing algorithms
+func WithAlgorithms(algorithms ...string) VerifierOption {
+	return func(config *VerifierConfig) {
+		config.Algorithms = algorithms
+	}
+}
+
+// Verifier validates token signatures and standard claims
+type Verifier struct {
+	keys   KeySource
+	config VerifierConfig
+}
+
+// NewVerifier creates a verifier resolving keys from the given source
+func NewVerifier(keys KeySource, options ...VerifierOption) *Verifier {
+	config := VerifierConfig{
+		Leeway:     30 * time.Second,
+		Algorithms: []string{RS256, ES256, HS256},
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+	return &Verifier{keys: keys, config: config}
+}
+
+// Verify checks the token and decodes its claims into claims
+func (v *Verifier) Verify(ctx context.Context, token string, claims any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("%w: expected 3 segments", ErrInvalidToken)
+	}
+
+	headerJSON, err := decodeSegment(parts[0])
+	if err != nil {
+		return fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	var h header
+	if err := json.Unmarshal(headerJSON, &h); err != nil {
+		return fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	if !slices.Contains(v.config.Algorithms, h.Algorithm) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedAlg, h.Algorithm)
+	}
+
+	signature, err := decodeSegment(parts[2])
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+
+	key, err := v.keys.Key(ctx, h.KeyID, h.Algorithm)
+	if err != nil {
+		return err
+	}
+	if err := verifySignature(h.Algorithm, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return err
+	}
+
+	claimsJSON, err := decodeSegment(parts[1])
+	if err != nil {
+		return fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+
+	var registered RegisteredClaims
+	if err := json.Unmarshal(claimsJSON, &registered); err != nil {
+		return fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	if err := v.validate(&registered); err != nil {
+		return err
+	}
+
+	if claims != nil {
+		if err := json.Unmarshal(claimsJSON, claims); err != nil {
+			return fmt.Errorf("%w: failed to decode claims: %v", ErrInvalidToken, err)
+		}
+	}
+	return nil
+}
+
+// Verify checks the token and returns its claims decoded as T
+func Verify[T any](ctx context.Context, v *Verifier, token string) (*T, error) {
+	var claims T
+	if err := v.Verify(ctx, token, &claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// validate checks the time, issuer, and audience claims
+func (v *Verifier) validate(claims *RegisteredClaims) error {
+	now := time.Now()
+
+	if claims.ExpiresAt != 0 && now.After(time.Unix(claims.ExpiresAt, 0).Add(v.config.Leeway)) {
+		return ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Before(time.Unix(claims.NotBefore, 0).Add(-v.config.Leeway)) {
+		return ErrTokenNotYetValid
+	}
+	if v.config.Issuer != "" && claims.Issuer != v.config.Issuer {
+		return ErrInvalidIssuer
+	}
+	if v.config.Audience != "" && !slices.Contains(claims.Audience, v.config.Audience) {
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+// verifySignature checks a signature against the signing input
+func verifySignature(algorithm string, key any, input, signature []byte) error {
+	digest := sha256.Sum256(input)
+
+	switch algorithm {
+	case HS256:
+		secret, ok := key.([]byte)
+		if !ok {
+			return fmt.Errorf("%w: key type does not match HS256", ErrInvalidSignature)
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(input)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	case RS256:
+		public, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: key type does not match RS256", ErrInvalidSignature)
+		}
+		if err := rsa.VerifyPKCS1v15(public, crypto.SHA256, digest[:], signature); err != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	case ES256:
+		public, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return fmt.Errorf("%w: key type does not match ES256", ErrInvalidSignature)
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(public, digest[:], r, s) {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedAlg, algorithm)
+	}
+}
+
+// encodeSegment base64url-encodes a token segment without padding
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSegment decodes a base64url token segment without padding
+func decodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(segment)
+}
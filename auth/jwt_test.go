@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testClaims struct {
+	RegisteredClaims
+	Roles []string `json:"roles"`
+}
+
+func TestIssueAndVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []SigningKey{
+		{ID: "hs", Algorithm: HS256, Key: []byte("secret")},
+		{ID: "rs", Algorithm: RS256, Key: rsaKey},
+		{ID: "es", Algorithm: ES256, Key: ecKey},
+	}
+
+	for _, key := range keys {
+		t.Run(key.Algorithm, func(t *testing.T) {
+			issuer := NewTokenIssuer(key, WithIssuer("core"), WithAudience("api"))
+			token, err := issuer.Issue(&testClaims{
+				RegisteredClaims: RegisteredClaims{Subject: "user-1"},
+				Roles:            []string{"admin"},
+			})
+			if err != nil {
+				t.Fatalf("Issue failed: %v", err)
+			}
+
+			verifier := NewVerifier(issuer.KeySource(), WithExpectedIssuer("core"), WithExpectedAudience("api"))
+			claims, err := Verify[testClaims](context.Background(), verifier, token)
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if claims.Subject != "user-1" || len(claims.Roles) != 1 || claims.Roles[0] != "admin" {
+				t.Errorf("Unexpected claims: %+v", claims)
+			}
+
+			tampered := token[:len(token)-4] + "AAAA"
+			if err := verifier.Verify(context.Background(), tampered, nil); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerify_ClaimValidation(t *testing.T) {
+	key := SigningKey{ID: "k1", Algorithm: HS256, Key: []byte("secret")}
+	verifier := NewVerifier(StaticKeys{"k1": []byte("secret")}, WithLeeway(0), WithExpectedIssuer("core"))
+
+	expired, _ := Sign(key, RegisteredClaims{Issuer: "core", ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+	if err := verifier.Verify(context.Background(), expired, nil); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Expected ErrTokenExpired, got %v", err)
+	}
+
+	future, _ := Sign(key, RegisteredClaims{Issuer: "core", NotBefore: time.Now().Add(time.Hour).Unix()})
+	if err := verifier.Verify(context.Background(), future, nil); !errors.Is(err, ErrTokenNotYetValid) {
+		t.Errorf("Expected ErrTokenNotYetValid, got %v", err)
+	}
+
+	wrongIssuer, _ := Sign(key, RegisteredClaims{Issuer: "other"})
+	if err := verifier.Verify(context.Background(), wrongIssuer, nil); !errors.Is(err, ErrInvalidIssuer) {
+		t.Errorf("Expected ErrInvalidIssuer, got %v", err)
+	}
+
+	restricted := NewVerifier(StaticKeys{"k1": []byte("secret")}, WithAlgorithms(RS256))
+	token, _ := Sign(key, RegisteredClaims{})
+	if err := restricted.Verify(context.Background(), token, nil); !errors.Is(err, ErrUnsupportedAlg) {
+		t.Errorf("Expected ErrUnsupportedAlg, got %v", err)
+	}
+}
+
+func TestJWKSCache_Rotation(t *testing.T) {
+	first, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	second, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	issuer := NewTokenIssuer(SigningKey{ID: "first", Algorithm: ES256, Key: first})
+	server := httptest.NewServer(issuer.JWKSHandler())
+	defer server.Close()
+
+	cache := NewJWKSCache(server.URL, WithRefreshInterval(time.Hour), WithMinRefreshDelay(0))
+	defer cache.Close()
+	verifier := NewVerifier(cache)
+
+	oldToken, err := issuer.Issue(&RegisteredClaims{Subject: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.Verify(context.Background(), oldToken, nil); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	issuer.Rotate(SigningKey{ID: "second", Algorithm: RS256, Key: second})
+	newToken, err := issuer.Issue(&RegisteredClaims{Subject: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unknown kid triggers a refresh that picks up the rotated key
+	if err := verifier.Verify(context.Background(), newToken, nil); err != nil {
+		t.Fatalf("Verify after rotation failed: %v", err)
+	}
+	if err := verifier.Verify(context.Background(), oldToken, nil); err != nil {
+		t.Fatalf("Verify of token signed by previous key failed: %v", err)
+	}
+
+	issuer.Retire("first")
+	if err := cache.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.Verify(context.Background(), oldToken, nil); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey after retiring key, got %v", err)
+	}
+}
+
+func TestJWKSCache_FailedRefreshIsRateLimited(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cache := NewJWKSCache(server.URL, WithRefreshInterval(time.Hour), WithMinRefreshDelay(time.Hour))
+	defer cache.Close()
+
+	// Wait for the initial background refresh to reach the endpoint
+	for hits.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.Key(context.Background(), fmt.Sprintf("kid-%d", i), ES256); err == nil {
+				t.Error("Expected an error for an unknown kid")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if hits.Load() != 1 {
+		t.Errorf("Expected unknown kids not to refetch after a failed refresh, got %d fetches", hits.Load())
+	}
+}
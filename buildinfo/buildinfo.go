@@ -0,0 +1,140 @@
+// Package buildinfo reports what build of a service is running: version,
+// git SHA, build date, and Go runtime details.
+//
+// Values set with -ldflags take precedence; anything not set is read from
+// the build information the Go toolchain embeds in the binary
+// (debug.ReadBuildInfo), which carries the module version and, for builds
+// from a git checkout, the commit and its time.
+//
+// The logger's service metadata and the REST client's default User-Agent
+// are populated from Get, so setting the ldflags once is enough.
+//
+// Example usage:
+//
+//	go build -ldflags "\
+//		-X github.com/khekrn/core/buildinfo.name=orders \
+//		-X github.com/khekrn/core/buildinfo.version=1.4.2 \
+//		-X github.com/khekrn/core/buildinfo.gitSHA=$(git rev-parse HEAD) \
+//		-X github.com/khekrn/core/buildinfo.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+//	mux.Handle("GET /buildinfo", buildinfo.Handler())
+//	fmt.Println(buildinfo.Get().Version) // "1.4.2"
+package buildinfo
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/khekrn/core/response"
+)
+
+// Set with -ldflags "-X github.com/khekrn/core/buildinfo.<name>=<value>"
+var (
+	name      string
+	version   string
+	gitSHA    string
+	buildDate string
+)
+
+// Info describes the running build. Empty fields are unknown.
+type Info struct {
+	Name      string `json:"name,omitempty"`       // Service name, defaulting to the executable name
+	Version   string `json:"version,omitempty"`    // Release version, defaulting to the main module version
+	GitSHA    string `json:"git_sha,omitempty"`    // Commit the binary was built from
+	GitDirty  bool   `json:"git_dirty,omitempty"`  // Whether the checkout had uncommitted changes
+	BuildDate string `json:"build_date,omitempty"` // RFC 3339 build time, defaulting to the commit time
+	Module    string `json:"module,omitempty"`     // Main module path
+	GoVersion string `json:"go_version"`           // Go toolchain version, e.g. go1.24.4
+	OS        string `json:"os"`                   // runtime.GOOS
+	Arch      string `json:"arch"`                 // runtime.GOARCH
+}
+
+// Get returns the build information of the running binary
+func Get() Info {
+	return detect()
+}
+
+// detect computes the build information once
+var detect = sync.OnceValue(func() Info {
+	info := Info{
+		Name:      name,
+		Version:   version,
+		GitSHA:    gitSHA,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info.fill(build)
+	}
+	if info.Name == "" && len(os.Args) > 0 {
+		info.Name = filepath.Base(os.Args[0])
+	}
+	return info
+})
+
+// fill sets the fields not given with -ldflags from the embedded build information
+func (i *Info) fill(build *debug.BuildInfo) {
+	i.Module = build.Main.Path
+	if i.Version == "" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+		i.Version = build.Main.Version
+	}
+	if build.GoVersion != "" {
+		i.GoVersion = build.GoVersion
+	}
+
+	revision := i.GitSHA == ""
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if revision {
+				i.GitSHA = setting.Value
+			}
+		case "vcs.modified":
+			if revision {
+				i.GitDirty = setting.Value == "true"
+			}
+		case "vcs.time":
+			if i.BuildDate == "" {
+				i.BuildDate = setting.Value
+			}
+		}
+	}
+}
+
+// ShortSHA returns the first 7 characters of the git SHA
+func (i Info) ShortSHA() string {
+	if len(i.GitSHA) > 7 {
+		return i.GitSHA[:7]
+	}
+	return i.GitSHA
+}
+
+// UserAgent returns a User-Agent identifying the build, e.g.
+// "orders/1.4.2 (abc1234; go1.24.4)"
+func (i Info) UserAgent() string {
+	product := i.Name
+	if product == "" {
+		product = "core-client"
+	}
+	if i.Version != "" {
+		product += "/" + i.Version
+	}
+	comment := i.GoVersion
+	if sha := i.ShortSHA(); sha != "" {
+		comment = sha + "; " + comment
+	}
+	return product + " (" + comment + ")"
+}
+
+// Handler serves Get as the data of a successful response envelope
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.Write(w, r, http.StatusOK, response.NewSuccessResponse("Build info", Get()))
+	})
+}
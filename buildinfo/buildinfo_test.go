@@ -0,0 +1,82 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"github.com/khekrn/core/response"
+)
+
+func TestGet(t *testing.T) {
+	info := Get()
+	if info.GoVersion == "" || info.OS != runtime.GOOS || info.Arch != runtime.GOARCH || info.Name == "" {
+		t.Errorf("Expected runtime details and a name, got %+v", info)
+	}
+}
+
+func TestInfo_Fill(t *testing.T) {
+	build := &debug.BuildInfo{
+		GoVersion: "go1.24.4",
+		Main:      debug.Module{Path: "github.com/acme/orders", Version: "v1.4.2"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "abc1234def5678"},
+			{Key: "vcs.modified", Value: "true"},
+			{Key: "vcs.time", Value: "2024-05-01T10:00:00Z"},
+		},
+	}
+
+	var detected Info
+	detected.fill(build)
+	if detected.Version != "v1.4.2" || detected.GitSHA != "abc1234def5678" || !detected.GitDirty ||
+		detected.BuildDate != "2024-05-01T10:00:00Z" || detected.Module != "github.com/acme/orders" {
+		t.Errorf("Unexpected detected info %+v", detected)
+	}
+
+	// Values from -ldflags take precedence
+	linked := Info{Version: "1.5.0", GitSHA: "fedcba9", BuildDate: "2024-06-01T00:00:00Z"}
+	linked.fill(build)
+	if linked.Version != "1.5.0" || linked.GitSHA != "fedcba9" || linked.GitDirty || linked.BuildDate != "2024-06-01T00:00:00Z" {
+		t.Errorf("Expected ldflags values to win, got %+v", linked)
+	}
+
+	devel := Info{}
+	devel.fill(&debug.BuildInfo{Main: debug.Module{Version: "(devel)"}})
+	if devel.Version != "" {
+		t.Errorf("Expected no version for development builds, got %q", devel.Version)
+	}
+}
+
+func TestInfo_UserAgent(t *testing.T) {
+	tests := []struct {
+		info Info
+		want string
+	}{
+		{Info{Name: "orders", Version: "1.4.2", GitSHA: "abc1234def", GoVersion: "go1.24.4"}, "orders/1.4.2 (abc1234; go1.24.4)"},
+		{Info{GoVersion: "go1.24.4"}, "core-client (go1.24.4)"},
+	}
+	for _, tt := range tests {
+		if got := tt.info.UserAgent(); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/buildinfo", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", recorder.Code)
+	}
+
+	var envelope response.Typed[Info]
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if envelope.Status != response.StatusAccept || envelope.Data != Get() {
+		t.Errorf("Unexpected envelope %+v", envelope)
+	}
+}
@@ -0,0 +1,364 @@
+// Package cache provides a generic in-memory cache with TTL expiry, LRU
+// eviction, and deduplicated loading.
+//
+// Expired entries are removed lazily when they are read or when the cache
+// is full; call DeleteExpired periodically to reclaim memory sooner.
+//
+// Example usage:
+//
+//	users := cache.New[string, User](
+//		cache.WithTTL(5*time.Minute),
+//		cache.WithMaxEntries(10_000),
+//	)
+//
+//	// Concurrent misses for the same key share one load
+//	user, err := users.GetOrLoad(ctx, id, func(ctx context.Context, id string) (User, error) {
+//		return userService.Get(ctx, id)
+//	})
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/khekrn/core/clock"
+)
+
+// EvictReason describes why an entry left the cache
+type EvictReason int
+
+// Eviction reasons
+const (
+	EvictCapacity EvictReason = iota // Removed as least recently used to make room
+	EvictExpired                     // Removed after its TTL passed
+)
+
+// String returns the reason name
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// Hooks are callbacks for metrics. Any hook may be nil. Hooks are called
+// while the cache is locked and must not call back into it.
+type Hooks struct {
+	OnHit   func(key any)
+	OnMiss  func(key any)
+	OnEvict func(key any, reason EvictReason)
+	// OnLoad is called after each GetOrLoad loader call
+	OnLoad func(key any, duration time.Duration, err error)
+}
+
+// Config holds configuration for a cache
+type Config struct {
+	TTL        time.Duration // Default time to live; zero means entries do not expire
+	MaxEntries int           // Maximum number of entries; zero means unbounded
+	Hooks      Hooks
+	Clock      clock.Clock // Time source for expiry; defaults to clock.System
+}
+
+// Option is a function type for configuring a cache
+type Option func(*Config)
+
+// WithTTL sets the default time to live of entries
+func WithTTL(ttl time.Duration) Option {
+	return func(config *Config) {
+		config.TTL = ttl
+	}
+}
+
+// WithMaxEntries bounds the cache, evicting the least recently used entry
+// when it is full
+func WithMaxEntries(maxEntries int) Option {
+	return func(config *Config) {
+		config.MaxEntries = maxEntries
+	}
+}
+
+// WithHooks sets metrics hooks
+func WithHooks(hooks Hooks) Option {
+	return func(config *Config) {
+		config.Hooks = hooks
+	}
+}
+
+// WithClock sets the time source for expiry, e.g. a fake clock in tests
+func WithClock(c clock.Clock) Option {
+	return func(config *Config) {
+		config.Clock = c
+	}
+}
+
+// Stats holds a snapshot of cache metrics
+type Stats struct {
+	Hits      int64 // Lookups that found a live entry
+	Misses    int64 // Lookups that found no entry or an expired one
+	Evictions int64 // Entries removed for capacity or expiry
+	Loads     int64 // Loader calls made by GetOrLoad
+	Entries   int   // Entries currently stored, including expired ones not yet removed
+}
+
+// PanicError wraps a value recovered from a panicking loader
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// Error implements the error interface
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("cache: loader panicked: %v", e.Value)
+}
+
+// Cache is a concurrency-safe in-memory cache
+type Cache[K comparable, V any] struct {
+	config Config
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[K]*list.Element
+	loads   map[K]*load[V]
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+	loaded    atomic.Int64
+}
+
+// entry is an element of the LRU list
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time // Zero when the entry does not expire
+}
+
+// load is a GetOrLoad call in progress, shared by concurrent callers
+type load[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// New creates a cache
+func New[K comparable, V any](options ...Option) *Cache[K, V] {
+	var config Config
+	for _, opt := range options {
+		opt(&config)
+	}
+	config.Clock = clock.OrSystem(config.Clock)
+	return &Cache[K, V]{
+		config:  config,
+		order:   list.New(),
+		entries: make(map[K]*list.Element),
+		loads:   make(map[K]*load[V]),
+	}
+}
+
+// Get returns the value for key, marking it recently used
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.get(key, c.config.Clock.Now())
+}
+
+// Set stores value under key with the default TTL
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.config.TTL)
+}
+
+// SetWithTTL stores value under key, expiring after ttl. A zero ttl means
+// the entry does not expire.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	now := c.config.Clock.Now()
+	var expires time.Time
+	if ttl > 0 {
+		expires = now.Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		item := element.Value.(*entry[K, V])
+		item.value = value
+		item.expires = expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expires: expires})
+	if c.config.MaxEntries > 0 && c.order.Len() > c.config.MaxEntries {
+		c.makeRoom(now)
+	}
+}
+
+// Delete removes the entry for key
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// DeleteExpired removes every expired entry
+func (c *Cache[K, V]) DeleteExpired() {
+	now := c.config.Clock.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for element := c.order.Back(); element != nil; {
+		prev := element.Prev()
+		if item := element.Value.(*entry[K, V]); item.expired(now) {
+			c.evict(element, EvictExpired)
+		}
+		element = prev
+	}
+}
+
+// Purge removes every entry
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[K]*list.Element)
+}
+
+// Len returns the number of entries, including expired ones not yet removed
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Stats returns a snapshot of the cache metrics
+func (c *Cache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Loads:     c.loaded.Load(),
+		Entries:   c.Len(),
+	}
+}
+
+// GetOrLoad returns the value for key, calling loader on a miss and storing
+// its result with the default TTL. Concurrent misses for the same key share
+// one loader call, which runs with the context of the first caller; the
+// others wait for it or for their own context. Errors are returned to every
+// waiting caller and not cached.
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K, loader func(ctx context.Context, key K) (V, error)) (V, error) {
+	c.mu.Lock()
+	if value, ok := c.get(key, c.config.Clock.Now()); ok {
+		c.mu.Unlock()
+		return value, nil
+	}
+	if pending, ok := c.loads[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-pending.done:
+			return pending.value, pending.err
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+
+	pending := &load[V]{done: make(chan struct{})}
+	c.loads[key] = pending
+	c.mu.Unlock()
+
+	start := c.config.Clock.Now()
+	pending.value, pending.err = c.runLoader(ctx, key, loader)
+	c.loaded.Add(1)
+	if c.config.Hooks.OnLoad != nil {
+		c.config.Hooks.OnLoad(key, c.config.Clock.Now().Sub(start), pending.err)
+	}
+
+	if pending.err == nil {
+		c.Set(key, pending.value)
+	}
+	c.mu.Lock()
+	delete(c.loads, key)
+	c.mu.Unlock()
+	close(pending.done)
+
+	return pending.value, pending.err
+}
+
+// runLoader calls loader, converting panics into errors so waiting callers
+// are released
+func (c *Cache[K, V]) runLoader(ctx context.Context, key K, loader func(ctx context.Context, key K) (V, error)) (value V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return loader(ctx, key)
+}
+
+// get returns the live value for key, removing it when expired. The caller
+// holds the lock.
+func (c *Cache[K, V]) get(key K, now time.Time) (V, bool) {
+	element, ok := c.entries[key]
+	if ok {
+		if item := element.Value.(*entry[K, V]); item.expired(now) {
+			c.evict(element, EvictExpired)
+			ok = false
+		}
+	}
+	if !ok {
+		c.misses.Add(1)
+		if c.config.Hooks.OnMiss != nil {
+			c.config.Hooks.OnMiss(key)
+		}
+		var zero V
+		return zero, false
+	}
+
+	c.hits.Add(1)
+	if c.config.Hooks.OnHit != nil {
+		c.config.Hooks.OnHit(key)
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*entry[K, V]).value, true
+}
+
+// makeRoom evicts the least recently used entry, preferring expired entries
+// near the back of the list. The caller holds the lock.
+func (c *Cache[K, V]) makeRoom(now time.Time) {
+	oldest := c.order.Back()
+	if item := oldest.Value.(*entry[K, V]); item.expired(now) {
+		c.evict(oldest, EvictExpired)
+		return
+	}
+	c.evict(oldest, EvictCapacity)
+}
+
+// evict removes element and reports it. The caller holds the lock.
+func (c *Cache[K, V]) evict(element *list.Element, reason EvictReason) {
+	item := element.Value.(*entry[K, V])
+	c.order.Remove(element)
+	delete(c.entries, item.key)
+	c.evictions.Add(1)
+	if c.config.Hooks.OnEvict != nil {
+		c.config.Hooks.OnEvict(item.key, reason)
+	}
+}
+
+// expired reports whether the entry's TTL has passed
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
@@ -0,0 +1,202 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/coretest"
+)
+
+func TestCache_GetSet(t *testing.T) {
+	c := New[string, int]()
+	if _, ok := c.Get("a"); ok {
+		t.Error("Expected a miss on an empty cache")
+	}
+
+	c.Set("a", 1)
+	c.Set("a", 2)
+	if value, ok := c.Get("a"); !ok || value != 2 {
+		t.Errorf("Expected 2, got %d %v", value, ok)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Expected 1 entry, got %d", c.Len())
+	}
+
+	c.Delete("a")
+	if _, ok := c.Get("a"); ok {
+		t.Error("Expected a miss after Delete")
+	}
+
+	c.Set("b", 1)
+	c.Set("c", 2)
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("Expected an empty cache after Purge, got %d", c.Len())
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	var reasons []EvictReason
+	clk := coretest.NewClock(time.Time{})
+	c := New[string, int](
+		WithTTL(20*time.Millisecond),
+		WithClock(clk),
+		WithHooks(Hooks{OnEvict: func(key any, reason EvictReason) { reasons = append(reasons, reason) }}),
+	)
+	c.Set("short", 1)
+	c.SetWithTTL("forever", 2, 0)
+	c.SetWithTTL("swept", 3, time.Millisecond)
+
+	clk.Advance(20 * time.Millisecond)
+	if _, ok := c.Get("short"); !ok {
+		t.Error("Expected the entry to live for its whole TTL")
+	}
+
+	clk.Advance(20 * time.Millisecond)
+	if _, ok := c.Get("short"); ok {
+		t.Error("Expected the entry to expire")
+	}
+	if value, ok := c.Get("forever"); !ok || value != 2 {
+		t.Error("Expected the entry without TTL to remain")
+	}
+
+	c.DeleteExpired()
+	if c.Len() != 1 {
+		t.Errorf("Expected 1 entry after DeleteExpired, got %d", c.Len())
+	}
+	if len(reasons) != 2 || reasons[0] != EvictExpired || reasons[1] != EvictExpired {
+		t.Errorf("Expected two expiry evictions, got %v", reasons)
+	}
+}
+
+func TestCache_LRU(t *testing.T) {
+	evicted := map[any]EvictReason{}
+	c := New[string, int](
+		WithMaxEntries(2),
+		WithHooks(Hooks{OnEvict: func(key any, reason EvictReason) { evicted[key] = reason }}),
+	)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Error("Expected the recently used entry to remain")
+	}
+	if reason, ok := evicted["b"]; !ok || reason != EvictCapacity || reason.String() != "capacity" {
+		t.Errorf("Unexpected evictions %v", evicted)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", c.Len())
+	}
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	c := New[string, int]()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key string) (int, error) {
+		calls.Add(1)
+		<-release
+		return len(key), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = c.GetOrLoad(context.Background(), "four", loader)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected one loader call, got %d", calls.Load())
+	}
+	for _, result := range results {
+		if result != 4 {
+			t.Errorf("Expected 4, got %d", result)
+		}
+	}
+	if value, ok := c.Get("four"); !ok || value != 4 {
+		t.Error("Expected the loaded value to be cached")
+	}
+}
+
+func TestCache_GetOrLoadErrors(t *testing.T) {
+	c := New[string, int]()
+	errLoad := errors.New("load failed")
+
+	_, err := c.GetOrLoad(context.Background(), "a", func(ctx context.Context, key string) (int, error) {
+		return 0, errLoad
+	})
+	if !errors.Is(err, errLoad) {
+		t.Errorf("Expected the loader error, got %v", err)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Error("Expected errors not to be cached")
+	}
+
+	_, err = c.GetOrLoad(context.Background(), "b", func(ctx context.Context, key string) (int, error) {
+		panic("boom")
+	})
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+		t.Errorf("Expected a PanicError, got %v", err)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	go c.GetOrLoad(context.Background(), "c", func(ctx context.Context, key string) (int, error) {
+		<-release
+		return 1, nil
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.GetOrLoad(ctx, "c", func(ctx context.Context, key string) (int, error) {
+		t.Error("Expected the pending load to be shared")
+		return 0, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the waiter's context error, got %v", err)
+	}
+}
+
+func TestCache_Stats(t *testing.T) {
+	var hits, misses, loads atomic.Int32
+	c := New[string, int](
+		WithMaxEntries(1),
+		WithHooks(Hooks{
+			OnHit:  func(key any) { hits.Add(1) },
+			OnMiss: func(key any) { misses.Add(1) },
+			OnLoad: func(key any, duration time.Duration, err error) { loads.Add(1) },
+		}),
+	)
+
+	c.Get("a")
+	c.GetOrLoad(context.Background(), "a", func(ctx context.Context, key string) (int, error) { return 1, nil })
+	c.Get("a")
+	c.Set("b", 2)
+
+	stats := c.Stats()
+	want := Stats{Hits: 1, Misses: 2, Evictions: 1, Loads: 1, Entries: 1}
+	if stats != want {
+		t.Errorf("Expected %+v, got %+v", want, stats)
+	}
+	if hits.Load() != 1 || misses.Load() != 2 || loads.Load() != 1 {
+		t.Errorf("Unexpected hook calls: %d hits, %d misses, %d loads", hits.Load(), misses.Load(), loads.Load())
+	}
+}
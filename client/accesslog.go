@@ -0,0 +1,77 @@
+package client
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+)
+
+// WithZapLogging writes one access log entry per attempt through log:
+// method, URL, status, attempt, latency, and the state of the circuit
+// breaker guarding the request. Successful responses are logged at info,
+// 4xx at warn, and 5xx and transport errors at error level. Secrets in the
+// URL are hidden as described by Redaction. A nil log uses the logger from
+// the request context, which includes its request ID and trace fields.
+//
+// Example:
+//
+//	restClient := client.NewClientBuilder().
+//		WithBaseURL("https://api.example.com").
+//		WithZapLogging(logger.Named("httpclient")).
+//		Build()
+func (b *ClientBuilder) WithZapLogging(log *zap.Logger) *ClientBuilder {
+	b.accessLog = true
+	b.accessLogger = log
+	return b
+}
+
+// accessLogger logs one entry per attempt
+type accessLogger struct {
+	log      *zap.Logger
+	redactor *redactor
+	client   *RESTClient
+}
+
+// middleware logs each attempt of one request, counting attempts
+func (a *accessLogger) middleware() Middleware {
+	attempt := 0
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*Response, error) {
+			log := a.log
+			if log == nil {
+				log = logger.FromContext(req.Context())
+			}
+			if log == nil {
+				return next(req)
+			}
+
+			attempt++
+			start := time.Now()
+			resp, err := next(req)
+
+			fields := []zap.Field{
+				zap.String("method", req.Method),
+				zap.String("url", a.redactor.url(req.URL)),
+				zap.Int("attempt", attempt),
+				zap.Duration("latency", time.Since(start)),
+			}
+			if cb := a.client.breakerFor(req); cb != nil {
+				fields = append(fields, zap.String("breaker_state", cb.State().String()))
+			}
+
+			switch {
+			case err != nil:
+				log.Error("HTTP client request failed", append(fields, zap.Error(err))...)
+			case resp.StatusCode >= http.StatusInternalServerError:
+				log.Error("HTTP client request", append(fields, zap.Int("status", resp.StatusCode))...)
+			case resp.StatusCode >= http.StatusBadRequest:
+				log.Warn("HTTP client request", append(fields, zap.Int("status", resp.StatusCode))...)
+			default:
+				log.Info("HTTP client request", append(fields, zap.Int("status", resp.StatusCode))...)
+			}
+			return resp, err
+		}
+	}
+}
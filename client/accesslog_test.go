@@ -0,0 +1,61 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRESTClient_ZapLogging(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithRetry(client.RetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}).
+		WithZapLogging(zap.New(core)).
+		Build()
+
+	if _, err := restClient.GET("/orders", client.WithQueryParam("api_key", "k-1")); err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected one entry per attempt, got %d", len(entries))
+	}
+	first, second := entries[0], entries[1]
+	if first.Level != zapcore.ErrorLevel || first.ContextMap()["status"] != int64(http.StatusServiceUnavailable) {
+		t.Errorf("Expected the 503 attempt at error level, got %v %v", first.Level, first.ContextMap())
+	}
+
+	fields := second.ContextMap()
+	if second.Level != zapcore.InfoLevel || fields["attempt"] != int64(2) || fields["method"] != "GET" || fields["status"] != int64(http.StatusOK) {
+		t.Errorf("Unexpected access log %v %v", second.Level, fields)
+	}
+	if fields["breaker_state"] != "closed" {
+		t.Errorf("Expected breaker state, got %v", fields["breaker_state"])
+	}
+	if _, ok := fields["latency"]; !ok {
+		t.Error("Expected latency field")
+	}
+	if url, _ := fields["url"].(string); strings.Contains(url, "k-1") || !strings.Contains(url, "/orders") {
+		t.Errorf("Expected redacted URL, got %q", url)
+	}
+}
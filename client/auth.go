@@ -0,0 +1,103 @@
+package client
+
+import (
+	"net/http"
+)
+
+// AuthProvider attaches credentials to outgoing requests. Apply runs before
+// every attempt, after the body and query parameters are set.
+type AuthProvider interface {
+	Apply(req *http.Request) error
+}
+
+// AuthProviderFunc adapts a function to the AuthProvider interface
+type AuthProviderFunc func(req *http.Request) error
+
+// Apply calls f
+func (f AuthProviderFunc) Apply(req *http.Request) error {
+	return f(req)
+}
+
+// refreshableAuth is implemented by providers whose credentials can be
+// refreshed after the server rejects them with 401. Refresh reports whether
+// retrying with newly applied credentials is worthwhile.
+type refreshableAuth interface {
+	AuthProvider
+	Refresh(req *http.Request) bool
+}
+
+// BasicAuth authenticates with HTTP Basic credentials
+func BasicAuth(username, password string) AuthProvider {
+	return AuthProviderFunc(func(req *http.Request) error {
+		req.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+// BearerToken authenticates with a static bearer token
+func BearerToken(token string) AuthProvider {
+	return AuthProviderFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// APIKeyHeader sends an API key in the named header, e.g. "X-API-Key"
+func APIKeyHeader(header, key string) AuthProvider {
+	return AuthProviderFunc(func(req *http.Request) error {
+		req.Header.Set(header, key)
+		return nil
+	})
+}
+
+// APIKeyQuery sends an API key in the named query parameter
+func APIKeyQuery(param, key string) AuthProvider {
+	return AuthProviderFunc(func(req *http.Request) error {
+		q := req.URL.Query()
+		q.Set(param, key)
+		req.URL.RawQuery = q.Encode()
+		return nil
+	})
+}
+
+// WithAuth sets the provider that authenticates every request, replacing
+// any OAuth2 configuration
+func (b *ClientBuilder) WithAuth(provider AuthProvider) *ClientBuilder {
+	b.auth = provider
+	b.oauth2 = nil
+	return b
+}
+
+// authMiddleware applies provider to each attempt and, for refreshable
+// providers, retries once with fresh credentials on 401
+func authMiddleware(provider AuthProvider) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*Response, error) {
+			if err := provider.Apply(req); err != nil {
+				return nil, err
+			}
+
+			resp, err := next(req)
+			if err != nil || resp.StatusCode != http.StatusUnauthorized {
+				return resp, err
+			}
+
+			refresher, ok := provider.(refreshableAuth)
+			if !ok || (req.Body != nil && req.GetBody == nil) || !refresher.Refresh(req) {
+				return resp, nil
+			}
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return resp, nil
+				}
+				req.Body = body
+			}
+			if err := provider.Apply(req); err != nil {
+				return resp, nil
+			}
+			resp.Close()
+			return next(req)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_AuthProviders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Authorization", r.Header.Get("Authorization"))
+		w.Header().Set("X-Api-Key-Header", r.Header.Get("X-API-Key"))
+		w.Header().Set("X-Api-Key-Query", r.URL.Query().Get("api_key"))
+		w.Header().Set("X-Other-Query", r.URL.Query().Get("page"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		provider client.AuthProvider
+		header   string
+		want     string
+	}{
+		{"basic", client.BasicAuth("user", "pass"), "X-Authorization", "Basic dXNlcjpwYXNz"},
+		{"bearer", client.BearerToken("abc"), "X-Authorization", "Bearer abc"},
+		{"api key header", client.APIKeyHeader("X-API-Key", "k1"), "X-Api-Key-Header", "k1"},
+		{"api key query", client.APIKeyQuery("api_key", "k2"), "X-Api-Key-Query", "k2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restClient := client.NewClientBuilder().
+				WithBaseURL(server.URL).
+				WithAuth(tt.provider).
+				Build()
+
+			resp, err := restClient.GET("/resource", client.WithQueryParam("page", "2"))
+			if err != nil {
+				t.Fatalf("GET failed: %v", err)
+			}
+			if got := resp.Headers.Get(tt.header); got != tt.want {
+				t.Errorf("Expected %s %q, got %q", tt.header, tt.want, got)
+			}
+			if resp.Headers.Get("X-Other-Query") != "2" {
+				t.Error("Expected existing query parameters to be preserved")
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult is the outcome of one request in a batch
+type BatchResult struct {
+	Response *Response
+	Err      error
+}
+
+// Batch executes requests in parallel with at most concurrency in flight and
+// returns their results in the order of configs. Requests without a context
+// use ctx; requests not yet started when ctx is cancelled fail with its error.
+// A concurrency below 1 runs every request at once.
+func (rc *RESTClient) Batch(ctx context.Context, configs []RequestConfig, concurrency int) []BatchResult {
+	results := make([]BatchResult, len(configs))
+	if concurrency < 1 || concurrency > len(configs) {
+		concurrency = len(configs)
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, config := range configs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		if config.Context == nil {
+			config.Context = ctx
+		}
+		wg.Add(1)
+		go func(i int, config RequestConfig) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Response, results[i].Err = rc.Request(config)
+		}(i, config)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,72 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_Batch(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if r.URL.Path == "/items/3" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutRetry().
+		WithoutCircuitBreaker().
+		Build()
+
+	configs := make([]client.RequestConfig, 10)
+	for i := range configs {
+		configs[i] = client.RequestConfig{Method: client.GET, URL: fmt.Sprintf("/items/%d", i)}
+	}
+
+	results := restClient.Batch(context.Background(), configs, 3)
+	if len(results) != len(configs) {
+		t.Fatalf("Expected %d results, got %d", len(configs), len(results))
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			t.Fatalf("Request %d failed: %v", i, result.Err)
+		}
+		if want := fmt.Sprintf("/items/%d", i); result.Response.String() != want {
+			t.Errorf("Result %d out of order: got %q", i, result.Response.String())
+		}
+	}
+	if results[3].Response.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for item 3, got %d", results[3].Response.StatusCode)
+	}
+	if peak.Load() > 3 {
+		t.Errorf("Expected at most 3 concurrent requests, saw %d", peak.Load())
+	}
+
+	// Requests not started before cancellation fail with the context error
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i, result := range restClient.Batch(ctx, configs, 2) {
+		if result.Err == nil {
+			t.Errorf("Expected error for request %d after cancellation", i)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"io"
+	"net/http"
+)
+
+// BodyReader is a request body streamed from a reader without buffering
+type BodyReader struct {
+	Reader io.Reader
+	// Size is the body length in bytes; a negative size sends the body with
+	// chunked transfer encoding
+	Size int64
+	// GetBody reopens the body for retries and redirects. It is set
+	// automatically for readers that implement io.Seeker; when nil the
+	// request is sent at most once.
+	GetBody func() (io.ReadCloser, error)
+}
+
+// WithBodyReader streams the request body from r instead of buffering it, as
+// happens for an io.Reader passed as the body. Pass the exact size when
+// known so Content-Length is sent, or -1 for chunked transfer. Seekable
+// readers such as *os.File are rewound for retries; the caller closes r.
+func WithBodyReader(r io.Reader, size int64) RequestOption {
+	return func(config *RequestConfig) {
+		config.Body = &BodyReader{Reader: r, Size: size}
+	}
+}
+
+// apply sets the content length and replay function on req
+func (b *BodyReader) apply(req *http.Request) {
+	req.ContentLength = b.Size
+	if b.Size < 0 {
+		req.ContentLength = -1
+	}
+	if b.Size == 0 {
+		req.Body = http.NoBody
+	}
+
+	req.GetBody = b.GetBody
+	if req.GetBody == nil {
+		if seeker, ok := b.Reader.(io.Seeker); ok {
+			if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+				req.GetBody = func() (io.ReadCloser, error) {
+					if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+						return nil, err
+					}
+					return io.NopCloser(b.Reader), nil
+				}
+			}
+		}
+	}
+	if req.Body == http.NoBody {
+		req.GetBody = func() (io.ReadCloser, error) { return http.NoBody, nil }
+	}
+}
+
+// canReplayBody reports whether req can be sent again with its full body
+func canReplayBody(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// cancelOnClose releases a request context when the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel func()
+}
+
+// Close closes the body and cancels the request context
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
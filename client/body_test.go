@@ -0,0 +1,132 @@
+package client_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_BodyReader(t *testing.T) {
+	payload := strings.Repeat("chunk-of-upload-data ", 1000)
+	path := filepath.Join(t.TempDir(), "upload.bin")
+	if err := os.WriteFile(path, []byte(payload), 0o600); err != nil {
+		t.Fatalf("Failed to write upload file: %v", err)
+	}
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != payload {
+			t.Errorf("Attempt %d: expected full payload, got %d bytes", calls.Load()+1, len(body))
+		}
+		if r.URL.Path == "/sized" && r.ContentLength != int64(len(payload)) {
+			t.Errorf("Expected Content-Length %d, got %d", len(payload), r.ContentLength)
+		}
+		if r.URL.Path == "/chunked" && (len(r.TransferEncoding) == 0 || r.TransferEncoding[0] != "chunked") {
+			t.Errorf("Expected chunked transfer encoding, got %v", r.TransferEncoding)
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithRetry(client.RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, BackoffFactor: 2}).
+		WithoutCircuitBreaker().
+		Build()
+
+	// A seekable file is rewound for the retry
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open upload file: %v", err)
+	}
+	defer file.Close()
+
+	resp, err := restClient.PUT("/sized", nil, client.WithBodyReader(file, int64(len(payload))))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated || calls.Load() != 2 {
+		t.Errorf("Expected 201 after one retry, got %d after %d calls", resp.StatusCode, calls.Load())
+	}
+
+	// Unknown size is sent chunked; a non-seekable reader is not retried
+	calls.Store(0)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.Write([]byte(payload))
+		pw.Close()
+	}()
+	resp, err = restClient.PUT("/chunked", nil, client.WithBodyReader(pr, -1))
+	if err != nil {
+		t.Fatalf("Expected the response when the body cannot be replayed, got %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected the unchanged 503 response, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single attempt for a non-replayable body, got %d", calls.Load())
+	}
+}
+
+func TestRESTClient_BodyReplay(t *testing.T) {
+	payload := `{"order":"ord-1","amount":100}`
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusTemporaryRedirect)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != payload {
+			t.Errorf("%s attempt %d: expected full payload, got %q", r.URL.Path, calls.Load()+1, body)
+		}
+		if r.URL.Path == "/flaky" && calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithRetry(client.RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}).
+		WithoutCircuitBreaker().
+		Build()
+
+	bodies := map[string]func() interface{}{
+		"string":       func() interface{} { return payload },
+		"bytes":        func() interface{} { return []byte(payload) },
+		"plain reader": func() interface{} { return io.MultiReader(strings.NewReader(payload)) },
+	}
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			calls.Store(0)
+			resp, err := restClient.POST("/flaky", body(), client.WithIdempotencyKey("ord-1"))
+			if err != nil || resp.StatusCode != http.StatusCreated {
+				t.Fatalf("Expected 201 after retries, got %v", err)
+			}
+			if calls.Load() != 3 {
+				t.Errorf("Expected 3 attempts, got %d", calls.Load())
+			}
+
+			resp, err = restClient.POST("/old", body())
+			if err != nil || resp.StatusCode != http.StatusCreated {
+				t.Errorf("Expected body to follow 307 redirect, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package client_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+	"github.com/sony/gobreaker/v2"
+)
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// failingHostTransport fails requests to the host "down" and answers all others
+func failingHostTransport() http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host == "down" || strings.HasPrefix(req.URL.Path, "/down") {
+			return nil, errors.New("connection refused")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok")), Header: http.Header{}}, nil
+	})
+}
+
+func tripAfterTwo(counts gobreaker.Counts) bool {
+	return counts.ConsecutiveFailures >= 2
+}
+
+func TestRESTClient_CircuitBreakerPerHost(t *testing.T) {
+	restClient := client.NewClientBuilder().
+		WithTransport(failingHostTransport()).
+		WithoutRetry().
+		WithCircuitBreaker(client.CircuitBreakerConfig{Name: "api", Timeout: time.Minute, ReadyToTrip: tripAfterTwo}).
+		WithCircuitBreakerPerKey(client.BreakerKeyByHost).
+		Build()
+
+	for i := 0; i < 3; i++ {
+		restClient.GET("http://down/items")
+	}
+	if _, err := restClient.GET("http://down/items"); !client.IsCircuitOpen(err) {
+		t.Errorf("Expected open circuit for failing host, got %v", err)
+	}
+	if _, err := restClient.GET("http://up/items"); err != nil {
+		t.Errorf("Expected healthy host to be unaffected, got %v", err)
+	}
+}
+
+func TestRESTClient_BreakerKey(t *testing.T) {
+	restClient := client.NewClientBuilder().
+		WithTransport(failingHostTransport()).
+		WithoutRetry().
+		WithCircuitBreaker(client.CircuitBreakerConfig{Name: "api", Timeout: time.Minute, ReadyToTrip: tripAfterTwo}).
+		WithCircuitBreakerForKey("reports", client.CircuitBreakerConfig{
+			Timeout:     time.Minute,
+			ReadyToTrip: func(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures >= 5 },
+		}).
+		Build()
+
+	for i := 0; i < 3; i++ {
+		restClient.GET("http://svc/down/orders", client.WithBreakerKey("orders"))
+	}
+	if _, err := restClient.GET("http://svc/orders", client.WithBreakerKey("orders")); !client.IsCircuitOpen(err) {
+		t.Errorf("Expected open circuit for orders route, got %v", err)
+	}
+	if _, err := restClient.GET("http://svc/users"); err != nil {
+		t.Errorf("Expected client-wide breaker to be unaffected, got %v", err)
+	}
+
+	// The per-key override tolerates more failures
+	for i := 0; i < 3; i++ {
+		restClient.GET("http://svc/down/reports", client.WithBreakerKey("reports"))
+	}
+	if _, err := restClient.GET("http://svc/reports", client.WithBreakerKey("reports")); err != nil {
+		t.Errorf("Expected reports breaker to stay closed, got %v", err)
+	}
+}
+
+func TestRESTClient_BreakerKeyOverridesKeyFunc(t *testing.T) {
+	restClient := client.NewClientBuilder().
+		WithTransport(failingHostTransport()).
+		WithoutRetry().
+		WithCircuitBreaker(client.CircuitBreakerConfig{Name: "api", Timeout: time.Minute, ReadyToTrip: tripAfterTwo}).
+		WithCircuitBreakerPerKey(client.BreakerKeyByHost).
+		Build()
+
+	for i := 0; i < 2; i++ {
+		restClient.POST("http://svc/down/orders", nil, client.WithBreakerKey("orders-write"))
+	}
+	if _, err := restClient.POST("http://svc/orders", nil, client.WithBreakerKey("orders-write")); !client.IsCircuitOpen(err) {
+		t.Errorf("Expected open circuit for orders-write, got %v", err)
+	}
+	if _, err := restClient.GET("http://svc/orders", client.WithBreakerKey("orders-read")); err != nil {
+		t.Errorf("Expected orders-read on the same host to be unaffected, got %v", err)
+	}
+
+	states := restClient.CircuitBreakerStates()
+	if _, ok := states["api:svc"]; ok {
+		t.Errorf("Expected host breaker not to be used for keyed requests: %v", states)
+	}
+}
+
+func TestRESTClient_CircuitBreakerState(t *testing.T) {
+	var transitions []string
+	restClient := client.NewClientBuilder().
+		WithTransport(failingHostTransport()).
+		WithoutRetry().
+		WithCircuitBreaker(client.CircuitBreakerConfig{
+			Name:        "api",
+			Timeout:     time.Minute,
+			ReadyToTrip: tripAfterTwo,
+			OnStateChange: func(name string, from, to gobreaker.State) {
+				transitions = append(transitions, name+" "+from.String()+"->"+to.String())
+			},
+		}).
+		WithCircuitBreakerPerKey(client.BreakerKeyByHost).
+		Build()
+
+	if restClient.CircuitBreakerState() != gobreaker.StateClosed {
+		t.Errorf("Expected closed breaker, got %s", restClient.CircuitBreakerState())
+	}
+
+	restClient.GET("http://up/items")
+	for i := 0; i < 2; i++ {
+		restClient.GET("http://down/items")
+	}
+
+	states := restClient.CircuitBreakerStates()
+	if states["api:down"] != gobreaker.StateOpen || states["api:up"] != gobreaker.StateClosed || states["api"] != gobreaker.StateClosed {
+		t.Errorf("Unexpected breaker states: %v", states)
+	}
+	if len(transitions) != 1 || transitions[0] != "api:down closed->open" {
+		t.Errorf("Unexpected state transitions: %v", transitions)
+	}
+
+	disabled := client.NewClientBuilder().WithoutCircuitBreaker().Build()
+	if disabled.CircuitBreakerState() != gobreaker.StateClosed || len(disabled.CircuitBreakerStates()) != 0 {
+		t.Error("Expected a client without breaker to report closed and no states")
+	}
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrBulkheadFull is returned when the client is at its concurrency limit
+// and its wait queue is full
+var ErrBulkheadFull = errors.New("too many concurrent requests")
+
+// WithMaxConcurrency limits the client to limit requests in flight at once.
+// Further requests wait for a free slot until their context is done.
+// Each attempt holds a slot, so backoff between retries does not, and a
+// streamed response holds its slot until its body is closed.
+func (b *ClientBuilder) WithMaxConcurrency(limit int) *ClientBuilder {
+	b.maxConcurrency = limit
+	return b
+}
+
+// WithMaxConcurrencyQueue bounds how many requests may wait for a slot when
+// WithMaxConcurrency is set; others fail with ErrBulkheadFull. A size of 0
+// rejects requests as soon as every slot is busy. Waiting is unbounded by default.
+func (b *ClientBuilder) WithMaxConcurrencyQueue(size int) *ClientBuilder {
+	b.maxConcurrencyQueue = &size
+	return b
+}
+
+// bulkhead is a semaphore with an optional bounded wait queue
+type bulkhead struct {
+	slots   chan struct{}
+	queue   int // Maximum waiting requests; negative means unbounded
+	waiting atomic.Int64
+}
+
+// newBulkhead creates a bulkhead allowing limit concurrent holders
+func newBulkhead(limit int, queue *int) *bulkhead {
+	b := &bulkhead{slots: make(chan struct{}, limit), queue: -1}
+	if queue != nil {
+		b.queue = *queue
+	}
+	return b
+}
+
+// acquire takes a slot, waiting while ctx allows, and returns its release function
+func (b *bulkhead) acquire(ctx context.Context) (func(), error) {
+	if b == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case b.slots <- struct{}{}:
+		return b.releaser(), nil
+	default:
+	}
+
+	if b.queue >= 0 && b.waiting.Add(1) > int64(b.queue) {
+		b.waiting.Add(-1)
+		return nil, ErrBulkheadFull
+	} else if b.queue < 0 {
+		b.waiting.Add(1)
+	}
+	defer b.waiting.Add(-1)
+
+	select {
+	case b.slots <- struct{}{}:
+		return b.releaser(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// releaser returns a function freeing one slot, safe to call more than once
+func (b *bulkhead) releaser() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-b.slots })
+	}
+}
@@ -0,0 +1,101 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+)
+
+// blockingServer holds requests until release is closed and signals each arrival
+func blockingServer(t *testing.T) (*httptest.Server, chan struct{}, chan struct{}) {
+	arrived := make(chan struct{}, 10)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+		<-release
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server, arrived, release
+}
+
+func TestRESTClient_MaxConcurrencyRejectsWhenQueueFull(t *testing.T) {
+	server, arrived, release := blockingServer(t)
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutRetry().
+		WithMaxConcurrency(1).
+		WithMaxConcurrencyQueue(0).
+		Build()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := restClient.GET("/slow")
+		done <- err
+	}()
+	<-arrived
+
+	if _, err := restClient.GET("/slow"); !errors.Is(err, client.ErrBulkheadFull) {
+		t.Errorf("Expected ErrBulkheadFull, got %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Expected first request to succeed, got %v", err)
+	}
+	if _, err := restClient.GET("/slow"); err != nil {
+		t.Errorf("Expected slot to be released, got %v", err)
+	}
+}
+
+func TestRESTClient_MaxConcurrencyWaitHonoursContext(t *testing.T) {
+	server, arrived, release := blockingServer(t)
+	defer close(release)
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutRetry().
+		WithMaxConcurrency(1).
+		Build()
+
+	go restClient.GET("/slow")
+	<-arrived
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := restClient.GET("/slow", client.WithContext(ctx)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected waiting request to stop at its deadline, got %v", err)
+	}
+}
+
+func TestRESTClient_MaxConcurrencyStreamHoldsSlot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("stream"))
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutRetry().
+		WithMaxConcurrency(1).
+		WithMaxConcurrencyQueue(0).
+		Build()
+
+	stream, err := restClient.GET("/stream", client.WithStreamResponse())
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	if _, err := restClient.GET("/other"); !errors.Is(err, client.ErrBulkheadFull) {
+		t.Errorf("Expected open stream to hold the slot, got %v", err)
+	}
+	stream.Close()
+	if _, err := restClient.GET("/other"); err != nil {
+		t.Errorf("Expected slot to be released after Close, got %v", err)
+	}
+}
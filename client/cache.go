@@ -0,0 +1,264 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khekrn/core/cache"
+	"github.com/khekrn/core/clock"
+)
+
+// CachedResponse is a response stored by the HTTP cache
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Vary       map[string]string // Request header values named by the Vary response header
+	Expires    time.Time         // Fresh until this time; revalidated afterwards
+}
+
+// CacheStore stores cached responses by key
+type CacheStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, entry *CachedResponse)
+	Delete(key string)
+}
+
+// WithCache caches GET responses in store, honouring Cache-Control, Expires,
+// and Vary. Stale entries with an ETag or Last-Modified are revalidated with
+// a conditional request and a 304 is returned to the caller as the cached 200.
+// Streamed responses are not cached.
+//
+// The cache is shared by every caller of the client, so private responses are
+// never stored, and responses to requests carrying an Authorization header
+// only when marked public or with s-maxage.
+func (b *ClientBuilder) WithCache(store CacheStore) *ClientBuilder {
+	b.cache = store
+	return b
+}
+
+// cacheMiddleware serves and stores GET responses using store, judging
+// freshness by clk
+func cacheMiddleware(store CacheStore, clk clock.Clock) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*Response, error) {
+			if req.Method != http.MethodGet || hasCacheDirective(req.Header, "no-store") || req.Header.Get("Range") != "" {
+				return next(req)
+			}
+
+			key := req.URL.String()
+			entry, ok := store.Get(key)
+			if ok && !entry.matches(req) {
+				entry, ok = nil, false
+			}
+			if ok && clk.Now().Before(entry.Expires) && !hasCacheDirective(req.Header, "no-cache") {
+				return entry.response(req), nil
+			}
+
+			conditional := req
+			if ok {
+				conditional = req.Clone(req.Context())
+				if etag := entry.Header.Get("ETag"); etag != "" && req.Header.Get("If-None-Match") == "" {
+					conditional.Header.Set("If-None-Match", etag)
+				}
+				if modified := entry.Header.Get("Last-Modified"); modified != "" && req.Header.Get("If-Modified-Since") == "" {
+					conditional.Header.Set("If-Modified-Since", modified)
+				}
+			}
+
+			resp, err := next(conditional)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok && resp.StatusCode == http.StatusNotModified && conditional != req {
+				// Refresh the stored headers and freshness from the 304
+				for name, values := range resp.Headers {
+					entry.Header[name] = values
+				}
+				now := clk.Now()
+				entry.Expires = now.Add(freshness(entry.Header, now))
+				store.Set(key, entry)
+				return entry.response(req), nil
+			}
+
+			if resp.StatusCode == http.StatusOK && resp.Body != nil {
+				// conditional carries any credentials applied by inner middleware
+				if !sharable(conditional, resp.Headers) {
+					store.Delete(key)
+				} else if stored, cacheable := newCachedResponse(req, resp, clk.Now()); cacheable {
+					store.Set(key, stored)
+				} else {
+					store.Delete(key)
+				}
+			}
+			return resp, nil
+		}
+	}
+}
+
+// sharable reports whether a response to req may be served to other callers:
+// private responses never are, and responses to requests with credentials
+// only when the server marks them public or gives them an s-maxage
+func sharable(req *http.Request, header http.Header) bool {
+	if hasCacheDirective(header, "private") {
+		return false
+	}
+	if req.Header.Get("Authorization") == "" {
+		return true
+	}
+	return hasCacheDirective(header, "public") || hasCacheDirective(header, "s-maxage")
+}
+
+// newCachedResponse builds a cache entry for resp received at now and
+// reports whether it may be stored
+func newCachedResponse(req *http.Request, resp *Response, now time.Time) (*CachedResponse, bool) {
+	if hasCacheDirective(resp.Headers, "no-store") || resp.Headers.Get("Vary") == "*" {
+		return nil, false
+	}
+	lifetime := freshness(resp.Headers, now)
+	if lifetime <= 0 && resp.Headers.Get("ETag") == "" && resp.Headers.Get("Last-Modified") == "" {
+		return nil, false
+	}
+
+	entry := &CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Headers.Clone(),
+		Body:       append([]byte{}, resp.Body...),
+		Expires:    now.Add(lifetime),
+	}
+	for _, name := range varyHeaders(resp.Headers) {
+		if entry.Vary == nil {
+			entry.Vary = make(map[string]string)
+		}
+		entry.Vary[name] = req.Header.Get(name)
+	}
+	return entry, true
+}
+
+// matches reports whether req sends the header values the entry varies on
+func (e *CachedResponse) matches(req *http.Request) bool {
+	for name, value := range e.Vary {
+		if req.Header.Get(name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// response returns a copy of the entry as a buffered Response
+func (e *CachedResponse) response(req *http.Request) *Response {
+	header := e.Header.Clone()
+	body := append([]byte{}, e.Body...)
+	return &Response{
+		Response: &http.Response{
+			Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+			StatusCode:    e.StatusCode,
+			Header:        header,
+			ContentLength: int64(len(body)),
+			Request:       req,
+		},
+		Body:       body,
+		StatusCode: e.StatusCode,
+		Headers:    header,
+	}
+}
+
+// freshness returns how long a response stays fresh from its caching
+// headers. now stands in for a missing Date header.
+func freshness(header http.Header, now time.Time) time.Duration {
+	if hasCacheDirective(header, "no-cache") {
+		return 0
+	}
+	if maxAge, ok := cacheDirective(header, "max-age"); ok {
+		if seconds, err := strconv.Atoi(maxAge); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		return 0
+	}
+	if expires := header.Get("Expires"); expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			return 0
+		}
+		date := now
+		if parsed, err := http.ParseTime(header.Get("Date")); err == nil {
+			date = parsed
+		}
+		return expiresAt.Sub(date)
+	}
+	return 0
+}
+
+// varyHeaders returns the request header names listed in Vary
+func varyHeaders(header http.Header) []string {
+	var names []string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
+
+// hasCacheDirective reports whether Cache-Control contains directive
+func hasCacheDirective(header http.Header, directive string) bool {
+	_, ok := cacheDirective(header, directive)
+	return ok
+}
+
+// cacheDirective returns the value of a Cache-Control directive
+func cacheDirective(header http.Header, directive string) (string, bool) {
+	for _, value := range header.Values("Cache-Control") {
+		for _, part := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+			if strings.EqualFold(name, directive) {
+				return strings.Trim(arg, `"`), true
+			}
+		}
+	}
+	return "", false
+}
+
+// MemoryCache is an in-memory LRU CacheStore backed by cache.Cache
+type MemoryCache struct {
+	entries *cache.Cache[string, *CachedResponse]
+}
+
+// NewMemoryCache creates an in-memory LRU cache holding up to capacity responses
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &MemoryCache{entries: cache.New[string, *CachedResponse](cache.WithMaxEntries(capacity))}
+}
+
+// Get returns the entry for key, marking it recently used
+func (c *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	stored, ok := c.entries.Get(key)
+	if !ok {
+		return nil, false
+	}
+	entry := *stored
+	entry.Header = entry.Header.Clone()
+	return &entry, true
+}
+
+// Set stores entry under key, evicting the least recently used entry when full
+func (c *MemoryCache) Set(key string, entry *CachedResponse) {
+	c.entries.Set(key, entry)
+}
+
+// Delete removes the entry for key
+func (c *MemoryCache) Delete(key string) {
+	c.entries.Delete(key)
+}
+
+// Len returns the number of cached responses
+func (c *MemoryCache) Len() int {
+	return c.entries.Len()
+}
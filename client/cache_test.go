@@ -0,0 +1,155 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+	"github.com/khekrn/core/coretest"
+)
+
+func TestRESTClient_Cache(t *testing.T) {
+	var hits, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/fresh":
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Write([]byte("fresh"))
+		case "/etag":
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Cache-Control", "no-cache")
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Write([]byte("tagged"))
+		case "/me":
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Write([]byte(r.Header.Get("Authorization")))
+		case "/private":
+			w.Header().Set("Cache-Control", "private, max-age=60")
+			w.Write([]byte("mine"))
+		case "/catalog":
+			w.Header().Set("Cache-Control", "public, max-age=60")
+			w.Write([]byte("catalog"))
+		case "/no-store":
+			w.Header().Set("Cache-Control", "no-store")
+			w.Write([]byte("secret"))
+		}
+	}))
+	defer server.Close()
+
+	store := client.NewMemoryCache(10)
+	clk := coretest.NewClock(time.Time{})
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithCache(store).
+		WithClock(clk).
+		WithoutCircuitBreaker().
+		Build()
+
+	get := func(path string) *client.Response {
+		t.Helper()
+		resp, err := restClient.GET(path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		return resp
+	}
+
+	t.Run("fresh responses are served from cache", func(t *testing.T) {
+		hits.Store(0)
+		get("/fresh")
+		resp := get("/fresh")
+		if resp.String() != "fresh" || resp.StatusCode != http.StatusOK {
+			t.Errorf("Unexpected cached response: %d %q", resp.StatusCode, resp.String())
+		}
+		if hits.Load() != 1 {
+			t.Errorf("Expected 1 server hit, got %d", hits.Load())
+		}
+
+		clk.Advance(61 * time.Second)
+		get("/fresh")
+		if hits.Load() != 2 {
+			t.Errorf("Expected the expired entry to be fetched again, got %d hits", hits.Load())
+		}
+	})
+
+	t.Run("stale responses are revalidated", func(t *testing.T) {
+		hits.Store(0)
+		get("/etag")
+		resp := get("/etag")
+		if resp.String() != "tagged" || resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected 304 to be served as cached 200, got %d %q", resp.StatusCode, resp.String())
+		}
+		if hits.Load() != 2 || notModified.Load() != 1 {
+			t.Errorf("Expected a conditional request answered with 304, got %d hits and %d 304s", hits.Load(), notModified.Load())
+		}
+	})
+
+	t.Run("authenticated responses are not shared between callers", func(t *testing.T) {
+		hits.Store(0)
+		alice, err := restClient.GET("/me", client.WithHeader("Authorization", "Bearer alice"))
+		if err != nil {
+			t.Fatalf("GET /me failed: %v", err)
+		}
+		bob, err := restClient.GET("/me", client.WithHeader("Authorization", "Bearer bob"))
+		if err != nil {
+			t.Fatalf("GET /me failed: %v", err)
+		}
+		if alice.String() != "Bearer alice" || bob.String() != "Bearer bob" {
+			t.Errorf("Expected each caller to get their own response, got %q and %q", alice.String(), bob.String())
+		}
+		if hits.Load() != 2 {
+			t.Errorf("Expected 2 server hits, got %d", hits.Load())
+		}
+
+		hits.Store(0)
+		restClient.GET("/catalog", client.WithHeader("Authorization", "Bearer alice"))
+		restClient.GET("/catalog", client.WithHeader("Authorization", "Bearer bob"))
+		if hits.Load() != 1 {
+			t.Errorf("Expected a public response to be cached, got %d server hits", hits.Load())
+		}
+	})
+
+	t.Run("private responses are not cached", func(t *testing.T) {
+		hits.Store(0)
+		get("/private")
+		get("/private")
+		if hits.Load() != 2 {
+			t.Errorf("Expected 2 server hits, got %d", hits.Load())
+		}
+	})
+
+	t.Run("no-store responses are not cached", func(t *testing.T) {
+		hits.Store(0)
+		get("/no-store")
+		get("/no-store")
+		if hits.Load() != 2 {
+			t.Errorf("Expected 2 server hits, got %d", hits.Load())
+		}
+	})
+}
+
+func TestMemoryCache_Eviction(t *testing.T) {
+	cache := client.NewMemoryCache(2)
+	cache.Set("a", &client.CachedResponse{Body: []byte("a")})
+	cache.Set("b", &client.CachedResponse{Body: []byte("b")})
+	cache.Get("a")
+	cache.Set("c", &client.CachedResponse{Body: []byte("c")})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Expected recently used entry to remain")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", cache.Len())
+	}
+}
@@ -40,15 +40,22 @@ package client
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	ddhttp "github.com/DataDog/dd-trace-go/contrib/net/http/v2"
+	"github.com/khekrn/core/buildinfo"
+	"github.com/khekrn/core/clock"
 	"github.com/khekrn/core/helpers"
+	"github.com/khekrn/core/idempotency"
+	"github.com/khekrn/core/ratelimit"
 	"github.com/sony/gobreaker/v2"
+	"go.uber.org/zap"
 )
 
 // HTTPMethod represents supported HTTP methods
@@ -65,12 +72,30 @@ const (
 	OPTIONS HTTPMethod = "OPTIONS" // OPTIONS method for capability discovery
 )
 
+// JitterStrategy controls how retry delays are randomized
+type JitterStrategy = helpers.JitterStrategy
+
+// Supported jitter strategies
+const (
+	JitterNone         = helpers.JitterNone         // Deterministic exponential backoff
+	JitterFull         = helpers.JitterFull         // Random delay between 0 and the backoff
+	JitterEqual        = helpers.JitterEqual        // Half the backoff plus a random half
+	JitterDecorrelated = helpers.JitterDecorrelated // Random delay between the initial backoff and 3x the previous delay
+)
+
 // RetryConfig holds configuration for retry behavior
 type RetryConfig struct {
 	MaxAttempts    int
 	InitialBackoff time.Duration
 	MaxBackoff     time.Duration
 	BackoffFactor  float64
+	Jitter         JitterStrategy
+	// RetryUnsafeMethods allows retrying POST and PATCH requests that carry
+	// no Idempotency-Key header
+	RetryUnsafeMethods bool
+	// ShouldRetry replaces the default classification, including the
+	// idempotent method check; see DefaultShouldRetry
+	ShouldRetry func(resp *Response, err error, attempt int) bool
 }
 
 // CircuitBreakerConfig holds circuit breaker configuration
@@ -80,34 +105,72 @@ type CircuitBreakerConfig struct {
 	Interval    time.Duration
 	Timeout     time.Duration
 	ReadyToTrip func(counts gobreaker.Counts) bool
+	// OnStateChange is called when the breaker changes state, e.g. to export metrics
+	OnStateChange func(name string, from, to gobreaker.State)
 }
 
 // RequestConfig holds configuration for a single request
 type RequestConfig struct {
-	Method      HTTPMethod
-	URL         string
-	Body        interface{}
-	Headers     map[string]string
-	QueryParams map[string]string
-	Timeout     time.Duration
-	Context     context.Context
+	Method            HTTPMethod
+	URL               string
+	Body              interface{}
+	Headers           map[string]string
+	QueryParams       map[string]string
+	Timeout           time.Duration // Deadline for the whole request, including retries
+	Context           context.Context
+	Stream            bool
+	Retry             *RetryConfig // Overrides the client retry configuration
+	NoRetry           bool         // Disables retries for this request
+	Codec             Codec        // Overrides the client codec
+	BreakerKey        string       // Selects a per-key circuit breaker
+	ErrorOnNonSuccess bool         // Returns an *HTTPError for non-2xx responses
 }
 
-// Response wraps HTTP response with additional metadata
+// Response wraps HTTP response with additional metadata.
+// Streamed responses leave Body nil; read them with Reader and Close them.
 type Response struct {
 	*http.Response
 	Body       []byte
 	StatusCode int
 	Headers    http.Header
+	codec      Codec
+	attempts   int
+	redactor   *redactor
 }
 
 // RESTClient provides a full-featured HTTP client
 type RESTClient struct {
-	client         *http.Client
-	baseURL        string
-	defaultHeaders map[string]string
-	retry          *RetryConfig
-	circuitBreaker *gobreaker.CircuitBreaker[*http.Response]
+	client            *http.Client
+	baseURL           string
+	defaultHeaders    map[string]string
+	retry             *RetryConfig
+	circuitBreaker    *gobreaker.CircuitBreaker[*http.Response]
+	breakers          *breakerGroup
+	errorOnNonSuccess bool
+	middleware        []Middleware
+	hooks             Hooks
+	auth              AuthProvider
+	signer            Signer
+	codec             Codec
+	cache             CacheStore
+	transport         *http.Transport // Configured transport reused by Dial, if any
+	datadog           bool
+	requestIDHeader   string
+	generateRequestID bool
+	retryBudget       *retryBudget
+	bulkhead          *bulkhead
+	rateLimiter       ratelimit.Limiter
+	fallback          FallbackFunc
+	health            *healthChecker
+	endpoints         *endpointSet
+	debugLog          *debugLogger
+	accessLog         *accessLogger
+	redactor          *redactor
+	curlDump          *curlDumper
+	harRecorder       *HARRecorder
+	baseTransport     http.RoundTripper // Configured transport before Datadog wrapping, shared by derived clients
+	config            *ClientBuilder    // Snapshot of the builder, inherited by FromSharedClient
+	clock             clock.Clock
 }
 
 // ClientBuilder provides a fluent interface for building REST clients
@@ -122,6 +185,39 @@ type ClientBuilder struct {
 	defaultHeaders      map[string]string
 	retry               *RetryConfig
 	circuitBreaker      *CircuitBreakerConfig
+	middleware          []Middleware
+	hooks               Hooks
+	oauth2              *OAuth2Config
+	auth                AuthProvider
+	signer              Signer
+	proxy               func(*http.Request) (*url.URL, error)
+	tlsConfig           *tls.Config
+	decompressors       []encodingDecompressor
+	codec               Codec
+	cache               CacheStore
+	breakerKeyFunc      BreakerKeyFunc
+	breakerOverrides    map[string]CircuitBreakerConfig
+	errorOnNonSuccess   bool
+	requestIDHeader     string
+	generateRequestID   bool
+	retryBudget         *RetryBudgetConfig
+	maxConcurrency      int
+	maxConcurrencyQueue *int
+	rateLimiter         ratelimit.Limiter
+	fallback            FallbackFunc
+	healthCheck         *healthCheckConfig
+	endpoints           []Endpoint
+	balanceStrategy     BalanceStrategy
+	endpointCooldown    time.Duration
+	redaction           Redaction
+	debugLog            bool
+	debugLogger         *zap.Logger
+	debugBodyLimit      int
+	accessLog           bool
+	accessLogger        *zap.Logger
+	curlDump            io.Writer
+	harRecorder         *HARRecorder
+	clock               clock.Clock
 }
 
 // NewClientBuilder creates a new client builder with sensible defaults including retry and circuit breaker
@@ -132,7 +228,7 @@ func NewClientBuilder() *ClientBuilder {
 		maxIdleConnsPerHost: 100,
 		idleConnTimeout:     90 * time.Second,
 		enableDatadog:       false,
-		defaultHeaders:      make(map[string]string),
+		defaultHeaders:      map[string]string{"User-Agent": buildinfo.Get().UserAgent()},
 		retry: &RetryConfig{
 			MaxAttempts:    3,
 			InitialBackoff: 100 * time.Millisecond,
@@ -152,84 +248,94 @@ func NewClientBuilder() *ClientBuilder {
 	}
 }
 
-// FromSharedClient creates a new client builder that inherits configuration from an existing RESTClient
+// FromSharedClient creates a new client builder that inherits configuration from an existing RESTClient.
+// The derived client shares the connection pool and copies default headers,
+// timeout, retry, retry budget, circuit breaker settings, concurrency limit,
+// middleware, hooks, and codec; any of them can be overridden on the
+// returned builder, e.g. with WithoutRetry. It gets its own circuit
+// breakers, retry budget, and concurrency slots rather than sharing state.
 func FromSharedClient(restClient *RESTClient, name string, baseURL string) *ClientBuilder {
-	builder := &ClientBuilder{
-		// Share the underlying HTTP client for connection pooling efficiency
-		transport:      restClient.client.Transport,
-		timeout:        restClient.client.Timeout,
-		enableDatadog:  detectDatadogEnabled(restClient.client),
-		baseURL:        baseURL,
-		defaultHeaders: make(map[string]string),
-	}
-
-	// If no baseURL provided, inherit from the shared client
-	if baseURL == "" {
-		builder.baseURL = restClient.baseURL
-	}
+	builder := restClient.config.clone()
 
-	// Copy default headers
-	for k, v := range restClient.defaultHeaders {
-		builder.defaultHeaders[k] = v
-	}
+	// Share the underlying transport for connection pooling efficiency. Its
+	// proxy, TLS, and decompression settings are already applied.
+	builder.transport = restClient.baseTransport
+	builder.proxy = nil
+	builder.tlsConfig = nil
+	builder.decompressors = nil
 
-	// Copy retry configuration if present
-	if restClient.retry != nil {
-		builder.retry = &RetryConfig{
-			MaxAttempts:    restClient.retry.MaxAttempts,
-			InitialBackoff: restClient.retry.InitialBackoff,
-			MaxBackoff:     restClient.retry.MaxBackoff,
-			BackoffFactor:  restClient.retry.BackoffFactor,
-		}
+	// If no baseURL provided, inherit from the shared client
+	if baseURL != "" {
+		builder.baseURL = baseURL
+		builder.endpoints = nil
 	}
 
-	// Extract connection settings from existing transport if it's a standard HTTP transport
-	if transport, ok := restClient.client.Transport.(*http.Transport); ok {
-		builder.maxIdleConns = transport.MaxIdleConns
-		builder.maxIdleConnsPerHost = transport.MaxIdleConnsPerHost
-		builder.idleConnTimeout = transport.IdleConnTimeout
-	} else {
-		// Set defaults if we can't extract from transport
-		builder.maxIdleConns = 100
-		builder.maxIdleConnsPerHost = 100
-		builder.idleConnTimeout = 90 * time.Second
-	}
+	// Don't copy auth provider, signer, cache, or health check - each service should have its own
+	builder.oauth2 = nil
+	builder.auth = nil
+	builder.signer = nil
+	builder.cache = nil
+	builder.healthCheck = nil
 
-	// Don't copy circuit breaker - each service should have its own
 	// Use the provided name for the circuit breaker
-	circuitBreakerName := name
-	if circuitBreakerName == "" {
-		circuitBreakerName = "shared-client-derived"
-	}
-
-	builder.circuitBreaker = &CircuitBreakerConfig{
-		Name:        circuitBreakerName,
-		MaxRequests: 3,
-		Interval:    10 * time.Second,
-		Timeout:     60 * time.Second,
-		ReadyToTrip: func(counts gobreaker.Counts) bool {
-			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
-			return counts.Requests >= 3 && failureRatio >= 0.6
-		},
+	if builder.circuitBreaker != nil {
+		builder.circuitBreaker.Name = name
+		if name == "" {
+			builder.circuitBreaker.Name = "shared-client-derived"
+		}
 	}
 
 	return builder
 }
 
-// detectDatadogEnabled attempts to detect if Datadog tracing is enabled on the HTTP client
-func detectDatadogEnabled(client *http.Client) bool {
-	if client == nil || client.Transport == nil {
-		return false
+// clone returns a copy of the builder that shares no mutable state with it
+func (b *ClientBuilder) clone() *ClientBuilder {
+	clone := *b
+	clone.defaultHeaders = make(map[string]string, len(b.defaultHeaders))
+	for k, v := range b.defaultHeaders {
+		clone.defaultHeaders[k] = v
 	}
-
-	// Check if the transport is wrapped by Datadog
-	// ddhttp.WrapClient creates a wrapper transport, so we need to check the type
-	transportType := fmt.Sprintf("%T", client.Transport)
-
-	// Datadog wrapped transports typically have "datadog" or "dd" in their type name
-	return strings.Contains(strings.ToLower(transportType), "datadog") ||
-		strings.Contains(strings.ToLower(transportType), "dd") ||
-		strings.Contains(strings.ToLower(transportType), "roundtripper")
+	clone.middleware = append([]Middleware(nil), b.middleware...)
+	clone.decompressors = append([]encodingDecompressor(nil), b.decompressors...)
+	clone.endpoints = append([]Endpoint(nil), b.endpoints...)
+	clone.redaction = Redaction{
+		Headers: append([]string(nil), b.redaction.Headers...),
+		Fields:  append([]string(nil), b.redaction.Fields...),
+	}
+	if b.retry != nil {
+		retry := *b.retry
+		clone.retry = &retry
+	}
+	if b.retryBudget != nil {
+		budget := *b.retryBudget
+		clone.retryBudget = &budget
+	}
+	if b.circuitBreaker != nil {
+		breaker := *b.circuitBreaker
+		clone.circuitBreaker = &breaker
+	}
+	if b.breakerOverrides != nil {
+		clone.breakerOverrides = make(map[string]CircuitBreakerConfig, len(b.breakerOverrides))
+		for k, v := range b.breakerOverrides {
+			clone.breakerOverrides[k] = v
+		}
+	}
+	if b.maxConcurrencyQueue != nil {
+		queue := *b.maxConcurrencyQueue
+		clone.maxConcurrencyQueue = &queue
+	}
+	if b.tlsConfig != nil {
+		clone.tlsConfig = b.tlsConfig.Clone()
+	}
+	if b.oauth2 != nil {
+		oauth2 := *b.oauth2
+		clone.oauth2 = &oauth2
+	}
+	if b.healthCheck != nil {
+		healthCheck := *b.healthCheck
+		clone.healthCheck = &healthCheck
+	}
+	return &clone
 }
 
 // WithTimeout sets the client timeout
@@ -280,6 +386,18 @@ func (b *ClientBuilder) WithDefaultHeader(key, value string) *ClientBuilder {
 	return b
 }
 
+// WithUserAgent sets the User-Agent header of all requests. It defaults to
+// the service's build, e.g. "orders/1.4.2 (abc1234; go1.24.4)", from
+// buildinfo.Get; an empty agent restores Go's default.
+func (b *ClientBuilder) WithUserAgent(agent string) *ClientBuilder {
+	if agent == "" {
+		delete(b.defaultHeaders, "User-Agent")
+		return b
+	}
+	b.defaultHeaders["User-Agent"] = agent
+	return b
+}
+
 // WithDefaultHeaders sets multiple default headers
 func (b *ClientBuilder) WithDefaultHeaders(headers map[string]string) *ClientBuilder {
 	for k, v := range headers {
@@ -311,6 +429,15 @@ func (b *ClientBuilder) WithDefaultRetry() *ClientBuilder {
 	return b
 }
 
+// WithClock sets the time source for retry backoff, event stream
+// reconnects, response cache freshness, and endpoint cooldowns, e.g. a fake
+// clock in tests.
+// Defaults to clock.System.
+func (b *ClientBuilder) WithClock(c clock.Clock) *ClientBuilder {
+	b.clock = c
+	return b
+}
+
 // WithCircuitBreaker configures circuit breaker
 func (b *ClientBuilder) WithCircuitBreaker(config CircuitBreakerConfig) *ClientBuilder {
 	b.circuitBreaker = &config
@@ -351,6 +478,8 @@ func (b *ClientBuilder) Build() *RESTClient {
 			IdleConnTimeout:     b.idleConnTimeout,
 		}
 	}
+	transport = b.configureTransport(transport)
+	config := b.clone()
 
 	client := &http.Client{
 		Timeout:   b.timeout,
@@ -362,22 +491,71 @@ func (b *ClientBuilder) Build() *RESTClient {
 	}
 
 	restClient := &RESTClient{
-		client:         client,
-		baseURL:        b.baseURL,
-		defaultHeaders: b.defaultHeaders,
-		retry:          b.retry,
+		client:            client,
+		baseURL:           b.baseURL,
+		defaultHeaders:    b.defaultHeaders,
+		retry:             b.retry,
+		middleware:        b.middleware,
+		hooks:             b.hooks,
+		auth:              b.auth,
+		signer:            b.signer,
+		codec:             b.codec,
+		cache:             b.cache,
+		errorOnNonSuccess: b.errorOnNonSuccess,
+		transport:         unwrapTransport(transport),
+		datadog:           b.enableDatadog,
+		requestIDHeader:   b.requestIDHeader,
+		generateRequestID: b.generateRequestID,
+		fallback:          b.fallback,
+		rateLimiter:       b.rateLimiter,
+		harRecorder:       b.harRecorder,
+		baseTransport:     transport,
+		config:            config,
+		clock:             clock.OrSystem(b.clock),
+	}
+
+	if b.oauth2 != nil {
+		restClient.auth = newOAuth2TokenSource(*b.oauth2, client)
+	}
+
+	restClient.redactor = newRedactor(b.redaction)
+	if b.debugLog {
+		limit := b.debugBodyLimit
+		if limit <= 0 {
+			limit = DefaultDebugBodyLimit
+		}
+		restClient.debugLog = &debugLogger{log: b.debugLogger, bodyLimit: limit, redactor: restClient.redactor}
+	}
+	if b.accessLog {
+		restClient.accessLog = &accessLogger{log: b.accessLogger, redactor: restClient.redactor, client: restClient}
+	}
+	if b.curlDump != nil {
+		restClient.curlDump = &curlDumper{w: b.curlDump, redactor: restClient.redactor}
+	}
+	if len(b.endpoints) > 0 {
+		restClient.endpoints = newEndpointSet(append([]Endpoint(nil), b.endpoints...), b.balanceStrategy, b.endpointCooldown, restClient.clock)
+		restClient.baseURL = b.endpoints[0].URL
+	}
+	if b.maxConcurrency > 0 {
+		restClient.bulkhead = newBulkhead(b.maxConcurrency, b.maxConcurrencyQueue)
+	}
+	if b.retryBudget != nil {
+		restClient.retryBudget = newRetryBudget(*b.retryBudget)
 	}
 
 	// Configure circuit breaker if specified
 	if b.circuitBreaker != nil {
-		settings := gobreaker.Settings{
-			Name:        b.circuitBreaker.Name,
-			MaxRequests: b.circuitBreaker.MaxRequests,
-			Interval:    b.circuitBreaker.Interval,
-			Timeout:     b.circuitBreaker.Timeout,
-			ReadyToTrip: b.circuitBreaker.ReadyToTrip,
+		restClient.circuitBreaker = newCircuitBreaker(*b.circuitBreaker)
+		restClient.breakers = &breakerGroup{
+			config:    *b.circuitBreaker,
+			keyFunc:   b.breakerKeyFunc,
+			overrides: b.breakerOverrides,
+			breakers:  make(map[string]*gobreaker.CircuitBreaker[*http.Response]),
 		}
-		restClient.circuitBreaker = gobreaker.NewCircuitBreaker[*http.Response](settings)
+	}
+
+	if b.healthCheck != nil && b.healthCheck.interval > 0 {
+		restClient.startHealthCheck(*b.healthCheck)
 	}
 
 	return restClient
@@ -393,34 +571,66 @@ func (rc *RESTClient) GetInstance() *http.Client {
 	return rc.client
 }
 
-// buildURL constructs the full URL from base URL and path
+// buildURL constructs the full URL from base URL and path; absolute URLs are used as is
 func (rc *RESTClient) buildURL(path string) string {
-	if rc.baseURL == "" {
+	if rc.baseURL == "" || isAbsoluteURL(path) {
 		return path
 	}
 	return rc.baseURL + "/" + strings.TrimPrefix(path, "/")
 }
 
+// isAbsoluteURL reports whether path is a full http(s) or ws(s) URL
+func isAbsoluteURL(path string) bool {
+	for _, scheme := range []string{"http://", "https://", "ws://", "wss://"} {
+		if strings.HasPrefix(path, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
 // createRequest creates an HTTP request with proper headers and body
 func (rc *RESTClient) createRequest(config RequestConfig) (*http.Request, error) {
-	url := rc.buildURL(config.URL)
+	fullURL := rc.buildURL(config.URL)
+	codec, explicitCodec := rc.resolveCodec(config)
 
 	var body io.Reader
+	var contentType string
 	if config.Body != nil {
 		switch v := config.Body.(type) {
+		case *Multipart:
+			data, multipartType, err := v.encode()
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(data)
+			contentType = multipartType
+		case *BodyReader:
+			// The caller owns the reader; keep the transport from closing it
+			body = io.NopCloser(v.Reader)
+		case url.Values:
+			body = strings.NewReader(v.Encode())
 		case string:
 			body = strings.NewReader(v)
 		case []byte:
 			body = bytes.NewReader(v)
+		case *bytes.Reader, *bytes.Buffer, *strings.Reader:
+			// http.NewRequest sets GetBody for these
+			body = v.(io.Reader)
 		case io.Reader:
-			body = v
+			// Buffer so retries and redirects can resend the payload; use
+			// WithBodyReader to stream large bodies instead
+			data, err := io.ReadAll(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read body: %w", err)
+			}
+			body = bytes.NewReader(data)
 		default:
-			// JSON encode the body using helpers package
-			jsonData, err := helpers.ToJSON(config.Body)
+			data, err := codec.Marshal(config.Body)
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal body: %w", err)
 			}
-			body = bytes.NewReader(jsonData)
+			body = bytes.NewReader(data)
 		}
 	}
 
@@ -428,12 +638,17 @@ func (rc *RESTClient) createRequest(config RequestConfig) (*http.Request, error)
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx = withBreakerKey(ctx, config.BreakerKey)
 
-	req, err := http.NewRequestWithContext(ctx, string(config.Method), url, body)
+	req, err := http.NewRequestWithContext(ctx, string(config.Method), fullURL, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	if v, ok := config.Body.(*BodyReader); ok {
+		v.apply(req)
+	}
+
 	// Add default headers
 	for k, v := range rc.defaultHeaders {
 		req.Header.Set(k, v)
@@ -444,6 +659,8 @@ func (rc *RESTClient) createRequest(config RequestConfig) (*http.Request, error)
 		req.Header.Set(k, v)
 	}
 
+	rc.setRequestID(req)
+
 	// Add query parameters
 	if len(config.QueryParams) > 0 {
 		q := req.URL.Query()
@@ -453,68 +670,137 @@ func (rc *RESTClient) createRequest(config RequestConfig) (*http.Request, error)
 		req.URL.RawQuery = q.Encode()
 	}
 
-	// Set JSON content type if body was auto-marshaled
+	if explicitCodec && req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", codec.ContentType())
+	}
+
+	// Set the codec content type if body was auto-marshaled
 	if config.Body != nil && req.Header.Get("Content-Type") == "" {
 		switch config.Body.(type) {
-		case string, []byte, io.Reader:
+		case string, []byte, io.Reader, *BodyReader:
 			// Don't auto-set content type for raw data
+		case *Multipart:
+			req.Header.Set("Content-Type", contentType)
+		case url.Values:
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 		default:
-			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Content-Type", codec.ContentType())
 		}
 	}
 
 	return req, nil
 }
 
-// executeWithRetry executes a request with retry logic
-func (rc *RESTClient) executeWithRetry(req *http.Request) (*Response, error) {
+// execute runs a request, retrying failed attempts when retry is set, and
+// reports each attempt to the client's hooks
+func (rc *RESTClient) execute(req *http.Request, retry *RetryConfig, handler Handler) (*Response, error) {
+	start := time.Now()
 	var lastErr error
+	var delay time.Duration
+	rc.retryBudget.request()
+
+	for attempt := 1; attempt <= retry.maxAttempts(); attempt++ {
+		if attempt > 1 {
+			if !rc.retryBudget.withdraw() {
+				err := fmt.Errorf("%w: %w", ErrRetryBudgetExhausted, lastErr)
+				rc.hooks.retryBudgetExhausted(requestInfo(req, attempt-1, start), err)
+				rc.hooks.error(requestInfo(req, attempt-1, start), err)
+				return nil, err
+			}
 
-	for attempt := 0; attempt < rc.getMaxAttempts(); attempt++ {
-		if attempt > 0 {
 			// Calculate backoff delay
-			delay := rc.calculateBackoff(attempt)
+			delay = retry.backoff(attempt-1, delay)
+			rc.hooks.retry(requestInfo(req, attempt-1, start), delay, lastErr)
 			select {
-			case <-time.After(delay):
+			case <-rc.clock.After(delay):
 			case <-req.Context().Done():
+				rc.hooks.error(requestInfo(req, attempt-1, start), req.Context().Err())
 				return nil, req.Context().Err()
 			}
+
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					lastErr = fmt.Errorf("failed to reset request body: %w", err)
+					break
+				}
+				req.Body = body
+			}
+		}
+
+		rc.hooks.request(requestInfo(req, attempt, start))
+		resp, err := handler(req)
+		if err == nil {
+			resp.attempts = attempt
+			rc.hooks.response(requestInfo(req, attempt, start), resp)
+		}
+
+		if retry == nil {
+			if err != nil {
+				rc.hooks.error(requestInfo(req, attempt, start), err)
+			}
+			return resp, err
 		}
 
-		resp, err := rc.executeRequest(req)
-		if err == nil && !rc.shouldRetry(resp.StatusCode) {
-			return resp, nil
+		// A consumed body that cannot be reopened must not be resent empty,
+		// so the caller gets this attempt's outcome as is
+		if !retry.shouldRetry(req, resp, err, attempt) || !canReplayBody(req) {
+			if err != nil {
+				rc.hooks.error(requestInfo(req, attempt, start), err)
+			}
+			return resp, err
 		}
 
 		lastErr = err
 		if err == nil {
-			lastErr = fmt.Errorf("HTTP %d", resp.StatusCode)
+			lastErr = rc.redactor.newHTTPError(req, resp, attempt)
+			resp.Close()
 		}
 	}
 
-	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
+	err := fmt.Errorf("%w: %w", ErrMaxRetriesExceeded, lastErr)
+	rc.hooks.error(requestInfo(req, retry.maxAttempts(), start), err)
+	return nil, err
 }
 
 // executeRequest executes a single HTTP request
-func (rc *RESTClient) executeRequest(req *http.Request) (*Response, error) {
+func (rc *RESTClient) executeRequest(req *http.Request, stream, contextDeadline bool) (*Response, error) {
 	var resp *http.Response
 	var err error
 
-	if rc.circuitBreaker != nil {
-		result, cbErr := rc.circuitBreaker.Execute(func() (*http.Response, error) {
-			return rc.client.Do(req)
+	client := rc.client
+	if stream || contextDeadline {
+		// The client timeout covers reading the body, which would abort
+		// long downloads; streamed requests and requests with their own
+		// timeout rely on the context deadline
+		deadlineClient := *rc.client
+		deadlineClient.Timeout = 0
+		client = &deadlineClient
+	}
+
+	if cb := rc.breakerFor(req); cb != nil {
+		result, cbErr := cb.Execute(func() (*http.Response, error) {
+			return client.Do(req)
 		})
 		if cbErr != nil {
 			return nil, fmt.Errorf("circuit breaker: %w", cbErr)
 		}
 		resp = result
 	} else {
-		resp, err = rc.client.Do(req)
+		resp, err = client.Do(req)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	if stream {
+		return &Response{
+			Response:   resp,
+			StatusCode: resp.StatusCode,
+			Headers:    resp.Header,
+		}, nil
+	}
+
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
@@ -530,47 +816,115 @@ func (rc *RESTClient) executeRequest(req *http.Request) (*Response, error) {
 	}, nil
 }
 
-// getMaxAttempts returns the maximum number of retry attempts
-func (rc *RESTClient) getMaxAttempts() int {
-	if rc.retry == nil {
+// maxAttempts returns the maximum number of retry attempts
+func (c *RetryConfig) maxAttempts() int {
+	if c == nil {
 		return 1
 	}
-	return rc.retry.MaxAttempts
+	return c.MaxAttempts
 }
 
-// calculateBackoff calculates the backoff delay for retry attempts
-func (rc *RESTClient) calculateBackoff(attempt int) time.Duration {
-	if rc.retry == nil {
+// backoff calculates the backoff delay for retry attempts, applying
+// the configured jitter. previous is the delay used before the last attempt.
+func (c *RetryConfig) backoff(attempt int, previous time.Duration) time.Duration {
+	if c == nil {
 		return 0
 	}
-
-	delay := time.Duration(float64(rc.retry.InitialBackoff) *
-		(rc.retry.BackoffFactor * float64(attempt-1)))
-
-	if delay > rc.retry.MaxBackoff {
-		delay = rc.retry.MaxBackoff
-	}
-
-	return delay
+	return c.policy().Backoff(attempt, previous)
 }
 
-// shouldRetry determines if a status code warrants a retry
-func (rc *RESTClient) shouldRetry(statusCode int) bool {
-	return statusCode >= 500 || statusCode == 429 || statusCode == 408
+// policy returns the backoff settings as a helpers.RetryPolicy
+func (c *RetryConfig) policy() helpers.RetryPolicy {
+	return helpers.RetryPolicy{
+		MaxAttempts:    c.MaxAttempts,
+		InitialBackoff: c.InitialBackoff,
+		MaxBackoff:     c.MaxBackoff,
+		BackoffFactor:  c.BackoffFactor,
+		Jitter:         c.Jitter,
+	}
 }
 
 // Request executes a generic HTTP request
 func (rc *RESTClient) Request(config RequestConfig) (*Response, error) {
+	callerContext := config.Context
+	cancel := context.CancelFunc(func() {})
+	if config.Timeout > 0 {
+		if config.Context == nil {
+			config.Context = context.Background()
+		}
+		config.Context, cancel = context.WithTimeout(config.Context, config.Timeout)
+	}
+
 	req, err := rc.createRequest(config)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	if rc.retry != nil {
-		return rc.executeWithRetry(req)
+	retry := rc.retry
+	if config.NoRetry {
+		retry = nil
+	} else if config.Retry != nil {
+		retry = config.Retry
 	}
 
-	return rc.executeRequest(req)
+	send := func(req *http.Request) (*Response, error) {
+		if err := rc.throttle(req); err != nil {
+			return nil, err
+		}
+		release, err := rc.bulkhead.acquire(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := rc.executeRequest(req, config.Stream, config.Timeout > 0)
+		if resp != nil && resp.Body == nil && resp.Response != nil {
+			// A streamed response keeps its slot until the body is closed
+			resp.Response.Body = &cancelOnClose{ReadCloser: resp.Response.Body, cancel: release}
+		} else {
+			release()
+		}
+		return resp, err
+	}
+	if rc.harRecorder != nil {
+		send = rc.harRecorder.middleware(rc.redactor)(send)
+	}
+	if rc.curlDump != nil {
+		send = rc.curlDump.middleware(send)
+	}
+	if rc.debugLog != nil {
+		send = rc.debugLog.middleware()(send)
+	}
+	if rc.accessLog != nil {
+		send = rc.accessLog.middleware()(send)
+	}
+	handler := rc.chain(send)
+
+	if rc.endpoints != nil && !isAbsoluteURL(config.URL) {
+		handler = rc.endpoints.middleware(rc.baseURL)(handler)
+	}
+
+	codec, _ := rc.resolveCodec(config)
+	resp, err := rc.execute(req, retry, handler)
+	if err != nil && rc.fallback != nil && shouldFallback(err) {
+		cancel()
+		// The timeout context is cancelled, so the fallback gets the caller's context
+		config.Context = callerContext
+		return rc.fallback(config, err)
+	}
+	if resp != nil && resp.Body == nil && resp.Response != nil {
+		// Keep the deadline running until the caller closes the stream
+		resp.Response.Body = &cancelOnClose{ReadCloser: resp.Response.Body, cancel: cancel}
+	} else {
+		cancel()
+	}
+	if resp != nil {
+		resp.codec = codec
+		resp.redactor = rc.redactor
+		if !resp.IsSuccess() && (rc.errorOnNonSuccess || config.ErrorOnNonSuccess) {
+			return nil, rc.nonSuccessError(req, resp)
+		}
+	}
+	return resp, err
 }
 
 // GET executes a GET request
@@ -683,7 +1037,10 @@ func WithQueryParams(params map[string]string) RequestOption {
 	}
 }
 
-// WithTimeout sets a request-specific timeout
+// WithTimeout bounds the whole request, including every retry attempt and
+// backoff, with a context deadline. It replaces the client-level timeout for
+// this request, so it can be longer or shorter than the client default. For
+// streamed responses the deadline also covers reading the body.
 func WithTimeout(timeout time.Duration) RequestOption {
 	return func(config *RequestConfig) {
 		config.Timeout = timeout
@@ -697,6 +1054,49 @@ func WithContext(ctx context.Context) RequestOption {
 	}
 }
 
+// WithRetry overrides the client's retry configuration for this request
+func WithRetry(retry RetryConfig) RequestOption {
+	return func(config *RequestConfig) {
+		config.Retry = &retry
+		config.NoRetry = false
+	}
+}
+
+// WithNoRetry disables retries for this request, e.g. for non-idempotent payment calls
+func WithNoRetry() RequestOption {
+	return func(config *RequestConfig) {
+		config.NoRetry = true
+	}
+}
+
+// WithFormBody sets an application/x-www-form-urlencoded request body.
+// Passing url.Values directly as the body of POST/PUT/PATCH has the same effect.
+func WithFormBody(values url.Values) RequestOption {
+	return func(config *RequestConfig) {
+		config.Body = values
+	}
+}
+
+// WithStreamResponse returns the response body unread so large payloads can
+// be piped without buffering. The caller must Close the response. The client
+// timeout is not applied to streamed requests; use WithContext for a deadline.
+func WithStreamResponse() RequestOption {
+	return func(config *RequestConfig) {
+		config.Stream = true
+	}
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header. The same key is sent on
+// every retry attempt so the server can deduplicate them.
+func WithIdempotencyKey(key string) RequestOption {
+	return WithHeader(idempotency.HeaderKey, key)
+}
+
+// WithGeneratedIdempotencyKey sets a freshly generated Idempotency-Key header
+func WithGeneratedIdempotencyKey() RequestOption {
+	return WithIdempotencyKey(idempotency.NewKey())
+}
+
 // JSON parses the response body as JSON using helpers package
 func (r *Response) JSON(v interface{}) error {
 	return helpers.UnmarshalJSON(r.Body, v)
@@ -711,3 +1111,19 @@ func (r *Response) String() string {
 func (r *Response) IsSuccess() bool {
 	return r.StatusCode >= 200 && r.StatusCode < 300
 }
+
+// Reader returns the unread body of a streamed response
+func (r *Response) Reader() io.ReadCloser {
+	if r.Response == nil || r.Body != nil {
+		return io.NopCloser(bytes.NewReader(r.Body))
+	}
+	return r.Response.Body
+}
+
+// Close closes the body of a streamed response. It is a no-op for buffered responses.
+func (r *Response) Close() error {
+	if r.Response == nil || r.Body != nil {
+		return nil
+	}
+	return r.Response.Body.Close()
+}
@@ -3,13 +3,20 @@ package client_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/khekrn/core/buildinfo"
 	"github.com/khekrn/core/client"
+	"github.com/sony/gobreaker/v2"
 )
 
 // Example usage demonstrating the REST client capabilities
@@ -245,6 +252,31 @@ func TestRESTClient_AllHTTPMethods(t *testing.T) {
 	}
 }
 
+func TestRESTClient_UserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.UserAgent()))
+	}))
+	defer server.Close()
+
+	agent := func(builder *client.ClientBuilder) string {
+		resp, err := builder.WithBaseURL(server.URL).Build().GET("/")
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		return resp.String()
+	}
+
+	if got := agent(client.NewClientBuilder()); got != buildinfo.Get().UserAgent() {
+		t.Errorf("Expected the build's User-Agent, got %q", got)
+	}
+	if got := agent(client.NewClientBuilder().WithUserAgent("billing/2.0")); got != "billing/2.0" {
+		t.Errorf("Expected the configured User-Agent, got %q", got)
+	}
+	if got := agent(client.NewClientBuilder().WithUserAgent("")); !strings.HasPrefix(got, "Go-http-client") {
+		t.Errorf("Expected Go's default User-Agent, got %q", got)
+	}
+}
+
 func TestFromSharedClient(t *testing.T) {
 	// Create test servers for different services
 	baseServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -360,6 +392,80 @@ func TestFromSharedClient(t *testing.T) {
 	}
 }
 
+func TestFromSharedClient_InheritsWithIsolation(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if strings.HasPrefix(r.URL.Path, "/down") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(r.Header.Get("X-Middleware")))
+	}))
+	defer server.Close()
+
+	transport := &http.Transport{}
+	failing := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasPrefix(req.URL.Path, "/refused") {
+			return nil, errors.New("connection refused")
+		}
+		return transport.RoundTrip(req)
+	})
+
+	parent := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithTransport(failing).
+		WithRetry(client.RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}).
+		WithCircuitBreaker(client.CircuitBreakerConfig{Name: "parent", Timeout: time.Minute, ReadyToTrip: tripAfterTwo}).
+		WithMiddleware(func(next client.Handler) client.Handler {
+			return func(req *http.Request) (*client.Response, error) {
+				req.Header.Set("X-Middleware", "inherited")
+				return next(req)
+			}
+		}).
+		Build()
+
+	derived := client.FromSharedClient(parent, "derived", "").Build()
+	noRetry := client.FromSharedClient(parent, "no-retry", "").WithoutRetry().Build()
+
+	// Middleware and retry settings carry over
+	resp, err := derived.GET("/ok")
+	if err != nil || resp.String() != "inherited" {
+		t.Fatalf("Expected inherited middleware, got %v %q", err, resp.String())
+	}
+	calls.Store(0)
+	derived.GET("/down")
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Expected derived client to inherit 3 attempts, got %d", got)
+	}
+
+	// Overrides on the derived builder do not leak into the parent
+	calls.Store(0)
+	noRetry.GET("/down")
+	parent.GET("/down")
+	if got := calls.Load(); got != 4 {
+		t.Errorf("Expected 1 attempt without retry and 3 for the parent, got %d", got)
+	}
+
+	// Breakers are separate instances with inherited settings
+	parent.GET("/refused", client.WithNoRetry())
+	parent.GET("/refused", client.WithNoRetry())
+	if parent.CircuitBreakerState() != gobreaker.StateOpen {
+		t.Fatalf("Expected parent breaker to open, got %s", parent.CircuitBreakerState())
+	}
+	if derived.CircuitBreakerState() != gobreaker.StateClosed {
+		t.Errorf("Expected derived breaker to be unaffected, got %s", derived.CircuitBreakerState())
+	}
+	if _, ok := derived.CircuitBreakerStates()["derived"]; !ok {
+		t.Errorf("Expected derived breaker to be named after the service, got %v", derived.CircuitBreakerStates())
+	}
+	derived.GET("/refused", client.WithNoRetry())
+	derived.GET("/refused", client.WithNoRetry())
+	if derived.CircuitBreakerState() != gobreaker.StateOpen {
+		t.Errorf("Expected derived breaker to inherit the trip threshold, got %s", derived.CircuitBreakerState())
+	}
+}
+
 func TestDefaultRetryAndCircuitBreaker(t *testing.T) {
 	// Create a server that returns errors
 	errorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -387,3 +493,114 @@ func TestDefaultRetryAndCircuitBreaker(t *testing.T) {
 		t.Error("Expected error due to server errors, but got success")
 	}
 }
+
+func TestRESTClient_StreamResponse(t *testing.T) {
+	payload := strings.Repeat("chunk-", 10000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		Build()
+
+	resp, err := restClient.GET("/download", client.WithStreamResponse())
+	if err != nil {
+		t.Fatalf("Streaming GET failed: %v", err)
+	}
+	defer resp.Close()
+
+	if resp.Body != nil {
+		t.Error("Expected streamed response not to be buffered")
+	}
+
+	var out strings.Builder
+	if _, err := io.Copy(&out, resp.Reader()); err != nil {
+		t.Fatalf("Failed to read stream: %v", err)
+	}
+	if out.String() != payload {
+		t.Errorf("Expected %d streamed bytes, got %d", len(payload), out.Len())
+	}
+}
+
+func TestRESTClient_Multipart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Failed to parse multipart form: %v", err)
+			return
+		}
+		file, header, err := r.FormFile("avatar")
+		if err != nil {
+			t.Errorf("Missing file part: %v", err)
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"name":     r.FormValue("name"),
+			"filename": header.Filename,
+			"content":  string(data),
+		})
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().WithBaseURL(server.URL).Build()
+
+	check := func(resp *client.Response, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("Multipart request failed: %v", err)
+		}
+		var result map[string]string
+		if err := resp.JSON(&result); err != nil {
+			t.Fatalf("Failed to parse JSON: %v", err)
+		}
+		if result["name"] != "jane" || result["filename"] != "me.png" || result["content"] != "png-bytes" {
+			t.Errorf("Unexpected multipart echo: %v", result)
+		}
+	}
+
+	check(restClient.PostMultipart("/upload",
+		[]client.FileField{{FieldName: "avatar", FileName: "me.png", ContentType: "image/png", Reader: strings.NewReader("png-bytes")}},
+		map[string]string{"name": "jane"},
+	))
+
+	check(restClient.POST("/upload", nil,
+		client.WithFormField("name", "jane"),
+		client.WithFilePart("avatar", "me.png", strings.NewReader("png-bytes")),
+	))
+}
+
+func TestRESTClient_FormBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("Expected form content type, got %s", ct)
+		}
+		r.ParseForm()
+		json.NewEncoder(w).Encode(map[string]string{
+			"grant_type": r.PostForm.Get("grant_type"),
+			"scope":      r.PostForm.Get("scope"),
+		})
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().WithBaseURL(server.URL).Build()
+
+	resp, err := restClient.POST("/oauth/token", nil, client.WithFormBody(url.Values{
+		"grant_type": {"client_credentials"},
+		"scope":      {"orders:read"},
+	}))
+	if err != nil {
+		t.Fatalf("Form POST failed: %v", err)
+	}
+
+	var result map[string]string
+	if err := resp.JSON(&result); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if result["grant_type"] != "client_credentials" || result["scope"] != "orders:read" {
+		t.Errorf("Unexpected form echo: %v", result)
+	}
+}
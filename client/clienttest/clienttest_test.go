@@ -0,0 +1,127 @@
+package clienttest_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/khekrn/core/client"
+	"github.com/khekrn/core/client/clienttest"
+)
+
+func TestTransport(t *testing.T) {
+	mock := clienttest.NewTransport()
+	mock.On("GET", "/users/42").RespondJSON(http.StatusOK, map[string]string{"id": "42"})
+	mock.On("POST", "/orders").BodyContains(`"sku":"A1"`).Respond(http.StatusCreated, `{"id":"ord-1"}`).Once()
+	mock.On("POST", "/orders").Respond(http.StatusConflict, `{"error":"duplicate"}`)
+	mock.On("DELETE", "https://api.example.com/orders/ord-1").RespondError(errors.New("connection reset"))
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL("https://api.example.com").
+		WithTransport(mock).
+		WithoutRetry().
+		WithoutCircuitBreaker().
+		Build()
+
+	resp, err := restClient.GET("/users/42")
+	if err != nil || resp.String() != `{"id":"42"}` {
+		t.Fatalf("Unexpected GET result: %v", err)
+	}
+
+	resp, err = restClient.POST("/orders", map[string]string{"sku": "A1"})
+	if err != nil || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 from body rule, got %v", err)
+	}
+	resp, err = restClient.POST("/orders", map[string]string{"sku": "A1"})
+	if err != nil || resp.StatusCode != http.StatusConflict {
+		t.Fatalf("Expected Once rule to fall through to 409, got %v", err)
+	}
+
+	if _, err := restClient.DELETE("/orders/ord-1"); err == nil {
+		t.Error("Expected simulated transport error")
+	}
+	if _, err := restClient.GET("/unknown"); err == nil {
+		t.Error("Expected error for unmatched request")
+	}
+
+	mock.AssertExpectations(t)
+	mock.AssertCalled(t, "POST", "/orders", 2)
+	if got := len(mock.Requests()); got != 5 {
+		t.Errorf("Expected 5 recorded requests, got %d", got)
+	}
+}
+
+func TestTransport_AssertExpectations(t *testing.T) {
+	mock := clienttest.NewTransport()
+	mock.On("GET", "/never")
+	mock.On("GET", "/twice").Times(2)
+	mock.RoundTrip(httptest.NewRequest("GET", "http://api/twice", nil))
+
+	probe := &testing.T{}
+	mock.AssertExpectations(probe)
+	if !probe.Failed() {
+		t.Error("Expected AssertExpectations to fail for uncalled rules")
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	cassette := filepath.Join(t.TempDir(), "cassettes", "orders.json")
+	run := func() (*clienttest.Recorder, *client.RESTClient) {
+		rec, err := clienttest.NewRecorder(cassette, clienttest.ModeReplayOrRecord, nil)
+		if err != nil {
+			t.Fatalf("NewRecorder failed: %v", err)
+		}
+		return rec, client.NewClientBuilder().
+			WithBaseURL(server.URL).
+			WithTransport(rec).
+			WithDefaultHeader("Authorization", "Bearer secret").
+			WithoutCircuitBreaker().
+			Build()
+	}
+
+	// First run records against the real server
+	rec, restClient := run()
+	if !rec.Recording() {
+		t.Fatal("Expected recording when the cassette does not exist")
+	}
+	if _, err := restClient.GET("/orders/1"); err != nil {
+		t.Fatalf("Recorded GET failed: %v", err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	data, err := os.ReadFile(cassette)
+	if err != nil || strings.Contains(string(data), "Bearer secret") || !strings.Contains(string(data), "REDACTED") {
+		t.Errorf("Expected Authorization to be redacted in the cassette: %v", err)
+	}
+
+	// Second run replays without touching the server
+	rec, restClient = run()
+	if rec.Recording() {
+		t.Fatal("Expected replay when the cassette exists")
+	}
+	resp, err := restClient.GET("/orders/1")
+	if err != nil || resp.String() != `{"path":"/orders/1"}` || resp.Headers.Get("Content-Type") != "application/json" {
+		t.Fatalf("Unexpected replayed response: %v", err)
+	}
+	if _, err := restClient.GET("/orders/2"); err == nil {
+		t.Error("Expected error for an unrecorded request")
+	}
+	if hits.Load() != 1 {
+		t.Errorf("Expected 1 real request, got %d", hits.Load())
+	}
+}
@@ -0,0 +1,290 @@
+// Package clienttest provides test doubles for code built on the client package.
+//
+// This package offers a programmable mock http.RoundTripper that matches
+// requests by method, URL, and body and answers with canned responses, plus
+// a cassette recorder that records real interactions once and replays them
+// in later test runs.
+//
+// Example usage:
+//
+//	mock := clienttest.NewTransport()
+//	mock.On("GET", "/users/42").RespondJSON(http.StatusOK, map[string]string{"id": "42"})
+//	mock.On("POST", "/orders").BodyContains(`"sku":"A1"`).Respond(http.StatusCreated, `{"id":"ord-1"}`).Times(1)
+//
+//	restClient := client.NewClientBuilder().
+//		WithBaseURL("https://api.example.com").
+//		WithTransport(mock).
+//		Build()
+//
+//	// ... exercise the code under test ...
+//	mock.AssertExpectations(t)
+package clienttest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/khekrn/core/helpers"
+)
+
+// Transport is a mock http.RoundTripper answering requests from rules
+type Transport struct {
+	mu       sync.Mutex
+	rules    []*Rule
+	requests []*RecordedRequest
+}
+
+// Rule matches requests and produces their response
+type Rule struct {
+	method  string
+	url     string
+	matches []func(req *http.Request, body []byte) bool
+	respond func(req *http.Request) (*http.Response, error)
+	times   int // 0 means unlimited
+	calls   int
+}
+
+// RecordedRequest is a request received by a Transport or Recorder
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// NewTransport creates a mock transport with no rules
+func NewTransport() *Transport {
+	return &Transport{}
+}
+
+// On adds a rule for method and url. A url starting with "/" matches the
+// request path; otherwise it must equal the full URL. An empty method
+// matches any method. Rules are tried in the order they were added.
+func (t *Transport) On(method, url string) *Rule {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rule := &Rule{
+		method: strings.ToUpper(method),
+		url:    url,
+		respond: func(req *http.Request) (*http.Response, error) {
+			return newResponse(req, http.StatusOK, nil, nil), nil
+		},
+	}
+	t.rules = append(t.rules, rule)
+	return rule
+}
+
+// RoundTrip answers req from the first matching rule with uses left
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.requests = append(t.requests, &RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+		Body:   string(body),
+	})
+	var matched *Rule
+	for _, rule := range t.rules {
+		if (rule.times == 0 || rule.calls < rule.times) && rule.match(req, body) {
+			rule.calls++
+			matched = rule
+			break
+		}
+	}
+	t.mu.Unlock()
+
+	if matched == nil {
+		return nil, fmt.Errorf("clienttest: no rule matches %s %s", req.Method, req.URL)
+	}
+	return matched.respond(req)
+}
+
+// Requests returns every request received, in order
+func (t *Transport) Requests() []*RecordedRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*RecordedRequest(nil), t.requests...)
+}
+
+// AssertExpectations fails the test if a rule limited with Times was not
+// called exactly that many times, or an unlimited rule was never called
+func (t *Transport) AssertExpectations(tb testing.TB) {
+	tb.Helper()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, rule := range t.rules {
+		switch {
+		case rule.times > 0 && rule.calls != rule.times:
+			tb.Errorf("clienttest: %s expected %d calls, got %d", rule, rule.times, rule.calls)
+		case rule.times == 0 && rule.calls == 0:
+			tb.Errorf("clienttest: %s was never called", rule)
+		}
+	}
+}
+
+// AssertCalled fails the test unless method and url were requested exactly times times
+func (t *Transport) AssertCalled(tb testing.TB, method, url string, times int) {
+	tb.Helper()
+
+	probe := &Rule{method: strings.ToUpper(method), url: url}
+	calls := 0
+	for _, recorded := range t.Requests() {
+		req, err := http.NewRequest(recorded.Method, recorded.URL, nil)
+		if err == nil && probe.match(req, nil) {
+			calls++
+		}
+	}
+	if calls != times {
+		tb.Errorf("clienttest: %s expected %d calls, got %d", probe, times, calls)
+	}
+}
+
+// BodyContains restricts the rule to requests whose body contains s
+func (r *Rule) BodyContains(s string) *Rule {
+	return r.MatchBody(func(body []byte) bool {
+		return bytes.Contains(body, []byte(s))
+	})
+}
+
+// MatchBody restricts the rule to requests whose body satisfies match
+func (r *Rule) MatchBody(match func(body []byte) bool) *Rule {
+	r.matches = append(r.matches, func(_ *http.Request, body []byte) bool {
+		return match(body)
+	})
+	return r
+}
+
+// MatchHeader restricts the rule to requests with the header value
+func (r *Rule) MatchHeader(key, value string) *Rule {
+	r.matches = append(r.matches, func(req *http.Request, _ []byte) bool {
+		return req.Header.Get(key) == value
+	})
+	return r
+}
+
+// Respond answers with status and body
+func (r *Rule) Respond(status int, body string) *Rule {
+	r.respond = func(req *http.Request) (*http.Response, error) {
+		return newResponse(req, status, nil, []byte(body)), nil
+	}
+	return r
+}
+
+// RespondJSON answers with status and v encoded as JSON
+func (r *Rule) RespondJSON(status int, v interface{}) *Rule {
+	body, err := helpers.ToJSON(v)
+	r.respond = func(req *http.Request) (*http.Response, error) {
+		if err != nil {
+			return nil, fmt.Errorf("clienttest: failed to marshal response: %w", err)
+		}
+		header := http.Header{"Content-Type": {"application/json"}}
+		return newResponse(req, status, header, body), nil
+	}
+	return r
+}
+
+// RespondWithHeader answers with status, headers, and body
+func (r *Rule) RespondWithHeader(status int, header http.Header, body string) *Rule {
+	r.respond = func(req *http.Request) (*http.Response, error) {
+		return newResponse(req, status, header.Clone(), []byte(body)), nil
+	}
+	return r
+}
+
+// RespondError fails matching requests with err, e.g. to simulate a connection reset
+func (r *Rule) RespondError(err error) *Rule {
+	r.respond = func(*http.Request) (*http.Response, error) {
+		return nil, err
+	}
+	return r
+}
+
+// RespondFunc answers matching requests with fn
+func (r *Rule) RespondFunc(fn func(req *http.Request) (*http.Response, error)) *Rule {
+	r.respond = fn
+	return r
+}
+
+// Times limits the rule to n matches; later requests fall through to other rules
+func (r *Rule) Times(n int) *Rule {
+	r.times = n
+	return r
+}
+
+// Once limits the rule to a single match
+func (r *Rule) Once() *Rule {
+	return r.Times(1)
+}
+
+// String describes the rule for assertion messages
+func (r *Rule) String() string {
+	method := r.method
+	if method == "" {
+		method = "*"
+	}
+	return method + " " + r.url
+}
+
+// match reports whether req satisfies the rule
+func (r *Rule) match(req *http.Request, body []byte) bool {
+	if r.method != "" && r.method != req.Method {
+		return false
+	}
+	if strings.HasPrefix(r.url, "/") {
+		if req.URL.Path != r.url {
+			return false
+		}
+	} else if r.url != "" && req.URL.String() != r.url {
+		return false
+	}
+	for _, match := range r.matches {
+		if !match(req, body) {
+			return false
+		}
+	}
+	return true
+}
+
+// newResponse builds a response to req
+func newResponse(req *http.Request, status int, header http.Header, body []byte) *http.Response {
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// readBody reads and restores the request body
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("clienttest: failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
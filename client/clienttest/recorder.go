@@ -0,0 +1,203 @@
+package clienttest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/khekrn/core/helpers"
+)
+
+// Mode selects whether a Recorder records or replays
+type Mode int
+
+// Recorder modes
+const (
+	ModeReplayOrRecord Mode = iota // Replay when the cassette exists, otherwise record
+	ModeReplay                     // Replay only; unmatched requests fail
+	ModeRecord                     // Always send real requests and overwrite the cassette
+)
+
+// Cassette is a recorded sequence of HTTP interactions
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedResponse is a recorded HTTP response
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// RecorderOption configures a Recorder
+type RecorderOption func(*Recorder)
+
+// WithRedactedHeaders replaces the values of request headers in the cassette,
+// e.g. credentials. Authorization is redacted by default.
+func WithRedactedHeaders(headers ...string) RecorderOption {
+	return func(r *Recorder) {
+		for _, header := range headers {
+			r.redact[http.CanonicalHeaderKey(header)] = true
+		}
+	}
+}
+
+// Recorder is an http.RoundTripper that records real interactions to a
+// cassette file and replays them. Requests are matched by method, URL, and
+// body; each recorded interaction is replayed once, in order.
+//
+// Example:
+//
+//	rec, err := clienttest.NewRecorder("testdata/orders.json", clienttest.ModeReplayOrRecord, nil)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//
+//	restClient := client.NewClientBuilder().WithTransport(rec).Build()
+type Recorder struct {
+	path     string
+	mode     Mode
+	real     http.RoundTripper
+	redact   map[string]bool
+	cassette *Cassette
+
+	mu   sync.Mutex
+	used map[*Interaction]bool
+}
+
+// NewRecorder creates a recorder for the cassette at path. real sends
+// requests while recording and defaults to http.DefaultTransport.
+func NewRecorder(path string, mode Mode, real http.RoundTripper, options ...RecorderOption) (*Recorder, error) {
+	if real == nil {
+		real = http.DefaultTransport
+	}
+	r := &Recorder{
+		path:     path,
+		mode:     mode,
+		real:     real,
+		redact:   map[string]bool{"Authorization": true},
+		cassette: &Cassette{},
+		used:     make(map[*Interaction]bool),
+	}
+	for _, option := range options {
+		option(r)
+	}
+
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && mode == ModeReplayOrRecord:
+		r.mode = ModeRecord
+		return r, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	cassette, err := helpers.FromJSON[Cassette](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cassette: %w", err)
+	}
+	r.cassette = cassette
+	r.mode = ModeReplay
+	return r, nil
+}
+
+// Recording reports whether the recorder is sending real requests
+func (r *Recorder) Recording() bool {
+	return r.mode == ModeRecord
+}
+
+// RoundTrip replays a recorded interaction or records a real one
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+
+	resp, err := r.real.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("clienttest: failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := req.Header.Clone()
+	for name := range header {
+		if r.redact[name] {
+			header[name] = []string{"REDACTED"}
+		}
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request: RecordedRequest{Method: req.Method, URL: req.URL.String(), Header: header, Body: string(body)},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       string(respBody),
+		},
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// replay answers req with the first unused matching interaction
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, interaction := range r.cassette.Interactions {
+		recorded := interaction.Request
+		if r.used[interaction] || recorded.Method != req.Method || recorded.URL != req.URL.String() || recorded.Body != string(body) {
+			continue
+		}
+		r.used[interaction] = true
+		return newResponse(req, interaction.Response.StatusCode, interaction.Response.Header.Clone(), []byte(interaction.Response.Body)), nil
+	}
+	return nil, fmt.Errorf("clienttest: no recorded interaction for %s %s in %s", req.Method, req.URL, r.path)
+}
+
+// Stop saves the cassette when recording
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := helpers.PrettyPrint(r.cassette)
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, []byte(data), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/khekrn/core/helpers"
+)
+
+// Codec encodes request bodies and decodes response bodies for a media type
+type Codec interface {
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Built-in codecs
+var (
+	JSONCodec Codec = jsonCodec{} // application/json, the default
+	XMLCodec  Codec = xmlCodec{}  // application/xml
+)
+
+// jsonCodec encodes JSON using the helpers package
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string { return "application/json" }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) { return helpers.ToJSON(v) }
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return helpers.UnmarshalJSON(data, v) }
+
+// xmlCodec encodes XML using encoding/xml
+type xmlCodec struct{}
+
+func (xmlCodec) ContentType() string { return "application/xml" }
+
+func (xmlCodec) Marshal(v interface{}) ([]byte, error) { return xml.Marshal(v) }
+
+func (xmlCodec) Unmarshal(data []byte, v interface{}) error { return xml.Unmarshal(data, v) }
+
+// WithCodec sets the codec used for structured request and response bodies
+// and the Accept header. The default is JSON.
+func (b *ClientBuilder) WithCodec(codec Codec) *ClientBuilder {
+	b.codec = codec
+	return b
+}
+
+// WithCodec overrides the client's codec for this request
+func WithCodec(codec Codec) RequestOption {
+	return func(config *RequestConfig) {
+		config.Codec = codec
+	}
+}
+
+// Decode unmarshals the response body with the codec used for the request
+func (r *Response) Decode(v interface{}) error {
+	codec := r.codec
+	if codec == nil {
+		codec = JSONCodec
+	}
+	if err := codec.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", codec.ContentType(), err)
+	}
+	return nil
+}
+
+// resolveCodec returns the codec for a request and whether one was chosen explicitly
+func (rc *RESTClient) resolveCodec(config RequestConfig) (Codec, bool) {
+	if config.Codec != nil {
+		return config.Codec, true
+	}
+	if rc.codec != nil {
+		return rc.codec, true
+	}
+	return JSONCodec, false
+}
@@ -0,0 +1,64 @@
+package client_test
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khekrn/core/client"
+)
+
+type xmlOrder struct {
+	XMLName xml.Name `xml:"order" json:"-"`
+	ID      string   `xml:"id" json:"id"`
+	Amount  int      `xml:"amount" json:"amount"`
+}
+
+func TestRESTClient_Codec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Request-Content-Type", r.Header.Get("Content-Type"))
+		w.Header().Set("X-Request-Accept", r.Header.Get("Accept"))
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	xmlClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithCodec(client.XMLCodec).
+		Build()
+
+	resp, err := xmlClient.POST("/orders", xmlOrder{ID: "ord-1", Amount: 42})
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	if resp.String() != "<order><id>ord-1</id><amount>42</amount></order>" {
+		t.Errorf("Unexpected XML body: %s", resp.String())
+	}
+	if resp.Headers.Get("X-Request-Content-Type") != "application/xml" || resp.Headers.Get("X-Request-Accept") != "application/xml" {
+		t.Errorf("Expected XML content type and accept headers, got %q and %q",
+			resp.Headers.Get("X-Request-Content-Type"), resp.Headers.Get("X-Request-Accept"))
+	}
+
+	var order xmlOrder
+	if err := resp.Decode(&order); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if order.ID != "ord-1" || order.Amount != 42 {
+		t.Errorf("Unexpected decoded order: %+v", order)
+	}
+
+	// A per-request codec overrides the client codec
+	resp, err = xmlClient.POST("/orders", xmlOrder{ID: "ord-2", Amount: 7}, client.WithCodec(client.JSONCodec))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	if resp.String() != `{"id":"ord-2","amount":7}` || resp.Headers.Get("X-Request-Content-Type") != "application/json" {
+		t.Errorf("Expected JSON body, got %s (%s)", resp.String(), resp.Headers.Get("X-Request-Content-Type"))
+	}
+	if err := resp.Decode(&order); err != nil || order.ID != "ord-2" {
+		t.Errorf("Expected JSON decode of ord-2, got %+v (%v)", order, err)
+	}
+}
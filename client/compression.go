@@ -0,0 +1,121 @@
+package client
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Decompressor wraps a compressed response body with a decoding reader
+type Decompressor func(r io.Reader) (io.ReadCloser, error)
+
+// WithCompression advertises gzip and deflate in Accept-Encoding and
+// transparently decompresses responses, so Response.Body and streamed
+// bodies hold the decoded payload.
+func (b *ClientBuilder) WithCompression() *ClientBuilder {
+	b.WithDecompressor("gzip", func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	})
+	b.WithDecompressor("deflate", func(r io.Reader) (io.ReadCloser, error) {
+		return zlib.NewReader(r)
+	})
+	return b
+}
+
+// WithDecompressor registers a decoder for a content encoding and enables
+// response decompression. Use it to add encodings without a standard library
+// decoder, such as brotli:
+//
+//	builder.WithDecompressor("br", func(r io.Reader) (io.ReadCloser, error) {
+//		return io.NopCloser(brotli.NewReader(r)), nil
+//	})
+func (b *ClientBuilder) WithDecompressor(encoding string, decompressor Decompressor) *ClientBuilder {
+	encoding = strings.ToLower(encoding)
+	for i, d := range b.decompressors {
+		if d.encoding == encoding {
+			b.decompressors[i].decompress = decompressor
+			return b
+		}
+	}
+	b.decompressors = append(b.decompressors, encodingDecompressor{encoding: encoding, decompress: decompressor})
+	return b
+}
+
+// encodingDecompressor pairs a content encoding with its decoder
+type encodingDecompressor struct {
+	encoding   string
+	decompress Decompressor
+}
+
+// decompressTransport requests compressed responses and decodes them
+type decompressTransport struct {
+	base           http.RoundTripper
+	decompressors  []encodingDecompressor
+	acceptEncoding string
+}
+
+// newDecompressTransport wraps base to decode the given encodings
+func newDecompressTransport(base http.RoundTripper, decompressors []encodingDecompressor) *decompressTransport {
+	encodings := make([]string, len(decompressors))
+	for i, d := range decompressors {
+		encodings[i] = d.encoding
+	}
+	return &decompressTransport{
+		base:           base,
+		decompressors:  decompressors,
+		acceptEncoding: strings.Join(encodings, ", "),
+	}
+}
+
+// RoundTrip sets Accept-Encoding unless the caller did and decodes the response body
+func (t *decompressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") == "" && req.Method != http.MethodHead {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", t.acceptEncoding)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" || resp.Body == nil || resp.Body == http.NoBody {
+		return resp, nil
+	}
+	for _, d := range t.decompressors {
+		if d.encoding != encoding {
+			continue
+		}
+		reader, err := d.decompress(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to decompress %s response: %w", encoding, err)
+		}
+		resp.Body = &decompressedBody{ReadCloser: reader, raw: resp.Body}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+		break
+	}
+	return resp, nil
+}
+
+// decompressedBody closes both the decoder and the underlying body
+type decompressedBody struct {
+	io.ReadCloser
+	raw io.ReadCloser
+}
+
+// Close closes the decoder and the raw body
+func (b *decompressedBody) Close() error {
+	err := b.ReadCloser.Close()
+	if rawErr := b.raw.Close(); err == nil {
+		err = rawErr
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package client_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_Compression(t *testing.T) {
+	payload := `{"items":["a","b","c"]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gzip":
+			if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+				t.Errorf("Expected gzip in Accept-Encoding, got %q", r.Header.Get("Accept-Encoding"))
+			}
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			gz.Write([]byte(payload))
+			gz.Close()
+		case "/custom":
+			if !strings.Contains(r.Header.Get("Accept-Encoding"), "rev") {
+				t.Errorf("Expected rev in Accept-Encoding, got %q", r.Header.Get("Accept-Encoding"))
+			}
+			w.Header().Set("Content-Encoding", "rev")
+			w.Write([]byte(reverse(payload)))
+		default:
+			w.Write([]byte(payload))
+		}
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithCompression().
+		WithDecompressor("rev", func(r io.Reader) (io.ReadCloser, error) {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return nil, err
+			}
+			return io.NopCloser(bytes.NewReader([]byte(reverse(string(data))))), nil
+		}).
+		Build()
+
+	for _, path := range []string{"/gzip", "/custom", "/plain"} {
+		resp, err := restClient.GET(path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		if resp.String() != payload {
+			t.Errorf("GET %s: expected decoded body %q, got %q", path, payload, resp.String())
+		}
+		if resp.Headers.Get("Content-Encoding") != "" {
+			t.Errorf("GET %s: expected Content-Encoding to be removed", path)
+		}
+	}
+
+	// Streamed responses are decoded as they are read
+	resp, err := restClient.GET("/gzip", client.WithStreamResponse())
+	if err != nil {
+		t.Fatalf("Streamed GET failed: %v", err)
+	}
+	defer resp.Close()
+	body, err := io.ReadAll(resp.Reader())
+	if err != nil {
+		t.Fatalf("Failed to read stream: %v", err)
+	}
+	if string(body) != payload {
+		t.Errorf("Expected decoded stream %q, got %q", payload, body)
+	}
+}
+
+func reverse(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
@@ -0,0 +1,86 @@
+package client
+
+import "time"
+
+// Config is the declarative form of common client settings, e.g. loaded
+// with config.Load from files and environment variables. Zero fields keep
+// the builder's settings.
+type Config struct {
+	BaseURL             string            `yaml:"base_url"`
+	Timeout             time.Duration     `yaml:"timeout"`
+	Headers             map[string]string `yaml:"headers"`
+	MaxAttempts         int               `yaml:"max_attempts"` // 1 disables retries
+	InitialBackoff      time.Duration     `yaml:"initial_backoff"`
+	MaxBackoff          time.Duration     `yaml:"max_backoff"`
+	MaxConcurrency      int               `yaml:"max_concurrency"`
+	MaxIdleConns        int               `yaml:"max_idle_conns"`
+	MaxIdleConnsPerHost int               `yaml:"max_idle_conns_per_host"`
+	IdleConnTimeout     time.Duration     `yaml:"idle_conn_timeout"`
+	Proxy               string            `yaml:"proxy"` // Proxy URL; see WithProxy
+	Datadog             bool              `yaml:"datadog"`
+	GenerateRequestID   bool              `yaml:"generate_request_id"`
+	ErrorOnNonSuccess   bool              `yaml:"error_on_non_success"`
+}
+
+// WithConfig applies the non-zero settings of cfg
+//
+// Example:
+//
+//	type Settings struct {
+//		Payments client.Config `yaml:"payments"`
+//	}
+//
+//	settings, err := config.Load[Settings](config.WithFiles("config.yaml"), config.WithEnvPrefix("APP"))
+//	paymentsClient := client.NewClientBuilder().WithConfig(settings.Payments).Build()
+func (b *ClientBuilder) WithConfig(cfg Config) *ClientBuilder {
+	if cfg.BaseURL != "" {
+		b.WithBaseURL(cfg.BaseURL)
+	}
+	if cfg.Timeout > 0 {
+		b.WithTimeout(cfg.Timeout)
+	}
+	b.WithDefaultHeaders(cfg.Headers)
+
+	if cfg.MaxAttempts == 1 {
+		b.WithoutRetry()
+	} else if cfg.MaxAttempts > 1 || cfg.InitialBackoff > 0 || cfg.MaxBackoff > 0 {
+		if b.retry == nil {
+			b.WithDefaultRetry()
+		}
+		if cfg.MaxAttempts > 1 {
+			b.retry.MaxAttempts = cfg.MaxAttempts
+		}
+		if cfg.InitialBackoff > 0 {
+			b.retry.InitialBackoff = cfg.InitialBackoff
+		}
+		if cfg.MaxBackoff > 0 {
+			b.retry.MaxBackoff = cfg.MaxBackoff
+		}
+	}
+
+	if cfg.MaxConcurrency > 0 {
+		b.WithMaxConcurrency(cfg.MaxConcurrency)
+	}
+	if cfg.MaxIdleConns > 0 {
+		b.WithMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		b.WithMaxIdleConnsPerHost(cfg.MaxIdleConnsPerHost)
+	}
+	if cfg.IdleConnTimeout > 0 {
+		b.WithIdleConnTimeout(cfg.IdleConnTimeout)
+	}
+	if cfg.Proxy != "" {
+		b.WithProxy(cfg.Proxy)
+	}
+	if cfg.Datadog {
+		b.WithDatadog(true)
+	}
+	if cfg.GenerateRequestID {
+		b.WithRequestIDGeneration()
+	}
+	if cfg.ErrorOnNonSuccess {
+		b.WithErrorOnNonSuccess()
+	}
+	return b
+}
@@ -0,0 +1,38 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestClientBuilder_WithConfig(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("X-Team") != "payments" {
+			t.Errorf("Expected the configured header, got %q", r.Header.Get("X-Team"))
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithConfig(client.Config{
+			BaseURL:     server.URL + "/",
+			Headers:     map[string]string{"X-Team": "payments"},
+			MaxAttempts: 1,
+		}).
+		Build()
+
+	resp, err := restClient.GET("/charges")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("Expected one attempt with retries disabled, got %d calls", calls.Load())
+	}
+}
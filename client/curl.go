@@ -0,0 +1,83 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CurlCommand returns a curl command reproducing the request that produced
+// the response, with secrets redacted as described by Redaction. It is
+// empty when the response was not produced by a request, e.g. a fallback.
+func (r *Response) CurlCommand() string {
+	if r.Response == nil || r.Response.Request == nil {
+		return ""
+	}
+	redactor := r.redactor
+	if redactor == nil {
+		redactor = newRedactor(Redaction{})
+	}
+	return curlCommand(r.Response.Request, redactor)
+}
+
+// WithCurlDump writes the equivalent curl command of every attempt to w,
+// one per line, with secrets redacted as described by Redaction
+func (b *ClientBuilder) WithCurlDump(w io.Writer) *ClientBuilder {
+	b.curlDump = w
+	return b
+}
+
+// curlDumper writes curl commands for requests, serializing writes
+type curlDumper struct {
+	mu       sync.Mutex
+	w        io.Writer
+	redactor *redactor
+}
+
+// middleware dumps each attempt before sending it
+func (d *curlDumper) middleware(next Handler) Handler {
+	return func(req *http.Request) (*Response, error) {
+		command := curlCommand(req, d.redactor)
+		d.mu.Lock()
+		fmt.Fprintln(d.w, command)
+		d.mu.Unlock()
+		return next(req)
+	}
+}
+
+// curlCommand renders req as a curl command line
+func curlCommand(req *http.Request, redactor *redactor) string {
+	var b strings.Builder
+	b.WriteString("curl")
+	if req.Method != http.MethodGet {
+		b.WriteString(" -X " + req.Method)
+	}
+	b.WriteString(" " + shellQuote(redactor.url(req.URL)))
+
+	header := redactor.header(req.Header)
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			b.WriteString(" -H " + shellQuote(name+": "+value))
+		}
+	}
+
+	if body, ok := redactor.requestBody(req); !ok {
+		b.WriteString(" --data-binary @-")
+	} else if len(body) > 0 {
+		b.WriteString(" --data-binary " + shellQuote(string(body)))
+	}
+	return b.String()
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
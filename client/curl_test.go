@@ -0,0 +1,51 @@
+package client_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/khekrn/core/buildinfo"
+	"github.com/khekrn/core/client"
+)
+
+func TestResponse_CurlCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var dump bytes.Buffer
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithCurlDump(&dump).
+		Build()
+
+	resp, err := restClient.POST("/orders", map[string]string{"sku": "it's", "password": "p"},
+		client.WithHeader("Authorization", "Bearer secret"),
+		client.WithHeader("X-Trace", "t-1"))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+
+	want := `curl -X POST '` + server.URL + `/orders' -H 'Authorization: REDACTED' -H 'Content-Type: application/json' -H 'User-Agent: ` + buildinfo.Get().UserAgent() + `' -H 'X-Trace: t-1' --data-binary '{"password":"REDACTED","sku":"it'\''s"}'`
+	if got := resp.CurlCommand(); got != want {
+		t.Errorf("Unexpected curl command\n got: %s\nwant: %s", got, want)
+	}
+	if got := strings.TrimSpace(dump.String()); got != want {
+		t.Errorf("Unexpected curl dump\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestResponse_CurlCommandGET(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	resp, err := client.NewClientBuilder().Build().GET(server.URL+"/search", client.WithQueryParam("q", "a b"))
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	if got, want := resp.CurlCommand(), `curl '`+server.URL+`/search?q=a+b' -H 'User-Agent: `+buildinfo.Get().UserAgent()+`'`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultDebugBodyLimit is the number of body bytes logged by WithDebugLogging
+const DefaultDebugBodyLimit = 4096
+
+// WithDebugLogging logs every attempt at debug level: method, URL, headers,
+// bodies capped at the debug body limit, status, latency, and attempt
+// number. Secrets are hidden as described by Redaction. A nil log uses the
+// logger from the request context, which includes its request ID.
+func (b *ClientBuilder) WithDebugLogging(log *zap.Logger) *ClientBuilder {
+	b.debugLog = true
+	b.debugLogger = log
+	return b
+}
+
+// WithDebugBodyLimit sets how many body bytes WithDebugLogging logs.
+// Defaults to DefaultDebugBodyLimit.
+func (b *ClientBuilder) WithDebugBodyLimit(limit int) *ClientBuilder {
+	b.debugBodyLimit = limit
+	return b
+}
+
+// debugLogger logs requests and responses with secrets redacted
+type debugLogger struct {
+	log       *zap.Logger
+	bodyLimit int
+	redactor  *redactor
+}
+
+// middleware logs each attempt of one request, counting attempts
+func (d *debugLogger) middleware() Middleware {
+	attempt := 0
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*Response, error) {
+			log := d.log
+			if log == nil {
+				log = logger.FromContext(req.Context())
+			}
+			if log == nil {
+				return next(req)
+			}
+
+			attempt++
+			fields := []zap.Field{
+				zap.String("method", req.Method),
+				zap.String("url", d.redactor.url(req.URL)),
+				zap.Int("attempt", attempt),
+			}
+			requestFields := append(fields, zap.Any("headers", d.redactor.header(req.Header)))
+			if body, ok := d.redactor.requestBody(req); !ok {
+				requestFields = append(requestFields, zap.String("body", "<stream>"))
+			} else if len(body) > 0 {
+				requestFields = append(requestFields, d.body(body))
+			}
+			log.Debug("HTTP request", requestFields...)
+
+			start := time.Now()
+			resp, err := next(req)
+			fields = append(fields, zap.Duration("latency", time.Since(start)))
+			if err != nil {
+				log.Debug("HTTP request failed", append(fields, zap.Error(err))...)
+				return resp, err
+			}
+
+			fields = append(fields,
+				zap.Int("status", resp.StatusCode),
+				zap.Any("headers", d.redactor.header(resp.Headers)),
+			)
+			if resp.Body == nil && resp.Response != nil {
+				fields = append(fields, zap.String("body", "<stream>"))
+			} else if len(resp.Body) > 0 {
+				fields = append(fields, d.body(d.redactor.body(resp.Headers.Get("Content-Type"), resp.Body)))
+			}
+			log.Debug("HTTP response", fields...)
+			return resp, err
+		}
+	}
+}
+
+// body returns the body field, truncated to the body limit
+func (d *debugLogger) body(body []byte) zap.Field {
+	if len(body) > d.bodyLimit {
+		return zap.String("body", string(body[:d.bodyLimit])+"...(truncated)")
+	}
+	return zap.String("body", string(body))
+}
@@ -0,0 +1,90 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRESTClient_DebugLogging(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Write([]byte(`{"access_token":"tok-123","name":"` + strings.Repeat("x", 100) + `"}`))
+	}))
+	defer server.Close()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithRetry(client.RetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}).
+		WithDebugLogging(zap.New(core)).
+		WithDebugBodyLimit(64).
+		WithRedaction(client.Redaction{Headers: []string{"X-Signature"}, Fields: []string{"card"}}).
+		Build()
+
+	_, err := restClient.POST("/login", map[string]string{"user": "ada", "password": "hunter2", "card": "4111"},
+		client.WithHeader("Authorization", "Bearer secret"),
+		client.WithHeader("X-Signature", "sig"),
+		client.WithQueryParam("api_key", "k-1"),
+		client.WithIdempotencyKey("login-1"))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+
+	entries := logs.All()
+	if len(entries) != 4 {
+		t.Fatalf("Expected 2 requests and 2 responses logged, got %d", len(entries))
+	}
+
+	var all strings.Builder
+	for _, entry := range entries {
+		for k, v := range entry.ContextMap() {
+			all.WriteString(k)
+			all.WriteString("=")
+			all.WriteString(strings.TrimSpace(strings.ReplaceAll(fmtValue(v), "\n", " ")))
+			all.WriteString(" ")
+		}
+	}
+	out := all.String()
+	for _, secret := range []string{"Bearer secret", "hunter2", "4111", "k-1", "tok-123", "session=abc", ":sig"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Expected %q to be redacted from debug logs: %s", secret, out)
+		}
+	}
+
+	last := entries[3].ContextMap()
+	if last["attempt"] != int64(2) || last["status"] != int64(http.StatusOK) {
+		t.Errorf("Unexpected response fields %v", last)
+	}
+	if body, _ := last["body"].(string); !strings.HasSuffix(body, "...(truncated)") {
+		t.Errorf("Expected truncated body, got %q", body)
+	}
+}
+
+func fmtValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case http.Header:
+		var parts []string
+		for k, values := range v {
+			parts = append(parts, k+":"+strings.Join(values, ","))
+		}
+		return strings.Join(parts, ";")
+	}
+	return ""
+}
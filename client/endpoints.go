@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/khekrn/core/clock"
+)
+
+// BalanceStrategy selects which base URL serves a request
+type BalanceStrategy int
+
+// Balance strategies
+const (
+	BalancePriority   BalanceStrategy = iota // First healthy endpoint in order; later ones are failovers
+	BalanceRoundRobin                        // Rotate through healthy endpoints
+	BalanceWeighted                          // Pick healthy endpoints at random in proportion to their weight
+)
+
+// Endpoint is a base URL served by a client with several base URLs
+type Endpoint struct {
+	URL    string
+	Weight int // Relative share of traffic for BalanceWeighted; defaults to 1
+}
+
+// EndpointStatus reports the health of an endpoint
+type EndpointStatus struct {
+	URL     string
+	Healthy bool
+}
+
+// DefaultEndpointCooldown is how long a failing endpoint is avoided
+const DefaultEndpointCooldown = 30 * time.Second
+
+// WithBaseURLs spreads requests with relative URLs across several base URLs.
+// An endpoint whose attempt fails with a transport error or a 5xx response
+// is avoided for the endpoint cooldown, so retries move on to the next one.
+// When every endpoint is failing, all are tried again.
+//
+// Example:
+//
+//	builder.WithBaseURLs([]string{"https://eu.api.example.com", "https://us.api.example.com"}, client.BalancePriority)
+func (b *ClientBuilder) WithBaseURLs(baseURLs []string, strategy BalanceStrategy) *ClientBuilder {
+	endpoints := make([]Endpoint, len(baseURLs))
+	for i, baseURL := range baseURLs {
+		endpoints[i] = Endpoint{URL: baseURL, Weight: 1}
+	}
+	return b.WithEndpoints(endpoints, strategy)
+}
+
+// WithEndpoints is WithBaseURLs with per-endpoint weights
+func (b *ClientBuilder) WithEndpoints(endpoints []Endpoint, strategy BalanceStrategy) *ClientBuilder {
+	b.endpoints = make([]Endpoint, len(endpoints))
+	for i, endpoint := range endpoints {
+		endpoint.URL = strings.TrimSuffix(endpoint.URL, "/")
+		b.endpoints[i] = endpoint
+	}
+	b.balanceStrategy = strategy
+	return b
+}
+
+// WithEndpointCooldown sets how long a failing endpoint is avoided.
+// Defaults to DefaultEndpointCooldown.
+func (b *ClientBuilder) WithEndpointCooldown(cooldown time.Duration) *ClientBuilder {
+	b.endpointCooldown = cooldown
+	return b
+}
+
+// Endpoints returns the health of each base URL set with WithBaseURLs
+func (rc *RESTClient) Endpoints() []EndpointStatus {
+	if rc.endpoints == nil {
+		return nil
+	}
+	return rc.endpoints.status()
+}
+
+// endpointSet balances requests across endpoints and tracks their health
+type endpointSet struct {
+	endpoints []Endpoint
+	strategy  BalanceStrategy
+	cooldown  time.Duration
+	clock     clock.Clock
+	next      atomic.Uint64
+
+	mu        sync.Mutex
+	downUntil []time.Time
+}
+
+// newEndpointSet creates an endpoint set, applying defaults. clk times the
+// cooldowns.
+func newEndpointSet(endpoints []Endpoint, strategy BalanceStrategy, cooldown time.Duration, clk clock.Clock) *endpointSet {
+	if cooldown <= 0 {
+		cooldown = DefaultEndpointCooldown
+	}
+	for i := range endpoints {
+		if endpoints[i].Weight <= 0 {
+			endpoints[i].Weight = 1
+		}
+	}
+	return &endpointSet{
+		endpoints: endpoints,
+		strategy:  strategy,
+		cooldown:  cooldown,
+		clock:     clk,
+		downUntil: make([]time.Time, len(endpoints)),
+	}
+}
+
+// pick returns the index of the endpoint for the next attempt
+func (s *endpointSet) pick() int {
+	s.mu.Lock()
+	now := s.clock.Now()
+	var candidates []int
+	for i, until := range s.downUntil {
+		if now.After(until) {
+			candidates = append(candidates, i)
+		}
+	}
+	s.mu.Unlock()
+	if len(candidates) == 0 {
+		for i := range s.endpoints {
+			candidates = append(candidates, i)
+		}
+	}
+
+	switch s.strategy {
+	case BalanceRoundRobin:
+		return candidates[(s.next.Add(1)-1)%uint64(len(candidates))]
+	case BalanceWeighted:
+		total := 0
+		for _, i := range candidates {
+			total += s.endpoints[i].Weight
+		}
+		n := rand.N(total)
+		for _, i := range candidates {
+			if n -= s.endpoints[i].Weight; n < 0 {
+				return i
+			}
+		}
+	}
+	return candidates[0]
+}
+
+// report records the outcome of an attempt against endpoint i
+func (s *endpointSet) report(i int, resp *Response, err error) {
+	failed := resp != nil && resp.StatusCode >= 500
+	if err != nil && !errors.Is(err, context.Canceled) && !IsCircuitOpen(err) {
+		failed = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if failed {
+		s.downUntil[i] = s.clock.Now().Add(s.cooldown)
+	} else if err == nil {
+		s.downUntil[i] = time.Time{}
+	}
+}
+
+// status returns the health of every endpoint
+func (s *endpointSet) status() []EndpointStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	status := make([]EndpointStatus, len(s.endpoints))
+	for i, endpoint := range s.endpoints {
+		status[i] = EndpointStatus{URL: endpoint.URL, Healthy: now.After(s.downUntil[i])}
+	}
+	return status
+}
+
+// middleware sends each attempt to a chosen endpoint by replacing the
+// primary base URL the request was built with
+func (s *endpointSet) middleware(primary string) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*Response, error) {
+			i := s.pick()
+			target := req
+			if rawURL := req.URL.String(); i > 0 && strings.HasPrefix(rawURL, primary) {
+				u, err := url.Parse(s.endpoints[i].URL + strings.TrimPrefix(rawURL, primary))
+				if err != nil {
+					return nil, err
+				}
+				target = req.Clone(req.Context())
+				target.URL = u
+				target.Host = u.Host
+			}
+
+			resp, err := next(target)
+			s.report(i, resp, err)
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+	"github.com/khekrn/core/coretest"
+)
+
+// namedServer answers with its name, or with status when status is non-zero
+func namedServer(t *testing.T, name string, status *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if code := status.Load(); code != 0 {
+			w.WriteHeader(int(code))
+			return
+		}
+		w.Write([]byte(name + r.URL.RequestURI()))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRESTClient_BaseURLsPriorityFailover(t *testing.T) {
+	var primaryStatus, secondaryStatus atomic.Int32
+	primary := namedServer(t, "primary", &primaryStatus)
+	secondary := namedServer(t, "secondary", &secondaryStatus)
+
+	restClient := client.NewClientBuilder().
+		WithBaseURLs([]string{primary.URL, secondary.URL}, client.BalancePriority).
+		WithRetry(client.RetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}).
+		WithoutCircuitBreaker().
+		Build()
+
+	resp, err := restClient.GET("/orders", client.WithQueryParam("page", "2"))
+	if err != nil || resp.String() != "primary/orders?page=2" {
+		t.Fatalf("Expected primary to serve, got %v %q", err, resp.String())
+	}
+
+	primaryStatus.Store(http.StatusServiceUnavailable)
+	resp, err = restClient.GET("/orders")
+	if err != nil || resp.String() != "secondary/orders" {
+		t.Fatalf("Expected retry to fail over to secondary, got %v", err)
+	}
+
+	status := restClient.Endpoints()
+	if len(status) != 2 || status[0].Healthy || !status[1].Healthy {
+		t.Errorf("Unexpected endpoint status %+v", status)
+	}
+
+	resp, err = restClient.GET("/orders", client.WithNoRetry())
+	if err != nil || resp.String() != "secondary/orders" {
+		t.Errorf("Expected failing primary to be skipped, got %v", err)
+	}
+}
+
+func TestRESTClient_BaseURLsTrailingSlashAndClock(t *testing.T) {
+	var primaryStatus, secondaryStatus atomic.Int32
+	primary := namedServer(t, "primary", &primaryStatus)
+	secondary := namedServer(t, "secondary", &secondaryStatus)
+
+	clk := coretest.NewClock(time.Time{})
+	restClient := client.NewClientBuilder().
+		WithBaseURLs([]string{primary.URL + "/", secondary.URL + "/"}, client.BalancePriority).
+		WithEndpointCooldown(time.Minute).
+		WithClock(clk).
+		WithoutRetry().
+		WithoutCircuitBreaker().
+		Build()
+
+	get := func(want string) {
+		t.Helper()
+		resp, err := restClient.GET("/orders")
+		if err != nil || resp.String() != want {
+			t.Fatalf("Expected %q, got %v %q", want, err, resp.String())
+		}
+	}
+
+	get("primary/orders")
+	primaryStatus.Store(http.StatusServiceUnavailable)
+	restClient.GET("/orders")
+	primaryStatus.Store(0)
+	get("secondary/orders")
+
+	// The cooldown runs on the client's clock
+	clk.Advance(time.Minute + time.Second)
+	get("primary/orders")
+}
+
+func TestRESTClient_BaseURLsRoundRobin(t *testing.T) {
+	var aStatus, bStatus atomic.Int32
+	a := namedServer(t, "a", &aStatus)
+	b := namedServer(t, "b", &bStatus)
+
+	restClient := client.NewClientBuilder().
+		WithBaseURLs([]string{a.URL, b.URL}, client.BalanceRoundRobin).
+		Build()
+
+	counts := map[string]int{}
+	for i := 0; i < 4; i++ {
+		resp, err := restClient.GET("/x")
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		counts[resp.String()]++
+	}
+	if counts["a/x"] != 2 || counts["b/x"] != 2 {
+		t.Errorf("Expected requests to alternate, got %v", counts)
+	}
+}
+
+func TestRESTClient_BaseURLsWeighted(t *testing.T) {
+	var aStatus, bStatus atomic.Int32
+	a := namedServer(t, "a", &aStatus)
+	b := namedServer(t, "b", &bStatus)
+
+	restClient := client.NewClientBuilder().
+		WithEndpoints([]client.Endpoint{{URL: a.URL, Weight: 1}, {URL: b.URL, Weight: 3}}, client.BalanceWeighted).
+		Build()
+
+	counts := map[string]int{}
+	for i := 0; i < 200; i++ {
+		resp, err := restClient.GET("/x")
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		counts[resp.String()]++
+	}
+	if counts["b/x"] <= counts["a/x"] {
+		t.Errorf("Expected heavier endpoint to receive more traffic, got %v", counts)
+	}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/khekrn/core/response"
+)
+
+// Envelope parses the body as the standard envelope of services built on the
+// response package. Validation errors in the data of error envelopes are
+// returned as []response.ValidationError. Error responses without an
+// envelope, such as a proxy's 502 page, get a status and message derived from
+// the HTTP status. This only applies to responses the caller receives: a
+// status the retry policy retries, such as 502 by default, ends in
+// ErrMaxRetriesExceeded and no Response once the attempts are used up.
+//
+// Example:
+//
+//	resp, err := restClient.GET("/users/7")
+//	if err != nil {
+//		return err
+//	}
+//	envelope, err := resp.Envelope()
+func (r *Response) Envelope() (response.Response, error) {
+	var envelope response.Response
+	err := r.Decode(&envelope)
+	if err == nil && envelope.Status == "" {
+		err = errors.New("missing status")
+	}
+	if err != nil {
+		if r.IsSuccess() {
+			return envelope, fmt.Errorf("response is not a standard envelope: %w", err)
+		}
+		return statusEnvelope(r.StatusCode), nil
+	}
+
+	if envelope.Status != response.StatusAccept {
+		if validationErrors, ok := toValidationErrors(envelope.Data); ok {
+			envelope.Data = validationErrors
+		}
+	}
+	return envelope, nil
+}
+
+// EnvelopeAs parses the body as a standard envelope with Data of type T. An
+// error envelope is returned as a *response.APIError with the downstream
+// HTTP status, code, message, and validation errors, so handlers can pass it
+// on with response.FromError.
+//
+// Example:
+//
+//	user, err := client.EnvelopeAs[User](resp)
+//	if err != nil {
+//		return response.FromError(err) // e.g. the downstream ERR_NOT_FOUND
+//	}
+//	fmt.Println(user.Data.Email)
+func EnvelopeAs[T any](r *Response) (response.Typed[T], error) {
+	envelope, err := r.Envelope()
+	if err != nil {
+		return response.Typed[T]{}, err
+	}
+	if envelope.Status != response.StatusAccept {
+		typed := response.Typed[T]{
+			Status:    envelope.Status,
+			Code:      envelope.Code,
+			Message:   envelope.Message,
+			RequestID: envelope.RequestID,
+			TraceID:   envelope.TraceID,
+		}
+		return typed, &response.APIError{
+			HTTPStatus: r.StatusCode,
+			Code:       envelope.Code,
+			Message:    envelope.Message,
+			Data:       envelope.Data,
+			Err:        fmt.Errorf("downstream responded with status %d", r.StatusCode),
+		}
+	}
+
+	var typed response.Typed[T]
+	if err := r.Decode(&typed); err != nil {
+		return typed, err
+	}
+	return typed, nil
+}
+
+// statusEnvelope returns an error envelope for an HTTP status
+func statusEnvelope(statusCode int) response.Response {
+	envelope := response.NewErrorResponse(http.StatusText(statusCode))
+	if statusCode >= http.StatusInternalServerError {
+		envelope.Status = response.StatusFailure
+	}
+	return envelope
+}
+
+// toValidationErrors converts decoded envelope data to validation errors
+// when every item has a field or reason
+func toValidationErrors(data any) ([]response.ValidationError, bool) {
+	items, ok := data.([]any)
+	if !ok || len(items) == 0 {
+		return nil, false
+	}
+
+	validationErrors := make([]response.ValidationError, 0, len(items))
+	for _, item := range items {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		field, _ := fields["field"].(string)
+		reason, _ := fields["reason"].(string)
+		if field == "" && reason == "" {
+			return nil, false
+		}
+		validationErrors = append(validationErrors, response.ValidationError{Field: field, Reason: reason})
+	}
+	return validationErrors, true
+}
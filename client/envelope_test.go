@@ -0,0 +1,73 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khekrn/core/client"
+	"github.com/khekrn/core/response"
+)
+
+type envelopeUser struct {
+	ID    int    `json:"id"`
+	Email string `json:"email"`
+}
+
+func TestResponse_Envelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/7":
+			response.Write(w, r, http.StatusOK, response.NewSuccessResponse("User found", envelopeUser{ID: 7, Email: "ada@example.com"}))
+		case "/users/8":
+			response.WriteError(w, r, response.ErrNotFound)
+		case "/users":
+			response.Write(w, r, http.StatusBadRequest, response.NewErrorResponseWithValidationErrors("Validation failed",
+				response.ValidationError{Field: "email", Reason: "email is required"}))
+		case "/proxy":
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("<html>Bad Gateway</html>"))
+		default:
+			w.Write([]byte(`{"id":1}`))
+		}
+	}))
+	defer server.Close()
+
+	// Without retries the 502 is returned to the caller instead of ErrMaxRetriesExceeded
+	restClient := client.NewClientBuilder().WithBaseURL(server.URL).WithoutRetry().WithoutCircuitBreaker().Build()
+
+	resp, _ := restClient.GET("/users/7")
+	user, err := client.EnvelopeAs[envelopeUser](resp)
+	if err != nil || user.Data.Email != "ada@example.com" || !user.IsSuccess() {
+		t.Errorf("Unexpected typed envelope %+v, %v", user, err)
+	}
+
+	resp, _ = restClient.GET("/users/8")
+	_, err = client.EnvelopeAs[envelopeUser](resp)
+	var apiErr *response.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusNotFound || apiErr.Code != response.CodeNotFound {
+		t.Errorf("Expected a downstream APIError, got %v", err)
+	}
+
+	resp, _ = restClient.POST("/users", map[string]string{})
+	envelope, err := resp.Envelope()
+	validationErrors, ok := envelope.Data.([]response.ValidationError)
+	if err != nil || !ok || len(validationErrors) != 1 || validationErrors[0].Field != "email" {
+		t.Errorf("Expected validation errors, got %+v, %v", envelope, err)
+	}
+
+	resp, err = restClient.GET("/proxy")
+	if err != nil {
+		t.Fatalf("GET /proxy failed: %v", err)
+	}
+	envelope, err = resp.Envelope()
+	if err != nil || envelope.Status != response.StatusFailure || envelope.Message != "Bad Gateway" {
+		t.Errorf("Unexpected envelope for a non-envelope error %+v, %v", envelope, err)
+	}
+
+	resp, _ = restClient.GET("/raw")
+	if _, err := resp.Envelope(); err == nil {
+		t.Error("Expected an error for a successful response without an envelope")
+	}
+}
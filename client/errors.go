@@ -0,0 +1,103 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/khekrn/core/helpers"
+)
+
+// maxErrorBodySnippet bounds how much of the body HTTPError.Error includes
+const maxErrorBodySnippet = 256
+
+// HTTPError is returned for unsuccessful HTTP responses. Use errors.As to
+// inspect the downstream status and error payload.
+type HTTPError struct {
+	StatusCode int
+	Body       []byte
+	Headers    http.Header
+	// URL is the request URL with secret query parameters redacted
+	URL      string
+	Method   string
+	Attempts int
+
+	// snippet is Body with secret fields redacted, used by Error
+	snippet []byte
+}
+
+// Error describes the failed request and the start of the response body
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("%s %s: HTTP %d", e.Method, e.URL, e.StatusCode)
+	body := e.Body
+	if e.snippet != nil {
+		body = e.snippet
+	}
+	if len(body) > 0 {
+		if len(body) > maxErrorBodySnippet {
+			body = body[:maxErrorBodySnippet]
+		}
+		msg += ": " + string(body)
+	}
+	return msg
+}
+
+// newHTTPError builds an HTTPError from the response to req, redacting the
+// URL and body snippet like every other request dump
+func (r *redactor) newHTTPError(req *http.Request, resp *Response, attempts int) *HTTPError {
+	return r.httpError(req.Method, r.url(req.URL), resp, attempts)
+}
+
+// httpError builds an HTTPError for an already redacted URL
+func (r *redactor) httpError(method, redactedURL string, resp *Response, attempts int) *HTTPError {
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Body:       resp.Body,
+		Headers:    resp.Headers,
+		URL:        redactedURL,
+		Method:     method,
+		Attempts:   attempts,
+		snippet:    r.body(resp.Headers.Get("Content-Type"), resp.Body),
+	}
+}
+
+// maxStreamedErrorBody bounds how much of a streamed error response is read
+const maxStreamedErrorBody = 64 << 10
+
+// WithErrorOnNonSuccess makes requests return an *HTTPError instead of a
+// response when the status is not 2xx
+func (b *ClientBuilder) WithErrorOnNonSuccess() *ClientBuilder {
+	b.errorOnNonSuccess = true
+	return b
+}
+
+// WithErrorOnNonSuccess returns an *HTTPError for a non-2xx response to this request
+func WithErrorOnNonSuccess() RequestOption {
+	return func(config *RequestConfig) {
+		config.ErrorOnNonSuccess = true
+	}
+}
+
+// ErrorJSON decodes the JSON error envelope carried by an *HTTPError in err's chain
+func ErrorJSON[T any](err error) (*T, error) {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return nil, fmt.Errorf("not an HTTP error: %w", err)
+	}
+	envelope, decodeErr := helpers.FromJSON[T](httpErr.Body)
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode error response: %w", decodeErr)
+	}
+	return envelope, nil
+}
+
+// nonSuccessError converts a non-2xx response into an *HTTPError, reading
+// and closing the body of a streamed response
+func (rc *RESTClient) nonSuccessError(req *http.Request, resp *Response) *HTTPError {
+	if resp.Body == nil && resp.Response != nil {
+		resp.Body, _ = io.ReadAll(io.LimitReader(resp.Response.Body, maxStreamedErrorBody))
+		resp.Response.Body.Close()
+	}
+	return rc.redactor.newHTTPError(req, resp, resp.attempts)
+}
@@ -0,0 +1,107 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/khekrn/core/client"
+)
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func TestRESTClient_ErrorOnNonSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ok" {
+			w.Write([]byte("fine"))
+			return
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"code":"invalid_amount","message":"amount must be positive"}`))
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutCircuitBreaker().
+		Build()
+
+	// Default behaviour returns the response
+	resp, err := restClient.POST("/payments", map[string]int{"amount": -1})
+	if err != nil || resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422 response without error, got %v", err)
+	}
+
+	// Per request
+	_, err = restClient.POST("/payments", map[string]int{"amount": -1}, client.WithErrorOnNonSuccess())
+	var httpErr *client.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnprocessableEntity || httpErr.Attempts != 1 {
+		t.Fatalf("Expected 422 HTTPError, got %v", err)
+	}
+
+	envelope, err := client.ErrorJSON[apiError](httpErr)
+	if err != nil {
+		t.Fatalf("ErrorJSON failed: %v", err)
+	}
+	if envelope.Code != "invalid_amount" || envelope.Message != "amount must be positive" {
+		t.Errorf("Unexpected error envelope: %+v", envelope)
+	}
+	if _, err := client.ErrorJSON[apiError](errors.New("dial tcp: timeout")); err == nil {
+		t.Error("Expected ErrorJSON to reject non-HTTP errors")
+	}
+
+	// Client level, including streamed responses
+	strict := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutCircuitBreaker().
+		WithErrorOnNonSuccess().
+		Build()
+	if _, err := strict.GET("/ok"); err != nil {
+		t.Errorf("Expected success for 200, got %v", err)
+	}
+	_, err = strict.GET("/payments", client.WithStreamResponse())
+	if !errors.As(err, &httpErr) || string(httpErr.Body) == "" {
+		t.Errorf("Expected HTTPError with streamed body read, got %v", err)
+	}
+}
+
+func TestHTTPError_RedactsURLAndBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":"bad_login","password":"hunter2"}`))
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutCircuitBreaker().
+		WithErrorOnNonSuccess().
+		Build()
+
+	_, err := restClient.GET("/login?user=ann&api_key=abc123")
+	var httpErr *client.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("Expected HTTPError, got %v", err)
+	}
+	msg := err.Error()
+	for _, secret := range []string{"abc123", "hunter2"} {
+		if strings.Contains(msg, secret) || strings.Contains(httpErr.URL, secret) {
+			t.Errorf("Expected %q to be redacted, got %q", secret, msg)
+		}
+	}
+	if !strings.Contains(msg, "user=ann") || !strings.Contains(msg, "bad_login") {
+		t.Errorf("Expected non-secret details to be kept, got %q", msg)
+	}
+
+	// The raw body is still available for decoding
+	envelope, err := client.ErrorJSON[map[string]string](httpErr)
+	if err != nil || (*envelope)["password"] != "hunter2" {
+		t.Errorf("Expected ErrorJSON to decode the raw body, got %v, %v", envelope, err)
+	}
+}
@@ -0,0 +1,31 @@
+package client
+
+import "errors"
+
+// FallbackFunc produces a response for a request that could not be served,
+// e.g. from a cache or a static default. It receives the request
+// configuration, with the caller's context rather than the request's timeout
+// context, and the error; returning an error fails the request with it.
+type FallbackFunc func(config RequestConfig, err error) (*Response, error)
+
+// WithFallback sets a function that answers requests rejected by the circuit
+// breaker or failed after retries are exhausted, including by the retry
+// budget. Other errors, such as cancellation, are returned unchanged.
+//
+// Example:
+//
+//	builder.WithFallback(func(config client.RequestConfig, err error) (*client.Response, error) {
+//		if cached, ok := lastKnown.Load(config.URL); ok {
+//			return cached.(*client.Response), nil
+//		}
+//		return nil, err
+//	})
+func (b *ClientBuilder) WithFallback(fallback FallbackFunc) *ClientBuilder {
+	b.fallback = fallback
+	return b
+}
+
+// shouldFallback reports whether err is served by the fallback
+func shouldFallback(err error) bool {
+	return IsCircuitOpen(err) || errors.Is(err, ErrMaxRetriesExceeded) || errors.Is(err, ErrRetryBudgetExhausted)
+}
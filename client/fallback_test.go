@@ -0,0 +1,96 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_FallbackAfterRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var fallbackErr error
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutCircuitBreaker().
+		WithRetry(client.RetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}).
+		WithFallback(func(config client.RequestConfig, err error) (*client.Response, error) {
+			fallbackErr = err
+			return &client.Response{StatusCode: http.StatusOK, Body: []byte(`{"source":"` + config.URL + `"}`)}, nil
+		}).
+		Build()
+
+	resp, err := restClient.GET("/orders")
+	if err != nil {
+		t.Fatalf("Expected fallback response, got %v", err)
+	}
+	if got := resp.String(); got != `{"source":"/orders"}` {
+		t.Errorf("Unexpected fallback body %q", got)
+	}
+	if !errors.Is(fallbackErr, client.ErrMaxRetriesExceeded) {
+		t.Errorf("Expected fallback to receive ErrMaxRetriesExceeded, got %v", fallbackErr)
+	}
+}
+
+func TestRESTClient_FallbackGetsCallerContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var fallbackCtx context.Context
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutCircuitBreaker().
+		WithRetry(client.RetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}).
+		WithFallback(func(config client.RequestConfig, err error) (*client.Response, error) {
+			fallbackCtx = config.Context
+			return &client.Response{StatusCode: http.StatusOK}, nil
+		}).
+		Build()
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "caller")
+	if _, err := restClient.GET("/orders", client.WithContext(ctx), client.WithTimeout(time.Second)); err != nil {
+		t.Fatalf("Expected fallback response, got %v", err)
+	}
+	if fallbackCtx != ctx || fallbackCtx.Err() != nil {
+		t.Errorf("Expected the fallback to get the live caller context, got %v", fallbackCtx)
+	}
+}
+
+func TestRESTClient_FallbackWhenCircuitOpen(t *testing.T) {
+	fallbacks := 0
+	restClient := client.NewClientBuilder().
+		WithTransport(failingHostTransport()).
+		WithoutRetry().
+		WithCircuitBreaker(client.CircuitBreakerConfig{Name: "api", Timeout: time.Minute, ReadyToTrip: tripAfterTwo}).
+		WithFallback(func(config client.RequestConfig, err error) (*client.Response, error) {
+			if !client.IsCircuitOpen(err) {
+				t.Errorf("Expected fallback only for open circuit, got %v", err)
+			}
+			fallbacks++
+			return nil, err
+		}).
+		Build()
+
+	for i := 0; i < 2; i++ {
+		if _, err := restClient.GET("http://down/items"); err == nil || client.IsCircuitOpen(err) {
+			t.Errorf("Expected transport failure before the circuit opens, got %v", err)
+		}
+	}
+	if _, err := restClient.GET("http://down/items"); !client.IsCircuitOpen(err) {
+		t.Errorf("Expected fallback error to be returned, got %v", err)
+	}
+	if fallbacks != 1 {
+		t.Errorf("Expected 1 fallback call, got %d", fallbacks)
+	}
+}
@@ -0,0 +1,272 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/khekrn/core/helpers"
+)
+
+// HARRecorder captures client traffic as an HTTP Archive (HAR 1.2) for
+// inspection in browser devtools. It keeps the most recent entries up to
+// its limit and can be switched on and off at runtime. Secrets are
+// redacted as described by Redaction.
+//
+// Example:
+//
+//	har := client.NewHARRecorder(500, 64*1024)
+//	restClient := client.NewClientBuilder().WithHARRecorder(har).Build()
+//
+//	// ... reproduce the issue ...
+//	har.Save("orders.har")
+type HARRecorder struct {
+	maxEntries  int
+	maxBodySize int
+	enabled     atomic.Bool
+
+	mu      sync.Mutex
+	entries []HAREntry
+}
+
+// HAR is the root of an HTTP Archive
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog holds the recorded entries
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator names the application that produced the archive
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry is one request and its response
+type HAREntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"` // Total time in milliseconds
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+// HARRequest is a recorded request
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	Cookies     []HARNameValue `json:"cookies"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARResponse is a recorded response
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []HARNameValue `json:"headers"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARNameValue is a header or query parameter
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARPostData is a recorded request body
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// HARContent is a recorded response body
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+// HARTimings breaks down the entry time in milliseconds
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// NewHARRecorder creates an enabled recorder keeping up to maxEntries
+// entries with bodies truncated to maxBodySize bytes
+func NewHARRecorder(maxEntries, maxBodySize int) *HARRecorder {
+	r := &HARRecorder{maxEntries: max(maxEntries, 1), maxBodySize: maxBodySize}
+	r.enabled.Store(true)
+	return r
+}
+
+// WithHARRecorder records every attempt the client makes into recorder
+func (b *ClientBuilder) WithHARRecorder(recorder *HARRecorder) *ClientBuilder {
+	b.harRecorder = recorder
+	return b
+}
+
+// Enable starts recording
+func (r *HARRecorder) Enable() {
+	r.enabled.Store(true)
+}
+
+// Disable stops recording; entries already recorded are kept
+func (r *HARRecorder) Disable() {
+	r.enabled.Store(false)
+}
+
+// Enabled reports whether the recorder is recording
+func (r *HARRecorder) Enabled() bool {
+	return r.enabled.Load()
+}
+
+// Reset discards the recorded entries
+func (r *HARRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// HAR returns the recorded entries as an archive
+func (r *HARRecorder) HAR() *HAR {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "github.com/khekrn/core/client", Version: "1.0"},
+		Entries: append([]HAREntry{}, r.entries...),
+	}}
+}
+
+// Save writes the archive to path
+func (r *HARRecorder) Save(path string) error {
+	data, err := helpers.ToJSON(r.HAR())
+	if err != nil {
+		return fmt.Errorf("failed to encode HAR: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write HAR: %w", err)
+	}
+	return nil
+}
+
+// middleware records each attempt while the recorder is enabled
+func (r *HARRecorder) middleware(redactor *redactor) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*Response, error) {
+			if !r.Enabled() {
+				return next(req)
+			}
+
+			entry := HAREntry{StartedDateTime: time.Now(), Request: r.request(req, redactor)}
+			resp, err := next(req)
+			elapsed := float64(time.Since(entry.StartedDateTime).Microseconds()) / 1000
+			entry.Time = elapsed
+			entry.Timings = HARTimings{Wait: elapsed}
+
+			switch {
+			case err != nil:
+				entry.Comment = err.Error()
+			default:
+				entry.Response = r.response(resp, redactor)
+			}
+
+			r.mu.Lock()
+			r.entries = append(r.entries, entry)
+			if len(r.entries) > r.maxEntries {
+				r.entries = append([]HAREntry(nil), r.entries[len(r.entries)-r.maxEntries:]...)
+			}
+			r.mu.Unlock()
+			return resp, err
+		}
+	}
+}
+
+// request converts req to a HAR request
+func (r *HARRecorder) request(req *http.Request, redactor *redactor) HARRequest {
+	recorded := HARRequest{
+		Method:      req.Method,
+		URL:         redactor.url(req.URL),
+		HTTPVersion: req.Proto,
+		Headers:     harHeaders(redactor.header(req.Header)),
+		QueryString: []HARNameValue{},
+		Cookies:     []HARNameValue{},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	for name, values := range redactor.values(req.URL.Query()) {
+		for _, value := range values {
+			recorded.QueryString = append(recorded.QueryString, HARNameValue{Name: name, Value: value})
+		}
+	}
+	if body, ok := redactor.requestBody(req); ok && len(body) > 0 {
+		recorded.BodySize = len(body)
+		recorded.PostData = &HARPostData{MimeType: req.Header.Get("Content-Type"), Text: r.truncate(body)}
+	}
+	return recorded
+}
+
+// response converts resp to a HAR response
+func (r *HARRecorder) response(resp *Response, redactor *redactor) HARResponse {
+	recorded := HARResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: "HTTP/1.1",
+		Headers:     harHeaders(redactor.header(resp.Headers)),
+		Cookies:     []HARNameValue{},
+		Content:     HARContent{Size: -1, MimeType: resp.Headers.Get("Content-Type")},
+		RedirectURL: resp.Headers.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	if resp.Response != nil && resp.Response.Proto != "" {
+		recorded.HTTPVersion = resp.Response.Proto
+	}
+	if resp.Body != nil {
+		recorded.BodySize = len(resp.Body)
+		recorded.Content.Size = len(resp.Body)
+		recorded.Content.Text = r.truncate(redactor.body(recorded.Content.MimeType, resp.Body))
+	}
+	return recorded
+}
+
+// truncate caps body at the recorder's body size
+func (r *HARRecorder) truncate(body []byte) string {
+	if r.maxBodySize > 0 && len(body) > r.maxBodySize {
+		return string(body[:r.maxBodySize])
+	}
+	return string(body)
+}
+
+// harHeaders flattens header into HAR name/value pairs
+func harHeaders(header http.Header) []HARNameValue {
+	pairs := []HARNameValue{}
+	for name, values := range header {
+		for _, value := range values {
+			pairs = append(pairs, HARNameValue{Name: name, Value: value})
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,70 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/khekrn/core/client"
+	"github.com/khekrn/core/helpers"
+)
+
+func TestHARRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("response for " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	har := client.NewHARRecorder(2, 8)
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithHARRecorder(har).
+		Build()
+
+	restClient.GET("/one")
+	har.Disable()
+	restClient.GET("/ignored")
+	har.Enable()
+	restClient.POST("/two", map[string]string{"password": "p"}, client.WithHeader("Authorization", "Bearer secret"))
+	restClient.GET("/three", client.WithQueryParam("token", "t"))
+
+	entries := har.HAR().Log.Entries
+	if len(entries) != 2 {
+		t.Fatalf("Expected recorder to keep the last 2 entries, got %d", len(entries))
+	}
+	post := entries[0]
+	if post.Request.Method != http.MethodPost || !strings.HasSuffix(post.Request.URL, "/two") {
+		t.Errorf("Unexpected first entry %s %s", post.Request.Method, post.Request.URL)
+	}
+	for _, header := range post.Request.Headers {
+		if header.Name == "Authorization" && header.Value != "REDACTED" {
+			t.Errorf("Expected Authorization to be redacted, got %q", header.Value)
+		}
+	}
+	if post.Request.PostData == nil || strings.Contains(post.Request.PostData.Text, `"p"`) {
+		t.Errorf("Expected redacted post data, got %+v", post.Request.PostData)
+	}
+	if post.Response.Status != http.StatusOK || post.Response.Content.Text != "response" || post.Response.Content.Size != 17 {
+		t.Errorf("Unexpected response %+v", post.Response.Content)
+	}
+	if url := entries[1].Request.URL; !strings.HasSuffix(url, "token=REDACTED") {
+		t.Errorf("Expected query secret to be redacted, got %s", url)
+	}
+
+	path := filepath.Join(t.TempDir(), "traffic.har")
+	if err := har.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := helpers.FromJSON[client.HAR](data)
+	if err != nil || saved.Log.Version != "1.2" || len(saved.Log.Entries) != 2 {
+		t.Errorf("Unexpected saved archive: %v", err)
+	}
+}
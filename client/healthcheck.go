@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithHealthCheck probes path on the base URL every interval in the
+// background. Probes run through the client's middleware and circuit
+// breaker, and a non-2xx answer counts as a breaker failure, so a dead
+// dependency opens the breaker before real traffic reaches it and a
+// recovered one closes it again once the breaker's timeout lets probes
+// through. Use Healthy to read the last result and Close to stop probing.
+func (b *ClientBuilder) WithHealthCheck(path string, interval time.Duration) *ClientBuilder {
+	b.healthCheck = &healthCheckConfig{path: path, interval: interval}
+	return b
+}
+
+// healthCheckConfig configures background health probing
+type healthCheckConfig struct {
+	path     string
+	interval time.Duration
+}
+
+// healthChecker tracks the result of background probes
+type healthChecker struct {
+	healthy atomic.Bool
+	stop    chan struct{}
+	once    sync.Once
+}
+
+// Healthy reports whether the last health probe succeeded. It is always
+// true for clients without a health check.
+func (rc *RESTClient) Healthy() bool {
+	return rc.health == nil || rc.health.healthy.Load()
+}
+
+// Close stops background work started by the client, such as health
+// probing. The client remains usable for requests.
+func (rc *RESTClient) Close() {
+	if rc.health != nil {
+		rc.health.once.Do(func() { close(rc.health.stop) })
+	}
+}
+
+// startHealthCheck probes immediately and then on every interval until Close
+func (rc *RESTClient) startHealthCheck(config healthCheckConfig) {
+	rc.health = &healthChecker{stop: make(chan struct{})}
+	rc.health.healthy.Store(true)
+
+	go func() {
+		ticker := time.NewTicker(config.interval)
+		defer ticker.Stop()
+		for {
+			rc.health.healthy.Store(rc.probe(config) == nil)
+			select {
+			case <-ticker.C:
+			case <-rc.health.stop:
+				return
+			}
+		}
+	}()
+}
+
+// probe sends one health check request, bounded by the probe interval
+func (rc *RESTClient) probe(config healthCheckConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), config.interval)
+	defer cancel()
+
+	req, err := rc.createRequest(RequestConfig{Method: GET, URL: config.path, Context: ctx})
+	if err != nil {
+		return err
+	}
+
+	handler := rc.chain(func(req *http.Request) (*Response, error) {
+		check := func() (*http.Response, error) {
+			resp, err := rc.client.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				return resp, fmt.Errorf("health check returned HTTP %d", resp.StatusCode)
+			}
+			return resp, nil
+		}
+
+		var resp *http.Response
+		var err error
+		if cb := rc.breakerFor(req); cb != nil {
+			resp, err = cb.Execute(check)
+		} else {
+			resp, err = check()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &Response{Response: resp, Body: []byte{}, StatusCode: resp.StatusCode, Headers: resp.Header}, nil
+	})
+	_, err = handler(req)
+	return err
+}
@@ -0,0 +1,64 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+	"github.com/sony/gobreaker/v2"
+)
+
+// waitFor polls cond until it holds or the test times out
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRESTClient_HealthCheck(t *testing.T) {
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			t.Errorf("Unexpected probe path %s", r.URL.Path)
+		}
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithCircuitBreaker(client.CircuitBreakerConfig{Name: "health", MaxRequests: 1, Timeout: 50 * time.Millisecond, ReadyToTrip: tripAfterTwo}).
+		WithHealthCheck("/health", 10*time.Millisecond).
+		Build()
+	defer restClient.Close()
+
+	if !restClient.Healthy() {
+		t.Error("Expected client to start healthy")
+	}
+
+	down.Store(true)
+	waitFor(t, "unhealthy", func() bool { return !restClient.Healthy() })
+	waitFor(t, "open circuit", func() bool { return restClient.CircuitBreakerState() == gobreaker.StateOpen })
+
+	down.Store(false)
+	waitFor(t, "healthy", restClient.Healthy)
+	waitFor(t, "closed circuit", func() bool { return restClient.CircuitBreakerState() == gobreaker.StateClosed })
+}
+
+func TestRESTClient_HealthyWithoutHealthCheck(t *testing.T) {
+	restClient := client.NewClientBuilder().Build()
+	defer restClient.Close()
+	if !restClient.Healthy() {
+		t.Error("Expected clients without a health check to report healthy")
+	}
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sony/gobreaker/v2"
+)
+
+// RequestInfo describes a request attempt passed to hooks
+type RequestInfo struct {
+	Method  string
+	URL     string
+	Attempt int           // 1-based attempt number
+	Elapsed time.Duration // Time since the first attempt started
+}
+
+// Hooks are observational callbacks invoked during a request's lifecycle.
+// Any hook may be nil. Hooks must not retain or mutate the request.
+type Hooks struct {
+	// OnRequest is called before each attempt
+	OnRequest func(info RequestInfo)
+	// OnResponse is called after each attempt that received a response
+	OnResponse func(info RequestInfo, resp *Response)
+	// OnRetry is called before waiting to retry, with the failure of the previous attempt
+	OnRetry func(info RequestInfo, delay time.Duration, err error)
+	// OnError is called once with the final error when the request fails.
+	// Use IsCircuitOpen to detect calls rejected by the circuit breaker.
+	OnError func(info RequestInfo, err error)
+	// OnRetryBudgetExhausted is called when the retry budget denies a retry,
+	// before OnError. err wraps ErrRetryBudgetExhausted.
+	OnRetryBudgetExhausted func(info RequestInfo, err error)
+}
+
+// WithHooks sets lifecycle hooks on the client
+func (b *ClientBuilder) WithHooks(hooks Hooks) *ClientBuilder {
+	b.hooks = hooks
+	return b
+}
+
+// IsCircuitOpen reports whether err was caused by the circuit breaker rejecting the call
+func IsCircuitOpen(err error) bool {
+	return errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests)
+}
+
+// requestInfo builds the hook info for an attempt
+func requestInfo(req *http.Request, attempt int, start time.Time) RequestInfo {
+	return RequestInfo{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Attempt: attempt,
+		Elapsed: time.Since(start),
+	}
+}
+
+// request calls OnRequest if set
+func (h Hooks) request(info RequestInfo) {
+	if h.OnRequest != nil {
+		h.OnRequest(info)
+	}
+}
+
+// response calls OnResponse if set
+func (h Hooks) response(info RequestInfo, resp *Response) {
+	if h.OnResponse != nil {
+		h.OnResponse(info, resp)
+	}
+}
+
+// retry calls OnRetry if set
+func (h Hooks) retry(info RequestInfo, delay time.Duration, err error) {
+	if h.OnRetry != nil {
+		h.OnRetry(info, delay, err)
+	}
+}
+
+// error calls OnError if set
+func (h Hooks) error(info RequestInfo, err error) {
+	if h.OnError != nil {
+		h.OnError(info, err)
+	}
+}
+
+// retryBudgetExhausted calls OnRetryBudgetExhausted if set
+func (h Hooks) retryBudgetExhausted(info RequestInfo, err error) {
+	if h.OnRetryBudgetExhausted != nil {
+		h.OnRetryBudgetExhausted(info, err)
+	}
+}
@@ -0,0 +1,91 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+	"github.com/sony/gobreaker/v2"
+)
+
+func TestRESTClient_Hooks(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var requests, responses, retries, errs []client.RequestInfo
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithRetry(client.RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}).
+		WithHooks(client.Hooks{
+			OnRequest:  func(info client.RequestInfo) { requests = append(requests, info) },
+			OnResponse: func(info client.RequestInfo, resp *client.Response) { responses = append(responses, info) },
+			OnRetry: func(info client.RequestInfo, delay time.Duration, err error) {
+				if err == nil {
+					t.Error("Expected OnRetry to receive the previous failure")
+				}
+				retries = append(retries, info)
+			},
+			OnError: func(info client.RequestInfo, err error) { errs = append(errs, info) },
+		}).
+		Build()
+
+	if _, err := restClient.GET("/flaky"); err != nil {
+		t.Fatalf("Expected success after retries, got %v", err)
+	}
+
+	if len(requests) != 3 || len(responses) != 3 || len(retries) != 2 || len(errs) != 0 {
+		t.Fatalf("Unexpected hook counts: requests=%d responses=%d retries=%d errors=%d",
+			len(requests), len(responses), len(retries), len(errs))
+	}
+	for i, info := range requests {
+		if info.Attempt != i+1 || info.Method != "GET" {
+			t.Errorf("Unexpected request info %+v", info)
+		}
+	}
+	if requests[2].Elapsed <= 0 {
+		t.Error("Expected elapsed time to be tracked across attempts")
+	}
+}
+
+func TestRESTClient_HooksCircuitOpen(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var rejected int
+	restClient := client.NewClientBuilder().
+		WithoutRetry().
+		WithCircuitBreaker(client.CircuitBreakerConfig{
+			Name:        "always-open",
+			Timeout:     time.Minute,
+			ReadyToTrip: func(counts gobreaker.Counts) bool { return true },
+		}).
+		WithHooks(client.Hooks{
+			OnError: func(info client.RequestInfo, err error) {
+				if client.IsCircuitOpen(err) {
+					rejected++
+				}
+			},
+		}).
+		Build()
+
+	// A transport failure trips the breaker, then the next call is rejected
+	restClient.GET("http://127.0.0.1:0/unreachable")
+	if _, err := restClient.GET(server.URL + "/ok"); !client.IsCircuitOpen(err) {
+		t.Fatalf("Expected circuit open error, got %v", err)
+	}
+	if rejected != 1 {
+		t.Errorf("Expected 1 rejected call reported to OnError, got %d", rejected)
+	}
+}
@@ -0,0 +1,14 @@
+package client
+
+// HTTPClient is the request API implemented by RESTClient. Accept it instead
+// of *RESTClient so tests can inject a fake.
+type HTTPClient interface {
+	GET(url string, options ...RequestOption) (*Response, error)
+	POST(url string, body interface{}, options ...RequestOption) (*Response, error)
+	PUT(url string, body interface{}, options ...RequestOption) (*Response, error)
+	PATCH(url string, body interface{}, options ...RequestOption) (*Response, error)
+	DELETE(url string, options ...RequestOption) (*Response, error)
+	Request(config RequestConfig) (*Response, error)
+}
+
+var _ HTTPClient = (*RESTClient)(nil)
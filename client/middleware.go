@@ -0,0 +1,48 @@
+package client
+
+import "net/http"
+
+// Handler executes a single request attempt
+type Handler func(req *http.Request) (*Response, error)
+
+// Middleware wraps a Handler to inspect or mutate outgoing requests and
+// incoming responses. Middleware runs once per attempt, inside the retry
+// loop and outside the circuit breaker.
+//
+// Example:
+//
+//	func Timing(next client.Handler) client.Handler {
+//		return func(req *http.Request) (*client.Response, error) {
+//			start := time.Now()
+//			resp, err := next(req)
+//			metrics.Observe(req.URL.Path, time.Since(start))
+//			return resp, err
+//		}
+//	}
+type Middleware func(next Handler) Handler
+
+// WithMiddleware appends middleware to the client. The first middleware
+// registered is the outermost and sees the request first.
+func (b *ClientBuilder) WithMiddleware(middleware ...Middleware) *ClientBuilder {
+	b.middleware = append(b.middleware, middleware...)
+	return b
+}
+
+// chain wraps handler with the client's middleware. Authentication and
+// signing run innermost so they apply to every attempt, with signing last.
+// The cache sits outside authentication so cache hits skip it.
+func (rc *RESTClient) chain(handler Handler) Handler {
+	if rc.signer != nil {
+		handler = signerMiddleware(rc.signer)(handler)
+	}
+	if rc.auth != nil {
+		handler = authMiddleware(rc.auth)(handler)
+	}
+	if rc.cache != nil {
+		handler = cacheMiddleware(rc.cache, rc.clock)(handler)
+	}
+	for i := len(rc.middleware) - 1; i >= 0; i-- {
+		handler = rc.middleware[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,74 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_Middleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen-Token", r.Header.Get("Authorization"))
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) client.Middleware {
+		return func(next client.Handler) client.Handler {
+			return func(req *http.Request) (*client.Response, error) {
+				order = append(order, name+":before")
+				resp, err := next(req)
+				order = append(order, name+":after")
+				return resp, err
+			}
+		}
+	}
+	auth := func(next client.Handler) client.Handler {
+		return func(req *http.Request) (*client.Response, error) {
+			req.Header.Set("Authorization", "Bearer injected")
+			return next(req)
+		}
+	}
+	upper := func(next client.Handler) client.Handler {
+		return func(req *http.Request) (*client.Response, error) {
+			resp, err := next(req)
+			if err == nil {
+				resp.Body = []byte(strings.ToUpper(string(resp.Body)))
+			}
+			return resp, err
+		}
+	}
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithMiddleware(trace("outer"), trace("inner"), auth, upper).
+		Build()
+
+	resp, err := restClient.GET("/greeting")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+
+	if resp.Headers.Get("X-Seen-Token") != "Bearer injected" {
+		t.Errorf("Expected middleware to inject auth header, got %q", resp.Headers.Get("X-Seen-Token"))
+	}
+	if resp.String() != "HELLO" {
+		t.Errorf("Expected middleware to mutate response, got %q", resp.String())
+	}
+
+	expected := []string{"outer:before", "inner:before", "inner:after", "outer:after"}
+	if strings.Join(order, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected order %v, got %v", expected, order)
+	}
+
+	// Derived clients inherit middleware
+	derived := client.FromSharedClient(restClient, "derived", server.URL).Build()
+	resp, err = derived.GET("/greeting")
+	if err != nil || resp.String() != "HELLO" {
+		t.Errorf("Expected derived client to inherit middleware, got %q (%v)", resp.String(), err)
+	}
+}
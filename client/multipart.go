@@ -0,0 +1,105 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// quoteEscaper escapes quoted Content-Disposition parameters, as mime/multipart does
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// FileField is a file part of a multipart/form-data body
+type FileField struct {
+	FieldName   string    // Form field name
+	FileName    string    // File name reported to the server
+	ContentType string    // Defaults to application/octet-stream
+	Reader      io.Reader // File contents
+}
+
+// Multipart is a multipart/form-data request body.
+// Pass it as the body of POST/PUT/PATCH, or build it with WithFilePart and WithFormField.
+type Multipart struct {
+	Fields map[string]string
+	Files  []FileField
+}
+
+// encode writes the multipart body and returns it with its Content-Type header
+func (m *Multipart) encode() ([]byte, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	// Sort field names so the encoded body is deterministic
+	keys := make([]string, 0, len(m.Fields))
+	for k := range m.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := writer.WriteField(k, m.Fields[k]); err != nil {
+			return nil, "", fmt.Errorf("failed to write form field %s: %w", k, err)
+		}
+	}
+
+	for _, file := range m.Files {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(file.FieldName), quoteEscaper.Replace(file.FileName)))
+		header.Set("Content-Type", contentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create file part %s: %w", file.FieldName, err)
+		}
+		if _, err := io.Copy(part, file.Reader); err != nil {
+			return nil, "", fmt.Errorf("failed to write file part %s: %w", file.FieldName, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to close multipart body: %w", err)
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// PostMultipart executes a POST request with a multipart/form-data body
+func (rc *RESTClient) PostMultipart(url string, files []FileField, fields map[string]string, options ...RequestOption) (*Response, error) {
+	return rc.POST(url, &Multipart{Fields: fields, Files: files}, options...)
+}
+
+// WithFilePart adds a file part to a multipart/form-data request body
+func WithFilePart(fieldName, fileName string, reader io.Reader) RequestOption {
+	return func(config *RequestConfig) {
+		body := multipartBody(config)
+		body.Files = append(body.Files, FileField{FieldName: fieldName, FileName: fileName, Reader: reader})
+	}
+}
+
+// WithFormField adds a field to a multipart/form-data request body
+func WithFormField(key, value string) RequestOption {
+	return func(config *RequestConfig) {
+		body := multipartBody(config)
+		if body.Fields == nil {
+			body.Fields = make(map[string]string)
+		}
+		body.Fields[key] = value
+	}
+}
+
+// multipartBody returns the request's multipart body, creating it if the body is unset
+func multipartBody(config *RequestConfig) *Multipart {
+	if body, ok := config.Body.(*Multipart); ok {
+		return body
+	}
+	body := &Multipart{}
+	config.Body = body
+	return body
+}
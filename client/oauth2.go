@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khekrn/core/helpers"
+)
+
+// OAuth2Config holds configuration for the OAuth2 client-credentials flow
+type OAuth2Config struct {
+	TokenURL       string
+	ClientID       string
+	ClientSecret   string
+	Scopes         []string
+	EndpointParams url.Values    // Extra token request parameters, e.g. audience
+	AuthInBody     bool          // Send client credentials in the form body instead of Basic auth
+	ExpiryDelta    time.Duration // Refresh this long before the token expires, defaults to 30s
+	HTTPClient     *http.Client  // Client for token requests, defaults to the REST client's HTTP client
+}
+
+// tokenResponse is the token endpoint response body
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// oauth2TokenSource fetches and caches client-credentials access tokens
+type oauth2TokenSource struct {
+	config OAuth2Config
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// WithOAuth2 authenticates every request with a client-credentials access
+// token. The token is cached until shortly before it expires and refreshed
+// once when the server answers 401.
+func (b *ClientBuilder) WithOAuth2(config OAuth2Config) *ClientBuilder {
+	b.oauth2 = &config
+	b.auth = nil
+	return b
+}
+
+// newOAuth2TokenSource creates a token source, using client for token requests unless configured otherwise
+func newOAuth2TokenSource(config OAuth2Config, client *http.Client) *oauth2TokenSource {
+	if config.ExpiryDelta <= 0 {
+		config.ExpiryDelta = 30 * time.Second
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = client
+	}
+	return &oauth2TokenSource{config: config}
+}
+
+// Token returns a cached token or fetches a new one
+func (s *oauth2TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expires) {
+		return s.token, nil
+	}
+
+	token, err := s.fetch(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to obtain OAuth2 token: %w", err)
+	}
+
+	s.token = token.AccessToken
+	s.expires = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - s.config.ExpiryDelta)
+	if token.ExpiresIn <= 0 {
+		// No expiry reported: keep the token until the server rejects it
+		s.expires = time.Now().Add(24 * time.Hour)
+	}
+	return s.token, nil
+}
+
+// Invalidate drops the cached token if it is still the given one
+func (s *oauth2TokenSource) Invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == token {
+		s.token = ""
+	}
+}
+
+// fetch requests a new token from the token endpoint
+func (s *oauth2TokenSource) fetch(ctx context.Context) (*tokenResponse, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+	for k, v := range s.config.EndpointParams {
+		form[k] = v
+	}
+	if s.config.AuthInBody {
+		form.Set("client_id", s.config.ClientID)
+		form.Set("client_secret", s.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if !s.config.AuthInBody {
+		req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+	}
+
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("token endpoint returned HTTP %d: %s", resp.StatusCode, body)
+	}
+
+	token, err := helpers.FromJSON[tokenResponse](body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access_token")
+	}
+	return token, nil
+}
+
+// Apply sets the Authorization header to a current access token
+func (s *oauth2TokenSource) Apply(req *http.Request) error {
+	token, err := s.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Refresh drops the token rejected for req so the next Apply fetches a new one
+func (s *oauth2TokenSource) Refresh(req *http.Request) bool {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	s.Invalidate(token)
+	return true
+}
@@ -0,0 +1,73 @@
+package client_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_OAuth2(t *testing.T) {
+	var issued atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "svc" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.ParseForm()
+		if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("scope") != "orders:read orders:write" {
+			t.Errorf("Unexpected token request form %v", r.PostForm)
+		}
+		n := issued.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer tokenServer.Close()
+
+	var revoked atomic.Value
+	revoked.Store("")
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if auth == "" || auth == "Bearer "+revoked.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(auth))
+	}))
+	defer api.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(api.URL).
+		WithOAuth2(client.OAuth2Config{
+			TokenURL:     tokenServer.URL,
+			ClientID:     "svc",
+			ClientSecret: "s3cret",
+			Scopes:       []string{"orders:read", "orders:write"},
+		}).
+		Build()
+
+	for range 3 {
+		resp, err := restClient.GET("/orders")
+		if err != nil || resp.String() != "Bearer token-1" {
+			t.Fatalf("Expected cached token-1, got %q (%v)", resp.String(), err)
+		}
+	}
+	if issued.Load() != 1 {
+		t.Errorf("Expected a single token request, got %d", issued.Load())
+	}
+
+	// A 401 for a revoked token triggers one refresh
+	revoked.Store("token-1")
+	resp, err := restClient.POST("/orders", map[string]string{"id": "1"})
+	if err != nil || resp.String() != "Bearer token-2" {
+		t.Fatalf("Expected refreshed token-2, got %q (%v)", resp.String(), err)
+	}
+}
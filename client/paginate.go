@@ -0,0 +1,244 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/khekrn/core/response"
+)
+
+// PageStrategy moves a paginated request from one page to the next
+type PageStrategy interface {
+	// First prepares the request for the first page
+	First(config *RequestConfig)
+	// Next prepares config for the page after resp and reports whether there is one
+	Next(resp *Response, config *RequestConfig) (bool, error)
+}
+
+// Paginator iterates over the pages of a GET endpoint. Pages are fetched
+// lazily, one per call to Next, so callers control the pace.
+//
+// Example:
+//
+//	pages := restClient.Paginate("/orders", client.LinkHeaderPages())
+//	for pages.Next() {
+//		var orders []Order
+//		pages.Page().JSON(&orders)
+//	}
+//	if err := pages.Err(); err != nil {
+//		return err
+//	}
+type Paginator struct {
+	client   *RESTClient
+	strategy PageStrategy
+	config   RequestConfig
+	page     *Response
+	err      error
+	started  bool
+	done     bool
+}
+
+// Paginate returns an iterator over the pages of url using strategy
+func (rc *RESTClient) Paginate(url string, strategy PageStrategy, options ...RequestOption) *Paginator {
+	config := RequestConfig{Method: GET, URL: url}
+	for _, opt := range options {
+		opt(&config)
+	}
+	return &Paginator{client: rc, strategy: strategy, config: config}
+}
+
+// Next fetches the next page, returning false when there are no more pages or an error occurred
+func (p *Paginator) Next() bool {
+	if p.done {
+		return false
+	}
+
+	if !p.started {
+		p.started = true
+		p.strategy.First(&p.config)
+	} else {
+		more, err := p.strategy.Next(p.page, &p.config)
+		if err != nil {
+			p.fail(fmt.Errorf("failed to determine next page: %w", err))
+			return false
+		}
+		if !more {
+			p.done = true
+			p.page = nil
+			return false
+		}
+	}
+
+	if p.config.Context != nil && p.config.Context.Err() != nil {
+		p.fail(p.config.Context.Err())
+		return false
+	}
+
+	resp, err := p.client.Request(p.config)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	if !resp.IsSuccess() {
+		target := p.config.URL
+		if u, err := url.Parse(target); err == nil {
+			target = p.client.redactor.url(u)
+		}
+		p.fail(p.client.redactor.httpError(string(p.config.Method), target, resp, 1))
+		return false
+	}
+	p.page = resp
+	return true
+}
+
+// Page returns the current page
+func (p *Paginator) Page() *Response {
+	return p.page
+}
+
+// Err returns the error that stopped iteration, if any
+func (p *Paginator) Err() error {
+	return p.err
+}
+
+// fail stops iteration with err
+func (p *Paginator) fail(err error) {
+	p.err = err
+	p.done = true
+	p.page = nil
+}
+
+// LinkHeaderPages follows RFC 5988 Link headers with rel="next"
+func LinkHeaderPages() PageStrategy {
+	return linkHeaderPages{}
+}
+
+type linkHeaderPages struct{}
+
+func (linkHeaderPages) First(*RequestConfig) {}
+
+func (linkHeaderPages) Next(resp *Response, config *RequestConfig) (bool, error) {
+	next := NextLink(resp.Headers.Values("Link"))
+	if next == "" {
+		return false, nil
+	}
+
+	target, err := url.Parse(next)
+	if err != nil {
+		return false, fmt.Errorf("invalid next link %q: %w", next, err)
+	}
+	if resp.Response != nil && resp.Response.Request != nil {
+		target = resp.Response.Request.URL.ResolveReference(target)
+	}
+
+	// The link carries the complete query for the next page
+	config.URL = target.String()
+	config.QueryParams = nil
+	return true, nil
+}
+
+// NextLink returns the URL with rel="next" from Link header values, or ""
+func NextLink(values []string) string {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range parts[1:] {
+				key, val, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(key, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(val, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// CursorPages passes the cursor returned by extract in the param query
+// parameter, stopping when extract returns an empty cursor
+func CursorPages(param string, extract func(resp *Response) (string, error)) PageStrategy {
+	return cursorPages{param: param, extract: extract}
+}
+
+type cursorPages struct {
+	param   string
+	extract func(resp *Response) (string, error)
+}
+
+func (s cursorPages) First(*RequestConfig) {}
+
+func (s cursorPages) Next(resp *Response, config *RequestConfig) (bool, error) {
+	cursor, err := s.extract(resp)
+	if err != nil || cursor == "" {
+		return false, err
+	}
+	setQueryParam(config, s.param, cursor)
+	return true, nil
+}
+
+// EnvelopeCursorPages follows the next cursor in the meta of page envelopes
+// built with response.NewPageResponse, such as those of the pagination
+// package, passing it in the param query parameter
+func EnvelopeCursorPages(param string) PageStrategy {
+	return CursorPages(param, func(resp *Response) (string, error) {
+		var envelope struct {
+			Data struct {
+				Meta response.Meta `json:"meta"`
+			} `json:"data"`
+		}
+		err := resp.Decode(&envelope)
+		return envelope.Data.Meta.NextCursor, err
+	})
+}
+
+// OffsetPages requests pages of limit items using offset and limit query
+// parameters. count returns the number of items on a page; a short page ends
+// iteration.
+func OffsetPages(offsetParam, limitParam string, limit int, count func(resp *Response) (int, error)) PageStrategy {
+	return offsetPages{offsetParam: offsetParam, limitParam: limitParam, limit: limit, count: count}
+}
+
+type offsetPages struct {
+	offsetParam string
+	limitParam  string
+	limit       int
+	count       func(resp *Response) (int, error)
+}
+
+func (s offsetPages) First(config *RequestConfig) {
+	if _, ok := config.QueryParams[s.offsetParam]; !ok {
+		setQueryParam(config, s.offsetParam, "0")
+	}
+	setQueryParam(config, s.limitParam, strconv.Itoa(s.limit))
+}
+
+func (s offsetPages) Next(resp *Response, config *RequestConfig) (bool, error) {
+	n, err := s.count(resp)
+	if err != nil || n < s.limit {
+		return false, err
+	}
+	offset, err := strconv.Atoi(config.QueryParams[s.offsetParam])
+	if err != nil {
+		return false, fmt.Errorf("invalid offset %q: %w", config.QueryParams[s.offsetParam], err)
+	}
+	setQueryParam(config, s.offsetParam, strconv.Itoa(offset+n))
+	return true, nil
+}
+
+// setQueryParam sets a query parameter on config
+func setQueryParam(config *RequestConfig, key, value string) {
+	if config.QueryParams == nil {
+		config.QueryParams = make(map[string]string)
+	}
+	config.QueryParams[key] = value
+}
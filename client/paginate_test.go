@@ -0,0 +1,114 @@
+package client_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_Paginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/link":
+			page, _ := strconv.Atoi(q.Get("page"))
+			if page == 0 {
+				page = 1
+			}
+			if page < 3 {
+				w.Header().Set("Link", fmt.Sprintf(`</link?page=%d>; rel="next", </link?page=1>; rel="first"`, page+1))
+			}
+			fmt.Fprintf(w, `{"page":%d}`, page)
+		case "/cursor":
+			next := map[string]string{"": "b", "b": "c", "c": ""}[q.Get("cursor")]
+			fmt.Fprintf(w, `{"next":%q}`, next)
+		case "/envelope":
+			next := map[string]string{"": "b", "b": "c", "c": ""}[q.Get("after")]
+			fmt.Fprintf(w, `{"status":"Accepted","data":{"items":[],"meta":{"limit":2,"has_more":%t,"next_cursor":%q}}}`, next != "", next)
+		case "/offset":
+			offset, _ := strconv.Atoi(q.Get("offset"))
+			limit, _ := strconv.Atoi(q.Get("limit"))
+			end := min(offset+limit, len(items))
+			w.Write([]byte(fmt.Sprint(end - offset)))
+		case "/fail":
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutRetry().
+		WithoutCircuitBreaker().
+		Build()
+
+	collect := func(pages *client.Paginator) []string {
+		var bodies []string
+		for pages.Next() {
+			bodies = append(bodies, pages.Page().String())
+		}
+		if err := pages.Err(); err != nil {
+			t.Fatalf("Pagination failed: %v", err)
+		}
+		return bodies
+	}
+
+	t.Run("link header", func(t *testing.T) {
+		got := collect(restClient.Paginate("/link", client.LinkHeaderPages()))
+		if fmt.Sprint(got) != `[{"page":1} {"page":2} {"page":3}]` {
+			t.Errorf("Unexpected pages: %v", got)
+		}
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		got := collect(restClient.Paginate("/cursor", client.CursorPages("cursor", func(resp *client.Response) (string, error) {
+			var body struct{ Next string }
+			err := resp.JSON(&body)
+			return body.Next, err
+		})))
+		if len(got) != 3 {
+			t.Errorf("Expected 3 cursor pages, got %v", got)
+		}
+	})
+
+	t.Run("envelope cursor", func(t *testing.T) {
+		got := collect(restClient.Paginate("/envelope", client.EnvelopeCursorPages("after")))
+		if len(got) != 3 {
+			t.Errorf("Expected 3 envelope pages, got %v", got)
+		}
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		got := collect(restClient.Paginate("/offset", client.OffsetPages("offset", "limit", 3, func(resp *client.Response) (int, error) {
+			return strconv.Atoi(resp.String())
+		})))
+		if fmt.Sprint(got) != "[3 3 1]" {
+			t.Errorf("Unexpected page sizes: %v", got)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		pages := restClient.Paginate("/fail", client.LinkHeaderPages())
+		if pages.Next() {
+			t.Error("Expected no pages")
+		}
+		if pages.Err() == nil {
+			t.Error("Expected error for failed page request")
+		}
+	})
+}
+
+func TestNextLink(t *testing.T) {
+	values := []string{`<https://api.example.com/items?page=1>; rel="prev"`, `<https://api.example.com/items?page=3>; rel="last", <https://api.example.com/items?page=2>; rel=next`}
+	if got := client.NextLink(values); got != "https://api.example.com/items?page=2" {
+		t.Errorf("Unexpected next link: %q", got)
+	}
+	if got := client.NextLink(nil); got != "" {
+		t.Errorf("Expected no next link, got %q", got)
+	}
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/khekrn/core/ratelimit"
+)
+
+// WithRateLimit throttles outbound requests with limiter, keyed by the
+// target host. Each attempt, including retries, waits for the limiter until
+// its context is done. Derived clients share the limiter, so their requests
+// to the same host count against one limit.
+//
+// Example:
+//
+//	// Stay under the partner API's 50 requests per second
+//	restClient := client.NewClientBuilder().
+//		WithBaseURL("https://partner.example.com").
+//		WithRateLimit(ratelimit.NewTokenBucket(ratelimit.PerSecond(50))).
+//		Build()
+func (b *ClientBuilder) WithRateLimit(limiter ratelimit.Limiter) *ClientBuilder {
+	b.rateLimiter = limiter
+	return b
+}
+
+// throttle waits until the rate limiter allows req
+func (rc *RESTClient) throttle(req *http.Request) error {
+	if rc.rateLimiter == nil {
+		return nil
+	}
+	return ratelimit.Wait(req.Context(), rc.rateLimiter, req.URL.Host)
+}
@@ -0,0 +1,44 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+	"github.com/khekrn/core/ratelimit"
+)
+
+func TestRESTClient_WithRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	throttled := 0
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithRateLimit(ratelimit.NewTokenBucket(ratelimit.Limit{Rate: 1, Period: 30 * time.Millisecond},
+			ratelimit.WithOnThrottle(func(key string, result ratelimit.Result) { throttled++ }))).
+		Build()
+
+	start := time.Now()
+	for range 3 {
+		if _, err := restClient.GET("/items"); err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || throttled == 0 {
+		t.Errorf("Expected requests to be throttled, took %v with %d throttled", elapsed, throttled)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err := restClient.Request(client.RequestConfig{Method: client.GET, URL: "/items", Context: ctx, NoRetry: true})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context to end the wait, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/khekrn/core/helpers"
+)
+
+// redacted replaces secret values in logs and dumps
+const redacted = helpers.RedactedValue
+
+// Redaction names the secrets hidden from debug logs, cURL dumps, and HAR
+// recordings, in addition to the defaults: the Authorization, Cookie,
+// Set-Cookie, Proxy-Authorization, and X-Api-Key headers, and fields named in
+// helpers.DefaultRedactedFields in JSON bodies, form bodies, and query strings.
+type Redaction struct {
+	Headers []string // Header names, case-insensitive
+	Fields  []string // Body and query field names, case-insensitive
+}
+
+// WithRedaction adds headers and fields to hide from debug output
+func (b *ClientBuilder) WithRedaction(redaction Redaction) *ClientBuilder {
+	b.redaction.Headers = append(b.redaction.Headers, redaction.Headers...)
+	b.redaction.Fields = append(b.redaction.Fields, redaction.Fields...)
+	return b
+}
+
+// redactor hides secrets in headers, URLs, and bodies
+type redactor struct {
+	headers map[string]bool
+	fields  map[string]bool
+}
+
+// newRedactor builds a redactor from the defaults and redaction
+func newRedactor(redaction Redaction) *redactor {
+	r := &redactor{headers: make(map[string]bool), fields: make(map[string]bool)}
+	for _, header := range append([]string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization", "X-Api-Key"}, redaction.Headers...) {
+		r.headers[http.CanonicalHeaderKey(header)] = true
+	}
+	for _, field := range slices.Concat(helpers.DefaultRedactedFields, redaction.Fields) {
+		r.fields[strings.ToLower(field)] = true
+	}
+	return r
+}
+
+// header returns a copy of header with secret values replaced
+func (r *redactor) header(header http.Header) http.Header {
+	clean := header.Clone()
+	for name := range clean {
+		if r.headers[http.CanonicalHeaderKey(name)] {
+			clean[name] = []string{redacted}
+		}
+	}
+	return clean
+}
+
+// url returns u as a string with secret query parameters replaced
+func (r *redactor) url(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	clean := *u
+	clean.RawQuery = r.values(u.Query()).Encode()
+	return clean.String()
+}
+
+// values returns a copy of values with secret fields replaced
+func (r *redactor) values(values url.Values) url.Values {
+	clean := make(url.Values, len(values))
+	for name, v := range values {
+		if r.fields[strings.ToLower(name)] {
+			v = []string{redacted}
+		}
+		clean[name] = v
+	}
+	return clean
+}
+
+// body returns body with secret fields replaced, for JSON and form bodies
+func (r *redactor) body(contentType string, body []byte) []byte {
+	switch {
+	case strings.Contains(contentType, "json"):
+		var v interface{}
+		if err := helpers.UnmarshalJSON(body, &v); err != nil {
+			return body
+		}
+		clean, err := helpers.ToJSON(r.json(v))
+		if err != nil {
+			return body
+		}
+		return clean
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return body
+		}
+		return []byte(r.values(values).Encode())
+	}
+	return body
+}
+
+// json replaces secret fields in a decoded JSON value
+func (r *redactor) json(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if r.fields[strings.ToLower(key)] {
+				v[key] = redacted
+			} else {
+				v[key] = r.json(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = r.json(value)
+		}
+	}
+	return v
+}
+
+// requestBody returns a redacted copy of the request body without consuming
+// it, and false when the body cannot be reread
+func (r *redactor) requestBody(req *http.Request) ([]byte, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	defer body.Close()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(body); err != nil {
+		return nil, false
+	}
+	return r.body(req.Header.Get("Content-Type"), buf.Bytes()), true
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrClientNotFound is returned when no client is registered under a name
+var ErrClientNotFound = errors.New("client: not registered")
+
+// Profile configures a named client in a Registry
+type Profile struct {
+	BaseURL  string            // Base URL used when the environment has no entry in BaseURLs
+	BaseURLs map[string]string // Base URL per environment, e.g. "staging", "production"
+	Builder  *ClientBuilder    // Client settings; defaults to NewClientBuilder()
+}
+
+// ProfileConfig is the declarative form of a Profile, e.g. loaded from JSON or YAML
+type ProfileConfig struct {
+	BaseURL     string            `json:"base_url" yaml:"base_url"`
+	BaseURLs    map[string]string `json:"base_urls" yaml:"base_urls"`
+	Timeout     string            `json:"timeout" yaml:"timeout"` // Go duration, e.g. "10s"
+	Headers     map[string]string `json:"headers" yaml:"headers"`
+	MaxAttempts int               `json:"max_attempts" yaml:"max_attempts"` // 1 disables retries
+}
+
+// Registry holds named clients configured once and shared across a service.
+// Clients are built on first use for the registry's environment.
+type Registry struct {
+	mu          sync.Mutex
+	environment string
+	profiles    map[string]Profile
+	clients     map[string]*RESTClient
+}
+
+// DefaultRegistry is the registry used by the package-level Register and Get
+var DefaultRegistry = NewRegistry("")
+
+// NewRegistry creates an empty registry for environment
+func NewRegistry(environment string) *Registry {
+	return &Registry{
+		environment: environment,
+		profiles:    make(map[string]Profile),
+		clients:     make(map[string]*RESTClient),
+	}
+}
+
+// NewRegistryFromConfig creates a registry from declarative profiles
+func NewRegistryFromConfig(environment string, profiles map[string]ProfileConfig) (*Registry, error) {
+	registry := NewRegistry(environment)
+	for name, config := range profiles {
+		profile, err := config.Profile()
+		if err != nil {
+			return nil, fmt.Errorf("invalid client %q: %w", name, err)
+		}
+		if err := registry.Register(name, profile); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Profile converts the configuration into a Profile
+func (c ProfileConfig) Profile() (Profile, error) {
+	builder := NewClientBuilder()
+	if c.Timeout != "" {
+		timeout, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return Profile{}, fmt.Errorf("invalid timeout: %w", err)
+		}
+		builder.WithTimeout(timeout)
+	}
+	for k, v := range c.Headers {
+		builder.WithDefaultHeader(k, v)
+	}
+	switch {
+	case c.MaxAttempts == 1:
+		builder.WithoutRetry()
+	case c.MaxAttempts > 1:
+		builder.retry.MaxAttempts = c.MaxAttempts
+	}
+	return Profile{BaseURL: c.BaseURL, BaseURLs: c.BaseURLs, Builder: builder}, nil
+}
+
+// Register adds a named client profile
+func (r *Registry) Register(name string, profile Profile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.profiles[name]; exists {
+		return fmt.Errorf("client %q already registered", name)
+	}
+	r.profiles[name] = profile
+	return nil
+}
+
+// Get returns the named client, building it on first use
+func (r *Registry) Get(name string) (*RESTClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if client, ok := r.clients[name]; ok {
+		return client, nil
+	}
+	profile, ok := r.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrClientNotFound, name)
+	}
+
+	builder := profile.Builder
+	if builder == nil {
+		builder = NewClientBuilder()
+	}
+	baseURL := profile.BaseURL
+	if envURL, ok := profile.BaseURLs[r.environment]; ok {
+		baseURL = envURL
+	}
+	if baseURL != "" {
+		builder.WithBaseURL(baseURL)
+	}
+	if builder.circuitBreaker != nil && (builder.circuitBreaker.Name == "" || builder.circuitBreaker.Name == "default-client") {
+		// Name breakers after the client so state and metrics are distinguishable
+		breaker := *builder.circuitBreaker
+		breaker.Name = name
+		builder.circuitBreaker = &breaker
+	}
+
+	client := builder.Build()
+	r.clients[name] = client
+	return client, nil
+}
+
+// MustGet returns the named client and panics if it is not registered
+func (r *Registry) MustGet(name string) *RESTClient {
+	client, err := r.Get(name)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// Names returns the registered client names in sorted order
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.profiles))
+	for name := range r.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Environment returns the environment used to select base URLs
+func (r *Registry) Environment() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.environment
+}
+
+// SetEnvironment changes the environment. Clients already built are
+// discarded and rebuilt on next use.
+func (r *Registry) SetEnvironment(environment string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.environment = environment
+	r.clients = make(map[string]*RESTClient)
+}
+
+// Register adds a named client profile to DefaultRegistry
+func Register(name string, profile Profile) error {
+	return DefaultRegistry.Register(name, profile)
+}
+
+// Get returns the named client from DefaultRegistry
+func Get(name string) (*RESTClient, error) {
+	return DefaultRegistry.Get(name)
+}
+
+// MustGet returns the named client from DefaultRegistry and panics if it is not registered
+func MustGet(name string) *RESTClient {
+	return DefaultRegistry.MustGet(name)
+}
@@ -0,0 +1,81 @@
+package client_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/khekrn/core/client"
+)
+
+// hostEchoTransport answers every request with its host
+func hostEchoTransport() http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(req.URL.Host)), Header: http.Header{}}, nil
+	})
+}
+
+func TestRegistry_EnvironmentBaseURL(t *testing.T) {
+	registry := client.NewRegistry("staging")
+	err := registry.Register("orders", client.Profile{
+		BaseURL:  "http://orders.local",
+		BaseURLs: map[string]string{"staging": "http://orders.staging"},
+		Builder:  client.NewClientBuilder().WithTransport(hostEchoTransport()),
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	orders := registry.MustGet("orders")
+	resp, err := orders.GET("/orders")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	if got := resp.String(); got != "orders.staging" {
+		t.Errorf("Expected staging host, got %q", got)
+	}
+	if again := registry.MustGet("orders"); again != orders {
+		t.Error("Expected Get to return the same client")
+	}
+
+	registry.SetEnvironment("dev")
+	resp, err = registry.MustGet("orders").GET("/orders")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	if got := resp.String(); got != "orders.local" {
+		t.Errorf("Expected fallback host, got %q", got)
+	}
+}
+
+func TestRegistry_Errors(t *testing.T) {
+	registry := client.NewRegistry("")
+	if err := registry.Register("payments", client.Profile{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := registry.Register("payments", client.Profile{}); err == nil {
+		t.Error("Expected duplicate registration to fail")
+	}
+	if _, err := registry.Get("missing"); !errors.Is(err, client.ErrClientNotFound) {
+		t.Errorf("Expected ErrClientNotFound, got %v", err)
+	}
+}
+
+func TestNewRegistryFromConfig(t *testing.T) {
+	registry, err := client.NewRegistryFromConfig("production", map[string]client.ProfileConfig{
+		"orders":   {BaseURLs: map[string]string{"production": "https://orders.example.com"}, Timeout: "5s", MaxAttempts: 1},
+		"payments": {BaseURL: "https://payments.example.com", Headers: map[string]string{"X-Team": "billing"}},
+	})
+	if err != nil {
+		t.Fatalf("NewRegistryFromConfig failed: %v", err)
+	}
+	if names := registry.Names(); len(names) != 2 || names[0] != "orders" || names[1] != "payments" {
+		t.Errorf("Unexpected names %v", names)
+	}
+
+	if _, err := client.NewRegistryFromConfig("", map[string]client.ProfileConfig{"bad": {Timeout: "soon"}}); err == nil {
+		t.Error("Expected invalid timeout to fail")
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/khekrn/core/idgen"
+	"github.com/khekrn/core/logger"
+)
+
+// DefaultRequestIDHeader is the header carrying the request ID
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// WithRequestIDHeader sets the header used to propagate the request ID from
+// the context (see logger.WithRequestID)
+func (b *ClientBuilder) WithRequestIDHeader(header string) *ClientBuilder {
+	b.requestIDHeader = header
+	return b
+}
+
+// WithRequestIDGeneration generates a request ID for requests whose context
+// carries none, so every outgoing call can be correlated
+func (b *ClientBuilder) WithRequestIDGeneration() *ClientBuilder {
+	b.generateRequestID = true
+	return b
+}
+
+// setRequestID propagates the context request ID, or a generated one, unless
+// the caller already set the header. Retries reuse the same ID.
+func (rc *RESTClient) setRequestID(req *http.Request) {
+	header := rc.requestIDHeader
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	if req.Header.Get(header) != "" {
+		return
+	}
+
+	requestID := RequestIDFromContext(req.Context())
+	if requestID == "" && rc.generateRequestID {
+		requestID = newRequestID()
+	}
+	if requestID != "" {
+		req.Header.Set(header, requestID)
+	}
+}
+
+// RequestIDFromContext returns the request ID stored with logger.WithRequestID
+func RequestIDFromContext(ctx context.Context) string {
+	return logger.RequestIDFromContext(ctx)
+}
+
+// newRequestID generates a request ID
+func newRequestID() string {
+	return idgen.RequestID()
+}
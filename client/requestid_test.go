@@ -0,0 +1,47 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_RequestIDPropagation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("X-Request-ID") + "|" + r.Header.Get("X-Correlation-ID")))
+	}))
+	defer server.Close()
+
+	ctx := context.WithValue(context.Background(), "RequestID", "req-123")
+
+	restClient := client.NewClientBuilder().WithBaseURL(server.URL).Build()
+	resp, err := restClient.GET("/", client.WithContext(ctx))
+	if err != nil || resp.String() != "req-123|" {
+		t.Errorf("Expected request ID from context, got %q (%v)", resp.String(), err)
+	}
+	resp, _ = restClient.GET("/")
+	if resp.String() != "|" {
+		t.Errorf("Expected no request ID without context value or generation, got %q", resp.String())
+	}
+	resp, _ = restClient.GET("/", client.WithContext(ctx), client.WithHeader("X-Request-ID", "explicit"))
+	if resp.String() != "explicit|" {
+		t.Errorf("Expected explicit header to win, got %q", resp.String())
+	}
+
+	custom := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithRequestIDHeader("X-Correlation-ID").
+		WithRequestIDGeneration().
+		Build()
+	resp, _ = custom.GET("/", client.WithContext(ctx))
+	if resp.String() != "|req-123" {
+		t.Errorf("Expected custom header, got %q", resp.String())
+	}
+	resp, _ = custom.GET("/")
+	if got := resp.String(); len(got) != 37 {
+		t.Errorf("Expected generated UUID request ID, got %q", got)
+	}
+}
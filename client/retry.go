@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/khekrn/core/helpers"
+	"github.com/khekrn/core/idempotency"
+)
+
+// ErrMaxRetriesExceeded is returned when every attempt allowed by the retry
+// configuration failed. It wraps the error of the last attempt.
+var ErrMaxRetriesExceeded = helpers.ErrMaxRetriesExceeded
+
+// DefaultShouldRetry reports whether an attempt failed transiently: a 408,
+// 429, or 5xx response, or a transport error such as a connection reset,
+// refused connection, unexpected EOF, timeout, or temporary DNS failure.
+// Cancellation, an open circuit, and certificate errors are not retried.
+func DefaultShouldRetry(resp *Response, err error, attempt int) bool {
+	if err == nil {
+		return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout
+	}
+	return isTransientError(err)
+}
+
+// isTransientError classifies transport errors worth retrying
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || IsCircuitOpen(err) {
+		return false
+	}
+
+	var certErr *x509.CertificateInvalidError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &certErr) || errors.As(err, &unknownAuthority) || errors.As(err, &hostnameErr) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// isIdempotent reports whether req may safely be sent more than once
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(idempotency.HeaderKey) != ""
+}
+
+// shouldRetry classifies an attempt using the configured or default classifier
+func (c *RetryConfig) shouldRetry(req *http.Request, resp *Response, err error, attempt int) bool {
+	if c.ShouldRetry != nil {
+		return c.ShouldRetry(resp, err, attempt)
+	}
+	if !c.RetryUnsafeMethods && !isIdempotent(req) {
+		return false
+	}
+	return DefaultShouldRetry(resp, err, attempt)
+}
@@ -0,0 +1,308 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/coretest"
+)
+
+func TestRetryConfig_BackoffJitter(t *testing.T) {
+	base := RetryConfig{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		BackoffFactor:  2.0,
+	}
+
+	t.Run("none is exponential and capped", func(t *testing.T) {
+		expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+		for i, want := range expected {
+			if got := base.backoff(i+1, 0); got != want {
+				t.Errorf("Attempt %d: expected %v, got %v", i+1, want, got)
+			}
+		}
+	})
+
+	bounds := map[JitterStrategy]func(attempt int, previous time.Duration) (time.Duration, time.Duration){
+		JitterFull: func(attempt int, _ time.Duration) (time.Duration, time.Duration) {
+			return 0, min(base.InitialBackoff<<(attempt-1), base.MaxBackoff)
+		},
+		JitterEqual: func(attempt int, _ time.Duration) (time.Duration, time.Duration) {
+			backoff := min(base.InitialBackoff<<(attempt-1), base.MaxBackoff)
+			return backoff / 2, backoff
+		},
+		JitterDecorrelated: func(_ int, previous time.Duration) (time.Duration, time.Duration) {
+			return base.InitialBackoff, min(max(previous*3, base.InitialBackoff), base.MaxBackoff)
+		},
+	}
+
+	for strategy, bound := range bounds {
+		t.Run(string(strategy), func(t *testing.T) {
+			config := base
+			config.Jitter = strategy
+
+			distinct := make(map[time.Duration]bool)
+			for range 50 {
+				var previous time.Duration
+				for attempt := 1; attempt <= config.MaxAttempts; attempt++ {
+					delay := config.backoff(attempt, previous)
+					low, high := bound(attempt, previous)
+					if delay < low || delay > high {
+						t.Fatalf("Attempt %d: delay %v outside [%v, %v]", attempt, delay, low, high)
+					}
+					distinct[delay] = true
+					previous = delay
+				}
+			}
+			if len(distinct) < 10 {
+				t.Errorf("Expected randomized delays, got %d distinct values", len(distinct))
+			}
+		})
+	}
+}
+
+func TestRequestRetryOverride(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	rc := NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutCircuitBreaker().
+		WithRetry(RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}).
+		Build()
+
+	tests := []struct {
+		name    string
+		options []RequestOption
+		calls   int32
+	}{
+		{"client default", nil, 3},
+		{"no retry", []RequestOption{WithNoRetry()}, 1},
+		{"override", []RequestOption{WithRetry(RetryConfig{MaxAttempts: 5, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1})}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			options := append(tt.options, WithIdempotencyKey("payment-1"))
+			rc.POST("/payments", map[string]int{"amount": 100}, options...)
+			if got := calls.Load(); got != tt.calls {
+				t.Errorf("Expected %d attempts, got %d", tt.calls, got)
+			}
+		})
+	}
+}
+
+func TestRetryExhaustedHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"code":"maintenance"}`))
+	}))
+	defer server.Close()
+
+	rc := NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutCircuitBreaker().
+		WithRetry(RetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}).
+		Build()
+
+	_, err := rc.GET("/status")
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("Expected *HTTPError, got %T: %v", err, err)
+	}
+	if httpErr.StatusCode != http.StatusServiceUnavailable || httpErr.Method != "GET" || httpErr.Attempts != 2 {
+		t.Errorf("Unexpected HTTPError fields: %+v", httpErr)
+	}
+	if string(httpErr.Body) != `{"code":"maintenance"}` || httpErr.Headers.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected response body and headers on HTTPError, got %q", httpErr.Body)
+	}
+	if httpErr.URL != server.URL+"/status" {
+		t.Errorf("Expected URL %s, got %s", server.URL+"/status", httpErr.URL)
+	}
+}
+
+func TestRetryWithClock(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	clk := coretest.NewClock(time.Time{})
+	rc := NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutCircuitBreaker().
+		WithRetry(RetryConfig{MaxAttempts: 3, InitialBackoff: time.Hour, MaxBackoff: 2 * time.Hour, BackoffFactor: 2}).
+		WithClock(clk).
+		Build()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := rc.GET("/status")
+		done <- err
+	}()
+
+	// Hour-long backoffs pass as soon as the clock is advanced
+	clk.BlockUntil(1)
+	clk.Advance(time.Hour)
+	clk.BlockUntil(1)
+	clk.Advance(2 * time.Hour)
+	select {
+	case err := <-done:
+		if err != nil || attempts.Load() != 3 {
+			t.Errorf("Expected success on the third attempt, got %v after %d", err, attempts.Load())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the retries to follow the fake clock")
+	}
+}
+
+func TestRetryClassification(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/reset" {
+			// Drop the connection without a response
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	fast := RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}
+	rc := NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutCircuitBreaker().
+		WithRetry(fast).
+		Build()
+
+	unsafe := fast
+	unsafe.RetryUnsafeMethods = true
+	custom := fast
+	custom.ShouldRetry = func(resp *Response, err error, attempt int) bool {
+		return err == nil && resp.StatusCode == http.StatusBadGateway && attempt < 2
+	}
+
+	tests := []struct {
+		name  string
+		call  func() (*Response, error)
+		calls int32
+	}{
+		{"transport error on GET", func() (*Response, error) { return rc.GET("/reset") }, 3},
+		{"POST not retried", func() (*Response, error) { return rc.POST("/orders", nil) }, 1},
+		{"POST with idempotency key", func() (*Response, error) { return rc.POST("/orders", nil, WithIdempotencyKey("k1")) }, 3},
+		{"POST with unsafe retries", func() (*Response, error) { return rc.POST("/orders", nil, WithRetry(unsafe)) }, 3},
+		{"custom classifier", func() (*Response, error) { return rc.POST("/orders", nil, WithRetry(custom)) }, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			tt.call()
+			if got := calls.Load(); got != tt.calls {
+				t.Errorf("Expected %d attempts, got %d", tt.calls, got)
+			}
+		})
+	}
+
+	// A non-retried POST returns the response rather than an error
+	resp, err := rc.POST("/orders", nil)
+	if err != nil || resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected 502 response for non-retried POST, got %v", err)
+	}
+}
+
+func TestDefaultShouldRetry(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *Response
+		err  error
+		want bool
+	}{
+		{"503", &Response{StatusCode: 503}, nil, true},
+		{"429", &Response{StatusCode: 429}, nil, true},
+		{"404", &Response{StatusCode: 404}, nil, false},
+		{"connection reset", nil, &net.OpError{Op: "read", Err: syscall.ECONNRESET}, true},
+		{"unexpected EOF", nil, io.ErrUnexpectedEOF, true},
+		{"canceled", nil, context.Canceled, false},
+		{"dns not found", nil, &net.DNSError{Err: "no such host", IsNotFound: true}, false},
+		{"dns timeout", nil, &net.DNSError{Err: "timeout", IsTimeout: true}, true},
+		{"other", nil, errors.New("failed to sign request"), false},
+	}
+	for _, tt := range tests {
+		if got := DefaultShouldRetry(tt.resp, tt.err, 1); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			select {
+			case <-time.After(100 * time.Millisecond):
+			case <-r.Context().Done():
+			}
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	rc := NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithTimeout(20 * time.Millisecond).
+		WithoutCircuitBreaker().
+		WithRetry(RetryConfig{MaxAttempts: 10, InitialBackoff: 50 * time.Millisecond, MaxBackoff: 50 * time.Millisecond, BackoffFactor: 1}).
+		Build()
+
+	// A longer per-request timeout replaces the client timeout
+	if _, err := rc.GET("/slow", WithNoRetry()); err == nil {
+		t.Error("Expected client timeout for slow endpoint")
+	}
+	if _, err := rc.GET("/slow", WithTimeout(time.Second)); err != nil {
+		t.Errorf("Expected per-request timeout to allow slow endpoint, got %v", err)
+	}
+
+	// The deadline spans all retry attempts
+	start := time.Now()
+	_, err := rc.GET("/down", WithTimeout(120*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("Expected retries to stop at the deadline, took %v", elapsed)
+	}
+
+	// Streamed bodies stay readable until closed
+	resp, err := rc.GET("/slow", WithTimeout(time.Second), WithStreamResponse())
+	if err != nil {
+		t.Fatalf("Streamed request failed: %v", err)
+	}
+	if _, err := io.ReadAll(resp.Reader()); err != nil {
+		t.Errorf("Expected streamed body to be readable, got %v", err)
+	}
+	resp.Close()
+}
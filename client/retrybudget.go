@@ -0,0 +1,100 @@
+package client
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRetryBudgetExhausted is returned when a retry is denied because the
+// client's retry budget is spent. It wraps the error of the last attempt.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// RetryBudgetConfig limits retries to a share of recent request volume so
+// that an outage does not multiply the load on the failing upstream
+type RetryBudgetConfig struct {
+	Ratio        float64       // Retries allowed per request in the window, e.g. 0.2 for 20%
+	MinPerSecond int           // Retries always allowed per second, so low traffic can still retry
+	Window       time.Duration // Period over which requests and retries are counted; defaults to 10s
+}
+
+// WithRetryBudget caps retries across all requests made by the client.
+// A denied retry fails the request with ErrRetryBudgetExhausted and calls
+// the OnRetryBudgetExhausted hook.
+func (b *ClientBuilder) WithRetryBudget(config RetryBudgetConfig) *ClientBuilder {
+	b.retryBudget = &config
+	return b
+}
+
+// retryBudgetBuckets is the number of buckets the window is divided into
+const retryBudgetBuckets = 10
+
+// retryBudget counts requests and retries over a sliding window
+type retryBudget struct {
+	config RetryBudgetConfig
+	width  time.Duration // Duration covered by one bucket
+
+	mu      sync.Mutex
+	buckets [retryBudgetBuckets]retryBudgetBucket
+}
+
+// retryBudgetBucket holds the counts for one slice of the window
+type retryBudgetBucket struct {
+	start    time.Time
+	requests int
+	retries  int
+}
+
+// newRetryBudget creates a budget from config, applying defaults
+func newRetryBudget(config RetryBudgetConfig) *retryBudget {
+	if config.Window <= 0 {
+		config.Window = 10 * time.Second
+	}
+	return &retryBudget{config: config, width: max(config.Window/retryBudgetBuckets, time.Millisecond)}
+}
+
+// request records a new request
+func (b *retryBudget) request() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bucket(time.Now()).requests++
+}
+
+// withdraw records a retry and reports whether the budget allows it
+func (b *retryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	current := b.bucket(now)
+	requests, retries := 0, 0
+	for _, bucket := range b.buckets {
+		if now.Sub(bucket.start) < b.config.Window {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+
+	allowed := b.config.Ratio*float64(requests) + float64(b.config.MinPerSecond)*b.config.Window.Seconds()
+	if float64(retries+1) > allowed {
+		return false
+	}
+	current.retries++
+	return true
+}
+
+// bucket returns the bucket for now, resetting it if it belongs to an earlier window
+func (b *retryBudget) bucket(now time.Time) *retryBudgetBucket {
+	start := now.Truncate(b.width)
+	bucket := &b.buckets[(start.UnixNano()/int64(b.width))%retryBudgetBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = retryBudgetBucket{start: start}
+	}
+	return bucket
+}
@@ -0,0 +1,75 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_RetryBudget(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var exhausted int
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutCircuitBreaker().
+		WithRetry(client.RetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}).
+		WithRetryBudget(client.RetryBudgetConfig{Ratio: 0.2, Window: time.Minute}).
+		WithHooks(client.Hooks{
+			OnRetryBudgetExhausted: func(info client.RequestInfo, err error) { exhausted++ },
+		}).
+		Build()
+
+	denied := 0
+	for i := 0; i < 10; i++ {
+		_, err := restClient.GET("/down")
+		if errors.Is(err, client.ErrRetryBudgetExhausted) {
+			denied++
+			var httpErr *client.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("Expected budget error to wrap the last HTTPError, got %v", err)
+			}
+		}
+	}
+
+	// 20% of 10 requests allows 2 retries
+	if got := calls.Load(); got != 12 {
+		t.Errorf("Expected 12 upstream calls, got %d", got)
+	}
+	if denied != 8 || exhausted != 8 {
+		t.Errorf("Expected 8 denied retries, got %d errors and %d hook calls", denied, exhausted)
+	}
+}
+
+func TestRESTClient_RetryBudgetMinPerSecond(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutCircuitBreaker().
+		WithRetry(client.RetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}).
+		WithRetryBudget(client.RetryBudgetConfig{MinPerSecond: 1, Window: time.Second}).
+		Build()
+
+	if _, err := restClient.GET("/flaky"); err != nil {
+		t.Errorf("Expected the minimum budget to allow a retry, got %v", err)
+	}
+}
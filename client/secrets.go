@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/khekrn/core/secrets"
+)
+
+// secretRefresher is implemented by secret providers that can re-read a
+// secret, such as *secrets.Cache
+type secretRefresher interface {
+	Refresh(ctx context.Context, name string) (bool, error)
+}
+
+// secretAuth applies credentials read from a secret provider on every attempt
+type secretAuth struct {
+	provider secrets.Provider
+	names    []string
+	apply    func(req *http.Request, values []string)
+}
+
+// Apply reads the secrets and attaches them to req
+func (a *secretAuth) Apply(req *http.Request) error {
+	values := make([]string, len(a.names))
+	for i, name := range a.names {
+		value, err := a.provider.Get(req.Context(), name)
+		if err != nil {
+			return fmt.Errorf("failed to read credential %s: %w", name, err)
+		}
+		values[i] = value
+	}
+	a.apply(req, values)
+	return nil
+}
+
+// Refresh re-reads the secrets after a 401 and reports whether any changed,
+// in which case the request is retried once
+func (a *secretAuth) Refresh(req *http.Request) bool {
+	refresher, ok := a.provider.(secretRefresher)
+	if !ok {
+		return false
+	}
+	changed := false
+	for _, name := range a.names {
+		if rotated, err := refresher.Refresh(req.Context(), name); err == nil && rotated {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// BearerTokenFromSecret authenticates with a bearer token read from provider.
+// With a *secrets.Cache, a 401 re-reads the token and retries once if it was
+// rotated.
+//
+// Example:
+//
+//	store := secrets.NewCache(secrets.NewVaultProvider(vaultAddr, vaultToken))
+//	client.NewClientBuilder().WithAuth(client.BearerTokenFromSecret(store, "payments/api#token"))
+func BearerTokenFromSecret(provider secrets.Provider, name string) AuthProvider {
+	return &secretAuth{provider: provider, names: []string{name}, apply: func(req *http.Request, values []string) {
+		req.Header.Set("Authorization", "Bearer "+values[0])
+	}}
+}
+
+// BasicAuthFromSecrets authenticates with HTTP Basic credentials read from provider
+func BasicAuthFromSecrets(provider secrets.Provider, usernameName, passwordName string) AuthProvider {
+	return &secretAuth{provider: provider, names: []string{usernameName, passwordName}, apply: func(req *http.Request, values []string) {
+		req.SetBasicAuth(values[0], values[1])
+	}}
+}
+
+// APIKeyHeaderFromSecret sends an API key read from provider in the named header
+func APIKeyHeaderFromSecret(provider secrets.Provider, header, name string) AuthProvider {
+	return &secretAuth{provider: provider, names: []string{name}, apply: func(req *http.Request, values []string) {
+		req.Header.Set(header, values[0])
+	}}
+}
+
+// WithClientCertificateFromSecrets presents the certificate and key (PEM)
+// read from provider for mutual TLS. They are read on every handshake, so
+// with a *secrets.Cache rotated certificates are picked up once the cached
+// values refresh. Handshakes fail if the secrets cannot be read or parsed.
+func (b *ClientBuilder) WithClientCertificateFromSecrets(provider secrets.Provider, certName, keyName string) *ClientBuilder {
+	var mu sync.Mutex
+	var lastCert, lastKey string
+	var parsed *tls.Certificate
+
+	b.ensureTLSConfig().GetClientCertificate = func(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		ctx := info.Context()
+		certPEM, err := provider.Get(ctx, certName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client certificate: %w", err)
+		}
+		keyPEM, err := provider.Get(ctx, keyName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client certificate key: %w", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if parsed != nil && certPEM == lastCert && keyPEM == lastKey {
+			return parsed, nil
+		}
+		cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		lastCert, lastKey, parsed = certPEM, keyPEM, &cert
+		return parsed, nil
+	}
+	return b
+}
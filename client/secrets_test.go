@@ -0,0 +1,138 @@
+package client_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/khekrn/core/client"
+	"github.com/khekrn/core/secrets"
+)
+
+// rotatingSecrets is a secret provider whose values can change between reads
+type rotatingSecrets struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (s *rotatingSecrets) Get(ctx context.Context, name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[name]
+	if !ok {
+		return "", secrets.ErrNotFound
+	}
+	return value, nil
+}
+
+func (s *rotatingSecrets) set(name, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[name] = value
+}
+
+func TestRESTClient_AuthFromSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer old" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("X-Authorization", r.Header.Get("Authorization"))
+		w.Header().Set("X-Api-Key-Header", r.Header.Get("X-API-Key"))
+	}))
+	defer server.Close()
+
+	source := &rotatingSecrets{values: map[string]string{"token": "abc", "user": "user", "pass": "pass", "key": "k1"}}
+	tests := []struct {
+		name     string
+		provider client.AuthProvider
+		header   string
+		want     string
+	}{
+		{"bearer", client.BearerTokenFromSecret(source, "token"), "X-Authorization", "Bearer abc"},
+		{"basic", client.BasicAuthFromSecrets(source, "user", "pass"), "X-Authorization", "Basic dXNlcjpwYXNz"},
+		{"api key header", client.APIKeyHeaderFromSecret(source, "X-API-Key", "key"), "X-Api-Key-Header", "k1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restClient := client.NewClientBuilder().WithBaseURL(server.URL).WithAuth(tt.provider).Build()
+			resp, err := restClient.GET("/resource")
+			if err != nil {
+				t.Fatalf("GET failed: %v", err)
+			}
+			if got := resp.Headers.Get(tt.header); got != tt.want {
+				t.Errorf("Expected %s %q, got %q", tt.header, tt.want, got)
+			}
+		})
+	}
+
+	// A rejected token is re-read from the cache and the request retried
+	source.set("token", "old")
+	store := secrets.NewCache(source)
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithAuth(client.BearerTokenFromSecret(store, "token")).
+		Build()
+	if resp, _ := restClient.GET("/resource"); resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatal("Expected the old token to be rejected")
+	}
+	source.set("token", "new")
+	resp, err := restClient.GET("/resource")
+	if err != nil || resp.Headers.Get("X-Authorization") != "Bearer new" {
+		t.Errorf("Expected the rotated token after a 401, got %v", err)
+	}
+
+	missing := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithAuth(client.BearerTokenFromSecret(source, "missing")).
+		Build()
+	if _, err := missing.GET("/resource"); err == nil {
+		t.Error("Expected an error for a missing secret")
+	}
+}
+
+func TestRESTClient_ClientCertificateFromSecrets(t *testing.T) {
+	certFile, keyFile, clientCert := writeClientCertificate(t)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello " + r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	mounted := secrets.NewFileProvider(filepath.Dir(certFile))
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithRootCAs(rootCAs).
+		WithClientCertificateFromSecrets(mounted, filepath.Base(certFile), filepath.Base(keyFile)).
+		Build()
+
+	resp, err := restClient.GET("/")
+	if err != nil {
+		t.Fatalf("GET over mTLS failed: %v", err)
+	}
+	if resp.String() != "hello test-client" {
+		t.Errorf("Expected client identity in response, got %q", resp.String())
+	}
+
+	missing := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutRetry().
+		WithRootCAs(rootCAs).
+		WithClientCertificateFromSecrets(mounted, "missing.pem", filepath.Base(keyFile)).
+		Build()
+	if _, err := missing.GET("/"); err == nil {
+		t.Error("Expected the handshake to fail without the certificate")
+	}
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/secrets/crypto"
+)
+
+func TestAWSSigV4Signer_GetVanilla(t *testing.T) {
+	// AWS Signature Version 4 test suite: get-vanilla
+	signer := NewAWSSigV4Signer("AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "service")
+	signer.Now = func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) }
+
+	req := httptest.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	req.Header = http.Header{}
+	if err := signer.Sign(req, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, " +
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != expected {
+		t.Errorf("Unexpected Authorization header\nwant: %s\ngot:  %s", expected, got)
+	}
+}
+
+func TestHMACSigner(t *testing.T) {
+	secret := []byte("shared-secret")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(body) == 0 || sha256Hex(body) != r.Header.Get("X-Content-SHA256") {
+			t.Error("Body hash does not match the body sent")
+		}
+
+		signature, _ := strings.CutPrefix(r.Header.Get("X-Signature"), "keyId=k1,signature=")
+		message := HMACStringToSign(r.Method, r.URL.RequestURI(), r.Header.Get("X-Timestamp"), r.Header.Get("X-Content-SHA256"))
+		if !crypto.VerifyHex(secret, []byte(message), signature) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	rc := NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithSigner(NewHMACSigner("k1", secret)).
+		Build()
+
+	resp, err := rc.POST("/orders", map[string]string{"id": "1"}, WithQueryParam("dry_run", "true"))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected signature to verify, got status %d", resp.StatusCode)
+	}
+}
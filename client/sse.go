@@ -0,0 +1,212 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSEEvent is a server-sent event received by an EventStream
+type SSEEvent struct {
+	ID    string        // Last event ID seen on the stream, including this event
+	Event string        // Event type, "message" when not set by the server
+	Data  string        // Data fields joined with newlines
+	Retry time.Duration // Reconnection delay requested by the server, if any
+}
+
+// EventStream delivers events from a server-sent events endpoint,
+// reconnecting with Last-Event-ID when the connection drops
+type EventStream struct {
+	events chan SSEEvent
+	err    error
+}
+
+// SSE subscribes to the text/event-stream at url. Events are delivered on
+// Events until the context is cancelled, the server answers 204, or
+// reconnecting fails; Err then reports why. Dropped connections are
+// re-established with the client's retry backoff, honouring the server's
+// retry hint. The client's retry MaxAttempts bounds consecutive failed
+// reconnects; a client without retries stops at the first failure.
+//
+// Example:
+//
+//	stream := restClient.SSE("/events", client.WithContext(ctx))
+//	for event := range stream.Events() {
+//		handle(event)
+//	}
+//	if err := stream.Err(); err != nil && !errors.Is(err, context.Canceled) {
+//		return err
+//	}
+func (rc *RESTClient) SSE(url string, options ...RequestOption) *EventStream {
+	config := RequestConfig{Method: GET, URL: url}
+	for _, opt := range options {
+		opt(&config)
+	}
+	if config.Context == nil {
+		config.Context = context.Background()
+	}
+
+	stream := &EventStream{events: make(chan SSEEvent)}
+	go stream.run(rc, config)
+	return stream
+}
+
+// Events returns the channel of received events, closed when the stream ends
+func (s *EventStream) Events() <-chan SSEEvent {
+	return s.events
+}
+
+// Err returns the error that ended the stream. It is valid once Events is closed.
+func (s *EventStream) Err() error {
+	return s.err
+}
+
+// run connects and reconnects until the stream ends
+func (s *EventStream) run(rc *RESTClient, config RequestConfig) {
+	defer close(s.events)
+
+	ctx := config.Context
+	retry := rc.retry
+	if config.NoRetry {
+		retry = nil
+	} else if config.Retry != nil {
+		retry = config.Retry
+	}
+
+	var lastEventID string
+	var serverRetry, delay time.Duration
+	failures := 0
+	for {
+		received, err := s.connect(rc, config, &lastEventID, &serverRetry)
+		if errors.Is(err, errStreamDone) {
+			return
+		}
+		if ctx.Err() != nil {
+			s.err = ctx.Err()
+			return
+		}
+
+		if received {
+			failures = 0
+			delay = 0
+		}
+		if err != nil {
+			failures++
+			if retry == nil || failures >= retry.maxAttempts() {
+				s.err = fmt.Errorf("event stream failed: %w", err)
+				return
+			}
+		}
+
+		switch {
+		case serverRetry > 0:
+			delay = serverRetry
+		case retry != nil:
+			delay = retry.backoff(max(failures, 1), delay)
+		default:
+			delay = time.Second
+		}
+		select {
+		case <-rc.clock.After(delay):
+		case <-ctx.Done():
+			s.err = ctx.Err()
+			return
+		}
+	}
+}
+
+// errStreamDone signals that the server asked the client not to reconnect
+var errStreamDone = errors.New("event stream done")
+
+// connect opens one connection and dispatches its events. It reports
+// whether any event was received and the error that ended the connection;
+// a nil error means the server closed the stream cleanly.
+func (s *EventStream) connect(rc *RESTClient, config RequestConfig, lastEventID *string, serverRetry *time.Duration) (bool, error) {
+	config.Stream = true
+	config.NoRetry = true
+	headers := make(map[string]string, len(config.Headers)+3)
+	for k, v := range config.Headers {
+		headers[k] = v
+	}
+	headers["Accept"] = "text/event-stream"
+	headers["Cache-Control"] = "no-cache"
+	if *lastEventID != "" {
+		headers["Last-Event-ID"] = *lastEventID
+	}
+	config.Headers = headers
+
+	resp, err := rc.Request(config)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return false, errStreamDone
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected HTTP %d from event stream", resp.StatusCode)
+	}
+
+	received := false
+	reader := bufio.NewReader(resp.Reader())
+	var event SSEEvent
+	var data []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return received, nil
+			}
+			return received, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			// A blank line dispatches the buffered event
+			if len(data) > 0 {
+				event.ID = *lastEventID
+				event.Data = strings.Join(data, "\n")
+				if event.Event == "" {
+					event.Event = "message"
+				}
+				select {
+				case s.events <- event:
+					received = true
+				case <-config.Context.Done():
+					return received, config.Context.Err()
+				}
+			}
+			event = SSEEvent{}
+			data = nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // Comment or heartbeat
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			data = append(data, value)
+		case "event":
+			event.Event = value
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				*lastEventID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				*serverRetry = time.Duration(ms) * time.Millisecond
+				event.Retry = *serverRetry
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_SSE(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("Expected event-stream Accept header, got %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch connections.Add(1) {
+		case 1:
+			fmt.Fprint(w, "retry: 5\n: heartbeat\n\nid: 1\nevent: order.created\ndata: {\"id\":1}\n\nid: 2\ndata: line one\ndata: line two\n\n")
+		case 2:
+			if got := r.Header.Get("Last-Event-ID"); got != "2" {
+				t.Errorf("Expected Last-Event-ID 2 on reconnect, got %q", got)
+			}
+			fmt.Fprint(w, "id: 3\r\ndata: resumed\r\n\r\n")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutCircuitBreaker().
+		Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var events []client.SSEEvent
+	stream := restClient.SSE("/events", client.WithContext(ctx))
+	for event := range stream.Events() {
+		events = append(events, event)
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Expected clean end after 204, got %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %+v", events)
+	}
+	if events[0].ID != "1" || events[0].Event != "order.created" || events[0].Data != `{"id":1}` {
+		t.Errorf("Unexpected first event: %+v", events[0])
+	}
+	if events[1].Event != "message" || events[1].Data != "line one\nline two" {
+		t.Errorf("Unexpected multi-line event: %+v", events[1])
+	}
+	if events[2].ID != "3" || events[2].Data != "resumed" {
+		t.Errorf("Unexpected resumed event: %+v", events[2])
+	}
+}
+
+func TestRESTClient_SSECancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: first\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().WithBaseURL(server.URL).Build()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := restClient.SSE("/events", client.WithContext(ctx))
+	if event := <-stream.Events(); event.Data != "first" {
+		t.Fatalf("Unexpected event: %+v", event)
+	}
+	cancel()
+	for range stream.Events() {
+	}
+	if stream.Err() != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", stream.Err())
+	}
+}
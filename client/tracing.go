@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// B3 propagation headers
+const (
+	b3SingleHeader  = "b3"
+	b3TraceIDHeader = "X-B3-TraceId"
+	b3SpanIDHeader  = "X-B3-SpanId"
+	b3SampledHeader = "X-B3-Sampled"
+	b3FlagsHeader   = "X-B3-Flags"
+)
+
+// traceConfig holds trace propagation settings
+type traceConfig struct {
+	b3 bool
+}
+
+// TraceOption configures TracePropagation
+type TraceOption func(*traceConfig)
+
+// WithB3Headers also sends B3 multi-header propagation for downstreams that
+// do not understand traceparent
+func WithB3Headers() TraceOption {
+	return func(config *traceConfig) {
+		config.b3 = true
+	}
+}
+
+// TracePropagation returns middleware that injects the span context from the
+// request context as W3C traceparent/tracestate headers. It works with any
+// OpenTelemetry tracer, or with a remote span context from
+// ExtractTraceContext, and does not require Datadog.
+//
+// Example:
+//
+//	restClient := client.NewClientBuilder().
+//		WithMiddleware(client.TracePropagation()).
+//		Build()
+func TracePropagation(options ...TraceOption) Middleware {
+	config := traceConfig{}
+	for _, option := range options {
+		option(&config)
+	}
+	propagator := propagation.TraceContext{}
+
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*Response, error) {
+			spanContext := trace.SpanContextFromContext(req.Context())
+			if !spanContext.IsValid() {
+				return next(req)
+			}
+
+			if req.Header.Get("traceparent") == "" {
+				propagator.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+			}
+			if config.b3 && req.Header.Get(b3TraceIDHeader) == "" {
+				req.Header.Set(b3TraceIDHeader, spanContext.TraceID().String())
+				req.Header.Set(b3SpanIDHeader, spanContext.SpanID().String())
+				sampled := "0"
+				if spanContext.IsSampled() {
+					sampled = "1"
+				}
+				req.Header.Set(b3SampledHeader, sampled)
+			}
+			return next(req)
+		}
+	}
+}
+
+// ExtractTraceContext returns ctx carrying the remote span context from
+// incoming headers: W3C traceparent/tracestate, falling back to B3 single or
+// multi-header format. ctx is returned unchanged when no valid context is present.
+func ExtractTraceContext(ctx context.Context, header http.Header) context.Context {
+	if header.Get("traceparent") != "" {
+		extracted := propagation.TraceContext{}.Extract(ctx, propagation.HeaderCarrier(header))
+		if trace.SpanContextFromContext(extracted).IsValid() {
+			return extracted
+		}
+	}
+
+	if spanContext, ok := extractB3(header); ok {
+		return trace.ContextWithRemoteSpanContext(ctx, spanContext)
+	}
+	return ctx
+}
+
+// extractB3 parses B3 single or multi-header propagation
+func extractB3(header http.Header) (trace.SpanContext, bool) {
+	var traceID, spanID, sampled string
+	if single := header.Get(b3SingleHeader); single != "" {
+		parts := strings.Split(single, "-")
+		if len(parts) < 2 {
+			return trace.SpanContext{}, false
+		}
+		traceID, spanID = parts[0], parts[1]
+		if len(parts) > 2 {
+			sampled = parts[2]
+		}
+	} else {
+		traceID = header.Get(b3TraceIDHeader)
+		spanID = header.Get(b3SpanIDHeader)
+		sampled = header.Get(b3SampledHeader)
+		if header.Get(b3FlagsHeader) == "1" {
+			sampled = "d"
+		}
+	}
+
+	// 64-bit trace IDs are left-padded to 128 bits
+	if len(traceID) == 16 {
+		traceID = strings.Repeat("0", 16) + traceID
+	}
+	tid, err := trace.TraceIDFromHex(strings.ToLower(traceID))
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	sid, err := trace.SpanIDFromHex(strings.ToLower(spanID))
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+
+	var flags trace.TraceFlags
+	switch sampled {
+	case "1", "true", "d":
+		flags = trace.FlagsSampled
+	}
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: flags,
+		Remote:     true,
+	})
+	return spanContext, spanContext.IsValid()
+}
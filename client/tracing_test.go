@@ -0,0 +1,76 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khekrn/core/client"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracePropagation(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithMiddleware(client.TracePropagation(client.WithB3Headers())).
+		Build()
+
+	// Continue a trace that arrived as B3 headers
+	incoming := http.Header{}
+	incoming.Set("X-B3-TraceId", "463ac35c9f6413ad")
+	incoming.Set("X-B3-SpanId", "a2fb4a1d1a96d312")
+	incoming.Set("X-B3-Sampled", "1")
+	ctx := client.ExtractTraceContext(context.Background(), incoming)
+
+	if _, err := restClient.GET("/", client.WithContext(ctx)); err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	if got := received.Get("traceparent"); got != "00-0000000000000000463ac35c9f6413ad-a2fb4a1d1a96d312-01" {
+		t.Errorf("Unexpected traceparent: %q", got)
+	}
+	if got := received.Get("X-B3-TraceId"); got != "0000000000000000463ac35c9f6413ad" {
+		t.Errorf("Unexpected B3 trace ID: %q", got)
+	}
+
+	// No span context, no headers
+	if _, err := restClient.GET("/"); err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	if received.Get("traceparent") != "" {
+		t.Errorf("Expected no traceparent without a span context, got %q", received.Get("traceparent"))
+	}
+}
+
+func TestExtractTraceContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  http.Header
+		traceID string
+		sampled bool
+	}{
+		{"w3c", http.Header{"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}}, "4bf92f3577b34da6a3ce929d0e0e4736", true},
+		{"b3 single", http.Header{"B3": {"80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-0"}}, "80f198ee56343ba864fe8b2a57d3eff7", false},
+		{"invalid", http.Header{"Traceparent": {"garbage"}}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := trace.SpanContextFromContext(client.ExtractTraceContext(context.Background(), tt.header))
+			if tt.traceID == "" {
+				if sc.IsValid() {
+					t.Errorf("Expected no span context, got %v", sc.TraceID())
+				}
+				return
+			}
+			if sc.TraceID().String() != tt.traceID || sc.IsSampled() != tt.sampled || !sc.IsRemote() {
+				t.Errorf("Unexpected span context: %s sampled=%v remote=%v", sc.TraceID(), sc.IsSampled(), sc.IsRemote())
+			}
+		})
+	}
+}
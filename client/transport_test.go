@@ -0,0 +1,193 @@
+package client_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_Proxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute URL
+		proxied = append(proxied, r.URL.String()+" "+r.Header.Get("Proxy-Authorization"))
+		w.Write([]byte("via proxy"))
+	}))
+	defer proxy.Close()
+
+	proxyURL := strings.Replace(proxy.URL, "http://", "http://user:pass@", 1)
+	restClient := client.NewClientBuilder().
+		WithProxy(proxyURL).
+		Build()
+
+	resp, err := restClient.GET("http://upstream.invalid/orders")
+	if err != nil {
+		t.Fatalf("GET via proxy failed: %v", err)
+	}
+	if resp.String() != "via proxy" {
+		t.Errorf("Expected response from proxy, got %q", resp.String())
+	}
+	if len(proxied) != 1 || proxied[0] != "http://upstream.invalid/orders Basic dXNlcjpwYXNz" {
+		t.Errorf("Unexpected proxied request %v", proxied)
+	}
+
+	invalid := client.NewClientBuilder().WithoutRetry().WithProxy("://bad").Build()
+	if _, err := invalid.GET("http://upstream.invalid/orders"); err == nil || !strings.Contains(err.Error(), "invalid proxy URL") {
+		t.Errorf("Expected invalid proxy URL error, got %v", err)
+	}
+}
+
+func TestRESTClient_MutualTLS(t *testing.T) {
+	certFile, keyFile, clientCert := writeClientCertificate(t)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello " + r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithRootCAs(rootCAs).
+		WithClientCertificate(certFile, keyFile).
+		Build()
+
+	resp, err := restClient.GET("/")
+	if err != nil {
+		t.Fatalf("GET over mTLS failed: %v", err)
+	}
+	if resp.String() != "hello test-client" {
+		t.Errorf("Expected client identity in response, got %q", resp.String())
+	}
+
+	// Without the private CA the server certificate is rejected
+	untrusted := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithClientCertificate(certFile, keyFile).
+		Build()
+	if _, err := untrusted.GET("/"); err == nil {
+		t.Error("Expected certificate verification error without root CAs")
+	}
+
+	// A missing certificate file fails the handshake with the load error
+	missing := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithRootCAs(rootCAs).
+		WithClientCertificate(filepath.Join(t.TempDir(), "missing.pem"), keyFile).
+		Build()
+	_, err = missing.GET("/")
+	if err == nil || !strings.Contains(err.Error(), "failed to load client certificate") {
+		t.Errorf("Expected client certificate load error, got %v", err)
+	}
+}
+
+func TestRESTClient_PinnedCertificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pinned"))
+	}))
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+	leaf := sha256.Sum256(server.Certificate().Raw)
+
+	pins := map[string]string{
+		"certificate": hex.EncodeToString(leaf[:]),
+		"spki":        client.SPKIFingerprint(server.Certificate()),
+	}
+	for name, pin := range pins {
+		restClient := client.NewClientBuilder().
+			WithBaseURL(server.URL).
+			WithRootCAs(rootCAs).
+			WithPinnedCertificates("sha256/AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=", pin).
+			Build()
+		if _, err := restClient.GET("/"); err != nil {
+			t.Errorf("Expected %s pin to match, got %v", name, err)
+		}
+	}
+
+	mismatched := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutRetry().
+		WithRootCAs(rootCAs).
+		WithPinnedCertificates(strings.Repeat("ab:", 31) + "ab").
+		Build()
+	if _, err := mismatched.GET("/"); err == nil || !strings.Contains(err.Error(), "does not match any pinned fingerprint") {
+		t.Errorf("Expected pin mismatch, got %v", err)
+	}
+
+	invalid := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithoutRetry().
+		WithRootCAs(rootCAs).
+		WithPinnedCertificates("not-a-pin").
+		Build()
+	if _, err := invalid.GET("/"); err == nil || !strings.Contains(err.Error(), "invalid certificate pin") {
+		t.Errorf("Expected invalid pin error, got %v", err)
+	}
+}
+
+// writeClientCertificate writes a self-signed client certificate and key as PEM files
+func writeClientCertificate(t *testing.T) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "client.pem")
+	keyFile = filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return certFile, keyFile, cert
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/gorilla/websocket"
+)
+
+// Dial opens a WebSocket connection to url, reusing the client's base URL,
+// default headers, authentication, TLS configuration, proxy, and Datadog
+// tracing. http and https URLs are dialed as ws and wss. Headers and query
+// parameters from options are sent with the handshake; use WithContext to
+// bound the handshake. The handshake response is returned even on failure
+// when the server answered.
+//
+// Example:
+//
+//	conn, _, err := restClient.Dial("/ws/orders", client.WithContext(ctx))
+//	if err != nil {
+//		return err
+//	}
+//	defer conn.Close()
+func (rc *RESTClient) Dial(url string, options ...RequestOption) (*websocket.Conn, *http.Response, error) {
+	config := RequestConfig{Method: GET, URL: url}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	req, err := rc.createRequest(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rc.auth != nil {
+		if err := rc.auth.Apply(req); err != nil {
+			return nil, nil, fmt.Errorf("failed to authenticate request: %w", err)
+		}
+	}
+
+	target := *req.URL
+	switch target.Scheme {
+	case "http":
+		target.Scheme = "ws"
+	case "https":
+		target.Scheme = "wss"
+	}
+
+	// The dialer sets the handshake headers itself
+	header := req.Header.Clone()
+	for _, name := range []string{"Upgrade", "Connection", "Sec-Websocket-Key", "Sec-Websocket-Version", "Sec-Websocket-Extensions", "Content-Type"} {
+		header.Del(name)
+	}
+
+	ctx := req.Context()
+	var span *tracer.Span
+	if rc.datadog {
+		span, ctx = tracer.StartSpanFromContext(ctx, "websocket.dial",
+			tracer.ResourceName(target.Path),
+			tracer.SpanType(ext.SpanTypeWeb),
+			tracer.Tag(ext.HTTPURL, target.String()),
+		)
+		if err := tracer.Inject(span.Context(), tracer.HTTPHeadersCarrier(header)); err != nil {
+			span.Finish(tracer.WithError(err))
+			return nil, nil, fmt.Errorf("failed to inject trace context: %w", err)
+		}
+	}
+
+	conn, resp, err := rc.dialer().DialContext(ctx, target.String(), header)
+	if span != nil {
+		if resp != nil {
+			span.SetTag(ext.HTTPCode, resp.StatusCode)
+		}
+		span.Finish(tracer.WithError(err))
+	}
+	if err != nil {
+		return nil, resp, fmt.Errorf("failed to dial websocket: %w", err)
+	}
+	return conn, resp, nil
+}
+
+// dialer builds a WebSocket dialer from the client's transport settings
+func (rc *RESTClient) dialer() *websocket.Dialer {
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: rc.client.Timeout,
+	}
+	if rc.transport != nil {
+		dialer.Proxy = rc.transport.Proxy
+		dialer.NetDialContext = rc.transport.DialContext
+		if rc.transport.TLSClientConfig != nil {
+			dialer.TLSClientConfig = rc.transport.TLSClientConfig.Clone()
+		}
+	}
+	return dialer
+}
+
+// unwrapTransport returns the *http.Transport beneath the client's own wrappers
+func unwrapTransport(transport http.RoundTripper) *http.Transport {
+	if decompress, ok := transport.(*decompressTransport); ok {
+		transport = decompress.base
+	}
+	t, _ := transport.(*http.Transport)
+	return t
+}
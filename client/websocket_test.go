@@ -0,0 +1,63 @@
+package client_test
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/khekrn/core/client"
+)
+
+func TestRESTClient_Dial(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws" || r.Header.Get("X-Tenant") != "acme" || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Unexpected handshake: %s tenant=%q auth=%q", r.URL.Path, r.Header.Get("X-Tenant"), r.Header.Get("Authorization"))
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		messageType, data, err := conn.ReadMessage()
+		if err == nil {
+			conn.WriteMessage(messageType, append([]byte("echo: "), data...))
+		}
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+	restClient := client.NewClientBuilder().
+		WithBaseURL(server.URL).
+		WithDefaultHeader("X-Tenant", "acme").
+		WithAuth(client.BearerToken("token")).
+		WithRootCAs(rootCAs).
+		Build()
+
+	conn, resp, err := restClient.Dial("/ws")
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("Expected 101, got %d", resp.StatusCode)
+	}
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("hello")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	_, data, err := conn.ReadMessage()
+	if err != nil || string(data) != "echo: hello" {
+		t.Errorf("Unexpected echo %q: %v", data, err)
+	}
+
+	// Without the client's TLS settings the server certificate is rejected
+	if _, _, err := client.NewClientBuilder().WithBaseURL(server.URL).Build().Dial("/ws"); err == nil {
+		t.Error("Expected certificate error without root CAs")
+	}
+}
@@ -0,0 +1,38 @@
+// Package clock abstracts reading the time and waiting for it to pass, so
+// code with TTLs, timeouts, and backoff can be tested deterministically.
+//
+// Production code uses System; tests pass a fake clock such as
+// coretest.Clock and advance it explicitly.
+//
+// Example usage:
+//
+//	users := cache.New[string, User](cache.WithTTL(time.Minute), cache.WithClock(clk))
+//	restClient := client.NewClientBuilder().WithClock(clk).Build()
+package clock
+
+import "time"
+
+// Clock tells the time and signals when a duration has passed
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// After returns a channel that receives the time once d has passed
+	After(d time.Duration) <-chan time.Time
+}
+
+// System is the Clock backed by the time package
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// OrSystem returns c, or System when c is nil, so zero-value
+// configurations use the real time
+func OrSystem(c Clock) Clock {
+	if c == nil {
+		return System
+	}
+	return c
+}
@@ -0,0 +1,28 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSystem(t *testing.T) {
+	before := time.Now()
+	if now := System.Now(); now.Before(before) {
+		t.Errorf("Expected the current time, got %v", now)
+	}
+	select {
+	case <-System.After(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Error("Expected After to fire")
+	}
+}
+
+func TestOrSystem(t *testing.T) {
+	if OrSystem(nil) != System {
+		t.Error("Expected System for a nil clock")
+	}
+	custom := systemClock{}
+	if OrSystem(custom) != Clock(custom) {
+		t.Error("Expected the given clock")
+	}
+}
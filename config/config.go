@@ -0,0 +1,414 @@
+// Package config loads typed configuration structs from defaults, YAML or
+// JSON files, and environment variables, in that order of precedence.
+//
+// Fields are configured with struct tags:
+//
+//   - yaml or json: the key in files, also used for the environment variable
+//     name. Defaults to the snake_case field name; "-" skips the field.
+//   - env: an explicit environment variable name, used without the prefix
+//   - default: the value used when neither a file nor the environment set one
+//   - required:"true": loading fails when the field is still zero
+//   - secret:"true": the value is redacted by String
+//
+// Environment variable names are the prefix and the upper-case key path
+// joined by underscores, e.g. APP_DATABASE_MAX_CONNS for the max_conns key
+// of the database section. Durations use time.ParseDuration syntax, Size
+// accepts units such as "10MB", and lists and maps in environment variables
+// are comma-separated, e.g. "a,b" and "k1=v1,k2=v2".
+//
+// Example usage:
+//
+//	type Settings struct {
+//		Port     int `yaml:"port" default:"8080"`
+//		Database struct {
+//			URL      config.Secret `yaml:"url" required:"true"`
+//			MaxConns int           `yaml:"max_conns" default:"10"`
+//		} `yaml:"database"`
+//		Client  client.Config `yaml:"client"`
+//		Log     logger.Config `yaml:"log"`
+//		Timeout time.Duration `yaml:"timeout" default:"30s"`
+//		MaxBody config.Size   `yaml:"max_body" default:"1MB"`
+//	}
+//
+//	settings, err := config.Load[Settings](
+//		config.WithFiles("config.yaml", "config.local.yaml"),
+//		config.WithEnvPrefix("APP"),
+//	)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	logger.Info("Loaded config", zap.String("config", config.String(settings)))
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds configuration for loading
+type Config struct {
+	Files            []string                        // Files merged in order; later files win
+	OptionalFiles    bool                            // Skips files that do not exist
+	EnvPrefix        string                          // Prefix of derived environment variable names
+	LookupEnv        func(key string) (string, bool) // Defaults to os.LookupEnv
+	AllowUnknownKeys bool                            // Ignores file keys that match no field
+}
+
+// Option is a function type for configuring loading
+type Option func(*Config)
+
+// WithFiles adds YAML or JSON files, merged in order so later files win
+func WithFiles(paths ...string) Option {
+	return func(config *Config) {
+		config.Files = append(config.Files, paths...)
+	}
+}
+
+// WithOptionalFiles skips files that do not exist instead of failing, e.g.
+// for a local override file
+func WithOptionalFiles() Option {
+	return func(config *Config) {
+		config.OptionalFiles = true
+	}
+}
+
+// WithEnvPrefix sets the prefix of derived environment variable names, e.g.
+// "APP" for APP_PORT
+func WithEnvPrefix(prefix string) Option {
+	return func(config *Config) {
+		config.EnvPrefix = prefix
+	}
+}
+
+// WithLookupEnv replaces os.LookupEnv, e.g. in tests
+func WithLookupEnv(lookup func(key string) (string, bool)) Option {
+	return func(config *Config) {
+		config.LookupEnv = lookup
+	}
+}
+
+// WithAllowUnknownKeys ignores file keys that match no field. By default
+// they fail loading, so typos do not go unnoticed.
+func WithAllowUnknownKeys() Option {
+	return func(config *Config) {
+		config.AllowUnknownKeys = true
+	}
+}
+
+// Load creates a T and fills it with LoadInto
+func Load[T any](options ...Option) (*T, error) {
+	target := new(T)
+	if err := LoadInto(target, options...); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// LoadInto fills the struct target points to from defaults, files, and the
+// environment. All invalid and missing fields are reported together.
+func LoadInto(target any, options ...Option) error {
+	config := Config{LookupEnv: os.LookupEnv}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: target must be a non-nil pointer to a struct, got %T", target)
+	}
+
+	data := map[string]any{}
+	for _, path := range config.Files {
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) && config.OptionalFiles {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("config: %w", err)
+		}
+		var file map[string]any
+		if err := yaml.Unmarshal(content, &file); err != nil {
+			return fmt.Errorf("config: failed to parse %s: %w", path, err)
+		}
+		merge(data, file)
+	}
+
+	l := &loader{config: config}
+	l.loadStruct(value.Elem(), data, "", envPrefix(config.EnvPrefix))
+	return errors.Join(l.errs...)
+}
+
+// loader collects errors while walking the target
+type loader struct {
+	config Config
+	errs   []error
+}
+
+// loadStruct fills the fields of a struct value and reports whether any was
+// set. path is the dotted key path for errors, env the variable name prefix.
+func (l *loader) loadStruct(value reflect.Value, data map[string]any, path string, env string) bool {
+	set := false
+	used := map[string]bool{}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		key := fieldKey(field)
+		if !field.IsExported() || key == "-" {
+			continue
+		}
+		used[key] = true
+		fieldPath := path + key
+		fieldEnv := env + envName(key)
+		node, inFile := data[key]
+		target := value.Field(i)
+
+		if isSection(field.Type) {
+			section, ok := node.(map[string]any)
+			if inFile && !ok && node != nil {
+				l.errs = append(l.errs, fmt.Errorf("%s: expected a section, got %v", fieldPath, node))
+				continue
+			}
+			if field.Type.Kind() == reflect.Pointer {
+				allocated := reflect.New(field.Type.Elem())
+				if l.loadStruct(allocated.Elem(), section, fieldPath+".", fieldEnv+"_") {
+					target.Set(allocated)
+					set = true
+				}
+			} else if l.loadStruct(target, section, fieldPath+".", fieldEnv+"_") {
+				set = true
+			}
+			continue
+		}
+
+		if name, ok := field.Tag.Lookup("env"); ok {
+			fieldEnv = name
+		}
+		var err error
+		if raw, ok := l.config.LookupEnv(fieldEnv); ok {
+			err = setString(target, raw)
+			set = true
+		} else if inFile && node != nil {
+			err = setNode(target, node)
+			set = true
+		} else if def, ok := field.Tag.Lookup("default"); ok {
+			err = setString(target, def)
+			set = true
+		}
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: %w", fieldPath, err))
+		} else if field.Tag.Get("required") == "true" && target.IsZero() {
+			l.errs = append(l.errs, fmt.Errorf("%s: required (set it in a file or %s)", fieldPath, fieldEnv))
+		}
+	}
+
+	if !l.config.AllowUnknownKeys {
+		var unknown []string
+		for key := range data {
+			if !used[key] {
+				unknown = append(unknown, path+key)
+			}
+		}
+		sort.Strings(unknown)
+		for _, key := range unknown {
+			l.errs = append(l.errs, fmt.Errorf("%s: unknown key", key))
+		}
+	}
+	return set
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isSection reports whether t is a struct, or pointer to one, whose fields
+// are loaded individually
+func isSection(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textUnmarshalerType) && t != reflect.TypeOf(time.Time{})
+}
+
+// setNode sets target from a decoded file value
+func setNode(target reflect.Value, node any) error {
+	switch target.Kind() {
+	case reflect.Slice:
+		items, ok := node.([]any)
+		if !ok {
+			return setString(target, fmt.Sprint(node))
+		}
+		slice := reflect.MakeSlice(target.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setNode(slice.Index(i), item); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		target.Set(slice)
+		return nil
+	case reflect.Map:
+		entries, ok := node.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected a map, got %v", node)
+		}
+		m := reflect.MakeMapWithSize(target.Type(), len(entries))
+		for key, entry := range entries {
+			k := reflect.New(target.Type().Key()).Elem()
+			if err := setString(k, key); err != nil {
+				return fmt.Errorf("key %q: %w", key, err)
+			}
+			v := reflect.New(target.Type().Elem()).Elem()
+			if err := setNode(v, entry); err != nil {
+				return fmt.Errorf("key %q: %w", key, err)
+			}
+			m.SetMapIndex(k, v)
+		}
+		target.Set(m)
+		return nil
+	}
+	if s, ok := node.(string); ok {
+		return setString(target, s)
+	}
+	return setString(target, fmt.Sprint(node))
+}
+
+// setString parses raw into target
+func setString(target reflect.Value, raw string) error {
+	if target.CanAddr() && target.Addr().Type().Implements(textUnmarshalerType) {
+		return target.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+	if target.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		target.SetInt(int64(d))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid bool %q", raw)
+		}
+		target.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, target.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		target.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, target.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", raw)
+		}
+		target.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, target.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		target.SetFloat(f)
+	case reflect.Slice:
+		var parts []string
+		if raw != "" {
+			parts = strings.Split(raw, ",")
+		}
+		slice := reflect.MakeSlice(target.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setString(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+	case reflect.Map:
+		m := reflect.MakeMap(target.Type())
+		for _, pair := range strings.Split(raw, ",") {
+			if pair = strings.TrimSpace(pair); pair == "" {
+				continue
+			}
+			key, val, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("invalid map entry %q, expected key=value", pair)
+			}
+			k := reflect.New(target.Type().Key()).Elem()
+			v := reflect.New(target.Type().Elem()).Elem()
+			if err := setString(k, strings.TrimSpace(key)); err != nil {
+				return err
+			}
+			if err := setString(v, strings.TrimSpace(val)); err != nil {
+				return err
+			}
+			m.SetMapIndex(k, v)
+		}
+		target.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", target.Type())
+	}
+	return nil
+}
+
+// merge deep-merges src into dst
+func merge(dst, src map[string]any) {
+	for key, value := range src {
+		if section, ok := value.(map[string]any); ok {
+			if existing, ok := dst[key].(map[string]any); ok {
+				merge(existing, section)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
+
+// fieldKey returns the file key of a field from its yaml or json tag,
+// defaulting to its snake_case name
+func fieldKey(field reflect.StructField) string {
+	for _, tag := range []string{"yaml", "json"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" {
+			return name
+		}
+	}
+	return snakeCase(field.Name)
+}
+
+// snakeCase converts a Go identifier such as MaxIdleConns or BaseURL to
+// max_idle_conns or base_url
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// envName converts a file key to its environment variable form
+func envName(key string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+}
+
+// envPrefix returns the variable name prefix for prefix
+func envPrefix(prefix string) string {
+	if prefix == "" {
+		return ""
+	}
+	return strings.TrimSuffix(envName(prefix), "_") + "_"
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/logger"
+)
+
+type testSettings struct {
+	Port     int           `yaml:"port" default:"8080"`
+	Debug    bool          `yaml:"debug"`
+	Timeout  time.Duration `yaml:"timeout" default:"30s"`
+	MaxBody  Size          `yaml:"max_body" default:"1MB"`
+	Hosts    []string      `yaml:"hosts"`
+	Labels   map[string]string
+	APIKey   string `env:"LEGACY_API_KEY" secret:"true"`
+	Ignored  string `yaml:"-"`
+	Database struct {
+		URL      Secret `yaml:"url" required:"true"`
+		MaxConns int    `yaml:"max_conns" default:"10"`
+	} `yaml:"database"`
+	Cache *struct {
+		TTL time.Duration `yaml:"ttl"`
+	} `yaml:"cache"`
+	Log logger.Config `yaml:"log"`
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func envMap(values map[string]string) Option {
+	return WithLookupEnv(func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	})
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	base := writeFile(t, "base.yaml", `
+port: 9000
+hosts: [a, b]
+labels:
+  team: payments
+database:
+  url: postgres://base
+  max_conns: 20
+log:
+  level: info
+  component_levels:
+    httpclient: debug
+`)
+	override := writeFile(t, "override.json", `{"database": {"max_conns": 30}, "timeout": "5s"}`)
+
+	settings, err := Load[testSettings](
+		WithFiles(base, override, filepath.Join(t.TempDir(), "missing.yaml")),
+		WithOptionalFiles(),
+		WithEnvPrefix("APP"),
+		envMap(map[string]string{
+			"APP_PORT":             "9100",
+			"APP_DEBUG":            "true",
+			"APP_DATABASE_URL":     "postgres://env",
+			"APP_LABELS":           "team=orders,tier=1",
+			"APP_LOG_LEVEL":        "warn",
+			"LEGACY_API_KEY":       "k-123",
+			"APP_LOG_REDACT":       "true",
+			"APP_IGNORED":          "not loaded",
+			"APP_LOG_OUTPUTS":      "stdout, /var/log/app.log",
+			"APP_MAX_BODY":         "64KiB",
+			"APP_CACHE_TTL":        "1m",
+			"APP_LOG_SERVICE_NAME": "orders",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if settings.Port != 9100 || !settings.Debug || settings.Timeout != 5*time.Second || settings.MaxBody != 64*KB {
+		t.Errorf("Unexpected scalars %+v", settings)
+	}
+	if settings.Database.URL.Value() != "postgres://env" || settings.Database.MaxConns != 30 {
+		t.Errorf("Unexpected database %+v", settings.Database)
+	}
+	if len(settings.Hosts) != 2 || settings.Labels["team"] != "orders" || settings.Labels["tier"] != "1" {
+		t.Errorf("Unexpected collections %v %v", settings.Hosts, settings.Labels)
+	}
+	if settings.APIKey != "k-123" || settings.Ignored != "" {
+		t.Errorf("Unexpected env tag handling %q %q", settings.APIKey, settings.Ignored)
+	}
+	if settings.Cache == nil || settings.Cache.TTL != time.Minute {
+		t.Errorf("Expected the pointer section to be allocated, got %+v", settings.Cache)
+	}
+	log := settings.Log
+	if log.Level != "warn" || !log.Redact || len(log.Outputs) != 2 || log.Outputs[1] != "/var/log/app.log" ||
+		log.ComponentLevels["httpclient"] != "debug" || log.Service == nil || log.Service.Name != "orders" {
+		t.Errorf("Unexpected logger config %+v", log)
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	settings, err := Load[testSettings](envMap(map[string]string{"DATABASE_URL": "postgres://x"}))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.Port != 8080 || settings.Timeout != 30*time.Second || settings.MaxBody != MB || settings.Database.MaxConns != 10 {
+		t.Errorf("Expected defaults, got %+v", settings)
+	}
+	if settings.Cache != nil || settings.Log.Service != nil {
+		t.Error("Expected unset pointer sections to stay nil")
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	file := writeFile(t, "bad.yaml", `
+port: eighty
+databse:
+  url: postgres://typo
+log:
+  reporter: sentry
+`)
+	_, err := Load[testSettings](WithFiles(file), envMap(map[string]string{"TIMEOUT": "30"}))
+	if err == nil {
+		t.Fatal("Expected errors")
+	}
+	for _, want := range []string{
+		`port: invalid integer "eighty"`,
+		`timeout: invalid duration "30"`,
+		"database.url: required (set it in a file or DATABASE_URL)",
+		"databse: unknown key",
+		"log.reporter: unknown key",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+
+	if _, err := Load[testSettings](WithFiles(file), WithAllowUnknownKeys(), envMap(map[string]string{"DATABASE_URL": "x"})); err == nil ||
+		strings.Contains(err.Error(), "unknown key") {
+		t.Errorf("Expected only the invalid port, got %v", err)
+	}
+	if _, err := Load[testSettings](WithFiles("missing.yaml")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+	if err := LoadInto(testSettings{}); err == nil {
+		t.Error("Expected an error for a non-pointer target")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, want := range map[string]string{"BaseURL": "base_url", "MaxIdleConns": "max_idle_conns", "URL": "url", "HTTPServer": "http_server"} {
+		if got := snakeCase(name); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
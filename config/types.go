@@ -0,0 +1,157 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Redacted replaces secret values in String and in logs
+const Redacted = "[REDACTED]"
+
+// Size is a byte count parsed from values such as "512", "64KB", or
+// "1.5GiB". Units are binary: KB and KiB both mean 1024 bytes.
+type Size int64
+
+// Size units
+const (
+	Byte Size = 1
+	KB   Size = 1 << (10 * iota)
+	MB
+	GB
+	TB
+)
+
+// sizeUnits maps upper-case unit suffixes to their sizes
+var sizeUnits = map[string]Size{
+	"B": Byte, "K": KB, "KB": KB, "KIB": KB, "M": MB, "MB": MB, "MIB": MB,
+	"G": GB, "GB": GB, "GIB": GB, "T": TB, "TB": TB, "TIB": TB,
+}
+
+// ParseSize parses a byte count with an optional unit
+func ParseSize(s string) (Size, error) {
+	trimmed := strings.TrimSpace(s)
+	split := strings.IndexFunc(trimmed, func(r rune) bool { return unicode.IsLetter(r) })
+	number, unit := trimmed, "B"
+	if split >= 0 {
+		number, unit = strings.TrimSpace(trimmed[:split]), strings.ToUpper(trimmed[split:])
+	}
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, unit)
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return Size(value * float64(multiplier)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (s *Size) UnmarshalText(text []byte) error {
+	size, err := ParseSize(string(text))
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
+
+// String returns the size in the largest unit that divides it, e.g. "64MB"
+func (s Size) String() string {
+	for _, unit := range []struct {
+		size Size
+		name string
+	}{{TB, "TB"}, {GB, "GB"}, {MB, "MB"}, {KB, "KB"}} {
+		if s != 0 && s%unit.size == 0 {
+			return strconv.FormatInt(int64(s/unit.size), 10) + unit.name
+		}
+	}
+	return strconv.FormatInt(int64(s), 10) + "B"
+}
+
+// Secret is a string that is redacted when printed, logged, or encoded.
+// Use Value to read it.
+type Secret string
+
+// Value returns the secret value
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String implements fmt.Stringer with the redacted form
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// GoString implements fmt.GoStringer, so %#v is redacted too
+func (s Secret) GoString() string {
+	return strconv.Quote(s.String())
+}
+
+// MarshalJSON encodes the redacted form
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// LogValue implements slog.LogValuer with the redacted form
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// String renders a configuration struct as sorted key=value pairs for
+// logging, e.g. "database.max_conns=10 database.url=[REDACTED] port=8080".
+// Fields tagged secret:"true" and Secret values are redacted.
+func String(cfg any) string {
+	value := reflect.ValueOf(cfg)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Sprint(cfg)
+	}
+
+	var pairs []string
+	flatten(value, "", &pairs)
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+// flatten appends the key=value pairs of a struct's fields
+func flatten(value reflect.Value, path string, pairs *[]string) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		key := fieldKey(field)
+		if !field.IsExported() || key == "-" {
+			continue
+		}
+		target := value.Field(i)
+		if isSection(field.Type) {
+			if target.Kind() == reflect.Pointer {
+				if target.IsNil() {
+					continue
+				}
+				target = target.Elem()
+			}
+			flatten(target, path+key+".", pairs)
+			continue
+		}
+
+		rendered := fmt.Sprint(target.Interface())
+		if field.Tag.Get("secret") == "true" && !target.IsZero() {
+			rendered = Redacted
+		}
+		*pairs = append(*pairs, path+key+"="+rendered)
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSize(t *testing.T) {
+	for input, want := range map[string]Size{
+		"512":    512,
+		"64KB":   64 * KB,
+		"64kib":  64 * KB,
+		"1.5GiB": GB + GB/2,
+		"10 MB":  10 * MB,
+		"2T":     2 * TB,
+	} {
+		if got, err := ParseSize(input); err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+	for _, invalid := range []string{"", "ten", "10XB", "-1MB"} {
+		if _, err := ParseSize(invalid); err == nil {
+			t.Errorf("Expected %q to be invalid", invalid)
+		}
+	}
+	if (64*MB).String() != "64MB" || Size(1500).String() != "1500B" || Size(0).String() != "0B" {
+		t.Error("Unexpected size formatting")
+	}
+}
+
+func TestSecret(t *testing.T) {
+	secret := Secret("hunter2")
+	if secret.Value() != "hunter2" {
+		t.Error("Expected Value to return the secret")
+	}
+	if fmt.Sprint(secret) != Redacted || fmt.Sprintf("%#v", secret) != `"[REDACTED]"` {
+		t.Errorf("Expected redacted formatting, got %v", secret)
+	}
+	if data, _ := json.Marshal(struct{ Password Secret }{secret}); string(data) != `{"Password":"[REDACTED]"}` {
+		t.Errorf("Expected redacted JSON, got %s", data)
+	}
+	if secret.LogValue().String() != Redacted || Secret("").String() != "" {
+		t.Error("Unexpected log value")
+	}
+}
+
+func TestString(t *testing.T) {
+	settings := testSettings{Port: 8080, Timeout: time.Minute, MaxBody: MB, APIKey: "k-123"}
+	settings.Database.URL = "postgres://user:pass@db"
+
+	rendered := String(&settings)
+	for _, want := range []string{"port=8080", "timeout=1m0s", "max_body=1MB", "api_key=[REDACTED]", "database.url=[REDACTED]", "log.level="} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("Expected %q in %s", want, rendered)
+		}
+	}
+	if strings.Contains(rendered, "pass") || strings.Contains(rendered, "k-123") || strings.Contains(rendered, "ignored") {
+		t.Errorf("Expected secrets and skipped fields to be hidden, got %s", rendered)
+	}
+	if String(nil) != "<nil>" || String((*testSettings)(nil)) != "" {
+		t.Error("Unexpected rendering of nil")
+	}
+}
@@ -0,0 +1,116 @@
+package coretest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/khekrn/core/clock"
+)
+
+// DefaultClockStart is the time a Clock created with a zero start shows
+var DefaultClockStart = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Clock is a fake clock.Clock whose time only moves when the test advances
+// it. Channels returned by After fire once the clock reaches their deadline.
+//
+// Example:
+//
+//	clk := coretest.NewClock(time.Time{})
+//	users := cache.New[string, User](cache.WithTTL(time.Minute), cache.WithClock(clk))
+//	users.Set("u-1", user)
+//	clk.Advance(2 * time.Minute)
+//	_, ok := users.Get("u-1") // false
+type Clock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []waiter
+}
+
+var _ clock.Clock = (*Clock)(nil)
+
+// waiter is a pending After call
+type waiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewClock creates a fake clock showing start, or DefaultClockStart when
+// start is zero
+func NewClock(start time.Time) *Clock {
+	if start.IsZero() {
+		start = DefaultClockStart
+	}
+	c := &Clock{now: start}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the fake time
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the fake time once the clock has
+// been advanced by d. A non-positive d fires immediately.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, waiter{deadline: c.now.Add(d), ch: ch})
+	c.cond.Broadcast()
+	return ch
+}
+
+// Advance moves the clock forward by d and fires the waiters that are due
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(c.now.Add(d))
+}
+
+// Set moves the clock to t and fires the waiters that are due. Moving it
+// backwards fires nothing.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(t)
+}
+
+// Waiters returns the number of pending After calls
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil waits until n After calls are pending, so a test can advance
+// the clock once the code under test is sleeping, e.g. between retries
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// set moves the clock to t and fires due waiters. The caller holds the lock.
+func (c *Clock) set(t time.Time) {
+	c.now = t
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(t) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- t
+	}
+	c.waiters = pending
+}
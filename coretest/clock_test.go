@@ -0,0 +1,61 @@
+package coretest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	clk := NewClock(time.Time{})
+	if !clk.Now().Equal(DefaultClockStart) {
+		t.Errorf("Expected the default start, got %v", clk.Now())
+	}
+
+	short := clk.After(time.Second)
+	long := clk.After(time.Minute)
+	select {
+	case <-clk.After(0):
+	default:
+		t.Error("Expected a zero duration to fire immediately")
+	}
+	if clk.Waiters() != 2 {
+		t.Errorf("Expected 2 waiters, got %d", clk.Waiters())
+	}
+
+	clk.Advance(time.Second)
+	select {
+	case fired := <-short:
+		if !fired.Equal(DefaultClockStart.Add(time.Second)) {
+			t.Errorf("Expected the advanced time, got %v", fired)
+		}
+	default:
+		t.Error("Expected the due waiter to fire")
+	}
+	select {
+	case <-long:
+		t.Error("Expected the later waiter to keep waiting")
+	default:
+	}
+
+	clk.Set(DefaultClockStart.Add(time.Hour))
+	if _, ok := <-long; !ok || clk.Waiters() != 0 {
+		t.Error("Expected every waiter to fire")
+	}
+}
+
+func TestClock_BlockUntil(t *testing.T) {
+	clk := NewClock(time.Time{})
+	done := make(chan struct{})
+	go func() {
+		<-clk.After(5 * time.Second)
+		close(done)
+	}()
+
+	clk.BlockUntil(1)
+	clk.Advance(5 * time.Second)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the sleeping goroutine to wake")
+	}
+}
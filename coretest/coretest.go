@@ -0,0 +1,101 @@
+// Package coretest provides test utilities shared by services built on the
+// core packages.
+//
+// This package bundles an httptest server answering with canned JSON
+// fixtures, assertions for the standard response envelope, the logger's
+// in-memory observer, and a fake clock for code with TTLs and backoff, such
+// as the cache package and the REST client's retries.
+//
+// Example usage:
+//
+//	func TestGetUser(t *testing.T) {
+//		logs := coretest.ObserveLogs(t)
+//		clk := coretest.NewClock(time.Time{})
+//		srv := coretest.NewServer(t, coretest.Rejected("GET", "/users/42", response.CodeNotFound))
+//		handler := NewHandler(client.NewClientBuilder().WithBaseURL(srv.URL).WithClock(clk).Build())
+//
+//		recorder := httptest.NewRecorder()
+//		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/users/42", nil))
+//		coretest.AssertRejected(t, recorder.Body.Bytes(), response.CodeNotFound)
+//		logs.AssertLogged(zapcore.WarnLevel, "user not found")
+//	}
+package coretest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/khekrn/core/logger"
+	"github.com/khekrn/core/response"
+)
+
+// ObserveLogs installs a logger capturing every entry as the global logger
+// for the duration of the test; see logger.NewTestLogger
+func ObserveLogs(t testing.TB) *logger.TestLogger {
+	t.Helper()
+	return logger.NewTestLogger(t)
+}
+
+// DecodeEnvelope parses body as a standard response envelope, failing the
+// test if it is not one
+func DecodeEnvelope(t testing.TB, body []byte) response.Response {
+	t.Helper()
+	var envelope response.Response
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Status == "" {
+		t.Fatalf("Expected a response envelope, got %q", body)
+	}
+	return envelope
+}
+
+// AssertAccepted fails the test unless body is a successful envelope, and
+// returns the envelope
+func AssertAccepted(t testing.TB, body []byte) response.Response {
+	t.Helper()
+	envelope := DecodeEnvelope(t, body)
+	if envelope.Status != response.StatusAccept {
+		t.Errorf("Expected status %s, got %s %s: %s", response.StatusAccept, envelope.Status, envelope.Code, envelope.Message)
+	}
+	return envelope
+}
+
+// AssertRejected fails the test unless body is a rejected envelope with
+// code, e.g. response.CodeNotFound, and returns the envelope
+func AssertRejected(t testing.TB, body []byte, code string) response.Response {
+	t.Helper()
+	return assertError(t, body, response.StatusReject, code)
+}
+
+// AssertFailed fails the test unless body is a failure envelope, as written
+// for 5xx errors, with code, and returns the envelope
+func AssertFailed(t testing.TB, body []byte, code string) response.Response {
+	t.Helper()
+	return assertError(t, body, response.StatusFailure, code)
+}
+
+// AssertValidationError fails the test unless body is a rejected
+// ERR_VALIDATION envelope with a failure for field
+func AssertValidationError(t testing.TB, body []byte, field string) {
+	t.Helper()
+	assertError(t, body, response.StatusReject, response.CodeValidation)
+
+	var envelope response.Typed[[]response.ValidationError]
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("Expected validation errors as data, got %q", body)
+	}
+	for _, validationErr := range envelope.Data {
+		if validationErr.Field == field {
+			return
+		}
+	}
+	t.Errorf("Expected a validation error for %s, got %+v", field, envelope.Data)
+}
+
+// assertError fails the test unless body is an error envelope with status and code
+func assertError(t testing.TB, body []byte, status, code string) response.Response {
+	t.Helper()
+	envelope := DecodeEnvelope(t, body)
+	if envelope.Status != status || envelope.Code != code {
+		t.Errorf("Expected %s %s, got %s %s: %s", status, code, envelope.Status, envelope.Code, envelope.Message)
+	}
+	return envelope
+}
@@ -0,0 +1,48 @@
+package coretest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khekrn/core/logger"
+	"github.com/khekrn/core/response"
+	"github.com/khekrn/core/validation"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAssertions(t *testing.T) {
+	write := func(err error) []byte {
+		recorder := httptest.NewRecorder()
+		response.WriteError(recorder, httptest.NewRequest(http.MethodGet, "/", nil), err)
+		return recorder.Body.Bytes()
+	}
+
+	AssertRejected(t, write(response.ErrNotFound), response.CodeNotFound)
+	AssertFailed(t, write(errors.New("boom")), response.CodeInternal)
+	AssertValidationError(t, write(validation.Errors{{Field: "email", Reason: "email is a required field"}}), "email")
+	AssertAccepted(t, []byte(`{"status":"Accepted","data":{"id":"42"}}`))
+
+	probe := &failureRecorder{TB: t}
+	AssertRejected(probe, write(response.ErrConflict), response.CodeNotFound)
+	if !probe.failed {
+		t.Error("Expected a code mismatch to fail")
+	}
+}
+
+// failureRecorder records failures instead of failing the test
+type failureRecorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *failureRecorder) Helper()                           {}
+func (r *failureRecorder) Errorf(format string, args ...any) { r.failed = true }
+func (r *failureRecorder) Fatalf(format string, args ...any) { r.failed = true }
+
+func TestObserveLogs(t *testing.T) {
+	logs := ObserveLogs(t)
+	logger.Warn("user not found")
+	logs.AssertLogged(zapcore.WarnLevel, "not found")
+}
@@ -0,0 +1,172 @@
+package coretest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/khekrn/core/response"
+)
+
+// Fixture is a canned response for requests matching Method and Path
+type Fixture struct {
+	Method string      // Matches any method when empty
+	Path   string      // Request path, without the query
+	Status int         // Defaults to 200
+	Header http.Header // Additional response headers
+	// Body is encoded as JSON; strings and byte slices are sent as is
+	Body any
+	// File names a file, e.g. "testdata/user.json", whose content is sent
+	// instead of Body
+	File string
+}
+
+// Accepted returns a fixture answering with a successful envelope carrying data
+func Accepted(method, path string, data any) Fixture {
+	return Fixture{Method: method, Path: path, Body: response.NewSuccessResponse("OK", data)}
+}
+
+// Rejected returns a fixture answering with the error envelope and HTTP
+// status of code, e.g. response.CodeNotFound
+func Rejected(method, path, code string) Fixture {
+	info, _ := response.LookupCode(code)
+	apiErr := response.NewAPIError(code, info.Description, nil)
+	return Fixture{Method: method, Path: path, Status: apiErr.HTTPStatus, Body: apiErr.Response()}
+}
+
+// Request is a request received by a Server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// JSON decodes the request body into v, failing the test on error
+func (r Request) JSON(t testing.TB, v any) {
+	t.Helper()
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("Failed to decode %s %s body %q: %v", r.Method, r.Path, r.Body, err)
+	}
+}
+
+// Server is an httptest server answering with canned JSON fixtures and
+// recording the requests it receives. Requests without a fixture fail the
+// test and are answered with a 404 envelope.
+//
+// Example:
+//
+//	srv := coretest.NewServer(t,
+//		coretest.Accepted("GET", "/users/42", User{ID: "42"}),
+//		coretest.Fixture{Method: "POST", Path: "/orders", Status: http.StatusCreated, File: "testdata/order.json"},
+//	)
+//	restClient := client.NewClientBuilder().WithBaseURL(srv.URL).Build()
+type Server struct {
+	*httptest.Server
+	t        testing.TB
+	mu       sync.Mutex
+	fixtures []Fixture
+	requests []Request
+}
+
+// NewServer starts a server answering with fixtures. It is closed when the
+// test finishes.
+func NewServer(t testing.TB, fixtures ...Fixture) *Server {
+	t.Helper()
+	s := &Server{t: t, fixtures: fixtures}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Add registers more fixtures. Later fixtures take precedence, so a test can
+// override a shared default.
+func (s *Server) Add(fixtures ...Fixture) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures = append(s.fixtures, fixtures...)
+}
+
+// Requests returns the requests received so far, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// serve answers r with the most recently added matching fixture
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	fixture, ok := s.match(r)
+	s.mu.Unlock()
+
+	if !ok {
+		s.t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		response.WriteError(w, r, response.ErrNotFound)
+		return
+	}
+
+	payload, err := fixture.payload()
+	if err != nil {
+		s.t.Errorf("Failed to load fixture for %s %s: %v", r.Method, r.URL.Path, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for name, values := range fixture.Header {
+		w.Header()[name] = values
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	status := fixture.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(payload)
+}
+
+// match returns the most recently added fixture for r. The caller holds the lock.
+func (s *Server) match(r *http.Request) (Fixture, bool) {
+	for i := len(s.fixtures) - 1; i >= 0; i-- {
+		fixture := s.fixtures[i]
+		if (fixture.Method == "" || strings.EqualFold(fixture.Method, r.Method)) && fixture.Path == r.URL.Path {
+			return fixture, true
+		}
+	}
+	return Fixture{}, false
+}
+
+// payload returns the response body of the fixture
+func (f Fixture) payload() ([]byte, error) {
+	if f.File != "" {
+		return os.ReadFile(f.File)
+	}
+	switch body := f.Body.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return body, nil
+	case string:
+		return []byte(body), nil
+	default:
+		var buf bytes.Buffer
+		err := json.NewEncoder(&buf).Encode(body)
+		return buf.Bytes(), err
+	}
+}
@@ -0,0 +1,57 @@
+package coretest
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/khekrn/core/response"
+)
+
+func TestServer(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "order.json")
+	if err := os.WriteFile(fixture, []byte(`{"id":"ord-1"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := NewServer(t,
+		Accepted("GET", "/users/42", map[string]string{"id": "42"}),
+		Fixture{Method: "POST", Path: "/orders", Status: http.StatusCreated, File: fixture},
+	)
+	srv.Add(Rejected("GET", "/users/42", response.CodeNotFound))
+
+	get := func(method, path, body string) (int, []byte) {
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, data
+	}
+
+	status, body := get("GET", "/users/42", "")
+	if status != http.StatusNotFound {
+		t.Errorf("Expected the later fixture to win, got %d", status)
+	}
+	AssertRejected(t, body, response.CodeNotFound)
+
+	status, body = get("POST", "/orders?dry_run=1", `{"sku":"A1"}`)
+	if status != http.StatusCreated || string(body) != `{"id":"ord-1"}` {
+		t.Errorf("Expected the file fixture, got %d %s", status, body)
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 2 || requests[1].Query != "dry_run=1" {
+		t.Fatalf("Unexpected requests %+v", requests)
+	}
+	var order struct{ SKU string }
+	requests[1].JSON(t, &order)
+	if order.SKU != "A1" {
+		t.Errorf("Expected the recorded body, got %+v", order)
+	}
+}
@@ -0,0 +1,440 @@
+// Package db provides an instrumented wrapper around database/sql with
+// connection pool configuration, query logging, slow-query detection,
+// tracing, and metrics.
+//
+// This package mirrors what the client package does for HTTP: a builder
+// with production-ready defaults, optional Datadog tracing, and structured
+// logging through the logger package. Any database/sql driver can be used
+// (e.g. pgx's stdlib driver registered as "pgx").
+//
+// Example usage:
+//
+//	database, err := db.NewDBBuilder().
+//		WithDriver("pgx", os.Getenv("DATABASE_URL")).
+//		WithMaxOpenConns(50).
+//		WithSlowQueryThreshold(250 * time.Millisecond).
+//		WithDatadog(true).
+//		Build()
+//	if err != nil {
+//		return err
+//	}
+//	defer database.Close()
+//
+//	users, err := db.Query(ctx, database, func(rows *sql.Rows) (User, error) {
+//		var u User
+//		err := rows.Scan(&u.ID, &u.Name)
+//		return u, err
+//	}, "SELECT id, name FROM users WHERE active = $1", true)
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/khekrn/core/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Config holds database connection and instrumentation settings
+type Config struct {
+	Driver             string
+	DSN                string
+	ServiceName        string
+	MaxOpenConns       int
+	MaxIdleConns       int
+	ConnMaxLifetime    time.Duration
+	ConnMaxIdleTime    time.Duration
+	SlowQueryThreshold time.Duration
+	LogQueries         bool
+	EnableDatadog      bool
+}
+
+// Querier is implemented by both *DB and *Tx
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Metrics holds a snapshot of query counters and connection pool statistics
+type Metrics struct {
+	Queries     int64
+	Errors      int64
+	SlowQueries int64
+	Pool        sql.DBStats
+}
+
+// DB wraps a *sql.DB with logging, tracing, and metrics
+type DB struct {
+	db     *sql.DB
+	config Config
+	tracer trace.Tracer
+
+	queries     atomic.Int64
+	errors      atomic.Int64
+	slowQueries atomic.Int64
+}
+
+// DBBuilder provides a fluent interface for building instrumented databases
+type DBBuilder struct {
+	config         Config
+	db             *sql.DB
+	tracerProvider trace.TracerProvider
+}
+
+// NewDBBuilder creates a new database builder with sensible pool defaults
+func NewDBBuilder() *DBBuilder {
+	return &DBBuilder{
+		config: Config{
+			ServiceName:        "db",
+			MaxOpenConns:       25,
+			MaxIdleConns:       25,
+			ConnMaxLifetime:    30 * time.Minute,
+			ConnMaxIdleTime:    5 * time.Minute,
+			SlowQueryThreshold: 200 * time.Millisecond,
+		},
+	}
+}
+
+// WithConfig replaces the builder configuration
+func (b *DBBuilder) WithConfig(config Config) *DBBuilder {
+	b.config = config
+	return b
+}
+
+// WithDriver sets the database/sql driver name and data source name
+func (b *DBBuilder) WithDriver(driver, dsn string) *DBBuilder {
+	b.config.Driver = driver
+	b.config.DSN = dsn
+	return b
+}
+
+// WithDB wraps an already opened *sql.DB instead of opening a new one
+func (b *DBBuilder) WithDB(db *sql.DB) *DBBuilder {
+	b.db = db
+	return b
+}
+
+// WithServiceName sets the service name reported in traces and logs
+func (b *DBBuilder) WithServiceName(name string) *DBBuilder {
+	b.config.ServiceName = name
+	return b
+}
+
+// WithMaxOpenConns sets the maximum number of open connections
+func (b *DBBuilder) WithMaxOpenConns(n int) *DBBuilder {
+	b.config.MaxOpenConns = n
+	return b
+}
+
+// WithMaxIdleConns sets the maximum number of idle connections
+func (b *DBBuilder) WithMaxIdleConns(n int) *DBBuilder {
+	b.config.MaxIdleConns = n
+	return b
+}
+
+// WithConnMaxLifetime sets the maximum lifetime of a connection
+func (b *DBBuilder) WithConnMaxLifetime(d time.Duration) *DBBuilder {
+	b.config.ConnMaxLifetime = d
+	return b
+}
+
+// WithConnMaxIdleTime sets the maximum idle time of a connection
+func (b *DBBuilder) WithConnMaxIdleTime(d time.Duration) *DBBuilder {
+	b.config.ConnMaxIdleTime = d
+	return b
+}
+
+// WithSlowQueryThreshold sets the duration above which queries are logged as slow
+func (b *DBBuilder) WithSlowQueryThreshold(threshold time.Duration) *DBBuilder {
+	b.config.SlowQueryThreshold = threshold
+	return b
+}
+
+// WithQueryLogging logs every query at debug level
+func (b *DBBuilder) WithQueryLogging(enable bool) *DBBuilder {
+	b.config.LogQueries = enable
+	return b
+}
+
+// WithDatadog enables Datadog tracing spans for queries
+func (b *DBBuilder) WithDatadog(enable bool) *DBBuilder {
+	b.config.EnableDatadog = enable
+	return b
+}
+
+// WithTracerProvider enables OpenTelemetry tracing spans for queries
+func (b *DBBuilder) WithTracerProvider(provider trace.TracerProvider) *DBBuilder {
+	b.tracerProvider = provider
+	return b
+}
+
+// Build opens the database and applies the pool configuration.
+// No connection is established until the first query or PingContext.
+func (b *DBBuilder) Build() (*DB, error) {
+	sqlDB := b.db
+	if sqlDB == nil {
+		if b.config.Driver == "" {
+			return nil, errors.New("db: driver is required")
+		}
+
+		var err error
+		sqlDB, err = sql.Open(b.config.Driver, b.config.DSN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open database: %w", err)
+		}
+	}
+
+	sqlDB.SetMaxOpenConns(b.config.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(b.config.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(b.config.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(b.config.ConnMaxIdleTime)
+
+	database := &DB{db: sqlDB, config: b.config}
+	if b.tracerProvider != nil {
+		database.tracer = b.tracerProvider.Tracer("github.com/khekrn/core/db")
+	}
+
+	return database, nil
+}
+
+// Unwrap returns the underlying *sql.DB
+func (d *DB) Unwrap() *sql.DB {
+	return d.db
+}
+
+// PingContext verifies the connection to the database
+func (d *DB) PingContext(ctx context.Context) error {
+	return d.db.PingContext(ctx)
+}
+
+// Close closes the database and its connection pool
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Metrics returns a snapshot of query counters and pool statistics
+func (d *DB) Metrics() Metrics {
+	return Metrics{
+		Queries:     d.queries.Load(),
+		Errors:      d.errors.Load(),
+		SlowQueries: d.slowQueries.Load(),
+		Pool:        d.db.Stats(),
+	}
+}
+
+// ExecContext executes a query that doesn't return rows
+func (d *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var result sql.Result
+	err := d.instrument(ctx, "sql.exec", query, func(ctx context.Context) error {
+		var err error
+		result, err = d.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// QueryContext executes a query that returns rows
+func (d *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := d.instrument(ctx, "sql.query", query, func(ctx context.Context) error {
+		var err error
+		rows, err = d.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRowContext executes a query that returns at most one row
+func (d *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	var row *sql.Row
+	d.instrument(ctx, "sql.query", query, func(ctx context.Context) error {
+		row = d.db.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+// BeginTx starts a transaction whose statements are instrumented like the DB's
+func (d *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	var tx *sql.Tx
+	err := d.instrument(ctx, "sql.begin", "BEGIN", func(ctx context.Context) error {
+		var err error
+		tx, err = d.db.BeginTx(ctx, opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{tx: tx, db: d}, nil
+}
+
+// Tx wraps a *sql.Tx with the same instrumentation as DB
+type Tx struct {
+	tx         *sql.Tx
+	db         *DB
+	savepoints int
+}
+
+// Unwrap returns the underlying *sql.Tx
+func (t *Tx) Unwrap() *sql.Tx {
+	return t.tx
+}
+
+// ExecContext executes a query that doesn't return rows within the transaction
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var result sql.Result
+	err := t.db.instrument(ctx, "sql.exec", query, func(ctx context.Context) error {
+		var err error
+		result, err = t.tx.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// QueryContext executes a query that returns rows within the transaction
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := t.db.instrument(ctx, "sql.query", query, func(ctx context.Context) error {
+		var err error
+		rows, err = t.tx.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRowContext executes a query that returns at most one row within the transaction
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	var row *sql.Row
+	t.db.instrument(ctx, "sql.query", query, func(ctx context.Context) error {
+		row = t.tx.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+// Commit commits the transaction
+func (t *Tx) Commit() error {
+	return t.db.instrument(context.Background(), "sql.commit", "COMMIT", func(context.Context) error {
+		return t.tx.Commit()
+	})
+}
+
+// Rollback aborts the transaction
+func (t *Tx) Rollback() error {
+	return t.db.instrument(context.Background(), "sql.rollback", "ROLLBACK", func(context.Context) error {
+		return t.tx.Rollback()
+	})
+}
+
+// Query runs a query and scans every row with the given function
+func Query[T any](ctx context.Context, q Querier, scan func(*sql.Rows) (T, error), query string, args ...any) ([]T, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []T
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		results = append(results, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// QueryOne runs a query expected to return a single row and scans it.
+// It returns sql.ErrNoRows when the query returns nothing.
+func QueryOne[T any](ctx context.Context, q Querier, scan func(*sql.Row) (T, error), query string, args ...any) (T, error) {
+	return scan(q.QueryRowContext(ctx, query, args...))
+}
+
+// instrument wraps a database call with tracing, logging, and metrics
+func (d *DB) instrument(ctx context.Context, operation, query string, fn func(ctx context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var ddSpan *tracer.Span
+	if d.config.EnableDatadog {
+		ddSpan, ctx = tracer.StartSpanFromContext(ctx, operation,
+			tracer.ServiceName(d.config.ServiceName),
+			tracer.ResourceName(query),
+			tracer.SpanType(ext.SpanTypeSQL),
+		)
+	}
+
+	var otelSpan trace.Span
+	if d.tracer != nil {
+		ctx, otelSpan = d.tracer.Start(ctx, operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.statement", query)),
+		)
+	}
+
+	start := time.Now()
+	err := fn(ctx)
+	duration := time.Since(start)
+
+	// sql.ErrNoRows is an expected outcome rather than a failure
+	failed := err != nil && !errors.Is(err, sql.ErrNoRows)
+
+	d.queries.Add(1)
+	if failed {
+		d.errors.Add(1)
+	}
+
+	if ddSpan != nil {
+		if failed {
+			ddSpan.Finish(tracer.WithError(err))
+		} else {
+			ddSpan.Finish()
+		}
+	}
+	if otelSpan != nil {
+		if failed {
+			otelSpan.RecordError(err)
+			otelSpan.SetStatus(codes.Error, err.Error())
+		}
+		otelSpan.End()
+	}
+
+	if logger.Logger != nil {
+		log := logger.FromContext(ctx)
+		fields := []zap.Field{
+			zap.String("operation", operation),
+			zap.String("query", query),
+			zap.Duration("duration", duration),
+		}
+
+		switch {
+		case failed:
+			log.Error("Database query failed", append(fields, zap.Error(err))...)
+		case d.config.SlowQueryThreshold > 0 && duration > d.config.SlowQueryThreshold:
+			log.Warn("Slow database query", fields...)
+		case d.config.LogQueries:
+			log.Debug("Database query", fields...)
+		}
+	}
+
+	if d.config.SlowQueryThreshold > 0 && duration > d.config.SlowQueryThreshold {
+		d.slowQueries.Add(1)
+	}
+
+	return err
+}
@@ -0,0 +1,199 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/helpers"
+)
+
+// fakeDriver records executed statements and can fail commits on demand
+type fakeDriver struct {
+	mu          sync.Mutex
+	statements  []string
+	failCommits int
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+func (d *fakeDriver) record(statement string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, statement)
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.driver.record("BEGIN")
+	return &fakeTx{driver: c.driver}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.driver.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+type fakeTx struct {
+	driver *fakeDriver
+}
+
+func (t *fakeTx) Commit() error {
+	t.driver.record("COMMIT")
+	t.driver.mu.Lock()
+	defer t.driver.mu.Unlock()
+	if t.driver.failCommits > 0 {
+		t.driver.failCommits--
+		return sqlStateError("40001")
+	}
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.driver.record("ROLLBACK")
+	return nil
+}
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+var (
+	testDriver   = &fakeDriver{}
+	registerOnce sync.Once
+)
+
+func newTestDB(t *testing.T) *DB {
+	registerOnce.Do(func() {
+		sql.Register("fakedb", testDriver)
+	})
+
+	testDriver.mu.Lock()
+	testDriver.statements = nil
+	testDriver.failCommits = 0
+	testDriver.mu.Unlock()
+
+	database, err := NewDBBuilder().WithDriver("fakedb", "").WithMaxOpenConns(1).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func statements() []string {
+	testDriver.mu.Lock()
+	defer testDriver.mu.Unlock()
+	return append([]string(nil), testDriver.statements...)
+}
+
+func assertStatements(t *testing.T, expected ...string) {
+	t.Helper()
+	got := statements()
+	if len(got) != len(expected) {
+		t.Fatalf("Expected statements %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("Expected statements %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestWithTransaction_Commit(t *testing.T) {
+	database := newTestDB(t)
+
+	err := database.WithTransaction(context.Background(), nil, func(ctx context.Context, tx *Tx) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO users VALUES (1)")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction failed: %v", err)
+	}
+
+	assertStatements(t, "BEGIN", "INSERT INTO users VALUES (1)", "COMMIT")
+}
+
+func TestWithTransaction_RollbackOnError(t *testing.T) {
+	database := newTestDB(t)
+	expected := errors.New("boom")
+
+	err := database.WithTransaction(context.Background(), nil, func(ctx context.Context, tx *Tx) error {
+		return expected
+	})
+	if !errors.Is(err, expected) {
+		t.Fatalf("Expected %v, got %v", expected, err)
+	}
+
+	assertStatements(t, "BEGIN", "ROLLBACK")
+}
+
+func TestWithTransaction_RetriesSerializationFailure(t *testing.T) {
+	database := newTestDB(t)
+	testDriver.failCommits = 2
+
+	attempts := 0
+	err := database.WithTransaction(context.Background(), &TxOptions{
+		Retry: &helpers.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, BackoffFactor: 2.0},
+	}, func(ctx context.Context, tx *Tx) error {
+		attempts++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction failed: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestWithTransaction_NestedSavepoint(t *testing.T) {
+	database := newTestDB(t)
+	inner := errors.New("inner failed")
+
+	err := database.WithTransaction(context.Background(), nil, func(ctx context.Context, tx *Tx) error {
+		err := database.WithTransaction(ctx, nil, func(ctx context.Context, tx *Tx) error {
+			return inner
+		})
+		if !errors.Is(err, inner) {
+			t.Errorf("Expected inner error, got %v", err)
+		}
+		_, err = database.Executor(ctx).ExecContext(ctx, "UPDATE users SET active = true")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction failed: %v", err)
+	}
+
+	assertStatements(t, "BEGIN", "SAVEPOINT sp_1", "ROLLBACK TO SAVEPOINT sp_1", "UPDATE users SET active = true", "COMMIT")
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	if !IsRetryableTxError(sqlStateError("40P01")) {
+		t.Error("Expected deadlock to be retryable")
+	}
+	if IsRetryableTxError(sqlStateError("23505")) {
+		t.Error("Expected unique violation not to be retryable")
+	}
+	if IsRetryableTxError(errors.New("plain")) {
+		t.Error("Expected plain error not to be retryable")
+	}
+}
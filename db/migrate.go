@@ -0,0 +1,318 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+)
+
+// ErrDirtyMigration is returned when a previous migration was interrupted
+// and the schema must be repaired manually before continuing
+var ErrDirtyMigration = errors.New("db: database has a dirty migration")
+
+// ErrChecksumMismatch is returned when an applied migration file has been modified
+var ErrChecksumMismatch = errors.New("db: migration checksum mismatch")
+
+// Migration is a single versioned SQL migration
+type Migration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	SQL       string
+	AppliedAt time.Time
+	Applied   bool
+	Dirty     bool
+}
+
+// MigrateConfig holds configuration for the migration runner
+type MigrateConfig struct {
+	Table   string
+	Dir     string
+	LockKey int64
+}
+
+// MigrateOption is a function type for configuring migrations
+type MigrateOption func(*MigrateConfig)
+
+// WithMigrationsTable sets the table that records applied migrations
+func WithMigrationsTable(table string) MigrateOption {
+	return func(config *MigrateConfig) {
+		config.Table = table
+	}
+}
+
+// WithMigrationsDir sets the directory within the file system containing the migrations
+func WithMigrationsDir(dir string) MigrateOption {
+	return func(config *MigrateConfig) {
+		config.Dir = dir
+	}
+}
+
+// WithMigrationLockKey sets the advisory lock key used to serialize migrators
+func WithMigrationLockKey(key int64) MigrateOption {
+	return func(config *MigrateConfig) {
+		config.LockKey = key
+	}
+}
+
+// newMigrateConfig applies options over the defaults
+func newMigrateConfig(options []MigrateOption) MigrateConfig {
+	config := MigrateConfig{
+		Table:   "schema_migrations",
+		Dir:     ".",
+		LockKey: 7_461_238_402,
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+	return config
+}
+
+// Migrate applies pending migrations from migrations in version order and
+// returns the ones that were applied.
+//
+// Migration files are named <version>_<name>.sql (or .up.sql); files ending
+// in .down.sql are ignored. Each migration runs in its own transaction.
+// A PostgreSQL advisory lock ensures that only one instance migrates at a
+// time, so it is safe to call at startup from every replica.
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	applied, err := database.Migrate(ctx, migrations, db.WithMigrationsDir("migrations"))
+func (d *DB) Migrate(ctx context.Context, migrations fs.FS, options ...MigrateOption) ([]Migration, error) {
+	config := newMigrateConfig(options)
+
+	files, err := loadMigrations(migrations, config.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", config.LockKey); err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", config.LockKey)
+
+	if err := ensureMigrationsTable(ctx, conn, config.Table); err != nil {
+		return nil, err
+	}
+
+	status, err := mergeStatus(ctx, conn, config.Table, files)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, m := range status {
+		if m.Applied {
+			continue
+		}
+
+		start := time.Now()
+		if err := applyMigration(ctx, conn, config.Table, m); err != nil {
+			return applied, err
+		}
+		m.Applied = true
+		m.AppliedAt = time.Now()
+		applied = append(applied, m)
+
+		if logger.Logger != nil {
+			logger.Info("Applied database migration",
+				zap.Int64("version", m.Version),
+				zap.String("name", m.Name),
+				zap.Duration("duration", time.Since(start)),
+			)
+		}
+	}
+
+	return applied, nil
+}
+
+// MigrationStatus returns every known migration with its applied state,
+// without applying anything. Useful for a --migrate-status CLI flag.
+func (d *DB) MigrationStatus(ctx context.Context, migrations fs.FS, options ...MigrateOption) ([]Migration, error) {
+	config := newMigrateConfig(options)
+
+	files, err := loadMigrations(migrations, config.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if err := ensureMigrationsTable(ctx, conn, config.Table); err != nil {
+		return nil, err
+	}
+
+	return mergeStatus(ctx, conn, config.Table, files)
+}
+
+// ForceMigration clears the dirty flag of a migration after the schema has
+// been repaired manually, marking it as applied
+func (d *DB) ForceMigration(ctx context.Context, version int64, options ...MigrateOption) error {
+	config := newMigrateConfig(options)
+
+	query := fmt.Sprintf("UPDATE %s SET dirty = false WHERE version = $1", config.Table)
+	result, err := d.db.ExecContext(ctx, query, version)
+	if err != nil {
+		return fmt.Errorf("failed to force migration: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("db: migration %d not found", version)
+	}
+	return nil
+}
+
+// loadMigrations reads and sorts migration files from the file system
+func loadMigrations(migrations fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(migrations, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var files []Migration
+	seen := make(map[int64]string)
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") || strings.HasSuffix(name, ".down.sql") {
+			continue
+		}
+
+		base := strings.TrimSuffix(strings.TrimSuffix(name, ".sql"), ".up")
+		versionPart, label, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(versionPart, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %q: missing numeric version prefix", name)
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, other, name)
+		}
+		seen[version] = name
+
+		content, err := fs.ReadFile(migrations, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+
+		sum := sha256.Sum256(content)
+		files = append(files, Migration{
+			Version:  version,
+			Name:     label,
+			Checksum: hex.EncodeToString(sum[:]),
+			SQL:      string(content),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Version < files[j].Version })
+	return files, nil
+}
+
+// ensureMigrationsTable creates the migrations table if it does not exist
+func ensureMigrationsTable(ctx context.Context, conn *sql.Conn, table string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version    BIGINT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		checksum   TEXT NOT NULL,
+		dirty      BOOLEAN NOT NULL DEFAULT false,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`, table)
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	return nil
+}
+
+// mergeStatus combines migration files with the applied rows, validating checksums
+func mergeStatus(ctx context.Context, conn *sql.Conn, table string, files []Migration) ([]Migration, error) {
+	query := fmt.Sprintf("SELECT version, checksum, dirty, applied_at FROM %s", table)
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]Migration)
+	for rows.Next() {
+		var m Migration
+		if err := rows.Scan(&m.Version, &m.Checksum, &m.Dirty, &m.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan migration: %w", err)
+		}
+		if m.Dirty {
+			return nil, fmt.Errorf("%w: version %d", ErrDirtyMigration, m.Version)
+		}
+		applied[m.Version] = m
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, m := range files {
+		row, ok := applied[m.Version]
+		if !ok {
+			continue
+		}
+		if row.Checksum != m.Checksum {
+			return nil, fmt.Errorf("%w: version %d (%s)", ErrChecksumMismatch, m.Version, m.Name)
+		}
+		files[i].Applied = true
+		files[i].AppliedAt = row.AppliedAt
+	}
+
+	return files, nil
+}
+
+// applyMigration runs a migration in a transaction, recording it as dirty
+// beforehand so that a crash mid-migration is detected on the next run
+func applyMigration(ctx context.Context, conn *sql.Conn, table string, m Migration) error {
+	insert := fmt.Sprintf("INSERT INTO %s (version, name, checksum, dirty) VALUES ($1, $2, $3, true)", table)
+	if _, err := conn.ExecContext(ctx, insert, m.Version, m.Name, m.Checksum); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", m.Version, err)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d: %w", m.Version, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+		_ = tx.Rollback()
+
+		// The transaction rolled back cleanly, so the schema is not dirty
+		remove := fmt.Sprintf("DELETE FROM %s WHERE version = $1", table)
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), remove, m.Version)
+		return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", m.Version, err)
+	}
+
+	update := fmt.Sprintf("UPDATE %s SET dirty = false, applied_at = now() WHERE version = $1", table)
+	if _, err := conn.ExecContext(ctx, update, m.Version); err != nil {
+		return fmt.Errorf("failed to mark migration %d as applied: %w", m.Version, err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/khekrn/core/clock"
+	"github.com/khekrn/core/helpers"
+)
+
+type contextKey int
+
+const (
+	txKey contextKey = iota
+)
+
+// TxOptions configures a transaction started by WithTransaction
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	Retry     *helpers.RetryPolicy
+}
+
+// defaultTxRetry is used when TxOptions.Retry is nil
+var defaultTxRetry = helpers.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     1 * time.Second,
+	BackoffFactor:  2.0,
+}
+
+// WithTransaction runs fn inside a transaction, committing when fn returns nil
+// and rolling back otherwise (including on panic).
+//
+// Serialization failures and deadlocks abort the whole transaction and are
+// retried with backoff according to opts.Retry, so fn must be safe to run
+// more than once. When called with a context that already carries a
+// transaction, fn runs inside a savepoint of the outer transaction instead
+// and is never retried on its own.
+func (d *DB) WithTransaction(ctx context.Context, opts *TxOptions, fn func(ctx context.Context, tx *Tx) error) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.withSavepoint(ctx, fn)
+	}
+
+	if opts == nil {
+		opts = &TxOptions{}
+	}
+	retry := defaultTxRetry
+	if opts.Retry != nil {
+		retry = *opts.Retry
+	}
+
+	var err error
+	var delay time.Duration
+	for attempt := 0; attempt < max(retry.MaxAttempts, 1); attempt++ {
+		if attempt > 0 {
+			delay = retry.Backoff(attempt, delay)
+			select {
+			case <-clock.OrSystem(retry.Clock).After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		err = d.runTransaction(ctx, opts, fn)
+		if err == nil || !IsRetryableTxError(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("transaction failed after %d attempts: %w", retry.MaxAttempts, err)
+}
+
+// runTransaction executes a single transaction attempt
+func (d *DB) runTransaction(ctx context.Context, opts *TxOptions, fn func(ctx context.Context, tx *Tx) error) (err error) {
+	tx, err := d.BeginTx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey, tx), tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// withSavepoint runs fn inside a savepoint of an existing transaction
+func (t *Tx) withSavepoint(ctx context.Context, fn func(ctx context.Context, tx *Tx) error) (err error) {
+	t.savepoints++
+	name := fmt.Sprintf("sp_%d", t.savepoints)
+
+	if _, err := t.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = t.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			panic(p)
+		}
+	}()
+
+	if err := fn(ctx, t); err != nil {
+		if _, rbErr := t.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back to savepoint: %w", rbErr))
+		}
+		return err
+	}
+
+	if _, err := t.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+// TxFromContext returns the transaction started by WithTransaction, if any
+func TxFromContext(ctx context.Context) (*Tx, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	tx, ok := ctx.Value(txKey).(*Tx)
+	return tx, ok
+}
+
+// Executor returns the transaction carried by ctx, or the DB itself when
+// there is none. Repositories can use it to take part in a caller's
+// transaction without changing their signatures.
+func (d *DB) Executor(ctx context.Context) Querier {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	return d
+}
+
+// IsRetryableTxError reports whether err is a serialization failure or
+// deadlock that can be resolved by retrying the whole transaction.
+// Drivers exposing the SQLSTATE via a SQLState() method (pgx, lib/pq) are supported.
+func IsRetryableTxError(err error) bool {
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		switch stateErr.SQLState() {
+		case "40001", // serialization_failure
+			"40P01": // deadlock_detected
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+// Package errors provides CodedError, an error carrying a response code,
+// HTTP status, client-facing message, internal cause, and the stack where it
+// was created, so services share one error taxonomy.
+//
+// The package re-exports Is, As, Unwrap, and Join from the standard library,
+// so it can replace the standard errors import.
+//
+// Example usage:
+//
+//	user, err := store.Find(ctx, id)
+//	if err == sql.ErrNoRows {
+//		return nil, errors.NotFound("User not found")
+//	}
+//	if err != nil {
+//		return nil, errors.Wrap(err, response.CodeUnavailable, "User store unavailable")
+//	}
+//
+//	// In the handler, the code, status, and message reach the client while
+//	// the cause and stack are only logged
+//	logger.Error("Get user failed", zap.Error(err))
+//	response.WriteError(w, r, err)
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/khekrn/core/response"
+)
+
+// Standard library functions, re-exported so this package can replace the
+// errors import
+var (
+	Is     = errors.Is
+	As     = errors.As
+	Unwrap = errors.Unwrap
+	Join   = errors.Join
+)
+
+// maxStackDepth is the number of frames captured per error
+const maxStackDepth = 32
+
+// CodedError is an error with a response code. Message is sent to clients,
+// while Cause and the stack are kept for logging.
+type CodedError struct {
+	Code       string // Machine-readable error code, e.g. ERR_NOT_FOUND
+	HTTPStatus int    // HTTP status code
+	Message    string // Client-facing message
+	Data       any    // Optional response data
+	Cause      error  // Wrapped cause
+	stack      []uintptr
+}
+
+// New creates a CodedError with the HTTP status registered for code,
+// capturing the caller's stack
+func New(code string, message string) *CodedError {
+	return newCoded(code, message, nil)
+}
+
+// Newf creates a CodedError with a formatted message
+func Newf(code string, format string, args ...any) *CodedError {
+	return newCoded(code, fmt.Sprintf(format, args...), nil)
+}
+
+// Wrap wraps err in a CodedError, or returns nil when err is nil. The stack
+// of a CodedError already in the chain is kept, so the trace points at the
+// origin of the failure.
+func Wrap(err error, code string, message string) error {
+	if err == nil {
+		return nil
+	}
+	return newCoded(code, message, err)
+}
+
+// Wrapf wraps err in a CodedError with a formatted message, or returns nil
+// when err is nil
+func Wrapf(err error, code string, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return newCoded(code, fmt.Sprintf(format, args...), err)
+}
+
+// BadRequest creates an ERR_BAD_REQUEST error
+func BadRequest(message string) *CodedError {
+	return newCoded(response.CodeBadRequest, message, nil)
+}
+
+// Unauthorized creates an ERR_UNAUTHORIZED error
+func Unauthorized(message string) *CodedError {
+	return newCoded(response.CodeUnauthorized, message, nil)
+}
+
+// Forbidden creates an ERR_FORBIDDEN error
+func Forbidden(message string) *CodedError {
+	return newCoded(response.CodeForbidden, message, nil)
+}
+
+// NotFound creates an ERR_NOT_FOUND error
+func NotFound(message string) *CodedError {
+	return newCoded(response.CodeNotFound, message, nil)
+}
+
+// Conflict creates an ERR_CONFLICT error
+func Conflict(message string) *CodedError {
+	return newCoded(response.CodeConflict, message, nil)
+}
+
+// Internal wraps err in an ERR_INTERNAL error with the code's description as
+// message, so the cause never reaches clients
+func Internal(err error) *CodedError {
+	info, _ := response.LookupCode(response.CodeInternal)
+	return newCoded(response.CodeInternal, info.Description, err)
+}
+
+// newCoded creates a CodedError, capturing the stack of the exported
+// function's caller
+func newCoded(code string, message string, cause error) *CodedError {
+	coded := &CodedError{
+		Code:       code,
+		HTTPStatus: response.HTTPStatusForCode(code),
+		Message:    message,
+		Cause:      cause,
+	}
+	var inner *CodedError
+	if errors.As(cause, &inner) && len(inner.stack) > 0 {
+		coded.stack = inner.stack
+	} else {
+		pcs := make([]uintptr, maxStackDepth)
+		coded.stack = pcs[:runtime.Callers(3, pcs)]
+	}
+	return coded
+}
+
+// WithStatus sets the HTTP status, overriding the one registered for the code
+func (e *CodedError) WithStatus(status int) *CodedError {
+	e.HTTPStatus = status
+	return e
+}
+
+// WithData sets the response data, e.g. validation errors
+func (e *CodedError) WithData(data any) *CodedError {
+	e.Data = data
+	return e
+}
+
+// Error implements the error interface
+func (e *CodedError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the wrapped cause
+func (e *CodedError) Unwrap() error {
+	return e.Cause
+}
+
+// APIError converts the error for response.FromError, which makes
+// response.WriteError send the code, status, message, and data
+func (e *CodedError) APIError() *response.APIError {
+	return &response.APIError{
+		HTTPStatus: e.HTTPStatus,
+		Code:       e.Code,
+		Message:    e.Message,
+		Data:       e.Data,
+		Err:        e,
+	}
+}
+
+// StackTrace returns the stack where the error was created, one
+// "function\n\tfile:line" frame per line pair like runtime/debug.Stack. The
+// logger's error reporter uses it in place of the logging call's stack.
+func (e *CodedError) StackTrace() string {
+	var sb strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		sb.WriteString(frame.Function)
+		sb.WriteString("\n\t")
+		sb.WriteString(frame.File)
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(frame.Line))
+		sb.WriteByte('\n')
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// Format implements fmt.Formatter. %+v prints the error followed by its
+// stack, which zap.Error logs as the "errorVerbose" field.
+func (e *CodedError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		io.WriteString(s, e.Error())
+		if s.Flag('+') {
+			io.WriteString(s, "\n")
+			io.WriteString(s, e.StackTrace())
+		}
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// CodeOf returns the code of the first CodedError in err's chain, or "" when
+// there is none
+func CodeOf(err error) string {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return ""
+}
+
+// HasCode reports whether a CodedError in err's chain has code
+func HasCode(err error, code string) bool {
+	for err != nil {
+		var coded *CodedError
+		if !errors.As(err, &coded) {
+			return false
+		}
+		if coded.Code == code {
+			return true
+		}
+		err = coded.Cause
+	}
+	return false
+}
+
+// HTTPStatusOf returns the HTTP status err is sent with by
+// response.WriteError, or 200 when err is nil
+func HTTPStatusOf(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	return response.FromError(err).HTTPStatus
+}
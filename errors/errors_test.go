@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/khekrn/core/response"
+)
+
+func TestNew(t *testing.T) {
+	err := NotFound("User not found")
+	if err.Code != response.CodeNotFound || err.HTTPStatus != http.StatusNotFound {
+		t.Errorf("Unexpected error %+v", err)
+	}
+	if err.Error() != "ERR_NOT_FOUND: User not found" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+	if !strings.Contains(err.StackTrace(), "TestNew") {
+		t.Errorf("Expected the stack to start at the caller, got %s", err.StackTrace())
+	}
+
+	custom := Newf("ERR_PAYMENT", "Order %d needs payment", 7).WithStatus(http.StatusPaymentRequired)
+	if custom.Message != "Order 7 needs payment" || custom.HTTPStatus != http.StatusPaymentRequired {
+		t.Errorf("Unexpected error %+v", custom)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if Wrap(nil, response.CodeInternal, "ignored") != nil || Wrapf(nil, response.CodeInternal, "%s", "ignored") != nil {
+		t.Error("Expected nil for a nil error")
+	}
+
+	err := Wrap(sql.ErrNoRows, response.CodeNotFound, "User not found")
+	if !Is(err, sql.ErrNoRows) {
+		t.Error("Expected the cause to be in the chain")
+	}
+	var coded *CodedError
+	if !As(err, &coded) || coded.Cause != sql.ErrNoRows {
+		t.Errorf("Expected a CodedError, got %v", err)
+	}
+
+	origin := Conflict("Version mismatch")
+	outer := Wrapf(fmt.Errorf("save: %w", origin), response.CodeUnavailable, "Retry %s", "later")
+	if !As(outer, &coded) || coded.StackTrace() != origin.StackTrace() {
+		t.Error("Expected the origin's stack to be kept")
+	}
+	if CodeOf(outer) != response.CodeUnavailable || !HasCode(outer, response.CodeConflict) || HasCode(outer, response.CodeNotFound) {
+		t.Errorf("Unexpected codes in %v", outer)
+	}
+	if CodeOf(sql.ErrNoRows) != "" {
+		t.Error("Expected no code for a plain error")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	err := BadRequest("Bad input")
+	if fmt.Sprintf("%v", err) != err.Error() || fmt.Sprintf("%s", err) != err.Error() {
+		t.Errorf("Unexpected formatting %v", err)
+	}
+	if verbose := fmt.Sprintf("%+v", err); !strings.HasPrefix(verbose, err.Error()+"\n") || !strings.Contains(verbose, "TestFormat") {
+		t.Errorf("Expected the stack with %%+v, got %s", verbose)
+	}
+}
+
+func TestResponseIntegration(t *testing.T) {
+	err := fmt.Errorf("get user: %w", Internal(sql.ErrConnDone))
+	apiErr := response.FromError(err)
+	if apiErr.Code != response.CodeInternal || apiErr.Message != "An unexpected error occurred" {
+		t.Errorf("Unexpected APIError %+v", apiErr)
+	}
+	if !Is(apiErr, sql.ErrConnDone) {
+		t.Error("Expected the cause to stay in the chain")
+	}
+
+	rec := httptest.NewRecorder()
+	response.WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil),
+		Forbidden("Not your order").WithData(map[string]string{"order_id": "o-1"}))
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), `"order_id":"o-1"`) {
+		t.Errorf("Unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+
+	if HTTPStatusOf(Unauthorized("Log in")) != http.StatusUnauthorized || HTTPStatusOf(nil) != http.StatusOK {
+		t.Error("Unexpected HTTP status")
+	}
+}
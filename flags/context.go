@@ -0,0 +1,43 @@
+package flags
+
+import (
+	"context"
+	"maps"
+)
+
+// overridesKey is the context key for per-request overrides
+type overridesKey struct{}
+
+// WithOverrides returns a context in which the given flags have fixed
+// values, taking precedence over the provider, e.g. to force a flag for a
+// test account or an internal header. Overrides already in ctx are kept
+// unless overridden again.
+//
+// Example:
+//
+//	if user.IsStaff {
+//		ctx = flags.WithOverrides(ctx, map[string]any{"new-checkout": true})
+//	}
+func WithOverrides(ctx context.Context, overrides map[string]any) context.Context {
+	merged := make(map[string]any, len(overrides))
+	if parent, ok := ctx.Value(overridesKey{}).(map[string]any); ok {
+		maps.Copy(merged, parent)
+	}
+	maps.Copy(merged, overrides)
+	return context.WithValue(ctx, overridesKey{}, merged)
+}
+
+// WithOverride returns a context in which key has a fixed value
+func WithOverride(ctx context.Context, key string, value any) context.Context {
+	return WithOverrides(ctx, map[string]any{key: value})
+}
+
+// overrideFromContext returns the override of key in ctx
+func overrideFromContext(ctx context.Context, key string) (any, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	overrides, _ := ctx.Value(overridesKey{}).(map[string]any)
+	value, ok := overrides[key]
+	return value, ok
+}
@@ -0,0 +1,26 @@
+package flags
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithOverrides(t *testing.T) {
+	f := New(NewStaticProvider(map[string]any{"new-checkout": false, "theme": "light"}))
+
+	ctx := WithOverrides(context.Background(), map[string]any{"new-checkout": true, "theme": "dark"})
+	child := WithOverride(ctx, "theme", "contrast")
+
+	if !f.Bool(ctx, "new-checkout", false) || f.String(ctx, "theme", "") != "dark" {
+		t.Error("Expected overrides to take precedence over the provider")
+	}
+	if !f.Bool(child, "new-checkout", false) || f.String(child, "theme", "") != "contrast" {
+		t.Error("Expected the child context to keep and replace parent overrides")
+	}
+	if f.String(ctx, "theme", "") != "dark" {
+		t.Error("Expected the parent context to be unchanged")
+	}
+	if f.Bool(context.Background(), "new-checkout", true) {
+		t.Error("Expected the provider value without overrides")
+	}
+}
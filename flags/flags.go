@@ -0,0 +1,245 @@
+// Package flags provides feature flags with pluggable providers, typed
+// accessors, per-request overrides, and change notifications.
+//
+// Static, environment, and file providers are built in; remote services
+// such as LaunchDarkly or Unleash plug in by implementing Provider, and
+// Chain combines providers in order of precedence.
+//
+// Example usage:
+//
+//	file := flags.NewFileProvider("flags.yaml")
+//	go file.Watch(ctx, 30*time.Second)
+//	flags.SetProvider(flags.Chain(flags.NewEnvProvider("FLAG_"), file))
+//
+//	if flags.Bool(ctx, "new-checkout", false) {
+//		return newCheckout(ctx, cart)
+//	}
+//
+//	flags.OnChange("max-cart-items", func(key string) {
+//		logger.Info("Flag changed", zap.String("flag", key))
+//	})
+package flags
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+)
+
+// Provider looks up raw flag values. Values are typically bool, string,
+// int, or float64; strings are parsed by the typed accessors. ctx carries
+// request data, such as the user or tenant ID, for targeting.
+type Provider interface {
+	Lookup(ctx context.Context, key string) (any, bool)
+}
+
+// Notifier is implemented by providers that report changes. Subscribe
+// registers fn to be called with the keys that changed.
+type Notifier interface {
+	Subscribe(fn func(keys []string))
+}
+
+// Flags evaluates flags from a provider
+type Flags struct {
+	provider Provider
+
+	mu        sync.RWMutex
+	listeners map[string][]func(key string) // Keyed by flag; "" receives every change
+}
+
+// New creates Flags backed by provider, subscribing to its changes when it
+// is a Notifier
+func New(provider Provider) *Flags {
+	f := &Flags{provider: provider, listeners: make(map[string][]func(string))}
+	if notifier, ok := provider.(Notifier); ok {
+		notifier.Subscribe(f.notify)
+	}
+	return f
+}
+
+// Lookup returns the raw value of key, preferring overrides in ctx
+func (f *Flags) Lookup(ctx context.Context, key string) (any, bool) {
+	if value, ok := overrideFromContext(ctx, key); ok {
+		return value, true
+	}
+	if f == nil || f.provider == nil {
+		return nil, false
+	}
+	return f.provider.Lookup(ctx, key)
+}
+
+// Bool returns the flag as a bool, or def when it is undefined or invalid
+func (f *Flags) Bool(ctx context.Context, key string, def bool) bool {
+	return get(f, ctx, key, def, func(value any) (bool, error) {
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+		return false, fmt.Errorf("unexpected %T", value)
+	})
+}
+
+// String returns the flag as a string, or def when it is undefined
+func (f *Flags) String(ctx context.Context, key string, def string) string {
+	return get(f, ctx, key, def, func(value any) (string, error) {
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+		return fmt.Sprint(value), nil
+	})
+}
+
+// Int returns the flag as an int, or def when it is undefined or invalid
+func (f *Flags) Int(ctx context.Context, key string, def int) int {
+	return get(f, ctx, key, def, func(value any) (int, error) {
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case float64:
+			if v == float64(int(v)) {
+				return int(v), nil
+			}
+		case string:
+			return strconv.Atoi(v)
+		}
+		return 0, fmt.Errorf("unexpected %T %v", value, value)
+	})
+}
+
+// Float returns the flag as a float64, or def when it is undefined or invalid
+func (f *Flags) Float(ctx context.Context, key string, def float64) float64 {
+	return get(f, ctx, key, def, func(value any) (float64, error) {
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case string:
+			return strconv.ParseFloat(v, 64)
+		}
+		return 0, fmt.Errorf("unexpected %T", value)
+	})
+}
+
+// Duration returns the flag as a duration such as "250ms", or def when it
+// is undefined or invalid
+func (f *Flags) Duration(ctx context.Context, key string, def time.Duration) time.Duration {
+	return get(f, ctx, key, def, func(value any) (time.Duration, error) {
+		switch v := value.(type) {
+		case time.Duration:
+			return v, nil
+		case string:
+			return time.ParseDuration(v)
+		}
+		return 0, fmt.Errorf("unexpected %T", value)
+	})
+}
+
+// OnChange calls fn with the key when the flag changes in the provider; an
+// empty key receives every change. Overrides in contexts do not notify.
+func (f *Flags) OnChange(key string, fn func(key string)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listeners[key] = append(f.listeners[key], fn)
+}
+
+// notify calls the listeners of the changed keys
+func (f *Flags) notify(keys []string) {
+	f.mu.RLock()
+	var calls []func()
+	for _, key := range keys {
+		for _, fn := range f.listeners[key] {
+			calls = append(calls, func() { fn(key) })
+		}
+		for _, fn := range f.listeners[""] {
+			calls = append(calls, func() { fn(key) })
+		}
+	}
+	f.mu.RUnlock()
+
+	for _, call := range calls {
+		call()
+	}
+}
+
+// get looks up key and converts it, logging invalid values and falling
+// back to def
+func get[T any](f *Flags, ctx context.Context, key string, def T, convert func(any) (T, error)) T {
+	value, ok := f.Lookup(ctx, key)
+	if !ok {
+		return def
+	}
+	converted, err := convert(value)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Invalid feature flag value; using default",
+			zap.String("flag", key), zap.Any("value", value), zap.Error(err))
+		return def
+	}
+	return converted
+}
+
+// global holds the Flags used by the package-level functions
+var global atomic.Pointer[Flags]
+
+func init() {
+	global.Store(New(NewStaticProvider(nil)))
+}
+
+// SetProvider replaces the provider used by the package-level functions.
+// Listeners registered with OnChange are kept.
+func SetProvider(provider Provider) {
+	next := New(provider)
+	previous := global.Swap(next)
+	previous.mu.RLock()
+	for key, fns := range previous.listeners {
+		next.listeners[key] = append(next.listeners[key], fns...)
+	}
+	previous.mu.RUnlock()
+}
+
+// Default returns the Flags used by the package-level functions
+func Default() *Flags {
+	return global.Load()
+}
+
+// Bool returns the flag as a bool from the default Flags
+func Bool(ctx context.Context, key string, def bool) bool {
+	return Default().Bool(ctx, key, def)
+}
+
+// String returns the flag as a string from the default Flags
+func String(ctx context.Context, key string, def string) string {
+	return Default().String(ctx, key, def)
+}
+
+// Int returns the flag as an int from the default Flags
+func Int(ctx context.Context, key string, def int) int {
+	return Default().Int(ctx, key, def)
+}
+
+// Float returns the flag as a float64 from the default Flags
+func Float(ctx context.Context, key string, def float64) float64 {
+	return Default().Float(ctx, key, def)
+}
+
+// Duration returns the flag as a duration from the default Flags
+func Duration(ctx context.Context, key string, def time.Duration) time.Duration {
+	return Default().Duration(ctx, key, def)
+}
+
+// OnChange registers a change listener on the default Flags
+func OnChange(key string, fn func(key string)) {
+	Default().OnChange(key, fn)
+}
@@ -0,0 +1,105 @@
+package flags
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFlags_TypedAccessors(t *testing.T) {
+	f := New(NewStaticProvider(map[string]any{
+		"new-checkout":   true,
+		"legacy-search":  "false",
+		"theme":          "dark",
+		"max-cart-items": 50,
+		"page-size":      float64(25),
+		"sample-rate":    0.25,
+		"timeout":        "250ms",
+		"broken":         "maybe",
+	}))
+	ctx := context.Background()
+
+	if !f.Bool(ctx, "new-checkout", false) || f.Bool(ctx, "legacy-search", true) || !f.Bool(ctx, "missing", true) {
+		t.Error("Unexpected bool values")
+	}
+	if f.String(ctx, "theme", "light") != "dark" || f.String(ctx, "max-cart-items", "") != "50" {
+		t.Error("Unexpected string values")
+	}
+	if f.Int(ctx, "max-cart-items", 0) != 50 || f.Int(ctx, "page-size", 0) != 25 || f.Int(ctx, "sample-rate", 7) != 7 {
+		t.Error("Unexpected int values")
+	}
+	if f.Float(ctx, "sample-rate", 0) != 0.25 || f.Float(ctx, "max-cart-items", 0) != 50 {
+		t.Error("Unexpected float values")
+	}
+	if f.Duration(ctx, "timeout", 0) != 250*time.Millisecond || f.Duration(ctx, "theme", time.Second) != time.Second {
+		t.Error("Unexpected duration values")
+	}
+	if f.Bool(ctx, "broken", true) != true {
+		t.Error("Expected the default for an invalid value")
+	}
+}
+
+func TestFlags_OnChange(t *testing.T) {
+	provider := NewStaticProvider(map[string]any{"new-checkout": false})
+	f := New(provider)
+
+	var mu sync.Mutex
+	var changed, all []string
+	f.OnChange("new-checkout", func(key string) {
+		mu.Lock()
+		defer mu.Unlock()
+		changed = append(changed, key)
+	})
+	f.OnChange("", func(key string) {
+		mu.Lock()
+		defer mu.Unlock()
+		all = append(all, key)
+	})
+
+	provider.Set("new-checkout", true)
+	provider.Set("new-checkout", true) // Unchanged
+	provider.Set("theme", "dark")
+	provider.Delete("theme")
+	provider.Delete("missing")
+
+	if len(changed) != 1 || changed[0] != "new-checkout" {
+		t.Errorf("Expected one change of new-checkout, got %v", changed)
+	}
+	if len(all) != 3 {
+		t.Errorf("Expected three changes, got %v", all)
+	}
+	if !f.Bool(context.Background(), "new-checkout", false) {
+		t.Error("Expected the new value")
+	}
+}
+
+func TestSetProvider(t *testing.T) {
+	previous := Default()
+	defer global.Store(previous)
+
+	var calls int
+	OnChange("beta", func(string) { calls++ })
+
+	provider := NewStaticProvider(map[string]any{"beta": "true"})
+	SetProvider(provider)
+	if !Bool(context.Background(), "beta", false) || String(context.Background(), "beta", "") != "true" {
+		t.Error("Expected the new provider to be used")
+	}
+
+	provider.Set("beta", false)
+	if calls != 1 {
+		t.Errorf("Expected listeners to be kept across providers, got %d calls", calls)
+	}
+	if Int(context.Background(), "missing", 3) != 3 || Float(context.Background(), "missing", 1.5) != 1.5 ||
+		Duration(context.Background(), "missing", time.Second) != time.Second {
+		t.Error("Expected defaults for missing flags")
+	}
+}
+
+func TestFlags_NilProvider(t *testing.T) {
+	var f *Flags
+	if f.Bool(context.Background(), "new-checkout", true) != true {
+		t.Error("Expected the default from nil Flags")
+	}
+}
@@ -0,0 +1,241 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// subscribers implements Notifier for the built-in providers
+type subscribers struct {
+	mu  sync.Mutex
+	fns []func(keys []string)
+}
+
+// Subscribe registers fn to be called with changed keys
+func (s *subscribers) Subscribe(fn func(keys []string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fns = append(s.fns, fn)
+}
+
+// publish calls the subscribers when keys is not empty
+func (s *subscribers) publish(keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	s.mu.Lock()
+	fns := append([]func([]string){}, s.fns...)
+	s.mu.Unlock()
+	for _, fn := range fns {
+		fn(keys)
+	}
+}
+
+// StaticProvider holds flags in memory. Set and Delete notify subscribers,
+// which makes it useful in tests and for flags managed by an admin API.
+type StaticProvider struct {
+	subscribers
+
+	mu     sync.RWMutex
+	values map[string]any
+}
+
+// NewStaticProvider creates a provider with the given values
+func NewStaticProvider(values map[string]any) *StaticProvider {
+	copied := make(map[string]any, len(values))
+	for key, value := range values {
+		copied[key] = value
+	}
+	return &StaticProvider{values: copied}
+}
+
+// Lookup returns the value of key
+func (p *StaticProvider) Lookup(ctx context.Context, key string) (any, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	value, ok := p.values[key]
+	return value, ok
+}
+
+// Set sets the value of key
+func (p *StaticProvider) Set(key string, value any) {
+	p.mu.Lock()
+	previous, existed := p.values[key]
+	p.values[key] = value
+	p.mu.Unlock()
+
+	if !existed || !reflect.DeepEqual(previous, value) {
+		p.publish([]string{key})
+	}
+}
+
+// Delete removes key
+func (p *StaticProvider) Delete(key string) {
+	p.mu.Lock()
+	_, existed := p.values[key]
+	delete(p.values, key)
+	p.mu.Unlock()
+
+	if existed {
+		p.publish([]string{key})
+	}
+}
+
+// EnvProvider reads flags from environment variables named by the prefix
+// and the upper-case key, with dashes and dots as underscores, e.g.
+// FLAG_NEW_CHECKOUT for "new-checkout" with prefix "FLAG_". Values are
+// strings parsed by the typed accessors.
+type EnvProvider struct {
+	prefix string
+}
+
+// NewEnvProvider creates a provider reading variables starting with prefix
+func NewEnvProvider(prefix string) *EnvProvider {
+	return &EnvProvider{prefix: prefix}
+}
+
+// Lookup returns the environment variable for key
+func (p *EnvProvider) Lookup(ctx context.Context, key string) (any, bool) {
+	name := p.prefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+	return os.LookupEnv(name)
+}
+
+// FileProvider reads flags from a flat YAML or JSON object of keys to
+// values, e.g. "new-checkout: true". Reload re-reads the file and notifies
+// subscribers of the keys that changed; Watch does so periodically.
+type FileProvider struct {
+	subscribers
+	path string
+
+	mu       sync.RWMutex
+	values   map[string]any
+	modified time.Time
+}
+
+// NewFileProvider creates a provider for the file at path. The file is read
+// on creation; read errors are logged and leave the provider empty until a
+// successful Reload.
+func NewFileProvider(path string) *FileProvider {
+	p := &FileProvider{path: path, values: map[string]any{}}
+	if err := p.Reload(); err != nil {
+		logger.Warn("Failed to load feature flags", zap.String("path", path), zap.Error(err))
+	}
+	return p
+}
+
+// Lookup returns the value of key
+func (p *FileProvider) Lookup(ctx context.Context, key string) (any, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	value, ok := p.values[key]
+	return value, ok
+}
+
+// Reload re-reads the file. On error the previous values are kept.
+func (p *FileProvider) Reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+	values := map[string]any{}
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", p.path, err)
+	}
+
+	p.mu.Lock()
+	changed := changedKeys(p.values, values)
+	p.values = values
+	p.modified = info.ModTime()
+	p.mu.Unlock()
+
+	p.publish(changed)
+	return nil
+}
+
+// Watch reloads the file every interval when its modification time changes,
+// until ctx is done. Reload errors are logged and the previous values kept.
+func (p *FileProvider) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(p.path)
+		p.mu.RLock()
+		unchanged := err == nil && info.ModTime().Equal(p.modified)
+		p.mu.RUnlock()
+		if unchanged {
+			continue
+		}
+		if err := p.Reload(); err != nil {
+			logger.FromContext(ctx).Warn("Failed to reload feature flags", zap.String("path", p.path), zap.Error(err))
+		}
+	}
+}
+
+// changedKeys returns the sorted keys whose values differ between before and after
+func changedKeys(before, after map[string]any) []string {
+	var keys []string
+	for key, value := range after {
+		if previous, ok := before[key]; !ok || !reflect.DeepEqual(previous, value) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// chain looks flags up in several providers
+type chain struct {
+	providers []Provider
+}
+
+// Chain returns a provider that looks flags up in providers in order and
+// returns the first defined value, e.g. environment overrides before a
+// remote service before a file of defaults. It notifies subscribers of the
+// changes of every provider that is a Notifier.
+func Chain(providers ...Provider) Provider {
+	return &chain{providers: providers}
+}
+
+// Lookup returns the first defined value of key
+func (c *chain) Lookup(ctx context.Context, key string) (any, bool) {
+	for _, provider := range c.providers {
+		if value, ok := provider.Lookup(ctx, key); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// Subscribe registers fn with every provider that is a Notifier
+func (c *chain) Subscribe(fn func(keys []string)) {
+	for _, provider := range c.providers {
+		if notifier, ok := provider.(Notifier); ok {
+			notifier.Subscribe(fn)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package flags
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("FLAG_NEW_CHECKOUT", "true")
+	t.Setenv("FLAG_SEARCH_PAGE_SIZE", "40")
+
+	f := New(NewEnvProvider("FLAG_"))
+	ctx := context.Background()
+	if !f.Bool(ctx, "new-checkout", false) || f.Int(ctx, "search.page-size", 0) != 40 {
+		t.Error("Expected flags from the environment")
+	}
+	if _, ok := f.Lookup(ctx, "missing"); ok {
+		t.Error("Expected missing variables to be undefined")
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	if err := os.WriteFile(path, []byte("new-checkout: true\nmax-cart-items: 50\ntheme: dark\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewFileProvider(path)
+	f := New(provider)
+	ctx := context.Background()
+	if !f.Bool(ctx, "new-checkout", false) || f.Int(ctx, "max-cart-items", 0) != 50 {
+		t.Error("Expected flags from the file")
+	}
+
+	var changed []string
+	f.OnChange("", func(key string) { changed = append(changed, key) })
+
+	if err := os.WriteFile(path, []byte(`{"new-checkout": true, "max-cart-items": 75}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if len(changed) != 2 || changed[0] != "max-cart-items" || changed[1] != "theme" {
+		t.Errorf("Expected max-cart-items and theme to change, got %v", changed)
+	}
+
+	if err := os.WriteFile(path, []byte("new-checkout: [unclosed"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.Reload(); err == nil {
+		t.Error("Expected a parse error")
+	}
+	if f.Int(ctx, "max-cart-items", 0) != 75 {
+		t.Error("Expected previous values to be kept after a failed reload")
+	}
+
+	if _, ok := NewFileProvider(filepath.Join(t.TempDir(), "missing.yaml")).Lookup(ctx, "new-checkout"); ok {
+		t.Error("Expected a missing file to define no flags")
+	}
+}
+
+func TestFileProvider_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	if err := os.WriteFile(path, []byte("new-checkout: false\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider := NewFileProvider(path)
+
+	changed := make(chan []string, 1)
+	provider.Subscribe(func(keys []string) { changed <- keys })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go provider.Watch(ctx, 10*time.Millisecond)
+
+	later := time.Now().Add(time.Second)
+	if err := os.WriteFile(path, []byte("new-checkout: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case keys := <-changed:
+		if len(keys) != 1 || keys[0] != "new-checkout" {
+			t.Errorf("Unexpected changed keys %v", keys)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Watch to reload the file")
+	}
+}
+
+func TestChain(t *testing.T) {
+	overrides := NewStaticProvider(map[string]any{"theme": "dark"})
+	defaults := NewStaticProvider(map[string]any{"theme": "light", "new-checkout": true})
+	f := New(Chain(overrides, NewEnvProvider("FLAG_TEST_CHAIN_"), defaults))
+	ctx := context.Background()
+
+	if f.String(ctx, "theme", "") != "dark" || !f.Bool(ctx, "new-checkout", false) {
+		t.Error("Expected the first provider defining a flag to win")
+	}
+
+	var mu sync.Mutex
+	var changed []string
+	f.OnChange("", func(key string) {
+		mu.Lock()
+		defer mu.Unlock()
+		changed = append(changed, key)
+	})
+	overrides.Set("theme", "contrast")
+	defaults.Set("new-checkout", false)
+	if len(changed) != 2 {
+		t.Errorf("Expected changes from every notifier, got %v", changed)
+	}
+}
@@ -0,0 +1,215 @@
+package grpcserver
+
+import (
+	"context"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/khekrn/core/idgen"
+	"github.com/khekrn/core/logger"
+	_ "github.com/khekrn/core/logger/datadog" // dd.trace_id in logs when EnableDatadog is set
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDHeader is the metadata key carrying the request ID
+const RequestIDHeader = "x-request-id"
+
+// Services exempt from authentication
+var publicServices = []string{"/grpc.health.v1.Health/", "/grpc.reflection."}
+
+// interceptorChain holds the state shared by the standard interceptors
+type interceptorChain struct {
+	server   *Server
+	authFunc AuthFunc
+	tracer   trace.Tracer
+}
+
+// newInterceptorChain creates the standard interceptor chain
+func newInterceptorChain(server *Server, authFunc AuthFunc, provider trace.TracerProvider) *interceptorChain {
+	c := &interceptorChain{server: server, authFunc: authFunc}
+	if provider != nil {
+		c.tracer = provider.Tracer("github.com/khekrn/core/grpcserver")
+	}
+	return c
+}
+
+// unary returns the unary interceptors in execution order
+func (c *interceptorChain) unary() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+			err = c.observe(ctx, info.FullMethod, func(ctx context.Context) error {
+				ctx, err := c.authenticate(ctx, info.FullMethod)
+				if err != nil {
+					return err
+				}
+				resp, err = handler(ctx, req)
+				return err
+			})
+			return resp, err
+		},
+	}
+}
+
+// stream returns the stream interceptors in execution order
+func (c *interceptorChain) stream() []grpc.StreamServerInterceptor {
+	return []grpc.StreamServerInterceptor{
+		func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return c.observe(ss.Context(), info.FullMethod, func(ctx context.Context) error {
+				ctx, err := c.authenticate(ctx, info.FullMethod)
+				if err != nil {
+					return err
+				}
+				return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+			})
+		},
+	}
+}
+
+// observe wraps a call with request ID propagation, tracing, logging,
+// metrics, and panic recovery
+func (c *interceptorChain) observe(ctx context.Context, method string, call func(ctx context.Context) error) (err error) {
+	requestID := incomingRequestID(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, requestID))
+	ctx = logger.WithRequestID(ctx, requestID)
+
+	var ddSpan *tracer.Span
+	if c.server.config.EnableDatadog {
+		ddSpan, ctx = tracer.StartSpanFromContext(ctx, "grpc.server",
+			tracer.ServiceName(c.server.config.ServiceName),
+			tracer.ResourceName(method),
+			tracer.SpanType(ext.AppTypeRPC),
+			tracer.Tag("request_id", requestID),
+		)
+	}
+
+	var otelSpan trace.Span
+	if c.tracer != nil {
+		ctx, otelSpan = c.tracer.Start(ctx, strings.TrimPrefix(method, "/"),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.method", method),
+			),
+		)
+	}
+
+	c.server.inFlight.Add(1)
+	start := time.Now()
+
+	defer func() {
+		if p := recover(); p != nil {
+			c.server.panics.Add(1)
+			logger.FromContext(ctx).Error("Panic in gRPC handler",
+				zap.String("method", method),
+				zap.Any("panic", p),
+				zap.ByteString("stack", debug.Stack()),
+			)
+			err = status.Error(codes.Internal, "internal error")
+		}
+
+		duration := time.Since(start)
+		code := status.Code(err)
+		c.server.inFlight.Add(-1)
+		c.server.requests.Add(1)
+		if isServerError(code) {
+			c.server.errors.Add(1)
+		}
+
+		if ddSpan != nil {
+			ddSpan.SetTag("grpc.code", code.String())
+			if isServerError(code) {
+				ddSpan.Finish(tracer.WithError(err))
+			} else {
+				ddSpan.Finish()
+			}
+		}
+		if otelSpan != nil {
+			otelSpan.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+			if isServerError(code) {
+				otelSpan.RecordError(err)
+				otelSpan.SetStatus(otelcodes.Error, err.Error())
+			}
+			otelSpan.End()
+		}
+
+		fields := []zap.Field{
+			zap.String("method", method),
+			zap.String("code", code.String()),
+			zap.Duration("duration", duration),
+		}
+		log := logger.FromContext(ctx)
+		switch {
+		case isServerError(code):
+			log.Error("gRPC call failed", append(fields, zap.Error(err))...)
+		case code != codes.OK:
+			log.Warn("gRPC call rejected", append(fields, zap.Error(err))...)
+		default:
+			log.Info("gRPC call", fields...)
+		}
+	}()
+
+	return call(ctx)
+}
+
+// authenticate runs the auth hook unless the method is exempt
+func (c *interceptorChain) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if c.authFunc == nil || slices.Contains(c.server.config.SkipAuthMethods, method) {
+		return ctx, nil
+	}
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+
+	authCtx, err := c.authFunc(ctx, method)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return authCtx, nil
+}
+
+// incomingRequestID returns the caller's request ID or generates a new one
+func incomingRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDHeader); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return idgen.RequestID()
+}
+
+// isServerError reports whether code indicates a server-side failure
+func isServerError(code codes.Code) bool {
+	switch code {
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss, codes.Unimplemented, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// contextStream overrides the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the enriched context
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,160 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// testService is a minimal hand-written service used to exercise the interceptors
+type testService interface {
+	Ping(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error)
+	Panic(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error)
+}
+
+type testServer struct{}
+
+func (testServer) Ping(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (testServer) Panic(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error) {
+	panic("boom")
+}
+
+func unaryHandler(call func(testService, context.Context, *emptypb.Empty) (*emptypb.Empty, error), method string) grpc.MethodHandler {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		req := new(emptypb.Empty)
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: method}
+		return interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+			return call(srv.(testService), ctx, req.(*emptypb.Empty))
+		})
+	}
+}
+
+var testServiceDesc = grpc.ServiceDesc{
+	ServiceName: "core.test.TestService",
+	HandlerType: (*testService)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Ping", Handler: unaryHandler(testService.Ping, "/core.test.TestService/Ping")},
+		{MethodName: "Panic", Handler: unaryHandler(testService.Panic, "/core.test.TestService/Panic")},
+	},
+}
+
+func startServer(t *testing.T, builder *ServerBuilder) (*Server, *grpc.ClientConn) {
+	t.Helper()
+	server, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	server.GRPC().RegisterService(&testServiceDesc, testServer{})
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.GRPC().Stop()
+	})
+	return server, conn
+}
+
+func TestServer_HealthAndRequestID(t *testing.T) {
+	server, conn := startServer(t, NewServerBuilder())
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Health check failed: %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING, got %v", resp.Status)
+	}
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), RequestIDHeader, "req-123")
+	if err := conn.Invoke(ctx, "/core.test.TestService/Ping", &emptypb.Empty{}, &emptypb.Empty{}, grpc.Header(&header)); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if got := header.Get(RequestIDHeader); len(got) != 1 || got[0] != "req-123" {
+		t.Errorf("Expected request ID to be echoed, got %v", got)
+	}
+	if m := server.Metrics(); m.Requests != 2 || m.InFlight != 0 {
+		t.Errorf("Unexpected metrics %+v", m)
+	}
+}
+
+func TestServer_Recovery(t *testing.T) {
+	server, conn := startServer(t, NewServerBuilder())
+
+	err := conn.Invoke(context.Background(), "/core.test.TestService/Panic", &emptypb.Empty{}, &emptypb.Empty{})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("Expected Internal, got %v", err)
+	}
+	if m := server.Metrics(); m.Panics != 1 || m.Errors != 1 {
+		t.Errorf("Unexpected metrics %+v", m)
+	}
+}
+
+func TestServer_Authentication(t *testing.T) {
+	builder := NewServerBuilder().WithAuthFunc(func(ctx context.Context, fullMethod string) (context.Context, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if len(md.Get("authorization")) == 0 {
+			return nil, errors.New("missing credentials")
+		}
+		return ctx, nil
+	})
+	_, conn := startServer(t, builder)
+
+	err := conn.Invoke(context.Background(), "/core.test.TestService/Ping", &emptypb.Empty{}, &emptypb.Empty{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("Expected Unauthenticated, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token")
+	if err := conn.Invoke(ctx, "/core.test.TestService/Ping", &emptypb.Empty{}, &emptypb.Empty{}); err != nil {
+		t.Fatalf("Expected authenticated call to succeed, got %v", err)
+	}
+
+	// Health checks are exempt from authentication
+	if _, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("Expected health check to bypass auth, got %v", err)
+	}
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	server, conn := startServer(t, NewServerBuilder())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	err := conn.Invoke(context.Background(), "/core.test.TestService/Ping", &emptypb.Empty{}, &emptypb.Empty{})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable after shutdown, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MaxBinaryJSONSize limits the encoded length, in bytes, that Base64Bytes,
+// Base64URLBytes, and HexBytes accept when unmarshaling. Larger values fail
+// with a *JSONLimitError before anything is decoded. Zero disables the limit.
+var MaxBinaryJSONSize int64 = 10 << 20
+
+// Base64Bytes is a []byte that marshals as standard padded base64.
+// Unmarshaling also accepts unpadded input.
+type Base64Bytes []byte
+
+// MarshalJSON encodes the bytes as a base64 string, or null when nil
+func (b Base64Bytes) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(base64.StdEncoding.EncodeToString(b))
+}
+
+// UnmarshalJSON decodes a base64 string
+func (b *Base64Bytes) UnmarshalJSON(data []byte) error {
+	decoded, err := unmarshalBinary(data, "base64", func(s string) ([]byte, error) {
+		return base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+	})
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// Base64URLBytes is a []byte that marshals as unpadded URL-safe base64, as
+// used by JWTs. Unmarshaling also accepts padded input.
+type Base64URLBytes []byte
+
+// MarshalJSON encodes the bytes as a URL-safe base64 string, or null when nil
+func (b Base64URLBytes) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+// UnmarshalJSON decodes a URL-safe base64 string
+func (b *Base64URLBytes) UnmarshalJSON(data []byte) error {
+	decoded, err := unmarshalBinary(data, "base64url", func(s string) ([]byte, error) {
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	})
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// HexBytes is a []byte that marshals as lowercase hex, e.g. for hashes.
+// Unmarshaling also accepts uppercase digits.
+type HexBytes []byte
+
+// MarshalJSON encodes the bytes as a hex string, or null when nil
+func (b HexBytes) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(hex.EncodeToString(b))
+}
+
+// UnmarshalJSON decodes a hex string
+func (b *HexBytes) UnmarshalJSON(data []byte) error {
+	decoded, err := unmarshalBinary(data, "hex", hex.DecodeString)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// unmarshalBinary checks the size limit, then decodes a JSON string with
+// decode. null decodes to nil.
+func unmarshalBinary(data []byte, encoding string, decode func(string) ([]byte, error)) ([]byte, error) {
+	if string(data) == "null" {
+		return nil, nil
+	}
+	if MaxBinaryJSONSize > 0 && int64(len(data)) > MaxBinaryJSONSize+2 {
+		return nil, &JSONLimitError{Limit: MaxBinaryJSONSize, Err: ErrJSONTooLarge}
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return nil, fmt.Errorf("invalid %s value: %w", encoding, err)
+	}
+	decoded, err := decode(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value: %w", encoding, err)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,72 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestBinaryJSONTypes(t *testing.T) {
+	raw := []byte{0xfb, 0xff, 0x01}
+	value := struct {
+		Std  Base64Bytes    `json:"std"`
+		URL  Base64URLBytes `json:"url"`
+		Hex  HexBytes       `json:"hex"`
+		None HexBytes       `json:"none"`
+	}{raw, raw, raw, nil}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	expected := `{"std":"+/8B","url":"-_8B","hex":"fbff01","none":null}`
+	if string(data) != expected {
+		t.Fatalf("Expected %s, got %s", expected, data)
+	}
+
+	var decoded struct {
+		Std  Base64Bytes    `json:"std"`
+		URL  Base64URLBytes `json:"url"`
+		Hex  HexBytes       `json:"hex"`
+		None HexBytes       `json:"none"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !bytes.Equal(decoded.Std, raw) || !bytes.Equal(decoded.URL, raw) || !bytes.Equal(decoded.Hex, raw) || decoded.None != nil {
+		t.Errorf("Unexpected round trip: %+v", decoded)
+	}
+}
+
+func TestBinaryJSONTypes_Lenient(t *testing.T) {
+	var std Base64Bytes
+	if err := json.Unmarshal([]byte(`"aGk"`), &std); err != nil || string(std) != "hi" {
+		t.Errorf("Expected unpadded base64 to decode, got %q, %v", std, err)
+	}
+	var url Base64URLBytes
+	if err := json.Unmarshal([]byte(`"aGk="`), &url); err != nil || string(url) != "hi" {
+		t.Errorf("Expected padded base64url to decode, got %q, %v", url, err)
+	}
+	var hexBytes HexBytes
+	if err := json.Unmarshal([]byte(`"FBFF"`), &hexBytes); err != nil || !bytes.Equal(hexBytes, []byte{0xfb, 0xff}) {
+		t.Errorf("Expected uppercase hex to decode, got %x, %v", hexBytes, err)
+	}
+	if err := json.Unmarshal([]byte(`"xyz"`), &hexBytes); err == nil {
+		t.Error("Expected invalid hex to fail")
+	}
+}
+
+func TestBinaryJSONTypes_SizeLimit(t *testing.T) {
+	original := MaxBinaryJSONSize
+	MaxBinaryJSONSize = 4
+	defer func() { MaxBinaryJSONSize = original }()
+
+	var b HexBytes
+	if err := json.Unmarshal([]byte(`"aabb"`), &b); err != nil {
+		t.Errorf("Expected value at the limit to decode, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`"aabbcc"`), &b); !errors.Is(err, ErrJSONTooLarge) {
+		t.Errorf("Expected ErrJSONTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CanonicalJSON converts any value to deterministic JSON suitable for hashing,
+// signing and snapshot tests. Output follows RFC 8785 (JSON Canonicalization
+// Scheme): object keys are sorted, there is no whitespace or HTML escaping,
+// and non-integer numbers use the shortest round-trip form. Integers keep
+// their exact digits, so 64-bit IDs are never rounded.
+func CanonicalJSON[T any](data T) ([]byte, error) {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	var doc interface{}
+	if err := decodeJSON(encoded.Bytes(), &doc); err != nil {
+		return nil, fmt.Errorf("failed to canonicalize JSON: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, doc); err != nil {
+		return nil, fmt.Errorf("failed to canonicalize JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// CanonicalizeJSON rewrites JSON bytes in the canonical form produced by CanonicalJSON
+func CanonicalizeJSON(jsonData []byte) ([]byte, error) {
+	if !json.Valid(jsonData) {
+		return nil, fmt.Errorf("invalid JSON data")
+	}
+	return CanonicalJSON(json.RawMessage(jsonData))
+}
+
+// writeCanonical writes a decoded JSON value to buf in canonical form
+func writeCanonical(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case string:
+		writeCanonicalString(buf, v)
+	case json.Number:
+		number, err := canonicalNumber(v)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(number)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return utf16Less(keys[i], keys[j]) })
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, key)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, v[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unexpected JSON value of type %T", value)
+	}
+	return nil
+}
+
+// canonicalNumber formats a number the way ECMAScript does, keeping
+// integer literals verbatim
+func canonicalNumber(n json.Number) (string, error) {
+	text := n.String()
+	if !strings.ContainsAny(text, ".eE") {
+		if text == "-0" {
+			return "0", nil
+		}
+		return text, nil
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return "", fmt.Errorf("invalid number %q: %w", text, err)
+	}
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", fmt.Errorf("number %q is out of range", text)
+	}
+	if f == 0 {
+		return "0", nil
+	}
+
+	if abs := math.Abs(f); abs >= 1e-6 && abs < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	}
+	formatted := strconv.FormatFloat(f, 'e', -1, 64)
+	// ECMAScript writes exponents without leading zeros, e.g. 1e-7 rather than 1e-07
+	mantissa, exponent, _ := strings.Cut(formatted, "e")
+	sign, digits := exponent[:1], strings.TrimLeft(exponent[1:], "0")
+	return mantissa + "e" + sign + digits, nil
+}
+
+// writeCanonicalString writes s as a JSON string, escaping only what RFC 8785 requires
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[c>>4])
+				buf.WriteByte(hex[c&0xf])
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// utf16Less orders strings by their UTF-16 code units, as RFC 8785 requires
+func utf16Less(a, b string) bool {
+	ar, br := []rune(a), []rune(b)
+	for i := 0; i < len(ar) && i < len(br); i++ {
+		if ar[i] == br[i] {
+			continue
+		}
+		if ua, ub := utf16Unit(ar[i]), utf16Unit(br[i]); ua != ub {
+			return ua < ub
+		}
+		return ar[i] < br[i]
+	}
+	return len(ar) < len(br)
+}
+
+// utf16Unit returns the first UTF-16 code unit of r
+func utf16Unit(r rune) rune {
+	if r >= 0x10000 {
+		return 0xD800 + ((r - 0x10000) >> 10)
+	}
+	return r
+}
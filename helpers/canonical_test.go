@@ -0,0 +1,81 @@
+package helpers
+
+import "testing"
+
+func TestCanonicalJSON(t *testing.T) {
+	type Item struct {
+		Zeta  string  `json:"zeta"`
+		Alpha float64 `json:"alpha"`
+	}
+
+	tests := []struct {
+		name     string
+		data     interface{}
+		expected string
+	}{
+		{"sorted map keys", map[string]int{"b": 2, "a": 1, "c": 3}, `{"a":1,"b":2,"c":3}`},
+		{"sorted struct fields", Item{Zeta: "z", Alpha: 1.5}, `{"alpha":1.5,"zeta":"z"}`},
+		{"no HTML escaping", map[string]string{"html": "<a href='x'>&</a>"}, `{"html":"<a href='x'>&</a>"}`},
+		{"control characters", "tab\there\u0001", `"tab\there\u0001"`},
+		{"nested", map[string]interface{}{"list": []interface{}{map[string]int{"y": 1, "x": 2}}, "empty": map[string]int{}}, `{"empty":{},"list":[{"x":2,"y":1}]}`},
+		{"utf16 key order", map[string]int{"\U0001F600": 1, "ﬁ": 2}, `{"😀":1,"ﬁ":2}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CanonicalJSON(tt.data)
+			if err != nil {
+				t.Fatalf("CanonicalJSON failed: %v", err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeJSON_Numbers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`12345678901234567890`, `12345678901234567890`},
+		{`-0`, `0`},
+		{`1.0`, `1`},
+		{`0.0`, `0`},
+		{`1.50`, `1.5`},
+		{`1e2`, `100`},
+		{`1e21`, `1e+21`},
+		{`1e-7`, `1e-7`},
+		{`0.000001`, `0.000001`},
+		{`-2.5E-10`, `-2.5e-10`},
+	}
+
+	for _, tt := range tests {
+		result, err := CanonicalizeJSON([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("CanonicalizeJSON(%s) failed: %v", tt.input, err)
+		}
+		if string(result) != tt.expected {
+			t.Errorf("CanonicalizeJSON(%s): expected %s, got %s", tt.input, tt.expected, result)
+		}
+	}
+}
+
+func TestCanonicalizeJSON_Stable(t *testing.T) {
+	a, err := CanonicalizeJSON([]byte(`{ "b": [1, 2], "a": {"d": true, "c": null} }`))
+	if err != nil {
+		t.Fatalf("CanonicalizeJSON failed: %v", err)
+	}
+	b, err := CanonicalizeJSON([]byte(`{"a":{"c":null,"d":true},"b":[1,2]}`))
+	if err != nil {
+		t.Fatalf("CanonicalizeJSON failed: %v", err)
+	}
+	if string(a) != string(b) {
+		t.Errorf("Expected identical output, got %s and %s", a, b)
+	}
+
+	if _, err := CanonicalizeJSON([]byte(`{invalid`)); err == nil {
+		t.Error("Expected invalid JSON to fail")
+	}
+}
@@ -0,0 +1,161 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// errNotReflectCopyable makes DeepCopy fall back to a JSON round trip
+var errNotReflectCopyable = errors.New("value cannot be copied via reflection")
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// DeepCopy returns a copy of v that shares no maps, slices, or pointers with
+// the original, so mutating one never affects the other. Values made of
+// exported fields, maps, slices, pointers, and interfaces are copied via
+// reflection, preserving shared and cyclic pointers. Values that reflection
+// cannot copy safely, such as structs with unexported reference fields, fall
+// back to a JSON round trip, which keeps only what encoding/json marshals.
+func DeepCopy[T any](v T) (T, error) {
+	copier := deepCopier{pointers: make(map[uintptr]reflect.Value)}
+	source := reflect.ValueOf(&v).Elem()
+	target := reflect.New(source.Type()).Elem()
+	if err := copier.copy(target, source); err == nil {
+		return target.Interface().(T), nil
+	}
+
+	var result T
+	data, err := json.Marshal(v)
+	if err != nil {
+		return result, fmt.Errorf("failed to deep copy %T: %w", v, err)
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("failed to deep copy %T: %w", v, err)
+	}
+	return result, nil
+}
+
+// deepCopier copies values recursively, remembering pointers already copied
+type deepCopier struct {
+	pointers map[uintptr]reflect.Value
+}
+
+// copy deep copies source into the settable target of the same type
+func (c *deepCopier) copy(target, source reflect.Value) error {
+	switch source.Kind() {
+	case reflect.Pointer:
+		if source.IsNil() {
+			return nil
+		}
+		if copied, ok := c.pointers[source.Pointer()]; ok {
+			target.Set(copied)
+			return nil
+		}
+		copied := reflect.New(source.Type().Elem())
+		c.pointers[source.Pointer()] = copied
+		target.Set(copied)
+		return c.copy(copied.Elem(), source.Elem())
+
+	case reflect.Interface:
+		if source.IsNil() {
+			return nil
+		}
+		elem := source.Elem()
+		copied := reflect.New(elem.Type()).Elem()
+		if err := c.copy(copied, elem); err != nil {
+			return err
+		}
+		target.Set(copied)
+		return nil
+
+	case reflect.Slice:
+		if source.IsNil() {
+			return nil
+		}
+		target.Set(reflect.MakeSlice(source.Type(), source.Len(), source.Cap()))
+		for i := range source.Len() {
+			if err := c.copy(target.Index(i), source.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Array:
+		for i := range source.Len() {
+			if err := c.copy(target.Index(i), source.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		if source.IsNil() {
+			return nil
+		}
+		target.Set(reflect.MakeMapWithSize(source.Type(), source.Len()))
+		iter := source.MapRange()
+		for iter.Next() {
+			value := reflect.New(source.Type().Elem()).Elem()
+			if err := c.copy(value, iter.Value()); err != nil {
+				return err
+			}
+			target.SetMapIndex(iter.Key(), value)
+		}
+		return nil
+
+	case reflect.Struct:
+		target.Set(source)
+		if source.Type() == timeType {
+			return nil
+		}
+		for i := range source.NumField() {
+			field := source.Type().Field(i)
+			if !field.IsExported() {
+				if hasReferences(field.Type, make(map[reflect.Type]bool)) {
+					return errNotReflectCopyable
+				}
+				continue
+			}
+			if err := c.copy(target.Field(i), source.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if source.IsNil() {
+			return nil
+		}
+		return errNotReflectCopyable
+
+	default:
+		target.Set(source)
+		return nil
+	}
+}
+
+// hasReferences reports whether values of t can share memory when assigned
+func hasReferences(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if t == timeType || seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map,
+		reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	case reflect.Array:
+		return hasReferences(t.Elem(), seen)
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if hasReferences(t.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
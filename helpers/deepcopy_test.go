@@ -0,0 +1,82 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type copyConfig struct {
+	Name     string
+	Limits   map[string]int
+	Hosts    []string
+	Backup   *copyConfig
+	Extra    any
+	Created  time.Time
+	retries  int
+	Callback func()
+}
+
+func TestDeepCopy(t *testing.T) {
+	original := copyConfig{
+		Name:    "primary",
+		Limits:  map[string]int{"rps": 10},
+		Hosts:   []string{"a", "b"},
+		Backup:  &copyConfig{Name: "backup", Hosts: []string{"c"}},
+		Extra:   map[string]any{"tags": []any{"x"}},
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		retries: 3,
+	}
+
+	copied, err := DeepCopy(original)
+	if err != nil {
+		t.Fatalf("DeepCopy failed: %v", err)
+	}
+	if !reflect.DeepEqual(copied, original) {
+		t.Fatalf("Expected equal copy, got %+v", copied)
+	}
+
+	copied.Limits["rps"] = 99
+	copied.Hosts[0] = "z"
+	copied.Backup.Hosts[0] = "z"
+	copied.Extra.(map[string]any)["tags"].([]any)[0] = "z"
+
+	if original.Limits["rps"] != 10 || original.Hosts[0] != "a" || original.Backup.Hosts[0] != "c" ||
+		original.Extra.(map[string]any)["tags"].([]any)[0] != "x" {
+		t.Errorf("Mutating the copy changed the original: %+v", original)
+	}
+}
+
+func TestDeepCopy_Cycle(t *testing.T) {
+	node := &copyConfig{Name: "loop"}
+	node.Backup = node
+
+	copied, err := DeepCopy(node)
+	if err != nil {
+		t.Fatalf("DeepCopy failed: %v", err)
+	}
+	if copied == node || copied.Backup != copied {
+		t.Error("Expected an independent copy that preserves the cycle")
+	}
+}
+
+type copySecret struct {
+	Public string `json:"public"`
+	cache  map[string]string
+}
+
+func TestDeepCopy_JSONFallback(t *testing.T) {
+	original := copySecret{Public: "visible", cache: map[string]string{"k": "v"}}
+
+	copied, err := DeepCopy(original)
+	if err != nil {
+		t.Fatalf("DeepCopy failed: %v", err)
+	}
+	if copied.Public != "visible" || copied.cache != nil {
+		t.Errorf("Expected JSON round trip result, got %+v", copied)
+	}
+
+	if _, err := DeepCopy(make(chan int)); err == nil {
+		t.Error("Expected error for uncopyable value")
+	}
+}
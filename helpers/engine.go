@@ -0,0 +1,53 @@
+package helpers
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+// Engine is the JSON encoder/decoder behind the generic helpers. The
+// standard-library-compatible configs of jsoniter
+// (jsoniter.ConfigCompatibleWithStandardLibrary) and sonic (sonic.ConfigStd)
+// satisfy it directly.
+type Engine interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StdEngine is the default engine backed by encoding/json
+var StdEngine Engine = stdEngine{}
+
+// stdEngine implements Engine with encoding/json
+type stdEngine struct{}
+
+func (stdEngine) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (stdEngine) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// engineHolder wraps an Engine so it can be stored atomically
+type engineHolder struct {
+	engine Engine
+}
+
+var currentEngine atomic.Pointer[engineHolder]
+
+// SetEngine replaces the JSON engine used by ToJSON, FromJSON, UnmarshalJSON
+// and the functions built on them; nil restores StdEngine. Call it once
+// during startup, before any JSON work begins.
+//
+// Formatting, validation and the path, diff, patch and merge helpers always
+// use encoding/json, since they depend on its token stream and json.Number.
+func SetEngine(engine Engine) {
+	if engine == nil {
+		engine = StdEngine
+	}
+	currentEngine.Store(&engineHolder{engine: engine})
+}
+
+// CurrentEngine returns the JSON engine in use
+func CurrentEngine() Engine {
+	if holder := currentEngine.Load(); holder != nil {
+		return holder.engine
+	}
+	return StdEngine
+}
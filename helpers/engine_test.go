@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// countingEngine records calls before delegating to encoding/json
+type countingEngine struct {
+	marshals   int
+	unmarshals int
+}
+
+func (e *countingEngine) Marshal(v interface{}) ([]byte, error) {
+	e.marshals++
+	return json.Marshal(v)
+}
+
+func (e *countingEngine) Unmarshal(data []byte, v interface{}) error {
+	e.unmarshals++
+	return json.Unmarshal(data, v)
+}
+
+func TestSetEngine(t *testing.T) {
+	engine := &countingEngine{}
+	SetEngine(engine)
+	t.Cleanup(func() { SetEngine(nil) })
+
+	if CurrentEngine() != engine {
+		t.Fatal("Expected CurrentEngine to return the configured engine")
+	}
+
+	user := TestStruct{ID: 1, Name: "John"}
+	data, err := ToJSON(user)
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	if _, err := FromJSON[TestStruct](data); err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+	if _, err := FromYAML[TestStruct]([]byte("id: 2\nname: Jane\n")); err != nil {
+		t.Fatalf("FromYAML failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteNDJSON(&buf, []TestStruct{user}); err != nil {
+		t.Fatalf("WriteNDJSON failed: %v", err)
+	}
+
+	if engine.marshals != 2 || engine.unmarshals != 2 {
+		t.Errorf("Expected 2 marshals and 2 unmarshals, got %d and %d", engine.marshals, engine.unmarshals)
+	}
+
+	SetEngine(nil)
+	if CurrentEngine() != StdEngine {
+		t.Error("Expected SetEngine(nil) to restore StdEngine")
+	}
+}
@@ -0,0 +1,132 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FlattenJSON flattens a JSON document into a single-level map whose keys are
+// the paths to each leaf joined with sep, e.g. {"a":{"b":[1,2]}} becomes
+// {"a.b.0": 1, "a.b.1": 2}. Empty objects and arrays are kept as leaf values
+// so UnflattenJSON can restore them. Numbers are returned as json.Number.
+func FlattenJSON(jsonData []byte, sep string) (map[string]interface{}, error) {
+	if sep == "" {
+		return nil, fmt.Errorf("separator must not be empty")
+	}
+
+	var doc interface{}
+	if err := decodeJSON(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	flat := make(map[string]interface{})
+	switch doc.(type) {
+	case map[string]interface{}, []interface{}:
+		flatten(flat, "", doc, sep)
+	default:
+		return nil, fmt.Errorf("JSON document must be an object or array")
+	}
+	return flat, nil
+}
+
+// UnflattenJSON reverses FlattenJSON, rebuilding nested JSON from a flat map
+// of sep-joined paths. A container whose keys are exactly 0..n-1 becomes an
+// array; any other container becomes an object.
+func UnflattenJSON(flat map[string]interface{}, sep string) ([]byte, error) {
+	if sep == "" {
+		return nil, fmt.Errorf("separator must not be empty")
+	}
+
+	root := make(map[string]interface{})
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		segments := strings.Split(key, sep)
+		node := root
+		for i, segment := range segments[:len(segments)-1] {
+			child, exists := node[segment]
+			if !exists {
+				child = make(map[string]interface{})
+				node[segment] = child
+			}
+			next, ok := child.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("key %q conflicts with value at %q", key, strings.Join(segments[:i+1], sep))
+			}
+			node = next
+		}
+
+		last := segments[len(segments)-1]
+		if existing, exists := node[last]; exists {
+			if _, isContainer := existing.(map[string]interface{}); isContainer {
+				return nil, fmt.Errorf("key %q conflicts with nested keys below it", key)
+			}
+		}
+		node[last] = flat[key]
+	}
+
+	return json.Marshal(restoreArrays(root))
+}
+
+// flatten adds the leaves of value to flat under prefix
+func flatten(flat map[string]interface{}, prefix string, value interface{}, sep string) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + sep + key
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && prefix != "" {
+			flat[prefix] = map[string]interface{}{}
+			return
+		}
+		for key, child := range v {
+			flatten(flat, join(key), child, sep)
+		}
+	case []interface{}:
+		if len(v) == 0 && prefix != "" {
+			flat[prefix] = []interface{}{}
+			return
+		}
+		for i, child := range v {
+			flatten(flat, join(strconv.Itoa(i)), child, sep)
+		}
+	default:
+		flat[prefix] = v
+	}
+}
+
+// restoreArrays converts maps keyed by 0..n-1 back into arrays
+func restoreArrays(value interface{}) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	for key, child := range m {
+		m[key] = restoreArrays(child)
+	}
+
+	if len(m) == 0 {
+		return m
+	}
+	items := make([]interface{}, len(m))
+	for key, child := range m {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(m) || strconv.Itoa(i) != key {
+			return m
+		}
+		items[i] = child
+	}
+	return items
+}
@@ -0,0 +1,75 @@
+package helpers
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFlattenJSON(t *testing.T) {
+	flat, err := FlattenJSON([]byte(`{"user":{"name":"John","tags":["a","b"],"address":{"zip":"0150"}},"id":12345678901234567890,"meta":{},"list":[]}`), ".")
+	if err != nil {
+		t.Fatalf("FlattenJSON failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"user.name":        `"John"`,
+		"user.tags.0":      `"a"`,
+		"user.tags.1":      `"b"`,
+		"user.address.zip": `"0150"`,
+		"id":               `12345678901234567890`,
+		"meta":             `{}`,
+		"list":             `[]`,
+	}
+	if len(flat) != len(expected) {
+		t.Fatalf("Expected %d keys, got %d: %v", len(expected), len(flat), flat)
+	}
+	for key, want := range expected {
+		got, _ := json.Marshal(flat[key])
+		if string(got) != want {
+			t.Errorf("Key %s: expected %s, got %s", key, want, got)
+		}
+	}
+
+	if _, ok := flat["id"].(json.Number); !ok {
+		t.Errorf("Expected json.Number for id, got %T", flat["id"])
+	}
+}
+
+func TestFlattenJSON_Errors(t *testing.T) {
+	if _, err := FlattenJSON([]byte(`{"a":1}`), ""); err == nil {
+		t.Error("Expected empty separator to fail")
+	}
+	if _, err := FlattenJSON([]byte(`42`), "."); err == nil {
+		t.Error("Expected scalar document to fail")
+	}
+	if _, err := FlattenJSON([]byte(`{invalid`), "."); err == nil {
+		t.Error("Expected invalid JSON to fail")
+	}
+}
+
+func TestUnflattenJSON_RoundTrip(t *testing.T) {
+	inputs := []string{
+		`{"user":{"name":"John","tags":["a","b"],"address":{"zip":"0150"}},"id":12345678901234567890,"meta":{},"list":[]}`,
+		`[{"a":1},{"b":[true,null]}]`,
+		`{"matrix":[[1,2],[3,4]],"sparse":{"0":"x","2":"y"}}`,
+	}
+
+	for _, input := range inputs {
+		flat, err := FlattenJSON([]byte(input), "/")
+		if err != nil {
+			t.Fatalf("FlattenJSON failed: %v", err)
+		}
+		result, err := UnflattenJSON(flat, "/")
+		if err != nil {
+			t.Fatalf("UnflattenJSON failed: %v", err)
+		}
+		assertJSONEqual(t, result, input)
+	}
+}
+
+func TestUnflattenJSON_Conflict(t *testing.T) {
+	_, err := UnflattenJSON(map[string]interface{}{"a": 1, "a.b": 2}, ".")
+	if err == nil {
+		t.Error("Expected conflicting keys to fail")
+	}
+}
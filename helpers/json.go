@@ -35,13 +35,13 @@ import (
 
 // ToJSON converts any value to JSON bytes using Go generics
 func ToJSON[T any](data T) ([]byte, error) {
-	return json.Marshal(data)
+	return CurrentEngine().Marshal(data)
 }
 
 // FromJSON converts JSON bytes to a struct and returns a pointer to the result
 func FromJSON[T any](jsonData []byte) (*T, error) {
 	var result T
-	err := json.Unmarshal(jsonData, &result)
+	err := CurrentEngine().Unmarshal(jsonData, &result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
@@ -51,7 +51,7 @@ func FromJSON[T any](jsonData []byte) (*T, error) {
 // FromJSONValue converts JSON bytes to a struct and returns the value (not pointer)
 func FromJSONValue[T any](jsonData []byte) (T, error) {
 	var result T
-	err := json.Unmarshal(jsonData, &result)
+	err := CurrentEngine().Unmarshal(jsonData, &result)
 	if err != nil {
 		return result, fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
@@ -61,7 +61,7 @@ func FromJSONValue[T any](jsonData []byte) (T, error) {
 // UnmarshalJSON unmarshals JSON bytes into the provided interface
 // This is a compatibility function for working with interface{} types
 func UnmarshalJSON(jsonData []byte, v interface{}) error {
-	return json.Unmarshal(jsonData, v)
+	return CurrentEngine().Unmarshal(jsonData, v)
 }
 
 // FromReader reads JSON from an io.Reader and converts it to a struct
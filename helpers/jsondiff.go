@@ -0,0 +1,161 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeType classifies a difference between two JSON documents
+type ChangeType string
+
+// JSON change types
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+// JSONChange is a difference at one path, using the GetPath syntax.
+// The root of the document has an empty path.
+type JSONChange struct {
+	Type ChangeType  `json:"type"`
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// JSONDiff is the list of changes between two JSON documents
+type JSONDiff []JSONChange
+
+// DiffJSON compares two JSON documents and returns the added, removed, and
+// changed paths in document order, with object keys sorted. Arrays are
+// compared index by index.
+//
+// Example:
+//
+//	diff, err := helpers.DiffJSON(before, after)
+//	for _, change := range diff {
+//		audit.Record(change.Path, change.Old, change.New)
+//	}
+//	fmt.Println(diff) // Human-readable report
+func DiffJSON(a, b []byte) (JSONDiff, error) {
+	var left, right interface{}
+	if err := json.Unmarshal(a, &left); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	if err := json.Unmarshal(b, &right); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	diff := JSONDiff{}
+	diffValues("", left, right, &diff)
+	return diff, nil
+}
+
+// String renders the diff as one line per change
+func (d JSONDiff) String() string {
+	var b strings.Builder
+	for _, change := range d {
+		path := change.Path
+		if path == "" {
+			path = "(root)"
+		}
+		switch change.Type {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "+ %s: %s\n", path, compactValue(change.New))
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "- %s: %s\n", path, compactValue(change.Old))
+		case ChangeChanged:
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", path, compactValue(change.Old), compactValue(change.New))
+		}
+	}
+	return b.String()
+}
+
+// diffValues appends the differences between left and right at path
+func diffValues(path string, left, right interface{}, diff *JSONDiff) {
+	switch l := left.(type) {
+	case map[string]interface{}:
+		r, ok := right.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(l)+len(r))
+		for key := range l {
+			keys = append(keys, key)
+		}
+		for key := range r {
+			if _, ok := l[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			lv, inLeft := l[key]
+			rv, inRight := r[key]
+			child := joinKey(path, key)
+			switch {
+			case !inRight:
+				*diff = append(*diff, JSONChange{Type: ChangeRemoved, Path: child, Old: lv})
+			case !inLeft:
+				*diff = append(*diff, JSONChange{Type: ChangeAdded, Path: child, New: rv})
+			default:
+				diffValues(child, lv, rv, diff)
+			}
+		}
+		return
+	case []interface{}:
+		r, ok := right.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < max(len(l), len(r)); i++ {
+			child := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(r):
+				*diff = append(*diff, JSONChange{Type: ChangeRemoved, Path: child, Old: l[i]})
+			case i >= len(l):
+				*diff = append(*diff, JSONChange{Type: ChangeAdded, Path: child, New: r[i]})
+			default:
+				diffValues(child, l[i], r[i], diff)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(left, right) {
+		*diff = append(*diff, JSONChange{Type: ChangeChanged, Path: path, Old: left, New: right})
+	}
+}
+
+// joinKey appends an object key to a path, quoting keys that are not plain identifiers
+func joinKey(path, key string) string {
+	plain := key != ""
+	for _, r := range key {
+		if !(r == '_' || r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			plain = false
+			break
+		}
+	}
+	switch {
+	case !plain:
+		return path + "[" + strconv.Quote(key) + "]"
+	case path == "":
+		return key
+	default:
+		return path + "." + key
+	}
+}
+
+// compactValue renders a decoded JSON value on one line
+func compactValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
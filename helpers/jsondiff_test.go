@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffJSON(t *testing.T) {
+	before := []byte(`{"name":"Ada","role":"admin","tags":["a","b"],"address":{"city":"London"},"labels":{"app.io/tier":"web"}}`)
+	after := []byte(`{"name":"Ada","tags":["a","c","d"],"address":{"city":"Paris","zip":"75001"},"labels":{"app.io/tier":"api"},"active":true}`)
+
+	diff, err := DiffJSON(before, after)
+	if err != nil {
+		t.Fatalf("DiffJSON failed: %v", err)
+	}
+
+	expected := JSONDiff{
+		{Type: ChangeAdded, Path: "active", New: true},
+		{Type: ChangeChanged, Path: "address.city", Old: "London", New: "Paris"},
+		{Type: ChangeAdded, Path: "address.zip", New: "75001"},
+		{Type: ChangeChanged, Path: `labels["app.io/tier"]`, Old: "web", New: "api"},
+		{Type: ChangeRemoved, Path: "role", Old: "admin"},
+		{Type: ChangeChanged, Path: "tags[1]", Old: "b", New: "c"},
+		{Type: ChangeAdded, Path: "tags[2]", New: "d"},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Unexpected diff:\n%v", diff)
+	}
+
+	report := diff.String()
+	if want := "~ address.city: \"London\" -> \"Paris\"\n"; !strings.Contains(report, want) {
+		t.Errorf("Expected report to contain %q, got:\n%s", want, report)
+	}
+	if want := "- role: \"admin\"\n"; !strings.Contains(report, want) {
+		t.Errorf("Expected report to contain %q, got:\n%s", want, report)
+	}
+}
+
+func TestDiffJSON_EqualAndTypeChanges(t *testing.T) {
+	diff, err := DiffJSON([]byte(`{"a":[1,{"b":2}]}`), []byte(`{ "a": [1, {"b": 2}] }`))
+	if err != nil || len(diff) != 0 {
+		t.Errorf("Expected no differences, got %v (%v)", diff, err)
+	}
+
+	diff, err = DiffJSON([]byte(`{"a":{"b":1}}`), []byte(`{"a":[1]}`))
+	if err != nil || len(diff) != 1 || diff[0].Type != ChangeChanged || diff[0].Path != "a" {
+		t.Errorf("Expected a type change at a, got %v (%v)", diff, err)
+	}
+
+	diff, err = DiffJSON([]byte(`1`), []byte(`2`))
+	if err != nil || len(diff) != 1 || diff[0].Path != "" || diff.String() != "~ (root): 1 -> 2\n" {
+		t.Errorf("Expected a root change, got %v (%v)", diff, err)
+	}
+
+	if _, err := DiffJSON([]byte(`{`), []byte(`{}`)); err == nil {
+		t.Error("Expected invalid JSON to fail")
+	}
+}
@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ArrayMergeStrategy selects how DeepMergeJSON combines arrays found at the same path
+type ArrayMergeStrategy int
+
+// Array merge strategies
+const (
+	ArrayReplace      ArrayMergeStrategy = iota // Later arrays replace earlier ones
+	ArrayAppend                                 // Later array elements are appended
+	ArrayMergeByIndex                           // Elements at the same index are deep merged
+)
+
+// DeepMergeJSON merges JSON objects recursively: nested objects are merged
+// key by key, arrays are combined according to arrays, and any other value
+// from a later object overrides the earlier one. Numbers keep their
+// original precision.
+//
+// Example:
+//
+//	merged, err := helpers.DeepMergeJSON(helpers.ArrayAppend,
+//		[]byte(`{"db":{"host":"localhost","port":5432},"tags":["a"]}`),
+//		[]byte(`{"db":{"host":"prod-db"},"tags":["b"]}`))
+//	// {"db":{"host":"prod-db","port":5432},"tags":["a","b"]}
+func DeepMergeJSON(arrays ArrayMergeStrategy, jsonObjects ...[]byte) ([]byte, error) {
+	result := map[string]interface{}{}
+	for _, jsonData := range jsonObjects {
+		var obj map[string]interface{}
+		if err := decodeJSON(jsonData, &obj); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON object: %w", err)
+		}
+		result = deepMerge(result, obj, arrays).(map[string]interface{})
+	}
+	return json.Marshal(result)
+}
+
+// deepMerge merges src into dst and returns the result
+func deepMerge(dst, src interface{}, arrays ArrayMergeStrategy) interface{} {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		d, ok := dst.(map[string]interface{})
+		if !ok {
+			return s
+		}
+		for key, value := range s {
+			if existing, ok := d[key]; ok {
+				d[key] = deepMerge(existing, value, arrays)
+			} else {
+				d[key] = value
+			}
+		}
+		return d
+	case []interface{}:
+		d, ok := dst.([]interface{})
+		if !ok {
+			return s
+		}
+		switch arrays {
+		case ArrayAppend:
+			return append(d, s...)
+		case ArrayMergeByIndex:
+			for i, value := range s {
+				if i < len(d) {
+					d[i] = deepMerge(d[i], value, arrays)
+				} else {
+					d = append(d, value)
+				}
+			}
+			return d
+		}
+	}
+	return src
+}
@@ -0,0 +1,45 @@
+package helpers
+
+import "testing"
+
+func TestDeepMergeJSON(t *testing.T) {
+	base := []byte(`{"db":{"host":"localhost","port":5432,"pool":{"max":10}},"tags":["a",{"x":1}],"debug":true}`)
+	override := []byte(`{"db":{"host":"prod-db","pool":{"min":2}},"tags":["b",{"y":2},"c"],"debug":null}`)
+
+	tests := []struct {
+		name     string
+		arrays   ArrayMergeStrategy
+		expected string
+	}{
+		{"replace arrays", ArrayReplace, `{"db":{"host":"prod-db","port":5432,"pool":{"max":10,"min":2}},"tags":["b",{"y":2},"c"],"debug":null}`},
+		{"append arrays", ArrayAppend, `{"db":{"host":"prod-db","port":5432,"pool":{"max":10,"min":2}},"tags":["a",{"x":1},"b",{"y":2},"c"],"debug":null}`},
+		{"merge arrays by index", ArrayMergeByIndex, `{"db":{"host":"prod-db","port":5432,"pool":{"max":10,"min":2}},"tags":["b",{"x":1,"y":2},"c"],"debug":null}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := DeepMergeJSON(tt.arrays, base, override)
+			if err != nil {
+				t.Fatalf("DeepMergeJSON failed: %v", err)
+			}
+			assertJSONEqual(t, merged, tt.expected)
+		})
+	}
+}
+
+func TestDeepMergeJSON_EdgeCases(t *testing.T) {
+	merged, err := DeepMergeJSON(ArrayReplace)
+	if err != nil || string(merged) != "{}" {
+		t.Errorf("Expected empty object, got %s (%v)", merged, err)
+	}
+
+	merged, err = DeepMergeJSON(ArrayReplace, []byte(`{"a":{"b":1}}`), []byte(`{"a":"flat"}`), []byte(`{"a":{"c":2}}`))
+	if err != nil {
+		t.Fatalf("DeepMergeJSON failed: %v", err)
+	}
+	assertJSONEqual(t, merged, `{"a":{"c":2}}`)
+
+	if _, err := DeepMergeJSON(ArrayReplace, []byte(`{"a":1}`), []byte(`[1]`)); err == nil {
+		t.Error("Expected non-object input to fail")
+	}
+}
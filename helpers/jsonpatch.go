@@ -0,0 +1,434 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchOperation is one RFC 6902 JSON Patch operation
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch document to doc. The patch
+// is applied atomically: if any operation fails, the error is returned and
+// no partial result. Numbers keep their original precision.
+//
+// Example:
+//
+//	patched, err := helpers.ApplyJSONPatch(order, []byte(`[
+//		{"op": "replace", "path": "/status", "value": "shipped"},
+//		{"op": "add", "path": "/tags/-", "value": "express"}
+//	]`))
+func ApplyJSONPatch(doc, patch []byte) ([]byte, error) {
+	var operations []jsonPatchOperation
+	if err := decodeJSON(patch, &operations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON patch: %w", err)
+	}
+	var document interface{}
+	if err := decodeJSON(doc, &document); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON document: %w", err)
+	}
+
+	for i, operation := range operations {
+		var err error
+		document, err = operation.apply(document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply JSON patch operation %d (%s): %w", i, operation.Op, err)
+		}
+	}
+	return json.Marshal(document)
+}
+
+// CreateJSONPatch returns an RFC 6902 JSON Patch document that transforms
+// oldDoc into newDoc. Arrays are patched index by index.
+func CreateJSONPatch(oldDoc, newDoc []byte) ([]byte, error) {
+	var left, right interface{}
+	if err := decodeJSON(oldDoc, &left); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON document: %w", err)
+	}
+	if err := decodeJSON(newDoc, &right); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON document: %w", err)
+	}
+
+	operations := []JSONPatchOperation{}
+	createPatch("", left, right, &operations)
+	return json.Marshal(operations)
+}
+
+// MarshalJSON encodes the operation, keeping a null value for add, replace, and test
+func (o JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	type operation JSONPatchOperation
+	if o.Op != "add" && o.Op != "replace" && o.Op != "test" {
+		return json.Marshal(operation(o))
+	}
+	return json.Marshal(struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// jsonPatchOperation is a decoded operation that tells a missing value from null
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// apply performs the operation on document and returns the new document
+func (o jsonPatchOperation) apply(document interface{}) (interface{}, error) {
+	if o.Path == nil {
+		return nil, fmt.Errorf("missing path")
+	}
+	path, err := parsePointer(*o.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch o.Op {
+	case "add", "replace", "test":
+		if o.Value == nil {
+			return nil, fmt.Errorf("missing value")
+		}
+		if err := decodeJSON(o.Value, &value); err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+	case "move", "copy":
+		if o.From == nil {
+			return nil, fmt.Errorf("missing from")
+		}
+	}
+
+	switch o.Op {
+	case "add":
+		return pointerAdd(document, path, value)
+	case "remove":
+		document, _, err = pointerRemove(document, path)
+		return document, err
+	case "replace":
+		if _, err := pointerGet(document, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		document, _, err = pointerRemove(document, path)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(document, path, value)
+	case "move":
+		from, err := parsePointer(*o.From)
+		if err != nil {
+			return nil, err
+		}
+		if *o.Path != *o.From && strings.HasPrefix(*o.Path, *o.From+"/") {
+			return nil, fmt.Errorf("cannot move a value into one of its children")
+		}
+		document, value, err = pointerRemove(document, from)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(document, path, value)
+	case "copy":
+		from, err := parsePointer(*o.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := pointerGet(document, from)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(document, path, deepCopyJSON(value))
+	case "test":
+		actual, err := pointerGet(document, path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(actual, value) {
+			return nil, fmt.Errorf("test failed")
+		}
+		return document, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", o.Op)
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// escapePointerToken escapes a key for use in a JSON Pointer
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// arrayIndex parses an array index token, allowing len for appends when allowEnd is set
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if index > length || (index == length && !allowEnd) {
+		return 0, fmt.Errorf("array index %d out of range", index)
+	}
+	return index, nil
+}
+
+// pointerGet returns the value at path
+func pointerGet(node interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", token)
+			}
+			node = child
+		case []interface{}:
+			index, err := arrayIndex(token, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			node = n[index]
+		default:
+			return nil, fmt.Errorf("path %q not found", token)
+		}
+	}
+	return node, nil
+}
+
+// pointerUpdate replaces the container holding the last token of path with
+// the result of update, rebuilding the parents so array changes take effect
+func pointerUpdate(node interface{}, path []string, update func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return update(node, path[0])
+	}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[path[0]]
+		if !ok {
+			return nil, fmt.Errorf("path %q not found", path[0])
+		}
+		updated, err := pointerUpdate(child, path[1:], update)
+		if err != nil {
+			return nil, err
+		}
+		n[path[0]] = updated
+		return n, nil
+	case []interface{}:
+		index, err := arrayIndex(path[0], len(n), false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := pointerUpdate(n[index], path[1:], update)
+		if err != nil {
+			return nil, err
+		}
+		n[index] = updated
+		return n, nil
+	}
+	return nil, fmt.Errorf("path %q not found", path[0])
+}
+
+// pointerAdd adds value at path, inserting into arrays
+func pointerAdd(document interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return pointerUpdate(document, path, func(container interface{}, token string) (interface{}, error) {
+		switch n := container.(type) {
+		case map[string]interface{}:
+			n[token] = value
+			return n, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(n), true)
+			if err != nil {
+				return nil, err
+			}
+			n = append(n, nil)
+			copy(n[index+1:], n[index:])
+			n[index] = value
+			return n, nil
+		}
+		return nil, fmt.Errorf("cannot add to a scalar value")
+	})
+}
+
+// pointerRemove removes the value at path and returns it
+func pointerRemove(document interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the document root")
+	}
+	var removed interface{}
+	document, err := pointerUpdate(document, path, func(container interface{}, token string) (interface{}, error) {
+		switch n := container.(type) {
+		case map[string]interface{}:
+			value, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", token)
+			}
+			removed = value
+			delete(n, token)
+			return n, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = n[index]
+			return append(n[:index:index], n[index+1:]...), nil
+		}
+		return nil, fmt.Errorf("path %q not found", token)
+	})
+	return document, removed, err
+}
+
+// createPatch appends the operations turning left into right at pointer
+func createPatch(pointer string, left, right interface{}, operations *[]JSONPatchOperation) {
+	switch l := left.(type) {
+	case map[string]interface{}:
+		r, ok := right.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(l))
+		for key := range l {
+			keys = append(keys, key)
+		}
+		for key := range r {
+			if _, ok := l[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := pointer + "/" + escapePointerToken(key)
+			lv, inLeft := l[key]
+			rv, inRight := r[key]
+			switch {
+			case !inRight:
+				*operations = append(*operations, JSONPatchOperation{Op: "remove", Path: child})
+			case !inLeft:
+				*operations = append(*operations, JSONPatchOperation{Op: "add", Path: child, Value: rv})
+			default:
+				createPatch(child, lv, rv, operations)
+			}
+		}
+		return
+	case []interface{}:
+		r, ok := right.([]interface{})
+		if !ok {
+			break
+		}
+		common := min(len(l), len(r))
+		for i := 0; i < common; i++ {
+			createPatch(pointer+"/"+strconv.Itoa(i), l[i], r[i], operations)
+		}
+		for i := len(l) - 1; i >= common; i-- {
+			*operations = append(*operations, JSONPatchOperation{Op: "remove", Path: pointer + "/" + strconv.Itoa(i)})
+		}
+		for i := common; i < len(r); i++ {
+			*operations = append(*operations, JSONPatchOperation{Op: "add", Path: pointer + "/-", Value: r[i]})
+		}
+		return
+	}
+
+	if !jsonEqual(left, right) {
+		*operations = append(*operations, JSONPatchOperation{Op: "replace", Path: pointer, Value: right})
+	}
+}
+
+// decodeJSON unmarshals data keeping numbers as json.Number
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("unexpected data after JSON value")
+	}
+	return nil
+}
+
+// jsonEqual compares decoded JSON values, treating numbers numerically
+func jsonEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok || !jsonEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, xok := new(big.Float).SetString(string(av))
+		y, yok := new(big.Float).SetString(string(bv))
+		return xok && yok && x.Cmp(y) == 0
+	}
+	return a == b
+}
+
+// deepCopyJSON copies a decoded JSON value
+func deepCopyJSON(v interface{}) interface{} {
+	switch n := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(n))
+		for key, value := range n {
+			copied[key] = deepCopyJSON(value)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(n))
+		for i, value := range n {
+			copied[i] = deepCopyJSON(value)
+		}
+		return copied
+	}
+	return v
+}
@@ -0,0 +1,117 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+	}{
+		{"add member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"add array element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"append", `{"foo":[1]}`, `[{"op":"add","path":"/foo/-","value":2}]`, `{"foo":[1,2]}`},
+		{"remove member", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"remove array element", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"replace", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"move", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"move array element", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{"copy", `{"a":{"b":[1]}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/b/-","value":2}]`, `{"a":{"b":[1]},"c":{"b":[1,2]}}`},
+		{"test passes", `{"n":1.0,"s":"x"}`, `[{"op":"test","path":"/n","value":1},{"op":"test","path":"/s","value":"x"}]`, `{"n":1.0,"s":"x"}`},
+		{"escaped keys", `{"a/b":1,"m~n":2}`, `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/m~0n"}]`, `{"a/b":3}`},
+		{"add null", `{}`, `[{"op":"add","path":"/x","value":null}]`, `{"x":null}`},
+		{"replace root", `{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+		{"big numbers", `{"id":12345678901234567890}`, `[{"op":"add","path":"/x","value":1}]`, `{"id":12345678901234567890,"x":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ApplyJSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ApplyJSONPatch failed: %v", err)
+			}
+			assertJSONEqual(t, result, tt.expected)
+		})
+	}
+}
+
+func TestApplyJSONPatch_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+	}{
+		{"test fails", `{"a":1}`, `[{"op":"test","path":"/a","value":2}]`},
+		{"missing member", `{"a":1}`, `[{"op":"remove","path":"/b"}]`},
+		{"index out of range", `{"a":[1]}`, `[{"op":"add","path":"/a/3","value":2}]`},
+		{"leading zero index", `{"a":[1,2]}`, `[{"op":"remove","path":"/a/01"}]`},
+		{"missing parent", `{}`, `[{"op":"add","path":"/a/b","value":1}]`},
+		{"move into child", `{"a":{"b":{}}}`, `[{"op":"move","from":"/a","path":"/a/b/c"}]`},
+		{"missing value", `{}`, `[{"op":"add","path":"/a"}]`},
+		{"unknown op", `{}`, `[{"op":"merge","path":"/a","value":1}]`},
+		{"invalid pointer", `{}`, `[{"op":"add","path":"a","value":1}]`},
+		{"replace missing", `{}`, `[{"op":"replace","path":"/a","value":1}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ApplyJSONPatch([]byte(tt.doc), []byte(tt.patch)); err == nil {
+				t.Error("Expected ApplyJSONPatch to fail")
+			}
+		})
+	}
+
+	// A failed operation leaves the caller's document untouched
+	doc := []byte(`{"a":1}`)
+	if _, err := ApplyJSONPatch(doc, []byte(`[{"op":"add","path":"/b","value":2},{"op":"test","path":"/a","value":0}]`)); err == nil || !strings.Contains(err.Error(), "operation 1") {
+		t.Errorf("Expected failure at operation 1, got %v", err)
+	}
+	if string(doc) != `{"a":1}` {
+		t.Errorf("Expected input document to be unchanged, got %s", doc)
+	}
+}
+
+func TestCreateJSONPatch(t *testing.T) {
+	pairs := [][2]string{
+		{`{"a":1,"b":{"c":[1,2,3]},"d":"x"}`, `{"a":2,"b":{"c":[1,5]},"e":null,"f/g":true}`},
+		{`{"list":[1]}`, `{"list":[1,2,3]}`},
+		{`{"a":{"b":1}}`, `{"a":[1]}`},
+		{`[1,2]`, `{"a":1}`},
+		{`{"same":true}`, `{"same":true}`},
+	}
+
+	for _, pair := range pairs {
+		patch, err := CreateJSONPatch([]byte(pair[0]), []byte(pair[1]))
+		if err != nil {
+			t.Fatalf("CreateJSONPatch failed: %v", err)
+		}
+		result, err := ApplyJSONPatch([]byte(pair[0]), patch)
+		if err != nil {
+			t.Fatalf("Generated patch %s failed to apply: %v", patch, err)
+		}
+		assertJSONEqual(t, result, pair[1])
+	}
+
+	patch, err := CreateJSONPatch([]byte(`{"a":1}`), []byte(`{"a":1}`))
+	if err != nil || string(patch) != "[]" {
+		t.Errorf("Expected empty patch, got %s (%v)", patch, err)
+	}
+}
+
+func assertJSONEqual(t *testing.T, actual []byte, expected string) {
+	t.Helper()
+	var a, e interface{}
+	if err := decodeJSON(actual, &a); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", actual, err)
+	}
+	if err := decodeJSON([]byte(expected), &e); err != nil {
+		t.Fatalf("Invalid expected JSON %s: %v", expected, err)
+	}
+	if !jsonEqual(a, e) {
+		t.Errorf("Expected %s, got %s", expected, actual)
+	}
+}
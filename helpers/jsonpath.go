@@ -0,0 +1,165 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrPathNotFound is returned when a JSON path does not exist in a document
+var ErrPathNotFound = errors.New("path not found")
+
+// pathSegment is one step of a JSON path: an object key or an array index
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// GetPath extracts the value at path from a JSON document without
+// unmarshaling the rest of it. Paths use dots for object keys and brackets
+// for array indices or quoted keys, e.g. "user.address.city",
+// "items[0].sku", or `labels["app.kubernetes.io/name"]`.
+//
+// Example:
+//
+//	city, err := helpers.GetPath[string](payload, "user.address.city")
+//	if errors.Is(err, helpers.ErrPathNotFound) {
+//		// field is absent
+//	}
+func GetPath[T any](jsonData []byte, path string) (T, error) {
+	var result T
+	segments, err := parsePath(path)
+	if err != nil {
+		return result, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	for _, segment := range segments {
+		if err := descend(dec, segment); err != nil {
+			if errors.Is(err, ErrPathNotFound) {
+				return result, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+			}
+			return result, fmt.Errorf("failed to read JSON path %s: %w", path, err)
+		}
+	}
+	if err := dec.Decode(&result); err != nil {
+		return result, fmt.Errorf("failed to unmarshal JSON path %s: %w", path, err)
+	}
+	return result, nil
+}
+
+// HasPath reports whether path exists in a JSON document
+func HasPath(jsonData []byte, path string) bool {
+	_, err := GetPath[json.RawMessage](jsonData, path)
+	return err == nil
+}
+
+// descend advances dec to the start of the value selected by segment
+func descend(dec *json.Decoder, segment pathSegment) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if segment.isIndex {
+		if token != json.Delim('[') {
+			return ErrPathNotFound
+		}
+		for i := 0; dec.More(); i++ {
+			if i == segment.index {
+				return nil
+			}
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+		}
+		return ErrPathNotFound
+	}
+
+	if token != json.Delim('{') {
+		return ErrPathNotFound
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if key == segment.key {
+			return nil
+		}
+		if err := skipValue(dec); err != nil {
+			return err
+		}
+	}
+	return ErrPathNotFound
+}
+
+// skipValue consumes the next value from dec
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// parsePath splits a dot/bracket path into segments
+func parsePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	rest := path
+	for rest != "" {
+		switch {
+		case rest[0] == '.':
+			rest = rest[1:]
+			if rest == "" || rest[0] == '.' || rest[0] == '[' {
+				return nil, fmt.Errorf("invalid JSON path %q", path)
+			}
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if strings.HasPrefix(rest, `["`) {
+				end = strings.Index(rest, `"]`) + 1
+			}
+			if end <= 1 {
+				return nil, fmt.Errorf("invalid JSON path %q", path)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			if strings.HasPrefix(inner, `"`) {
+				key, err := strconv.Unquote(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid JSON path %q: %w", path, err)
+				}
+				segments = append(segments, pathSegment{key: key})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid array index %q in JSON path %q", inner, path)
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			segments = append(segments, pathSegment{key: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+	return segments, nil
+}
@@ -0,0 +1,73 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetPath(t *testing.T) {
+	payload := []byte(`{
+		"user": {"name": "Ada", "address": {"city": "London", "zip": "N1"}},
+		"items": [{"sku": "A1", "qty": 2}, {"sku": "B2", "qty": 5, "tags": ["x", "y"]}],
+		"labels": {"app.kubernetes.io/name": "orders"},
+		"total": 12.5
+	}`)
+
+	city, err := GetPath[string](payload, "user.address.city")
+	if err != nil || city != "London" {
+		t.Errorf("Expected London, got %q (%v)", city, err)
+	}
+
+	qty, err := GetPath[int](payload, "items[1].qty")
+	if err != nil || qty != 5 {
+		t.Errorf("Expected 5, got %d (%v)", qty, err)
+	}
+
+	tag, err := GetPath[string](payload, "items[1].tags[1]")
+	if err != nil || tag != "y" {
+		t.Errorf("Expected y, got %q (%v)", tag, err)
+	}
+
+	name, err := GetPath[string](payload, `labels["app.kubernetes.io/name"]`)
+	if err != nil || name != "orders" {
+		t.Errorf("Expected orders, got %q (%v)", name, err)
+	}
+
+	type item struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	first, err := GetPath[item](payload, "items[0]")
+	if err != nil || first.SKU != "A1" || first.Qty != 2 {
+		t.Errorf("Unexpected item %+v (%v)", first, err)
+	}
+
+	total, err := GetPath[float64](payload, "total")
+	if err != nil || total != 12.5 {
+		t.Errorf("Expected 12.5, got %v (%v)", total, err)
+	}
+}
+
+func TestGetPath_Errors(t *testing.T) {
+	payload := []byte(`{"user": {"name": "Ada"}, "items": [1, 2]}`)
+
+	for _, path := range []string{"user.email", "items[5]", "user[0]", "items.name", "missing.deep"} {
+		if _, err := GetPath[string](payload, path); !errors.Is(err, ErrPathNotFound) {
+			t.Errorf("Expected ErrPathNotFound for %q, got %v", path, err)
+		}
+	}
+
+	for _, path := range []string{"user..name", "items[x]", "items[", "user."} {
+		if _, err := GetPath[string](payload, path); err == nil || errors.Is(err, ErrPathNotFound) {
+			t.Errorf("Expected invalid path error for %q, got %v", path, err)
+		}
+	}
+
+	if _, err := GetPath[int](payload, "user.name"); err == nil {
+		t.Error("Expected type mismatch error")
+	}
+
+	if !HasPath(payload, "items[1]") || HasPath(payload, "items[2]") {
+		t.Error("Unexpected HasPath result")
+	}
+}
@@ -0,0 +1,127 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Decode limit errors
+var (
+	ErrJSONTooLarge = errors.New("JSON document exceeds size limit")
+	ErrJSONTooDeep  = errors.New("JSON document exceeds nesting depth limit")
+)
+
+// JSONLimitError reports which decode limit untrusted input exceeded
+type JSONLimitError struct {
+	Limit int64 // The exceeded limit, in bytes or nesting levels
+	Err   error // ErrJSONTooLarge or ErrJSONTooDeep
+}
+
+// Error implements the error interface
+func (e *JSONLimitError) Error() string {
+	return fmt.Sprintf("%v (limit %d)", e.Err, e.Limit)
+}
+
+// Unwrap returns ErrJSONTooLarge or ErrJSONTooDeep
+func (e *JSONLimitError) Unwrap() error {
+	return e.Err
+}
+
+// FromJSONLimited converts JSON bytes to a struct like FromJSON, but first
+// rejects documents larger than maxBytes or with objects and arrays nested
+// deeper than maxDepth, returning a *JSONLimitError. A limit of zero or less
+// disables that check. Use it for attacker-controlled input.
+func FromJSONLimited[T any](jsonData []byte, maxBytes int64, maxDepth int) (*T, error) {
+	if maxBytes > 0 && int64(len(jsonData)) > maxBytes {
+		return nil, &JSONLimitError{Limit: maxBytes, Err: ErrJSONTooLarge}
+	}
+	if maxDepth > 0 {
+		if err := checkJSONDepth(jsonData, maxDepth); err != nil {
+			return nil, err
+		}
+	}
+	return FromJSON[T](jsonData)
+}
+
+// FromReaderLimited reads at most maxBytes of JSON from an io.Reader and
+// converts it to a struct using FromJSONLimited
+func FromReaderLimited[T any](reader io.Reader, maxBytes int64, maxDepth int) (*T, error) {
+	if maxBytes > 0 {
+		reader = LimitReader(reader, maxBytes)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		var limitErr *JSONLimitError
+		if errors.As(err, &limitErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read JSON data: %w", err)
+	}
+	return FromJSONLimited[T](data, maxBytes, maxDepth)
+}
+
+// LimitReader returns a reader that reads from r but fails with a
+// *JSONLimitError once more than maxBytes are read. Unlike io.LimitReader,
+// oversized input is an error rather than silently truncated.
+func LimitReader(r io.Reader, maxBytes int64) io.Reader {
+	return &limitReader{reader: r, limit: maxBytes}
+}
+
+// limitReader fails reads past its limit
+type limitReader struct {
+	reader io.Reader
+	limit  int64
+	read   int64
+}
+
+// Read implements io.Reader
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.read > l.limit {
+		return 0, &JSONLimitError{Limit: l.limit, Err: ErrJSONTooLarge}
+	}
+	// Read one byte past the limit to tell "exactly at the limit" from "over"
+	if remaining := l.limit - l.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.reader.Read(p)
+	l.read += int64(n)
+	if l.read > l.limit {
+		return n - int(l.read-l.limit), &JSONLimitError{Limit: l.limit, Err: ErrJSONTooLarge}
+	}
+	return n, err
+}
+
+// checkJSONDepth scans jsonData and fails when objects and arrays nest
+// deeper than maxDepth, without decoding anything
+func checkJSONDepth(jsonData []byte, maxDepth int) error {
+	depth := 0
+	inString := false
+	escaped := false
+	for _, b := range jsonData {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case b == '\\':
+				escaped = true
+			case b == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch b {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return &JSONLimitError{Limit: int64(maxDepth), Err: ErrJSONTooDeep}
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFromJSONLimited(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+		Tags any    `json:"tags"`
+	}
+
+	result, err := FromJSONLimited[payload]([]byte(`{"name":"[[[[","tags":[["a"]]}`), 100, 3)
+	if err != nil || result.Name != "[[[[" {
+		t.Fatalf("Expected document within limits to decode, got %+v, %v", result, err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		maxBytes int64
+		maxDepth int
+		expected error
+	}{
+		{"too large", `{"name":"abcdefghij"}`, 10, 0, ErrJSONTooLarge},
+		{"too deep", `{"tags":[[[["a"]]]]}`, 0, 4, ErrJSONTooDeep},
+		{"escaped quote", `{"name":"\"[[[[","tags":[[[1]]]}`, 0, 3, ErrJSONTooDeep},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromJSONLimited[payload]([]byte(tt.input), tt.maxBytes, tt.maxDepth)
+			var limitErr *JSONLimitError
+			if !errors.Is(err, tt.expected) || !errors.As(err, &limitErr) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestLimitReader(t *testing.T) {
+	data, err := io.ReadAll(LimitReader(strings.NewReader("12345"), 5))
+	if err != nil || string(data) != "12345" {
+		t.Errorf("Expected input at the limit to be read, got %q, %v", data, err)
+	}
+
+	data, err = io.ReadAll(LimitReader(strings.NewReader("123456"), 5))
+	if !errors.Is(err, ErrJSONTooLarge) || len(data) > 5 {
+		t.Errorf("Expected ErrJSONTooLarge after at most 5 bytes, got %q, %v", data, err)
+	}
+
+	if _, err := FromReaderLimited[map[string]int](strings.NewReader(`{"a":1,"b":2}`), 8, 0); !errors.Is(err, ErrJSONTooLarge) {
+		t.Errorf("Expected ErrJSONTooLarge, got %v", err)
+	}
+	if m, err := FromReaderLimited[map[string]int](strings.NewReader(`{"a":1}`), 8, 1); err != nil || (*m)["a"] != 1 {
+		t.Errorf("Unexpected result %v, %v", m, err)
+	}
+}
@@ -0,0 +1,96 @@
+package helpers
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Keys returns the keys of m in unspecified order
+func Keys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// SortedKeys returns the keys of m in ascending order
+func SortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := Keys(m)
+	slices.Sort(keys)
+	return keys
+}
+
+// Values returns the values of m in unspecified order
+func Values[K comparable, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, value := range m {
+		values = append(values, value)
+	}
+	return values
+}
+
+// SortedValues returns the values of m ordered by their keys
+func SortedValues[K cmp.Ordered, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, key := range SortedKeys(m) {
+		values = append(values, m[key])
+	}
+	return values
+}
+
+// MergeMaps returns a new map containing the entries of all maps, with later
+// maps overriding earlier ones
+func MergeMaps[K comparable, V any](maps ...map[K]V) map[K]V {
+	size := 0
+	for _, m := range maps {
+		size += len(m)
+	}
+	result := make(map[K]V, size)
+	for _, m := range maps {
+		for key, value := range m {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// FilterMap returns a new map with the entries of m for which keep returns true
+func FilterMap[K comparable, V any](m map[K]V, keep func(K, V) bool) map[K]V {
+	result := make(map[K]V)
+	for key, value := range m {
+		if keep(key, value) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// Invert returns a map from values to keys. When several keys share a value,
+// which key is kept is unspecified; use InvertSorted for a deterministic result.
+func Invert[K, V comparable](m map[K]V) map[V]K {
+	result := make(map[V]K, len(m))
+	for key, value := range m {
+		result[value] = key
+	}
+	return result
+}
+
+// InvertSorted returns a map from values to keys, keeping the smallest key
+// when several keys share a value
+func InvertSorted[K cmp.Ordered, V comparable](m map[K]V) map[V]K {
+	result := make(map[V]K, len(m))
+	keys := SortedKeys(m)
+	for i := len(keys) - 1; i >= 0; i-- {
+		result[m[keys[i]]] = keys[i]
+	}
+	return result
+}
+
+// GetOrDefault returns m[key], or fallback when key is absent
+func GetOrDefault[K comparable, V any](m map[K]V, key K, fallback V) V {
+	if value, ok := m[key]; ok {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestKeysValues(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "c": 3}
+
+	keys := Keys(m)
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("Keys: got %v", keys)
+	}
+	values := Values(m)
+	sort.Ints(values)
+	if !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Errorf("Values: got %v", values)
+	}
+
+	if got := SortedKeys(m); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("SortedKeys: got %v", got)
+	}
+	if got := SortedValues(m); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("SortedValues: got %v", got)
+	}
+}
+
+func TestMergeFilterMaps(t *testing.T) {
+	base := map[string]int{"a": 1, "b": 2}
+	merged := MergeMaps(base, map[string]int{"b": 20, "c": 30}, nil)
+	if !reflect.DeepEqual(merged, map[string]int{"a": 1, "b": 20, "c": 30}) {
+		t.Errorf("MergeMaps: got %v", merged)
+	}
+	if base["b"] != 2 {
+		t.Error("Expected MergeMaps to leave inputs untouched")
+	}
+
+	filtered := FilterMap(merged, func(_ string, v int) bool { return v > 10 })
+	if !reflect.DeepEqual(filtered, map[string]int{"b": 20, "c": 30}) {
+		t.Errorf("FilterMap: got %v", filtered)
+	}
+}
+
+func TestInvert(t *testing.T) {
+	if got := Invert(map[string]int{"a": 1, "b": 2}); !reflect.DeepEqual(got, map[int]string{1: "a", 2: "b"}) {
+		t.Errorf("Invert: got %v", got)
+	}
+
+	for i := 0; i < 10; i++ {
+		if got := InvertSorted(map[string]int{"c": 1, "a": 1, "b": 2}); !reflect.DeepEqual(got, map[int]string{1: "a", 2: "b"}) {
+			t.Fatalf("InvertSorted: got %v", got)
+		}
+	}
+}
+
+func TestGetOrDefault(t *testing.T) {
+	m := map[string]int{"zero": 0}
+	if GetOrDefault(m, "zero", 5) != 0 {
+		t.Error("Expected present zero value to be returned")
+	}
+	if GetOrDefault(m, "missing", 5) != 5 {
+		t.Error("Expected fallback for missing key")
+	}
+	if GetOrDefault[string, int](nil, "missing", 7) != 7 {
+		t.Error("Expected fallback for nil map")
+	}
+}
@@ -0,0 +1,92 @@
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"sync"
+)
+
+// ReadNDJSON returns an iterator over newline-delimited JSON records in r.
+// Blank lines are skipped. Iteration stops after the first error, which is
+// yielded together with the zero value and includes the line number.
+//
+// Example:
+//
+//	for event, err := range helpers.ReadNDJSON[Event](resp.Body) {
+//		if err != nil {
+//			return err
+//		}
+//		process(event)
+//	}
+func ReadNDJSON[T any](r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		reader := bufio.NewReader(r)
+		for lineNum := 1; ; lineNum++ {
+			line, readErr := reader.ReadBytes('\n')
+			if readErr != nil && !errors.Is(readErr, io.EOF) {
+				var zero T
+				yield(zero, fmt.Errorf("failed to read NDJSON line %d: %w", lineNum, readErr))
+				return
+			}
+
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				var record T
+				if err := CurrentEngine().Unmarshal(line, &record); err != nil {
+					var zero T
+					yield(zero, fmt.Errorf("failed to unmarshal NDJSON line %d: %w", lineNum, err))
+					return
+				}
+				if !yield(record, nil) {
+					return
+				}
+			}
+
+			if readErr != nil {
+				return
+			}
+		}
+	}
+}
+
+// WriteNDJSON writes items to w as newline-delimited JSON
+func WriteNDJSON[T any](w io.Writer, items []T) error {
+	writer := NewNDJSONWriter[T](w)
+	for _, item := range items {
+		if err := writer.Write(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NDJSONWriter appends newline-delimited JSON records to an io.Writer.
+// It is safe for concurrent use.
+type NDJSONWriter[T any] struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewNDJSONWriter creates an NDJSON writer for w
+func NewNDJSONWriter[T any](w io.Writer) *NDJSONWriter[T] {
+	return &NDJSONWriter[T]{w: w}
+}
+
+// Write appends one record followed by a newline
+func (w *NDJSONWriter[T]) Write(item T) error {
+	data, err := ToJSON(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal NDJSON record: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write NDJSON record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package helpers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type ndjsonEvent struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestNDJSON_RoundTrip(t *testing.T) {
+	events := []ndjsonEvent{{ID: 1, Name: "created"}, {ID: 2, Name: "updated"}}
+
+	var buf bytes.Buffer
+	if err := WriteNDJSON(&buf, events); err != nil {
+		t.Fatalf("WriteNDJSON failed: %v", err)
+	}
+
+	expected := "{\"id\":1,\"name\":\"created\"}\n{\"id\":2,\"name\":\"updated\"}\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	var got []ndjsonEvent
+	for event, err := range ReadNDJSON[ndjsonEvent](&buf) {
+		if err != nil {
+			t.Fatalf("ReadNDJSON failed: %v", err)
+		}
+		got = append(got, event)
+	}
+	if len(got) != 2 || got[0] != events[0] || got[1] != events[1] {
+		t.Errorf("Expected %v, got %v", events, got)
+	}
+}
+
+func TestReadNDJSON_BlankLinesAndMissingNewline(t *testing.T) {
+	input := "{\"id\":1}\r\n\n  \n{\"id\":2}"
+
+	var ids []int
+	for event, err := range ReadNDJSON[ndjsonEvent](strings.NewReader(input)) {
+		if err != nil {
+			t.Fatalf("ReadNDJSON failed: %v", err)
+		}
+		ids = append(ids, event.ID)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("Expected [1 2], got %v", ids)
+	}
+}
+
+func TestReadNDJSON_InvalidLine(t *testing.T) {
+	input := "{\"id\":1}\nnot json\n{\"id\":3}\n"
+
+	var count int
+	var lastErr error
+	for _, err := range ReadNDJSON[ndjsonEvent](strings.NewReader(input)) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		count++
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 record before the error, got %d", count)
+	}
+	if lastErr == nil || !strings.Contains(lastErr.Error(), "line 2") {
+		t.Errorf("Expected error for line 2, got %v", lastErr)
+	}
+}
+
+func TestReadNDJSON_EarlyBreak(t *testing.T) {
+	input := "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n"
+
+	var count int
+	for range ReadNDJSON[ndjsonEvent](strings.NewReader(input)) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("Expected to stop after 2 records, got %d", count)
+	}
+}
+
+func TestNDJSONWriter_Append(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewNDJSONWriter[map[string]int](&buf)
+	for i := 0; i < 3; i++ {
+		if err := writer.Write(map[string]int{"n": i}); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Errorf("Expected 3 lines, got %d", lines)
+	}
+}
@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// FromJSONNumber converts JSON bytes to a struct like FromJSON, but numbers
+// decoded into interface{} values become json.Number instead of float64, so
+// 64-bit IDs and monetary amounts keep their exact text. Decoding always
+// uses encoding/json, regardless of SetEngine.
+func FromJSONNumber[T any](jsonData []byte) (*T, error) {
+	var result T
+	if err := DecodeWithNumbers(jsonData, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DecodeWithNumbers unmarshals JSON bytes into v, decoding numbers in
+// interface{} values as json.Number
+func DecodeWithNumbers(jsonData []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return nil
+}
+
+// NumberToBigInt converts a json.Number holding an integer to a big.Int
+func NumberToBigInt(n json.Number) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(n.String(), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer: %q", n)
+	}
+	return value, nil
+}
+
+// NumberToRat converts a json.Number to an exact big.Rat, suitable for
+// decimal amounts such as prices
+func NumberToRat(n json.Number) (*big.Rat, error) {
+	value, ok := new(big.Rat).SetString(n.String())
+	if !ok {
+		return nil, fmt.Errorf("invalid number: %q", n)
+	}
+	return value, nil
+}
+
+// NumberToDecimalString formats a json.Number as a plain decimal string with
+// exactly scale digits after the point, rounding half away from zero,
+// e.g. "1.005e2" with scale 2 becomes "100.50"
+func NumberToDecimalString(n json.Number, scale int) (string, error) {
+	value, err := NumberToRat(n)
+	if err != nil {
+		return "", err
+	}
+	return value.FloatString(scale), nil
+}
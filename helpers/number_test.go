@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFromJSONNumber(t *testing.T) {
+	type Payment struct {
+		ID     interface{}            `json:"id"`
+		Amount json.Number            `json:"amount"`
+		Meta   map[string]interface{} `json:"meta"`
+	}
+
+	payment, err := FromJSONNumber[Payment]([]byte(`{"id":9007199254740993,"amount":19.99,"meta":{"ref":12345678901234567890}}`))
+	if err != nil {
+		t.Fatalf("FromJSONNumber failed: %v", err)
+	}
+
+	if id, ok := payment.ID.(json.Number); !ok || id.String() != "9007199254740993" {
+		t.Errorf("Expected exact json.Number ID, got %#v", payment.ID)
+	}
+	if payment.Amount.String() != "19.99" {
+		t.Errorf("Expected amount 19.99, got %s", payment.Amount)
+	}
+	if ref := payment.Meta["ref"].(json.Number); ref.String() != "12345678901234567890" {
+		t.Errorf("Expected exact nested number, got %s", ref)
+	}
+
+	if _, err := FromJSONNumber[Payment]([]byte(`{invalid`)); err == nil {
+		t.Error("Expected invalid JSON to fail")
+	}
+}
+
+func TestDecodeWithNumbers(t *testing.T) {
+	var values []interface{}
+	if err := DecodeWithNumbers([]byte(`[1, 2.5, "x"]`), &values); err != nil {
+		t.Fatalf("DecodeWithNumbers failed: %v", err)
+	}
+	if _, ok := values[0].(json.Number); !ok {
+		t.Errorf("Expected json.Number, got %T", values[0])
+	}
+}
+
+func TestNumberConversions(t *testing.T) {
+	bigInt, err := NumberToBigInt("12345678901234567890")
+	if err != nil || bigInt.String() != "12345678901234567890" {
+		t.Errorf("Unexpected big.Int: %v (%v)", bigInt, err)
+	}
+	if _, err := NumberToBigInt("1.5"); err == nil {
+		t.Error("Expected non-integer to fail")
+	}
+
+	rat, err := NumberToRat("0.1")
+	if err != nil || rat.String() != "1/10" {
+		t.Errorf("Unexpected big.Rat: %v (%v)", rat, err)
+	}
+	if _, err := NumberToRat("abc"); err == nil {
+		t.Error("Expected invalid number to fail")
+	}
+
+	tests := []struct {
+		number   json.Number
+		scale    int
+		expected string
+	}{
+		{"19.99", 2, "19.99"},
+		{"1.005e2", 2, "100.50"},
+		{"0.125", 2, "0.13"},
+		{"-0.125", 2, "-0.13"},
+		{"42", 0, "42"},
+	}
+	for _, tt := range tests {
+		result, err := NumberToDecimalString(tt.number, tt.scale)
+		if err != nil || result != tt.expected {
+			t.Errorf("NumberToDecimalString(%s, %d): expected %s, got %s (%v)", tt.number, tt.scale, tt.expected, result, err)
+		}
+	}
+}
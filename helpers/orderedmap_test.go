@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	var m OrderedMap[string, int]
+	m.Set("zeta", 1)
+	m.Set("alpha", 2)
+	m.Set("mid", 3)
+	m.Set("zeta", 10)
+
+	if !reflect.DeepEqual(m.Keys(), []string{"zeta", "alpha", "mid"}) {
+		t.Errorf("Unexpected keys: %v", m.Keys())
+	}
+	if !reflect.DeepEqual(m.Values(), []int{10, 2, 3}) {
+		t.Errorf("Unexpected values: %v", m.Values())
+	}
+	if v, ok := m.Get("alpha"); !ok || v != 2 {
+		t.Errorf("Get returned %d, %v", v, ok)
+	}
+
+	m.Delete("alpha")
+	m.Delete("missing")
+	if m.Len() != 2 || m.Has("alpha") || !reflect.DeepEqual(m.Keys(), []string{"zeta", "mid"}) {
+		t.Errorf("Unexpected map after Delete: %v", m.Keys())
+	}
+
+	var visited []string
+	for key := range m.All() {
+		visited = append(visited, key)
+		break
+	}
+	if !reflect.DeepEqual(visited, []string{"zeta"}) {
+		t.Errorf("Expected iteration to stop early, got %v", visited)
+	}
+}
+
+func TestOrderedMap_JSON(t *testing.T) {
+	m := NewOrderedMap[string, any]()
+	m.Set("zeta", 1)
+	m.Set("alpha", []string{"x"})
+	m.Set("gamma", nil)
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"zeta":1,"alpha":["x"],"gamma":null}` {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+
+	var decoded OrderedMap[string, json.RawMessage]
+	if err := json.Unmarshal([]byte(`{"b": 1, "a": {"nested": true}, "c": null}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Keys(), []string{"b", "a", "c"}) {
+		t.Errorf("Expected key order to be kept, got %v", decoded.Keys())
+	}
+
+	if err := json.Unmarshal([]byte(`[1]`), &decoded); err == nil {
+		t.Error("Expected array to fail")
+	}
+}
+
+func TestOrderedMap_IntKeys(t *testing.T) {
+	var m OrderedMap[int, string]
+	if err := json.Unmarshal([]byte(`{"3":"c","1":"a"}`), &m); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(m.Keys(), []int{3, 1}) {
+		t.Errorf("Unexpected keys: %v", m.Keys())
+	}
+
+	data, err := json.Marshal(&m)
+	if err != nil || string(data) != `{"3":"c","1":"a"}` {
+		t.Errorf("Unexpected JSON: %s (%v)", data, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"x":"bad"}`), &m); err == nil {
+		t.Error("Expected non-numeric key to fail")
+	}
+}
@@ -0,0 +1,24 @@
+package helpers
+
+// Ptr returns a pointer to a copy of v, handy for optional JSON fields
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Deref returns the value p points to, or fallback when p is nil
+func Deref[T any](p *T, fallback T) T {
+	if p == nil {
+		return fallback
+	}
+	return *p
+}
+
+// Coalesce returns the first non-nil pointer, or nil when all are nil
+func Coalesce[T any](values ...*T) *T {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package helpers
+
+import "testing"
+
+func TestPtrDeref(t *testing.T) {
+	value := 42
+	p := Ptr(value)
+	value = 0
+	if *p != 42 {
+		t.Errorf("Expected pointer to a copy holding 42, got %d", *p)
+	}
+
+	if Deref(p, 7) != 42 {
+		t.Error("Expected Deref to return the pointed-to value")
+	}
+	if Deref[int](nil, 7) != 7 {
+		t.Error("Expected Deref to return the fallback for nil")
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	first, second := Ptr("a"), Ptr("b")
+	if Coalesce(nil, first, second) != first {
+		t.Error("Expected the first non-nil pointer")
+	}
+	if Coalesce[string](nil, nil) != nil {
+		t.Error("Expected nil when all pointers are nil")
+	}
+	if Coalesce[string]() != nil {
+		t.Error("Expected nil without arguments")
+	}
+}
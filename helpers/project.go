@@ -0,0 +1,154 @@
+package helpers
+
+import "fmt"
+
+// PickFields returns a JSON document containing only the values at the given
+// paths, keeping their nesting. Paths use the GetPath syntax, and a "*"
+// segment matches every key or array element, e.g. "id", "user.name", or
+// "items.*.sku". Paths that do not exist are ignored; picked array elements
+// keep their relative order.
+//
+// Example:
+//
+//	sparse, err := helpers.PickFields(payload, "id", "user.name", "items.*.sku")
+//	// {"id":1,"items":[{"sku":"A"},{"sku":"B"}],"user":{"name":"John"}}
+func PickFields(jsonData []byte, paths ...string) ([]byte, error) {
+	doc, segments, err := decodeProjection(jsonData, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	picked, ok := pickPaths(doc, segments)
+	if !ok {
+		switch doc.(type) {
+		case map[string]interface{}:
+			picked = map[string]interface{}{}
+		case []interface{}:
+			picked = []interface{}{}
+		default:
+			picked = nil
+		}
+	}
+	return ToJSON(picked)
+}
+
+// OmitFields returns a JSON document with the values at the given paths
+// removed. Paths use the same syntax as PickFields; omitted array elements
+// are removed from the array rather than set to null. Paths that do not exist
+// are ignored.
+func OmitFields(jsonData []byte, paths ...string) ([]byte, error) {
+	doc, segments, err := decodeProjection(jsonData, paths)
+	if err != nil {
+		return nil, err
+	}
+	return ToJSON(omitPaths(doc, segments))
+}
+
+// decodeProjection decodes jsonData and parses the projection paths
+func decodeProjection(jsonData []byte, paths []string) (interface{}, [][]pathSegment, error) {
+	var doc interface{}
+	if err := decodeJSON(jsonData, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	segments := make([][]pathSegment, 0, len(paths))
+	for _, path := range paths {
+		parsed, err := parsePath(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(parsed) == 0 {
+			return nil, nil, fmt.Errorf("invalid JSON path %q", path)
+		}
+		segments = append(segments, parsed)
+	}
+	return doc, segments, nil
+}
+
+// matchSegment reports whether segment selects the object key or array index
+func matchSegment(segment pathSegment, key string, index int, isIndex bool) bool {
+	if segment.key == "*" {
+		return true
+	}
+	if isIndex {
+		return segment.isIndex && segment.index == index
+	}
+	return !segment.isIndex && segment.key == key
+}
+
+// remainingPaths returns the rest of each path whose first segment matches,
+// and whether any path ends at this key or index
+func remainingPaths(paths [][]pathSegment, key string, index int, isIndex bool) ([][]pathSegment, bool) {
+	var rest [][]pathSegment
+	complete := false
+	for _, path := range paths {
+		if !matchSegment(path[0], key, index, isIndex) {
+			continue
+		}
+		if len(path) == 1 {
+			complete = true
+		} else {
+			rest = append(rest, path[1:])
+		}
+	}
+	return rest, complete
+}
+
+// pickPaths returns the parts of doc selected by paths, and false when none is
+func pickPaths(doc interface{}, paths [][]pathSegment) (interface{}, bool) {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{})
+		for key, value := range v {
+			rest, complete := remainingPaths(paths, key, 0, false)
+			if complete {
+				result[key] = value
+			} else if picked, ok := pickPaths(value, rest); ok {
+				result[key] = picked
+			}
+		}
+		return result, len(result) > 0
+	case []interface{}:
+		result := make([]interface{}, 0)
+		for i, value := range v {
+			rest, complete := remainingPaths(paths, "", i, true)
+			if complete {
+				result = append(result, value)
+			} else if picked, ok := pickPaths(value, rest); ok {
+				result = append(result, picked)
+			}
+		}
+		return result, len(result) > 0
+	}
+	return nil, false
+}
+
+// omitPaths removes the parts of doc selected by paths
+func omitPaths(doc interface{}, paths [][]pathSegment) interface{} {
+	if len(paths) == 0 {
+		return doc
+	}
+
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			rest, complete := remainingPaths(paths, key, 0, false)
+			if complete {
+				delete(v, key)
+			} else {
+				v[key] = omitPaths(value, rest)
+			}
+		}
+		return v
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for i, value := range v {
+			rest, complete := remainingPaths(paths, "", i, true)
+			if !complete {
+				result = append(result, omitPaths(value, rest))
+			}
+		}
+		return result
+	}
+	return doc
+}
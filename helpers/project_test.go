@@ -0,0 +1,68 @@
+package helpers
+
+import "testing"
+
+const projectionDoc = `{
+	"id": 1,
+	"user": {"name": "John", "email": "john@example.com", "password": "x"},
+	"items": [{"sku": "A", "price": 10}, {"sku": "B", "price": 20}],
+	"meta": {"a": {"secret": 1, "keep": 2}, "b": {"secret": 3}}
+}`
+
+func TestPickFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []string
+		expected string
+	}{
+		{"top level", []string{"id"}, `{"id":1}`},
+		{"nested", []string{"id", "user.name"}, `{"id":1,"user":{"name":"John"}}`},
+		{"wildcard", []string{"items.*.sku"}, `{"items":[{"sku":"A"},{"sku":"B"}]}`},
+		{"index", []string{"items[1].price"}, `{"items":[{"price":20}]}`},
+		{"wildcard key", []string{"meta.*.secret"}, `{"meta":{"a":{"secret":1},"b":{"secret":3}}}`},
+		{"overlapping", []string{"user", "user.name"}, `{"user":{"email":"john@example.com","name":"John","password":"x"}}`},
+		{"missing", []string{"nope.deeper"}, `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PickFields([]byte(projectionDoc), tt.paths...)
+			if err != nil {
+				t.Fatalf("PickFields failed: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestOmitFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []string
+		expected string
+	}{
+		{"nested", []string{"user.password", "meta", "items"}, `{"id":1,"user":{"email":"john@example.com","name":"John"}}`},
+		{"wildcard", []string{"items.*.price", "meta.*.secret", "user", "id"}, `{"items":[{"sku":"A"},{"sku":"B"}],"meta":{"a":{"keep":2},"b":{}}}`},
+		{"index", []string{"items[0]", "user", "meta", "id"}, `{"items":[{"price":20,"sku":"B"}]}`},
+		{"missing", []string{"nope", "user", "meta", "items"}, `{"id":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OmitFields([]byte(projectionDoc), tt.paths...)
+			if err != nil {
+				t.Fatalf("OmitFields failed: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := OmitFields([]byte(projectionDoc), "user..name"); err == nil {
+		t.Error("Expected invalid path to fail")
+	}
+	if _, err := PickFields([]byte(`{`), "id"); err == nil {
+		t.Error("Expected invalid JSON to fail")
+	}
+}
@@ -0,0 +1,269 @@
+package helpers
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces sensitive values in redacted output
+const RedactedValue = "REDACTED"
+
+// DefaultRedactedFields are field names that are always treated as sensitive,
+// matched case-insensitively. The client debug log and the logger's Redacted
+// field and redaction core use the same list.
+var DefaultRedactedFields = []string{"password", "secret", "token", "access_token", "refresh_token", "client_secret", "api_key", "authorization"}
+
+// RedactionPattern masks sensitive values found inside strings, whatever the
+// field is called
+type RedactionPattern struct {
+	Name    string                  // Describes the pattern, e.g. "card_number"
+	Pattern *regexp.Regexp          // Matches candidate values
+	Valid   func(match string) bool // Filters out false positives; nil accepts every match
+}
+
+// DefaultRedactedPatterns are masked in every string value by RedactJSON,
+// RedactString, and the logger's redaction core. Card numbers are 13 to 19
+// digits, optionally separated by spaces or dashes, that pass the Luhn check.
+var DefaultRedactedPatterns = []RedactionPattern{
+	{Name: "card_number", Pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), Valid: luhnValid},
+}
+
+// IsSensitiveField reports whether name is in DefaultRedactedFields or
+// extra, ignoring case
+func IsSensitiveField(name string, extra ...string) bool {
+	return containsFold(DefaultRedactedFields, name) || containsFold(extra, name)
+}
+
+// RedactString replaces the parts of s matching DefaultRedactedPatterns with
+// RedactedValue
+//
+// Example:
+//
+//	helpers.RedactString("paid with 4111 1111 1111 1111")
+//	// "paid with REDACTED"
+func RedactString(s string) string {
+	for _, pattern := range DefaultRedactedPatterns {
+		s = pattern.Pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if pattern.Valid != nil && !pattern.Valid(match) {
+				return match
+			}
+			return RedactedValue
+		})
+	}
+	return s
+}
+
+// RedactJSON converts a value to JSON with sensitive fields replaced by
+// RedactedValue. A field is sensitive when it is tagged `redact:"true"`,
+// carries the json tag option "redact" (e.g. `json:"ssn,redact"`), or its
+// JSON name is in DefaultRedactedFields. Card numbers and other
+// DefaultRedactedPatterns matches are masked in every string value.
+//
+// Example:
+//
+//	type User struct {
+//		Email    string `json:"email" redact:"true"`
+//		Password string `json:"password"`
+//		SSN      string `json:"ssn,redact"`
+//	}
+//
+//	safe, err := helpers.RedactJSON(user)
+//	// {"email":"REDACTED","password":"REDACTED","ssn":"REDACTED"}
+func RedactJSON[T any](data T) ([]byte, error) {
+	jsonData, err := ToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	var doc interface{}
+	if err := decodeJSON(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	redactTagged(reflect.ValueOf(data), doc)
+	redactNamed(doc, DefaultRedactedFields)
+	return ToJSON(doc)
+}
+
+// RedactFields replaces the values at the given paths in a JSON document with
+// RedactedValue. Paths use the GetPath syntax, and a "*" segment matches every
+// key or array element, e.g. "user.password" or "cards.*.number". Paths that
+// do not exist are ignored.
+func RedactFields(jsonData []byte, paths ...string) ([]byte, error) {
+	var doc interface{}
+	if err := decodeJSON(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	for _, path := range paths {
+		segments, err := parsePath(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(segments) == 0 {
+			return nil, fmt.Errorf("invalid JSON path %q", path)
+		}
+		redactPath(doc, segments)
+	}
+	return ToJSON(doc)
+}
+
+// redactPath replaces the values matching segments below doc
+func redactPath(doc interface{}, segments []pathSegment) {
+	segment, last := segments[0], len(segments) == 1
+	apply := func(get func() interface{}, set func()) {
+		if last {
+			set()
+		} else {
+			redactPath(get(), segments[1:])
+		}
+	}
+
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		if segment.isIndex {
+			return
+		}
+		for key := range v {
+			if segment.key == "*" || segment.key == key {
+				apply(func() interface{} { return v[key] }, func() { v[key] = RedactedValue })
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			if segment.key == "*" || (segment.isIndex && segment.index == i) {
+				apply(func() interface{} { return v[i] }, func() { v[i] = RedactedValue })
+			}
+		}
+	}
+}
+
+// redactNamed replaces values of object keys named in fields,
+// case-insensitively, and pattern matches in string values
+func redactNamed(doc interface{}, fields []string) {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if containsFold(fields, key) {
+				v[key] = RedactedValue
+			} else if str, ok := value.(string); ok {
+				v[key] = RedactString(str)
+			} else {
+				redactNamed(value, fields)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			if str, ok := value.(string); ok {
+				v[i] = RedactString(str)
+			} else {
+				redactNamed(value, fields)
+			}
+		}
+	}
+}
+
+// redactTagged replaces values of struct fields tagged as sensitive, walking
+// the Go value alongside its decoded JSON
+func redactTagged(value reflect.Value, doc interface{}) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		if obj, ok := doc.(map[string]interface{}); ok {
+			redactStruct(value, obj)
+		}
+	case reflect.Slice, reflect.Array:
+		if items, ok := doc.([]interface{}); ok {
+			for i := 0; i < value.Len() && i < len(items); i++ {
+				redactTagged(value.Index(i), items[i])
+			}
+		}
+	case reflect.Map:
+		if obj, ok := doc.(map[string]interface{}); ok && value.Type().Key().Kind() == reflect.String {
+			iter := value.MapRange()
+			for iter.Next() {
+				redactTagged(iter.Value(), obj[iter.Key().String()])
+			}
+		}
+	}
+}
+
+// redactStruct handles the fields of one struct, including promoted fields
+// of untagged embedded structs
+func redactStruct(value reflect.Value, obj map[string]interface{}) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, hasOptions := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && !hasOptions {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := value.Field(i)
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				redactStruct(embedded, obj)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fieldDoc, ok := obj[name]
+		if !ok {
+			continue
+		}
+		if field.Tag.Get("redact") == "true" || containsFold(strings.Split(options, ","), "redact") {
+			obj[name] = RedactedValue
+			continue
+		}
+		redactTagged(value.Field(i), fieldDoc)
+	}
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+		digit := int(s[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,117 @@
+package helpers
+
+import "testing"
+
+type redactCard struct {
+	Number string `json:"number" redact:"true"`
+	Brand  string `json:"brand"`
+}
+
+type redactAudit struct {
+	IP string `json:"ip,omitempty,redact"`
+}
+
+type redactUser struct {
+	redactAudit
+	Name     string                `json:"name"`
+	Email    string                `json:"email" redact:"true"`
+	Password string                `json:"password"`
+	SSN      string                `json:"ssn,redact"`
+	Cards    []redactCard          `json:"cards"`
+	Primary  *redactCard           `json:"primary"`
+	ByName   map[string]redactCard `json:"by_name"`
+	Dash     string                `json:"-,"`
+}
+
+func TestRedactJSON(t *testing.T) {
+	card := redactCard{Number: "4111111111111111", Brand: "visa"}
+	user := redactUser{
+		redactAudit: redactAudit{IP: "10.0.0.1"},
+		Name:        "John",
+		Email:       "john@example.com",
+		Password:    "hunter2",
+		SSN:         "123-45-6789",
+		Cards:       []redactCard{card},
+		Primary:     &card,
+		ByName:      map[string]redactCard{"main": card},
+		Dash:        "kept",
+	}
+
+	result, err := RedactJSON(user)
+	if err != nil {
+		t.Fatalf("RedactJSON failed: %v", err)
+	}
+	assertJSONEqual(t, result, `{
+		"ip": "REDACTED",
+		"name": "John",
+		"email": "REDACTED",
+		"password": "REDACTED",
+		"ssn": "REDACTED",
+		"cards": [{"number": "REDACTED", "brand": "visa"}],
+		"primary": {"number": "REDACTED", "brand": "visa"},
+		"by_name": {"main": {"number": "REDACTED", "brand": "visa"}},
+		"-": "kept"
+	}`)
+
+	if user.Password != "hunter2" || card.Number != "4111111111111111" {
+		t.Error("Expected RedactJSON to leave the input untouched")
+	}
+}
+
+func TestRedactJSON_DefaultFieldsInMaps(t *testing.T) {
+	result, err := RedactJSON(map[string]interface{}{"Access_Token": "abc", "nested": []interface{}{map[string]string{"api_key": "k"}}})
+	if err != nil {
+		t.Fatalf("RedactJSON failed: %v", err)
+	}
+	assertJSONEqual(t, result, `{"Access_Token":"REDACTED","nested":[{"api_key":"REDACTED"}]}`)
+}
+
+func TestRedactString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"paid with 4111 1111 1111 1111", "paid with REDACTED"},
+		{"card 5500-0000-0000-0004 declined", "card REDACTED declined"},
+		{"order 1234567890123456", "order 1234567890123456"}, // fails the Luhn check
+		{"no digits here", "no digits here"},
+	}
+	for _, tt := range tests {
+		if result := RedactString(tt.input); result != tt.expected {
+			t.Errorf("RedactString(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+
+	result, err := RedactJSON(map[string]interface{}{"note": "card 4111111111111111", "tags": []string{"4111111111111111"}, "Authorization": "Bearer x"})
+	if err != nil {
+		t.Fatalf("RedactJSON failed: %v", err)
+	}
+	assertJSONEqual(t, result, `{"note":"card REDACTED","tags":["REDACTED"],"Authorization":"REDACTED"}`)
+
+	if !IsSensitiveField("PASSWORD") || !IsSensitiveField("ssn", "SSN") || IsSensitiveField("name") {
+		t.Error("Unexpected IsSensitiveField result")
+	}
+}
+
+func TestRedactFields(t *testing.T) {
+	input := []byte(`{"user":{"email":"a@b.c","name":"A"},"cards":[{"number":"1"},{"number":"2"}],"id":12345678901234567890}`)
+
+	result, err := RedactFields(input, "user.email", "cards.*.number", "missing.path", "cards[5].number")
+	if err != nil {
+		t.Fatalf("RedactFields failed: %v", err)
+	}
+	assertJSONEqual(t, result, `{"user":{"email":"REDACTED","name":"A"},"cards":[{"number":"REDACTED"},{"number":"REDACTED"}],"id":12345678901234567890}`)
+
+	result, err = RedactFields(input, "cards[1]")
+	if err != nil {
+		t.Fatalf("RedactFields failed: %v", err)
+	}
+	assertJSONEqual(t, result, `{"user":{"email":"a@b.c","name":"A"},"cards":[{"number":"1"},"REDACTED"],"id":12345678901234567890}`)
+
+	if _, err := RedactFields(input, "a..b"); err == nil {
+		t.Error("Expected invalid path to fail")
+	}
+	if _, err := RedactFields([]byte(`{invalid`), "a"); err == nil {
+		t.Error("Expected invalid JSON to fail")
+	}
+}
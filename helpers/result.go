@@ -0,0 +1,158 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Result holds either a value or an error, for pipelines that pass outcomes
+// along instead of returning early
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok creates a successful result
+func Ok[T any](value T) Result[T] {
+	return Result[T]{value: value}
+}
+
+// Err creates a failed result
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// Try creates a result from a (value, error) pair, e.g. Try(FromJSON[User](data))
+func Try[T any](value T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+	return Ok(value)
+}
+
+// IsOk reports whether the result holds a value
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// Err returns the error, or nil for a successful result
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Unwrap returns the value and error as a Go pair
+func (r Result[T]) Unwrap() (T, error) {
+	return r.value, r.err
+}
+
+// OrElse returns the value, or fallback when the result failed
+func (r Result[T]) OrElse(fallback T) T {
+	if r.err != nil {
+		return fallback
+	}
+	return r.value
+}
+
+// Map applies fn to the value of a successful result; a failed result is
+// returned unchanged. Use MapResult to change the value type.
+func (r Result[T]) Map(fn func(T) (T, error)) Result[T] {
+	if r.err != nil {
+		return r
+	}
+	return Try(fn(r.value))
+}
+
+// MapResult applies fn to the value of a successful result, producing a
+// result of another type; a failed result keeps its error
+func MapResult[T, R any](r Result[T], fn func(T) (R, error)) Result[R] {
+	if r.err != nil {
+		return Err[R](r.err)
+	}
+	return Try(fn(r.value))
+}
+
+// Optional is a value that may be absent, null, or present. Used as a struct
+// field with the json ",omitzero" option, an absent value is omitted when
+// marshaling, and unmarshaling distinguishes a missing key from an explicit
+// null, which PATCH-style updates need.
+//
+// Example:
+//
+//	type UpdateUser struct {
+//		Nickname helpers.Optional[string] `json:"nickname,omitzero"`
+//	}
+//
+//	// {}                  -> !Nickname.IsPresent()
+//	// {"nickname": null}  -> Nickname.IsNull()
+//	// {"nickname": "jo"}  -> Nickname.Get() == "jo", true
+type Optional[T any] struct {
+	value   T
+	present bool
+	null    bool
+}
+
+// Some creates an optional holding value
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{value: value, present: true}
+}
+
+// None creates an absent optional, the same as the zero value
+func None[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+// Null creates an optional that is present but explicitly null
+func Null[T any]() Optional[T] {
+	return Optional[T]{present: true, null: true}
+}
+
+// IsPresent reports whether the optional was set, including to null
+func (o Optional[T]) IsPresent() bool {
+	return o.present
+}
+
+// IsNull reports whether the optional was explicitly set to null
+func (o Optional[T]) IsNull() bool {
+	return o.null
+}
+
+// IsZero reports whether the optional is absent, so ",omitzero" omits it
+func (o Optional[T]) IsZero() bool {
+	return !o.present
+}
+
+// Get returns the value and true when the optional holds a non-null value
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.present && !o.null
+}
+
+// OrElse returns the value, or fallback when the optional is absent or null
+func (o Optional[T]) OrElse(fallback T) T {
+	if value, ok := o.Get(); ok {
+		return value
+	}
+	return fallback
+}
+
+// MarshalJSON encodes the value, or null when absent or null
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.present || o.null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON marks the optional present and decodes the value or null
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		*o = Null[T]()
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("failed to unmarshal optional value: %w", err)
+	}
+	*o = Some(value)
+	return nil
+}
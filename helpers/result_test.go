@@ -0,0 +1,101 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestResult(t *testing.T) {
+	ok := Try(strconv.Atoi("42"))
+	if !ok.IsOk() || ok.Err() != nil || ok.OrElse(0) != 42 {
+		t.Errorf("Unexpected successful result: %+v", ok)
+	}
+
+	failed := Try(strconv.Atoi("nope"))
+	if failed.IsOk() || failed.Err() == nil || failed.OrElse(-1) != -1 {
+		t.Errorf("Unexpected failed result: %+v", failed)
+	}
+	if _, err := failed.Unwrap(); err == nil {
+		t.Error("Expected Unwrap to return the error")
+	}
+}
+
+func TestResult_Map(t *testing.T) {
+	double := func(n int) (int, error) { return n * 2, nil }
+
+	if got := Ok(21).Map(double).OrElse(0); got != 42 {
+		t.Errorf("Expected 42, got %d", got)
+	}
+
+	boom := errors.New("boom")
+	calls := 0
+	failed := Err[int](boom).Map(func(n int) (int, error) { calls++; return n, nil })
+	if !errors.Is(failed.Err(), boom) || calls != 0 {
+		t.Error("Expected Map to skip failed results")
+	}
+
+	text := MapResult(Ok(7), func(n int) (string, error) { return strconv.Itoa(n), nil })
+	if value, err := text.Unwrap(); err != nil || value != "7" {
+		t.Errorf("Unexpected MapResult: %q, %v", value, err)
+	}
+	if converted := MapResult(Err[int](boom), func(n int) (string, error) { return "", nil }); !errors.Is(converted.Err(), boom) {
+		t.Error("Expected MapResult to keep the error")
+	}
+
+	if got := Ok(1).Map(func(int) (int, error) { return 0, boom }); !errors.Is(got.Err(), boom) {
+		t.Error("Expected Map to capture fn errors")
+	}
+}
+
+func TestOptional(t *testing.T) {
+	if value, ok := Some("x").Get(); !ok || value != "x" {
+		t.Error("Expected Some to hold a value")
+	}
+	if None[string]().IsPresent() || None[string]().OrElse("fallback") != "fallback" {
+		t.Error("Expected None to be absent")
+	}
+	if null := Null[string](); !null.IsPresent() || !null.IsNull() || null.OrElse("fallback") != "fallback" {
+		t.Error("Expected Null to be present and null")
+	}
+}
+
+func TestOptional_JSON(t *testing.T) {
+	type Update struct {
+		Nickname Optional[string] `json:"nickname,omitzero"`
+		Age      Optional[int]    `json:"age,omitzero"`
+	}
+
+	tests := []struct {
+		input            string
+		present, null    bool
+		expectedNickname string
+	}{
+		{`{}`, false, false, ""},
+		{`{"nickname":null}`, true, true, ""},
+		{`{"nickname":"jo"}`, true, false, "jo"},
+	}
+	for _, tt := range tests {
+		var update Update
+		if err := json.Unmarshal([]byte(tt.input), &update); err != nil {
+			t.Fatalf("Unmarshal %s failed: %v", tt.input, err)
+		}
+		if update.Nickname.IsPresent() != tt.present || update.Nickname.IsNull() != tt.null || update.Nickname.OrElse("") != tt.expectedNickname {
+			t.Errorf("%s: unexpected optional %+v", tt.input, update.Nickname)
+		}
+
+		data, err := json.Marshal(update)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if string(data) != tt.input {
+			t.Errorf("Expected round trip to %s, got %s", tt.input, data)
+		}
+	}
+
+	var update Update
+	if err := json.Unmarshal([]byte(`{"age":"old"}`), &update); err == nil {
+		t.Error("Expected type mismatch to fail")
+	}
+}
@@ -0,0 +1,178 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/khekrn/core/clock"
+)
+
+// ErrMaxRetriesExceeded is returned when every attempt allowed by the retry
+// policy failed. It wraps the error of the last attempt.
+var ErrMaxRetriesExceeded = errors.New("max retries exceeded")
+
+// JitterStrategy controls how retry delays are randomized
+type JitterStrategy string
+
+// Supported jitter strategies
+const (
+	JitterNone         JitterStrategy = ""             // Deterministic exponential backoff
+	JitterFull         JitterStrategy = "full"         // Random delay between 0 and the backoff
+	JitterEqual        JitterStrategy = "equal"        // Half the backoff plus a random half
+	JitterDecorrelated JitterStrategy = "decorrelated" // Random delay between the initial backoff and 3x the previous delay
+)
+
+// RetryPolicy configures Retry. The zero value makes a single attempt. A
+// zero MaxBackoff leaves the delays uncapped.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	BackoffFactor  float64
+	Jitter         JitterStrategy
+	// ShouldRetry classifies a failed attempt; see DefaultShouldRetry
+	ShouldRetry func(err error, attempt int) bool
+	// Clock waits out the backoff delays; defaults to clock.System
+	Clock clock.Clock
+}
+
+// DefaultRetryPolicy returns the policy used by the REST client: 3 attempts
+// with exponential backoff from 100ms up to 5s
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		BackoffFactor:  2.0,
+	}
+}
+
+// permanentError marks an error that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not retryable, so Retry returns it immediately.
+// Retry returns the original err, not the wrapper.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// DefaultShouldRetry retries every error except cancellation, deadline
+// expiry, and errors marked with Permanent
+func DefaultShouldRetry(err error, attempt int) bool {
+	return !IsPermanent(err) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// Retry calls fn until it succeeds, the policy's attempts are used up, an
+// error is classified as not retryable, or ctx is done. Between attempts it
+// waits for the policy's backoff delay.
+//
+// Example:
+//
+//	user, err := helpers.Retry(ctx, helpers.DefaultRetryPolicy(), func() (User, error) {
+//		return repo.FindUser(ctx, id)
+//	})
+func Retry[T any](ctx context.Context, policy RetryPolicy, fn func() (T, error)) (T, error) {
+	var zero T
+	var lastErr error
+	var delay time.Duration
+
+	for attempt := 1; attempt <= max(policy.MaxAttempts, 1); attempt++ {
+		if attempt > 1 {
+			delay = policy.Backoff(attempt-1, delay)
+			select {
+			case <-clock.OrSystem(policy.Clock).After(delay):
+			case <-ctx.Done():
+				return zero, fmt.Errorf("%w: %w", ctx.Err(), lastErr)
+			}
+		} else if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+
+		value, err := fn()
+		if err == nil {
+			return value, nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return zero, permanent.err
+		}
+		if !policy.shouldRetry(err, attempt) {
+			return zero, err
+		}
+		lastErr = err
+	}
+
+	if policy.MaxAttempts <= 1 {
+		return zero, lastErr
+	}
+	return zero, fmt.Errorf("%w: %w", ErrMaxRetriesExceeded, lastErr)
+}
+
+// shouldRetry classifies an attempt using the configured or default classifier
+func (p RetryPolicy) shouldRetry(err error, attempt int) bool {
+	if p.ShouldRetry != nil {
+		return p.ShouldRetry(err, attempt)
+	}
+	return DefaultShouldRetry(err, attempt)
+}
+
+// Backoff returns the delay before the retry that follows the given failed
+// attempt, applying the configured jitter. previous is the delay used before
+// that attempt, which decorrelated jitter builds on.
+func (p RetryPolicy) Backoff(attempt int, previous time.Duration) time.Duration {
+	scaled := float64(p.InitialBackoff) * math.Pow(p.BackoffFactor, float64(attempt-1))
+	delay := time.Duration(math.MaxInt64)
+	if scaled < float64(math.MaxInt64) {
+		delay = time.Duration(scaled)
+	}
+
+	// A zero MaxBackoff means the delay is not capped
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	switch p.Jitter {
+	case JitterFull:
+		delay = randomDuration(0, delay)
+	case JitterEqual:
+		delay = delay/2 + randomDuration(0, delay/2)
+	case JitterDecorrelated:
+		upper := previous * 3
+		if upper < p.InitialBackoff {
+			upper = p.InitialBackoff
+		}
+		delay = randomDuration(p.InitialBackoff, upper)
+		if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+			delay = p.MaxBackoff
+		}
+	}
+
+	return delay
+}
+
+// randomDuration returns a random duration in [low, high]
+func randomDuration(low, high time.Duration) time.Duration {
+	if high <= low {
+		return low
+	}
+	return low + rand.N(high-low+1)
+}
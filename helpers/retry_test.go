@@ -0,0 +1,151 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func fastPolicy(attempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    attempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		BackoffFactor:  2.0,
+	}
+}
+
+func TestRetry_SucceedsAfterFailures(t *testing.T) {
+	calls := 0
+	value, err := Retry(context.Background(), fastPolicy(3), func() (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errors.New("transient")
+		}
+		return "ok", nil
+	})
+	if err != nil || value != "ok" {
+		t.Fatalf("Expected ok, got %q, %v", value, err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetry_MaxRetriesExceeded(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+	_, err := Retry(context.Background(), fastPolicy(3), func() (int, error) {
+		calls++
+		return 0, boom
+	})
+	if !errors.Is(err, ErrMaxRetriesExceeded) || !errors.Is(err, boom) {
+		t.Errorf("Expected wrapped ErrMaxRetriesExceeded, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetry_Classification(t *testing.T) {
+	boom := errors.New("boom")
+
+	calls := 0
+	_, err := Retry(context.Background(), fastPolicy(5), func() (int, error) {
+		calls++
+		return 0, Permanent(boom)
+	})
+	if err != boom || calls != 1 {
+		t.Errorf("Expected permanent error after 1 call, got %v after %d", err, calls)
+	}
+
+	calls = 0
+	policy := fastPolicy(5)
+	policy.ShouldRetry = func(err error, attempt int) bool { return attempt < 2 }
+	_, err = Retry(context.Background(), policy, func() (int, error) {
+		calls++
+		return 0, boom
+	})
+	if err != boom || calls != 2 {
+		t.Errorf("Expected custom classifier to stop after 2 calls, got %v after %d", err, calls)
+	}
+
+	if DefaultShouldRetry(context.Canceled, 1) || !DefaultShouldRetry(boom, 1) {
+		t.Error("Unexpected DefaultShouldRetry classification")
+	}
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := fastPolicy(5)
+	policy.InitialBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+
+	calls := 0
+	_, err := Retry(ctx, policy, func() (int, error) {
+		calls++
+		cancel()
+		return 0, errors.New("transient")
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Errorf("Expected cancellation after 1 call, got %v after %d", err, calls)
+	}
+}
+
+// instantClock records the delays it is asked to wait and fires at once
+type instantClock struct {
+	delays []time.Duration
+}
+
+func (c *instantClock) Now() time.Time { return time.Time{} }
+
+func (c *instantClock) After(d time.Duration) <-chan time.Time {
+	c.delays = append(c.delays, d)
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+func TestRetry_Clock(t *testing.T) {
+	clk := &instantClock{}
+	policy := RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Minute, MaxBackoff: time.Hour, BackoffFactor: 2.0, Clock: clk}
+
+	_, err := Retry(context.Background(), policy, func() (int, error) {
+		return 0, errors.New("transient")
+	})
+	if !errors.Is(err, ErrMaxRetriesExceeded) {
+		t.Fatalf("Expected ErrMaxRetriesExceeded, got %v", err)
+	}
+	if len(clk.delays) != 3 || clk.delays[0] != time.Minute || clk.delays[2] != 4*time.Minute {
+		t.Errorf("Expected exponential delays on the clock, got %v", clk.delays)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, BackoffFactor: 2.0}
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, want := range expected {
+		if got := policy.Backoff(i+1, 0); got != want {
+			t.Errorf("Attempt %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+
+	policy.Jitter = JitterFull
+	for attempt := 1; attempt <= 5; attempt++ {
+		if got := policy.Backoff(attempt, 0); got < 0 || got > expected[attempt-1] {
+			t.Errorf("Attempt %d: jittered delay %v outside [0, %v]", attempt, got, expected[attempt-1])
+		}
+	}
+	uncapped := RetryPolicy{InitialBackoff: time.Second, BackoffFactor: 2.0}
+	if got := uncapped.Backoff(4, 0); got != 8*time.Second {
+		t.Errorf("Expected no cap without MaxBackoff, got %v", got)
+	}
+	if got := uncapped.Backoff(100, 0); got <= 0 {
+		t.Errorf("Expected an overflowing delay to saturate, got %v", got)
+	}
+	uncapped.Jitter = JitterDecorrelated
+	if got := uncapped.Backoff(2, time.Minute); got < time.Second || got > 3*time.Minute {
+		t.Errorf("Expected a decorrelated delay in [1s, 3m], got %v", got)
+	}
+}
@@ -0,0 +1,117 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Set is an unordered collection of unique values. The zero value is not
+// usable; create sets with NewSet. Sets marshal to JSON arrays sorted by
+// their encoded form, so output is stable.
+type Set[T comparable] map[T]struct{}
+
+// NewSet creates a set containing items
+func NewSet[T comparable](items ...T) Set[T] {
+	s := make(Set[T], len(items))
+	s.Add(items...)
+	return s
+}
+
+// Add inserts items into the set
+func (s Set[T]) Add(items ...T) {
+	for _, item := range items {
+		s[item] = struct{}{}
+	}
+}
+
+// Remove deletes items from the set
+func (s Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(s, item)
+	}
+}
+
+// Has reports whether item is in the set
+func (s Set[T]) Has(item T) bool {
+	_, ok := s[item]
+	return ok
+}
+
+// Len returns the number of items in the set
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Items returns the items of the set in unspecified order
+func (s Set[T]) Items() []T {
+	return Keys(s)
+}
+
+// Union returns a new set with the items in s or other
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	result := make(Set[T], len(s)+len(other))
+	for item := range s {
+		result[item] = struct{}{}
+	}
+	for item := range other {
+		result[item] = struct{}{}
+	}
+	return result
+}
+
+// Intersect returns a new set with the items in both s and other
+func (s Set[T]) Intersect(other Set[T]) Set[T] {
+	small, large := s, other
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	result := make(Set[T])
+	for item := range small {
+		if large.Has(item) {
+			result[item] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Difference returns a new set with the items in s that are not in other
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	result := make(Set[T])
+	for item := range s {
+		if !other.Has(item) {
+			result[item] = struct{}{}
+		}
+	}
+	return result
+}
+
+// MarshalJSON encodes the set as a JSON array in a stable order
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	encoded := make([][]byte, 0, len(s))
+	for item := range s {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, data)
+	}
+	sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 })
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	buf.Write(bytes.Join(encoded, []byte(",")))
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a JSON array into the set, dropping duplicates
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("failed to unmarshal set: %w", err)
+	}
+	*s = NewSet(items...)
+	return nil
+}
@@ -0,0 +1,62 @@
+package helpers
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet("a", "b", "a")
+	if s.Len() != 2 || !s.Has("a") || s.Has("z") {
+		t.Errorf("Unexpected set: %v", s)
+	}
+	s.Add("c")
+	s.Remove("a", "missing")
+	if s.Len() != 2 || s.Has("a") || !s.Has("c") {
+		t.Errorf("Unexpected set after Add/Remove: %v", s)
+	}
+}
+
+func TestSet_Operations(t *testing.T) {
+	a := NewSet(1, 2, 3)
+	b := NewSet(2, 3, 4)
+
+	assertSet := func(name string, got Set[int], want ...int) {
+		t.Helper()
+		if got.Len() != len(want) {
+			t.Errorf("%s: expected %v, got %v", name, want, got.Items())
+			return
+		}
+		for _, item := range want {
+			if !got.Has(item) {
+				t.Errorf("%s: expected %v, got %v", name, want, got.Items())
+				return
+			}
+		}
+	}
+
+	assertSet("Union", a.Union(b), 1, 2, 3, 4)
+	assertSet("Intersect", a.Intersect(b), 2, 3)
+	assertSet("Difference", a.Difference(b), 1)
+	assertSet("original", a, 1, 2, 3)
+}
+
+func TestSet_JSON(t *testing.T) {
+	data, err := json.Marshal(NewSet("c", "a", "b"))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `["a","b","c"]` {
+		t.Errorf("Expected sorted array, got %s", data)
+	}
+
+	var decoded struct {
+		Tags Set[string] `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(`{"tags":["x","y","x"]}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Tags.Len() != 2 || !decoded.Tags.Has("y") {
+		t.Errorf("Unexpected decoded set: %v", decoded.Tags)
+	}
+}
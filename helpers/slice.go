@@ -0,0 +1,83 @@
+package helpers
+
+// Map returns the result of applying fn to each element of items
+func Map[T, R any](items []T, fn func(T) R) []R {
+	result := make([]R, len(items))
+	for i, item := range items {
+		result[i] = fn(item)
+	}
+	return result
+}
+
+// Filter returns the elements of items for which keep returns true
+func Filter[T any](items []T, keep func(T) bool) []T {
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		if keep(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Reduce folds items into a single value, starting from initial
+func Reduce[T, R any](items []T, initial R, fn func(R, T) R) R {
+	result := initial
+	for _, item := range items {
+		result = fn(result, item)
+	}
+	return result
+}
+
+// Chunk splits items into consecutive slices of at most size elements.
+// It panics if size is less than 1.
+func Chunk[T any](items []T, size int) [][]T {
+	if size < 1 {
+		panic("helpers: chunk size must be at least 1")
+	}
+	chunks := make([][]T, 0, (len(items)+size-1)/size)
+	for start := 0; start < len(items); start += size {
+		end := min(start+size, len(items))
+		chunks = append(chunks, items[start:end:end])
+	}
+	return chunks
+}
+
+// Unique returns items without duplicates, keeping the first occurrence of each
+func Unique[T comparable](items []T) []T {
+	seen := make(map[T]struct{}, len(items))
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		if _, ok := seen[item]; ok {
+			continue
+		}
+		seen[item] = struct{}{}
+		result = append(result, item)
+	}
+	return result
+}
+
+// GroupBy groups items by the key returned by keyFn, keeping their order within each group
+func GroupBy[T any, K comparable](items []T, keyFn func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, item := range items {
+		key := keyFn(item)
+		groups[key] = append(groups[key], item)
+	}
+	return groups
+}
+
+// Contains reports whether value is present in items
+func Contains[T comparable](items []T, value T) bool {
+	return IndexOf(items, value) >= 0
+}
+
+// IndexOf returns the index of the first occurrence of value in items, or -1
+func IndexOf[T comparable](items []T, value T) int {
+	for i, item := range items {
+		if item == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,69 @@
+package helpers
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestMapFilterReduce(t *testing.T) {
+	numbers := []int{1, 2, 3, 4}
+
+	if got := Map(numbers, strconv.Itoa); !reflect.DeepEqual(got, []string{"1", "2", "3", "4"}) {
+		t.Errorf("Map: got %v", got)
+	}
+	if got := Filter(numbers, func(n int) bool { return n%2 == 0 }); !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Errorf("Filter: got %v", got)
+	}
+	if got := Reduce(numbers, 0, func(sum, n int) int { return sum + n }); got != 10 {
+		t.Errorf("Reduce: got %d", got)
+	}
+	if got := Map([]int(nil), strconv.Itoa); len(got) != 0 {
+		t.Errorf("Map on nil: got %v", got)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	chunks := Chunk([]int{1, 2, 3, 4, 5}, 2)
+	if !reflect.DeepEqual(chunks, [][]int{{1, 2}, {3, 4}, {5}}) {
+		t.Errorf("Chunk: got %v", chunks)
+	}
+
+	// Appending to a chunk must not overwrite the next one
+	chunks[0] = append(chunks[0], 99)
+	if chunks[1][0] != 3 {
+		t.Error("Expected chunks to have independent capacity")
+	}
+
+	if got := Chunk([]int{}, 3); len(got) != 0 {
+		t.Errorf("Chunk on empty: got %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Chunk with size 0 to panic")
+		}
+	}()
+	Chunk([]int{1}, 0)
+}
+
+func TestUniqueGroupBy(t *testing.T) {
+	if got := Unique([]string{"b", "a", "b", "c", "a"}); !reflect.DeepEqual(got, []string{"b", "a", "c"}) {
+		t.Errorf("Unique: got %v", got)
+	}
+
+	groups := GroupBy([]string{"apple", "avocado", "banana"}, func(s string) byte { return s[0] })
+	if !reflect.DeepEqual(groups, map[byte][]string{'a': {"apple", "avocado"}, 'b': {"banana"}}) {
+		t.Errorf("GroupBy: got %v", groups)
+	}
+}
+
+func TestContainsIndexOf(t *testing.T) {
+	items := []string{"a", "b", "c", "b"}
+	if !Contains(items, "c") || Contains(items, "z") {
+		t.Error("Contains returned wrong result")
+	}
+	if IndexOf(items, "b") != 1 || IndexOf(items, "z") != -1 {
+		t.Error("IndexOf returned wrong result")
+	}
+}
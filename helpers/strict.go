@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/khekrn/core/internal/strictjson"
+)
+
+// Strict decoding errors
+var (
+	ErrUnknownField = strictjson.ErrUnknownField
+	ErrTrailingData = strictjson.ErrTrailingData
+)
+
+// StrictDecodeError reports the field a strict decode rejected: Path is the
+// path of the offending field, e.g. "items[2].sku", and Err is
+// ErrUnknownField or the underlying *json.UnmarshalTypeError
+type StrictDecodeError = strictjson.DecodeError
+
+// FromJSONStrict converts JSON bytes to a struct like FromJSON, but rejects
+// fields that do not exist in T and any data after the JSON value. Field
+// errors are returned as *StrictDecodeError carrying the field path.
+// Strict decoding always uses encoding/json, regardless of SetEngine.
+func FromJSONStrict[T any](jsonData []byte) (*T, error) {
+	var result T
+	if err := strictjson.Decode(jsonData, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FromReaderStrict reads JSON from an io.Reader and converts it to a struct
+// using FromJSONStrict
+func FromReaderStrict[T any](reader io.Reader) (*T, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON data: %w", err)
+	}
+	return FromJSONStrict[T](data)
+}
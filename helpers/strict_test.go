@@ -0,0 +1,104 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type strictAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type strictBase struct {
+	ID int `json:"id"`
+}
+
+type strictOrder struct {
+	strictBase
+	Customer string          `json:"customer"`
+	Address  strictAddress   `json:"address"`
+	Items    []strictAddress `json:"items"`
+	Internal string          `json:"-"`
+}
+
+func TestFromJSONStrict(t *testing.T) {
+	order, err := FromJSONStrict[strictOrder]([]byte(`{"id":7,"CUSTOMER":"acme","address":{"city":"Oslo"},"items":[{"zip":"0150"}]}`))
+	if err != nil {
+		t.Fatalf("FromJSONStrict failed: %v", err)
+	}
+	if order.ID != 7 || order.Customer != "acme" || order.Address.City != "Oslo" || order.Items[0].Zip != "0150" {
+		t.Errorf("Unexpected result: %+v", order)
+	}
+}
+
+func TestFromJSONStrict_UnknownFields(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		path string
+	}{
+		{"top level", `{"id":1,"custmer":"acme"}`, "custmer"},
+		{"nested", `{"address":{"city":"Oslo","zipp":"0150"}}`, "address.zipp"},
+		{"in array", `{"items":[{"zip":"1"},{"zip":"2","country":"NO"}]}`, "items[1].country"},
+		{"ignored field", `{"Internal":"x"}`, "Internal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromJSONStrict[strictOrder]([]byte(tt.json))
+
+			var strictErr *StrictDecodeError
+			if !errors.As(err, &strictErr) {
+				t.Fatalf("Expected StrictDecodeError, got %v", err)
+			}
+			if !errors.Is(err, ErrUnknownField) {
+				t.Errorf("Expected ErrUnknownField, got %v", err)
+			}
+			if strictErr.Path != tt.path {
+				t.Errorf("Expected path %q, got %q", tt.path, strictErr.Path)
+			}
+		})
+	}
+}
+
+func TestFromJSONStrict_TypeMismatch(t *testing.T) {
+	_, err := FromJSONStrict[strictOrder]([]byte(`{"address":{"city":42}}`))
+
+	var strictErr *StrictDecodeError
+	if !errors.As(err, &strictErr) {
+		t.Fatalf("Expected StrictDecodeError, got %v", err)
+	}
+	if strictErr.Path != "address.city" {
+		t.Errorf("Expected path address.city, got %q", strictErr.Path)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Errorf("Expected wrapped UnmarshalTypeError, got %v", err)
+	}
+}
+
+func TestFromJSONStrict_TrailingData(t *testing.T) {
+	for _, input := range []string{`{"id":1}{"id":2}`, `{"id":1} garbage`} {
+		if _, err := FromJSONStrict[strictOrder]([]byte(input)); !errors.Is(err, ErrTrailingData) {
+			t.Errorf("Expected ErrTrailingData for %q, got %v", input, err)
+		}
+	}
+
+	if _, err := FromJSONStrict[strictOrder]([]byte("{\"id\":1}\n  ")); err != nil {
+		t.Errorf("Expected trailing whitespace to be accepted, got %v", err)
+	}
+}
+
+func TestFromReaderStrict(t *testing.T) {
+	order, err := FromReaderStrict[strictOrder](strings.NewReader(`{"id":3}`))
+	if err != nil || order.ID != 3 {
+		t.Errorf("Expected order 3, got %+v (%v)", order, err)
+	}
+
+	if _, err := FromReaderStrict[strictOrder](strings.NewReader(`{"nope":1}`)); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("Expected ErrUnknownField, got %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structField is a struct field as seen by encoding/json
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var structFieldCache sync.Map // map[reflect.Type][]structField
+
+// ToMap converts a struct to a map keyed by JSON field names, honoring json
+// tags ("-", omitempty and renamed fields) and promoting fields of untagged
+// embedded structs. Nested structs become nested maps; other values,
+// including slices and types with custom JSON marshaling, keep their Go
+// values. It uses reflection rather than a JSON round trip.
+func ToMap[T any](data T) (map[string]any, error) {
+	value := reflect.ValueOf(data)
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, fmt.Errorf("cannot convert nil %T to map", data)
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot convert %T to map: not a struct", data)
+	}
+	return structToMap(value), nil
+}
+
+// FromMap converts a map keyed by JSON field names to a struct and returns a
+// pointer to the result. Keys are matched like encoding/json does, preferring
+// exact names over case-insensitive ones, and unknown keys are ignored.
+// Values that are directly assignable are set via reflection; anything else,
+// such as float64 into an int field, is converted through JSON.
+func FromMap[T any](m map[string]any) (*T, error) {
+	var result T
+	value := reflect.ValueOf(&result).Elem()
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot convert map to %T: not a struct", result)
+	}
+	if err := mapToStruct(m, value, ""); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// structToMap converts a struct value to a map
+func structToMap(value reflect.Value) map[string]any {
+	fields := cachedStructFields(value.Type())
+	result := make(map[string]any, len(fields))
+	for _, field := range fields {
+		fieldValue, err := value.FieldByIndexErr(field.index)
+		if err != nil {
+			continue // nil embedded pointer
+		}
+		if field.omitEmpty && isEmptyJSONValue(fieldValue) {
+			continue
+		}
+		result[field.name] = toMapValue(fieldValue)
+	}
+	return result
+}
+
+// toMapValue converts nested structs to maps and leaves other values as-is
+func toMapValue(value reflect.Value) any {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		if value.Elem().Kind() == reflect.Struct && !hasCustomJSON(value.Elem().Type()) {
+			return structToMap(value.Elem())
+		}
+	}
+	if value.Kind() == reflect.Struct && !hasCustomJSON(value.Type()) {
+		return structToMap(value)
+	}
+	return value.Interface()
+}
+
+// mapToStruct sets the fields of value from m
+func mapToStruct(m map[string]any, value reflect.Value, path string) error {
+	fields := cachedStructFields(value.Type())
+	for key, item := range m {
+		field, ok := matchStructField(fields, key)
+		if !ok {
+			continue
+		}
+		fieldPath := joinKey(path, field.name)
+		target, err := fieldByIndexAlloc(value, field.index)
+		if err != nil {
+			return fmt.Errorf("failed to convert field %s: %w", fieldPath, err)
+		}
+		if err := setFromMapValue(target, item, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setFromMapValue assigns item to target, recursing into nested maps
+func setFromMapValue(target reflect.Value, item any, path string) error {
+	if item == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	itemValue := reflect.ValueOf(item)
+	if itemValue.Type().AssignableTo(target.Type()) {
+		target.Set(itemValue)
+		return nil
+	}
+
+	if nested, ok := item.(map[string]any); ok {
+		structValue := target
+		if target.Kind() == reflect.Pointer && target.Type().Elem().Kind() == reflect.Struct {
+			if target.IsNil() {
+				target.Set(reflect.New(target.Type().Elem()))
+			}
+			structValue = target.Elem()
+		}
+		if structValue.Kind() == reflect.Struct && !hasCustomJSON(structValue.Type()) {
+			return mapToStruct(nested, structValue, path)
+		}
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to convert field %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, target.Addr().Interface()); err != nil {
+		return fmt.Errorf("failed to convert field %s: %w", path, err)
+	}
+	return nil
+}
+
+// fieldByIndexAlloc returns the field at index, allocating nil embedded pointers
+func fieldByIndexAlloc(value reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				if !value.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %s", value.Type().Elem())
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(x)
+	}
+	return value, nil
+}
+
+// matchStructField finds the field for key, preferring an exact match
+func matchStructField(fields []structField, key string) (structField, bool) {
+	var fold structField
+	var folded bool
+	for _, field := range fields {
+		if field.name == key {
+			return field, true
+		}
+		if !folded && strings.EqualFold(field.name, key) {
+			fold, folded = field, true
+		}
+	}
+	return fold, folded
+}
+
+// cachedStructFields returns the JSON fields of t, computing them once per type
+func cachedStructFields(t reflect.Type) []structField {
+	if fields, ok := structFieldCache.Load(t); ok {
+		return fields.([]structField)
+	}
+	fields := collectStructFields(t, nil)
+
+	// Like encoding/json, a shallower field hides deeper fields with the same name
+	byName := make(map[string]int)
+	var visible []structField
+	for _, field := range fields {
+		if i, ok := byName[field.name]; ok {
+			if len(field.index) < len(visible[i].index) {
+				visible[i] = field
+			}
+			continue
+		}
+		byName[field.name] = len(visible)
+		visible = append(visible, field)
+	}
+
+	structFieldCache.Store(t, visible)
+	return visible
+}
+
+// collectStructFields lists the JSON fields of t, including promoted fields
+func collectStructFields(t reflect.Type, parent []int) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, hasOptions := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && !hasOptions {
+			continue
+		}
+		index := append(append([]int{}, parent...), i)
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, collectStructFields(embedded, index)...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, structField{
+			name:      name,
+			index:     index,
+			omitEmpty: containsFold(strings.Split(options, ","), "omitempty"),
+		})
+	}
+	return fields
+}
+
+// hasCustomJSON reports whether t controls its own JSON encoding
+func hasCustomJSON(t reflect.Type) bool {
+	pointer := reflect.PointerTo(t)
+	return pointer.Implements(reflect.TypeFor[json.Marshaler]()) ||
+		pointer.Implements(reflect.TypeFor[json.Unmarshaler]()) ||
+		pointer.Implements(reflect.TypeFor[interface{ MarshalText() ([]byte, error) }]())
+}
+
+// isEmptyJSONValue reports whether omitempty would drop value
+func isEmptyJSONValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return value.IsZero()
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mapAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type MapBase struct {
+	ID   int64  `json:"id"`
+	Name string `json:"base_name"`
+}
+
+type mapUser struct {
+	MapBase
+	Name      string            `json:"name"`
+	Email     string            `json:"email,omitempty"`
+	Age       int               `json:"age"`
+	Address   mapAddress        `json:"address"`
+	Billing   *mapAddress       `json:"billing"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	Secret    string            `json:"-"`
+	internal  string
+	Untagged  bool
+}
+
+func TestToMap(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	user := mapUser{
+		MapBase:   MapBase{ID: 9007199254740993, Name: "base"},
+		Name:      "John",
+		Age:       30,
+		Address:   mapAddress{City: "Oslo"},
+		Tags:      []string{"a"},
+		CreatedAt: created,
+		Secret:    "hidden",
+		internal:  "x",
+		Untagged:  true,
+	}
+
+	m, err := ToMap(&user)
+	if err != nil {
+		t.Fatalf("ToMap failed: %v", err)
+	}
+
+	expected := map[string]any{
+		"id":         int64(9007199254740993),
+		"base_name":  "base",
+		"name":       "John",
+		"age":        30,
+		"address":    map[string]any{"city": "Oslo"},
+		"billing":    nil,
+		"tags":       []string{"a"},
+		"created_at": created,
+		"Untagged":   true,
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, m)
+	}
+}
+
+func TestToMap_Errors(t *testing.T) {
+	if _, err := ToMap(42); err == nil {
+		t.Error("Expected non-struct to fail")
+	}
+	var user *mapUser
+	if _, err := ToMap(user); err == nil {
+		t.Error("Expected nil pointer to fail")
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	user, err := FromMap[mapUser](map[string]any{
+		"id":         float64(42),
+		"base_name":  "base",
+		"NAME":       "John",
+		"age":        int32(30),
+		"address":    map[string]any{"city": "Oslo", "zip": "0150"},
+		"billing":    map[string]any{"city": "Bergen"},
+		"tags":       []any{"a", "b"},
+		"labels":     map[string]string{"tier": "gold"},
+		"created_at": "2024-01-02T03:04:05Z",
+		"-":          "ignored",
+		"unknown":    true,
+	})
+	if err != nil {
+		t.Fatalf("FromMap failed: %v", err)
+	}
+
+	if user.ID != 42 || user.MapBase.Name != "base" || user.Name != "John" || user.Age != 30 {
+		t.Errorf("Unexpected scalar fields: %+v", user)
+	}
+	if user.Address.Zip != "0150" || user.Billing == nil || user.Billing.City != "Bergen" {
+		t.Errorf("Unexpected nested fields: %+v %+v", user.Address, user.Billing)
+	}
+	if !reflect.DeepEqual(user.Tags, []string{"a", "b"}) || user.Labels["tier"] != "gold" {
+		t.Errorf("Unexpected collections: %v %v", user.Tags, user.Labels)
+	}
+	if !user.CreatedAt.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Unexpected time: %v", user.CreatedAt)
+	}
+	if user.Secret != "" {
+		t.Error("Expected json:\"-\" field to be ignored")
+	}
+}
+
+func TestFromMap_RoundTrip(t *testing.T) {
+	original := mapUser{MapBase: MapBase{ID: 7}, Name: "Jane", Address: mapAddress{City: "Oslo"}, Billing: &mapAddress{City: "Bergen"}}
+
+	m, err := ToMap(original)
+	if err != nil {
+		t.Fatalf("ToMap failed: %v", err)
+	}
+	decoded, err := FromMap[mapUser](m)
+	if err != nil {
+		t.Fatalf("FromMap failed: %v", err)
+	}
+	if !reflect.DeepEqual(*decoded, original) {
+		t.Errorf("Expected %+v, got %+v", original, *decoded)
+	}
+}
+
+func TestFromMap_Errors(t *testing.T) {
+	if _, err := FromMap[mapUser](map[string]any{"age": 1.5}); err == nil {
+		t.Error("Expected fractional age to fail")
+	}
+	if _, err := FromMap[int](map[string]any{}); err == nil {
+		t.Error("Expected non-struct target to fail")
+	}
+}
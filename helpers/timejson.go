@@ -0,0 +1,176 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// flexTimeLayouts are the text layouts FlexTime accepts, tried in order.
+// Layouts without a zone are read as UTC.
+var flexTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	time.DateOnly,
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// epochMillisThreshold separates epoch seconds from milliseconds: 1e12
+// seconds is in the year 33658, while 1e12 milliseconds is in 2001
+const epochMillisThreshold = 1e12
+
+// FlexTime is a time.Time that unmarshals from RFC 3339, common date-time
+// layouts without a zone, yyyy-mm-dd, RFC 1123, and epoch seconds or
+// milliseconds given as numbers or strings. It marshals as RFC 3339 in UTC,
+// or null when zero.
+type FlexTime struct {
+	time.Time
+}
+
+// MarshalJSON encodes the time as an RFC 3339 string in UTC
+func (t FlexTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.UTC().Format(time.RFC3339Nano))
+}
+
+// UnmarshalJSON decodes any of the supported layouts
+func (t *FlexTime) UnmarshalJSON(data []byte) error {
+	parsed, err := parseFlexTime(data)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// UnixTime is a time.Time that marshals as epoch seconds and unmarshals
+// from the same inputs as FlexTime
+type UnixTime struct {
+	time.Time
+}
+
+// MarshalJSON encodes the time as whole epoch seconds, or null when zero
+func (t UnixTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return strconv.AppendInt(nil, t.Unix(), 10), nil
+}
+
+// UnmarshalJSON decodes any of the FlexTime layouts
+func (t *UnixTime) UnmarshalJSON(data []byte) error {
+	parsed, err := parseFlexTime(data)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// DateOnly is a calendar date that marshals as yyyy-mm-dd. It unmarshals
+// from the same inputs as FlexTime, keeping the date in the input's zone
+// and dropping the time of day; the result is midnight UTC.
+type DateOnly struct {
+	time.Time
+}
+
+// MarshalJSON encodes the date as yyyy-mm-dd, or null when zero
+func (d DateOnly) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.Format(time.DateOnly))
+}
+
+// UnmarshalJSON decodes any of the FlexTime layouts and truncates to the date
+func (d *DateOnly) UnmarshalJSON(data []byte) error {
+	parsed, err := parseFlexTime(data)
+	if err != nil {
+		return err
+	}
+	if parsed.IsZero() {
+		d.Time = time.Time{}
+		return nil
+	}
+	year, month, day := parsed.Date()
+	d.Time = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return nil
+}
+
+// parseFlexTime decodes a JSON string or number in any FlexTime layout.
+// null and the empty string decode to the zero time.
+func parseFlexTime(data []byte) (time.Time, error) {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return time.Time{}, nil
+	}
+
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %s: %w", data, err)
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return time.Time{}, nil
+		}
+	}
+
+	if isEpochNumber(text) {
+		return parseEpoch(text)
+	}
+	for _, layout := range flexTimeLayouts {
+		if parsed, err := time.Parse(layout, text); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %s: unsupported format", data)
+}
+
+// isEpochNumber reports whether text looks like epoch seconds or milliseconds
+func isEpochNumber(text string) bool {
+	text = strings.TrimPrefix(text, "-")
+	if text == "" || text[0] == '.' {
+		return false
+	}
+	dot := false
+	for _, r := range text {
+		switch {
+		case r == '.' && !dot:
+			dot = true
+		case r < '0' || r > '9':
+			return false
+		}
+	}
+	return true
+}
+
+// parseEpoch converts epoch seconds or milliseconds, optionally fractional,
+// to a UTC time
+func parseEpoch(text string) (time.Time, error) {
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		if n >= epochMillisThreshold || n <= -epochMillisThreshold {
+			return time.UnixMilli(n).UTC(), nil
+		}
+		return time.Unix(n, 0).UTC(), nil
+	}
+
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid epoch time %q: %w", text, err)
+	}
+	if math.Abs(f) >= epochMillisThreshold {
+		f /= 1000
+	}
+	seconds, fraction := math.Modf(f)
+	return time.Unix(int64(seconds), int64(math.Round(fraction*1e9))).UTC(), nil
+}
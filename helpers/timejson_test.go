@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestFlexTime_Unmarshal(t *testing.T) {
+	expected := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{`"2024-03-01T12:30:45Z"`, expected},
+		{`"2024-03-01T14:30:45+02:00"`, expected},
+		{`"2024-03-01T12:30:45"`, expected},
+		{`"2024-03-01 12:30:45"`, expected},
+		{`"Fri, 01 Mar 2024 12:30:45 GMT"`, expected},
+		{`1709296245`, expected},
+		{`"1709296245"`, expected},
+		{`1709296245000`, expected},
+		{`1709296245.5`, expected.Add(500 * time.Millisecond)},
+		{`"2024-03-01"`, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{`null`, time.Time{}},
+		{`""`, time.Time{}},
+	}
+	for _, tt := range tests {
+		var got FlexTime
+		if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.input, tt.expected, got.Time)
+		}
+	}
+
+	var got FlexTime
+	if err := json.Unmarshal([]byte(`"next tuesday"`), &got); err == nil {
+		t.Error("Expected unsupported format to fail")
+	}
+}
+
+func TestTimeTypes_Marshal(t *testing.T) {
+	moment := time.Date(2024, 3, 1, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600))
+	value := struct {
+		Flex  FlexTime `json:"flex"`
+		Unix  UnixTime `json:"unix"`
+		Date  DateOnly `json:"date"`
+		Empty FlexTime `json:"empty"`
+	}{FlexTime{moment}, UnixTime{moment}, DateOnly{moment}, FlexTime{}}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	expected := `{"flex":"2024-03-02T04:30:00Z","unix":1709353800,"date":"2024-03-01","empty":null}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
+
+func TestDateOnly_Unmarshal(t *testing.T) {
+	var date DateOnly
+	if err := json.Unmarshal([]byte(`"2024-03-01T23:30:00-05:00"`), &date); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !date.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 2024-03-01 in the input's zone, got %v", date.Time)
+	}
+
+	var unix UnixTime
+	if err := json.Unmarshal([]byte(`"2024-03-01"`), &unix); err != nil || unix.Unix() != 1709251200 {
+		t.Errorf("Unexpected UnixTime %v, %v", unix.Time, err)
+	}
+}
@@ -0,0 +1,20 @@
+package helpers
+
+import (
+	"github.com/khekrn/core/response"
+	"github.com/khekrn/core/validation"
+)
+
+// ValidateStruct validates a struct using its `validate` tags and returns the
+// failures keyed by JSON field names, or nil when it is valid. It uses the
+// validation package's default validator, so its shared rules (phone, iban,
+// currency) and any rules added with validation.RegisterRule apply.
+//
+// Example:
+//
+//	if errs := helpers.ValidateStruct(req); errs != nil {
+//		resp := response.NewErrorResponseWithValidationErrors("Validation failed", errs...)
+//	}
+func ValidateStruct(v any) []response.ValidationError {
+	return validation.Struct(v)
+}
@@ -0,0 +1,29 @@
+package helpers
+
+import "testing"
+
+func TestValidateStruct(t *testing.T) {
+	type Request struct {
+		Email    string `json:"email" validate:"required,email"`
+		Currency string `json:"currency" validate:"required,currency"`
+		Age      int    `json:"age" validate:"gte=0"`
+	}
+
+	if errs := ValidateStruct(Request{Email: "a@b.co", Currency: "EUR"}); errs != nil {
+		t.Errorf("Expected valid request, got %v", errs)
+	}
+
+	errs := ValidateStruct(Request{Email: "nope", Currency: "XXX", Age: -1})
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 errors, got %v", errs)
+	}
+	fields := map[string]bool{}
+	for _, err := range errs {
+		fields[err.Field] = err.Reason != ""
+	}
+	for _, field := range []string{"email", "currency", "age"} {
+		if !fields[field] {
+			t.Errorf("Expected a reason for field %s, got %v", field, errs)
+		}
+	}
+}
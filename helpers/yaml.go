@@ -0,0 +1,301 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLToJSON converts a YAML document to JSON. Mapping keys keep their
+// document order, integers keep their full precision, anchors and merge keys
+// are resolved, and only the first document of a multi-document stream is
+// converted. Empty input converts to null.
+func YAMLToJSON(yamlData []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(yamlData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+	}
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		return []byte("null"), nil
+	}
+
+	var buf bytes.Buffer
+	if err := writeYAMLNodeJSON(&buf, doc.Content[0]); err != nil {
+		return nil, fmt.Errorf("failed to convert YAML to JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// YAMLDocumentsToJSON converts every document in a multi-document YAML
+// stream, such as a set of Kubernetes manifests, to JSON. Empty documents
+// are skipped.
+func YAMLDocumentsToJSON(r io.Reader) ([][]byte, error) {
+	decoder := yaml.NewDecoder(r)
+	var documents [][]byte
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return documents, nil
+			}
+			return nil, fmt.Errorf("failed to unmarshal YAML document %d: %w", len(documents)+1, err)
+		}
+		if len(doc.Content) == 0 || (doc.Content[0].ShortTag() == "!!null" && doc.Content[0].Value == "") {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := writeYAMLNodeJSON(&buf, &doc); err != nil {
+			return nil, fmt.Errorf("failed to convert YAML document %d to JSON: %w", len(documents)+1, err)
+		}
+		documents = append(documents, buf.Bytes())
+	}
+}
+
+// JSONToYAML converts a JSON document to block-style YAML, keeping object
+// key order and the exact text of numbers
+func JSONToYAML(jsonData []byte) ([]byte, error) {
+	if !json.Valid(jsonData) {
+		return nil, fmt.Errorf("invalid JSON data")
+	}
+
+	// JSON is a subset of YAML, so the YAML parser gives an ordered node tree
+	var doc yaml.Node
+	if err := yaml.Unmarshal(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	resetYAMLStyle(&doc)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// FromYAML converts YAML bytes to a struct and returns a pointer to the result.
+// The document goes through YAMLToJSON, so struct json tags apply.
+func FromYAML[T any](yamlData []byte) (*T, error) {
+	jsonData, err := YAMLToJSON(yamlData)
+	if err != nil {
+		return nil, err
+	}
+	return FromJSON[T](jsonData)
+}
+
+// FromYAMLValue converts YAML bytes to a struct and returns the value (not pointer)
+func FromYAMLValue[T any](yamlData []byte) (T, error) {
+	var result T
+	jsonData, err := YAMLToJSON(yamlData)
+	if err != nil {
+		return result, err
+	}
+	return FromJSONValue[T](jsonData)
+}
+
+// ToYAML converts any value to YAML bytes using its JSON encoding, so struct
+// json tags and MarshalJSON methods apply
+func ToYAML[T any](data T) ([]byte, error) {
+	jsonData, err := ToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return JSONToYAML(jsonData)
+}
+
+// writeYAMLNodeJSON writes node to buf as JSON
+func writeYAMLNodeJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeYAMLNodeJSON(buf, node.Content[0])
+	case yaml.AliasNode:
+		return writeYAMLNodeJSON(buf, node.Alias)
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeYAMLNodeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case yaml.MappingNode:
+		keys, values, err := yamlMappingEntries(node)
+		if err != nil {
+			return err
+		}
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			encodedKey, _ := json.Marshal(key)
+			buf.Write(encodedKey)
+			buf.WriteByte(':')
+			if err := writeYAMLNodeJSON(buf, values[i]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case yaml.ScalarNode:
+		return writeYAMLScalarJSON(buf, node)
+	}
+	return fmt.Errorf("unsupported YAML node kind %d at line %d", node.Kind, node.Line)
+}
+
+// yamlMappingEntries returns the keys and values of a mapping in document
+// order, applying "<<" merge keys and letting later keys override earlier ones
+func yamlMappingEntries(node *yaml.Node) ([]string, []*yaml.Node, error) {
+	var keys []string
+	var values []*yaml.Node
+	index := make(map[string]int)
+
+	set := func(key string, value *yaml.Node, override bool) {
+		if i, ok := index[key]; ok {
+			if override {
+				values[i] = value
+			}
+			return
+		}
+		index[key] = len(keys)
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, value := node.Content[i], node.Content[i+1]
+		if keyNode.Kind == yaml.AliasNode {
+			keyNode = keyNode.Alias
+		}
+
+		if keyNode.Kind == yaml.ScalarNode && keyNode.Tag == "!!merge" {
+			sources := []*yaml.Node{value}
+			if value.Kind == yaml.SequenceNode {
+				sources = value.Content
+			}
+			for _, source := range sources {
+				if source.Kind == yaml.AliasNode {
+					source = source.Alias
+				}
+				if source.Kind != yaml.MappingNode {
+					return nil, nil, fmt.Errorf("merge key at line %d must reference a mapping", keyNode.Line)
+				}
+				mergedKeys, mergedValues, err := yamlMappingEntries(source)
+				if err != nil {
+					return nil, nil, err
+				}
+				for j, key := range mergedKeys {
+					set(key, mergedValues[j], false)
+				}
+			}
+			continue
+		}
+
+		if keyNode.Kind != yaml.ScalarNode {
+			return nil, nil, fmt.Errorf("unsupported non-scalar mapping key at line %d", keyNode.Line)
+		}
+		set(keyNode.Value, value, true)
+	}
+	return keys, values, nil
+}
+
+// writeYAMLScalarJSON writes a resolved YAML scalar to buf as JSON
+func writeYAMLScalarJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.ShortTag() {
+	case "!!null":
+		buf.WriteString("null")
+		return nil
+	case "!!bool":
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return err
+		}
+		buf.WriteString(strconv.FormatBool(b))
+		return nil
+	case "!!int":
+		// Plain decimal integers are copied verbatim to keep full precision
+		text := strings.TrimPrefix(strings.ReplaceAll(node.Value, "_", ""), "+")
+		if isJSONInteger(text) {
+			buf.WriteString(text)
+			return nil
+		}
+		var n int64
+		if err := node.Decode(&n); err != nil {
+			var u uint64
+			if err := node.Decode(&u); err != nil {
+				return err
+			}
+			buf.WriteString(strconv.FormatUint(u, 10))
+			return nil
+		}
+		buf.WriteString(strconv.FormatInt(n, 10))
+		return nil
+	case "!!float":
+		text := strings.TrimPrefix(strings.ReplaceAll(node.Value, "_", ""), "+")
+		if json.Valid([]byte(text)) {
+			buf.WriteString(text)
+			return nil
+		}
+		var f float64
+		if err := node.Decode(&f); err != nil {
+			return err
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("YAML value %q at line %d cannot be represented in JSON", node.Value, node.Line)
+		}
+		encoded, _ := json.Marshal(f)
+		buf.Write(encoded)
+		return nil
+	}
+
+	// Strings, timestamps and binary values are emitted as their text
+	encoded, err := json.Marshal(node.Value)
+	if err != nil {
+		return err
+	}
+	buf.Write(encoded)
+	return nil
+}
+
+// isJSONInteger reports whether s is a valid JSON integer literal
+func isJSONInteger(s string) bool {
+	digits := strings.TrimPrefix(s, "-")
+	if digits == "" || (len(digits) > 1 && digits[0] == '0') {
+		return false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// resetYAMLStyle switches a parsed JSON tree to block style with plain scalars
+// wherever YAML allows it
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
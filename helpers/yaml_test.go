@@ -0,0 +1,112 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected string
+	}{
+		{"key order", "zeta: 1\nalpha: 2\nmid: 3\n", `{"zeta":1,"alpha":2,"mid":3}`},
+		{"large integer", "id: 12345678901234567890\n", `{"id":12345678901234567890}`},
+		{"scalars", "s: hello\nq: \"true\"\nb: yes\nt: true\nn: ~\nf: 1.5\nh: 0x1F\n", `{"s":"hello","q":"true","b":"yes","t":true,"n":null,"f":1.5,"h":31}`},
+		{"nested", "spec:\n  ports:\n    - 80\n    - 443\n  labels:\n    app: web\n", `{"spec":{"ports":[80,443],"labels":{"app":"web"}}}`},
+		{"anchors and merge keys", "base: &base\n  a: 1\n  b: 2\nchild:\n  <<: *base\n  b: 3\n", `{"base":{"a":1,"b":2},"child":{"a":1,"b":3}}`},
+		{"non-string keys", "1: one\ntrue: yes\n", `{"1":"one","true":"yes"}`},
+		{"empty", "", `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := YAMLToJSON([]byte(tt.yaml))
+			if err != nil {
+				t.Fatalf("YAMLToJSON failed: %v", err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestYAMLToJSON_Errors(t *testing.T) {
+	if _, err := YAMLToJSON([]byte("a: [1, 2")); err == nil {
+		t.Error("Expected invalid YAML to fail")
+	}
+	if _, err := YAMLToJSON([]byte("v: .inf\n")); err == nil {
+		t.Error("Expected infinity to fail")
+	}
+}
+
+func TestJSONToYAML(t *testing.T) {
+	result, err := JSONToYAML([]byte(`{"name":"web","replicas":3,"id":12345678901234567890,"enabled":"true","ports":[80,443],"meta":{"z":null,"a":{}}}`))
+	if err != nil {
+		t.Fatalf("JSONToYAML failed: %v", err)
+	}
+
+	expected := `name: web
+replicas: 3
+id: 12345678901234567890
+enabled: "true"
+ports:
+  - 80
+  - 443
+meta:
+  z: null
+  a: {}
+`
+	if string(result) != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+
+	if _, err := JSONToYAML([]byte(`{invalid`)); err == nil {
+		t.Error("Expected invalid JSON to fail")
+	}
+}
+
+func TestYAMLDocumentsToJSON(t *testing.T) {
+	stream := "kind: Service\n---\n---\nkind: Deployment\n"
+
+	documents, err := YAMLDocumentsToJSON(strings.NewReader(stream))
+	if err != nil {
+		t.Fatalf("YAMLDocumentsToJSON failed: %v", err)
+	}
+	if len(documents) != 2 || string(documents[0]) != `{"kind":"Service"}` || string(documents[1]) != `{"kind":"Deployment"}` {
+		t.Errorf("Unexpected documents: %q", documents)
+	}
+}
+
+func TestYAML_TypedRoundTrip(t *testing.T) {
+	type Config struct {
+		Name    string            `json:"name"`
+		Port    int               `json:"port"`
+		Labels  map[string]string `json:"labels"`
+		Enabled bool              `json:"enabled"`
+	}
+
+	original := Config{Name: "api", Port: 8080, Labels: map[string]string{"tier": "backend"}, Enabled: true}
+	data, err := ToYAML(original)
+	if err != nil {
+		t.Fatalf("ToYAML failed: %v", err)
+	}
+
+	decoded, err := FromYAML[Config](data)
+	if err != nil {
+		t.Fatalf("FromYAML failed: %v", err)
+	}
+	if decoded.Name != original.Name || decoded.Port != original.Port || decoded.Labels["tier"] != "backend" || !decoded.Enabled {
+		t.Errorf("Expected %+v, got %+v", original, *decoded)
+	}
+
+	value, err := FromYAMLValue[Config]([]byte("name: worker\nport: 9090\n"))
+	if err != nil {
+		t.Fatalf("FromYAMLValue failed: %v", err)
+	}
+	if value.Name != "worker" || value.Port != 9090 {
+		t.Errorf("Unexpected value: %+v", value)
+	}
+}
@@ -0,0 +1,301 @@
+// Package idempotency provides HTTP middleware that makes unsafe requests
+// safe to retry by honoring the Idempotency-Key header.
+//
+// The first request carrying a given key is executed normally and its
+// response is stored together with a hash of the request. Later requests
+// with the same key receive the stored response without re-running the
+// handler. Reusing a key with a different payload is rejected, as is a
+// duplicate that arrives while the original is still being processed.
+//
+// Example usage:
+//
+//	store := idempotency.NewMemoryStore()
+//	handler := idempotency.Middleware(store,
+//		idempotency.WithTTL(24*time.Hour),
+//		idempotency.WithLockTTL(time.Minute),
+//		idempotency.WithRequiredKey(),
+//	)(mux)
+//
+//	// On the calling side, the REST client sends the key for you
+//	resp, err := restClient.POST("/payments", payment,
+//		client.WithIdempotencyKey(idempotency.NewKey()),
+//	)
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/khekrn/core/idgen"
+	"github.com/khekrn/core/response"
+)
+
+// HeaderKey is the request header carrying the idempotency key
+const HeaderKey = "Idempotency-Key"
+
+// HeaderReplayed is set on responses that were served from the store
+const HeaderReplayed = "Idempotent-Replayed"
+
+// CodeKeyReused is the error code for a key reused with a different
+// request, returned with HTTP 422
+const CodeKeyReused = "ERR_IDEMPOTENCY_KEY_REUSED"
+
+func init() {
+	if err := response.RegisterCode(response.CodeInfo{
+		Code:        CodeKeyReused,
+		HTTPStatus:  http.StatusUnprocessableEntity,
+		Description: "The idempotency key was already used with a different request",
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// ErrNotFound is returned by a Store when no record exists for a key
+var ErrNotFound = errors.New("idempotency: record not found")
+
+// Record represents a stored request fingerprint and, once completed, its response
+type Record struct {
+	Key         string      `json:"key"`
+	RequestHash string      `json:"request_hash"`
+	Completed   bool        `json:"completed"`
+	StatusCode  int         `json:"status_code,omitempty"`
+	Headers     http.Header `json:"headers,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// Store persists idempotency records
+type Store interface {
+	// Reserve atomically creates an in-progress record for the key if none exists.
+	// It returns the existing record and false when the key is already taken.
+	Reserve(ctx context.Context, record *Record, ttl time.Duration) (*Record, bool, error)
+
+	// Complete stores the final response for a previously reserved key
+	Complete(ctx context.Context, record *Record, ttl time.Duration) error
+
+	// Release removes a reservation so the request can be attempted again
+	Release(ctx context.Context, key string) error
+}
+
+// Config holds configuration for the idempotency middleware
+type Config struct {
+	TTL         time.Duration // How long completed responses are kept
+	LockTTL     time.Duration // How long an in-progress reservation holds the key
+	Methods     []string
+	RequireKey  bool
+	MaxBodySize int64
+	KeyScope    func(r *http.Request) string
+}
+
+// Option is a function type for configuring the middleware
+type Option func(*Config)
+
+// WithTTL sets how long completed responses are kept
+func WithTTL(ttl time.Duration) Option {
+	return func(config *Config) {
+		config.TTL = ttl
+	}
+}
+
+// WithLockTTL sets how long a reservation holds the key while the request is
+// processed. A reservation left behind by a crashed instance frees the key
+// once it expires, so keep it short but longer than the slowest handler.
+func WithLockTTL(ttl time.Duration) Option {
+	return func(config *Config) {
+		config.LockTTL = ttl
+	}
+}
+
+// WithMethods sets the HTTP methods the middleware applies to
+func WithMethods(methods ...string) Option {
+	return func(config *Config) {
+		config.Methods = methods
+	}
+}
+
+// WithRequiredKey rejects requests without an Idempotency-Key header
+func WithRequiredKey() Option {
+	return func(config *Config) {
+		config.RequireKey = true
+	}
+}
+
+// WithMaxBodySize limits the request body size read for hashing
+func WithMaxBodySize(size int64) Option {
+	return func(config *Config) {
+		config.MaxBodySize = size
+	}
+}
+
+// WithKeyScope namespaces keys, e.g. per authenticated user or tenant,
+// so that two callers cannot collide on the same key
+func WithKeyScope(scope func(r *http.Request) string) Option {
+	return func(config *Config) {
+		config.KeyScope = scope
+	}
+}
+
+// NewKey generates an idempotency key suitable for the Idempotency-Key header,
+// in the same format as request IDs
+func NewKey() string {
+	return idgen.RequestID()
+}
+
+// Middleware returns an http middleware that deduplicates requests by Idempotency-Key
+func Middleware(store Store, options ...Option) func(http.Handler) http.Handler {
+	config := Config{
+		TTL:         24 * time.Hour,
+		LockTTL:     time.Minute,
+		Methods:     []string{http.MethodPost, http.MethodPatch},
+		MaxBodySize: 1 << 20,
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	methods := make(map[string]bool, len(config.Methods))
+	for _, m := range config.Methods {
+		methods[m] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !methods[r.Method] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := r.Header.Get(HeaderKey)
+			if key == "" {
+				if config.RequireKey {
+					response.WriteError(w, r, response.NewAPIError(response.CodeBadRequest, "Idempotency-Key header is required", nil))
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if config.KeyScope != nil {
+				key = config.KeyScope(r) + ":" + key
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, config.MaxBodySize+1))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					response.WriteError(w, r, response.NewAPIError(response.CodePayloadTooLarge, "Request body too large", err))
+					return
+				}
+				response.WriteError(w, r, response.NewAPIError(response.CodeBadRequest, "Failed to read request body", err))
+				return
+			}
+			if int64(len(body)) > config.MaxBodySize {
+				response.WriteError(w, r, response.NewAPIError(response.CodePayloadTooLarge, "Request body too large", nil))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			record := &Record{
+				Key:         key,
+				RequestHash: hashRequest(r, body),
+				CreatedAt:   time.Now(),
+			}
+
+			existing, reserved, err := store.Reserve(r.Context(), record, config.LockTTL)
+			if err != nil {
+				response.WriteError(w, r, response.NewAPIError(response.CodeInternal, "Failed to check idempotency key", err))
+				return
+			}
+
+			if !reserved {
+				switch {
+				case existing.RequestHash != record.RequestHash:
+					response.WriteError(w, r, response.NewAPIError(CodeKeyReused, "Idempotency-Key was already used with a different request", nil))
+				case !existing.Completed:
+					response.WriteError(w, r, response.NewAPIError(response.CodeConflict, "A request with this Idempotency-Key is still being processed", nil))
+				default:
+					replay(w, existing)
+				}
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			defer func() {
+				if p := recover(); p != nil {
+					_ = store.Release(context.WithoutCancel(r.Context()), key)
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+
+			// Server errors are not cached so the client can safely retry
+			if recorder.statusCode >= 500 {
+				_ = store.Release(context.WithoutCancel(r.Context()), key)
+				return
+			}
+
+			record.Completed = true
+			record.StatusCode = recorder.statusCode
+			record.Headers = w.Header().Clone()
+			record.Body = recorder.body.Bytes()
+			_ = store.Complete(context.WithoutCancel(r.Context()), record, config.TTL)
+		})
+	}
+}
+
+// hashRequest fingerprints the parts of a request that must match on replay
+func hashRequest(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.RequestURI()))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replay writes a stored response back to the client
+func replay(w http.ResponseWriter, record *Record) {
+	for k, values := range record.Headers {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+// responseRecorder captures the status code and body written by a handler
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+// WriteHeader records the status code before passing it through
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if r.wroteHeader {
+		return
+	}
+	r.wroteHeader = true
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write tees the body into the recorder buffer
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
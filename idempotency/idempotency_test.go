@@ -0,0 +1,144 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestHandler(calls *int32, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"id":"123"}`))
+	})
+}
+
+func doRequest(handler http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware_ReplaysDuplicate(t *testing.T) {
+	var calls int32
+	handler := Middleware(NewMemoryStore())(newTestHandler(&calls, http.StatusCreated))
+
+	first := doRequest(handler, "key-1", `{"amount":10}`)
+	second := doRequest(handler, "key-1", `{"amount":10}`)
+
+	if calls != 1 {
+		t.Errorf("Expected handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated {
+		t.Errorf("Expected replayed status 201, got %d", second.Code)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("Expected replayed body %q, got %q", first.Body.String(), second.Body.String())
+	}
+	if second.Header().Get(HeaderReplayed) != "true" {
+		t.Error("Expected Idempotent-Replayed header on replayed response")
+	}
+}
+
+func TestMiddleware_RejectsDifferentPayload(t *testing.T) {
+	var calls int32
+	handler := Middleware(NewMemoryStore())(newTestHandler(&calls, http.StatusCreated))
+
+	doRequest(handler, "key-1", `{"amount":10}`)
+	rec := doRequest(handler, "key-1", `{"amount":20}`)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"code":"`+CodeKeyReused+`"`) {
+		t.Errorf("Expected error code %s, got %s", CodeKeyReused, rec.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("Expected handler to run once, ran %d times", calls)
+	}
+}
+
+func TestMiddleware_ServerErrorsAreNotStored(t *testing.T) {
+	var calls int32
+	handler := Middleware(NewMemoryStore())(newTestHandler(&calls, http.StatusInternalServerError))
+
+	doRequest(handler, "key-1", `{}`)
+	doRequest(handler, "key-1", `{}`)
+
+	if calls != 2 {
+		t.Errorf("Expected handler to run twice after server error, ran %d times", calls)
+	}
+}
+
+func TestMiddleware_RequiredKey(t *testing.T) {
+	var calls int32
+	handler := Middleware(NewMemoryStore(), WithRequiredKey())(newTestHandler(&calls, http.StatusOK))
+
+	rec := doRequest(handler, "", `{}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without key, got %d", rec.Code)
+	}
+	if calls != 0 {
+		t.Errorf("Expected handler not to run, ran %d times", calls)
+	}
+}
+
+func TestMiddleware_InProgressConflict(t *testing.T) {
+	store := NewMemoryStore()
+	req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+	store.Reserve(context.Background(), &Record{Key: "key-1", RequestHash: hashRequest(req, []byte(`{}`))}, time.Hour)
+
+	var calls int32
+	handler := Middleware(store)(newTestHandler(&calls, http.StatusOK))
+
+	rec := doRequest(handler, "key-1", `{}`)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for in-progress key, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"code":"ERR_CONFLICT"`) {
+		t.Errorf("Expected error code ERR_CONFLICT, got %s", rec.Body.String())
+	}
+	if calls != 0 {
+		t.Errorf("Expected handler not to run, ran %d times", calls)
+	}
+}
+
+// ttlStore records the TTLs the middleware passes to the store
+type ttlStore struct {
+	*MemoryStore
+	reserveTTL, completeTTL time.Duration
+}
+
+func (s *ttlStore) Reserve(ctx context.Context, record *Record, ttl time.Duration) (*Record, bool, error) {
+	s.reserveTTL = ttl
+	return s.MemoryStore.Reserve(ctx, record, ttl)
+}
+
+func (s *ttlStore) Complete(ctx context.Context, record *Record, ttl time.Duration) error {
+	s.completeTTL = ttl
+	return s.MemoryStore.Complete(ctx, record, ttl)
+}
+
+func TestMiddleware_LockTTL(t *testing.T) {
+	store := &ttlStore{MemoryStore: NewMemoryStore()}
+	var calls int32
+	handler := Middleware(store, WithTTL(time.Hour), WithLockTTL(10*time.Second))(newTestHandler(&calls, http.StatusCreated))
+
+	doRequest(handler, "key-1", `{}`)
+	if store.reserveTTL != 10*time.Second {
+		t.Errorf("Expected the reservation to use the lock TTL, got %v", store.reserveTTL)
+	}
+	if store.completeTTL != time.Hour {
+		t.Errorf("Expected the completed record to use the TTL, got %v", store.completeTTL)
+	}
+}
@@ -0,0 +1,214 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process Store, suitable for tests and single-instance services
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	record    Record
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]memoryEntry)}
+}
+
+// Reserve creates an in-progress record if the key is free or expired
+func (s *MemoryStore) Reserve(ctx context.Context, record *Record, ttl time.Duration) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.records[record.Key]; ok && time.Now().Before(entry.expiresAt) {
+		existing := entry.record
+		return &existing, false, nil
+	}
+
+	s.records[record.Key] = memoryEntry{record: *record, expiresAt: time.Now().Add(ttl)}
+	return nil, true, nil
+}
+
+// Complete stores the final response for a key
+func (s *MemoryStore) Complete(ctx context.Context, record *Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[record.Key] = memoryEntry{record: *record, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Release removes a key from the store
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// SQLStore persists records in a relational database table.
+//
+// The queries use PostgreSQL syntax. The table can be created with:
+//
+//	CREATE TABLE idempotency_keys (
+//		key          TEXT PRIMARY KEY,
+//		record       JSONB NOT NULL,
+//		expires_at   TIMESTAMPTZ NOT NULL
+//	);
+type SQLStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLStore creates a store backed by the given database and table name
+func NewSQLStore(db *sql.DB, table string) *SQLStore {
+	if table == "" {
+		table = "idempotency_keys"
+	}
+	return &SQLStore{db: db, table: table}
+}
+
+// Reserve inserts an in-progress record unless a live one already exists
+func (s *SQLStore) Reserve(ctx context.Context, record *Record, ttl time.Duration) (*Record, bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	// Expired rows are overwritten so keys can be reused after their TTL
+	query := fmt.Sprintf(`INSERT INTO %s (key, record, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET record = EXCLUDED.record, expires_at = EXCLUDED.expires_at
+		WHERE %s.expires_at < now()`, s.table, s.table)
+	result, err := s.db.ExecContext(ctx, query, record.Key, data, time.Now().Add(ttl))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 1 {
+		return nil, true, nil
+	}
+
+	var stored []byte
+	query = fmt.Sprintf(`SELECT record FROM %s WHERE key = $1`, s.table)
+	if err := s.db.QueryRowContext(ctx, query, record.Key).Scan(&stored); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, ErrNotFound
+		}
+		return nil, false, fmt.Errorf("failed to load idempotency record: %w", err)
+	}
+
+	var existing Record
+	if err := json.Unmarshal(stored, &existing); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal record: %w", err)
+	}
+	return &existing, false, nil
+}
+
+// Complete stores the final response for a key
+func (s *SQLStore) Complete(ctx context.Context, record *Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET record = $2, expires_at = $3 WHERE key = $1`, s.table)
+	if _, err := s.db.ExecContext(ctx, query, record.Key, data, time.Now().Add(ttl)); err != nil {
+		return fmt.Errorf("failed to complete idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Release removes a key from the table
+func (s *SQLStore) Release(ctx context.Context, key string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE key = $1`, s.table)
+	if _, err := s.db.ExecContext(ctx, query, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// RedisClient is the subset of Redis commands needed by RedisStore.
+// It is satisfied by a thin adapter over any Redis driver, keeping this
+// package free of a hard dependency on one.
+type RedisClient interface {
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Del(ctx context.Context, key string) error
+}
+
+// RedisStore persists records in Redis using SET NX for reservations
+type RedisStore struct {
+	client RedisClient
+	prefix string
+}
+
+// NewRedisStore creates a store backed by Redis with the given key prefix
+func NewRedisStore(client RedisClient, prefix string) *RedisStore {
+	if prefix == "" {
+		prefix = "idempotency:"
+	}
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Reserve sets the key only if it does not already exist
+func (s *RedisStore) Reserve(ctx context.Context, record *Record, ttl time.Duration) (*Record, bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	ok, err := s.client.SetNX(ctx, s.prefix+record.Key, data, ttl)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if ok {
+		return nil, true, nil
+	}
+
+	stored, err := s.client.Get(ctx, s.prefix+record.Key)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load idempotency record: %w", err)
+	}
+	if stored == nil {
+		return nil, false, ErrNotFound
+	}
+
+	var existing Record
+	if err := json.Unmarshal(stored, &existing); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal record: %w", err)
+	}
+	return &existing, false, nil
+}
+
+// Complete stores the final response for a key
+func (s *RedisStore) Complete(ctx context.Context, record *Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	if err := s.client.Set(ctx, s.prefix+record.Key, data, ttl); err != nil {
+		return fmt.Errorf("failed to complete idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Release removes a key from Redis
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.prefix+key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+// Package idgen generates the IDs used across services: random UUIDv4s,
+// time-ordered UUIDv7s and ULIDs, and snowflake-style 64-bit IDs.
+//
+// RequestID is the one generator for request IDs, idempotency keys, and
+// audit event IDs, so every ID a request leaves behind has the same format.
+//
+// Example usage:
+//
+//	orderID := idgen.UUIDv7()           // "01890a5d-ac96-774b-bcce-b302099a8057"
+//	token := idgen.UUIDv4()             // random, for unguessable identifiers
+//	eventID := idgen.NewULID().String() // "01H2XCEJQTF2JBRQ8Z9YA7G5TK"
+//
+//	nodes, _ := idgen.NewSnowflake(7)
+//	rowID := nodes.Next() // 64-bit, sortable by creation time
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UUID is an RFC 9562 UUID
+type UUID [16]byte
+
+// NewUUIDv4 returns a random UUID
+func NewUUIDv4() UUID {
+	var u UUID
+	rand.Read(u[:])
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
+
+// v7 holds the state that keeps UUIDv7s from one process strictly increasing
+var v7 struct {
+	sync.Mutex
+	lastMillis int64
+	sequence   uint16
+}
+
+// NewUUIDv7 returns a UUID starting with the current Unix time in
+// milliseconds. UUIDs from one process are strictly increasing: within a
+// millisecond, the 12 bits after the version count up from a random start.
+func NewUUIDv7() UUID {
+	var u UUID
+	rand.Read(u[:])
+
+	v7.Lock()
+	millis := time.Now().UnixMilli()
+	if millis > v7.lastMillis {
+		v7.lastMillis = millis
+		v7.sequence = binary.BigEndian.Uint16(u[6:]) & 0x7ff
+	} else {
+		v7.sequence++
+		if v7.sequence > 0xfff {
+			v7.lastMillis++
+			v7.sequence = 0
+		}
+	}
+	millis, sequence := v7.lastMillis, v7.sequence
+	v7.Unlock()
+
+	u[0] = byte(millis >> 40)
+	u[1] = byte(millis >> 32)
+	u[2] = byte(millis >> 24)
+	u[3] = byte(millis >> 16)
+	u[4] = byte(millis >> 8)
+	u[5] = byte(millis)
+	u[6] = 0x70 | byte(sequence>>8)
+	u[7] = byte(sequence)
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
+
+// ParseUUID parses a UUID in the canonical 8-4-4-4-12 hex form
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+	if _, err := hex.Decode(u[:], []byte(strings.ReplaceAll(s, "-", ""))); err != nil {
+		return u, fmt.Errorf("invalid UUID %q: %w", s, err)
+	}
+	return u, nil
+}
+
+// String returns the UUID in the canonical 8-4-4-4-12 hex form
+func (u UUID) String() string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
+
+// Version returns the UUID version, e.g. 4 or 7
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// Time returns the creation time of a UUIDv7, or the zero time for other
+// versions
+func (u UUID) Time() time.Time {
+	if u.Version() != 7 {
+		return time.Time{}
+	}
+	var b [8]byte
+	copy(b[2:], u[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(b[:])))
+}
+
+// UUIDv4 returns a random UUID string
+func UUIDv4() string {
+	return NewUUIDv4().String()
+}
+
+// UUIDv7 returns a time-ordered UUID string
+func UUIDv7() string {
+	return NewUUIDv7().String()
+}
+
+// RequestID returns a new ID for a request, idempotency key, or audit event.
+// It is a UUIDv7, so IDs sort by creation time and show when they were made.
+func RequestID() string {
+	return UUIDv7()
+}
+
+// crockford is the Crockford base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID is a Universally Unique Lexicographically Sortable Identifier: a
+// 48-bit millisecond timestamp followed by 80 random bits
+type ULID [16]byte
+
+// NewULID returns a ULID for the current time. ULIDs created in the same
+// millisecond are not ordered among themselves.
+func NewULID() ULID {
+	var id ULID
+	rand.Read(id[6:])
+	millis := uint64(time.Now().UnixMilli())
+	for i := 5; i >= 0; i-- {
+		id[i] = byte(millis)
+		millis >>= 8
+	}
+	return id
+}
+
+// ParseULID parses a 26-character ULID string, case-insensitively
+func ParseULID(s string) (ULID, error) {
+	var id ULID
+	if len(s) != 26 || s[0] > '7' {
+		return id, fmt.Errorf("invalid ULID %q", s)
+	}
+	var hi, lo uint64 // 128 bits as two halves
+	for _, c := range strings.ToUpper(s) {
+		value := strings.IndexRune(crockford, c)
+		if value < 0 {
+			return id, fmt.Errorf("invalid ULID %q", s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(value)
+	}
+	binary.BigEndian.PutUint64(id[:8], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id, nil
+}
+
+// String returns the ULID as 26 Crockford base32 characters
+func (id ULID) String() string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	var b [26]byte
+	for i := 25; i >= 0; i-- {
+		b[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(b[:])
+}
+
+// Time returns the creation time of the ULID
+func (id ULID) Time() time.Time {
+	var b [8]byte
+	copy(b[2:], id[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(b[:])))
+}
+
+// Snowflake layout: 41 bits of milliseconds since SnowflakeEpoch, 10 bits of
+// node ID, and 12 bits of sequence
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	MaxSnowflakeNode      = 1<<snowflakeNodeBits - 1
+)
+
+// SnowflakeEpoch is the start of snowflake timestamps, 2020-01-01 UTC
+var SnowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ErrInvalidNode is returned for snowflake node IDs outside 0-1023
+var ErrInvalidNode = errors.New("snowflake node must be between 0 and 1023")
+
+// Snowflake generates 64-bit IDs that sort by creation time. Each instance
+// producing IDs concurrently needs its own node ID, e.g. from a pod ordinal.
+type Snowflake struct {
+	node int64
+
+	mu         sync.Mutex
+	lastMillis int64
+	sequence   int64
+}
+
+// NewSnowflake creates a generator for node, between 0 and MaxSnowflakeNode
+func NewSnowflake(node int64) (*Snowflake, error) {
+	if node < 0 || node > MaxSnowflakeNode {
+		return nil, ErrInvalidNode
+	}
+	return &Snowflake{node: node}, nil
+}
+
+// Next returns a new ID. Up to 4096 IDs are generated per millisecond; when
+// they run out, or the clock moves backwards, IDs continue from the last
+// millisecond used rather than repeat.
+func (s *Snowflake) Next() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	millis := time.Since(SnowflakeEpoch).Milliseconds()
+	if millis > s.lastMillis {
+		s.lastMillis = millis
+		s.sequence = 0
+	} else {
+		s.sequence++
+		if s.sequence >= 1<<snowflakeSequenceBits {
+			s.lastMillis++
+			s.sequence = 0
+		}
+	}
+	return s.lastMillis<<(snowflakeNodeBits+snowflakeSequenceBits) | s.node<<snowflakeSequenceBits | s.sequence
+}
+
+// SnowflakeTime returns the creation time of a snowflake ID
+func SnowflakeTime(id int64) time.Time {
+	return SnowflakeEpoch.Add(time.Duration(id>>(snowflakeNodeBits+snowflakeSequenceBits)) * time.Millisecond)
+}
@@ -0,0 +1,123 @@
+package idgen
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[47][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestUUIDv4(t *testing.T) {
+	u := NewUUIDv4()
+	if u.Version() != 4 || !uuidPattern.MatchString(u.String()) {
+		t.Errorf("Unexpected UUIDv4 %s", u)
+	}
+	if !u.Time().IsZero() {
+		t.Error("Expected no time for a UUIDv4")
+	}
+	if UUIDv4() == UUIDv4() {
+		t.Error("Expected unique UUIDs")
+	}
+}
+
+func TestUUIDv7(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	u := NewUUIDv7()
+	if u.Version() != 7 || !uuidPattern.MatchString(u.String()) {
+		t.Errorf("Unexpected UUIDv7 %s", u)
+	}
+	if created := u.Time(); created.Before(before) || created.After(time.Now()) {
+		t.Errorf("Unexpected time %v", created)
+	}
+
+	ids := make([]string, 10_000)
+	for i := range ids {
+		ids[i] = UUIDv7()
+	}
+	if !sort.StringsAreSorted(ids) {
+		t.Error("Expected UUIDv7s to be strictly increasing")
+	}
+	if RequestID() <= ids[len(ids)-1] {
+		t.Error("Expected request IDs to be UUIDv7s")
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	u := NewUUIDv7()
+	parsed, err := ParseUUID(u.String())
+	if err != nil || parsed != u {
+		t.Errorf("Expected %s, got %s %v", u, parsed, err)
+	}
+	if _, err := ParseUUID(strings.ToUpper(u.String())); err != nil {
+		t.Errorf("Expected upper case to parse, got %v", err)
+	}
+	for _, invalid := range []string{"", "not-a-uuid", "0189-0a5dac96-774b-bcce-b302099a8057", "zz890a5d-ac96-774b-bcce-b302099a8057"} {
+		if _, err := ParseUUID(invalid); err == nil {
+			t.Errorf("Expected %q to be invalid", invalid)
+		}
+	}
+}
+
+func TestULID(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	id := NewULID()
+	s := id.String()
+	if len(s) != 26 || strings.Trim(s, crockford) != "" {
+		t.Errorf("Unexpected ULID %s", s)
+	}
+	if created := id.Time(); created.Before(before) || created.After(time.Now()) {
+		t.Errorf("Unexpected time %v", created)
+	}
+
+	parsed, err := ParseULID(strings.ToLower(s))
+	if err != nil || parsed != id {
+		t.Errorf("Expected %s, got %s %v", s, parsed, err)
+	}
+	if max, err := ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ"); err != nil || max.String() != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Errorf("Expected the maximum ULID to round trip, got %s %v", max, err)
+	}
+	for _, invalid := range []string{"", "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", "01H2XCEJQTF2JBRQ8Z9YA7G5TU"} {
+		if _, err := ParseULID(invalid); err == nil {
+			t.Errorf("Expected %q to be invalid", invalid)
+		}
+	}
+}
+
+func TestSnowflake(t *testing.T) {
+	if _, err := NewSnowflake(MaxSnowflakeNode + 1); err != ErrInvalidNode {
+		t.Errorf("Expected ErrInvalidNode, got %v", err)
+	}
+
+	node, _ := NewSnowflake(42)
+	seen := make(map[int64]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 5000 {
+				id := node.Next()
+				mu.Lock()
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 20_000 {
+		t.Errorf("Expected 20000 unique IDs, got %d", len(seen))
+	}
+
+	id := node.Next()
+	if id>>snowflakeSequenceBits&MaxSnowflakeNode != 42 {
+		t.Errorf("Expected node 42 in %d", id)
+	}
+	if created := SnowflakeTime(id); time.Since(created) > time.Second || created.After(time.Now().Add(time.Second)) {
+		t.Errorf("Unexpected time %v", created)
+	}
+}
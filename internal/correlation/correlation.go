@@ -0,0 +1,70 @@
+// Package correlation holds the context keys for correlation IDs, shared by
+// the logger and response packages.
+package correlation
+
+import (
+	"context"
+	"sync"
+)
+
+// legacyRequestIDKey is the untyped context key older code stores request IDs under
+const legacyRequestIDKey = "RequestID"
+
+// Key identifies a correlation value stored in a context
+type Key struct {
+	field string
+}
+
+// Field returns the field name the key's value is reported under
+func (k *Key) Field() string {
+	return k.field
+}
+
+// Built-in keys
+var (
+	RequestID = NewKey("request_id")
+	TenantID  = NewKey("tenant_id")
+	UserID    = NewKey("user_id")
+)
+
+var (
+	mu   sync.RWMutex
+	keys []*Key
+)
+
+// NewKey registers a key reported under field
+func NewKey(field string) *Key {
+	key := &Key{field: field}
+	mu.Lock()
+	keys = append(keys, key)
+	mu.Unlock()
+	return key
+}
+
+// Keys returns the registered keys in registration order
+func Keys() []*Key {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]*Key(nil), keys...)
+}
+
+// With returns a context carrying value under key
+func With(ctx context.Context, key *Key, value string) context.Context {
+	return context.WithValue(ctx, key, value)
+}
+
+// FromContext returns the value stored under key, or ""
+func FromContext(ctx context.Context, key *Key) string {
+	value, _ := ctx.Value(key).(string)
+	return value
+}
+
+// RequestIDFromContext returns the request ID stored under RequestID, or
+// under the legacy "RequestID" string key, or ""
+func RequestIDFromContext(ctx context.Context) string {
+	if requestID := FromContext(ctx, RequestID); requestID != "" {
+		return requestID
+	}
+	requestID, _ := ctx.Value(legacyRequestIDKey).(string)
+	return requestID
+}
@@ -0,0 +1,210 @@
+// Package strictjson implements strict JSON decoding, shared by the helpers
+// and validation packages.
+package strictjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Strict decoding errors
+var (
+	ErrUnknownField = errors.New("unknown field")
+	ErrTrailingData = errors.New("unexpected data after top-level JSON value")
+)
+
+// DecodeError reports the field a strict decode rejected
+type DecodeError struct {
+	Path string // Path of the offending field, e.g. "items[2].sku"
+	Err  error  // ErrUnknownField or the underlying *json.UnmarshalTypeError
+}
+
+// Error implements the error interface
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to unmarshal JSON at %q: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Decode decodes jsonData into the value v points to, rejecting fields that
+// do not exist in it and any data after the JSON value. Field errors are
+// returned as *DecodeError carrying the field path.
+func Decode(jsonData []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return decodeError(jsonData, reflect.TypeOf(v), err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to unmarshal JSON: %w", ErrTrailingData)
+	}
+	return nil
+}
+
+// decodeError converts a decoder error into a DecodeError with
+// the path of the offending field when one can be determined
+func decodeError(jsonData []byte, t reflect.Type, err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return &DecodeError{Path: typeErr.Field, Err: err}
+	}
+
+	// encoding/json reports only the field name, e.g. `json: unknown field "nmae"`
+	name, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	if unquoted, unquoteErr := strconv.Unquote(name); unquoteErr == nil {
+		name = unquoted
+	}
+
+	path := name
+	var doc any
+	if decodeJSON(jsonData, &doc) == nil {
+		for _, candidate := range unknownFieldPaths(doc, t, "") {
+			if candidate == name || strings.HasSuffix(candidate, "."+name) {
+				path = candidate
+				break
+			}
+		}
+	}
+	return &DecodeError{Path: path, Err: ErrUnknownField}
+}
+
+// unknownFieldPaths returns the paths of object keys in doc that have no
+// matching struct field in t
+func unknownFieldPaths(doc any, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[json.Unmarshaler]()) {
+		return nil
+	}
+
+	var paths []string
+	switch value := doc.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			var elemType reflect.Type
+			switch t.Kind() {
+			case reflect.Map:
+				elemType = t.Elem()
+			case reflect.Struct:
+				field, ok := structFieldForKey(t, key)
+				if !ok {
+					paths = append(paths, joinKey(path, key))
+					continue
+				}
+				elemType = field.Type
+			default:
+				continue
+			}
+			paths = append(paths, unknownFieldPaths(value[key], elemType, joinKey(path, key))...)
+		}
+	case []any:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return nil
+		}
+		for i, item := range value {
+			paths = append(paths, unknownFieldPaths(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return paths
+}
+
+// structFieldForKey finds the field encoding/json would decode key into,
+// preferring an exact name match over a case-insensitive one
+func structFieldForKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	var fold reflect.StructField
+	var folded bool
+	for _, field := range jsonFields(t) {
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" {
+			name = tag
+		}
+		if name == key {
+			return field, true
+		}
+		if !folded && strings.EqualFold(name, key) {
+			fold, folded = field, true
+		}
+	}
+	return fold, folded
+}
+
+// jsonFields returns the exported fields of t that encoding/json decodes,
+// including fields promoted from untagged embedded structs
+func jsonFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && tag == "" {
+				fields = append(fields, jsonFields(embedded)...)
+				continue
+			}
+		}
+		if field.IsExported() {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// decodeJSON decodes a single JSON value, keeping numbers as json.Number
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("unexpected data after JSON value")
+	}
+	return nil
+}
+
+// joinKey appends an object key to a path, quoting keys that are not plain identifiers
+func joinKey(path, key string) string {
+	plain := key != ""
+	for _, r := range key {
+		if !(r == '_' || r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			plain = false
+			break
+		}
+	}
+	switch {
+	case !plain:
+		return path + "[" + strconv.Quote(key) + "]"
+	case path == "":
+		return key
+	default:
+		return path + "." + key
+	}
+}
@@ -0,0 +1,198 @@
+// Package validationmsg translates go-playground/validator failures into the
+// readable English messages and field paths shared by the response and
+// validation packages.
+package validationmsg
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// custom holds the messages set with Set, which take precedence over the
+// built-in ones
+var custom = struct {
+	sync.RWMutex
+	messages map[string]string
+}{messages: map[string]string{}}
+
+// messages are the built-in messages per tag. Messages may use {0} for the
+// field name and {1} for the tag parameter.
+var messages = map[string]string{
+	"required":                      "{0} is a required field",
+	"required_if":                   "{0} is a required field",
+	"required_unless":               "{0} is a required field",
+	"required_with":                 "{0} is a required field",
+	"required_with_all":             "{0} is a required field",
+	"required_without":              "{0} is a required field",
+	"required_without_all":          "{0} is a required field",
+	"excluded_if":                   "{0} is an excluded field",
+	"excluded_unless":               "{0} is an excluded field",
+	"excluded_with":                 "{0} is an excluded field",
+	"excluded_with_all":             "{0} is an excluded field",
+	"excluded_without":              "{0} is an excluded field",
+	"excluded_without_all":          "{0} is an excluded field",
+	"isdefault":                     "{0} must be default value",
+	"eq":                            "{0} is not equal to {1}",
+	"ne":                            "{0} should not be equal to {1}",
+	"eqfield":                       "{0} must be equal to {1}",
+	"eqcsfield":                     "{0} must be equal to {1}",
+	"necsfield":                     "{0} cannot be equal to {1}",
+	"gtcsfield":                     "{0} must be greater than {1}",
+	"gtecsfield":                    "{0} must be greater than or equal to {1}",
+	"ltcsfield":                     "{0} must be less than {1}",
+	"ltecsfield":                    "{0} must be less than or equal to {1}",
+	"nefield":                       "{0} cannot be equal to {1}",
+	"gtfield":                       "{0} must be greater than {1}",
+	"gtefield":                      "{0} must be greater than or equal to {1}",
+	"ltfield":                       "{0} must be less than {1}",
+	"ltefield":                      "{0} must be less than or equal to {1}",
+	"alpha":                         "{0} can only contain alphabetic characters",
+	"alphanum":                      "{0} can only contain alphanumeric characters",
+	"numeric":                       "{0} must be a valid numeric value",
+	"number":                        "{0} must be a valid number",
+	"hexadecimal":                   "{0} must be a valid hexadecimal",
+	"hexcolor":                      "{0} must be a valid HEX color",
+	"rgb":                           "{0} must be a valid RGB color",
+	"rgba":                          "{0} must be a valid RGBA color",
+	"hsl":                           "{0} must be a valid HSL color",
+	"hsla":                          "{0} must be a valid HSLA color",
+	"e164":                          "{0} must be a valid E.164 formatted phone number",
+	"email":                         "{0} must be a valid email address",
+	"url":                           "{0} must be a valid URL",
+	"uri":                           "{0} must be a valid URI",
+	"base64":                        "{0} must be a valid Base64 string",
+	"contains":                      "{0} must contain the text '{1}'",
+	"containsany":                   "{0} must contain at least one of the following characters '{1}'",
+	"excludes":                      "{0} cannot contain the text '{1}'",
+	"excludesall":                   "{0} cannot contain any of the following characters '{1}'",
+	"excludesrune":                  "{0} cannot contain the following '{1}'",
+	"isbn":                          "{0} must be a valid ISBN number",
+	"isbn10":                        "{0} must be a valid ISBN-10 number",
+	"isbn13":                        "{0} must be a valid ISBN-13 number",
+	"issn":                          "{0} must be a valid ISSN number",
+	"uuid":                          "{0} must be a valid UUID",
+	"uuid3":                         "{0} must be a valid version 3 UUID",
+	"uuid4":                         "{0} must be a valid version 4 UUID",
+	"uuid5":                         "{0} must be a valid version 5 UUID",
+	"ulid":                          "{0} must be a valid ULID",
+	"ascii":                         "{0} must contain only ascii characters",
+	"printascii":                    "{0} must contain only printable ascii characters",
+	"multibyte":                     "{0} must contain multibyte characters",
+	"datauri":                       "{0} must contain a valid Data URI",
+	"latitude":                      "{0} must contain valid latitude coordinates",
+	"longitude":                     "{0} must contain a valid longitude coordinates",
+	"ssn":                           "{0} must be a valid SSN number",
+	"ipv4":                          "{0} must be a valid IPv4 address",
+	"ipv6":                          "{0} must be a valid IPv6 address",
+	"ip":                            "{0} must be a valid IP address",
+	"cidr":                          "{0} must contain a valid CIDR notation",
+	"cidrv4":                        "{0} must contain a valid CIDR notation for an IPv4 address",
+	"cidrv6":                        "{0} must contain a valid CIDR notation for an IPv6 address",
+	"tcp_addr":                      "{0} must be a valid TCP address",
+	"tcp4_addr":                     "{0} must be a valid IPv4 TCP address",
+	"tcp6_addr":                     "{0} must be a valid IPv6 TCP address",
+	"udp_addr":                      "{0} must be a valid UDP address",
+	"udp4_addr":                     "{0} must be a valid IPv4 UDP address",
+	"udp6_addr":                     "{0} must be a valid IPv6 UDP address",
+	"ip_addr":                       "{0} must be a resolvable IP address",
+	"ip4_addr":                      "{0} must be a resolvable IPv4 address",
+	"ip6_addr":                      "{0} must be a resolvable IPv6 address",
+	"unix_addr":                     "{0} must be a resolvable UNIX address",
+	"mac":                           "{0} must contain a valid MAC address",
+	"fqdn":                          "{0} must be a valid FQDN",
+	"unique":                        "{0} must contain unique values",
+	"iscolor":                       "{0} must be a valid color",
+	"cron":                          "{0} must be a valid cron expression",
+	"oneof":                         "{0} must be one of [{1}]",
+	"json":                          "{0} must be a valid json string",
+	"jwt":                           "{0} must be a valid jwt string",
+	"lowercase":                     "{0} must be a lowercase string",
+	"uppercase":                     "{0} must be an uppercase string",
+	"datetime":                      "{0} does not match the {1} format",
+	"postcode_iso3166_alpha2":       "{0} does not match postcode format of {1} country",
+	"postcode_iso3166_alpha2_field": "{0} does not match postcode format of country in {1} field",
+	"boolean":                       "{0} must be a valid boolean value",
+	"image":                         "{0} must be a valid image",
+	"cve":                           "{0} must be a valid cve identifier",
+}
+
+// sizeMessages are the built-in messages for tags whose meaning depends on
+// the field kind. In the text and items variants {1} is the parameter with
+// its unit, e.g. "3 characters".
+var sizeMessages = map[string]struct{ text, items, number, date string }{
+	"len": {"{0} must be {1} in length", "{0} must contain {1}", "{0} must be equal to {1}", ""},
+	"min": {"{0} must be at least {1} in length", "{0} must contain at least {1}", "{0} must be {1} or greater", ""},
+	"max": {"{0} must be a maximum of {1} in length", "{0} must contain at maximum {1}", "{0} must be {1} or less", ""},
+	"lt":  {"{0} must be less than {1} in length", "{0} must contain less than {1}", "{0} must be less than {1}", "{0} must be less than the current Date & Time"},
+	"lte": {"{0} must be at maximum {1} in length", "{0} must contain at maximum {1}", "{0} must be {1} or less", "{0} must be less than or equal to the current Date & Time"},
+	"gt":  {"{0} must be greater than {1} in length", "{0} must contain more than {1}", "{0} must be greater than {1}", "{0} must be greater than the current Date & Time"},
+	"gte": {"{0} must be at least {1} in length", "{0} must contain at least {1}", "{0} must be {1} or greater", "{0} must be greater than or equal to the current Date & Time"},
+}
+
+// timeType identifies time.Time fields, which size tags compare to the current time
+var timeType = reflect.TypeOf(time.Time{})
+
+// Set overrides the message for a tag, including custom tags
+func Set(tag, message string) {
+	custom.Lock()
+	defer custom.Unlock()
+	custom.messages[tag] = message
+}
+
+// Reason returns the message for a failed field. rules holds messages that
+// take precedence over Set and the built-in ones, such as the messages of a
+// validator's registered rules, and may be nil.
+func Reason(fe validator.FieldError, rules map[string]string) string {
+	param := fe.Param()
+
+	message, ok := rules[fe.Tag()]
+	if !ok {
+		custom.RLock()
+		message, ok = custom.messages[fe.Tag()]
+		custom.RUnlock()
+	}
+	if !ok {
+		message, ok = messages[fe.Tag()]
+	}
+	if !ok {
+		if size, sized := sizeMessages[fe.Tag()]; sized {
+			switch {
+			case fe.Kind() == reflect.String:
+				message, param = size.text, withUnit(param, "character")
+			case fe.Kind() == reflect.Slice || fe.Kind() == reflect.Array || fe.Kind() == reflect.Map:
+				message, param = size.items, withUnit(param, "item")
+			case fe.Type() == timeType && size.date != "":
+				message = size.date
+			default:
+				message = size.number
+			}
+			ok = true
+		}
+	}
+	if !ok {
+		message = "{0} is invalid"
+	}
+
+	return strings.NewReplacer("{0}", fe.Field(), "{1}", param).Replace(message)
+}
+
+// withUnit appends a unit to a count, e.g. "1 character" or "3 characters"
+func withUnit(count, unit string) string {
+	if count == "1" {
+		return count + " " + unit
+	}
+	return count + " " + unit + "s"
+}
+
+// Path strips the top-level struct name from a namespace such as
+// "CreateUserRequest.address.city"
+func Path(namespace string) string {
+	if _, rest, ok := strings.Cut(namespace, "."); ok {
+		return rest
+	}
+	return namespace
+}
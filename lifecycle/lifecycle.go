@@ -0,0 +1,283 @@
+// Package lifecycle starts a service's components in order and shuts them
+// down gracefully on SIGTERM or SIGINT.
+//
+// Components register hooks with start and stop functions. Hooks start in
+// registration order and stop in reverse, each stop with its own timeout, so
+// a component is only stopped after everything registered after it, e.g.
+// the HTTP server before the database it uses.
+//
+// Example usage:
+//
+//	app := lifecycle.New(lifecycle.WithShutdownTimeout(30 * time.Second))
+//	app.Append(lifecycle.LoggerHook())
+//	app.Append(lifecycle.Closer("db", db))
+//	app.Append(lifecycle.Hook{
+//		Name:    "scheduler",
+//		OnStart: func(ctx context.Context) error { jobs.Start(); return nil },
+//		OnStop:  jobs.Stop,
+//	})
+//	app.AppendHTTPServer(&http.Server{Addr: ":8080", Handler: handler})
+//
+//	if err := app.Run(context.Background()); err != nil {
+//		logger.Error("Service stopped with errors", zap.Error(err))
+//	}
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+)
+
+// Default timeouts
+const (
+	DefaultStartTimeout    = 15 * time.Second
+	DefaultStopTimeout     = 10 * time.Second
+	DefaultShutdownTimeout = 30 * time.Second
+)
+
+// Hook is a component's start and stop functions. Both are optional.
+// OnStart must return once the component is running; long-running work
+// belongs in a goroutine.
+type Hook struct {
+	Name    string                          // Name used in logs and errors
+	OnStart func(ctx context.Context) error // Starts the component
+	OnStop  func(ctx context.Context) error // Stops the component, respecting the context deadline
+	Timeout time.Duration                   // Stop timeout (default: the app's stop timeout)
+}
+
+// Config holds the configuration of an App
+type Config struct {
+	StartTimeout    time.Duration // Timeout for each OnStart (default: DefaultStartTimeout)
+	StopTimeout     time.Duration // Default timeout for each OnStop (default: DefaultStopTimeout)
+	ShutdownTimeout time.Duration // Timeout for the whole shutdown (default: DefaultShutdownTimeout)
+	Signals         []os.Signal   // Signals that trigger shutdown (default: SIGTERM, SIGINT)
+}
+
+// Option is a function type for configuring an App
+type Option func(*Config)
+
+// WithStartTimeout sets the timeout for each OnStart
+func WithStartTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.StartTimeout = timeout
+	}
+}
+
+// WithStopTimeout sets the default timeout for each OnStop
+func WithStopTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.StopTimeout = timeout
+	}
+}
+
+// WithShutdownTimeout sets the timeout for the whole shutdown
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.ShutdownTimeout = timeout
+	}
+}
+
+// WithSignals sets the signals that trigger shutdown
+func WithSignals(signals ...os.Signal) Option {
+	return func(config *Config) {
+		config.Signals = signals
+	}
+}
+
+// App runs the hooks of a service
+type App struct {
+	config  Config
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+	failed  chan error
+}
+
+// New creates an App
+func New(options ...Option) *App {
+	config := Config{
+		StartTimeout:    DefaultStartTimeout,
+		StopTimeout:     DefaultStopTimeout,
+		ShutdownTimeout: DefaultShutdownTimeout,
+		Signals:         []os.Signal{syscall.SIGTERM, os.Interrupt},
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+	return &App{config: config, failed: make(chan error, 1)}
+}
+
+// Append registers a hook. Register every hook before Start.
+func (a *App) Append(hook Hook) {
+	a.mu.Lock()
+	a.hooks = append(a.hooks, hook)
+	a.mu.Unlock()
+}
+
+// Start runs the OnStart functions in registration order. When one fails,
+// the hooks already started are stopped and the error is returned.
+func (a *App) Start(ctx context.Context) error {
+	a.mu.Lock()
+	hooks := a.hooks[a.started:]
+	a.mu.Unlock()
+
+	for _, hook := range hooks {
+		if hook.OnStart != nil {
+			startCtx, cancel := context.WithTimeout(ctx, a.config.StartTimeout)
+			err := hook.OnStart(startCtx)
+			cancel()
+			if err != nil {
+				err = fmt.Errorf("failed to start %s: %w", hook.Name, err)
+				stopCtx, cancel := context.WithTimeout(context.Background(), a.config.ShutdownTimeout)
+				defer cancel()
+				return errors.Join(err, a.Stop(stopCtx))
+			}
+		}
+		a.mu.Lock()
+		a.started++
+		a.mu.Unlock()
+		logger.Debug("Component started", zap.String("component", hook.Name))
+	}
+	return nil
+}
+
+// Stop runs the OnStop functions of the started hooks in reverse order, each
+// with its timeout, and returns their errors joined. Every hook is stopped
+// even when earlier ones fail.
+func (a *App) Stop(ctx context.Context) error {
+	a.mu.Lock()
+	hooks := a.hooks[:a.started]
+	a.started = 0
+	a.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if hook.OnStop == nil {
+			continue
+		}
+
+		timeout := hook.Timeout
+		if timeout <= 0 {
+			timeout = a.config.StopTimeout
+		}
+		stopCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := hook.OnStop(stopCtx)
+		cancel()
+
+		fields := []zap.Field{zap.String("component", hook.Name), zap.Duration("duration", time.Since(start))}
+		if err != nil {
+			logger.Error("Component failed to stop", append(fields, zap.Error(err))...)
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", hook.Name, err))
+			continue
+		}
+		logger.Debug("Component stopped", fields...)
+	}
+	return errors.Join(errs...)
+}
+
+// Fail reports that a component stopped unexpectedly, which makes Run shut
+// down and return err. Only the first failure is kept.
+func (a *App) Fail(err error) {
+	select {
+	case a.failed <- err:
+	default:
+	}
+}
+
+// Run starts the hooks, waits for a shutdown signal, ctx to be done, or a
+// component failure, and then stops the hooks within the shutdown timeout.
+// It returns the start error, or the failure and stop errors joined.
+func (a *App) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, a.config.Signals...)
+	defer stop()
+
+	if err := a.Start(ctx); err != nil {
+		return err
+	}
+	logger.Info("Service started")
+
+	var failure error
+	select {
+	case <-ctx.Done():
+		logger.Info("Shutting down")
+	case failure = <-a.failed:
+		logger.Error("Component failed, shutting down", zap.Error(failure))
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.config.ShutdownTimeout)
+	defer cancel()
+	err := errors.Join(failure, a.Stop(shutdownCtx))
+	logger.Info("Service stopped")
+	return err
+}
+
+// AppendHTTPServer registers an HTTP server. The listener is opened on start,
+// so a port already in use fails Start, and the server is shut down
+// gracefully on stop. If serving fails later, the app shuts down. Servers
+// whose TLSConfig has certificates serve HTTPS.
+func (a *App) AppendHTTPServer(server *http.Server) {
+	a.Append(Hook{
+		Name: "http-server " + server.Addr,
+		OnStart: func(ctx context.Context) error {
+			addr := server.Addr
+			if addr == "" {
+				addr = ":http"
+			}
+			listener, err := new(net.ListenConfig).Listen(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+			serve := server.Serve
+			if tlsConfig := server.TLSConfig; tlsConfig != nil && (len(tlsConfig.Certificates) > 0 || tlsConfig.GetCertificate != nil) {
+				serve = func(listener net.Listener) error { return server.ServeTLS(listener, "", "") }
+			}
+			go func() {
+				if err := serve(listener); !errors.Is(err, http.ErrServerClosed) {
+					a.Fail(fmt.Errorf("http server %s: %w", server.Addr, err))
+				}
+			}()
+			return nil
+		},
+		OnStop: server.Shutdown,
+	})
+}
+
+// LoggerHook flushes the global logger on stop. Append it first so it runs
+// after every other component has stopped and logged.
+func LoggerHook() Hook {
+	return Hook{
+		Name: "logger",
+		OnStop: func(ctx context.Context) error {
+			err := logger.Sync()
+			if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) {
+				// Syncing stdout or stderr fails on terminals and pipes
+				return nil
+			}
+			return err
+		},
+	}
+}
+
+// Closer returns a hook that closes c on stop, e.g. a database or a file
+func Closer(name string, c io.Closer) Hook {
+	return Hook{
+		Name: name,
+		OnStop: func(ctx context.Context) error {
+			return c.Close()
+		},
+	}
+}
@@ -0,0 +1,164 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder records hook calls in order
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) hook(name string, startErr error) Hook {
+	return Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			r.record("start " + name)
+			return startErr
+		},
+		OnStop: func(ctx context.Context) error {
+			r.record("stop " + name)
+			return nil
+		},
+	}
+}
+
+func (r *recorder) record(call string) {
+	r.mu.Lock()
+	r.calls = append(r.calls, call)
+	r.mu.Unlock()
+}
+
+func (r *recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.calls, ", ")
+}
+
+func TestApp_StartStopOrder(t *testing.T) {
+	rec := &recorder{}
+	app := New()
+	app.Append(rec.hook("db", nil))
+	app.Append(rec.hook("server", nil))
+
+	if err := app.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := app.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if rec.String() != "start db, start server, stop server, stop db" {
+		t.Errorf("Unexpected calls: %s", rec)
+	}
+
+	// Hooks are only stopped once
+	if err := app.Stop(context.Background()); err != nil || strings.Count(rec.String(), "stop db") != 1 {
+		t.Errorf("Expected a second Stop to do nothing, got %v: %s", err, rec)
+	}
+}
+
+func TestApp_StartFailureRollsBack(t *testing.T) {
+	rec := &recorder{}
+	app := New()
+	app.Append(rec.hook("db", nil))
+	app.Append(rec.hook("cache", errors.New("connection refused")))
+	app.Append(rec.hook("server", nil))
+
+	err := app.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to start cache") {
+		t.Errorf("Expected the start error, got %v", err)
+	}
+	if rec.String() != "start db, start cache, stop db" {
+		t.Errorf("Unexpected calls: %s", rec)
+	}
+}
+
+func TestApp_StopTimeoutAndErrors(t *testing.T) {
+	app := New(WithStopTimeout(20 * time.Millisecond))
+	stopped := false
+	app.Append(Hook{Name: "first", OnStop: func(ctx context.Context) error {
+		stopped = true
+		return nil
+	}})
+	app.Append(Hook{Name: "slow", OnStop: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	app.Start(context.Background())
+	err := app.Stop(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "failed to stop slow") {
+		t.Errorf("Expected the slow hook to time out, got %v", err)
+	}
+	if !stopped {
+		t.Error("Expected the remaining hooks to stop after a failure")
+	}
+}
+
+func TestApp_Run(t *testing.T) {
+	rec := &recorder{}
+	app := New()
+	app.Append(rec.hook("worker", nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- app.Run(ctx) }()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if rec.String() != "start worker, stop worker" {
+		t.Errorf("Unexpected calls: %s", rec)
+	}
+
+	failure := errors.New("consumer lost connection")
+	app = New()
+	app.Append(Hook{Name: "consumer", OnStart: func(ctx context.Context) error {
+		go app.Fail(failure)
+		return nil
+	}})
+	if err := app.Run(context.Background()); !errors.Is(err, failure) {
+		t.Errorf("Expected Run to return the failure, got %v", err)
+	}
+}
+
+func TestApp_HTTPServer(t *testing.T) {
+	app := New()
+	app.AppendHTTPServer(&http.Server{Addr: "127.0.0.1:0", Handler: http.NotFoundHandler()})
+	if err := app.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := app.Stop(context.Background()); err != nil {
+		t.Errorf("Stop failed: %v", err)
+	}
+
+	app = New()
+	app.AppendHTTPServer(&http.Server{Addr: "127.0.0.1:-1"})
+	if err := app.Start(context.Background()); err == nil {
+		t.Error("Expected an invalid address to fail Start")
+	}
+}
+
+func TestCloser(t *testing.T) {
+	closed := false
+	hook := Closer("db", closerFunc(func() error {
+		closed = true
+		return nil
+	}))
+	if err := hook.OnStop(context.Background()); err != nil || !closed {
+		t.Errorf("Expected the closer to be closed, got %v", err)
+	}
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
@@ -0,0 +1,326 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Audit logger defaults, applied when the AuditConfig field is zero
+const (
+	DefaultAuditFlushInterval = time.Second
+	DefaultAuditBufferSize    = 64 << 10
+)
+
+// Audit errors
+var (
+	ErrAuditNotConfigured = errors.New("audit logger not configured")
+	ErrAuditClosed        = errors.New("audit logger closed")
+	ErrAuditTampered      = errors.New("audit trail tampered")
+)
+
+// AuditOutcome is the result of an audited action
+type AuditOutcome string
+
+// Audit outcomes
+const (
+	AuditSuccess AuditOutcome = "success"
+	AuditFailure AuditOutcome = "failure"
+	AuditDenied  AuditOutcome = "denied"
+)
+
+// AuditEvent is one entry of the audit trail. ID, Time, Actor, RequestID,
+// and TenantID are filled in from the context when empty; PrevHash and Hash
+// are set by the AuditLogger.
+type AuditEvent struct {
+	ID        string                 `json:"id"`
+	Time      time.Time              `json:"timestamp"`
+	Actor     string                 `json:"actor"`
+	Action    string                 `json:"action"`
+	Resource  string                 `json:"resource"`
+	Outcome   AuditOutcome           `json:"outcome"`
+	Reason    string                 `json:"reason,omitempty"`
+	Before    interface{}            `json:"before,omitempty"`
+	After     interface{}            `json:"after,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+	TenantID  string                 `json:"tenant_id,omitempty"`
+	PrevHash  string                 `json:"prev_hash,omitempty"`
+	Hash      string                 `json:"hash,omitempty"`
+}
+
+// AuditConfig configures an AuditLogger
+type AuditConfig struct {
+	Outputs       []string      // Sink paths, e.g. a file or RotateScheme URL; kept apart from application logs
+	FlushInterval time.Duration // How often buffered events are written and synced
+	BufferSize    int           // Bytes buffered before an early flush
+	PreviousHash  string        // Continues an existing chain, e.g. the last hash of the current file
+}
+
+// AuditLogger writes an append-only, tamper-evident audit trail as JSON
+// lines. Each line carries the SHA-256 of its content and the hash of the
+// previous line, so edits, insertions, and deletions break the chain
+// checked by VerifyAuditTrail. Events are buffered, flushed and synced every
+// FlushInterval, and flushed on Close; an accepted event is never dropped
+// while the process shuts down cleanly.
+type AuditLogger struct {
+	config AuditConfig
+	sink   zapcore.WriteSyncer
+	closer func()
+
+	mu       sync.Mutex
+	buf      *bufio.Writer
+	prevHash string
+	closed   bool
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// globalAudit is the AuditLogger used by Audit
+var globalAudit atomic.Pointer[AuditLogger]
+
+// NewAuditLogger opens the audit outputs and starts the periodic flush
+func NewAuditLogger(config AuditConfig) (*AuditLogger, error) {
+	if len(config.Outputs) == 0 {
+		return nil, fmt.Errorf("audit logger requires at least one output")
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultAuditFlushInterval
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = DefaultAuditBufferSize
+	}
+
+	sink, closer, err := zap.Open(config.Outputs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit outputs: %w", err)
+	}
+	a := &AuditLogger{
+		config:   config,
+		sink:     sink,
+		closer:   closer,
+		buf:      bufio.NewWriterSize(sink, config.BufferSize),
+		prevHash: config.PreviousHash,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go a.flushLoop()
+	return a, nil
+}
+
+// InitAudit creates an AuditLogger and installs it for Audit. Close it on
+// shutdown to flush buffered events.
+//
+// Example:
+//
+//	audit, err := logger.InitAudit(logger.AuditConfig{
+//		Outputs: []string{"rotate:///var/log/audit.log?max_size_mb=100"},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer audit.Close()
+func InitAudit(config AuditConfig) (*AuditLogger, error) {
+	a, err := NewAuditLogger(config)
+	if err != nil {
+		return nil, err
+	}
+	globalAudit.Store(a)
+	return a, nil
+}
+
+// Audit records event with the AuditLogger installed by InitAudit
+//
+// Example:
+//
+//	logger.Audit(ctx, logger.AuditEvent{
+//		Action:   "user.role.update",
+//		Resource: "user/42",
+//		Outcome:  logger.AuditSuccess,
+//		Before:   map[string]string{"role": "viewer"},
+//		After:    map[string]string{"role": "admin"},
+//	})
+func Audit(ctx context.Context, event AuditEvent) error {
+	a := globalAudit.Load()
+	if a == nil {
+		return ErrAuditNotConfigured
+	}
+	return a.Log(ctx, event)
+}
+
+// Log appends event to the trail. The actor defaults to the user ID in ctx.
+func (a *AuditLogger) Log(ctx context.Context, event AuditEvent) error {
+	if event.Action == "" {
+		return fmt.Errorf("audit event requires an action")
+	}
+	if event.ID == "" {
+		event.ID = newRequestID()
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	event.Time = event.Time.UTC()
+	if ctx != nil {
+		if event.Actor == "" {
+			event.Actor = UserIDFromContext(ctx)
+		}
+		if event.RequestID == "" {
+			event.RequestID = RequestIDFromContext(ctx)
+		}
+		if event.TenantID == "" {
+			event.TenantID = TenantIDFromContext(ctx)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return ErrAuditClosed
+	}
+
+	event.PrevHash = a.prevHash
+	event.Hash = ""
+	content, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+	hash := auditHash(content)
+	if _, err := a.buf.Write(appendAuditHash(content, hash)); err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	a.prevHash = hash
+	return nil
+}
+
+// Flush writes buffered events and syncs the outputs
+func (a *AuditLogger) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.flush()
+}
+
+// LastHash returns the hash of the last event, to continue the chain with
+// AuditConfig.PreviousHash after a restart
+func (a *AuditLogger) LastHash() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.prevHash
+}
+
+// Close flushes buffered events and closes the outputs. Later events return
+// ErrAuditClosed.
+func (a *AuditLogger) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	a.mu.Unlock()
+
+	close(a.done)
+	<-a.stopped
+
+	a.mu.Lock()
+	err := a.flush()
+	a.mu.Unlock()
+	a.closer()
+	globalAudit.CompareAndSwap(a, nil)
+	return err
+}
+
+// flush writes and syncs; the caller holds mu
+func (a *AuditLogger) flush() error {
+	if err := a.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush audit events: %w", err)
+	}
+	if err := a.sink.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit outputs: %w", err)
+	}
+	return nil
+}
+
+// flushLoop flushes every FlushInterval until Close
+func (a *AuditLogger) flushLoop() {
+	defer close(a.stopped)
+	ticker := time.NewTicker(a.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.Flush()
+		case <-a.done:
+			return
+		}
+	}
+}
+
+// auditHashSuffix is the length of `,"hash":"<64 hex digits>"}`
+const auditHashSuffix = len(`,"hash":""}`) + sha256.Size*2
+
+// auditHash returns the hex SHA-256 of an encoded event without its hash
+func auditHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// appendAuditHash adds the hash as the last field of the encoded event and
+// terminates the line
+func appendAuditHash(content []byte, hash string) []byte {
+	line := append([]byte(nil), content[:len(content)-1]...)
+	line = append(line, `,"hash":"`...)
+	line = append(line, hash...)
+	return append(line, "\"}\n"...)
+}
+
+// VerifyAuditTrail checks every line's hash and its link to the previous
+// line, returning an error wrapping ErrAuditTampered with the first broken
+// line number. An event with an empty prev_hash starts a new chain, as
+// happens when a process starts without AuditConfig.PreviousHash. The first
+// event may link to an earlier file, so verify rotated files in order, e.g.
+// through io.MultiReader, to also detect removed files.
+func VerifyAuditTrail(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), 16<<20)
+
+	prevHash, first := "", true
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if len(data) == 0 {
+			continue
+		}
+
+		var event AuditEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("%w: line %d is not a valid event: %v", ErrAuditTampered, line, err)
+		}
+		if len(data) < auditHashSuffix || !bytes.Equal(data[len(data)-auditHashSuffix:], appendAuditHash([]byte("}"), event.Hash)[:auditHashSuffix]) {
+			return fmt.Errorf("%w: line %d has no trailing hash", ErrAuditTampered, line)
+		}
+		content := append(append([]byte(nil), data[:len(data)-auditHashSuffix]...), '}')
+		if auditHash(content) != event.Hash {
+			return fmt.Errorf("%w: line %d does not match its hash", ErrAuditTampered, line)
+		}
+		if !first && event.PrevHash != "" && event.PrevHash != prevHash {
+			return fmt.Errorf("%w: line %d does not follow the previous event", ErrAuditTampered, line)
+		}
+		prevHash, first = event.Hash, false
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read audit trail: %w", err)
+	}
+	return nil
+}
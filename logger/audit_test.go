@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestAudit(t *testing.T) (*AuditLogger, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := InitAudit(AuditConfig{Outputs: []string{path}, FlushInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("InitAudit failed: %v", err)
+	}
+	t.Cleanup(func() { audit.Close() })
+	return audit, path
+}
+
+func TestAudit(t *testing.T) {
+	audit, path := newTestAudit(t)
+	ctx := WithUserID(WithRequestID(context.Background(), "req-1"), "u-7")
+
+	err := Audit(ctx, AuditEvent{
+		Action:   "user.role.update",
+		Resource: "user/42",
+		Outcome:  AuditSuccess,
+		Before:   map[string]string{"role": "viewer"},
+		After:    map[string]string{"role": "admin"},
+	})
+	if err != nil {
+		t.Fatalf("Audit failed: %v", err)
+	}
+	Audit(ctx, AuditEvent{Action: "user.delete", Resource: "user/43", Outcome: AuditDenied, Actor: "admin"})
+
+	data, _ := os.ReadFile(path)
+	if len(data) != 0 {
+		t.Fatal("Expected events to be buffered until flushed")
+	}
+	if err := audit.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(lines))
+	}
+
+	var first, second AuditEvent
+	json.Unmarshal([]byte(lines[0]), &first)
+	json.Unmarshal([]byte(lines[1]), &second)
+	if first.Actor != "u-7" || first.RequestID != "req-1" || first.ID == "" || first.Outcome != AuditSuccess {
+		t.Errorf("Unexpected event %+v", first)
+	}
+	if second.Actor != "admin" || second.PrevHash != first.Hash || first.PrevHash != "" {
+		t.Errorf("Expected events to be chained, got %+v", second)
+	}
+	if err := VerifyAuditTrail(bytes.NewReader(data)); err != nil {
+		t.Errorf("Expected a valid trail, got %v", err)
+	}
+
+	if err := Audit(ctx, AuditEvent{Action: "late"}); !errors.Is(err, ErrAuditNotConfigured) {
+		t.Errorf("Expected ErrAuditNotConfigured after Close, got %v", err)
+	}
+	if err := audit.Log(ctx, AuditEvent{Action: "late"}); !errors.Is(err, ErrAuditClosed) {
+		t.Errorf("Expected ErrAuditClosed, got %v", err)
+	}
+}
+
+func TestVerifyAuditTrail_DetectsTampering(t *testing.T) {
+	audit, path := newTestAudit(t)
+	for _, resource := range []string{"doc/1", "doc/2", "doc/3"} {
+		audit.Log(context.Background(), AuditEvent{Action: "doc.read", Resource: resource, Outcome: AuditSuccess})
+	}
+	audit.Flush()
+	data, _ := os.ReadFile(path)
+	lines := strings.SplitAfter(string(data), "\n")
+
+	tests := map[string]string{
+		"edited":  strings.Replace(string(data), "doc/2", "doc/9", 1),
+		"deleted": lines[0] + lines[2],
+		"swapped": lines[0] + lines[2] + lines[1],
+	}
+	for name, trail := range tests {
+		if err := VerifyAuditTrail(strings.NewReader(trail)); !errors.Is(err, ErrAuditTampered) {
+			t.Errorf("%s: expected ErrAuditTampered, got %v", name, err)
+		}
+	}
+
+	// A later file continues the chain from the last hash
+	next, err := NewAuditLogger(AuditConfig{Outputs: []string{path + ".2"}, PreviousHash: audit.LastHash()})
+	if err != nil {
+		t.Fatalf("NewAuditLogger failed: %v", err)
+	}
+	next.Log(context.Background(), AuditEvent{Action: "doc.read", Resource: "doc/4"})
+	next.Close()
+	continued, _ := os.ReadFile(path + ".2")
+	if err := VerifyAuditTrail(strings.NewReader(string(data) + string(continued))); err != nil {
+		t.Errorf("Expected continued chain to verify, got %v", err)
+	}
+}
+
+func TestAudit_RequiresAction(t *testing.T) {
+	audit, _ := newTestAudit(t)
+	if err := audit.Log(context.Background(), AuditEvent{Resource: "x"}); err == nil {
+		t.Error("Expected an event without action to fail")
+	}
+	if _, err := NewAuditLogger(AuditConfig{}); err == nil {
+		t.Error("Expected missing outputs to fail")
+	}
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// componentLevels holds the per-component levels, keyed by component name
+var componentLevels sync.Map
+
+// Named returns a child of the global Logger with a component field. Entries
+// are filtered by the component's level when one is configured with
+// WithComponentLevels or SetComponentLevel, and by the global level otherwise.
+//
+// Example:
+//
+//	log := logger.Named("payments")
+//	log.Debug("charging card", zap.String("order_id", id))
+func Named(component string) *zap.Logger {
+	return global().WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if filtered, ok := core.(*levelCore); ok {
+			return &levelCore{Core: filtered.Core, level: componentLevel(component)}
+		}
+		return core
+	})).With(zap.String("component", component))
+}
+
+// SetComponentLevel changes the level of a component at runtime, including
+// loggers already returned by Named
+func SetComponentLevel(component, level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid log level %q for component %q: %w", level, component, err)
+	}
+	if current, ok := componentLevels.Load(component); ok {
+		current.(zap.AtomicLevel).SetLevel(parsed)
+		return nil
+	}
+	componentLevels.Store(component, zap.NewAtomicLevelAt(parsed))
+	return nil
+}
+
+// ResetComponentLevel makes a component follow the global level again
+func ResetComponentLevel(component string) {
+	componentLevels.Delete(component)
+}
+
+// componentLevel is a LevelEnabler that uses the component's level when one
+// is configured and the global level otherwise
+type componentLevel string
+
+// Enabled reports whether level is enabled for the component
+func (c componentLevel) Enabled(level zapcore.Level) bool {
+	if configured, ok := componentLevels.Load(string(c)); ok {
+		return configured.(zap.AtomicLevel).Enabled(level)
+	}
+	return atomicLevel.Enabled(level)
+}
+
+// levelCore filters entries by level before they reach the wrapped core,
+// which is built to accept every level so components can log below the
+// global level
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+// Enabled reports whether level passes the filter
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level)
+}
+
+// With returns a filtered core with fields added
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+// Check passes enabled entries to the wrapped core
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// setComponentLevels replaces the configured component levels
+func setComponentLevels(levels map[string]zapcore.Level) {
+	componentLevels.Range(func(key, _ any) bool {
+		componentLevels.Delete(key)
+		return true
+	})
+	for component, level := range levels {
+		componentLevels.Store(component, zap.NewAtomicLevelAt(level))
+	}
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNamed_ComponentLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	_, err := Init(
+		WithLevel("info"),
+		WithEncoding(EncodingJSON),
+		WithOutputs(path),
+		WithComponentLevels(map[string]string{"httpclient": "debug", "payments": "error"}),
+	)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	Named("httpclient").Debug("request sent")
+	Named("payments").Warn("slow charge")
+	Named("orders").Debug("order skipped")
+	Named("orders").Info("order placed")
+	Logger.Debug("global debug")
+	Logger.Sync()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	output := string(data)
+	for _, expected := range []string{`"request sent"`, `"component":"httpclient"`, `"order placed"`, `"component":"orders"`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s in output:\n%s", expected, output)
+		}
+	}
+	for _, unexpected := range []string{"slow charge", "order skipped", "global debug"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("Expected %q to be filtered:\n%s", unexpected, output)
+		}
+	}
+}
+
+func TestSetComponentLevel(t *testing.T) {
+	if _, err := Init(WithLevel("info"), WithOutputs(filepath.Join(t.TempDir(), "app.log"))); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	log := Named("cache")
+	if log.Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("Expected component to follow the global level")
+	}
+
+	if err := SetComponentLevel("cache", "debug"); err != nil {
+		t.Fatalf("SetComponentLevel failed: %v", err)
+	}
+	if !log.Core().Enabled(zapcore.DebugLevel) || Logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Error("Expected only the component to be at debug")
+	}
+
+	ResetComponentLevel("cache")
+	if log.Core().Enabled(zapcore.DebugLevel) {
+		t.Error("Expected reset component to follow the global level")
+	}
+	if err := SetComponentLevel("cache", "chatty"); err == nil {
+		t.Error("Expected invalid level to fail")
+	}
+}
+
+func TestInit_InvalidComponentLevel(t *testing.T) {
+	previous := Logger
+	if _, err := Init(WithComponentLevels(map[string]string{"db": "loud"})); err == nil {
+		t.Error("Expected invalid component level to fail")
+	}
+	if Logger != previous {
+		t.Error("Expected global Logger to be unchanged")
+	}
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/khekrn/core/internal/correlation"
+	"go.uber.org/zap"
+)
+
+// CorrelationKey identifies a context value that FromContext attaches to
+// every log entry under its field name
+type CorrelationKey = correlation.Key
+
+// Built-in correlation keys
+var (
+	RequestIDKey = correlation.RequestID
+	TenantIDKey  = correlation.TenantID
+	UserIDKey    = correlation.UserID
+)
+
+// NewCorrelationKey registers a context value that FromContext attaches as
+// field, e.g. NewCorrelationKey("session_id"). Call it once, typically in a
+// package-level var, and store values with WithCorrelation.
+func NewCorrelationKey(field string) *CorrelationKey {
+	return correlation.NewKey(field)
+}
+
+// WithCorrelation returns a context carrying value under key
+func WithCorrelation(ctx context.Context, key *CorrelationKey, value string) context.Context {
+	return correlation.With(ctx, key, value)
+}
+
+// CorrelationFromContext returns the value stored under key, or ""
+func CorrelationFromContext(ctx context.Context, key *CorrelationKey) string {
+	return correlation.FromContext(ctx, key)
+}
+
+// WithRequestID returns a context carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return WithCorrelation(ctx, RequestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored with WithRequestID, or
+// under the legacy "RequestID" string key, or ""
+func RequestIDFromContext(ctx context.Context) string {
+	return correlation.RequestIDFromContext(ctx)
+}
+
+// WithTenantID returns a context carrying the tenant ID
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return WithCorrelation(ctx, TenantIDKey, tenantID)
+}
+
+// TenantIDFromContext returns the tenant ID stored with WithTenantID, or ""
+func TenantIDFromContext(ctx context.Context) string {
+	return CorrelationFromContext(ctx, TenantIDKey)
+}
+
+// WithUserID returns a context carrying the user ID
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return WithCorrelation(ctx, UserIDKey, userID)
+}
+
+// UserIDFromContext returns the user ID stored with WithUserID, or ""
+func UserIDFromContext(ctx context.Context) string {
+	return CorrelationFromContext(ctx, UserIDKey)
+}
+
+// correlationFields returns a field for each correlation value in ctx
+func correlationFields(ctx context.Context) []zap.Field {
+	var fields []zap.Field
+	for _, key := range correlation.Keys() {
+		value := CorrelationFromContext(ctx, key)
+		if key == RequestIDKey {
+			value = RequestIDFromContext(ctx)
+		}
+		if value != "" {
+			fields = append(fields, zap.String(key.Field(), value))
+		}
+	}
+	return fields
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func observeGlobal(t *testing.T) *observer.ObservedLogs {
+	t.Helper()
+	core, logs := observer.New(zap.DebugLevel)
+	previous := Logger
+	Logger = zap.New(core)
+	t.Cleanup(func() { Logger = previous })
+	return logs
+}
+
+func TestCorrelationFromContext(t *testing.T) {
+	ctx := WithUserID(WithTenantID(WithRequestID(context.Background(), "req-1"), "acme"), "u-7")
+	if RequestIDFromContext(ctx) != "req-1" || TenantIDFromContext(ctx) != "acme" || UserIDFromContext(ctx) != "u-7" {
+		t.Error("Expected stored correlation values")
+	}
+
+	legacy := context.WithValue(context.Background(), "RequestID", "req-legacy")
+	if RequestIDFromContext(legacy) != "req-legacy" {
+		t.Error("Expected the legacy RequestID key to be read")
+	}
+	if RequestIDFromContext(context.Background()) != "" {
+		t.Error("Expected empty request ID")
+	}
+}
+
+func TestFromContext_CorrelationFields(t *testing.T) {
+	logs := observeGlobal(t)
+	sessionKey := NewCorrelationKey("session_id")
+
+	ctx := WithTenantID(WithRequestID(context.Background(), "req-1"), "acme")
+	ctx = WithCorrelation(ctx, sessionKey, "s-9")
+	FromContext(ctx).Info("hello")
+
+	fields := logs.All()[0].ContextMap()
+	expected := map[string]any{"request_id": "req-1", "tenant_id": "acme", "session_id": "s-9"}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, fields)
+		}
+	}
+	if _, ok := fields["user_id"]; ok {
+		t.Error("Expected absent correlation values to be skipped")
+	}
+}
+
+func TestFromContext_ContextLoggerGetsCorrelationFields(t *testing.T) {
+	observeGlobal(t)
+	core, logs := observer.New(zap.DebugLevel)
+
+	ctx := WithContext(context.Background(), zap.New(core).With(zap.String("component", "worker")))
+	FromContext(WithRequestID(ctx, "req-2")).Info("hello")
+
+	fields := logs.All()[0].ContextMap()
+	if fields["component"] != "worker" || fields["request_id"] != "req-2" {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+}
+
+func TestAppendFields(t *testing.T) {
+	logs := observeGlobal(t)
+
+	parent := AppendFields(WithTenantID(context.Background(), "acme"), zap.String("order_id", "o-1"))
+	child := AppendFields(parent, zap.String("table", "payments"), zap.Int("attempt", 2))
+	if AppendFields(child) != child {
+		t.Error("Expected AppendFields without fields to return ctx")
+	}
+
+	FromContext(child).Info("child")
+	FromContext(parent).Info("parent")
+
+	fields := logs.All()[0].ContextMap()
+	if fields["tenant_id"] != "acme" || fields["order_id"] != "o-1" || fields["table"] != "payments" || fields["attempt"] != int64(2) {
+		t.Errorf("Unexpected child fields %v", fields)
+	}
+	if _, ok := logs.All()[1].ContextMap()["table"]; ok {
+		t.Error("Expected child fields not to leak into the parent context")
+	}
+	if len(FieldsFromContext(child)) != 3 || FieldsFromContext(context.Background()) != nil {
+		t.Error("Unexpected FieldsFromContext result")
+	}
+}
@@ -0,0 +1,36 @@
+// Package datadog adds Datadog trace correlation to the logger package.
+// Importing it registers a trace extractor, so logger.FromContext attaches
+// dd.trace_id and dd.span_id whenever a Datadog span is active in the
+// context, linking logs and traces in Datadog.
+//
+// Example usage:
+//
+//	import _ "github.com/khekrn/core/logger/datadog"
+package datadog
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger.RegisterTraceExtractor(TraceFields)
+}
+
+// TraceFields returns dd.trace_id and dd.span_id for the Datadog span in ctx,
+// as the decimal strings the Datadog log pipeline expects
+func TraceFields(ctx context.Context) []zap.Field {
+	span, ok := tracer.SpanFromContext(ctx)
+	if !ok || span == nil {
+		return nil
+	}
+	spanContext := span.Context()
+	return []zap.Field{
+		zap.String("dd.trace_id", strconv.FormatUint(spanContext.TraceIDLower(), 10)),
+		zap.String("dd.span_id", strconv.FormatUint(spanContext.SpanID(), 10)),
+	}
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// atomicLevel is the level of every logger built by this package, so it can
+// be changed at runtime without rebuilding the logger
+var atomicLevel = zap.NewAtomicLevel()
+
+// SetLevel changes the minimum level of the global logger at runtime, e.g.
+// to "debug" while investigating an incident
+func SetLevel(level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	atomicLevel.SetLevel(parsed)
+	return nil
+}
+
+// Level returns the current minimum level of the global logger
+func Level() zapcore.Level {
+	return atomicLevel.Level()
+}
+
+// LevelHandler returns an http.Handler that reports the current level on
+// GET and changes it on PUT, with a body such as {"level":"debug"}. Mount it
+// on an internal admin port only.
+//
+// Example:
+//
+//	admin.Handle("/log/level", logger.LevelHandler())
+//	// curl -X PUT -d '{"level":"debug"}' localhost:9090/log/level
+func LevelHandler() http.Handler {
+	return atomicLevel
+}
+
+// ReloadLevelOnSIGHUP sets the level from source each time the process
+// receives SIGHUP, until ctx is done. source typically reads a config file
+// or environment file; when it fails, the level is left unchanged and the
+// error is logged.
+//
+// Example:
+//
+//	go logger.ReloadLevelOnSIGHUP(ctx, func() (string, error) {
+//		data, err := os.ReadFile("/etc/myapp/log-level")
+//		return strings.TrimSpace(string(data)), err
+//	})
+func ReloadLevelOnSIGHUP(ctx context.Context, source func() (string, error)) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	reloadLevelOn(ctx, signals, source)
+}
+
+// reloadLevelOn sets the level from source for every value on signals
+func reloadLevelOn(ctx context.Context, signals <-chan os.Signal, source func() (string, error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			level, err := source()
+			if err == nil {
+				err = SetLevel(level)
+			}
+			if err != nil {
+				global().Warn("Failed to reload log level", zap.Error(err))
+			} else {
+				global().Info("Log level reloaded", zap.String("level", Level().String()))
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSetLevel(t *testing.T) {
+	if _, err := Init(WithLevel("info"), WithOutputs(filepath.Join(t.TempDir(), "app.log"))); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if Logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("Expected debug to be disabled at info level")
+	}
+
+	if err := SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel failed: %v", err)
+	}
+	if !Logger.Core().Enabled(zapcore.DebugLevel) || Level() != zapcore.DebugLevel {
+		t.Error("Expected SetLevel to change the running logger")
+	}
+	if err := SetLevel("chatty"); err == nil {
+		t.Error("Expected invalid level to fail")
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	SetLevel("info")
+	handler := LevelHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"warn"}`)))
+	if rec.Code != http.StatusOK || Level() != zapcore.WarnLevel {
+		t.Errorf("Expected PUT to set warn, got %d %s (%v)", rec.Code, rec.Body, Level())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(rec.Body.String(), `"warn"`) {
+		t.Errorf("Expected GET to report warn, got %s", rec.Body)
+	}
+}
+
+func TestReloadLevelOnSignal(t *testing.T) {
+	logs := observeGlobal(t)
+	SetLevel("info")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal)
+	done := make(chan struct{})
+	levels := []string{"error", "bogus"}
+	go func() {
+		reloadLevelOn(ctx, signals, func() (string, error) {
+			level := levels[0]
+			levels = levels[1:]
+			return level, nil
+		})
+		close(done)
+	}()
+
+	signals <- syscall.SIGHUP
+	signals <- syscall.SIGHUP
+	cancel()
+	<-done
+
+	if Level() != zapcore.ErrorLevel {
+		t.Errorf("Expected the first reload to set error and the invalid one to be ignored, got %v", Level())
+	}
+	if logs.FilterMessage("Failed to reload log level").Len() != 1 {
+		t.Error("Expected the invalid level to be logged")
+	}
+}
@@ -7,21 +7,26 @@
 // Example usage:
 //
 //	// Initialize logger
-//	logger.InitLogger("info", "production")
-//	defer logger.Sync()
+//	log, err := logger.Init(logger.WithEnvironment("production"), logger.WithLevel("info"))
+//	if err != nil {
+//		return err
+//	}
+//	defer log.Sync()
 //
 //	// Basic logging
 //	logger.Info("Application started")
 //	logger.Error("Error occurred", zap.String("error", "connection failed"))
 //
 //	// Context-aware logging
-//	ctx := context.WithValue(context.Background(), "RequestID", "req-123")
+//	ctx := logger.WithRequestID(context.Background(), "req-123")
 //	log := logger.FromContext(ctx)
 //	log.Info("Processing request") // Automatically includes request_id
 package logger
 
 import (
 	"context"
+	"fmt"
+	"os"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -31,17 +36,46 @@ type contextKey int
 
 const (
 	loggerKey contextKey = iota
+	fieldsKey
 )
 
-// Logger is the global logger instance
-var Logger *zap.Logger
+// Logger is the global logger instance. Until Init or InitLogger runs it
+// discards every entry, so packages can log before the application has
+// configured logging.
+var Logger = newNopLogger()
+
+// newNopLogger returns a logger that discards entries but still exits on
+// Fatal, as the configured logger would
+func newNopLogger() *zap.Logger {
+	return zap.NewNop().WithOptions(zap.WithFatalHook(zapcore.WriteThenFatal))
+}
+
+// global returns the global Logger, or a no-op logger if it was set to nil
+func global() *zap.Logger {
+	if logger := Logger; logger != nil {
+		return logger
+	}
+	return newNopLogger()
+}
+
+// Supported log encodings
+const (
+	EncodingJSON    = "json"    // One JSON object per line, for log aggregation
+	EncodingConsole = "console" // Human-readable, colored output for development
+)
 
 // InitLogger initializes the global logger with the specified log level and environment.
+// If the logger cannot be built, the error is written to stderr and the
+// global Logger is left unchanged; use Init or MustInit to handle it.
 //
 // logLevel: The minimum log level (debug, info, warn, error, fatal, panic)
-// env: The environment type (development, production) - affects output format and features
+// env: The environment type (development, production) - production uses
+// NewProductionConfig (JSON), anything else NewDevelopmentConfig (console)
 func InitLogger(logLevel, env string) {
-	var err error
+	zapCfg := NewDevelopmentConfig()
+	if env == "production" {
+		zapCfg = NewProductionConfig()
+	}
 
 	// Set default log level to InfoLevel
 	level := zapcore.InfoLevel
@@ -52,34 +86,95 @@ func InitLogger(logLevel, env string) {
 			level = lvl
 		}
 	}
+	zapCfg.Level = zap.NewAtomicLevelAt(level)
 
-	zapCfg := zap.Config{
-		Level:             zap.NewAtomicLevelAt(level),
-		Development:       false,
-		DisableCaller:     false,
-		DisableStacktrace: env == "production",
-		Encoding:          "console",
-		EncoderConfig: zapcore.EncoderConfig{
-			TimeKey:          "timestamp",
-			LevelKey:         "level",
-			NameKey:          "logger",
-			CallerKey:        "caller",
-			MessageKey:       "message",
-			StacktraceKey:    "stacktrace",
-			LineEnding:       zapcore.DefaultLineEnding,
-			EncodeLevel:      zapcore.CapitalColorLevelEncoder,
-			EncodeTime:       zapcore.ISO8601TimeEncoder,
-			EncodeDuration:   zapcore.StringDurationEncoder,
-			EncodeCaller:     zapcore.ShortCallerEncoder,
-			ConsoleSeparator: " | ",
+	if err := InitLoggerWithConfig(zapCfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+	}
+}
+
+// InitLoggerWithConfig initializes the global logger from a zap config,
+// typically a preset with adjustments, e.g. a different Encoding
+func InitLoggerWithConfig(zapCfg zap.Config) error {
+	_, err := build(zapCfg)
+	return err
+}
+
+// build builds a logger from zapCfg with the shared runtime level and
+// installs it as the global Logger
+func build(zapCfg zap.Config, opts ...zap.Option) (*zap.Logger, error) {
+	level := zapcore.InfoLevel
+	if zapCfg.Level != (zap.AtomicLevel{}) {
+		level = zapCfg.Level.Level()
+	}
+	// The core accepts every level; levelCore applies the global level, or a
+	// component's own level for loggers returned by Named
+	zapCfg.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+
+	opts = append([]zap.Option{zap.AddCaller(), zap.AddCallerSkip(1)}, opts...)
+	opts = append(opts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: core, level: atomicLevel}
+	}))
+	logger, err := zapCfg.Build(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build logger: %w", err)
+	}
+	atomicLevel.SetLevel(level)
+	Logger = logger
+	return logger, nil
+}
+
+// NewProductionConfig returns the production preset: JSON encoding at info
+// level to stdout, sampling of repeated messages, and no stack traces. Add
+// a RotateScheme URL to OutputPaths to also write a rotated file.
+func NewProductionConfig() zap.Config {
+	encoderCfg := baseEncoderConfig()
+	encoderCfg.EncodeLevel = zapcore.LowercaseLevelEncoder
+
+	return zap.Config{
+		Level:             zap.NewAtomicLevelAt(zapcore.InfoLevel),
+		DisableStacktrace: true,
+		Sampling: &zap.SamplingConfig{
+			Initial:    100,
+			Thereafter: 100,
 		},
-		OutputPaths:      []string{"stdout", "/tmp/logs"},
+		Encoding:         EncodingJSON,
+		EncoderConfig:    encoderCfg,
+		OutputPaths:      []string{"stdout"},
 		ErrorOutputPaths: []string{"stderr"},
 	}
+}
 
-	Logger, err = zapCfg.Build(zap.AddCaller(), zap.AddCallerSkip(1))
-	if err != nil {
-		panic("Failed to initialize logger: " + err.Error())
+// NewDevelopmentConfig returns the development preset: colored console
+// encoding at debug level to stdout with stack traces on errors
+func NewDevelopmentConfig() zap.Config {
+	encoderCfg := baseEncoderConfig()
+	encoderCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	encoderCfg.ConsoleSeparator = " | "
+
+	return zap.Config{
+		Level:            zap.NewAtomicLevelAt(zapcore.DebugLevel),
+		Encoding:         EncodingConsole,
+		EncoderConfig:    encoderCfg,
+		OutputPaths:      []string{"stdout"},
+		ErrorOutputPaths: []string{"stderr"},
+	}
+}
+
+// baseEncoderConfig returns the field names and encoders shared by the presets
+func baseEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "timestamp",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "message",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.CapitalLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 }
 
@@ -88,53 +183,83 @@ func WithContext(ctx context.Context, logger *zap.Logger) context.Context {
 	return context.WithValue(ctx, loggerKey, logger)
 }
 
+// AppendFields returns a context whose FromContext loggers include fields,
+// after any fields appended by parent contexts. Each layer can add what it
+// knows, e.g. a handler the order ID and a repository the table, without
+// passing loggers around.
+//
+// Example:
+//
+//	ctx = logger.AppendFields(ctx, zap.String("order_id", order.ID))
+//	logger.FromContext(ctx).Info("Charging card") // includes order_id
+func AppendFields(ctx context.Context, fields ...zap.Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	existing := FieldsFromContext(ctx)
+	combined := make([]zap.Field, 0, len(existing)+len(fields))
+	combined = append(append(combined, existing...), fields...)
+	return context.WithValue(ctx, fieldsKey, combined)
+}
+
+// FieldsFromContext returns the fields added with AppendFields
+func FieldsFromContext(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey).([]zap.Field)
+	return fields
+}
+
 // FromContext extracts a logger from the context. If no logger is found,
-// it returns the global logger. If a RequestID is present in the context,
-// it automatically adds it as a field to the logger.
+// it returns the global logger. Correlation values in the context, such as
+// the request ID stored with WithRequestID, the IDs of the active trace
+// span, and fields added with AppendFields are included.
 func FromContext(ctx context.Context) *zap.Logger {
 	if ctx == nil {
-		return Logger
+		return global()
 	}
 
 	// First check for logger directly in context
-	if logger, ok := ctx.Value(loggerKey).(*zap.Logger); ok {
-		return logger
+	logger, ok := ctx.Value(loggerKey).(*zap.Logger)
+	if !ok || logger == nil {
+		logger = global()
 	}
 
-	// Fallback to adding request ID if available
-	if requestID, ok := ctx.Value("RequestID").(string); ok && requestID != "" {
-		return Logger.With(zap.String("request_id", requestID))
+	fields := append(correlationFields(ctx), traceFields(ctx)...)
+	fields = append(fields, FieldsFromContext(ctx)...)
+	if len(fields) > 0 {
+		return logger.With(fields...)
 	}
-
-	return Logger
+	return logger
 }
 
 // Info logs an info level message using the global logger
 func Info(message string, fields ...zap.Field) {
-	Logger.Info(message, fields...)
+	global().Info(message, fields...)
 }
 
 // Error logs an error level message using the global logger
 func Error(message string, fields ...zap.Field) {
-	Logger.Error(message, fields...)
+	global().Error(message, fields...)
 }
 
 // Debug logs a debug level message using the global logger
 func Debug(message string, fields ...zap.Field) {
-	Logger.Debug(message, fields...)
+	global().Debug(message, fields...)
 }
 
 // Warn logs a warning level message using the global logger
 func Warn(message string, fields ...zap.Field) {
-	Logger.Warn(message, fields...)
+	global().Warn(message, fields...)
 }
 
 // Fatal logs a fatal level message using the global logger and exits the program
 func Fatal(message string, fields ...zap.Field) {
-	Logger.Fatal(message, fields...)
+	global().Fatal(message, fields...)
 }
 
 // Sync flushes any buffered log entries. Should be called before program exit.
 func Sync() error {
-	return Logger.Sync()
+	return global().Sync()
 }
@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestPresets(t *testing.T) {
+	if cfg := NewProductionConfig(); cfg.Encoding != EncodingJSON || !cfg.DisableStacktrace || cfg.Sampling == nil {
+		t.Errorf("Unexpected production preset: %+v", cfg)
+	}
+	if cfg := NewDevelopmentConfig(); cfg.Encoding != EncodingConsole || cfg.Sampling != nil {
+		t.Errorf("Unexpected development preset: %+v", cfg)
+	}
+}
+
+func TestInitLoggerWithConfig_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	cfg := NewProductionConfig()
+	cfg.OutputPaths = []string{path}
+
+	if err := InitLoggerWithConfig(cfg); err != nil {
+		t.Fatalf("InitLoggerWithConfig failed: %v", err)
+	}
+	Info("hello")
+	_ = Sync()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(data))), &entry); err != nil {
+		t.Fatalf("Expected a JSON log line, got %q: %v", data, err)
+	}
+	if entry["message"] != "hello" || entry["level"] != "info" {
+		t.Errorf("Unexpected entry: %v", entry)
+	}
+}
+
+func TestInitLoggerWithConfig_InvalidEncoding(t *testing.T) {
+	cfg := NewProductionConfig()
+	cfg.Encoding = "xml"
+	if err := InitLoggerWithConfig(cfg); err == nil {
+		t.Error("Expected unknown encoding to fail")
+	}
+}
+
+func TestInit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := Init(
+		WithEnvironment("production"),
+		WithLevel("warn"),
+		WithOutputs(path),
+		WithoutSampling(),
+		WithFields(zap.String("service", "orders")),
+	)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if Logger != log {
+		t.Error("Expected Init to install the global logger")
+	}
+
+	Info("skipped")
+	Warn("kept")
+	_ = Sync()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected only the warning, got %q", data)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Expected JSON, got %q", lines[0])
+	}
+	if entry["message"] != "kept" || entry["service"] != "orders" {
+		t.Errorf("Unexpected entry: %v", entry)
+	}
+}
+
+func TestInit_Errors(t *testing.T) {
+	previous := Logger
+	for _, opts := range [][]Option{
+		{WithLevel("loud")},
+		{WithEncoding("xml")},
+		{WithOutputs("rotate:///tmp/x.log?compress=maybe")},
+	} {
+		if _, err := Init(opts...); err == nil {
+			t.Error("Expected Init to fail")
+		}
+	}
+	if Logger != previous {
+		t.Error("Expected a failed Init to keep the global logger")
+	}
+}
+
+func TestDefaultLogger_IsSafe(t *testing.T) {
+	previous := Logger
+	t.Cleanup(func() { Logger = previous })
+
+	for _, logger := range []*zap.Logger{newNopLogger(), nil} {
+		Logger = logger
+		Info("before init")
+		Infof("before %s", "init")
+		Named("payments").Warn("before init")
+		FromContext(context.Background()).Error("before init")
+		FromContext(WithRequestID(context.Background(), "req-1")).Debug("before init")
+		if err := Sync(); err != nil {
+			t.Errorf("Expected Sync to succeed, got %v", err)
+		}
+	}
+}
+
+func TestMustInit(t *testing.T) {
+	previous := Logger
+	t.Cleanup(func() { Logger = previous })
+
+	if MustInit(WithOutputs(os.DevNull)) != Logger {
+		t.Error("Expected MustInit to install the logger")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustInit to panic on an invalid level")
+		}
+	}()
+	MustInit(WithLevel("loud"))
+}
+
+func TestInitLogger_DoesNotPanic(t *testing.T) {
+	previous := Logger
+	t.Cleanup(func() { Logger = previous })
+
+	InitLogger("debug", "development")
+	if Logger == previous || Level() != zap.DebugLevel {
+		t.Error("Expected InitLogger to install a debug logger")
+	}
+}
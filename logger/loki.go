@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LokiScheme is the zap sink scheme for Grafana Loki, e.g.
+// "loki://loki.internal:3100?labels=app:orders,env:prod&tenant=team-a".
+// Add tls=true to push over HTTPS.
+const LokiScheme = "loki"
+
+// lokiPushPath is the Loki push API endpoint
+const lokiPushPath = "/loki/api/v1/push"
+
+// LokiConfig configures a Loki sink
+type LokiConfig struct {
+	URL     string            // Push endpoint, e.g. "http://loki:3100/loki/api/v1/push"
+	Labels  map[string]string // Stream labels, e.g. {"app": "orders"}
+	Tenant  string            // Sent as X-Scope-OrgID for multi-tenant Loki
+	Headers map[string]string // Extra headers, e.g. Authorization
+	Client  *http.Client      // http.DefaultClient when nil
+	Remote  RemoteConfig
+}
+
+// NewLokiSink pushes batches of log lines to Grafana Loki as a single stream
+func NewLokiSink(config LokiConfig) (*RemoteSink, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("loki sink requires a URL")
+	}
+	if len(config.Labels) == 0 {
+		return nil, fmt.Errorf("loki sink requires at least one label")
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	return newRemoteSink(&lokiWriter{config: config, timeout: writeTimeout(config.Remote)}, config.Remote), nil
+}
+
+// lokiWriter pushes batches to the Loki push API
+type lokiWriter struct {
+	config  LokiConfig
+	timeout time.Duration
+}
+
+// lokiPush is the body of a Loki push request
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+// lokiStream is a labeled stream of [timestamp, line] pairs
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// writeBatch pushes entries as one stream
+func (w *lokiWriter) writeBatch(entries []remoteEntry) error {
+	stream := lokiStream{Stream: w.config.Labels, Values: make([][2]string, len(entries))}
+	for i, entry := range entries {
+		stream.Values[i] = [2]string{
+			strconv.FormatInt(entry.at.UnixNano(), 10),
+			strings.TrimRight(string(entry.line), "\n"),
+		}
+	}
+	body, err := json.Marshal(lokiPush{Streams: []lokiStream{stream}})
+	if err != nil {
+		return fmt.Errorf("failed to encode loki push: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create loki request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.config.Tenant != "" {
+		req.Header.Set("X-Scope-OrgID", w.config.Tenant)
+	}
+	for key, value := range w.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.config.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push to loki: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("loki push failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(message))
+	}
+	return nil
+}
+
+// close is a no-op; the HTTP client owns its connections
+func (w *lokiWriter) close() error {
+	return nil
+}
+
+// newLokiSink creates a Loki sink from a loki:// URL. labels is a comma
+// separated list of name:value pairs.
+func newLokiSink(u *url.URL) (zap.Sink, error) {
+	remote, err := parseRemoteConfig(u.Query())
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(query.Get("labels"), ",") {
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid loki label %q, expected name:value", pair)
+		}
+		labels[name] = value
+	}
+
+	scheme := "http"
+	if query.Get("tls") == "true" {
+		scheme = "https"
+	}
+	path := u.Path
+	if path == "" || path == "/" {
+		path = lokiPushPath
+	}
+	return NewLokiSink(LokiConfig{
+		URL:    (&url.URL{Scheme: scheme, Host: u.Host, Path: path}).String(),
+		Labels: labels,
+		Tenant: query.Get("tenant"),
+		Remote: remote,
+	})
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"os"
+
+	"github.com/khekrn/core/buildinfo"
+	"go.uber.org/zap"
+)
+
+// ServiceMetadata identifies the process in every log entry. Empty fields
+// are detected by DetectServiceMetadata where possible.
+type ServiceMetadata struct {
+	Name        string `yaml:"name"`        // "service" field
+	Version     string `yaml:"version"`     // "version" field
+	GitSHA      string `yaml:"git_sha"`     // "git_sha" field
+	Environment string `yaml:"environment"` // "environment" field
+	Region      string `yaml:"region"`      // "region" field
+	Hostname    string `yaml:"hostname"`    // "hostname" field
+}
+
+// Environment variables read by DetectServiceMetadata, in order of preference
+var (
+	serviceNameEnv = []string{"SERVICE_NAME", "OTEL_SERVICE_NAME", "DD_SERVICE"}
+	versionEnv     = []string{"SERVICE_VERSION", "DD_VERSION"}
+	environmentEnv = []string{"ENVIRONMENT", "APP_ENV", "DD_ENV"}
+	regionEnv      = []string{"REGION", "AWS_REGION", "AWS_DEFAULT_REGION", "GOOGLE_CLOUD_REGION", "FLY_REGION"}
+)
+
+// DetectServiceMetadata reads the metadata available to the process: the
+// name, version, environment, and region from common environment variables
+// (e.g. SERVICE_NAME, AWS_REGION), the git SHA, and the name and version
+// from buildinfo.Get when no variable sets them, and the hostname.
+func DetectServiceMetadata() ServiceMetadata {
+	meta := ServiceMetadata{
+		Name:        firstEnv(serviceNameEnv),
+		Version:     firstEnv(versionEnv),
+		Environment: firstEnv(environmentEnv),
+		Region:      firstEnv(regionEnv),
+	}
+	build := buildinfo.Get()
+	if meta.Version == "" {
+		meta.Version = build.Version
+	}
+	meta.GitSHA = build.GitSHA
+	if meta.Name == "" {
+		meta.Name = build.Name
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		meta.Hostname = hostname
+	}
+	return meta
+}
+
+// WithServiceMetadata adds service, version, git_sha, environment, region,
+// and hostname fields to every entry. Empty fields are detected with
+// DetectServiceMetadata, and the environment defaults to the one passed to
+// WithEnvironment.
+//
+// Example:
+//
+//	logger.Init(
+//		logger.WithEnvironment("production"),
+//		logger.WithServiceMetadata(logger.ServiceMetadata{Name: "orders", Version: version}),
+//	)
+func WithServiceMetadata(meta ServiceMetadata) Option {
+	return func(config *Config) {
+		config.Service = &meta
+	}
+}
+
+// fields returns meta as log fields, filling empty values from detected and
+// skipping values that are still empty
+func (meta ServiceMetadata) fields(detected ServiceMetadata) []zap.Field {
+	var fields []zap.Field
+	for _, field := range []struct {
+		key, value, fallback string
+	}{
+		{"service", meta.Name, detected.Name},
+		{"version", meta.Version, detected.Version},
+		{"git_sha", meta.GitSHA, detected.GitSHA},
+		{"environment", meta.Environment, detected.Environment},
+		{"region", meta.Region, detected.Region},
+		{"hostname", meta.Hostname, detected.Hostname},
+	} {
+		value := field.value
+		if value == "" {
+			value = field.fallback
+		}
+		if value != "" {
+			fields = append(fields, zap.String(field.key, value))
+		}
+	}
+	return fields
+}
+
+// firstEnv returns the first non-empty environment variable in names
+func firstEnv(names []string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDetectServiceMetadata(t *testing.T) {
+	t.Setenv("SERVICE_NAME", "")
+	t.Setenv("OTEL_SERVICE_NAME", "orders")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("REGION", "")
+
+	meta := DetectServiceMetadata()
+	hostname, _ := os.Hostname()
+	if meta.Name != "orders" || meta.Region != "eu-west-1" || meta.Hostname != hostname {
+		t.Errorf("Unexpected metadata %+v", meta)
+	}
+}
+
+func TestWithServiceMetadata(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("REGION", "")
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	_, err := Init(
+		WithEnvironment("production"),
+		WithOutputs(os.DevNull),
+		WithServiceMetadata(ServiceMetadata{Name: "orders", Version: "1.4.2", GitSHA: "abc123"}),
+		WithFields(zap.String("team", "payments")),
+		WithZapOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })),
+	)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	Info("started")
+
+	fields := logs.All()[0].ContextMap()
+	expected := map[string]any{
+		"service": "orders", "version": "1.4.2", "git_sha": "abc123",
+		"environment": "production", "region": "us-east-1", "team": "payments",
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, fields)
+		}
+	}
+	if fields["hostname"] == "" || fields["hostname"] == nil {
+		t.Error("Expected the hostname to be detected")
+	}
+}
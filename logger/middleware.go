@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/khekrn/core/idgen"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader is the header HTTPMiddleware reads and writes the request ID in
+const RequestIDHeader = "X-Request-ID"
+
+// HTTPMiddleware gives every request a request-scoped logger. It takes the
+// request ID from the context, e.g. when set by middleware.RequestID, or from
+// the X-Request-ID header, or generates one, echoes it in the response, and
+// stores it with WithRequestID. The logger in the request context carries
+// method, path, and remote_addr fields, so handlers only need
+// FromContext(r.Context()). When the handler returns, an access log line with
+// status, bytes, and duration is written: 5xx responses at error level, 4xx
+// at warn, and the rest at info.
+//
+// Example:
+//
+//	http.ListenAndServe(":8080", logger.HTTPMiddleware(mux))
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := RequestIDFromContext(r.Context())
+		if requestID == "" {
+			requestID = r.Header.Get(RequestIDHeader)
+		}
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx := WithRequestID(r.Context(), requestID)
+		ctx = WithContext(ctx, contextLogger(ctx).With(
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.String("remote_addr", r.RemoteAddr),
+		))
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		fields := []zap.Field{
+			zap.Int("status", recorder.statusCode),
+			zap.Int64("bytes", recorder.bytes),
+			zap.Duration("duration", time.Since(start)),
+		}
+		log := FromContext(ctx)
+		switch {
+		case recorder.statusCode >= http.StatusInternalServerError:
+			log.Error("HTTP request", fields...)
+		case recorder.statusCode >= http.StatusBadRequest:
+			log.Warn("HTTP request", fields...)
+		default:
+			log.Info("HTTP request", fields...)
+		}
+	})
+}
+
+// statusRecorder captures the status code and body size written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	bytes       int64
+	wroteHeader bool
+}
+
+// WriteHeader records the status code before passing it through
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+		r.statusCode = statusCode
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write counts the bytes written
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController, so
+// handlers can still flush or hijack
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Flush implements http.Flusher for streaming handlers
+func (r *statusRecorder) Flush() {
+	r.wroteHeader = true
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// newRequestID generates a request ID
+func newRequestID() string {
+	return idgen.RequestID()
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	logs := observeGlobal(t)
+
+	handler := HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("handling")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("missing"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Header().Get(RequestIDHeader) != "req-42" {
+		t.Errorf("Expected request ID to be echoed, got %q", rec.Header().Get(RequestIDHeader))
+	}
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected handler and access log entries, got %d", len(entries))
+	}
+	handling := entries[0].ContextMap()
+	if handling["request_id"] != "req-42" || handling["method"] != "GET" || handling["path"] != "/users/7" {
+		t.Errorf("Unexpected handler log fields %v", handling)
+	}
+
+	access := entries[1]
+	fields := access.ContextMap()
+	if access.Level != zapcore.WarnLevel || fields["status"] != int64(404) || fields["bytes"] != int64(7) || fields["request_id"] != "req-42" {
+		t.Errorf("Unexpected access log %v %v", access.Level, fields)
+	}
+	if _, ok := fields["duration"]; !ok {
+		t.Error("Expected duration field")
+	}
+}
+
+func TestHTTPMiddleware_GeneratesRequestID(t *testing.T) {
+	observeGlobal(t)
+
+	var seen string
+	handler := HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if seen == "" || rec.Header().Get(RequestIDHeader) != seen {
+		t.Errorf("Expected generated request ID %q in response, got %q", seen, rec.Header().Get(RequestIDHeader))
+	}
+}
@@ -0,0 +1,295 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Config holds the settings applied by Init. Empty fields keep the values
+// of the environment preset. The yaml tags let config.Load fill it from files
+// and the environment; fields tagged "-" are set in code.
+type Config struct {
+	Level             string              `yaml:"level"`            // Minimum level: debug, info, warn, error, dpanic, panic, fatal
+	Environment       string              `yaml:"environment"`      // "production" selects NewProductionConfig, anything else NewDevelopmentConfig
+	Encoding          string              `yaml:"encoding"`         // EncodingJSON or EncodingConsole
+	Outputs           []string            `yaml:"outputs"`          // Output paths, e.g. "stdout" or a RotateScheme URL
+	ErrorOutputs      []string            `yaml:"error_outputs"`    // Paths for the logger's own errors
+	Sampling          *zap.SamplingConfig `yaml:"-"`                // Replaces the preset's sampling
+	DisableSampling   bool                `yaml:"disable_sampling"` // Logs every entry, even in production
+	Fields            []zap.Field         `yaml:"-"`                // Added to every entry
+	ZapOptions        []zap.Option        `yaml:"-"`                // Passed to zap.Config.Build
+	Reporter          ErrorReporter       `yaml:"-"`                // Receives error and fatal entries
+	ReportLimit       int                 `yaml:"report_limit"`     // Reports per ReportInterval; DefaultReportLimit when zero, unlimited when negative
+	ReportInterval    time.Duration       `yaml:"report_interval"`  // DefaultReportInterval when zero
+	ComponentLevels   map[string]string   `yaml:"component_levels"` // Levels of loggers returned by Named, keyed by component
+	Redact            bool                `yaml:"redact"`           // Masks sensitive fields and values before encoding
+	RedactFields      []string            `yaml:"redact_fields"`    // Field names masked in addition to helpers.DefaultRedactedFields
+	Service           *ServiceMetadata    `yaml:"service"`          // Adds service metadata fields; empty values are detected
+	AdditionalOutputs []Output            `yaml:"-"`                // Destinations with their own encoding, written to as well
+}
+
+// Option is a function type for configuring Init
+type Option func(*Config)
+
+// WithLevel sets the minimum log level, e.g. "debug" or "warn"
+func WithLevel(level string) Option {
+	return func(config *Config) {
+		config.Level = level
+	}
+}
+
+// WithEnvironment selects the preset, "production" or "development"
+func WithEnvironment(env string) Option {
+	return func(config *Config) {
+		config.Environment = env
+	}
+}
+
+// WithEncoding sets the encoding, EncodingJSON or EncodingConsole
+func WithEncoding(encoding string) Option {
+	return func(config *Config) {
+		config.Encoding = encoding
+	}
+}
+
+// WithOutputs replaces the output paths, e.g. "stdout", "stderr", a file
+// path, or a RotateScheme URL
+func WithOutputs(paths ...string) Option {
+	return func(config *Config) {
+		config.Outputs = paths
+	}
+}
+
+// WithErrorOutputs replaces the paths the logger reports its own errors to
+func WithErrorOutputs(paths ...string) Option {
+	return func(config *Config) {
+		config.ErrorOutputs = paths
+	}
+}
+
+// WithSampling logs the first initial entries with the same level and
+// message each second, then every thereafter-th one
+func WithSampling(initial, thereafter int) Option {
+	return func(config *Config) {
+		config.Sampling = &zap.SamplingConfig{Initial: initial, Thereafter: thereafter}
+		config.DisableSampling = false
+	}
+}
+
+// WithoutSampling logs every entry
+func WithoutSampling() Option {
+	return func(config *Config) {
+		config.Sampling = nil
+		config.DisableSampling = true
+	}
+}
+
+// WithFields adds fields to every entry, e.g. the service name and version
+func WithFields(fields ...zap.Field) Option {
+	return func(config *Config) {
+		config.Fields = append(config.Fields, fields...)
+	}
+}
+
+// WithZapOptions passes additional options to zap, e.g. zap.Hooks
+func WithZapOptions(opts ...zap.Option) Option {
+	return func(config *Config) {
+		config.ZapOptions = append(config.ZapOptions, opts...)
+	}
+}
+
+// WithErrorReporter forwards error and fatal entries, with stack traces and
+// fields, to reporter, e.g. an adapter for Sentry. Entries are tagged with
+// the environment and rate limited to DefaultReportLimit per
+// DefaultReportInterval unless WithReportRateLimit is used.
+func WithErrorReporter(reporter ErrorReporter) Option {
+	return func(config *Config) {
+		config.Reporter = reporter
+	}
+}
+
+// WithReportRateLimit allows at most limit error reports per interval;
+// a negative limit disables rate limiting
+func WithReportRateLimit(limit int, interval time.Duration) Option {
+	return func(config *Config) {
+		config.ReportLimit = limit
+		config.ReportInterval = interval
+	}
+}
+
+// WithComponentLevels sets independent levels for loggers returned by Named,
+// e.g. {"httpclient": "debug"}. Components not in the map use the global
+// level.
+func WithComponentLevels(levels map[string]string) Option {
+	return func(config *Config) {
+		config.ComponentLevels = levels
+	}
+}
+
+// WithRedaction masks sensitive values before they are encoded: fields
+// named in helpers.DefaultRedactedFields or fields, card numbers and other
+// helpers.DefaultRedactedPatterns matches in messages and string fields, and
+// sensitive parts of structs logged with zap.Any. Error reports are masked
+// the same way.
+func WithRedaction(fields ...string) Option {
+	return func(config *Config) {
+		config.Redact = true
+		config.RedactFields = append(config.RedactFields, fields...)
+	}
+}
+
+// WithConfig applies a loaded Config, e.g. from config.Load. It replaces
+// the settings of earlier options, so pass it first and refine it with
+// later ones.
+//
+// Example:
+//
+//	log, err := logger.Init(
+//		logger.WithConfig(settings.Log),
+//		logger.WithErrorReporter(sentryReporter),
+//	)
+func WithConfig(cfg Config) Option {
+	return func(config *Config) {
+		*config = cfg
+	}
+}
+
+// Init builds a logger from the environment preset and options, installs it
+// as the global Logger, and returns it. Unlike InitLogger it never panics:
+// an invalid level, encoding, or output is returned as an error and the
+// global Logger is left unchanged.
+//
+// Example:
+//
+//	log, err := logger.Init(
+//		logger.WithEnvironment("production"),
+//		logger.WithLevel(os.Getenv("LOG_LEVEL")),
+//		logger.WithFields(zap.String("service", "orders")),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	defer log.Sync()
+func Init(opts ...Option) (*zap.Logger, error) {
+	var config Config
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	zapCfg, err := config.zapConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	componentLevels := make(map[string]zapcore.Level, len(config.ComponentLevels))
+	for component, level := range config.ComponentLevels {
+		parsed, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level %q for component %q: %w", level, component, err)
+		}
+		componentLevels[component] = parsed
+	}
+
+	// Redaction wraps each output on its own, as redactCore requires
+	var outputs []zapcore.Core
+	for _, output := range config.AdditionalOutputs {
+		core, err := newOutputCore(output, zapCfg.Sampling)
+		if err != nil {
+			return nil, err
+		}
+		if config.Redact {
+			core = newRedactCore(core, config.RedactFields)
+		}
+		outputs = append(outputs, core)
+	}
+
+	var zapOpts []zap.Option
+	if config.Redact || len(outputs) > 0 {
+		zapOpts = append(zapOpts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			if config.Redact {
+				core = newRedactCore(core, config.RedactFields)
+			}
+			return zapcore.NewTee(append([]zapcore.Core{core}, outputs...)...)
+		}))
+	}
+	zapOpts = append(zapOpts, config.ZapOptions...)
+	fields := config.Fields
+	if config.Service != nil {
+		meta := *config.Service
+		if meta.Environment == "" {
+			meta.Environment = config.Environment
+		}
+		fields = append(meta.fields(DetectServiceMetadata()), fields...)
+	}
+	if len(fields) > 0 {
+		zapOpts = append(zapOpts, zap.Fields(fields...))
+	}
+	if config.Reporter != nil {
+		limit, interval := config.ReportLimit, config.ReportInterval
+		if limit == 0 {
+			limit = DefaultReportLimit
+		}
+		if interval <= 0 {
+			interval = DefaultReportInterval
+		}
+		var reporter zapcore.Core = newReportCore(config.Reporter, config.Environment, limit, interval)
+		if config.Redact {
+			reporter = newRedactCore(reporter, config.RedactFields)
+		}
+		zapOpts = append(zapOpts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(core, reporter)
+		}))
+	}
+	logger, err := build(zapCfg, zapOpts...)
+	if err != nil {
+		return nil, err
+	}
+	setComponentLevels(componentLevels)
+	return logger, nil
+}
+
+// MustInit is like Init but panics if the logger cannot be built, for
+// programs that should not start without logging
+func MustInit(opts ...Option) *zap.Logger {
+	logger, err := Init(opts...)
+	if err != nil {
+		panic("Failed to initialize logger: " + err.Error())
+	}
+	return logger
+}
+
+// zapConfig applies the config to the environment preset
+func (c Config) zapConfig() (zap.Config, error) {
+	zapCfg := NewDevelopmentConfig()
+	if c.Environment == "production" {
+		zapCfg = NewProductionConfig()
+	}
+	zapCfg.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+	if c.Level != "" {
+		level, err := zapcore.ParseLevel(c.Level)
+		if err != nil {
+			return zapCfg, fmt.Errorf("invalid log level %q: %w", c.Level, err)
+		}
+		zapCfg.Level = zap.NewAtomicLevelAt(level)
+	}
+	if c.Encoding != "" {
+		zapCfg.Encoding = c.Encoding
+	}
+	if len(c.Outputs) > 0 {
+		zapCfg.OutputPaths = c.Outputs
+	}
+	if len(c.ErrorOutputs) > 0 {
+		zapCfg.ErrorOutputPaths = c.ErrorOutputs
+	}
+	if c.Sampling != nil {
+		zapCfg.Sampling = c.Sampling
+	}
+	if c.DisableSampling {
+		zapCfg.Sampling = nil
+	}
+	return zapCfg, nil
+}
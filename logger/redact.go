@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"encoding/json"
+
+	"github.com/khekrn/core/helpers"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redacted creates a field holding value as JSON with sensitive fields
+// masked by helpers.RedactJSON: fields tagged `redact:"true"` or
+// `json:",redact"` and fields named in helpers.DefaultRedactedFields.
+// If value cannot be encoded, the whole field is redacted.
+//
+// Example:
+//
+//	logger.Info("User registered", logger.Redacted("user", user))
+func Redacted(key string, value interface{}) zap.Field {
+	data, err := helpers.RedactJSON(value)
+	if err != nil {
+		return zap.String(key, helpers.RedactedValue)
+	}
+	return zap.Reflect(key, json.RawMessage(data))
+}
+
+// redactCore masks sensitive values before the wrapped core encodes them:
+// fields named in helpers.DefaultRedactedFields or fields, matches of
+// helpers.DefaultRedactedPatterns in messages and string fields, and
+// sensitive parts of structs logged with zap.Any. The wrapped core must
+// write every entry passed to Write, so each core of a tee is wrapped on
+// its own.
+type redactCore struct {
+	zapcore.Core
+	fields []string
+}
+
+// newRedactCore wraps core, masking fields in addition to the defaults
+func newRedactCore(core zapcore.Core, fields []string) *redactCore {
+	return &redactCore{Core: core, fields: fields}
+}
+
+// With returns a core with redacted fields added
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.redact(fields)), fields: c.fields}
+}
+
+// Check asks the wrapped core, which may sample, before adding this core
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Check(entry, nil) == nil {
+		return checked
+	}
+	return checked.AddCore(entry, c)
+}
+
+// Write redacts the message and fields and writes them to the wrapped core
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = helpers.RedactString(entry.Message)
+	return c.Core.Write(entry, c.redact(fields))
+}
+
+// redact returns a copy of fields with sensitive values masked
+func (c *redactCore) redact(fields []zapcore.Field) []zapcore.Field {
+	clean := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		switch {
+		case helpers.IsSensitiveField(field.Key, c.fields...):
+			clean[i] = zap.String(field.Key, helpers.RedactedValue)
+		case field.Type == zapcore.StringType:
+			clean[i] = zap.String(field.Key, helpers.RedactString(field.String))
+		case field.Type == zapcore.ReflectType:
+			clean[i] = redactedReflect(field.Key, field.Interface, c.fields)
+		default:
+			clean[i] = field
+		}
+	}
+	return clean
+}
+
+// redactedReflect masks value like Redacted, also masking the extra fields
+func redactedReflect(key string, value interface{}, fields []string) zap.Field {
+	data, err := helpers.RedactJSON(value)
+	if err == nil && len(fields) > 0 {
+		var doc interface{}
+		if err = json.Unmarshal(data, &doc); err == nil {
+			data, err = json.Marshal(redactNamedFields(doc, fields))
+		}
+	}
+	if err != nil {
+		return zap.String(key, helpers.RedactedValue)
+	}
+	return zap.Reflect(key, json.RawMessage(data))
+}
+
+// redactNamedFields replaces the values of object keys in fields,
+// case-insensitively
+func redactNamedFields(doc interface{}, fields []string) interface{} {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if helpers.IsSensitiveField(key, fields...) {
+				v[key] = helpers.RedactedValue
+			} else {
+				v[key] = redactNamedFields(value, fields)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactNamedFields(value, fields)
+		}
+	}
+	return doc
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithRedaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	var reported map[string]interface{}
+	_, err := Init(
+		WithEncoding(EncodingJSON),
+		WithOutputs(path),
+		WithRedaction("ssn"),
+		WithFields(zap.String("api_key", "k-123")),
+		WithErrorReporter(ErrorReporterFunc(func(entry zapcore.Entry, fields map[string]interface{}) error {
+			reported = fields
+			return nil
+		})),
+	)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	type payment struct {
+		Card  string `json:"card"`
+		SSN   string `json:"ssn"`
+		Token string `json:"token"`
+		Plan  string `json:"plan"`
+	}
+	Logger.With(zap.String("Authorization", "Bearer abc")).Error("charged 4111 1111 1111 1111",
+		zap.String("password", "hunter2"),
+		zap.String("ssn", "123-45-6789"),
+		zap.String("note", "card 4111111111111111 on file"),
+		zap.Any("payment", payment{Card: "4111111111111111", SSN: "123-45-6789", Token: "t", Plan: "pro"}),
+		zap.Int("attempt", 2),
+	)
+	Logger.Sync()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	output := string(data)
+	for _, secret := range []string{"k-123", "Bearer abc", "hunter2", "123-45-6789", "4111"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("Expected %q to be redacted:\n%s", secret, output)
+		}
+	}
+	for _, expected := range []string{`"message":"charged REDACTED"`, `"note":"card REDACTED on file"`, `"plan":"pro"`, `"attempt":2`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s in output:\n%s", expected, output)
+		}
+	}
+
+	if reported["password"] != "REDACTED" || reported["Authorization"] != "REDACTED" {
+		t.Errorf("Expected reported fields to be redacted, got %v", reported)
+	}
+}
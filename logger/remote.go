@@ -0,0 +1,360 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Sink schemes for shipping logs to a collector, usable in zap.Config
+// OutputPaths, e.g. "tcp://collector:5170?buffer_size=4096&backpressure=drop_oldest"
+const (
+	TCPScheme = "tcp"
+	UDPScheme = "udp"
+)
+
+// Remote sink defaults, applied when the RemoteConfig field is zero
+const (
+	DefaultRemoteBufferSize    = 1024
+	DefaultRemoteBatchSize     = 100
+	DefaultRemoteFlushInterval = time.Second
+	DefaultRemoteWriteTimeout  = 5 * time.Second
+)
+
+// ErrSinkClosed is returned when writing to a closed RemoteSink
+var ErrSinkClosed = errors.New("log sink closed")
+
+// BackpressurePolicy decides what a RemoteSink does when its buffer is full
+type BackpressurePolicy string
+
+// Supported backpressure policies
+const (
+	DropNewest BackpressurePolicy = "drop_newest" // Discard the entry being written (default)
+	DropOldest BackpressurePolicy = "drop_oldest" // Discard the oldest buffered entry
+	Block      BackpressurePolicy = "block"       // Wait for room, slowing down the caller
+)
+
+func init() {
+	for scheme, factory := range map[string]func(*url.URL) (zap.Sink, error){
+		TCPScheme:    newNetworkSink,
+		UDPScheme:    newNetworkSink,
+		SyslogScheme: newSyslogSink,
+		LokiScheme:   newLokiSink,
+	} {
+		if err := zap.RegisterSink(scheme, factory); err != nil {
+			panic("Failed to register " + scheme + " sink: " + err.Error())
+		}
+	}
+}
+
+// RemoteConfig configures the buffering of a RemoteSink
+type RemoteConfig struct {
+	BufferSize    int                // Entries buffered before the backpressure policy applies
+	BatchSize     int                // Entries sent per batch
+	FlushInterval time.Duration      // Maximum time an entry waits for its batch to fill
+	WriteTimeout  time.Duration      // Deadline for connecting and sending one batch
+	Backpressure  BackpressurePolicy // DropNewest when empty
+	OnError       func(error)        // Called when a batch cannot be sent; the batch is discarded
+}
+
+// remoteEntry is a buffered log line and the time it was written
+type remoteEntry struct {
+	at   time.Time
+	line []byte
+}
+
+// batchWriter sends batches of log lines to a collector
+type batchWriter interface {
+	writeBatch(entries []remoteEntry) error
+	close() error
+}
+
+// RemoteSink ships log lines to a collector from a background goroutine, so
+// logging never waits on the network. Lines are buffered and sent in
+// batches; when the buffer is full the Backpressure policy decides whether
+// entries are dropped or the caller waits. It implements zap.Sink.
+type RemoteSink struct {
+	writer batchWriter
+	config RemoteConfig
+
+	queue   chan remoteEntry
+	flushes chan chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+
+	closeOnce sync.Once
+	dropped   atomic.Uint64
+	failed    atomic.Uint64
+}
+
+// newRemoteSink starts the background sender for writer
+func newRemoteSink(writer batchWriter, config RemoteConfig) *RemoteSink {
+	if config.BufferSize <= 0 {
+		config.BufferSize = DefaultRemoteBufferSize
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultRemoteBatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultRemoteFlushInterval
+	}
+	if config.Backpressure == "" {
+		config.Backpressure = DropNewest
+	}
+
+	s := &RemoteSink{
+		writer:  writer,
+		config:  config,
+		queue:   make(chan remoteEntry, config.BufferSize),
+		flushes: make(chan chan struct{}),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// NewNetworkSink ships newline-delimited log lines to address over network
+// "tcp" or "udp", e.g. to Fluent Bit or Vector. TCP connections are opened
+// lazily and re-dialed after a failed batch; each UDP line is one datagram.
+func NewNetworkSink(network, address string, config RemoteConfig) (*RemoteSink, error) {
+	if network != "tcp" && network != "udp" {
+		return nil, fmt.Errorf("unsupported network %q", network)
+	}
+	if address == "" {
+		return nil, fmt.Errorf("network sink requires an address")
+	}
+	return newRemoteSink(&netWriter{network: network, address: address, timeout: writeTimeout(config)}, config), nil
+}
+
+// Write buffers a copy of p, applying the backpressure policy when the
+// buffer is full. Dropped entries are counted, not returned as errors.
+func (s *RemoteSink) Write(p []byte) (int, error) {
+	select {
+	case <-s.done:
+		return 0, ErrSinkClosed
+	default:
+	}
+
+	entry := remoteEntry{at: time.Now(), line: append([]byte(nil), p...)}
+	switch s.config.Backpressure {
+	case Block:
+		select {
+		case s.queue <- entry:
+		case <-s.done:
+			return 0, ErrSinkClosed
+		}
+	case DropOldest:
+		for {
+			select {
+			case s.queue <- entry:
+				return len(p), nil
+			default:
+			}
+			select {
+			case <-s.queue:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.queue <- entry:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+	return len(p), nil
+}
+
+// Sync sends every entry buffered before the call
+func (s *RemoteSink) Sync() error {
+	reply := make(chan struct{})
+	select {
+	case s.flushes <- reply:
+		<-reply
+		return nil
+	case <-s.stopped:
+		return nil
+	}
+}
+
+// Close sends the buffered entries and closes the connection
+func (s *RemoteSink) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		<-s.stopped
+		err = s.writer.close()
+	})
+	return err
+}
+
+// Dropped returns the number of entries discarded because the buffer was full
+func (s *RemoteSink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Failed returns the number of entries discarded because sending failed
+func (s *RemoteSink) Failed() uint64 {
+	return s.failed.Load()
+}
+
+// run batches buffered entries until the sink is closed
+func (s *RemoteSink) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]remoteEntry, 0, s.config.BatchSize)
+	for {
+		select {
+		case entry := <-s.queue:
+			batch = append(batch, entry)
+			if len(batch) >= s.config.BatchSize {
+				batch = s.send(batch)
+			}
+		case <-ticker.C:
+			batch = s.send(batch)
+		case reply := <-s.flushes:
+			batch = s.send(s.drain(batch))
+			close(reply)
+		case <-s.done:
+			s.send(s.drain(batch))
+			return
+		}
+	}
+}
+
+// drain moves every buffered entry into batch, sending full batches
+func (s *RemoteSink) drain(batch []remoteEntry) []remoteEntry {
+	for {
+		select {
+		case entry := <-s.queue:
+			batch = append(batch, entry)
+			if len(batch) >= s.config.BatchSize {
+				batch = s.send(batch)
+			}
+		default:
+			return batch
+		}
+	}
+}
+
+// send writes batch and returns it emptied for reuse
+func (s *RemoteSink) send(batch []remoteEntry) []remoteEntry {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := s.writer.writeBatch(batch); err != nil {
+		s.failed.Add(uint64(len(batch)))
+		if s.config.OnError != nil {
+			s.config.OnError(err)
+		}
+	}
+	return batch[:0]
+}
+
+// netWriter writes lines to a TCP or UDP connection, dialing on demand
+type netWriter struct {
+	network string
+	address string
+	timeout time.Duration
+	format  func(remoteEntry) []byte
+
+	conn net.Conn
+}
+
+// writeBatch sends each line, dropping the connection on failure so the
+// next batch re-dials
+func (w *netWriter) writeBatch(entries []remoteEntry) error {
+	if w.conn == nil {
+		conn, err := net.DialTimeout(w.network, w.address, w.timeout)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s %s: %w", w.network, w.address, err)
+		}
+		w.conn = conn
+	}
+
+	w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+	for _, entry := range entries {
+		line := entry.line
+		if w.format != nil {
+			line = w.format(entry)
+		}
+		if _, err := w.conn.Write(line); err != nil {
+			w.conn.Close()
+			w.conn = nil
+			return fmt.Errorf("failed to write to %s %s: %w", w.network, w.address, err)
+		}
+	}
+	return nil
+}
+
+// close closes the connection if one is open
+func (w *netWriter) close() error {
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// writeTimeout returns the configured write timeout or the default
+func writeTimeout(config RemoteConfig) time.Duration {
+	if config.WriteTimeout > 0 {
+		return config.WriteTimeout
+	}
+	return DefaultRemoteWriteTimeout
+}
+
+// newNetworkSink creates a network sink from a tcp:// or udp:// URL
+func newNetworkSink(u *url.URL) (zap.Sink, error) {
+	config, err := parseRemoteConfig(u.Query())
+	if err != nil {
+		return nil, err
+	}
+	return NewNetworkSink(u.Scheme, u.Host, config)
+}
+
+// parseRemoteConfig reads the buffering settings shared by the remote sink
+// URLs: buffer_size, batch_size, flush_interval, write_timeout, and
+// backpressure
+func parseRemoteConfig(query url.Values) (RemoteConfig, error) {
+	var config RemoteConfig
+	for key, target := range map[string]*int{"buffer_size": &config.BufferSize, "batch_size": &config.BatchSize} {
+		if value := query.Get(key); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return config, fmt.Errorf("invalid %s %q: %w", key, value, err)
+			}
+			*target = parsed
+		}
+	}
+	for key, target := range map[string]*time.Duration{"flush_interval": &config.FlushInterval, "write_timeout": &config.WriteTimeout} {
+		if value := query.Get(key); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				return config, fmt.Errorf("invalid %s %q: %w", key, value, err)
+			}
+			*target = parsed
+		}
+	}
+	if value := query.Get("backpressure"); value != "" {
+		policy := BackpressurePolicy(value)
+		if policy != DropNewest && policy != DropOldest && policy != Block {
+			return config, fmt.Errorf("invalid backpressure %q", value)
+		}
+		config.Backpressure = policy
+	}
+	return config, nil
+}
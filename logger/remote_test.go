@@ -0,0 +1,254 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// blockingWriter records batches and blocks until released
+type blockingWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	lines   []string
+}
+
+func (w *blockingWriter) writeBatch(entries []remoteEntry) error {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, entry := range entries {
+		w.lines = append(w.lines, string(entry.line))
+	}
+	return nil
+}
+
+func (w *blockingWriter) close() error { return nil }
+
+func TestRemoteSink_Backpressure(t *testing.T) {
+	tests := []struct {
+		policy   BackpressurePolicy
+		expected []string
+	}{
+		{DropNewest, []string{"0", "1", "2"}},
+		{DropOldest, []string{"0", "3", "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			writer := &blockingWriter{release: make(chan struct{})}
+			sink := newRemoteSink(writer, RemoteConfig{BufferSize: 2, BatchSize: 1, Backpressure: tt.policy})
+
+			sink.Write([]byte("0"))
+			waitFor(t, func() bool { return len(sink.queue) == 0 })
+			for _, line := range []string{"1", "2", "3", "4"} {
+				sink.Write([]byte(line))
+			}
+			if sink.Dropped() != 2 {
+				t.Errorf("Expected 2 dropped entries, got %d", sink.Dropped())
+			}
+
+			close(writer.release)
+			sink.Close()
+			if strings.Join(writer.lines, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, writer.lines)
+			}
+		})
+	}
+}
+
+func TestRemoteSink_Block(t *testing.T) {
+	writer := &blockingWriter{release: make(chan struct{})}
+	sink := newRemoteSink(writer, RemoteConfig{BufferSize: 1, BatchSize: 1, Backpressure: Block})
+
+	done := make(chan struct{})
+	go func() {
+		for _, line := range []string{"0", "1", "2"} {
+			sink.Write([]byte(line))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("Expected Write to block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(writer.release)
+	<-done
+	sink.Close()
+	if len(writer.lines) != 3 || sink.Dropped() != 0 {
+		t.Errorf("Expected every entry to be sent, got %v", writer.lines)
+	}
+	if _, err := sink.Write([]byte("late")); !errors.Is(err, ErrSinkClosed) {
+		t.Errorf("Expected ErrSinkClosed, got %v", err)
+	}
+}
+
+func TestNetworkSink_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			received <- scanner.Text()
+		}
+	}()
+
+	sink, err := zapSink(t, "tcp://"+listener.Addr().String()+"?batch_size=10")
+	if err != nil {
+		t.Fatalf("Failed to open sink: %v", err)
+	}
+	sink.Write([]byte(`{"message":"one"}` + "\n"))
+	sink.Write([]byte(`{"message":"two"}` + "\n"))
+	sink.Sync()
+
+	for _, expected := range []string{`{"message":"one"}`, `{"message":"two"}`} {
+		select {
+		case line := <-received:
+			if line != expected {
+				t.Errorf("Expected %s, got %s", expected, line)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timed out waiting for line")
+		}
+	}
+}
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket failed: %v", err)
+	}
+	defer conn.Close()
+
+	sink, err := zapSink(t, "syslog://"+conn.LocalAddr().String()+"?facility=local0&tag=orders")
+	if err != nil {
+		t.Fatalf("Failed to open sink: %v", err)
+	}
+	sink.Write([]byte(`{"level":"error","message":"boom"}` + "\n"))
+	sink.Sync()
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	message := string(buf[:n])
+	// local0 (16) * 8 + error (3)
+	if !strings.HasPrefix(message, "<131>1 ") || !strings.Contains(message, " orders ") || !strings.HasSuffix(message, `"message":"boom"}`+"\n") {
+		t.Errorf("Unexpected syslog message %q", message)
+	}
+}
+
+func TestLokiSink(t *testing.T) {
+	var push lokiPush
+	var tenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != lokiPushPath {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		tenant = r.Header.Get("X-Scope-OrgID")
+		json.NewDecoder(r.Body).Decode(&push)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	sink, err := zapSink(t, "loki://"+host+"?labels=app:orders,env:test&tenant=team-a")
+	if err != nil {
+		t.Fatalf("Failed to open sink: %v", err)
+	}
+	sink.Write([]byte(`{"message":"one"}` + "\n"))
+	sink.Sync()
+
+	if tenant != "team-a" || len(push.Streams) != 1 {
+		t.Fatalf("Unexpected push %+v (tenant %q)", push, tenant)
+	}
+	stream := push.Streams[0]
+	if stream.Stream["app"] != "orders" || stream.Stream["env"] != "test" {
+		t.Errorf("Unexpected labels %v", stream.Stream)
+	}
+	if len(stream.Values) != 1 || stream.Values[0][1] != `{"message":"one"}` {
+		t.Errorf("Unexpected values %v", stream.Values)
+	}
+}
+
+func TestLokiSink_FailureCounted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	var reported error
+	sink, err := NewLokiSink(LokiConfig{
+		URL:    server.URL + lokiPushPath,
+		Labels: map[string]string{"app": "orders"},
+		Remote: RemoteConfig{OnError: func(err error) { reported = err }},
+	})
+	if err != nil {
+		t.Fatalf("NewLokiSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	sink.Write([]byte("line\n"))
+	sink.Sync()
+	if sink.Failed() != 1 || reported == nil || !strings.Contains(reported.Error(), "429") {
+		t.Errorf("Expected failed push to be reported, got %d %v", sink.Failed(), reported)
+	}
+}
+
+func TestRemoteSink_InvalidURL(t *testing.T) {
+	for _, rawURL := range []string{
+		"tcp://127.0.0.1:1?backpressure=spill",
+		"syslog://127.0.0.1:514?facility=nope",
+		"loki://127.0.0.1:3100",
+		"udp://127.0.0.1:1?buffer_size=lots",
+	} {
+		if _, err := zapSink(t, rawURL); err == nil {
+			t.Errorf("Expected %s to fail", rawURL)
+		}
+	}
+}
+
+// zapSink opens rawURL through zap's sink registry
+func zapSink(t *testing.T, rawURL string) (zapcore.WriteSyncer, error) {
+	t.Helper()
+	sink, closeSink, err := zap.Open(rawURL)
+	if err == nil {
+		t.Cleanup(closeSink)
+	}
+	return sink, err
+}
+
+// waitFor polls condition until it holds or a second passes
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"errors"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Default error report rate limit, applied unless WithReportRateLimit is used
+const (
+	DefaultReportLimit    = 100
+	DefaultReportInterval = time.Minute
+)
+
+// ErrorReporter forwards error entries to a service such as Sentry or
+// Rollbar. Report receives entries at error level and above, with the stack
+// trace in entry.Stack and the entry's and logger's fields in fields,
+// including "environment" when one is configured. The stack is taken from an
+// error field with a StackTrace() string method, such as errors.CodedError,
+// when there is one. Report is called while
+// logging, so it should hand the event to an asynchronous client rather
+// than block on the network. A reporter that also implements
+// interface{ Sync() error } is flushed by Sync.
+type ErrorReporter interface {
+	Report(entry zapcore.Entry, fields map[string]interface{}) error
+}
+
+// ErrorReporterFunc adapts a function to ErrorReporter
+type ErrorReporterFunc func(entry zapcore.Entry, fields map[string]interface{}) error
+
+// Report calls f
+func (f ErrorReporterFunc) Report(entry zapcore.Entry, fields map[string]interface{}) error {
+	return f(entry, fields)
+}
+
+// reportCore is a zapcore.Core that sends error entries to an ErrorReporter
+type reportCore struct {
+	reporter    ErrorReporter
+	environment string
+	limiter     *reportLimiter
+	fields      []zapcore.Field
+}
+
+// newReportCore creates a core reporting at most limit entries per interval;
+// a limit of zero or less disables rate limiting
+func newReportCore(reporter ErrorReporter, environment string, limit int, interval time.Duration) *reportCore {
+	return &reportCore{
+		reporter:    reporter,
+		environment: environment,
+		limiter:     &reportLimiter{limit: limit, interval: interval},
+	}
+}
+
+// Enabled reports whether level is error or above
+func (c *reportCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.ErrorLevel
+}
+
+// With returns a core that also reports fields
+func (c *reportCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return &clone
+}
+
+// Check adds the core for enabled entries
+func (c *reportCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write reports the entry unless the rate limit is exhausted
+func (c *reportCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if !c.limiter.allow(entry.Time) {
+		return nil
+	}
+
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(encoder)
+	}
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	if c.environment != "" {
+		encoder.Fields["environment"] = c.environment
+	}
+	if entry.Stack == "" {
+		entry.Stack = errorStack(fields)
+	}
+	if entry.Stack == "" {
+		entry.Stack = string(debug.Stack())
+	}
+	return c.reporter.Report(entry, encoder.Fields)
+}
+
+// Sync flushes the reporter when it supports it
+func (c *reportCore) Sync() error {
+	if syncer, ok := c.reporter.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
+
+// errorStack returns the stack trace of the first error field whose error
+// records where it was created, such as errors.CodedError
+func errorStack(fields []zapcore.Field) string {
+	for _, field := range fields {
+		err, ok := field.Interface.(error)
+		if field.Type != zapcore.ErrorType || !ok {
+			continue
+		}
+		var traced interface{ StackTrace() string }
+		if errors.As(err, &traced) {
+			return traced.StackTrace()
+		}
+	}
+	return ""
+}
+
+// reportLimiter allows limit reports per fixed interval
+type reportLimiter struct {
+	limit    int
+	interval time.Duration
+
+	mu          sync.Mutex
+	windowStart time.Time
+	count       int
+}
+
+// allow reports whether another report fits in the window containing now
+func (l *reportLimiter) allow(now time.Time) bool {
+	if l.limit <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.windowStart) >= l.interval {
+		l.windowStart = now
+		l.count = 0
+	}
+	if l.count >= l.limit {
+		return false
+	}
+	l.count++
+	return true
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type recordingReporter struct {
+	mu      sync.Mutex
+	entries []zapcore.Entry
+	fields  []map[string]interface{}
+}
+
+func (r *recordingReporter) Report(entry zapcore.Entry, fields map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	r.fields = append(r.fields, fields)
+	return nil
+}
+
+func TestWithErrorReporter(t *testing.T) {
+	reporter := &recordingReporter{}
+	_, err := Init(
+		WithEnvironment("production"),
+		WithOutputs(filepath.Join(t.TempDir(), "app.log")),
+		WithErrorReporter(reporter),
+		WithReportRateLimit(2, time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	Warn("not reported")
+	Logger.With(zap.String("order_id", "o-1")).Error("charge failed", zap.Int("attempt", 3))
+	Error("second")
+	Error("rate limited")
+
+	if len(reporter.entries) != 2 {
+		t.Fatalf("Expected 2 reports, got %d", len(reporter.entries))
+	}
+	entry, fields := reporter.entries[0], reporter.fields[0]
+	if entry.Message != "charge failed" || entry.Stack == "" {
+		t.Errorf("Expected message and stack trace, got %+v", entry)
+	}
+	if fields["order_id"] != "o-1" || fields["attempt"] != int64(3) || fields["environment"] != "production" {
+		t.Errorf("Unexpected report fields %v", fields)
+	}
+}
+
+// tracedError records a stack like errors.CodedError
+type tracedError struct{}
+
+func (tracedError) Error() string      { return "traced" }
+func (tracedError) StackTrace() string { return "origin\n\tfile.go:1\n" }
+
+func TestErrorStack(t *testing.T) {
+	fields := []zapcore.Field{zap.String("id", "1"), zap.Error(fmt.Errorf("wrapped: %w", tracedError{}))}
+	if stack := errorStack(fields); stack != "origin\n\tfile.go:1\n" {
+		t.Errorf("Expected the error's stack, got %q", stack)
+	}
+	if stack := errorStack([]zapcore.Field{zap.Error(errors.New("plain"))}); stack != "" {
+		t.Errorf("Expected no stack for a plain error, got %q", stack)
+	}
+}
+
+func TestReportLimiter(t *testing.T) {
+	limiter := &reportLimiter{limit: 1, interval: time.Minute}
+	start := time.Now()
+	if !limiter.allow(start) || limiter.allow(start.Add(time.Second)) {
+		t.Error("Expected one report per window")
+	}
+	if !limiter.allow(start.Add(time.Minute)) {
+		t.Error("Expected a new window to allow reports")
+	}
+	if unlimited := (&reportLimiter{limit: -1}); !unlimited.allow(start) || !unlimited.allow(start) {
+		t.Error("Expected negative limit to disable rate limiting")
+	}
+}
@@ -0,0 +1,315 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RotateScheme is the zap sink scheme for rotating log files, usable in
+// zap.Config OutputPaths, e.g.
+// "rotate:///var/log/app.log?max_size_mb=100&max_backups=5&max_age=168h&compress=true"
+const RotateScheme = "rotate"
+
+// backupTimeFormat is the timestamp added to rotated file names
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// defaultMaxSize is the rotation size used when RotationConfig.MaxSize is zero
+const defaultMaxSize = 100 << 20
+
+func init() {
+	if err := zap.RegisterSink(RotateScheme, newRotateSink); err != nil {
+		panic("Failed to register rotate sink: " + err.Error())
+	}
+}
+
+// RotationConfig configures a RotatingFile
+type RotationConfig struct {
+	Filename   string        // Path of the active log file
+	MaxSize    int64         // Size in bytes that triggers rotation; 100 MiB when zero
+	MaxBackups int           // Rotated files to keep; zero keeps all
+	MaxAge     time.Duration // Rotated files older than this are removed; zero keeps all
+	Compress   bool          // Gzip rotated files
+}
+
+// RotatingFile is a log file that is renamed with a timestamp suffix, e.g.
+// app-2024-01-02T15-04-05.000.log, once it reaches MaxSize. Old rotated
+// files are compressed and removed in the background according to the
+// config. It is safe for concurrent use and implements zapcore.WriteSyncer.
+type RotatingFile struct {
+	config RotationConfig
+	now    func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	millMu sync.Mutex
+	millWG sync.WaitGroup
+}
+
+// NewRotatingFile opens or creates the log file, creating its directory
+func NewRotatingFile(config RotationConfig) (*RotatingFile, error) {
+	if config.Filename == "" {
+		return nil, fmt.Errorf("rotating log file requires a filename")
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = defaultMaxSize
+	}
+
+	r := &RotatingFile{config: config, now: time.Now}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write appends p to the log file, rotating first when p would push the
+// file past MaxSize
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.config.MaxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Sync flushes the log file to disk
+func (r *RotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Sync()
+}
+
+// Close closes the log file and waits for background cleanup to finish
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	r.mu.Unlock()
+
+	r.millWG.Wait()
+	return err
+}
+
+// Rotate closes the current file, renames it with a timestamp, and opens a
+// new one, e.g. in response to SIGHUP
+func (r *RotatingFile) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rotate()
+}
+
+// open opens the log file for appending
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.config.Filename), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(r.config.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file and opens a new one; r.mu must be held
+func (r *RotatingFile) rotate() error {
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			return fmt.Errorf("failed to close log file: %w", err)
+		}
+		r.file = nil
+	}
+
+	now := r.now()
+	if err := os.Rename(r.config.Filename, r.backupName(now)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.millWG.Add(1)
+	go func() {
+		defer r.millWG.Done()
+		r.mill(now)
+	}()
+	return nil
+}
+
+// backupName returns the rotated file name for t
+func (r *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := r.nameParts()
+	return filepath.Join(dir, prefix+t.UTC().Format(backupTimeFormat)+ext)
+}
+
+// nameParts splits the log file name into directory, backup prefix, and
+// extension, e.g. "/var/log", "app-", ".log"
+func (r *RotatingFile) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(r.config.Filename)
+	base := filepath.Base(r.config.Filename)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// backupFile is a rotated log file
+type backupFile struct {
+	path       string
+	rotatedAt  time.Time
+	compressed bool
+}
+
+// mill compresses and removes rotated files according to the config, with
+// ages measured from now
+func (r *RotatingFile) mill(now time.Time) {
+	r.millMu.Lock()
+	defer r.millMu.Unlock()
+
+	backups, err := r.backups()
+	if err != nil {
+		return
+	}
+
+	cutoff := now.Add(-r.config.MaxAge)
+	for i, backup := range backups {
+		expired := r.config.MaxAge > 0 && backup.rotatedAt.Before(cutoff)
+		if expired || (r.config.MaxBackups > 0 && i >= r.config.MaxBackups) {
+			os.Remove(backup.path)
+			continue
+		}
+		if r.config.Compress && !backup.compressed {
+			compressFile(backup.path)
+		}
+	}
+}
+
+// backups returns the rotated files, newest first
+func (r *RotatingFile) backups() ([]backupFile, error) {
+	dir, prefix, ext := r.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backupFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		compressed := strings.HasSuffix(stamp, ext+".gz")
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		rotatedAt, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, name), rotatedAt: rotatedAt, compressed: compressed})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].rotatedAt.After(backups[j].rotatedAt)
+	})
+	return backups, nil
+}
+
+// compressFile gzips path to path.gz and removes the original
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// newRotateSink builds a RotatingFile from a rotate:// URL
+func newRotateSink(u *url.URL) (zap.Sink, error) {
+	config := RotationConfig{Filename: u.Path}
+	query := u.Query()
+
+	if value := query.Get("max_size_mb"); value != "" {
+		megabytes, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_size_mb %q: %w", value, err)
+		}
+		config.MaxSize = megabytes << 20
+	}
+	if value := query.Get("max_backups"); value != "" {
+		backups, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_backups %q: %w", value, err)
+		}
+		config.MaxBackups = backups
+	}
+	if value := query.Get("max_age"); value != "" {
+		age, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_age %q: %w", value, err)
+		}
+		config.MaxAge = age
+	}
+	if value := query.Get("compress"); value != "" {
+		compress, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid compress %q: %w", value, err)
+		}
+		config.Compress = compress
+	}
+
+	return NewRotatingFile(config)
+}
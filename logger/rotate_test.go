@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRotatingFile_RotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewRotatingFile(RotationConfig{Filename: filepath.Join(dir, "app.log"), MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewRotatingFile failed: %v", err)
+	}
+	clock := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	expected := []string{"app-2024-01-02T03-04-07.000.log", "app-2024-01-02T03-04-08.000.log", "app.log"}
+	if names := listDir(t, dir); strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "app.log")); string(data) != "fourth\n" {
+		t.Errorf("Expected active file to hold the last line, got %q", data)
+	}
+}
+
+func TestRotatingFile_CompressAndMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "app-2020-01-01T00-00-00.000.log")
+	if err := os.WriteFile(old, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRotatingFile(RotationConfig{Filename: filepath.Join(dir, "app.log"), MaxAge: 24 * time.Hour, Compress: true})
+	if err != nil {
+		t.Fatalf("NewRotatingFile failed: %v", err)
+	}
+	r.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	r.Write([]byte("current\n"))
+	if err := r.Rotate(); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	r.Close()
+
+	expected := []string{"app-2024-01-02T03-04-05.000.log.gz", "app.log"}
+	if names := listDir(t, dir); strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected %v, got %v", expected, names)
+	}
+
+	file, err := os.Open(filepath.Join(dir, expected[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Expected gzip file: %v", err)
+	}
+	if data, _ := io.ReadAll(gz); string(data) != "current\n" {
+		t.Errorf("Unexpected compressed content %q", data)
+	}
+}
+
+func TestRotateSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	cfg := NewProductionConfig()
+	cfg.OutputPaths = []string{RotateScheme + "://" + path + "?max_size_mb=1&max_backups=3&compress=true"}
+
+	if err := InitLoggerWithConfig(cfg); err != nil {
+		t.Fatalf("InitLoggerWithConfig failed: %v", err)
+	}
+	Info("rotated")
+	_ = Sync()
+
+	if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), `"message":"rotated"`) {
+		t.Errorf("Expected log line in %s, got %q, %v", path, data, err)
+	}
+
+	cfg.OutputPaths = []string{RotateScheme + "://" + path + "?max_backups=lots"}
+	if err := InitLoggerWithConfig(cfg); err == nil {
+		t.Error("Expected invalid sink option to fail")
+	}
+}
@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewSlogHandler returns a slog.Handler that writes through the package
+// logger, so libraries using log/slog share its outputs, format, level, and
+// the correlation and trace fields FromContext adds. Records go to the
+// logger in the context passed to slog, or the global Logger when there is
+// none, as it is at the time of logging.
+//
+// Example:
+//
+//	slog.SetDefault(slog.New(logger.NewSlogHandler()))
+//	slog.InfoContext(ctx, "cache warmed", "entries", 512)
+func NewSlogHandler() slog.Handler {
+	return &slogHandler{}
+}
+
+// slogHandler adapts zap to slog.Handler. Groups are opened lazily, so a
+// group without attributes is left out of the output.
+type slogHandler struct {
+	fields []zap.Field // Fields from WithAttrs, with zap.Namespace for groups
+	groups []string    // Groups opened since the last WithAttrs
+}
+
+// Enabled reports whether the logger in ctx logs at level
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return contextLogger(ctx).Core().Enabled(zapLevel(level))
+}
+
+// Handle writes record with the handler's and record's attributes
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	logger := FromContext(ctx)
+
+	// Caller and time come from the record rather than this frame
+	checked := logger.WithOptions(zap.WithCaller(false)).Check(zapLevel(record.Level), record.Message)
+	if checked == nil {
+		return nil
+	}
+	checked.Entry.Time = record.Time
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		checked.Entry.Caller = zapcore.EntryCaller{Defined: true, PC: frame.PC, File: frame.File, Line: frame.Line, Function: frame.Function}
+	}
+
+	fields := append([]zap.Field(nil), h.fields...)
+	if record.NumAttrs() > 0 {
+		var attrs []zap.Field
+		record.Attrs(func(attr slog.Attr) bool {
+			attrs = appendAttr(attrs, attr)
+			return true
+		})
+		if len(attrs) > 0 {
+			fields = appendGroups(fields, h.groups)
+			fields = append(fields, attrs...)
+		}
+	}
+	checked.Write(fields...)
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to every record
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []zap.Field
+	for _, attr := range attrs {
+		fields = appendAttr(fields, attr)
+	}
+	if len(fields) == 0 {
+		return h
+	}
+	combined := appendGroups(append([]zap.Field(nil), h.fields...), h.groups)
+	return &slogHandler{fields: append(combined, fields...)}
+}
+
+// WithGroup returns a handler that nests later attributes under name
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := append(append([]string(nil), h.groups...), name)
+	return &slogHandler{fields: h.fields, groups: groups}
+}
+
+// contextLogger returns the logger in ctx, without correlation fields, or
+// the global Logger
+func contextLogger(ctx context.Context) *zap.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey).(*zap.Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return global()
+}
+
+// zapLevel maps a slog level to the nearest zap level at or below it
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case level >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case level >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+// appendGroups opens a namespace for each group
+func appendGroups(fields []zap.Field, groups []string) []zap.Field {
+	for _, group := range groups {
+		fields = append(fields, zap.Namespace(group))
+	}
+	return fields
+}
+
+// appendAttr converts attr to zap fields, skipping empty attributes and
+// inlining groups without a key
+func appendAttr(fields []zap.Field, attr slog.Attr) []zap.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	value := attr.Value
+	switch value.Kind() {
+	case slog.KindGroup:
+		var group []zap.Field
+		for _, nested := range value.Group() {
+			group = appendAttr(group, nested)
+		}
+		if len(group) == 0 {
+			return fields
+		}
+		if attr.Key == "" {
+			return append(fields, group...)
+		}
+		return append(fields, zap.Object(attr.Key, zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			for _, field := range group {
+				field.AddTo(enc)
+			}
+			return nil
+		})))
+	case slog.KindString:
+		return append(fields, zap.String(attr.Key, value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(attr.Key, value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(attr.Key, value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(attr.Key, value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(attr.Key, value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(attr.Key, value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(attr.Key, value.Time()))
+	default:
+		if err, ok := value.Any().(error); ok {
+			return append(fields, zap.NamedError(attr.Key, err))
+		}
+		return append(fields, zap.Any(attr.Key, value.Any()))
+	}
+}
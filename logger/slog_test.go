@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// jsonGlobal installs a global JSON logger writing to the returned buffer,
+// using slog's key names
+func jsonGlobal(t *testing.T, level zapcore.Level) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	encoderCfg := baseEncoderConfig()
+	encoderCfg.TimeKey = slog.TimeKey
+	encoderCfg.LevelKey = slog.LevelKey
+	encoderCfg.MessageKey = slog.MessageKey
+	encoderCfg.EncodeLevel = zapcore.LowercaseLevelEncoder
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), zapcore.AddSync(&buf), level)
+
+	previous := Logger
+	Logger = zap.New(core, zap.AddCaller())
+	t.Cleanup(func() { Logger = previous })
+	return &buf
+}
+
+func TestSlogHandler_Conformance(t *testing.T) {
+	buf := jsonGlobal(t, zapcore.DebugLevel)
+
+	err := slogtest.TestHandler(NewSlogHandler(), func() []map[string]any {
+		var results []map[string]any
+		for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+			var entry map[string]any
+			if err := json.Unmarshal(line, &entry); err != nil {
+				t.Fatalf("Invalid JSON line %s: %v", line, err)
+			}
+			results = append(results, entry)
+		}
+		return results
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSlogHandler_LevelAndCorrelation(t *testing.T) {
+	buf := jsonGlobal(t, zapcore.InfoLevel)
+	log := slog.New(NewSlogHandler())
+
+	ctx := WithRequestID(context.Background(), "req-9")
+	log.DebugContext(ctx, "hidden")
+	log.WarnContext(ctx, "cache miss", "key", "user:7")
+
+	output := buf.String()
+	if strings.Contains(output, "hidden") {
+		t.Errorf("Expected debug to be filtered:\n%s", output)
+	}
+	for _, expected := range []string{`"level":"warn"`, `"request_id":"req-9"`, `"key":"user:7"`, `"caller":"logger/slog_test.go:`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s in output:\n%s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// sugar returns the global logger's SugaredLogger. The global logger already
+// skips one caller frame, so callers of the functions below are reported.
+func sugar() *zap.SugaredLogger {
+	return global().Sugar()
+}
+
+// Debugf logs a printf-style debug message using the global logger
+func Debugf(template string, args ...interface{}) {
+	sugar().Debugf(template, args...)
+}
+
+// Infof logs a printf-style info message using the global logger
+func Infof(template string, args ...interface{}) {
+	sugar().Infof(template, args...)
+}
+
+// Warnf logs a printf-style warning message using the global logger
+func Warnf(template string, args ...interface{}) {
+	sugar().Warnf(template, args...)
+}
+
+// Errorf logs a printf-style error message using the global logger
+func Errorf(template string, args ...interface{}) {
+	sugar().Errorf(template, args...)
+}
+
+// Fatalf logs a printf-style fatal message using the global logger and exits the program
+func Fatalf(template string, args ...interface{}) {
+	sugar().Fatalf(template, args...)
+}
+
+// Debugw logs a debug message with loosely typed key-value pairs, e.g.
+// Debugw("Cache miss", "key", key, "size", n)
+func Debugw(message string, keysAndValues ...interface{}) {
+	sugar().Debugw(message, keysAndValues...)
+}
+
+// Infow logs an info message with loosely typed key-value pairs
+func Infow(message string, keysAndValues ...interface{}) {
+	sugar().Infow(message, keysAndValues...)
+}
+
+// Warnw logs a warning message with loosely typed key-value pairs
+func Warnw(message string, keysAndValues ...interface{}) {
+	sugar().Warnw(message, keysAndValues...)
+}
+
+// Errorw logs an error message with loosely typed key-value pairs
+func Errorw(message string, keysAndValues ...interface{}) {
+	sugar().Errorw(message, keysAndValues...)
+}
+
+// Fatalw logs a fatal message with loosely typed key-value pairs and exits the program
+func Fatalw(message string, keysAndValues ...interface{}) {
+	sugar().Fatalw(message, keysAndValues...)
+}
+
+// SugarFromContext returns FromContext(ctx) as a SugaredLogger, for
+// printf-style logging with the request's correlation fields
+func SugarFromContext(ctx context.Context) *zap.SugaredLogger {
+	return FromContext(ctx).Sugar()
+}
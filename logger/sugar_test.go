@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSugaredHelpers(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	previous := Logger
+	Logger = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+	t.Cleanup(func() { Logger = previous })
+
+	Infof("user %d logged in", 7)
+	Warnw("Slow query", "table", "orders", "ms", 250)
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Message != "user 7 logged in" {
+		t.Errorf("Unexpected message %q", entries[0].Message)
+	}
+	if fields := entries[1].ContextMap(); fields["table"] != "orders" || fields["ms"] != int64(250) {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+	for _, entry := range entries {
+		if !strings.HasSuffix(filepath.Base(entry.Caller.File), "sugar_test.go") {
+			t.Errorf("Expected caller in sugar_test.go, got %s", entry.Caller.File)
+		}
+	}
+
+	SugarFromContext(WithRequestID(context.Background(), "req-1")).Errorf("failed: %v", "boom")
+	if fields := logs.All()[2].ContextMap(); fields["request_id"] != "req-1" {
+		t.Errorf("Expected request_id from context, got %v", fields)
+	}
+}
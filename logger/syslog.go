@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SyslogScheme is the zap sink scheme for a syslog server, e.g.
+// "syslog://logs.internal:514?network=tcp&facility=local0&tag=orders"
+const SyslogScheme = "syslog"
+
+// syslogFacilities maps facility names to their RFC 5424 codes
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogSeverities maps log levels to RFC 5424 severities
+var syslogSeverities = map[string]int{
+	"debug": 7, "info": 6, "warn": 4, "error": 3, "dpanic": 2, "panic": 2, "fatal": 2,
+}
+
+// SyslogConfig configures a syslog sink
+type SyslogConfig struct {
+	Network  string // "udp" or "tcp"; udp when empty
+	Address  string // host:port of the syslog server
+	Facility string // e.g. "local0"; "user" when empty
+	Tag      string // APP-NAME in each message; the executable name when empty
+	Remote   RemoteConfig
+}
+
+// NewSyslogSink ships log lines to a syslog server as RFC 5424 messages.
+// The severity comes from the "level" field of JSON lines and is info for
+// other encodings.
+func NewSyslogSink(config SyslogConfig) (*RemoteSink, error) {
+	if config.Network == "" {
+		config.Network = "udp"
+	}
+	if config.Network != "tcp" && config.Network != "udp" {
+		return nil, fmt.Errorf("unsupported syslog network %q", config.Network)
+	}
+	if config.Address == "" {
+		return nil, fmt.Errorf("syslog sink requires an address")
+	}
+	if config.Facility == "" {
+		config.Facility = "user"
+	}
+	facility, ok := syslogFacilities[config.Facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", config.Facility)
+	}
+	if config.Tag == "" {
+		config.Tag = filepath.Base(os.Args[0])
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	format := func(entry remoteEntry) []byte {
+		message := bytes.TrimRight(entry.line, "\n")
+		header := fmt.Sprintf("<%d>1 %s %s %s %d - - ",
+			facility*8+syslogSeverity(message),
+			entry.at.UTC().Format(time.RFC3339Nano),
+			hostname, config.Tag, os.Getpid())
+		return append(append([]byte(header), message...), '\n')
+	}
+
+	writer := &netWriter{
+		network: config.Network,
+		address: config.Address,
+		timeout: writeTimeout(config.Remote),
+		format:  format,
+	}
+	return newRemoteSink(writer, config.Remote), nil
+}
+
+// syslogSeverity reads the level of a JSON log line, defaulting to info
+func syslogSeverity(line []byte) int {
+	const key = `"level":"`
+	start := bytes.Index(line, []byte(key))
+	if start < 0 {
+		return syslogSeverities["info"]
+	}
+	rest := line[start+len(key):]
+	end := bytes.IndexByte(rest, '"')
+	if end < 0 {
+		return syslogSeverities["info"]
+	}
+	if severity, ok := syslogSeverities[string(bytes.ToLower(rest[:end]))]; ok {
+		return severity
+	}
+	return syslogSeverities["info"]
+}
+
+// newSyslogSink creates a syslog sink from a syslog:// URL with optional
+// network, facility, and tag parameters
+func newSyslogSink(u *url.URL) (zap.Sink, error) {
+	remote, err := parseRemoteConfig(u.Query())
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	return NewSyslogSink(SyslogConfig{
+		Network:  query.Get("network"),
+		Address:  u.Host,
+		Facility: query.Get("facility"),
+		Tag:      query.Get("tag"),
+		Remote:   remote,
+	})
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Output is a destination with its own encoding and level, written to in
+// addition to the main outputs
+type Output struct {
+	Encoding string   // EncodingJSON or EncodingConsole
+	Paths    []string // e.g. "stderr", a file path, or a RotateScheme URL
+	Level    string   // Minimum level for this output, on top of the global level; all levels when empty
+}
+
+// WithAdditionalOutputs writes every entry to outputs as well, each with
+// its own encoding. JSON outputs use the production encoder settings and
+// console outputs the colored development ones.
+//
+// Example:
+//
+//	logger.Init(
+//		logger.WithEncoding(logger.EncodingConsole),
+//		logger.WithAdditionalOutputs(logger.Output{
+//			Encoding: logger.EncodingJSON,
+//			Paths:    []string{"rotate:///var/log/app.json"},
+//			Level:    "info",
+//		}),
+//	)
+func WithAdditionalOutputs(outputs ...Output) Option {
+	return func(config *Config) {
+		config.AdditionalOutputs = append(config.AdditionalOutputs, outputs...)
+	}
+}
+
+// newOutputCore opens output and returns a core writing to it
+func newOutputCore(output Output, sampling *zap.SamplingConfig) (zapcore.Core, error) {
+	if len(output.Paths) == 0 {
+		return nil, fmt.Errorf("output requires at least one path")
+	}
+
+	var encoder zapcore.Encoder
+	switch output.Encoding {
+	case EncodingJSON:
+		encoder = zapcore.NewJSONEncoder(NewProductionConfig().EncoderConfig)
+	case EncodingConsole:
+		encoder = zapcore.NewConsoleEncoder(NewDevelopmentConfig().EncoderConfig)
+	default:
+		return nil, fmt.Errorf("invalid output encoding %q", output.Encoding)
+	}
+
+	var level zapcore.LevelEnabler = zapcore.DebugLevel
+	if output.Level != "" {
+		parsed, err := zapcore.ParseLevel(output.Level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid output level %q: %w", output.Level, err)
+		}
+		level = parsed
+	}
+
+	sink, _, err := zap.Open(output.Paths...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output %v: %w", output.Paths, err)
+	}
+
+	core := zapcore.NewCore(encoder, sink, level)
+	if sampling != nil {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, sampling.Initial, sampling.Thereafter)
+	}
+	return core, nil
+}
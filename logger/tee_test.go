@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestWithAdditionalOutputs(t *testing.T) {
+	dir := t.TempDir()
+	consolePath := filepath.Join(dir, "console.log")
+	jsonPath := filepath.Join(dir, "app.json")
+
+	_, err := Init(
+		WithLevel("debug"),
+		WithEncoding(EncodingConsole),
+		WithOutputs(consolePath),
+		WithRedaction(),
+		WithAdditionalOutputs(Output{Encoding: EncodingJSON, Paths: []string{jsonPath}, Level: "warn"}),
+	)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	Debug("cache warmed")
+	Warn("slow query", zap.String("password", "hunter2"))
+	Logger.Sync()
+
+	console, _ := os.ReadFile(consolePath)
+	if !strings.Contains(string(console), "cache warmed") || !strings.Contains(string(console), " | ") {
+		t.Errorf("Expected console-encoded debug entry, got:\n%s", console)
+	}
+
+	data, _ := os.ReadFile(jsonPath)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected only the warn entry in the JSON output, got:\n%s", data)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Expected JSON output, got %s", lines[0])
+	}
+	if entry["message"] != "slow query" || entry["level"] != "warn" || entry["password"] != "REDACTED" {
+		t.Errorf("Unexpected JSON entry %v", entry)
+	}
+}
+
+func TestWithAdditionalOutputs_Invalid(t *testing.T) {
+	for _, output := range []Output{
+		{Encoding: "xml", Paths: []string{"stdout"}},
+		{Encoding: EncodingJSON},
+		{Encoding: EncodingJSON, Paths: []string{"stdout"}, Level: "loud"},
+	} {
+		if _, err := Init(WithAdditionalOutputs(output)); err == nil {
+			t.Errorf("Expected %+v to fail", output)
+		}
+	}
+}
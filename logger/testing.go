@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestLogger captures log entries in memory for assertions in tests
+type TestLogger struct {
+	*zap.Logger
+	t    testing.TB
+	logs *observer.ObservedLogs
+}
+
+// NewTestLogger installs a logger that captures every entry, at every
+// level, as the global Logger for the duration of the test, so code calling
+// logger.Info or FromContext neither panics nor writes files. The previous
+// global Logger is restored when the test finishes.
+//
+// Example:
+//
+//	log := logger.NewTestLogger(t)
+//	service.Charge(ctx, order)
+//	log.AssertLogged(zapcore.InfoLevel, "charged", zap.String("order_id", "o-1"))
+func NewTestLogger(t testing.TB) *TestLogger {
+	t.Helper()
+	core, logs := observer.New(zapcore.DebugLevel)
+	testLogger := &TestLogger{Logger: zap.New(core), t: t, logs: logs}
+
+	previous := Logger
+	Logger = testLogger.Logger
+	t.Cleanup(func() { Logger = previous })
+	return testLogger
+}
+
+// Entries returns the captured entries in order
+func (l *TestLogger) Entries() []observer.LoggedEntry {
+	return l.logs.All()
+}
+
+// Reset discards the captured entries
+func (l *TestLogger) Reset() {
+	l.logs.TakeAll()
+}
+
+// AssertLogged fails the test unless an entry at level has a message
+// containing msgContains and carries every field in fields, including
+// fields added with With
+func (l *TestLogger) AssertLogged(level zapcore.Level, msgContains string, fields ...zap.Field) {
+	l.t.Helper()
+	if !l.logged(level, msgContains, fields) {
+		l.t.Errorf("Expected a %s entry containing %q with fields %s, got:\n%s", level, msgContains, describeFields(fields), l.describe())
+	}
+}
+
+// AssertNotLogged fails the test if an entry at level has a message
+// containing msgContains
+func (l *TestLogger) AssertNotLogged(level zapcore.Level, msgContains string) {
+	l.t.Helper()
+	if l.logged(level, msgContains, nil) {
+		l.t.Errorf("Expected no %s entry containing %q, got:\n%s", level, msgContains, l.describe())
+	}
+}
+
+// logged reports whether a matching entry was captured
+func (l *TestLogger) logged(level zapcore.Level, msgContains string, fields []zap.Field) bool {
+	for _, entry := range l.logs.All() {
+		if entry.Level == level && strings.Contains(entry.Message, msgContains) && hasFields(entry.Context, fields) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFields reports whether context contains every field in fields
+func hasFields(context, fields []zap.Field) bool {
+	for _, field := range fields {
+		found := false
+		for _, candidate := range context {
+			if candidate.Equals(field) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// describe lists the captured entries for failure messages
+func (l *TestLogger) describe() string {
+	entries := l.logs.All()
+	if len(entries) == 0 {
+		return "  (no entries)"
+	}
+	var b strings.Builder
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "  %s %q %s\n", entry.Level, entry.Message, describeFields(entry.Context))
+	}
+	return b.String()
+}
+
+// describeFields formats fields as a map for failure messages
+func describeFields(fields []zap.Field) string {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	return fmt.Sprint(encoder.Fields)
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// recordingTB captures assertion failures instead of failing the test
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestNewTestLogger(t *testing.T) {
+	log := NewTestLogger(t)
+
+	Info("Order placed", zap.String("order_id", "o-1"), zap.Int("items", 3))
+	FromContext(WithRequestID(context.Background(), "req-1")).Warn("Stock low")
+
+	log.AssertLogged(zapcore.InfoLevel, "placed", zap.String("order_id", "o-1"), zap.Int("items", 3))
+	log.AssertLogged(zapcore.WarnLevel, "Stock", zap.String("request_id", "req-1"))
+	log.AssertNotLogged(zapcore.ErrorLevel, "")
+	if len(log.Entries()) != 2 {
+		t.Errorf("Expected 2 entries, got %d", len(log.Entries()))
+	}
+
+	log.Reset()
+	if len(log.Entries()) != 0 {
+		t.Error("Expected Reset to discard entries")
+	}
+}
+
+func TestTestLogger_AssertionFailures(t *testing.T) {
+	recorder := &recordingTB{TB: t}
+	log := NewTestLogger(recorder)
+	Info("Order placed", zap.String("order_id", "o-1"))
+
+	log.AssertLogged(zapcore.InfoLevel, "placed", zap.String("order_id", "o-2"))
+	log.AssertLogged(zapcore.ErrorLevel, "placed")
+	log.AssertNotLogged(zapcore.InfoLevel, "Order")
+	if len(recorder.failures) != 3 {
+		t.Errorf("Expected 3 failures, got %v", recorder.failures)
+	}
+}
+
+func TestNewTestLogger_RestoresGlobal(t *testing.T) {
+	previous := Logger
+	t.Run("inner", func(t *testing.T) {
+		NewTestLogger(t)
+		if Logger == previous {
+			t.Error("Expected the test logger to be installed")
+		}
+	})
+	if Logger != previous {
+		t.Error("Expected the previous Logger to be restored")
+	}
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// TraceExtractor returns log fields identifying the span active in ctx, or
+// none when there is no span
+type TraceExtractor func(ctx context.Context) []zap.Field
+
+var (
+	traceMu         sync.RWMutex
+	traceExtractors = []TraceExtractor{OpenTelemetryTraceFields}
+)
+
+// RegisterTraceExtractor adds a source of trace fields that FromContext
+// attaches to every entry. OpenTelemetry is registered by default; import
+// github.com/khekrn/core/logger/datadog to add Datadog.
+func RegisterTraceExtractor(extractor TraceExtractor) {
+	traceMu.Lock()
+	traceExtractors = append(traceExtractors, extractor)
+	traceMu.Unlock()
+}
+
+// OpenTelemetryTraceFields returns trace_id and span_id for the
+// OpenTelemetry span in ctx, as hex strings
+func OpenTelemetryTraceFields(ctx context.Context) []zap.Field {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("trace_id", spanContext.TraceID().String()),
+		zap.String("span_id", spanContext.SpanID().String()),
+	}
+}
+
+// traceFields returns the fields of every registered extractor
+func traceFields(ctx context.Context) []zap.Field {
+	traceMu.RLock()
+	defer traceMu.RUnlock()
+
+	var fields []zap.Field
+	for _, extractor := range traceExtractors {
+		fields = append(fields, extractor(ctx)...)
+	}
+	return fields
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type testTraceKey struct{}
+
+func TestFromContext_OpenTelemetryTraceFields(t *testing.T) {
+	logs := observeGlobal(t)
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+	FromContext(ctx).Info("traced")
+	FromContext(context.Background()).Info("untraced")
+
+	fields := logs.All()[0].ContextMap()
+	if fields["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || fields["span_id"] != "00f067aa0ba902b7" {
+		t.Errorf("Unexpected trace fields %v", fields)
+	}
+	if _, ok := logs.All()[1].ContextMap()["trace_id"]; ok {
+		t.Error("Expected no trace fields without a span")
+	}
+}
+
+func TestRegisterTraceExtractor(t *testing.T) {
+	logs := observeGlobal(t)
+	RegisterTraceExtractor(func(ctx context.Context) []zap.Field {
+		if id, ok := ctx.Value(testTraceKey{}).(string); ok {
+			return []zap.Field{zap.String("custom.trace_id", id)}
+		}
+		return nil
+	})
+
+	FromContext(context.WithValue(context.Background(), testTraceKey{}, "abc")).Info("traced")
+	if fields := logs.All()[0].ContextMap(); fields["custom.trace_id"] != "abc" {
+		t.Errorf("Expected custom trace field, got %v", fields)
+	}
+}
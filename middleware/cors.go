@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default CORS settings
+var (
+	DefaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	DefaultCORSHeaders = []string{"Content-Type", "Authorization", "X-Request-ID", "Idempotency-Key"}
+)
+
+// CORSConfig holds the cross-origin resource sharing policy
+type CORSConfig struct {
+	AllowedOrigins   []string      // Allowed origins: exact, "*" for any, or with a subdomain wildcard like "https://*.example.com"
+	AllowedMethods   []string      // Allowed methods (default: DefaultCORSMethods)
+	AllowedHeaders   []string      // Allowed request headers, "*" for any (default: DefaultCORSHeaders)
+	ExposedHeaders   []string      // Response headers readable by the browser
+	AllowCredentials bool          // Allow cookies and credentials; requires explicit origins or patterns, not "*"
+	MaxAge           time.Duration // How long browsers may cache preflight results
+}
+
+// CORS answers preflight requests and adds CORS headers for allowed origins.
+// Requests from other origins pass through without CORS headers, so browsers
+// block them.
+//
+// CORS panics when AllowCredentials is combined with the "*" origin, which
+// would give every website credentialed access.
+//
+// Example:
+//
+//	cors := middleware.CORS(middleware.CORSConfig{
+//		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.dev"},
+//		AllowCredentials: true,
+//		MaxAge:           time.Hour,
+//	})
+func CORS(config CORSConfig) Middleware {
+	if len(config.AllowedMethods) == 0 {
+		config.AllowedMethods = DefaultCORSMethods
+	}
+	if len(config.AllowedHeaders) == 0 {
+		config.AllowedHeaders = DefaultCORSHeaders
+	}
+	methods := strings.Join(config.AllowedMethods, ", ")
+	headers := strings.Join(config.AllowedHeaders, ", ")
+	anyHeader := slices.Contains(config.AllowedHeaders, "*")
+	anyOrigin := slices.Contains(config.AllowedOrigins, "*")
+	if anyOrigin && config.AllowCredentials {
+		panic("middleware: CORS cannot allow credentials for any origin; list the allowed origins instead")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			w.Header().Add("Vary", "Origin")
+
+			if origin == "" || !(anyOrigin || originAllowed(config.AllowedOrigins, origin)) {
+				if preflight {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if anyOrigin {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if config.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if len(config.ExposedHeaders) > 0 {
+					w.Header().Set("Access-Control-Expose-Headers", strings.Join(config.ExposedHeaders, ", "))
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			if anyHeader {
+				w.Header().Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+			} else {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+			if config.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(config.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// originAllowed reports whether origin matches one of the allowed origins
+func originAllowed(allowed []string, origin string) bool {
+	for _, pattern := range allowed {
+		if prefix, suffix, ok := strings.Cut(pattern, "*"); ok {
+			if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(pattern, origin) {
+			return true
+		}
+	}
+	return false
+}
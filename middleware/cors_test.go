@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+	handler := CORS(CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.dev"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name      string
+		method    string
+		origin    string
+		preflight bool
+		status    int
+		allowed   string
+	}{
+		{"simple", http.MethodGet, "https://app.example.com", false, http.StatusOK, "https://app.example.com"},
+		{"wildcard subdomain", http.MethodGet, "https://preview.example.dev", false, http.StatusOK, "https://preview.example.dev"},
+		{"other origin", http.MethodGet, "https://evil.example.com", false, http.StatusOK, ""},
+		{"no origin", http.MethodGet, "", false, http.StatusOK, ""},
+		{"preflight", http.MethodOptions, "https://app.example.com", true, http.StatusNoContent, "https://app.example.com"},
+		{"rejected preflight", http.MethodOptions, "https://evil.example.com", true, http.StatusNoContent, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/", nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		if tt.preflight {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status || rec.Header().Get("Access-Control-Allow-Origin") != tt.allowed {
+			t.Errorf("%s: unexpected %d %q", tt.name, rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+		}
+		if tt.allowed == "" {
+			continue
+		}
+		if rec.Header().Get("Access-Control-Allow-Credentials") != "true" {
+			t.Errorf("%s: expected credentials to be allowed", tt.name)
+		}
+		if tt.preflight && (rec.Header().Get("Access-Control-Max-Age") != "3600" || rec.Header().Get("Access-Control-Allow-Methods") == "") {
+			t.Errorf("%s: unexpected preflight headers %v", tt.name, rec.Header())
+		}
+		if !tt.preflight && rec.Header().Get("Access-Control-Expose-Headers") != "X-Request-ID" {
+			t.Errorf("%s: expected exposed headers", tt.name)
+		}
+	}
+}
+
+func TestCORS_AnyOrigin(t *testing.T) {
+	handler := CORS(CORSConfig{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"*"}})(http.NotFoundHandler())
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://anywhere.example")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	req.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" || rec.Header().Get("Access-Control-Allow-Headers") != "X-Custom" {
+		t.Errorf("Unexpected headers %v", rec.Header())
+	}
+}
+
+func TestCORS_RejectsCredentialsForAnyOrigin(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected CORS to panic for credentials with any origin")
+		}
+	}()
+	CORS(CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+}
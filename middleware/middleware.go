@@ -0,0 +1,168 @@
+// Package middleware provides composable net/http middleware for inbound
+// requests, the server-side counterpart of the client package.
+//
+// This package offers panic recovery, request ID propagation, access logging
+// through the logger package, CORS, timeouts, and body size limits. Errors
+// are written in the response package's format.
+//
+// Example usage:
+//
+//	handler := middleware.Chain(
+//		middleware.RequestID,
+//		middleware.AccessLog,
+//		middleware.Recover,
+//		middleware.CORS(middleware.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}),
+//		middleware.Timeout(10*time.Second),
+//		middleware.MaxBodySize(1<<20),
+//	)(mux)
+//
+//	http.ListenAndServe(":8080", handler)
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/khekrn/core/idgen"
+	"github.com/khekrn/core/logger"
+	"github.com/khekrn/core/response"
+	"go.uber.org/zap"
+)
+
+// Middleware wraps an http.Handler
+type Middleware func(http.Handler) http.Handler
+
+// Chain composes middlewares into one. The first middleware is the
+// outermost, so it sees the request first.
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// RequestID takes the request ID from the X-Request-ID header or generates
+// one, echoes it in the response, and stores it with logger.WithRequestID,
+// so the logger, response.WithContext, and the client's request ID
+// propagation all see it
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(logger.RequestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set(logger.RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// AccessLog gives every request a request-scoped logger and writes an access
+// log line when it completes, as described by logger.HTTPMiddleware
+func AccessLog(next http.Handler) http.Handler {
+	return logger.HTTPMiddleware(next)
+}
+
+// Recover turns panics into a 500 response with code ERR_INTERNAL and logs
+// them with a stack trace. http.ErrAbortHandler is re-panicked so the server
+// aborts the response as intended.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tw := &trackingWriter{ResponseWriter: w}
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			logger.FromContext(r.Context()).Error("Panic recovered",
+				zap.Any("panic", recovered),
+				zap.Stack("stacktrace"),
+			)
+			if !tw.wrote {
+				response.WriteError(tw, r, fmt.Errorf("panic: %v", recovered))
+			}
+		}()
+		next.ServeHTTP(tw, r)
+	})
+}
+
+// Timeout gives each request a context deadline. Handlers must respect the
+// context; when the deadline passes before a handler has written anything,
+// a 504 response with code ERR_TIMEOUT is written once it returns.
+func Timeout(timeout time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			r = r.WithContext(ctx)
+			tw := &trackingWriter{ResponseWriter: w}
+			next.ServeHTTP(tw, r)
+
+			if !tw.wrote && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				response.WriteError(tw, r, context.DeadlineExceeded)
+			}
+		})
+	}
+}
+
+// MaxBodySize limits request bodies to limit bytes. Requests declaring a
+// larger Content-Length get a 413 response with code ERR_PAYLOAD_TOO_LARGE;
+// otherwise reads past the limit fail with *http.MaxBytesError.
+func MaxBodySize(limit int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				response.Write(w, r, http.StatusRequestEntityTooLarge,
+					response.NewErrorResponseWithCode(response.CodePayloadTooLarge, "Request body too large"))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// trackingWriter records whether the handler has written a response
+type trackingWriter struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+// WriteHeader records the write before passing it through
+func (w *trackingWriter) WriteHeader(statusCode int) {
+	w.wrote = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write records the write before passing it through
+func (w *trackingWriter) Write(b []byte) (int, error) {
+	w.wrote = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *trackingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush implements http.Flusher for streaming handlers
+func (w *trackingWriter) Flush() {
+	w.wrote = true
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// newRequestID generates a request ID
+func newRequestID() string {
+	return idgen.RequestID()
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/logger"
+	"github.com/khekrn/core/response"
+	"go.uber.org/zap"
+)
+
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder) response.Response {
+	t.Helper()
+	var resp response.Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Invalid response body %q: %v", rec.Body.String(), err)
+	}
+	return resp
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	handler := Chain(mark("first"), mark("second"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if strings.Join(order, ",") != "first,second,handler" {
+		t.Errorf("Unexpected order %v", order)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	handler := Chain(RequestID, AccessLog)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logger.RequestIDFromContext(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if seen == "" || rec.Header().Get(logger.RequestIDHeader) != seen {
+		t.Errorf("Expected a generated request ID to be shared, got %q and %q", seen, rec.Header().Get(logger.RequestIDHeader))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(logger.RequestIDHeader, "req-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if seen != "req-1" {
+		t.Errorf("Expected the incoming request ID, got %q", seen)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	log := logger.NewTestLogger(t)
+
+	handler := Chain(RequestID, Recover)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	resp := decodeResponse(t, rec)
+	if rec.Code != http.StatusInternalServerError || resp.Code != response.CodeInternal || resp.RequestID == "" {
+		t.Errorf("Unexpected response %d %+v", rec.Code, resp)
+	}
+	log.AssertLogged(zap.ErrorLevel, "Panic recovered", zap.Any("panic", "boom"))
+
+	// A panic after the handler has written leaves the response alone
+	handler = Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("late")
+	}))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusAccepted || rec.Body.Len() != 0 {
+		t.Errorf("Expected the written response to be kept, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("Expected http.ErrAbortHandler to be re-panicked, got %v", recovered)
+		}
+	}()
+	Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestTimeout(t *testing.T) {
+	handler := Timeout(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if resp := decodeResponse(t, rec); rec.Code != http.StatusGatewayTimeout || resp.Code != response.CodeTimeout {
+		t.Errorf("Unexpected response %d %+v", rec.Code, resp)
+	}
+
+	handler = Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Errorf("Unexpected response %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	var readErr error
+	handler := MaxBodySize(4)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("too large")))
+	if resp := decodeResponse(t, rec); rec.Code != http.StatusRequestEntityTooLarge || resp.Code != response.CodePayloadTooLarge {
+		t.Errorf("Unexpected response %d %+v", rec.Code, resp)
+	}
+
+	// Without a Content-Length the limit applies while reading
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader("too large")))
+	req.ContentLength = -1
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	var maxErr *http.MaxBytesError
+	if !errors.As(readErr, &maxErr) {
+		t.Errorf("Expected a MaxBytesError, got %v", readErr)
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("ok")))
+	if readErr != nil {
+		t.Errorf("Expected a small body to be read, got %v", readErr)
+	}
+}
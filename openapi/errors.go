@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/khekrn/core/response"
+)
+
+// CodeMethodNotAllowed is the error code for a method the spec does not
+// define on the path, returned with HTTP 405
+const CodeMethodNotAllowed = "ERR_METHOD_NOT_ALLOWED"
+
+func init() {
+	if err := response.RegisterCode(response.CodeInfo{
+		Code:        CodeMethodNotAllowed,
+		HTTPStatus:  http.StatusMethodNotAllowed,
+		Description: "The method is not allowed for this path",
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// ValidationErrors converts an openapi3filter validation error into field
+// errors. Body fields are reported as dotted paths (e.g. "items.0.sku") and
+// parameters by name.
+func ValidationErrors(err error, includeDetails bool) []response.ValidationError {
+	// Match the concrete types rather than using errors.As, since RequestError
+	// unwraps to its nested MultiError and would lose the parameter context
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var result []response.ValidationError
+		for _, nested := range e {
+			result = append(result, ValidationErrors(nested, includeDetails)...)
+		}
+		return result
+
+	case *openapi3filter.RequestError:
+		switch {
+		case e.Parameter != nil:
+			return []response.ValidationError{{Field: e.Parameter.Name, Reason: reason(e, includeDetails)}}
+
+		case e.RequestBody != nil:
+			if nested, ok := e.Err.(openapi3.MultiError); ok {
+				result := make([]response.ValidationError, 0, len(nested))
+				for _, n := range nested {
+					result = append(result, bodyError(n, includeDetails))
+				}
+				return result
+			}
+			return []response.ValidationError{bodyError(e.Err, includeDetails)}
+
+		default:
+			return []response.ValidationError{{Field: "request", Reason: reason(e, includeDetails)}}
+		}
+
+	default:
+		return []response.ValidationError{{Field: "request", Reason: err.Error()}}
+	}
+}
+
+// bodyError converts a request body schema error into a field error
+func bodyError(err error, includeDetails bool) response.ValidationError {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		field := strings.Join(schemaErr.JSONPointer(), ".")
+		if field == "" {
+			field = "body"
+		}
+		return response.ValidationError{Field: field, Reason: schemaErr.Reason}
+	}
+
+	if err == nil {
+		return response.ValidationError{Field: "body", Reason: "Invalid request body"}
+	}
+	if includeDetails {
+		return response.ValidationError{Field: "body", Reason: err.Error()}
+	}
+	return response.ValidationError{Field: "body", Reason: "Invalid request body"}
+}
+
+// reason returns a client-facing reason for a parameter or request error
+func reason(err *openapi3filter.RequestError, includeDetails bool) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err.Err, &schemaErr) && !includeDetails {
+		return schemaErr.Reason
+	}
+	if err.Err != nil && includeDetails {
+		return err.Err.Error()
+	}
+	if err.Reason != "" {
+		return err.Reason
+	}
+	if err.Err != nil {
+		return err.Err.Error()
+	}
+	return "invalid value"
+}
@@ -0,0 +1,161 @@
+// Package openapi provides HTTP middleware that validates incoming requests
+// against an OpenAPI 3 specification.
+//
+// This package offers loading of specs (including split files with $refs)
+// from an fs.FS such as embed.FS, and middleware that validates request
+// paths, parameters, and bodies, rejecting contract violations with a 400
+// in the standard validation-error envelope before they reach handlers.
+//
+// Example usage:
+//
+//	//go:embed api/*.yaml
+//	var specs embed.FS
+//
+//	doc, err := openapi.Load(ctx, specs, "api/openapi.yaml")
+//	validate, err := openapi.Middleware(doc, openapi.WithSkipUnknownRoutes())
+//
+//	http.ListenAndServe(":8080", validate(mux))
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/khekrn/core/response"
+)
+
+// Load reads, resolves, and validates the OpenAPI document at name within fsys.
+// Relative $refs are resolved against the same file system.
+func Load(ctx context.Context, fsys fs.FS, name string) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	loader.Context = ctx
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(_ *openapi3.Loader, location *url.URL) ([]byte, error) {
+		return fs.ReadFile(fsys, strings.TrimPrefix(path.Clean(location.Path), "/"))
+	}
+
+	doc, err := loader.LoadFromURI(&url.URL{Path: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI spec %s: %w", name, err)
+	}
+	if err := doc.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec %s: %w", name, err)
+	}
+	return doc, nil
+}
+
+// Config holds configuration for the validation middleware
+type Config struct {
+	SkipUnknownRoutes  bool
+	ExcludedPaths      []string
+	AuthenticationFunc openapi3filter.AuthenticationFunc
+	IncludeDetails     bool
+}
+
+// Option is a function type for configuring the validation middleware
+type Option func(*Config)
+
+// WithSkipUnknownRoutes passes requests for routes missing from the spec
+// through unvalidated instead of rejecting them with 404
+func WithSkipUnknownRoutes() Option {
+	return func(config *Config) {
+		config.SkipUnknownRoutes = true
+	}
+}
+
+// WithExcludedPaths skips validation for requests whose path starts with
+// any of the given prefixes, e.g. "/health" or "/metrics"
+func WithExcludedPaths(prefixes ...string) Option {
+	return func(config *Config) {
+		config.ExcludedPaths = append(config.ExcludedPaths, prefixes...)
+	}
+}
+
+// WithAuthenticationFunc validates the spec's security requirements.
+// By default security requirements are not checked, leaving authentication
+// to dedicated middleware.
+func WithAuthenticationFunc(fn openapi3filter.AuthenticationFunc) Option {
+	return func(config *Config) {
+		config.AuthenticationFunc = fn
+	}
+}
+
+// WithErrorDetails includes the underlying schema error in validation reasons,
+// useful in development but verbose for clients
+func WithErrorDetails() Option {
+	return func(config *Config) {
+		config.IncludeDetails = true
+	}
+}
+
+// Middleware returns middleware validating requests against doc
+func Middleware(doc *openapi3.T, options ...Option) (func(http.Handler) http.Handler, error) {
+	config := Config{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI router: %w", err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, prefix := range config.ExcludedPaths {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				switch {
+				case errors.Is(err, routers.ErrMethodNotAllowed):
+					response.WriteError(w, r, response.NewAPIError(CodeMethodNotAllowed, "Method not allowed", err))
+				case config.SkipUnknownRoutes:
+					next.ServeHTTP(w, r)
+				default:
+					response.WriteError(w, r, response.NewAPIError(response.CodeNotFound, "Route not found", err))
+				}
+				return
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					MultiError:         true,
+					AuthenticationFunc: config.AuthenticationFunc,
+				},
+			}
+
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				var securityErr *openapi3filter.SecurityRequirementsError
+				if errors.As(err, &securityErr) {
+					response.WriteError(w, r, response.NewAPIError(response.CodeUnauthorized, "Unauthorized", err))
+					return
+				}
+
+				apiErr := response.NewAPIError(response.CodeValidation, "Request validation failed", err)
+				apiErr.Data = ValidationErrors(err, config.IncludeDetails)
+				response.WriteError(w, r, apiErr)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
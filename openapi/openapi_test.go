@@ -0,0 +1,145 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/khekrn/core/response"
+)
+
+const spec = `
+openapi: 3.0.3
+info:
+  title: Orders
+  version: "1.0"
+paths:
+  /orders/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+  /orders:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "schemas.yaml#/Order"
+      responses:
+        "201":
+          description: Created
+`
+
+const schemas = `
+Order:
+  type: object
+  required: [sku, quantity]
+  properties:
+    sku:
+      type: string
+    quantity:
+      type: integer
+      minimum: 1
+`
+
+func newHandler(t *testing.T, options ...Option) http.Handler {
+	t.Helper()
+	fsys := fstest.MapFS{
+		"api/openapi.yaml": {Data: []byte(spec)},
+		"api/schemas.yaml": {Data: []byte(schemas)},
+	}
+
+	doc, err := Load(context.Background(), fsys, "api/openapi.yaml")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	validate, err := Middleware(doc, options...)
+	if err != nil {
+		t.Fatalf("Middleware failed: %v", err)
+	}
+	return validate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestMiddleware(t *testing.T) {
+	handler := newHandler(t)
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		expected int
+	}{
+		{"valid path param", http.MethodGet, "/orders/42", "", http.StatusOK},
+		{"invalid path param", http.MethodGet, "/orders/abc", "", http.StatusBadRequest},
+		{"valid body", http.MethodPost, "/orders", `{"sku":"A1","quantity":2}`, http.StatusOK},
+		{"invalid body", http.MethodPost, "/orders", `{"quantity":0}`, http.StatusBadRequest},
+		{"unknown route", http.MethodGet, "/unknown", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMiddleware_ValidationErrors(t *testing.T) {
+	handler := newHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"quantity":0}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var resp struct {
+		Status string                     `json:"status"`
+		Code   string                     `json:"code"`
+		Data   []response.ValidationError `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	fields := make(map[string]bool)
+	for _, e := range resp.Data {
+		fields[e.Field] = true
+	}
+	if resp.Status != response.StatusReject || resp.Code != response.CodeValidation || !fields["sku"] || !fields["quantity"] {
+		t.Errorf("Expected errors for sku and quantity, got %+v", resp)
+	}
+}
+
+func TestMiddleware_SkipAndExclude(t *testing.T) {
+	handler := newHandler(t, WithSkipUnknownRoutes(), WithExcludedPaths("/orders/internal"))
+
+	for _, target := range []string{"/unknown", "/orders/internal"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected %s to pass through, got %d", target, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package outbox provides a transactional outbox for reliably publishing
+// events from services that store state in a database.
+//
+// This package offers an Outbox that writes events to a table inside the
+// caller's db transaction, so events are recorded if and only if the
+// business change commits, and a Relay that publishes stored events to a
+// message queue with at-least-once delivery, per-aggregate ordering, and
+// cleanup of delivered events.
+//
+// Example usage:
+//
+//	box := outbox.New(database)
+//
+//	err := database.WithTransaction(ctx, nil, func(ctx context.Context, tx *db.Tx) error {
+//		if _, err := tx.ExecContext(ctx, "INSERT INTO orders ...", ...); err != nil {
+//			return err
+//		}
+//		return box.Add(ctx, outbox.NewMessage("order", order.ID, "order.created", order))
+//	})
+//
+//	relay := outbox.NewRelay(box, publisher, outbox.WithPollInterval(time.Second))
+//	go relay.Run(ctx)
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/khekrn/core/db"
+)
+
+// ErrNoTransaction is returned by Add when the context carries no db transaction
+var ErrNoTransaction = errors.New("outbox: messages must be added within a db transaction")
+
+// Message is an event stored in the outbox
+type Message struct {
+	ID            int64
+	AggregateType string
+	AggregateID   string
+	EventType     string
+	Payload       json.RawMessage
+	Headers       map[string]string
+	CreatedAt     time.Time
+	Attempts      int
+}
+
+// NewMessage creates a message with payload encoded as JSON.
+// Messages with the same aggregate type and ID are published in order.
+func NewMessage(aggregateType, aggregateID, eventType string, payload any) Message {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		// Surface the error on Add rather than forcing every caller to handle it here
+		data = nil
+	}
+	return Message{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+	}
+}
+
+// Publisher delivers messages to a queue or broker. Publish must return
+// nil only once the broker has acknowledged the message.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// PublisherFunc adapts a function to the Publisher interface
+type PublisherFunc func(ctx context.Context, msg Message) error
+
+// Publish calls f(ctx, msg)
+func (f PublisherFunc) Publish(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// Config holds configuration for an Outbox
+type Config struct {
+	Table string
+}
+
+// Option is a function type for configuring an Outbox
+type Option func(*Config)
+
+// WithTable sets the outbox table name
+func WithTable(table string) Option {
+	return func(config *Config) {
+		config.Table = table
+	}
+}
+
+// Outbox stores messages in a database table
+type Outbox struct {
+	db     *db.DB
+	config Config
+}
+
+// New creates an outbox backed by database
+func New(database *db.DB, options ...Option) *Outbox {
+	config := Config{Table: "outbox_messages"}
+	for _, opt := range options {
+		opt(&config)
+	}
+	return &Outbox{db: database, config: config}
+}
+
+// CreateTable creates the outbox table and its index if they do not exist.
+// Services using db.Migrate can copy the statement from Schema instead.
+func (o *Outbox) CreateTable(ctx context.Context) error {
+	if _, err := o.db.ExecContext(ctx, o.Schema()); err != nil {
+		return fmt.Errorf("failed to create outbox table: %w", err)
+	}
+	return nil
+}
+
+// Schema returns the PostgreSQL DDL for the outbox table
+func (o *Outbox) Schema() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	id             BIGSERIAL PRIMARY KEY,
+	aggregate_type TEXT NOT NULL,
+	aggregate_id   TEXT NOT NULL,
+	event_type     TEXT NOT NULL,
+	payload        JSONB NOT NULL,
+	headers        JSONB,
+	created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+	published_at   TIMESTAMPTZ,
+	attempts       INT NOT NULL DEFAULT 0,
+	last_error     TEXT
+);
+CREATE INDEX IF NOT EXISTS %[1]s_pending_idx ON %[1]s (id) WHERE published_at IS NULL;`, o.config.Table)
+}
+
+// Add writes messages to the outbox using the transaction carried by ctx.
+// It fails with ErrNoTransaction when called outside db.WithTransaction,
+// since writing outside the business transaction defeats the pattern.
+func (o *Outbox) Add(ctx context.Context, messages ...Message) error {
+	tx, ok := db.TxFromContext(ctx)
+	if !ok {
+		return ErrNoTransaction
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (aggregate_type, aggregate_id, event_type, payload, headers)
+		VALUES ($1, $2, $3, $4, $5)`, o.config.Table)
+
+	for _, msg := range messages {
+		if msg.Payload == nil {
+			return fmt.Errorf("outbox: message %s for %s/%s has no valid JSON payload",
+				msg.EventType, msg.AggregateType, msg.AggregateID)
+		}
+
+		var headers []byte
+		if len(msg.Headers) > 0 {
+			var err error
+			if headers, err = json.Marshal(msg.Headers); err != nil {
+				return fmt.Errorf("failed to encode headers: %w", err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, query, msg.AggregateType, msg.AggregateID, msg.EventType, []byte(msg.Payload), headers); err != nil {
+			return fmt.Errorf("failed to write outbox message: %w", err)
+		}
+	}
+	return nil
+}
+
+// Pending returns the number of messages not yet published
+func (o *Outbox) Pending(ctx context.Context) (int64, error) {
+	var count int64
+	query := fmt.Sprintf("SELECT count(*) FROM %s WHERE published_at IS NULL", o.config.Table)
+	if err := o.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count pending messages: %w", err)
+	}
+	return count, nil
+}
+
+// fetchPending locks and returns the oldest pending messages
+func (o *Outbox) fetchPending(ctx context.Context, tx *db.Tx, limit int) ([]Message, error) {
+	query := fmt.Sprintf(`SELECT id, aggregate_type, aggregate_id, event_type, payload, headers, created_at, attempts
+		FROM %s WHERE published_at IS NULL ORDER BY id LIMIT $1 FOR UPDATE`, o.config.Table)
+
+	return db.Query(ctx, tx, func(rows *sql.Rows) (Message, error) {
+		var msg Message
+		var payload, headers []byte
+		if err := rows.Scan(&msg.ID, &msg.AggregateType, &msg.AggregateID, &msg.EventType, &payload, &headers, &msg.CreatedAt, &msg.Attempts); err != nil {
+			return msg, err
+		}
+		msg.Payload = payload
+		if len(headers) > 0 {
+			if err := json.Unmarshal(headers, &msg.Headers); err != nil {
+				return msg, fmt.Errorf("failed to decode headers of message %d: %w", msg.ID, err)
+			}
+		}
+		return msg, nil
+	}, query, limit)
+}
+
+// markPublished records successful delivery of the given messages
+func (o *Outbox) markPublished(ctx context.Context, tx *db.Tx, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET published_at = now() WHERE id IN (%s)", o.config.Table, strings.Join(placeholders, ", "))
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to mark messages as published: %w", err)
+	}
+	return nil
+}
+
+// markFailed records a failed delivery attempt
+func (o *Outbox) markFailed(ctx context.Context, tx *db.Tx, id int64, cause error) error {
+	query := fmt.Sprintf("UPDATE %s SET attempts = attempts + 1, last_error = $2 WHERE id = $1", o.config.Table)
+	if _, err := tx.ExecContext(ctx, query, id, cause.Error()); err != nil {
+		return fmt.Errorf("failed to record delivery failure: %w", err)
+	}
+	return nil
+}
+
+// Cleanup deletes messages published before the retention period and returns how many were removed
+func (o *Outbox) Cleanup(ctx context.Context, retention time.Duration) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE published_at IS NOT NULL AND published_at < $1", o.config.Table)
+	result, err := o.db.ExecContext(ctx, query, time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to clean up outbox: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,36 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAdd_RequiresTransaction(t *testing.T) {
+	box := New(nil)
+
+	err := box.Add(context.Background(), NewMessage("order", "o-1", "order.created", map[string]string{"id": "o-1"}))
+	if !errors.Is(err, ErrNoTransaction) {
+		t.Fatalf("Expected ErrNoTransaction, got %v", err)
+	}
+}
+
+func TestNewMessage(t *testing.T) {
+	msg := NewMessage("order", "o-1", "order.created", map[string]int{"total": 42})
+	if string(msg.Payload) != `{"total":42}` {
+		t.Errorf("Unexpected payload %s", msg.Payload)
+	}
+
+	invalid := NewMessage("order", "o-1", "order.created", make(chan int))
+	if invalid.Payload != nil {
+		t.Error("Expected unencodable payload to be nil")
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema := New(nil, WithTable("events_outbox")).Schema()
+	if !strings.Contains(schema, "CREATE TABLE IF NOT EXISTS events_outbox") {
+		t.Errorf("Expected schema to use custom table, got %s", schema)
+	}
+}
@@ -0,0 +1,188 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/khekrn/core/db"
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+)
+
+// RelayConfig holds configuration for a Relay
+type RelayConfig struct {
+	PollInterval    time.Duration
+	BatchSize       int
+	Retention       time.Duration
+	CleanupInterval time.Duration
+	LockKey         int64
+}
+
+// RelayOption is a function type for configuring a Relay
+type RelayOption func(*RelayConfig)
+
+// WithPollInterval sets how often the outbox is polled when it is idle
+func WithPollInterval(interval time.Duration) RelayOption {
+	return func(config *RelayConfig) {
+		config.PollInterval = interval
+	}
+}
+
+// WithBatchSize sets the maximum number of messages published per poll
+func WithBatchSize(size int) RelayOption {
+	return func(config *RelayConfig) {
+		config.BatchSize = size
+	}
+}
+
+// WithRetention sets how long published messages are kept before cleanup.
+// A zero retention disables cleanup.
+func WithRetention(retention time.Duration) RelayOption {
+	return func(config *RelayConfig) {
+		config.Retention = retention
+	}
+}
+
+// WithCleanupInterval sets how often published messages are cleaned up
+func WithCleanupInterval(interval time.Duration) RelayOption {
+	return func(config *RelayConfig) {
+		config.CleanupInterval = interval
+	}
+}
+
+// WithRelayLockKey sets the advisory lock key that elects a single active relay
+func WithRelayLockKey(key int64) RelayOption {
+	return func(config *RelayConfig) {
+		config.LockKey = key
+	}
+}
+
+// Relay publishes outbox messages to a Publisher.
+//
+// Delivery is at-least-once: a message is marked as published only after
+// the publisher acknowledges it, so a crash in between causes redelivery and
+// consumers must be idempotent. A PostgreSQL advisory lock ensures only one
+// replica relays at a time, and a failed message holds back later messages
+// of the same aggregate, preserving per-aggregate order.
+type Relay struct {
+	outbox    *Outbox
+	publisher Publisher
+	config    RelayConfig
+}
+
+// NewRelay creates a relay publishing messages from outbox
+func NewRelay(outbox *Outbox, publisher Publisher, options ...RelayOption) *Relay {
+	config := RelayConfig{
+		PollInterval:    time.Second,
+		BatchSize:       100,
+		Retention:       7 * 24 * time.Hour,
+		CleanupInterval: time.Hour,
+		LockKey:         7_461_238_403,
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+	return &Relay{outbox: outbox, publisher: publisher, config: config}
+}
+
+// Run relays messages until ctx is cancelled. Full batches are followed
+// immediately by the next poll so that a backlog drains quickly.
+func (r *Relay) Run(ctx context.Context) error {
+	poll := time.NewTimer(0)
+	defer poll.Stop()
+
+	cleanup := time.NewTicker(r.config.CleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-poll.C:
+			published, err := r.RelayOnce(ctx)
+			if err != nil && ctx.Err() == nil && logger.Logger != nil {
+				logger.Error("Outbox relay failed", zap.Error(err))
+			}
+			if err == nil && published == r.config.BatchSize {
+				poll.Reset(0)
+			} else {
+				poll.Reset(r.config.PollInterval)
+			}
+
+		case <-cleanup.C:
+			if r.config.Retention <= 0 {
+				continue
+			}
+			removed, err := r.outbox.Cleanup(ctx, r.config.Retention)
+			if logger.Logger == nil {
+				continue
+			}
+			if err != nil {
+				logger.Warn("Outbox cleanup failed", zap.Error(err))
+			} else if removed > 0 {
+				logger.Debug("Cleaned up outbox messages", zap.Int64("removed", removed))
+			}
+		}
+	}
+}
+
+// RelayOnce publishes a single batch of pending messages and returns the
+// number published. It returns zero without error when another relay holds the lock.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	published := 0
+
+	err := r.outbox.db.WithTransaction(ctx, nil, func(ctx context.Context, tx *db.Tx) error {
+		published = 0
+
+		var locked bool
+		if err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", r.config.LockKey).Scan(&locked); err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		messages, err := r.outbox.fetchPending(ctx, tx, r.config.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		blocked := make(map[string]bool)
+		var delivered []int64
+
+		for _, msg := range messages {
+			aggregate := msg.AggregateType + "/" + msg.AggregateID
+			if blocked[aggregate] {
+				continue
+			}
+
+			if err := r.publisher.Publish(ctx, msg); err != nil {
+				blocked[aggregate] = true
+				if logger.Logger != nil {
+					logger.Warn("Failed to publish outbox message",
+						zap.Int64("id", msg.ID),
+						zap.String("aggregate", aggregate),
+						zap.String("event_type", msg.EventType),
+						zap.Int("attempts", msg.Attempts+1),
+						zap.Error(err),
+					)
+				}
+				if err := r.outbox.markFailed(ctx, tx, msg.ID, err); err != nil {
+					// Rolling back means delivered messages are sent again, which at-least-once allows
+					return err
+				}
+				continue
+			}
+			delivered = append(delivered, msg.ID)
+		}
+
+		if err := r.outbox.markPublished(ctx, tx, delivered); err != nil {
+			return err
+		}
+		published = len(delivered)
+		return nil
+	})
+
+	return published, err
+}
@@ -0,0 +1,57 @@
+package pagination
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keyset describes a keyset (seek) ordering over columns that together are
+// unique, e.g. created_at then id, and renders the matching SQL fragments
+// with PostgreSQL placeholders. All columns sort in the same direction, so
+// the seek predicate is a single row comparison an index on the columns
+// can serve.
+//
+// Example:
+//
+//	var users = pagination.Keyset{Columns: []string{"created_at", "id"}, Descending: true}
+//
+//	query, args := "SELECT id, name, created_at FROM users", []any{}
+//	if params.Cursor() != "" {
+//		c, err := pagination.Decode[userCursor](codec, params.Cursor())
+//		query += " WHERE " + users.Where(params, 1)
+//		args = append(args, c.CreatedAt, c.ID)
+//	}
+//	query += fmt.Sprintf(" ORDER BY %s LIMIT %d", users.OrderBy(params), params.Limit+1)
+type Keyset struct {
+	Columns    []string
+	Descending bool
+}
+
+// Where returns the predicate selecting rows past the cursor in the paging
+// direction, e.g. "(created_at, id) < ($1, $2)", numbering placeholders
+// from start. The cursor values are bound in column order.
+func (k Keyset) Where(params Params, start int) string {
+	placeholders := make([]string, len(k.Columns))
+	for i := range k.Columns {
+		placeholders[i] = fmt.Sprintf("$%d", start+i)
+	}
+	operator := ">"
+	if k.Descending != params.Backward() {
+		operator = "<"
+	}
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(k.Columns, ", "), operator, strings.Join(placeholders, ", "))
+}
+
+// OrderBy returns the ORDER BY list for the paging direction. Backward pages
+// are read in reverse order, which NewPage flips back.
+func (k Keyset) OrderBy(params Params) string {
+	direction := "ASC"
+	if k.Descending != params.Backward() {
+		direction = "DESC"
+	}
+	columns := make([]string, len(k.Columns))
+	for i, column := range k.Columns {
+		columns[i] = column + " " + direction
+	}
+	return strings.Join(columns, ", ")
+}
@@ -0,0 +1,36 @@
+package pagination
+
+import "testing"
+
+func TestKeyset(t *testing.T) {
+	keyset := Keyset{Columns: []string{"created_at", "id"}, Descending: true}
+
+	tests := []struct {
+		name    string
+		params  Params
+		where   string
+		orderBy string
+	}{
+		{"forward", Params{After: "a"}, "(created_at, id) < ($3, $4)", "created_at DESC, id DESC"},
+		{"backward", Params{Before: "b"}, "(created_at, id) > ($3, $4)", "created_at ASC, id ASC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keyset.Where(tt.params, 3); got != tt.where {
+				t.Errorf("Expected where %q, got %q", tt.where, got)
+			}
+			if got := keyset.OrderBy(tt.params); got != tt.orderBy {
+				t.Errorf("Expected order by %q, got %q", tt.orderBy, got)
+			}
+			if tt.params.Cursor() == "" {
+				t.Error("Expected the cursor in the paging direction")
+			}
+		})
+	}
+
+	ascending := Keyset{Columns: []string{"id"}}
+	if got := ascending.Where(Params{}, 1); got != "(id) > ($1)" {
+		t.Errorf("Unexpected ascending predicate %q", got)
+	}
+}
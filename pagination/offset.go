@@ -0,0 +1,58 @@
+package pagination
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/khekrn/core/response"
+)
+
+// OffsetParams holds parsed limit/offset query parameters
+type OffsetParams struct {
+	Limit  int
+	Offset int
+}
+
+// ParseOffsetParams reads limit and offset from the request query. Prefer
+// cursors for large or frequently changing collections: offsets skip or
+// repeat items when rows are inserted or deleted between requests.
+func ParseOffsetParams(r *http.Request, options ...Option) (OffsetParams, error) {
+	config := newConfig(options)
+
+	query := r.URL.Query()
+	limit, err := parseLimit(query, config)
+	if err != nil {
+		return OffsetParams{}, err
+	}
+	params := OffsetParams{Limit: limit}
+
+	if raw := query.Get(config.OffsetParam); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return OffsetParams{}, fmt.Errorf("%w: %s must be a non-negative integer", ErrInvalidParams, config.OffsetParam)
+		}
+		params.Offset = offset
+	}
+
+	return params, nil
+}
+
+// NewOffsetPage builds a page from items fetched with Limit+1 rows starting
+// at Offset, so that the presence of an extra row signals more data. total
+// is included in the metadata when not nil.
+func NewOffsetPage[T any](items []T, params OffsetParams, total *int64) response.Page[T] {
+	hasMore := len(items) > params.Limit
+	if hasMore {
+		items = items[:params.Limit]
+	}
+	return response.Page[T]{
+		Items: items,
+		Meta: response.Meta{
+			Limit:   params.Limit,
+			Offset:  params.Offset,
+			HasMore: hasMore,
+			Total:   total,
+		},
+	}
+}
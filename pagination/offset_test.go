@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseOffsetParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		limit   int
+		offset  int
+		wantErr bool
+	}{
+		{"default", "", DefaultLimit, 0, false},
+		{"explicit", "?limit=5&offset=10", 5, 10, false},
+		{"clamped", "?limit=1000&offset=3", 50, 3, false},
+		{"negative offset", "?offset=-1", 0, 0, true},
+		{"not a number", "?offset=abc", 0, 0, true},
+		{"zero limit", "?limit=0", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/users"+tt.query, nil)
+			params, err := ParseOffsetParams(req, WithMaxLimit(50))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidParams) {
+					t.Errorf("Expected ErrInvalidParams, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseOffsetParams failed: %v", err)
+			}
+			if params.Limit != tt.limit || params.Offset != tt.offset {
+				t.Errorf("Expected limit %d offset %d, got %+v", tt.limit, tt.offset, params)
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/users?skip=4", nil)
+	if params, _ := ParseOffsetParams(req, WithOffsetParam("skip")); params.Offset != 4 {
+		t.Errorf("Expected offset from custom parameter, got %+v", params)
+	}
+}
+
+func TestNewOffsetPage(t *testing.T) {
+	total := int64(7)
+	page := NewOffsetPage([]int{3, 4, 5}, OffsetParams{Limit: 2, Offset: 2}, &total)
+	if len(page.Items) != 2 || !page.Meta.HasMore {
+		t.Fatalf("Unexpected page: %+v", page)
+	}
+
+	data, err := json.Marshal(page.Meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"limit":2,"has_more":true,"offset":2,"total":7}` {
+		t.Errorf("Unexpected meta %s", data)
+	}
+
+	last := NewOffsetPage([]int{7}, OffsetParams{Limit: 2, Offset: 6}, nil)
+	if last.Meta.HasMore || last.Meta.Total != nil {
+		t.Errorf("Unexpected last page: %+v", last)
+	}
+}
@@ -0,0 +1,289 @@
+// Package pagination provides cursor-based pagination utilities shared
+// across services.
+//
+// This package offers opaque, tamper-proof cursors (HMAC-signed JSON encoded
+// as base64), parsing of limit/after/before and limit/offset query
+// parameters with bounds, keyset (seek) SQL fragments, and construction of
+// the response package's Page and Meta structures. Clients follow the pages
+// with client.EnvelopeCursorPages or client.OffsetPages.
+//
+// Example usage:
+//
+//	type userCursor struct {
+//		CreatedAt time.Time `json:"c"`
+//		ID        int64     `json:"i"`
+//	}
+//
+//	codec := pagination.NewCodec([]byte(os.Getenv("CURSOR_SECRET")))
+//
+//	params, err := pagination.ParseParams(r, pagination.WithMaxLimit(100))
+//	after, err := pagination.Decode[userCursor](codec, params.After)
+//
+//	// Fetch one extra row so HasMore can be determined
+//	users, err := repo.ListAfter(ctx, after, params.Limit+1)
+//
+//	page, err := pagination.NewPage(codec, users, params, func(u User) userCursor {
+//		return userCursor{CreatedAt: u.CreatedAt, ID: u.ID}
+//	})
+//	json.NewEncoder(w).Encode(response.NewPageResponse("Users", page))
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/khekrn/core/helpers"
+	"github.com/khekrn/core/response"
+)
+
+// Default limits applied by ParseParams
+const (
+	DefaultLimit = 20
+	DefaultMax   = 100
+)
+
+// ErrInvalidCursor is returned when a cursor is malformed or its signature does not match
+var ErrInvalidCursor = errors.New("pagination: invalid cursor")
+
+// ErrInvalidParams is returned when the pagination query parameters are invalid
+var ErrInvalidParams = errors.New("pagination: invalid parameters")
+
+// Codec encodes and decodes signed opaque cursors
+type Codec struct {
+	secret []byte
+}
+
+// NewCodec creates a cursor codec signing with secret. All instances of a
+// service must share the secret for cursors to be portable between them.
+func NewCodec(secret []byte) *Codec {
+	return &Codec{secret: secret}
+}
+
+// Encode serializes v to JSON, signs it, and returns an opaque cursor
+func (c *Codec) Encode(v any) (string, error) {
+	payload, err := helpers.ToJSON(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	signed := append(c.sign(payload), payload...)
+	return base64.RawURLEncoding.EncodeToString(signed), nil
+}
+
+// decode verifies the cursor signature and returns its JSON payload
+func (c *Codec) decode(cursor string) ([]byte, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(raw) < sha256.Size {
+		return nil, ErrInvalidCursor
+	}
+	signature, payload := raw[:sha256.Size], raw[sha256.Size:]
+	if !hmac.Equal(signature, c.sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+	return payload, nil
+}
+
+// sign computes the HMAC-SHA256 of payload
+func (c *Codec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// Decode verifies and decodes a cursor into T. An empty cursor yields the zero value.
+func Decode[T any](c *Codec, cursor string) (T, error) {
+	var zero T
+	if cursor == "" {
+		return zero, nil
+	}
+	payload, err := c.decode(cursor)
+	if err != nil {
+		return zero, err
+	}
+	value, err := helpers.FromJSONValue[T](payload)
+	if err != nil {
+		return zero, ErrInvalidCursor
+	}
+	return value, nil
+}
+
+// Params holds parsed pagination query parameters
+type Params struct {
+	Limit  int
+	After  string
+	Before string
+}
+
+// Backward reports whether the client is paging towards earlier items
+func (p Params) Backward() bool {
+	return p.Before != ""
+}
+
+// Cursor returns the cursor in the paging direction, or "" for the first page
+func (p Params) Cursor() string {
+	if p.Backward() {
+		return p.Before
+	}
+	return p.After
+}
+
+// Config holds configuration for ParseParams and ParseOffsetParams
+type Config struct {
+	DefaultLimit int
+	MaxLimit     int
+	LimitParam   string
+	AfterParam   string
+	BeforeParam  string
+	OffsetParam  string
+}
+
+// Option is a function type for configuring ParseParams and ParseOffsetParams
+type Option func(*Config)
+
+// WithDefaultLimit sets the limit used when the request does not specify one
+func WithDefaultLimit(limit int) Option {
+	return func(config *Config) {
+		config.DefaultLimit = limit
+	}
+}
+
+// WithMaxLimit sets the largest limit a client may request; larger values are clamped
+func WithMaxLimit(limit int) Option {
+	return func(config *Config) {
+		config.MaxLimit = limit
+	}
+}
+
+// WithParamNames overrides the query parameter names
+func WithParamNames(limit, after, before string) Option {
+	return func(config *Config) {
+		config.LimitParam = limit
+		config.AfterParam = after
+		config.BeforeParam = before
+	}
+}
+
+// WithOffsetParam overrides the offset query parameter name used by ParseOffsetParams
+func WithOffsetParam(name string) Option {
+	return func(config *Config) {
+		config.OffsetParam = name
+	}
+}
+
+// newConfig applies options to the default configuration
+func newConfig(options []Option) Config {
+	config := Config{
+		DefaultLimit: DefaultLimit,
+		MaxLimit:     DefaultMax,
+		LimitParam:   "limit",
+		AfterParam:   "after",
+		BeforeParam:  "before",
+		OffsetParam:  "offset",
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+	return config
+}
+
+// parseLimit reads the limit query parameter, applying the default and maximum
+func parseLimit(query url.Values, config Config) (int, error) {
+	limit := config.DefaultLimit
+	if raw := query.Get(config.LimitParam); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			return 0, fmt.Errorf("%w: %s must be a positive integer", ErrInvalidParams, config.LimitParam)
+		}
+		limit = parsed
+	}
+	if config.MaxLimit > 0 && limit > config.MaxLimit {
+		limit = config.MaxLimit
+	}
+	return limit, nil
+}
+
+// ParseParams reads limit, after, and before from the request query
+func ParseParams(r *http.Request, options ...Option) (Params, error) {
+	config := newConfig(options)
+
+	query := r.URL.Query()
+	limit, err := parseLimit(query, config)
+	if err != nil {
+		return Params{}, err
+	}
+	params := Params{
+		Limit:  limit,
+		After:  query.Get(config.AfterParam),
+		Before: query.Get(config.BeforeParam),
+	}
+
+	if params.After != "" && params.Before != "" {
+		return Params{}, fmt.Errorf("%w: %s and %s cannot be combined", ErrInvalidParams, config.AfterParam, config.BeforeParam)
+	}
+
+	return params, nil
+}
+
+// ValidationErrors converts a ParseParams or Decode error into field errors
+// suitable for response.NewErrorResponseWithValidationErrors
+func ValidationErrors(err error) []response.ValidationError {
+	switch {
+	case errors.Is(err, ErrInvalidCursor):
+		return []response.ValidationError{{Field: "cursor", Reason: "Invalid or expired cursor"}}
+	case errors.Is(err, ErrInvalidParams):
+		return []response.ValidationError{{Field: "pagination", Reason: err.Error()}}
+	default:
+		return nil
+	}
+}
+
+// NewPage builds a page from items fetched with Limit+1 rows, so that the
+// presence of an extra row signals more data. For backward paging (Before
+// set), items are expected in reverse order and are flipped back here.
+// cursorFn extracts the cursor value for an item.
+func NewPage[T, C any](c *Codec, items []T, params Params, cursorFn func(T) C) (response.Page[T], error) {
+	hasMore := len(items) > params.Limit
+	if hasMore {
+		items = items[:params.Limit]
+	}
+	if params.Backward() {
+		items = slices.Clone(items)
+		slices.Reverse(items)
+	}
+
+	page := response.Page[T]{
+		Items: items,
+		Meta:  response.Meta{Limit: params.Limit, HasMore: hasMore},
+	}
+	if len(items) == 0 {
+		return page, nil
+	}
+
+	// Forward pages always have a predecessor when an after cursor was given,
+	// backward pages always have a successor
+	needNext := hasMore || params.Backward()
+	needPrev := params.After != "" || (params.Backward() && hasMore)
+
+	if needNext {
+		next, err := c.Encode(cursorFn(items[len(items)-1]))
+		if err != nil {
+			return page, err
+		}
+		page.Meta.NextCursor = next
+	}
+	if needPrev {
+		prev, err := c.Encode(cursorFn(items[0]))
+		if err != nil {
+			return page, err
+		}
+		page.Meta.PrevCursor = prev
+	}
+
+	return page, nil
+}
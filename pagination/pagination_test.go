@@ -0,0 +1,98 @@
+package pagination
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+type cursor struct {
+	ID int `json:"id"`
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+
+	encoded, err := codec.Encode(cursor{ID: 42})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	decoded, err := Decode[cursor](codec, encoded)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if decoded.ID != 42 {
+		t.Errorf("Expected ID 42, got %d", decoded.ID)
+	}
+
+	other := NewCodec([]byte("other"))
+	if _, err := Decode[cursor](other, encoded); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor for wrong secret, got %v", err)
+	}
+	if _, err := Decode[cursor](codec, "not-a-cursor"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor for garbage, got %v", err)
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected int
+		wantErr  bool
+	}{
+		{"default", "", DefaultLimit, false},
+		{"explicit", "?limit=5", 5, false},
+		{"clamped", "?limit=1000", 50, false},
+		{"zero", "?limit=0", 0, true},
+		{"not a number", "?limit=abc", 0, true},
+		{"both directions", "?after=a&before=b", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/users"+tt.query, nil)
+			params, err := ParseParams(req, WithMaxLimit(50))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidParams) {
+					t.Errorf("Expected ErrInvalidParams, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseParams failed: %v", err)
+			}
+			if params.Limit != tt.expected {
+				t.Errorf("Expected limit %d, got %d", tt.expected, params.Limit)
+			}
+		})
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	idOf := func(id int) cursor { return cursor{ID: id} }
+
+	// First page: extra row present
+	page, err := NewPage(codec, []int{1, 2, 3}, Params{Limit: 2}, idOf)
+	if err != nil {
+		t.Fatalf("NewPage failed: %v", err)
+	}
+	if len(page.Items) != 2 || !page.Meta.HasMore || page.Meta.NextCursor == "" || page.Meta.PrevCursor != "" {
+		t.Fatalf("Unexpected first page: %+v", page)
+	}
+	next, _ := Decode[cursor](codec, page.Meta.NextCursor)
+	if next.ID != 2 {
+		t.Errorf("Expected next cursor at 2, got %d", next.ID)
+	}
+
+	// Backward page: items arrive reversed and are flipped back
+	page, err = NewPage(codec, []int{4, 3}, Params{Limit: 2, Before: page.Meta.NextCursor}, idOf)
+	if err != nil {
+		t.Fatalf("NewPage failed: %v", err)
+	}
+	if page.Items[0] != 3 || page.Items[1] != 4 || page.Meta.HasMore || page.Meta.PrevCursor != "" {
+		t.Errorf("Unexpected backward page: %+v", page)
+	}
+}
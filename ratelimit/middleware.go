@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/khekrn/core/logger"
+	"github.com/khekrn/core/response"
+	"go.uber.org/zap"
+)
+
+// KeyFunc returns the rate limit key of a request. An empty key skips
+// limiting for the request.
+type KeyFunc func(r *http.Request) string
+
+// KeyByIP keys requests by the client IP of the connection. Behind a proxy,
+// use KeyByHeader with the header the proxy sets instead.
+func KeyByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// KeyByHeader keys requests by a header, e.g. X-API-Key or X-Real-IP
+func KeyByHeader(name string) KeyFunc {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// Middleware limits inbound requests by key. It sets X-RateLimit-Limit and
+// X-RateLimit-Remaining on every limited response, and rejects denied
+// requests with 429, code ERR_RATE_LIMITED, and Retry-After. Store errors are
+// logged and the request is let through, so an unavailable store does not
+// take the service down.
+//
+// Example:
+//
+//	limiter := ratelimit.NewSlidingWindow(ratelimit.PerMinute(600))
+//	handler := middleware.Chain(
+//		middleware.RequestID,
+//		ratelimit.Middleware(limiter, ratelimit.KeyByHeader("X-API-Key")),
+//	)(mux)
+func Middleware(limiter Limiter, keyFunc KeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := keyFunc(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := limiter.Allow(r.Context(), key)
+			if err != nil {
+				logger.FromContext(r.Context()).Warn("Rate limiter failed; allowing request", zap.Error(err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+				response.WriteError(w, r, response.ErrRateLimited)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingLimiter always returns an error, like an unavailable store
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string) (Result, error) {
+	return Result{}, errors.New("store unavailable")
+}
+
+func TestMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	handler := Middleware(NewTokenBucket(Limit{Rate: 1, Period: time.Hour}), KeyByIP)(ok)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:5000"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent || rec.Header().Get("X-RateLimit-Limit") != "1" || rec.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("Unexpected first response %d %v", rec.Code, rec.Header())
+	}
+
+	req.RemoteAddr = "10.0.0.1:6000"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "3600" {
+		t.Errorf("Expected 429 with Retry-After, got %d %v", rec.Code, rec.Header())
+	}
+	if !strings.Contains(rec.Body.String(), `"code":"ERR_RATE_LIMITED"`) {
+		t.Errorf("Unexpected body %s", rec.Body.String())
+	}
+
+	keyed := Middleware(NewTokenBucket(Limit{Rate: 1, Period: time.Hour}), KeyByHeader("X-API-Key"))(ok)
+	for range 2 {
+		rec = httptest.NewRecorder()
+		keyed.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Expected requests without a key to skip limiting, got %d", rec.Code)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	Middleware(failingLimiter{}, KeyByIP)(ok).ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected store errors to fail open, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,271 @@
+// Package ratelimit provides token bucket and sliding window rate limiters
+// keyed by caller, usable as inbound HTTP middleware and as the client
+// package's outbound throttle.
+//
+// Limiter state lives in a Store. MemoryStore keeps it in process; a shared
+// store such as Redis makes the limits apply across instances.
+//
+// Example usage:
+//
+//	// 100 requests per minute per client IP, with bursts of 20
+//	limiter := ratelimit.NewTokenBucket(
+//		ratelimit.Limit{Rate: 100, Period: time.Minute, Burst: 20},
+//		ratelimit.WithOnThrottle(func(key string, result ratelimit.Result) {
+//			throttled.Inc()
+//		}),
+//	)
+//	handler := ratelimit.Middleware(limiter, ratelimit.KeyByIP)(mux)
+package ratelimit
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Limit describes an allowed rate
+type Limit struct {
+	Rate   int           // Requests allowed per period
+	Period time.Duration // Length of the period
+	Burst  int           // Token bucket capacity; defaults to Rate. Ignored by sliding windows.
+}
+
+// PerSecond returns a limit of rate requests per second
+func PerSecond(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Second}
+}
+
+// PerMinute returns a limit of rate requests per minute
+func PerMinute(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Minute}
+}
+
+// Result is the outcome of a limiter check
+type Result struct {
+	Allowed    bool          // Whether the request may proceed
+	Limit      int           // Maximum requests allowed at once
+	Remaining  int           // Requests still allowed right now
+	RetryAfter time.Duration // When denied, how long until a request may be allowed
+}
+
+// Limiter decides whether a request for key may proceed
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// Store holds limiter state per key. Update must apply fn atomically: it
+// passes the key's current state, or nil when there is none, and stores the
+// returned state for ttl. A Redis store can implement it with WATCH/MULTI or
+// a Lua compare-and-set, retrying on conflict.
+type Store interface {
+	Update(ctx context.Context, key string, ttl time.Duration, fn func(state []byte) ([]byte, error)) error
+}
+
+// Config holds configuration for a limiter
+type Config struct {
+	Store      Store                           // Defaults to a MemoryStore with DefaultMaxKeys keys
+	Prefix     string                          // Prepended to store keys, to share a store between limiters
+	OnThrottle func(key string, result Result) // Called when a request is denied, e.g. to count throttled calls
+}
+
+// Option is a function type for configuring a limiter
+type Option func(*Config)
+
+// WithStore sets the store holding limiter state
+func WithStore(store Store) Option {
+	return func(config *Config) {
+		config.Store = store
+	}
+}
+
+// WithPrefix sets the prefix of store keys
+func WithPrefix(prefix string) Option {
+	return func(config *Config) {
+		config.Prefix = prefix
+	}
+}
+
+// WithOnThrottle sets a callback for denied requests
+func WithOnThrottle(fn func(key string, result Result)) Option {
+	return func(config *Config) {
+		config.OnThrottle = fn
+	}
+}
+
+// newConfig applies options over the defaults
+func newConfig(options []Option) Config {
+	var config Config
+	for _, opt := range options {
+		opt(&config)
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryStore(DefaultMaxKeys)
+	}
+	return config
+}
+
+// errCorruptState is returned when a store holds state of the wrong shape
+var errCorruptState = errors.New("ratelimit: corrupt limiter state")
+
+// TokenBucket allows bursts of up to Burst requests, refilled at Rate per
+// Period
+type TokenBucket struct {
+	limit  Limit
+	config Config
+}
+
+// NewTokenBucket creates a token bucket limiter
+func NewTokenBucket(limit Limit, options ...Option) *TokenBucket {
+	if limit.Burst <= 0 {
+		limit.Burst = limit.Rate
+	}
+	return &TokenBucket{limit: limit, config: newConfig(options)}
+}
+
+// Allow takes a token from key's bucket when one is available
+func (b *TokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	if b.limit.Rate <= 0 || b.limit.Period <= 0 {
+		return Result{}, fmt.Errorf("ratelimit: invalid limit %+v", b.limit)
+	}
+	now := time.Now()
+	perToken := b.limit.Period / time.Duration(b.limit.Rate)
+	capacity := float64(b.limit.Burst)
+	ttl := perToken * time.Duration(b.limit.Burst)
+
+	var result Result
+	err := b.config.Store.Update(ctx, b.config.Prefix+key, ttl, func(state []byte) ([]byte, error) {
+		tokens, last := capacity, now
+		if state != nil {
+			if len(state) != 16 {
+				return nil, errCorruptState
+			}
+			tokens = math.Float64frombits(binary.BigEndian.Uint64(state))
+			last = time.Unix(0, int64(binary.BigEndian.Uint64(state[8:])))
+			if elapsed := now.Sub(last); elapsed > 0 {
+				tokens = math.Min(capacity, tokens+float64(elapsed)/float64(perToken))
+			}
+		}
+
+		result = Result{Limit: b.limit.Burst}
+		if tokens >= 1 {
+			tokens--
+			result.Allowed = true
+		} else {
+			result.RetryAfter = time.Duration((1 - tokens) * float64(perToken))
+		}
+		result.Remaining = int(tokens)
+
+		state = make([]byte, 16)
+		binary.BigEndian.PutUint64(state, math.Float64bits(tokens))
+		binary.BigEndian.PutUint64(state[8:], uint64(now.UnixNano()))
+		return state, nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	b.config.report(key, result)
+	return result, nil
+}
+
+// SlidingWindow allows Rate requests in any window of length Period. It
+// approximates the window from the current and previous fixed windows, so it
+// needs constant state per key.
+type SlidingWindow struct {
+	limit  Limit
+	config Config
+}
+
+// NewSlidingWindow creates a sliding window limiter
+func NewSlidingWindow(limit Limit, options ...Option) *SlidingWindow {
+	return &SlidingWindow{limit: limit, config: newConfig(options)}
+}
+
+// Allow counts a request for key when the window has room
+func (w *SlidingWindow) Allow(ctx context.Context, key string) (Result, error) {
+	if w.limit.Rate <= 0 || w.limit.Period <= 0 {
+		return Result{}, fmt.Errorf("ratelimit: invalid limit %+v", w.limit)
+	}
+	now := time.Now()
+	period := w.limit.Period
+	rate := float64(w.limit.Rate)
+
+	var result Result
+	err := w.config.Store.Update(ctx, w.config.Prefix+key, 2*period, func(state []byte) ([]byte, error) {
+		start := now.Truncate(period)
+		var current, previous float64
+		if state != nil {
+			if len(state) != 24 {
+				return nil, errCorruptState
+			}
+			stored := time.Unix(0, int64(binary.BigEndian.Uint64(state)))
+			storedCurrent := math.Float64frombits(binary.BigEndian.Uint64(state[8:]))
+			storedPrevious := math.Float64frombits(binary.BigEndian.Uint64(state[16:]))
+			switch {
+			case stored.Equal(start):
+				current, previous = storedCurrent, storedPrevious
+			case stored.Add(period).Equal(start):
+				previous = storedCurrent
+			}
+		}
+
+		elapsed := now.Sub(start)
+		weight := 1 - float64(elapsed)/float64(period)
+		estimate := previous*weight + current
+
+		result = Result{Limit: w.limit.Rate}
+		if estimate+1 <= rate {
+			current++
+			estimate++
+			result.Allowed = true
+		} else if spare := rate - current - 1; spare >= 0 && previous > 0 {
+			// Wait until enough of the previous window has slid out
+			result.RetryAfter = time.Duration((1-spare/previous)*float64(period)) - elapsed
+		} else {
+			result.RetryAfter = period - elapsed
+		}
+		result.Remaining = max(0, int(rate-math.Ceil(estimate)))
+
+		state = make([]byte, 24)
+		binary.BigEndian.PutUint64(state, uint64(start.UnixNano()))
+		binary.BigEndian.PutUint64(state[8:], math.Float64bits(current))
+		binary.BigEndian.PutUint64(state[16:], math.Float64bits(previous))
+		return state, nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	w.config.report(key, result)
+	return result, nil
+}
+
+// report calls OnThrottle for denied results
+func (c Config) report(key string, result Result) {
+	if !result.Allowed && c.OnThrottle != nil {
+		c.OnThrottle(key, result)
+	}
+}
+
+// Wait blocks until limiter allows a request for key or ctx is done. It is
+// the outbound counterpart of Middleware, used by the client's WithRateLimit.
+func Wait(ctx context.Context, limiter Limiter, key string) error {
+	for {
+		result, err := limiter.Allow(ctx, key)
+		if err != nil {
+			return err
+		}
+		if result.Allowed {
+			return nil
+		}
+
+		timer := time.NewTimer(max(result.RetryAfter, time.Millisecond))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
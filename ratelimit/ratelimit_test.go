@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	var throttled []string
+	limiter := NewTokenBucket(Limit{Rate: 10, Period: 100 * time.Millisecond, Burst: 3},
+		WithOnThrottle(func(key string, result Result) { throttled = append(throttled, key) }))
+	ctx := context.Background()
+
+	for i := range 3 {
+		result, err := limiter.Allow(ctx, "a")
+		if err != nil || !result.Allowed || result.Remaining != 2-i || result.Limit != 3 {
+			t.Fatalf("Request %d: unexpected %+v %v", i, result, err)
+		}
+	}
+	result, _ := limiter.Allow(ctx, "a")
+	if result.Allowed || result.RetryAfter <= 0 || result.RetryAfter > 10*time.Millisecond {
+		t.Errorf("Expected the burst to be exhausted, got %+v", result)
+	}
+	if len(throttled) != 1 || throttled[0] != "a" {
+		t.Errorf("Expected one throttled call, got %v", throttled)
+	}
+
+	if result, _ := limiter.Allow(ctx, "b"); !result.Allowed {
+		t.Error("Expected keys to be limited independently")
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if result, _ := limiter.Allow(ctx, "a"); !result.Allowed {
+		t.Errorf("Expected a refilled token, got %+v", result)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	limiter := NewSlidingWindow(Limit{Rate: 3, Period: 50 * time.Millisecond})
+	ctx := context.Background()
+
+	allowed := 0
+	for range 5 {
+		result, err := limiter.Allow(ctx, "a")
+		if err != nil {
+			t.Fatalf("Allow failed: %v", err)
+		}
+		if result.Allowed {
+			allowed++
+		} else if result.RetryAfter <= 0 || result.RetryAfter > 100*time.Millisecond {
+			t.Errorf("Unexpected retry after %v", result.RetryAfter)
+		}
+	}
+	if allowed != 3 {
+		t.Errorf("Expected 3 allowed requests, got %d", allowed)
+	}
+
+	time.Sleep(120 * time.Millisecond)
+	if result, _ := limiter.Allow(ctx, "a"); !result.Allowed || result.Remaining != 2 {
+		t.Errorf("Expected a fresh window, got %+v", result)
+	}
+}
+
+func TestLimiter_Invalid(t *testing.T) {
+	if _, err := NewTokenBucket(Limit{}).Allow(context.Background(), "a"); err == nil {
+		t.Error("Expected an error for an invalid limit")
+	}
+	if _, err := NewSlidingWindow(Limit{Rate: 1}).Allow(context.Background(), "a"); err == nil {
+		t.Error("Expected an error for an invalid limit")
+	}
+
+	store := NewMemoryStore(10)
+	store.Update(context.Background(), "a", time.Minute, func([]byte) ([]byte, error) { return []byte("x"), nil })
+	if _, err := NewTokenBucket(PerSecond(1), WithStore(store)).Allow(context.Background(), "a"); !errors.Is(err, errCorruptState) {
+		t.Errorf("Expected corrupt state, got %v", err)
+	}
+}
+
+func TestWait(t *testing.T) {
+	limiter := NewTokenBucket(Limit{Rate: 1, Period: 20 * time.Millisecond})
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 3 {
+		if err := Wait(ctx, limiter, "a"); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("Expected Wait to throttle, took %v", elapsed)
+	}
+
+	slow := NewTokenBucket(Limit{Rate: 1, Period: time.Hour})
+	Wait(ctx, slow, "a")
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := Wait(ctx, slow, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/khekrn/core/cache"
+)
+
+// DefaultMaxKeys is the number of keys held by the default MemoryStore
+const DefaultMaxKeys = 100_000
+
+// MemoryStore is an in-process Store. Idle keys expire after their TTL, and
+// the least recently used keys are evicted beyond maxKeys, which resets
+// their limits.
+type MemoryStore struct {
+	mu     sync.Mutex
+	states *cache.Cache[string, []byte]
+}
+
+// NewMemoryStore creates a store holding up to maxKeys keys; zero or less
+// means unbounded
+func NewMemoryStore(maxKeys int) *MemoryStore {
+	return &MemoryStore{states: cache.New[string, []byte](cache.WithMaxEntries(max(maxKeys, 0)))}
+}
+
+// Update applies fn to the state of key under the store's lock
+func (s *MemoryStore) Update(ctx context.Context, key string, ttl time.Duration, fn func(state []byte) ([]byte, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, _ := s.states.Get(key)
+	next, err := fn(state)
+	if err != nil {
+		return err
+	}
+	s.states.SetWithTTL(key, next, ttl)
+	return nil
+}
+
+// Len returns the number of keys held, including expired ones not yet removed
+func (s *MemoryStore) Len() int {
+	return s.states.Len()
+}
@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore(2)
+	ctx := context.Background()
+	increment := func(state []byte) ([]byte, error) {
+		return append(state, 1), nil
+	}
+
+	store.Update(ctx, "a", time.Minute, increment)
+	store.Update(ctx, "a", time.Minute, func(state []byte) ([]byte, error) {
+		if len(state) != 1 {
+			t.Errorf("Expected the stored state, got %v", state)
+		}
+		return increment(state)
+	})
+
+	errFail := errors.New("fail")
+	if err := store.Update(ctx, "a", time.Minute, func([]byte) ([]byte, error) { return nil, errFail }); err != errFail {
+		t.Errorf("Expected the update error, got %v", err)
+	}
+
+	store.Update(ctx, "b", time.Minute, increment)
+	store.Update(ctx, "c", time.Minute, increment)
+	if store.Len() != 2 {
+		t.Errorf("Expected the store to be bounded, got %d keys", store.Len())
+	}
+
+	store.Update(ctx, "short", time.Millisecond, increment)
+	time.Sleep(5 * time.Millisecond)
+	store.Update(ctx, "short", time.Minute, func(state []byte) ([]byte, error) {
+		if state != nil {
+			t.Errorf("Expected expired state to be gone, got %v", state)
+		}
+		return state, nil
+	})
+}
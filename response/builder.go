@@ -0,0 +1,115 @@
+package response
+
+import (
+	"context"
+	"net/http"
+)
+
+// Builder assembles a Response field by field, so optional fields can be
+// added without new constructors
+type Builder struct {
+	resp Response
+	ctx  context.Context
+}
+
+// New starts building a response
+//
+// Example:
+//
+//	resp := response.New().
+//		Message("Orders found").
+//		Data(orders).
+//		Meta("total", 42).
+//		Context(r.Context()).
+//		Build()
+func New() *Builder {
+	return &Builder{}
+}
+
+// Status sets the status. When unset, Build uses the status matching the code,
+// or StatusAccept without one.
+func (b *Builder) Status(status string) *Builder {
+	b.resp.Status = status
+	return b
+}
+
+// Code sets the machine-readable error code
+func (b *Builder) Code(code string) *Builder {
+	b.resp.Code = code
+	return b
+}
+
+// Message sets the human-readable message
+func (b *Builder) Message(message string) *Builder {
+	b.resp.Message = message
+	return b
+}
+
+// Data sets the response data
+func (b *Builder) Data(data any) *Builder {
+	b.resp.Data = data
+	return b
+}
+
+// Meta adds a metadata entry
+func (b *Builder) Meta(key string, value any) *Builder {
+	if b.resp.Meta == nil {
+		b.resp.Meta = make(map[string]any)
+	}
+	b.resp.Meta[key] = value
+	return b
+}
+
+// Link adds a hypermedia link under rel
+func (b *Builder) Link(rel string, link Link) *Builder {
+	b.resp = WithLink(b.resp, rel, link)
+	return b
+}
+
+// RequestID sets the request ID
+func (b *Builder) RequestID(requestID string) *Builder {
+	b.resp.RequestID = requestID
+	return b
+}
+
+// TraceID sets the trace ID
+func (b *Builder) TraceID(traceID string) *Builder {
+	b.resp.TraceID = traceID
+	return b
+}
+
+// Context fills unset request and trace IDs from ctx at Build, as
+// WithContext does
+func (b *Builder) Context(ctx context.Context) *Builder {
+	b.ctx = ctx
+	return b
+}
+
+// Build returns the response. The builder can keep being used afterwards
+// without affecting it.
+func (b *Builder) Build() Response {
+	resp := b.resp
+	if resp.Meta != nil {
+		meta := make(map[string]any, len(resp.Meta))
+		for key, value := range resp.Meta {
+			meta[key] = value
+		}
+		resp.Meta = meta
+	}
+
+	if resp.Status == "" {
+		switch {
+		case resp.Code == "":
+			resp.Status = StatusAccept
+		case HTTPStatusForCode(resp.Code) < http.StatusInternalServerError:
+			resp.Status = StatusReject
+		default:
+			resp.Status = StatusFailure
+		}
+	}
+
+	if b.ctx != nil {
+		resp = WithContext(b.ctx, resp)
+	}
+	return resp
+}
@@ -0,0 +1,44 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/khekrn/core/internal/correlation"
+)
+
+func TestBuilder(t *testing.T) {
+	ctx := correlation.With(context.Background(), correlation.RequestID, "req-1")
+
+	b := New().Message("Orders found").Data([]string{"a"}).Meta("total", 42).Context(ctx)
+	resp := b.Build()
+	data, _ := json.Marshal(resp)
+	if string(data) != `{"status":"Accepted","message":"Orders found","data":["a"],"meta":{"total":42},"request_id":"req-1"}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	b.Meta("page", 2)
+	if len(resp.Meta) != 1 {
+		t.Errorf("Expected built responses to be unaffected by later changes, got %v", resp.Meta)
+	}
+
+	tests := []struct {
+		builder *Builder
+		status  string
+	}{
+		{New().Code(CodeNotFound), StatusReject},
+		{New().Code(CodeUnavailable), StatusFailure},
+		{New().Code(CodeNotFound).Status(StatusFailure), StatusFailure},
+	}
+	for _, tt := range tests {
+		if got := tt.builder.Build().Status; got != tt.status {
+			t.Errorf("Expected %s, got %s", tt.status, got)
+		}
+	}
+
+	explicit := New().RequestID("upstream").TraceID("trace").Context(ctx).Build()
+	if explicit.RequestID != "upstream" || explicit.TraceID != "trace" {
+		t.Errorf("Expected explicit IDs to be kept, got %+v", explicit)
+	}
+}
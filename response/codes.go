@@ -0,0 +1,120 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Standard error codes. Clients branch on these instead of on messages,
+// which are for humans and may change.
+const (
+	CodeBadRequest      = "ERR_BAD_REQUEST"
+	CodeValidation      = "ERR_VALIDATION"
+	CodeUnauthorized    = "ERR_UNAUTHORIZED"
+	CodeForbidden       = "ERR_FORBIDDEN"
+	CodeNotFound        = "ERR_NOT_FOUND"
+	CodeConflict        = "ERR_CONFLICT"
+	CodePayloadTooLarge = "ERR_PAYLOAD_TOO_LARGE"
+	CodeRateLimited     = "ERR_RATE_LIMITED"
+	CodeInternal        = "ERR_INTERNAL"
+	CodeUnavailable     = "ERR_UNAVAILABLE"
+	CodeTimeout         = "ERR_TIMEOUT"
+)
+
+// CodeInfo describes a registered error code
+type CodeInfo struct {
+	Code        string `json:"code"`        // Stable machine-readable code, e.g. ERR_NOT_FOUND
+	HTTPStatus  int    `json:"http_status"` // HTTP status the code is returned with
+	Description string `json:"description"` // What the code means, for API documentation
+}
+
+// codeRegistry holds the known error codes
+var codeRegistry = struct {
+	sync.RWMutex
+	codes map[string]CodeInfo
+}{codes: map[string]CodeInfo{
+	CodeBadRequest:      {CodeBadRequest, http.StatusBadRequest, "The request is malformed"},
+	CodeValidation:      {CodeValidation, http.StatusBadRequest, "One or more fields are invalid"},
+	CodeUnauthorized:    {CodeUnauthorized, http.StatusUnauthorized, "Authentication is missing or invalid"},
+	CodeForbidden:       {CodeForbidden, http.StatusForbidden, "The caller may not perform this action"},
+	CodeNotFound:        {CodeNotFound, http.StatusNotFound, "The resource does not exist"},
+	CodeConflict:        {CodeConflict, http.StatusConflict, "The request conflicts with the current state"},
+	CodePayloadTooLarge: {CodePayloadTooLarge, http.StatusRequestEntityTooLarge, "The request body is too large"},
+	CodeRateLimited:     {CodeRateLimited, http.StatusTooManyRequests, "Too many requests; retry later"},
+	CodeInternal:        {CodeInternal, http.StatusInternalServerError, "An unexpected error occurred"},
+	CodeUnavailable:     {CodeUnavailable, http.StatusServiceUnavailable, "The service is temporarily unavailable"},
+	CodeTimeout:         {CodeTimeout, http.StatusGatewayTimeout, "A dependency did not respond in time"},
+}}
+
+// RegisterCode adds a service-specific error code, e.g. ERR_INSUFFICIENT_FUNDS.
+// Registering a code again with the same HTTP status updates its description;
+// a different status is an error, since clients may already rely on it. Call
+// it during initialization.
+func RegisterCode(info CodeInfo) error {
+	if info.Code == "" {
+		return fmt.Errorf("error code is required")
+	}
+	if info.HTTPStatus < 400 || info.HTTPStatus > 599 {
+		return fmt.Errorf("error code %s needs a 4xx or 5xx HTTP status, got %d", info.Code, info.HTTPStatus)
+	}
+
+	codeRegistry.Lock()
+	defer codeRegistry.Unlock()
+	if existing, ok := codeRegistry.codes[info.Code]; ok && existing.HTTPStatus != info.HTTPStatus {
+		return fmt.Errorf("error code %s is already registered with HTTP status %d", info.Code, existing.HTTPStatus)
+	}
+	codeRegistry.codes[info.Code] = info
+	return nil
+}
+
+// LookupCode returns the registered information for code
+func LookupCode(code string) (CodeInfo, bool) {
+	codeRegistry.RLock()
+	defer codeRegistry.RUnlock()
+	info, ok := codeRegistry.codes[code]
+	return info, ok
+}
+
+// HTTPStatusForCode returns the HTTP status registered for code, or 500 for
+// unknown codes
+func HTTPStatusForCode(code string) int {
+	if info, ok := LookupCode(code); ok {
+		return info.HTTPStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// Codes returns every registered code sorted by code, e.g. to publish them in
+// API documentation
+func Codes() []CodeInfo {
+	codeRegistry.RLock()
+	defer codeRegistry.RUnlock()
+	codes := make([]CodeInfo, 0, len(codeRegistry.codes))
+	for _, info := range codeRegistry.codes {
+		codes = append(codes, info)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i].Code < codes[j].Code })
+	return codes
+}
+
+// NewErrorResponseWithCode creates an error response with a machine-readable
+// code. The status is StatusReject for codes registered with a 4xx HTTP
+// status and StatusFailure otherwise, matching HTTPStatusForCode.
+//
+// Example:
+//
+//	resp := response.NewErrorResponseWithCode(response.CodeNotFound, "User not found")
+//	w.WriteHeader(response.HTTPStatusForCode(resp.Code))
+func NewErrorResponseWithCode(code string, message string) Response {
+	status := StatusFailure
+	if HTTPStatusForCode(code) < http.StatusInternalServerError {
+		status = StatusReject
+	}
+	return Response{
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+}
@@ -0,0 +1,64 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestNewErrorResponseWithCode(t *testing.T) {
+	tests := []struct {
+		code   string
+		status string
+		http   int
+	}{
+		{CodeNotFound, StatusReject, http.StatusNotFound},
+		{CodeRateLimited, StatusReject, http.StatusTooManyRequests},
+		{CodeUnavailable, StatusFailure, http.StatusServiceUnavailable},
+		{"ERR_UNKNOWN", StatusFailure, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		resp := NewErrorResponseWithCode(tt.code, "message")
+		if resp.Status != tt.status || resp.Code != tt.code || HTTPStatusForCode(tt.code) != tt.http {
+			t.Errorf("%s: unexpected response %+v (HTTP %d)", tt.code, resp, HTTPStatusForCode(tt.code))
+		}
+	}
+
+	data, _ := json.Marshal(NewErrorResponseWithCode(CodeNotFound, "User not found"))
+	if string(data) != `{"status":"Rejected","code":"ERR_NOT_FOUND","message":"User not found"}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+	data, _ = json.Marshal(NewErrorResponse("User not found"))
+	if string(data) != `{"status":"Rejected","message":"User not found"}` {
+		t.Errorf("Expected no code field without a code, got %s", data)
+	}
+}
+
+func TestRegisterCode(t *testing.T) {
+	if err := RegisterCode(CodeInfo{Code: "ERR_INSUFFICIENT_FUNDS", HTTPStatus: http.StatusPaymentRequired, Description: "Balance too low"}); err != nil {
+		t.Fatalf("RegisterCode failed: %v", err)
+	}
+	if info, ok := LookupCode("ERR_INSUFFICIENT_FUNDS"); !ok || info.HTTPStatus != http.StatusPaymentRequired {
+		t.Errorf("Unexpected lookup %+v", info)
+	}
+	if err := RegisterCode(CodeInfo{Code: "ERR_INSUFFICIENT_FUNDS", HTTPStatus: http.StatusPaymentRequired, Description: "Updated"}); err != nil {
+		t.Errorf("Expected re-registration with the same status to succeed, got %v", err)
+	}
+
+	for _, info := range []CodeInfo{
+		{Code: CodeNotFound, HTTPStatus: http.StatusGone},
+		{Code: "", HTTPStatus: http.StatusBadRequest},
+		{Code: "ERR_OK", HTTPStatus: http.StatusOK},
+	} {
+		if err := RegisterCode(info); err == nil {
+			t.Errorf("Expected %+v to fail", info)
+		}
+	}
+
+	codes := Codes()
+	for i := 1; i < len(codes); i++ {
+		if codes[i-1].Code >= codes[i].Code {
+			t.Fatal("Expected codes sorted by code")
+		}
+	}
+}
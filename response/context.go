@@ -0,0 +1,49 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/khekrn/core/internal/correlation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithContext returns resp with RequestID set to the request ID stored with
+// logger.WithRequestID and TraceID set to the ID of the OpenTelemetry span in
+// ctx, so clients can quote them to support. Fields that are already set are
+// kept.
+//
+// Example:
+//
+//	resp := response.WithContext(r.Context(), response.NewErrorResponse("Payment declined"))
+func WithContext(ctx context.Context, resp Response) Response {
+	if resp.RequestID == "" {
+		resp.RequestID = correlation.RequestIDFromContext(ctx)
+	}
+	if resp.TraceID == "" {
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+			resp.TraceID = spanContext.TraceID().String()
+		}
+	}
+	return resp
+}
+
+// Write writes resp as a JSON response with the given HTTP status, adding the
+// request and trace IDs from the request context with WithContext
+//
+// Example:
+//
+//	response.Write(w, r, http.StatusCreated, response.NewSuccessResponse("User created", user))
+func Write(w http.ResponseWriter, r *http.Request, status int, resp Response) error {
+	body, err := json.Marshal(WithContext(r.Context(), resp))
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(body)
+	return err
+}
@@ -0,0 +1,62 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khekrn/core/internal/correlation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithContext(t *testing.T) {
+	ctx := correlation.With(context.Background(), correlation.RequestID, "req-1")
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	resp := WithContext(ctx, NewErrorResponse("Payment declined"))
+	if resp.RequestID != "req-1" || resp.TraceID != traceID.String() {
+		t.Errorf("Unexpected IDs %+v", resp)
+	}
+
+	kept := WithContext(ctx, Response{Status: StatusReject, RequestID: "upstream"})
+	if kept.RequestID != "upstream" {
+		t.Errorf("Expected an existing request ID to be kept, got %q", kept.RequestID)
+	}
+
+	legacy := WithContext(context.WithValue(context.Background(), "RequestID", "req-legacy"), Response{})
+	if legacy.RequestID != "req-legacy" || legacy.TraceID != "" {
+		t.Errorf("Unexpected IDs from a legacy context %+v", legacy)
+	}
+
+	data, _ := json.Marshal(NewErrorResponse("missing"))
+	if string(data) != `{"status":"Rejected","message":"missing"}` {
+		t.Errorf("Expected no ID fields without a context, got %s", data)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/orders/7", nil)
+	req = req.WithContext(correlation.With(req.Context(), correlation.RequestID, "req-2"))
+
+	rec := httptest.NewRecorder()
+	if err := WriteError(rec, req, ErrNotFound); err != nil {
+		t.Fatalf("WriteError failed: %v", err)
+	}
+	var resp Response
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if rec.Code != http.StatusNotFound || resp.RequestID != "req-2" || resp.Code != CodeNotFound {
+		t.Errorf("Unexpected response %d %+v", rec.Code, resp)
+	}
+	if rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+
+	problem := ProblemFromResponse(resp, 0)
+	if problem.Extensions["request_id"] != "req-2" || problem.Response().RequestID != "req-2" {
+		t.Errorf("Expected the request ID in the problem, got %+v", problem)
+	}
+}
@@ -0,0 +1,62 @@
+// Package echoresp renders the standard response envelope from Echo handlers.
+//
+// It depends only on the JSON method of echo.Context, so importing it does
+// not add Echo to the module's dependencies.
+//
+// Example usage:
+//
+//	e.GET("/users/:id", func(c echo.Context) error {
+//		user, err := store.Find(c.Param("id"))
+//		if err != nil {
+//			return echoresp.ErrorWithCode(c, response.CodeNotFound, "User not found")
+//		}
+//		return echoresp.Success(c, user)
+//	})
+package echoresp
+
+import (
+	"net/http"
+
+	"github.com/khekrn/core/response"
+)
+
+// Context is the part of echo.Context used to render responses
+type Context interface {
+	JSON(code int, i any) error
+}
+
+// Respond renders resp with the given HTTP status
+func Respond(c Context, status int, resp response.Response) error {
+	return c.JSON(status, resp)
+}
+
+// Success renders data in a successful response with status 200
+func Success(c Context, data any) error {
+	return Respond(c, http.StatusOK, response.NewSuccessResponse("", data))
+}
+
+// Created renders data in a successful response with status 201
+func Created(c Context, data any) error {
+	return Respond(c, http.StatusCreated, response.NewSuccessResponse("", data))
+}
+
+// Error renders an error response with the given HTTP status. 5xx statuses
+// produce StatusFailure and others StatusReject.
+func Error(c Context, status int, message string) error {
+	resp := response.NewErrorResponse(message)
+	if status >= http.StatusInternalServerError {
+		resp.Status = response.StatusFailure
+	}
+	return Respond(c, status, resp)
+}
+
+// ErrorWithCode renders an error response with a machine-readable code and
+// the HTTP status registered for it
+func ErrorWithCode(c Context, code string, message string) error {
+	return Respond(c, response.HTTPStatusForCode(code), response.NewErrorResponseWithCode(code, message))
+}
+
+// ValidationErrors renders a validation error response with status 400
+func ValidationErrors(c Context, message string, errs ...response.ValidationError) error {
+	return Respond(c, http.StatusBadRequest, response.NewErrorResponseWithValidationErrors(message, errs...))
+}
@@ -0,0 +1,47 @@
+package echoresp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/khekrn/core/response"
+)
+
+// fakeContext records the rendered response like echo.Context
+type fakeContext struct {
+	status int
+	body   any
+}
+
+func (c *fakeContext) JSON(code int, i any) error {
+	c.status = code
+	c.body = i
+	return nil
+}
+
+func TestHelpers(t *testing.T) {
+	tests := []struct {
+		name   string
+		render func(Context) error
+		status int
+		resp   string
+	}{
+		{"success", func(c Context) error { return Success(c, "data") }, http.StatusOK, response.StatusAccept},
+		{"created", func(c Context) error { return Created(c, "data") }, http.StatusCreated, response.StatusAccept},
+		{"error", func(c Context) error { return Error(c, http.StatusBadGateway, "upstream") }, http.StatusBadGateway, response.StatusFailure},
+		{"code", func(c Context) error { return ErrorWithCode(c, response.CodeRateLimited, "slow down") }, http.StatusTooManyRequests, response.StatusReject},
+		{"validation", func(c Context) error {
+			return ValidationErrors(c, "invalid", response.ValidationError{Field: "email", Reason: "Required"})
+		}, http.StatusBadRequest, response.StatusReject},
+	}
+	for _, tt := range tests {
+		c := &fakeContext{}
+		if err := tt.render(c); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		resp, ok := c.body.(response.Response)
+		if c.status != tt.status || !ok || resp.Status != tt.resp {
+			t.Errorf("%s: unexpected %d %+v", tt.name, c.status, c.body)
+		}
+	}
+}
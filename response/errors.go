@@ -0,0 +1,167 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors mapped by FromError. Wrap them to add context, e.g.
+// fmt.Errorf("user %s: %w", id, response.ErrNotFound).
+var (
+	ErrBadRequest   = errors.New("response: bad request")
+	ErrValidation   = errors.New("response: validation failed")
+	ErrUnauthorized = errors.New("response: unauthorized")
+	ErrForbidden    = errors.New("response: forbidden")
+	ErrNotFound     = errors.New("response: not found")
+	ErrConflict     = errors.New("response: conflict")
+	ErrRateLimited  = errors.New("response: rate limited")
+	ErrUnavailable  = errors.New("response: unavailable")
+)
+
+// sentinelCodes maps the sentinel errors to their codes, in match order
+var sentinelCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrBadRequest, CodeBadRequest},
+	{ErrValidation, CodeValidation},
+	{ErrUnauthorized, CodeUnauthorized},
+	{ErrForbidden, CodeForbidden},
+	{ErrNotFound, CodeNotFound},
+	{ErrConflict, CodeConflict},
+	{ErrRateLimited, CodeRateLimited},
+	{ErrUnavailable, CodeUnavailable},
+	{context.DeadlineExceeded, CodeTimeout},
+}
+
+// FieldErrors is implemented by errors that carry field-level validation
+// failures, such as validation.Errors
+type FieldErrors interface {
+	error
+	FieldErrors() []ValidationError
+}
+
+// APIErrorMapper is implemented by errors that map themselves to an
+// APIError, such as errors.CodedError
+type APIErrorMapper interface {
+	error
+	APIError() *APIError
+}
+
+// APIError is an error that knows how it is returned to clients. Message is
+// sent to the client, while Err is the underlying cause, kept for logging.
+type APIError struct {
+	HTTPStatus int    // HTTP status code
+	Code       string // Machine-readable error code
+	Message    string // Client-facing message
+	Data       any    // Optional response data, e.g. validation errors
+	Err        error  // Wrapped cause
+}
+
+// NewAPIError creates an APIError with the HTTP status registered for code
+func NewAPIError(code string, message string, err error) *APIError {
+	return &APIError{
+		HTTPStatus: HTTPStatusForCode(code),
+		Code:       code,
+		Message:    message,
+		Err:        err,
+	}
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the wrapped cause
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Response returns the error as a Response. The status is StatusFailure for
+// 5xx errors and StatusReject otherwise.
+func (e *APIError) Response() Response {
+	resp := Response{
+		Status:  StatusReject,
+		Code:    e.Code,
+		Message: e.Message,
+		Data:    e.Data,
+	}
+	if e.HTTPStatus >= http.StatusInternalServerError {
+		resp.Status = StatusFailure
+	}
+	return resp
+}
+
+// FromError maps err to an APIError. An APIError in the chain is returned
+// as is; an APIErrorMapper in the chain maps itself; FieldErrors become ERR_VALIDATION with the failures as data; the
+// sentinel errors and context.DeadlineExceeded map to their codes; anything
+// else is ERR_INTERNAL. Mapped errors use the code's description as the
+// message, so internal details in err never reach clients. FromError returns
+// nil for a nil error, so only call it once err is known to be non-nil.
+//
+// Example:
+//
+//	func (h *Handler) GetUser(ctx context.Context, id string) (*User, error) {
+//		user, err := h.store.Find(ctx, id)
+//		if err != nil {
+//			return nil, response.FromError(err)
+//		}
+//		return user, nil
+//	}
+func FromError(err error) *APIError {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	var mapper APIErrorMapper
+	if errors.As(err, &mapper) {
+		return mapper.APIError()
+	}
+
+	var fieldErrs FieldErrors
+	if errors.As(err, &fieldErrs) {
+		mapped := NewAPIError(CodeValidation, "Validation failed", err)
+		mapped.Data = fieldErrs.FieldErrors()
+		return mapped
+	}
+
+	code := CodeInternal
+	for _, sentinel := range sentinelCodes {
+		if errors.Is(err, sentinel.err) {
+			code = sentinel.code
+			break
+		}
+	}
+	info, _ := LookupCode(code)
+	return NewAPIError(code, info.Description, err)
+}
+
+// WriteError maps err with FromError and writes it with Write. A nil err is
+// written as ERR_INTERNAL, since reaching an error path without an error is
+// a bug.
+//
+// Example:
+//
+//	if err != nil {
+//		response.WriteError(w, r, err)
+//		return
+//	}
+func WriteError(w http.ResponseWriter, r *http.Request, err error) error {
+	apiErr := FromError(err)
+	if apiErr == nil {
+		info, _ := LookupCode(CodeInternal)
+		apiErr = NewAPIError(CodeInternal, info.Description, nil)
+	}
+	return Write(w, r, apiErr.HTTPStatus, apiErr.Response())
+}
@@ -0,0 +1,63 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// testFieldErrors implements FieldErrors like validation.Errors
+type testFieldErrors []ValidationError
+
+func (e testFieldErrors) Error() string                  { return "validation failed" }
+func (e testFieldErrors) FieldErrors() []ValidationError { return e }
+
+// testMapper implements APIErrorMapper like errors.CodedError
+type testMapper struct{ code string }
+
+func (e testMapper) Error() string { return e.code }
+func (e testMapper) APIError() *APIError {
+	return &APIError{HTTPStatus: http.StatusTeapot, Code: e.code, Message: "Mapped", Err: e}
+}
+
+func TestFromError(t *testing.T) {
+	custom := &APIError{HTTPStatus: http.StatusPaymentRequired, Code: "ERR_PAYMENT", Message: "Pay up"}
+
+	tests := []struct {
+		name   string
+		err    error
+		code   string
+		http   int
+		status string
+	}{
+		{"not found", fmt.Errorf("user 7: %w", ErrNotFound), CodeNotFound, http.StatusNotFound, StatusReject},
+		{"conflict", ErrConflict, CodeConflict, http.StatusConflict, StatusReject},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), CodeTimeout, http.StatusGatewayTimeout, StatusFailure},
+		{"unknown", errors.New("pq: connection refused"), CodeInternal, http.StatusInternalServerError, StatusFailure},
+		{"api error", fmt.Errorf("charge: %w", custom), "ERR_PAYMENT", http.StatusPaymentRequired, StatusReject},
+		{"mapper", fmt.Errorf("brew: %w", testMapper{"ERR_TEAPOT"}), "ERR_TEAPOT", http.StatusTeapot, StatusReject},
+		{"validation", testFieldErrors{{Field: "email", Reason: "Required"}}, CodeValidation, http.StatusBadRequest, StatusReject},
+	}
+	for _, tt := range tests {
+		apiErr := FromError(tt.err)
+		resp := apiErr.Response()
+		if apiErr.Code != tt.code || apiErr.HTTPStatus != tt.http || resp.Status != tt.status {
+			t.Errorf("%s: unexpected %+v", tt.name, apiErr)
+		}
+		if apiErr != custom && apiErr.Unwrap() == nil {
+			t.Errorf("%s: expected the cause to be wrapped", tt.name)
+		}
+	}
+
+	if apiErr := FromError(errors.New("pq: password authentication failed")); apiErr.Message != "An unexpected error occurred" {
+		t.Errorf("Expected internal details to be hidden, got %q", apiErr.Message)
+	}
+	if apiErr := FromError(testFieldErrors{{Field: "email", Reason: "Required"}}); len(apiErr.Response().Data.([]ValidationError)) != 1 {
+		t.Errorf("Expected validation errors as data, got %+v", apiErr.Data)
+	}
+	if FromError(nil) != nil {
+		t.Error("Expected nil for a nil error")
+	}
+}
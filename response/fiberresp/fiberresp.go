@@ -0,0 +1,69 @@
+// Package fiberresp renders the standard response envelope from Fiber
+// handlers.
+//
+// It depends only on the SendStatus and JSON methods of *fiber.Ctx (Fiber
+// v2.50 and later, and v3), so importing it does not add Fiber to the
+// module's dependencies.
+//
+// Example usage:
+//
+//	app.Get("/users/:id", func(c *fiber.Ctx) error {
+//		user, err := store.Find(c.Params("id"))
+//		if err != nil {
+//			return fiberresp.ErrorWithCode(c, response.CodeNotFound, "User not found")
+//		}
+//		return fiberresp.Success(c, user)
+//	})
+package fiberresp
+
+import (
+	"net/http"
+
+	"github.com/khekrn/core/response"
+)
+
+// Context is the part of *fiber.Ctx used to render responses
+type Context interface {
+	SendStatus(status int) error
+	JSON(data any, ctype ...string) error
+}
+
+// Respond renders resp with the given HTTP status. The body written by
+// SendStatus is replaced by the JSON envelope.
+func Respond(c Context, status int, resp response.Response) error {
+	if err := c.SendStatus(status); err != nil {
+		return err
+	}
+	return c.JSON(resp)
+}
+
+// Success renders data in a successful response with status 200
+func Success(c Context, data any) error {
+	return Respond(c, http.StatusOK, response.NewSuccessResponse("", data))
+}
+
+// Created renders data in a successful response with status 201
+func Created(c Context, data any) error {
+	return Respond(c, http.StatusCreated, response.NewSuccessResponse("", data))
+}
+
+// Error renders an error response with the given HTTP status. 5xx statuses
+// produce StatusFailure and others StatusReject.
+func Error(c Context, status int, message string) error {
+	resp := response.NewErrorResponse(message)
+	if status >= http.StatusInternalServerError {
+		resp.Status = response.StatusFailure
+	}
+	return Respond(c, status, resp)
+}
+
+// ErrorWithCode renders an error response with a machine-readable code and
+// the HTTP status registered for it
+func ErrorWithCode(c Context, code string, message string) error {
+	return Respond(c, response.HTTPStatusForCode(code), response.NewErrorResponseWithCode(code, message))
+}
+
+// ValidationErrors renders a validation error response with status 400
+func ValidationErrors(c Context, message string, errs ...response.ValidationError) error {
+	return Respond(c, http.StatusBadRequest, response.NewErrorResponseWithValidationErrors(message, errs...))
+}
@@ -0,0 +1,58 @@
+package fiberresp
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/khekrn/core/response"
+)
+
+// fakeContext records the rendered response like *fiber.Ctx
+type fakeContext struct {
+	status int
+	body   any
+	err    error
+}
+
+func (c *fakeContext) SendStatus(status int) error {
+	c.status = status
+	return c.err
+}
+
+func (c *fakeContext) JSON(data any, ctype ...string) error {
+	c.body = data
+	return nil
+}
+
+func TestHelpers(t *testing.T) {
+	tests := []struct {
+		name   string
+		render func(Context) error
+		status int
+		resp   string
+	}{
+		{"success", func(c Context) error { return Success(c, "data") }, http.StatusOK, response.StatusAccept},
+		{"created", func(c Context) error { return Created(c, "data") }, http.StatusCreated, response.StatusAccept},
+		{"error", func(c Context) error { return Error(c, http.StatusServiceUnavailable, "down") }, http.StatusServiceUnavailable, response.StatusFailure},
+		{"code", func(c Context) error { return ErrorWithCode(c, response.CodeConflict, "exists") }, http.StatusConflict, response.StatusReject},
+		{"validation", func(c Context) error {
+			return ValidationErrors(c, "invalid", response.ValidationError{Field: "email", Reason: "Required"})
+		}, http.StatusBadRequest, response.StatusReject},
+	}
+	for _, tt := range tests {
+		c := &fakeContext{}
+		if err := tt.render(c); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		resp, ok := c.body.(response.Response)
+		if c.status != tt.status || !ok || resp.Status != tt.resp {
+			t.Errorf("%s: unexpected %d %+v", tt.name, c.status, c.body)
+		}
+	}
+
+	failing := &fakeContext{err: errors.New("closed")}
+	if err := Success(failing, "data"); err == nil || failing.body != nil {
+		t.Error("Expected a SendStatus error to stop rendering")
+	}
+}
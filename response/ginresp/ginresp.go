@@ -0,0 +1,63 @@
+// Package ginresp renders the standard response envelope from Gin handlers.
+//
+// It depends only on the JSON method of *gin.Context, so importing it does
+// not add Gin to the module's dependencies.
+//
+// Example usage:
+//
+//	r.GET("/users/:id", func(c *gin.Context) {
+//		user, err := store.Find(c.Param("id"))
+//		if err != nil {
+//			ginresp.ErrorWithCode(c, response.CodeNotFound, "User not found")
+//			return
+//		}
+//		ginresp.Success(c, user)
+//	})
+package ginresp
+
+import (
+	"net/http"
+
+	"github.com/khekrn/core/response"
+)
+
+// Context is the part of *gin.Context used to render responses
+type Context interface {
+	JSON(code int, obj any)
+}
+
+// Respond renders resp with the given HTTP status
+func Respond(c Context, status int, resp response.Response) {
+	c.JSON(status, resp)
+}
+
+// Success renders data in a successful response with status 200
+func Success(c Context, data any) {
+	Respond(c, http.StatusOK, response.NewSuccessResponse("", data))
+}
+
+// Created renders data in a successful response with status 201
+func Created(c Context, data any) {
+	Respond(c, http.StatusCreated, response.NewSuccessResponse("", data))
+}
+
+// Error renders an error response with the given HTTP status. 5xx statuses
+// produce StatusFailure and others StatusReject.
+func Error(c Context, status int, message string) {
+	resp := response.NewErrorResponse(message)
+	if status >= http.StatusInternalServerError {
+		resp.Status = response.StatusFailure
+	}
+	Respond(c, status, resp)
+}
+
+// ErrorWithCode renders an error response with a machine-readable code and
+// the HTTP status registered for it
+func ErrorWithCode(c Context, code string, message string) {
+	Respond(c, response.HTTPStatusForCode(code), response.NewErrorResponseWithCode(code, message))
+}
+
+// ValidationErrors renders a validation error response with status 400
+func ValidationErrors(c Context, message string, errs ...response.ValidationError) {
+	Respond(c, http.StatusBadRequest, response.NewErrorResponseWithValidationErrors(message, errs...))
+}
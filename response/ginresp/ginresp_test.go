@@ -0,0 +1,44 @@
+package ginresp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/khekrn/core/response"
+)
+
+// fakeContext records the rendered response like *gin.Context
+type fakeContext struct {
+	status int
+	body   any
+}
+
+func (c *fakeContext) JSON(code int, obj any) {
+	c.status = code
+	c.body = obj
+}
+
+func TestHelpers(t *testing.T) {
+	tests := []struct {
+		name   string
+		render func(Context)
+		status int
+		resp   string
+	}{
+		{"success", func(c Context) { Success(c, "data") }, http.StatusOK, response.StatusAccept},
+		{"created", func(c Context) { Created(c, "data") }, http.StatusCreated, response.StatusAccept},
+		{"error", func(c Context) { Error(c, http.StatusBadGateway, "upstream") }, http.StatusBadGateway, response.StatusFailure},
+		{"code", func(c Context) { ErrorWithCode(c, response.CodeNotFound, "missing") }, http.StatusNotFound, response.StatusReject},
+		{"validation", func(c Context) {
+			ValidationErrors(c, "invalid", response.ValidationError{Field: "email", Reason: "Required"})
+		}, http.StatusBadRequest, response.StatusReject},
+	}
+	for _, tt := range tests {
+		c := &fakeContext{}
+		tt.render(c)
+		resp, ok := c.body.(response.Response)
+		if c.status != tt.status || !ok || resp.Status != tt.resp {
+			t.Errorf("%s: unexpected %d %+v", tt.name, c.status, c.body)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package response
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type contextKey int
+
+const localeKey contextKey = iota
+
+// catalog holds the registered messages per locale
+var catalog = struct {
+	sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]string
+}{defaultLocale: "en", messages: map[string]map[string]string{}}
+
+// RegisterMessages adds messages for a locale such as "en" or "pt-BR", keyed
+// by message key. Messages are format strings for the arguments passed to T.
+// Call it during initialization.
+//
+// Example:
+//
+//	response.RegisterMessages("en", map[string]string{"user.not_found": "User %s not found"})
+//	response.RegisterMessages("de", map[string]string{"user.not_found": "Benutzer %s nicht gefunden"})
+func RegisterMessages(locale string, messages map[string]string) {
+	locale = normalizeLocale(locale)
+	catalog.Lock()
+	defer catalog.Unlock()
+	if catalog.messages[locale] == nil {
+		catalog.messages[locale] = make(map[string]string, len(messages))
+	}
+	for key, message := range messages {
+		catalog.messages[locale][key] = message
+	}
+}
+
+// SetDefaultLocale sets the locale used when none of the context's locales
+// has a message. It is "en" by default.
+func SetDefaultLocale(locale string) {
+	catalog.Lock()
+	catalog.defaultLocale = normalizeLocale(locale)
+	catalog.Unlock()
+}
+
+// WithLocale returns a context carrying the preferred locales, most
+// preferred first
+func WithLocale(ctx context.Context, locales ...string) context.Context {
+	return context.WithValue(ctx, localeKey, locales)
+}
+
+// LocalesFromContext returns the locales stored with WithLocale, or nil
+func LocalesFromContext(ctx context.Context) []string {
+	locales, _ := ctx.Value(localeKey).([]string)
+	return locales
+}
+
+// LocaleMiddleware stores the locales of the Accept-Language header in the
+// request context for T
+func LocaleMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if locales := ParseAcceptLanguage(r.Header.Get("Accept-Language")); len(locales) > 0 {
+			r = r.WithContext(WithLocale(r.Context(), locales...))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header
+// ordered by quality, e.g. ["pt-BR", "pt", "en"] for "pt-BR,pt;q=0.9,en;q=0.8".
+// Wildcards and locales with quality 0 are skipped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale  string
+		quality float64
+	}
+
+	var parsed []weighted
+	for _, part := range strings.Split(header, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale = strings.TrimSpace(locale)
+		if locale == "" || locale == "*" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			value, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = value
+		}
+		if quality > 0 {
+			parsed = append(parsed, weighted{locale, quality})
+		}
+	}
+
+	sort.SliceStable(parsed, func(i, j int) bool { return parsed[i].quality > parsed[j].quality })
+	locales := make([]string, len(parsed))
+	for i, p := range parsed {
+		locales[i] = p.locale
+	}
+	return locales
+}
+
+// T returns the message for key in the best locale of ctx, formatted with
+// args. Each locale of the context is tried, then its base language (pt for
+// pt-BR), then the default locale. The key itself is returned when no locale
+// has the message.
+//
+// Example:
+//
+//	resp := response.NewErrorResponseWithCode(response.CodeNotFound, response.T(ctx, "user.not_found", id))
+func T(ctx context.Context, key string, args ...any) string {
+	catalog.RLock()
+	message, ok := lookupMessage(append(LocalesFromContext(ctx), catalog.defaultLocale), key)
+	catalog.RUnlock()
+
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// lookupMessage returns the message for key in the first locale that has it,
+// trying each locale's base language after the locale itself. The caller
+// holds the catalog lock.
+func lookupMessage(locales []string, key string) (string, bool) {
+	for _, locale := range locales {
+		locale = normalizeLocale(locale)
+		if message, ok := catalog.messages[locale][key]; ok {
+			return message, true
+		}
+		if base, _, found := strings.Cut(locale, "-"); found {
+			if message, ok := catalog.messages[base][key]; ok {
+				return message, true
+			}
+		}
+	}
+	return "", false
+}
+
+// normalizeLocale lowercases a locale and uses dashes, so "pt_BR" and
+// "pt-br" match "pt-BR"
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
@@ -0,0 +1,68 @@
+package response
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestT(t *testing.T) {
+	RegisterMessages("en", map[string]string{"test.not_found": "User %s not found", "test.only_en": "English only"})
+	RegisterMessages("pt", map[string]string{"test.not_found": "Usuário %s não encontrado"})
+	RegisterMessages("pt_BR", map[string]string{"test.greeting": "Oi"})
+
+	ctx := WithLocale(context.Background(), "pt-BR")
+	tests := []struct {
+		ctx      context.Context
+		key      string
+		args     []any
+		expected string
+	}{
+		{ctx, "test.greeting", nil, "Oi"},
+		{ctx, "test.not_found", []any{"7"}, "Usuário 7 não encontrado"},
+		{ctx, "test.only_en", nil, "English only"},
+		{ctx, "test.missing", nil, "test.missing"},
+		{context.Background(), "test.not_found", []any{"7"}, "User 7 not found"},
+	}
+	for _, tt := range tests {
+		if got := T(tt.ctx, tt.key, tt.args...); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.key, tt.expected, got)
+		}
+	}
+
+	SetDefaultLocale("pt")
+	defer SetDefaultLocale("en")
+	if got := T(context.Background(), "test.not_found", "7"); got != "Usuário 7 não encontrado" {
+		t.Errorf("Expected the default locale to be used, got %q", got)
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := map[string][]string{
+		"pt-BR,pt;q=0.9,en;q=0.8": {"pt-BR", "pt", "en"},
+		"en;q=0.5, de":            {"de", "en"},
+		"fr;q=0, *, es;q=bad":     {},
+		"":                        {},
+	}
+	for header, expected := range tests {
+		if got := ParseAcceptLanguage(header); !slices.Equal(got, expected) {
+			t.Errorf("%q: expected %v, got %v", header, expected, got)
+		}
+	}
+}
+
+func TestLocaleMiddleware(t *testing.T) {
+	var locales []string
+	handler := LocaleMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locales = LocalesFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "de-CH, de;q=0.9")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if !slices.Equal(locales, []string{"de-CH", "de"}) {
+		t.Errorf("Unexpected locales %v", locales)
+	}
+}
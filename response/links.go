@@ -0,0 +1,69 @@
+package response
+
+import "net/http"
+
+// Common link relations
+const (
+	RelSelf  = "self"
+	RelNext  = "next"
+	RelPrev  = "prev"
+	RelFirst = "first"
+	RelLast  = "last"
+)
+
+// Link is a hypermedia link to a related resource or action
+type Link struct {
+	Href   string `json:"href"`             // Target URL
+	Rel    string `json:"rel,omitempty"`    // Relation of the target to the response
+	Method string `json:"method,omitempty"` // HTTP method to use, GET when empty
+}
+
+// WithLink returns resp with link added under rel. The link's Rel defaults
+// to rel.
+//
+// Example:
+//
+//	resp = response.WithLink(resp, "cancel", response.Link{Href: "/orders/7/cancel", Method: http.MethodPost})
+func WithLink(resp Response, rel string, link Link) Response {
+	if link.Rel == "" {
+		link.Rel = rel
+	}
+	links := make(map[string]Link, len(resp.Links)+1)
+	for existing, l := range resp.Links {
+		links[existing] = l
+	}
+	links[rel] = link
+	resp.Links = links
+	return resp
+}
+
+// WithSelfLink returns resp with a link to the current resource
+func WithSelfLink(resp Response, href string) Response {
+	return WithLink(resp, RelSelf, Link{Href: href, Method: http.MethodGet})
+}
+
+// WithNextLink returns resp with a link to the next page of a collection.
+// An empty href, e.g. on the last page, leaves resp unchanged.
+//
+// Example:
+//
+//	resp := response.NewPageResponse("Users", page)
+//	resp = response.WithSelfLink(resp, r.URL.String())
+//	if page.Meta.HasMore {
+//		resp = response.WithNextLink(resp, "/users?after="+page.Meta.NextCursor)
+//	}
+func WithNextLink(resp Response, href string) Response {
+	if href == "" {
+		return resp
+	}
+	return WithLink(resp, RelNext, Link{Href: href, Method: http.MethodGet})
+}
+
+// WithPrevLink returns resp with a link to the previous page of a collection.
+// An empty href, e.g. on the first page, leaves resp unchanged.
+func WithPrevLink(resp Response, href string) Response {
+	if href == "" {
+		return resp
+	}
+	return WithLink(resp, RelPrev, Link{Href: href, Method: http.MethodGet})
+}
@@ -0,0 +1,32 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestLinks(t *testing.T) {
+	original := NewPageResponse("Users", Page[string]{Items: []string{"a"}})
+	resp := WithSelfLink(original, "/users?limit=1")
+	resp = WithNextLink(resp, "/users?limit=1&after=abc")
+	resp = WithPrevLink(resp, "")
+	resp = WithLink(resp, "create", Link{Href: "/users", Method: http.MethodPost})
+
+	if original.Links != nil {
+		t.Error("Expected the original response to be unchanged")
+	}
+	if len(resp.Links) != 3 || resp.Links[RelNext].Rel != RelNext || resp.Links["create"].Method != http.MethodPost {
+		t.Errorf("Unexpected links %+v", resp.Links)
+	}
+
+	data, _ := json.Marshal(WithSelfLink(NewSuccessResponse("ok", nil), "/users/7"))
+	if string(data) != `{"status":"Accepted","message":"ok","links":{"self":{"href":"/users/7","rel":"self","method":"GET"}}}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	built := New().Link(RelSelf, Link{Href: "/orders/7"}).Build()
+	if built.Links[RelSelf].Href != "/orders/7" {
+		t.Errorf("Unexpected builder links %+v", built.Links)
+	}
+}
@@ -0,0 +1,29 @@
+package response
+
+// Meta holds cursor or offset pagination metadata
+type Meta struct {
+	Limit      int    `json:"limit"`                 // Maximum number of items per page
+	HasMore    bool   `json:"has_more"`              // Whether more items exist in the paging direction
+	NextCursor string `json:"next_cursor,omitempty"` // Cursor for the following page
+	PrevCursor string `json:"prev_cursor,omitempty"` // Cursor for the preceding page
+	Offset     int    `json:"offset,omitempty"`      // Offset of the first item, for offset pagination
+	Total      *int64 `json:"total,omitempty"`       // Total item count, when cheap to compute
+}
+
+// Page represents a single page of items with its pagination metadata
+type Page[T any] struct {
+	Items []T  `json:"items"` // Items in the page
+	Meta  Meta `json:"meta"`  // Pagination metadata
+}
+
+// NewPageResponse creates a successful response with a page of items in the Data field
+func NewPageResponse[T any](message string, page Page[T]) Response {
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	return Response{
+		Status:  StatusAccept,
+		Message: message,
+		Data:    page,
+	}
+}
@@ -0,0 +1,186 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// problemMembers are the members defined by RFC 7807, which extensions may
+// not override
+var problemMembers = map[string]bool{"type": true, "title": true, "status": true, "detail": true, "instance": true}
+
+// Problem represents an RFC 7807 problem details object. Extensions are
+// serialized as top-level members next to the standard ones.
+type Problem struct {
+	Type       string         // URI identifying the problem type; "about:blank" when empty
+	Title      string         // Short, human-readable summary of the problem type
+	Status     int            // HTTP status code
+	Detail     string         // Human-readable explanation of this occurrence
+	Instance   string         // URI identifying this occurrence
+	Extensions map[string]any // Additional members, e.g. code or errors
+}
+
+// NewProblem creates a problem with the given HTTP status, using the status
+// text as the title
+func NewProblem(status int, detail string) Problem {
+	return Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// MarshalJSON encodes the problem with its extensions as top-level members
+func (p Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		if !problemMembers[key] {
+			members[key] = value
+		}
+	}
+	if p.Type != "" {
+		members["type"] = p.Type
+	}
+	if p.Title != "" {
+		members["title"] = p.Title
+	}
+	if p.Status != 0 {
+		members["status"] = p.Status
+	}
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON decodes a problem, collecting unknown members in Extensions
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var standard struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail"`
+		Instance string `json:"instance"`
+	}
+	if err := json.Unmarshal(data, &standard); err != nil {
+		return err
+	}
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	*p = Problem{
+		Type:     standard.Type,
+		Title:    standard.Title,
+		Status:   standard.Status,
+		Detail:   standard.Detail,
+		Instance: standard.Instance,
+	}
+	for key, value := range members {
+		if problemMembers[key] {
+			continue
+		}
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]any)
+		}
+		p.Extensions[key] = value
+	}
+	return nil
+}
+
+// ProblemFromResponse converts a Response to a problem. The message becomes
+// the detail, and the code, data, request ID, and trace ID become the "code",
+// "data", "request_id", and "trace_id" extensions. A zero httpStatus is
+// derived from the code when one is set, and from the response status
+// otherwise.
+func ProblemFromResponse(resp Response, httpStatus int) Problem {
+	if httpStatus == 0 {
+		switch {
+		case resp.Code != "":
+			httpStatus = HTTPStatusForCode(resp.Code)
+		case resp.Status == StatusFailure:
+			httpStatus = http.StatusInternalServerError
+		default:
+			httpStatus = http.StatusBadRequest
+		}
+	}
+
+	problem := NewProblem(httpStatus, resp.Message)
+	extensions := map[string]any{}
+	if resp.Code != "" {
+		extensions["code"] = resp.Code
+	}
+	if resp.Data != nil {
+		extensions["data"] = resp.Data
+	}
+	if resp.RequestID != "" {
+		extensions["request_id"] = resp.RequestID
+	}
+	if resp.TraceID != "" {
+		extensions["trace_id"] = resp.TraceID
+	}
+	if len(extensions) > 0 {
+		problem.Extensions = extensions
+	}
+	return problem
+}
+
+// Response converts the problem to a Response. The status is StatusFailure
+// for 5xx problems and StatusReject otherwise, and the message is the detail,
+// falling back to the title.
+func (p Problem) Response() Response {
+	resp := Response{
+		Status:  StatusReject,
+		Message: p.Detail,
+	}
+	if p.Status >= http.StatusInternalServerError {
+		resp.Status = StatusFailure
+	}
+	if resp.Message == "" {
+		resp.Message = p.Title
+	}
+	if code, ok := p.Extensions["code"].(string); ok {
+		resp.Code = code
+	}
+	if data, ok := p.Extensions["data"]; ok {
+		resp.Data = data
+	}
+	if requestID, ok := p.Extensions["request_id"].(string); ok {
+		resp.RequestID = requestID
+	}
+	if traceID, ok := p.Extensions["trace_id"].(string); ok {
+		resp.TraceID = traceID
+	}
+	return resp
+}
+
+// WriteProblem writes the problem as the HTTP response with the
+// application/problem+json content type and the problem's status, or 500
+// when it has none
+//
+// Example:
+//
+//	resp := response.NewErrorResponseWithCode(response.CodeNotFound, "User not found")
+//	response.WriteProblem(w, response.ProblemFromResponse(resp, 0))
+func WriteProblem(w http.ResponseWriter, problem Problem) error {
+	if problem.Status == 0 {
+		problem.Status = http.StatusInternalServerError
+	}
+	body, err := json.Marshal(problem)
+	if err != nil {
+		return fmt.Errorf("failed to encode problem: %w", err)
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	_, err = w.Write(body)
+	return err
+}
@@ -0,0 +1,88 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblem_JSON(t *testing.T) {
+	problem := Problem{
+		Type:       "https://example.com/probs/out-of-credit",
+		Title:      "You do not have enough credit.",
+		Status:     http.StatusForbidden,
+		Detail:     "Your current balance is 30, but that costs 50.",
+		Instance:   "/account/12345/msgs/abc",
+		Extensions: map[string]any{"balance": 30, "status": "ignored"},
+	}
+
+	data, err := json.Marshal(problem)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var members map[string]any
+	json.Unmarshal(data, &members)
+	if members["status"] != float64(http.StatusForbidden) || members["balance"] != float64(30) {
+		t.Errorf("Unexpected members %v", members)
+	}
+
+	var decoded Problem
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Type != problem.Type || decoded.Status != problem.Status || decoded.Instance != problem.Instance {
+		t.Errorf("Unexpected decoded problem %+v", decoded)
+	}
+	if len(decoded.Extensions) != 1 || decoded.Extensions["balance"] != float64(30) {
+		t.Errorf("Unexpected extensions %v", decoded.Extensions)
+	}
+
+	data, _ = json.Marshal(NewProblem(http.StatusNotFound, ""))
+	if string(data) != `{"status":404,"title":"Not Found"}` {
+		t.Errorf("Expected empty members to be omitted, got %s", data)
+	}
+}
+
+func TestProblem_ResponseConversion(t *testing.T) {
+	resp := NewErrorResponseWithCode(CodeNotFound, "User not found")
+	problem := ProblemFromResponse(resp, 0)
+	if problem.Status != http.StatusNotFound || problem.Title != "Not Found" || problem.Detail != "User not found" {
+		t.Errorf("Unexpected problem %+v", problem)
+	}
+	if back := problem.Response(); back.Status != StatusReject || back.Code != CodeNotFound || back.Message != "User not found" {
+		t.Errorf("Unexpected round trip %+v", back)
+	}
+
+	validation := ProblemFromResponse(NewErrorResponseWithValidationErrors("Validation failed",
+		ValidationError{Field: "email", Reason: "Required"},
+	), 0)
+	if validation.Status != http.StatusBadRequest || validation.Extensions["data"] == nil {
+		t.Errorf("Unexpected validation problem %+v", validation)
+	}
+
+	failure := ProblemFromResponse(Response{Status: StatusFailure, Message: "boom"}, 0)
+	if failure.Status != http.StatusInternalServerError || failure.Response().Status != StatusFailure {
+		t.Errorf("Unexpected failure problem %+v", failure)
+	}
+
+	if msg := NewProblem(http.StatusConflict, "").Response().Message; msg != "Conflict" {
+		t.Errorf("Expected the title as message, got %q", msg)
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if err := WriteProblem(rec, NewProblem(http.StatusTooManyRequests, "Slow down")); err != nil {
+		t.Fatalf("WriteProblem failed: %v", err)
+	}
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Content-Type") != ProblemContentType {
+		t.Errorf("Unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	rec = httptest.NewRecorder()
+	WriteProblem(rec, Problem{Title: "Unknown"})
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 for a problem without status, got %d", rec.Code)
+	}
+}
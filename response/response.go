@@ -12,6 +12,9 @@
 //	// Error response
 //	resp := response.NewErrorResponse("User not found")
 //
+//	// Error response with a machine-readable code
+//	resp := response.NewErrorResponseWithCode(response.CodeNotFound, "User not found")
+//
 //	// Validation error response
 //	resp := response.NewErrorResponseWithValidationErrors("Validation failed",
 //		response.ValidationError{Field: "email", Reason: "Required"},
@@ -33,9 +36,14 @@ type ValidationError struct {
 
 // Response represents a standardized API response structure
 type Response struct {
-	Status  string `json:"status"`            // Status of the operation (Accepted/Rejected/Failed)
-	Message string `json:"message,omitempty"` // Human-readable message
-	Data    any    `json:"data,omitempty"`    // Response data or validation errors
+	Status    string          `json:"status"`               // Status of the operation (Accepted/Rejected/Failed)
+	Code      string          `json:"code,omitempty"`       // Machine-readable error code, e.g. ERR_NOT_FOUND
+	Message   string          `json:"message,omitempty"`    // Human-readable message
+	Data      any             `json:"data,omitempty"`       // Response data or validation errors
+	Meta      map[string]any  `json:"meta,omitempty"`       // Additional metadata, e.g. totals or deprecation notices
+	Links     map[string]Link `json:"links,omitempty"`      // Hypermedia links keyed by relation
+	RequestID string          `json:"request_id,omitempty"` // Request ID, set by WithContext
+	TraceID   string          `json:"trace_id,omitempty"`   // Trace ID, set by WithContext
 }
 
 // NewResponse creates a new response with the specified status, message, and data
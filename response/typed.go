@@ -0,0 +1,111 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Typed is a Response whose Data field has a concrete type, so consumers can
+// decode the envelope in one step instead of going through
+// map[string]interface{}. It has the same JSON shape as Response.
+type Typed[T any] struct {
+	Status    string          `json:"status"`               // Status of the operation (Accepted/Rejected/Failed)
+	Code      string          `json:"code,omitempty"`       // Machine-readable error code, e.g. ERR_NOT_FOUND
+	Message   string          `json:"message,omitempty"`    // Human-readable message
+	Data      T               `json:"data,omitzero"`        // Response data
+	Meta      map[string]any  `json:"meta,omitempty"`       // Additional metadata
+	Links     map[string]Link `json:"links,omitempty"`      // Hypermedia links keyed by relation
+	RequestID string          `json:"request_id,omitempty"` // Request ID, set by WithContext
+	TraceID   string          `json:"trace_id,omitempty"`   // Trace ID, set by WithContext
+}
+
+// NewTyped creates a typed response with the specified status, message, and data
+func NewTyped[T any](status string, message string, data T) Typed[T] {
+	return Typed[T]{
+		Status:  status,
+		Message: message,
+		Data:    data,
+	}
+}
+
+// NewTypedSuccess creates a successful typed response with StatusAccept
+func NewTypedSuccess[T any](message string, data T) Typed[T] {
+	return NewTyped(StatusAccept, message, data)
+}
+
+// Untyped returns the response as a Response
+func (t Typed[T]) Untyped() Response {
+	return Response{
+		Status:    t.Status,
+		Code:      t.Code,
+		Message:   t.Message,
+		Data:      t.Data,
+		Meta:      t.Meta,
+		Links:     t.Links,
+		RequestID: t.RequestID,
+		TraceID:   t.TraceID,
+	}
+}
+
+// IsSuccess reports whether the status is StatusAccept
+func (t Typed[T]) IsSuccess() bool {
+	return t.Status == StatusAccept
+}
+
+// Decode parses a response envelope with Data of type T
+//
+// Example:
+//
+//	resp, err := response.Decode[User](body)
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(resp.Data.Email)
+func Decode[T any](data []byte) (Typed[T], error) {
+	var typed Typed[T]
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return typed, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return typed, nil
+}
+
+// DecodeReader parses a response envelope with Data of type T from r, e.g.
+// an HTTP response body
+func DecodeReader[T any](r io.Reader) (Typed[T], error) {
+	var typed Typed[T]
+	if err := json.NewDecoder(r).Decode(&typed); err != nil {
+		return typed, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return typed, nil
+}
+
+// As converts a Response to a Typed response, converting Data to T through
+// JSON when it is not already a T, e.g. when it was decoded as a map
+func As[T any](resp Response) (Typed[T], error) {
+	typed := Typed[T]{
+		Status:    resp.Status,
+		Code:      resp.Code,
+		Message:   resp.Message,
+		Meta:      resp.Meta,
+		Links:     resp.Links,
+		RequestID: resp.RequestID,
+		TraceID:   resp.TraceID,
+	}
+	if data, ok := resp.Data.(T); ok {
+		typed.Data = data
+		return typed, nil
+	}
+	if resp.Data == nil {
+		return typed, nil
+	}
+
+	raw, err := json.Marshal(resp.Data)
+	if err != nil {
+		return typed, fmt.Errorf("failed to encode response data: %w", err)
+	}
+	if err := json.Unmarshal(raw, &typed.Data); err != nil {
+		return typed, fmt.Errorf("failed to convert response data: %w", err)
+	}
+	return typed, nil
+}
@@ -0,0 +1,74 @@
+package response
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type testUser struct {
+	ID    int    `json:"id"`
+	Email string `json:"email"`
+}
+
+func TestTyped_RoundTrip(t *testing.T) {
+	typed := NewTypedSuccess("User found", testUser{ID: 7, Email: "ada@example.com"})
+
+	data, err := json.Marshal(typed)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	untyped, _ := json.Marshal(typed.Untyped())
+	if string(data) != string(untyped) {
+		t.Errorf("Expected the Response JSON shape, got %s and %s", data, untyped)
+	}
+
+	decoded, err := Decode[testUser](data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !decoded.IsSuccess() || decoded.Message != "User found" || decoded.Data != typed.Data {
+		t.Errorf("Unexpected decoded response %+v", decoded)
+	}
+
+	fromReader, err := DecodeReader[testUser](strings.NewReader(string(data)))
+	if err != nil || fromReader.Data.Email != "ada@example.com" {
+		t.Errorf("Unexpected DecodeReader result %+v, %v", fromReader, err)
+	}
+}
+
+func TestTyped_OmitsZeroData(t *testing.T) {
+	data, _ := json.Marshal(NewTyped(StatusReject, "Not found", testUser{}))
+	if string(data) != `{"status":"Rejected","message":"Not found"}` {
+		t.Errorf("Expected zero data to be omitted, got %s", data)
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	if _, err := Decode[testUser]([]byte(`{"status":"Accepted","data":{"id":"seven"}}`)); err == nil {
+		t.Error("Expected a type mismatch to fail")
+	}
+	if _, err := DecodeReader[testUser](strings.NewReader(`{`)); err == nil {
+		t.Error("Expected invalid JSON to fail")
+	}
+}
+
+func TestAs(t *testing.T) {
+	var resp Response
+	json.Unmarshal([]byte(`{"status":"Accepted","data":{"id":7,"email":"ada@example.com"}}`), &resp)
+
+	typed, err := As[testUser](resp)
+	if err != nil || typed.Data.ID != 7 || typed.Status != StatusAccept {
+		t.Errorf("Unexpected As result %+v, %v", typed, err)
+	}
+
+	direct, err := As[testUser](NewSuccessResponse("ok", testUser{ID: 1}))
+	if err != nil || direct.Data.ID != 1 {
+		t.Errorf("Expected typed data to be used directly, got %+v, %v", direct, err)
+	}
+
+	empty, err := As[testUser](NewErrorResponse("missing"))
+	if err != nil || empty.Data != (testUser{}) || empty.Message != "missing" {
+		t.Errorf("Unexpected As result for empty data %+v, %v", empty, err)
+	}
+}
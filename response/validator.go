@@ -0,0 +1,74 @@
+package response
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/khekrn/core/internal/validationmsg"
+)
+
+// SetValidationMessage overrides the message for a validation tag, including
+// custom tags, for FromValidationErrors and the validation package alike. The
+// message may use {0} for the field name and {1} for the tag parameter. Call
+// it during initialization.
+//
+// Example:
+//
+//	response.SetValidationMessage("required", "{0} cannot be empty")
+//	response.SetValidationMessage("sku", "{0} must be a valid SKU")
+func SetValidationMessage(tag, message string) {
+	validationmsg.Set(tag, message)
+}
+
+// FromValidationErrors converts validator.ValidationErrors into validation
+// errors with the same readable reasons as the validation package. Fields are
+// reported by their path without the top-level struct name, e.g.
+// "address.city"; register JSONTagName on the validator so the path uses JSON
+// names. Errors of other types become a
+// single entry with the error text as reason, and nil returns nil.
+//
+// Example:
+//
+//	validate := validator.New()
+//	validate.RegisterTagNameFunc(response.JSONTagName)
+//
+//	if err := validate.Struct(req); err != nil {
+//		resp := response.NewErrorResponseWithValidationErrors("Validation failed",
+//			response.FromValidationErrors(err)...)
+//	}
+func FromValidationErrors(err error) []ValidationError {
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return []ValidationError{{Reason: err.Error()}}
+	}
+
+	result := make([]ValidationError, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		result = append(result, ValidationError{
+			Field:  validationmsg.Path(fe.Namespace()),
+			Reason: validationmsg.Reason(fe, nil),
+		})
+	}
+	return result
+}
+
+// JSONTagName returns the JSON name of a struct field, for use with
+// validator.Validate.RegisterTagNameFunc. Fields tagged json:"-" return an
+// empty name and untagged fields their Go name.
+func JSONTagName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
+}
@@ -0,0 +1,78 @@
+package response
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type testAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type testSignup struct {
+	Email   string      `json:"email" validate:"required,email"`
+	Name    string      `json:"name" validate:"min=2"`
+	Age     int         `json:"age" validate:"gte=18"`
+	Tags    []string    `json:"tags" validate:"max=1"`
+	Plan    string      `json:"plan" validate:"oneof=free pro"`
+	Address testAddress `json:"address"`
+}
+
+func TestFromValidationErrors(t *testing.T) {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterTagNameFunc(JSONTagName)
+
+	errs := FromValidationErrors(validate.Struct(testSignup{
+		Email: "not-an-email",
+		Name:  "A",
+		Age:   16,
+		Tags:  []string{"a", "b"},
+		Plan:  "gold",
+	}))
+
+	expected := map[string]string{
+		"email":        "email must be a valid email address",
+		"name":         "name must be at least 2 characters in length",
+		"age":          "age must be 18 or greater",
+		"tags":         "tags must contain at maximum 1 item",
+		"plan":         "plan must be one of [free pro]",
+		"address.city": "city is a required field",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %+v", len(expected), errs)
+	}
+	for _, e := range errs {
+		if expected[e.Field] != e.Reason {
+			t.Errorf("Field %s: expected %q, got %q", e.Field, expected[e.Field], e.Reason)
+		}
+	}
+}
+
+func TestFromValidationErrors_CustomMessages(t *testing.T) {
+	SetValidationMessage("required", "{0} cannot be empty")
+	defer SetValidationMessage("required", "{0} is a required field")
+
+	validate := validator.New()
+	validate.RegisterValidation("sku", func(fl validator.FieldLevel) bool { return false })
+	SetValidationMessage("sku", "{0} must be a valid SKU")
+
+	errs := FromValidationErrors(validate.Struct(testAddress{}))
+	if len(errs) != 1 || errs[0].Reason != "City cannot be empty" {
+		t.Errorf("Unexpected errors %+v", errs)
+	}
+	errs = FromValidationErrors(validate.Struct(struct {
+		SKU string `validate:"sku"`
+	}{}))
+	if len(errs) != 1 || errs[0].Field != "SKU" || errs[0].Reason != "SKU must be a valid SKU" {
+		t.Errorf("Unexpected errors %+v", errs)
+	}
+
+	if errs := FromValidationErrors(errors.New("bad input")); len(errs) != 1 || errs[0].Reason != "bad input" {
+		t.Errorf("Unexpected errors for a plain error %+v", errs)
+	}
+	if FromValidationErrors(nil) != nil {
+		t.Error("Expected nil for a nil error")
+	}
+}
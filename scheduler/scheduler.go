@@ -0,0 +1,481 @@
+// Package scheduler runs recurring in-process jobs on cron expressions or
+// fixed intervals.
+//
+// Jobs get per-run timeouts, optional start jitter, overlap prevention, and
+// an optional distributed lock so that only one replica of a service runs a
+// given job at a time. Every run is logged through the logger package and
+// recorded in per-job statistics, and runs get a context whose logger
+// includes the job name and a run ID.
+//
+// Example usage:
+//
+//	s := scheduler.New(scheduler.WithLocker(redisLocker))
+//
+//	// Every night at 03:00 UTC
+//	err := s.AddCron("cleanup", "0 3 * * *", cleanupJob,
+//		scheduler.WithTimeout(10*time.Minute),
+//		scheduler.WithDistributedLock(),
+//	)
+//
+//	// Every minute, spread over 10 seconds across replicas
+//	err = s.AddInterval("refresh-cache", time.Minute, refreshJob,
+//		scheduler.WithJitter(10*time.Second),
+//	)
+//
+//	// Refresh a token right away, then every 45 minutes
+//	err = s.AddInterval("refresh-token", 45*time.Minute, refreshToken,
+//		scheduler.WithRunOnStart(),
+//	)
+//
+//	s.Start()
+//	defer s.Stop(context.Background())
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/khekrn/core/idgen"
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+)
+
+// ErrJobExists is returned when adding a job with a name that is already registered
+var ErrJobExists = errors.New("scheduler: job already exists")
+
+// Job is the function executed on each run
+type Job func(ctx context.Context) error
+
+// Locker provides mutual exclusion across service replicas
+type Locker interface {
+	// TryLock attempts to acquire the named lock for at most ttl.
+	// It returns a release function and true when the lock was acquired.
+	TryLock(ctx context.Context, name string, ttl time.Duration) (release func(), acquired bool, err error)
+}
+
+// Outcome describes how a job run ended
+type Outcome string
+
+// Possible run outcomes
+const (
+	OutcomeSuccess Outcome = "success" // Job returned nil
+	OutcomeFailure Outcome = "failure" // Job returned an error or panicked
+	OutcomeSkipped Outcome = "skipped" // Previous run still active or lock held elsewhere
+)
+
+// RunResult describes a single job run
+type RunResult struct {
+	Job      string
+	RunID    string // Also the request ID in the run context; empty when skipped for overlap
+	Outcome  Outcome
+	Started  time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// JobStats holds cumulative statistics for a job
+type JobStats struct {
+	Runs         int64
+	Failures     int64
+	Skipped      int64
+	LastRun      time.Time
+	LastDuration time.Duration
+	LastError    error
+	NextRun      time.Time
+}
+
+// JobConfig holds configuration for a single job
+type JobConfig struct {
+	Timeout         time.Duration
+	Jitter          time.Duration
+	AllowOverlap    bool
+	DistributedLock bool
+	LockTTL         time.Duration
+	RunOnStart      bool
+}
+
+// JobOption is a function type for configuring jobs
+type JobOption func(*JobConfig)
+
+// WithTimeout sets the maximum duration of a single run
+func WithTimeout(timeout time.Duration) JobOption {
+	return func(config *JobConfig) {
+		config.Timeout = timeout
+	}
+}
+
+// WithJitter delays each run by a random duration up to jitter
+func WithJitter(jitter time.Duration) JobOption {
+	return func(config *JobConfig) {
+		config.Jitter = jitter
+	}
+}
+
+// WithOverlap allows a new run to start while the previous one is still active
+func WithOverlap() JobOption {
+	return func(config *JobConfig) {
+		config.AllowOverlap = true
+	}
+}
+
+// WithRunOnStart runs the job as soon as it is scheduled, before its first
+// activation, e.g. to warm a cache or fetch a token at startup. Jitter
+// applies to this run too, spreading the start across replicas.
+func WithRunOnStart() JobOption {
+	return func(config *JobConfig) {
+		config.RunOnStart = true
+	}
+}
+
+// WithDistributedLock runs the job only on the replica that acquires the
+// scheduler's Locker. The lock is held for the run timeout unless WithLockTTL is set.
+func WithDistributedLock() JobOption {
+	return func(config *JobConfig) {
+		config.DistributedLock = true
+	}
+}
+
+// WithLockTTL sets how long the distributed lock is held
+func WithLockTTL(ttl time.Duration) JobOption {
+	return func(config *JobConfig) {
+		config.LockTTL = ttl
+	}
+}
+
+// Option is a function type for configuring the scheduler
+type Option func(*Scheduler)
+
+// WithLocker sets the distributed lock implementation
+func WithLocker(locker Locker) Option {
+	return func(s *Scheduler) {
+		s.locker = locker
+	}
+}
+
+// WithLocation sets the time zone used to evaluate cron expressions
+func WithLocation(location *time.Location) Option {
+	return func(s *Scheduler) {
+		s.location = location
+	}
+}
+
+// WithRunHook sets a callback invoked after every run, e.g. to record metrics
+func WithRunHook(hook func(RunResult)) Option {
+	return func(s *Scheduler) {
+		s.onRun = hook
+	}
+}
+
+// Scheduler manages a set of recurring jobs
+type Scheduler struct {
+	mu       sync.Mutex
+	jobs     map[string]*entry
+	locker   Locker
+	location *time.Location
+	onRun    func(RunResult)
+
+	// runCtx is the parent of every run and is only cancelled on a forced stop
+	runCtx    context.Context
+	cancelRun context.CancelFunc
+	runs      sync.WaitGroup
+
+	// loopCtx stops the scheduling loops without interrupting active runs
+	loopCtx    context.Context
+	cancelLoop context.CancelFunc
+	loops      sync.WaitGroup
+}
+
+// entry is a registered job with its schedule and state
+type entry struct {
+	name     string
+	job      Job
+	schedule Schedule
+	config   JobConfig
+
+	mu      sync.Mutex
+	running int
+	stats   JobStats
+}
+
+// New creates a scheduler
+func New(options ...Option) *Scheduler {
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	s := &Scheduler{
+		jobs:      make(map[string]*entry),
+		location:  time.UTC,
+		runCtx:    runCtx,
+		cancelRun: cancelRun,
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+// AddCron registers a job that runs on a cron expression
+func (s *Scheduler) AddCron(name, expr string, job Job, options ...JobOption) error {
+	schedule, err := ParseCron(expr, s.location)
+	if err != nil {
+		return err
+	}
+	return s.Add(name, schedule, job, options...)
+}
+
+// AddInterval registers a job that runs at a fixed interval
+func (s *Scheduler) AddInterval(name string, interval time.Duration, job Job, options ...JobOption) error {
+	if interval <= 0 {
+		return fmt.Errorf("scheduler: interval must be positive")
+	}
+	return s.Add(name, IntervalSchedule{Interval: interval}, job, options...)
+}
+
+// Add registers a job with a custom schedule. Jobs added after Start begin immediately.
+func (s *Scheduler) Add(name string, schedule Schedule, job Job, options ...JobOption) error {
+	config := JobConfig{}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	if config.DistributedLock && s.locker == nil {
+		return fmt.Errorf("scheduler: job %q requires a distributed lock but no Locker is configured", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("%w: %s", ErrJobExists, name)
+	}
+
+	e := &entry{name: name, job: job, schedule: schedule, config: config}
+	s.jobs[name] = e
+
+	if s.loopCtx != nil {
+		s.loops.Add(1)
+		go s.loop(s.loopCtx, e)
+	}
+	return nil
+}
+
+// Start begins running all registered jobs
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.loopCtx != nil {
+		return
+	}
+	s.loopCtx, s.cancelLoop = context.WithCancel(context.Background())
+
+	for _, e := range s.jobs {
+		s.loops.Add(1)
+		go s.loop(s.loopCtx, e)
+	}
+}
+
+// Stop stops scheduling new runs and waits for active runs to finish.
+// If the context expires first, active runs are cancelled and the context
+// error is returned. A stopped scheduler cannot be restarted.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if s.cancelLoop != nil {
+		s.cancelLoop()
+	}
+	s.mu.Unlock()
+	s.loops.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		s.runs.Wait()
+		close(done)
+	}()
+
+	defer s.cancelRun()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns statistics for the named job
+func (s *Scheduler) Stats(name string) (JobStats, bool) {
+	s.mu.Lock()
+	e, ok := s.jobs[name]
+	s.mu.Unlock()
+
+	if !ok {
+		return JobStats{}, false
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.stats, true
+}
+
+// loop waits for each activation time of a job and dispatches a run
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	defer s.loops.Done()
+
+	next := e.schedule.Next(time.Now())
+	if e.config.RunOnStart {
+		next = time.Now()
+	}
+	for !next.IsZero() {
+		e.mu.Lock()
+		e.stats.NextRun = next
+		e.mu.Unlock()
+
+		delay := time.Until(next)
+		if e.config.Jitter > 0 {
+			delay += rand.N(e.config.Jitter)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.dispatch(e)
+
+		// Skip activations missed while the process was busy rather than bursting
+		now := time.Now()
+		if next = e.schedule.Next(next); !next.IsZero() && next.Before(now) {
+			next = e.schedule.Next(now)
+		}
+	}
+}
+
+// dispatch starts a run unless the previous one is still active
+func (s *Scheduler) dispatch(e *entry) {
+	e.mu.Lock()
+	if e.running > 0 && !e.config.AllowOverlap {
+		e.mu.Unlock()
+		s.record(e, RunResult{Job: e.name, Outcome: OutcomeSkipped, Started: time.Now(),
+			Err: errors.New("previous run still active")})
+		return
+	}
+	e.running++
+	e.mu.Unlock()
+
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+		defer func() {
+			e.mu.Lock()
+			e.running--
+			e.mu.Unlock()
+		}()
+		s.run(e)
+	}()
+}
+
+// run executes a single job run with timeout, locking and panic recovery
+func (s *Scheduler) run(e *entry) {
+	result := RunResult{Job: e.name, RunID: idgen.RequestID(), Started: time.Now()}
+
+	ctx := logger.WithRequestID(s.runCtx, result.RunID)
+	ctx = logger.AppendFields(ctx, zap.String("job", e.name))
+	if e.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.config.Timeout)
+		defer cancel()
+	}
+
+	if e.config.DistributedLock {
+		release, acquired, err := s.locker.TryLock(ctx, "scheduler:"+e.name, e.lockTTL())
+		if err != nil {
+			result.Outcome = OutcomeFailure
+			result.Err = fmt.Errorf("failed to acquire lock: %w", err)
+			s.record(e, result)
+			return
+		}
+		if !acquired {
+			result.Outcome = OutcomeSkipped
+			result.Err = errors.New("lock held by another instance")
+			s.record(e, result)
+			return
+		}
+		defer release()
+	}
+
+	result.Err = execute(ctx, e.job)
+	result.Duration = time.Since(result.Started)
+	result.Outcome = OutcomeSuccess
+	if result.Err != nil {
+		result.Outcome = OutcomeFailure
+	}
+	s.record(e, result)
+}
+
+// lockTTL returns how long the distributed lock should be held
+func (e *entry) lockTTL() time.Duration {
+	switch {
+	case e.config.LockTTL > 0:
+		return e.config.LockTTL
+	case e.config.Timeout > 0:
+		return e.config.Timeout
+	default:
+		return time.Minute
+	}
+}
+
+// execute runs the job, converting panics into errors
+func execute(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return job(ctx)
+}
+
+// record updates job statistics, logs the outcome and calls the run hook
+func (s *Scheduler) record(e *entry, result RunResult) {
+	e.mu.Lock()
+	switch result.Outcome {
+	case OutcomeSkipped:
+		e.stats.Skipped++
+	case OutcomeFailure:
+		e.stats.Runs++
+		e.stats.Failures++
+	default:
+		e.stats.Runs++
+	}
+	if result.Outcome != OutcomeSkipped {
+		e.stats.LastRun = result.Started
+		e.stats.LastDuration = result.Duration
+		e.stats.LastError = result.Err
+	}
+	e.mu.Unlock()
+
+	fields := []zap.Field{
+		zap.String("job", result.Job),
+		zap.String("outcome", string(result.Outcome)),
+		zap.Duration("duration", result.Duration),
+	}
+	if result.RunID != "" {
+		fields = append(fields, zap.String("run_id", result.RunID))
+	}
+	switch result.Outcome {
+	case OutcomeFailure:
+		logger.Error("Scheduled job failed", append(fields, zap.Error(result.Err))...)
+	case OutcomeSkipped:
+		logger.Debug("Scheduled job skipped", append(fields, zap.Error(result.Err))...)
+	default:
+		logger.Info("Scheduled job completed", fields...)
+	}
+
+	if s.onRun != nil {
+		s.onRun(result)
+	}
+}
@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/logger"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	base := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC) // Monday
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17 * * MON-FRI", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 8 * * 0", time.Date(2024, 1, 21, 8, 30, 0, 0, time.UTC)},
+		{"30 8 * * 7", time.Date(2024, 1, 21, 8, 30, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr, time.UTC)
+			if err != nil {
+				t.Fatalf("ParseCron failed: %v", err)
+			}
+			if next := schedule.Next(base); !next.Equal(tt.expected) {
+				t.Errorf("Expected next %v, got %v", tt.expected, next)
+			}
+		})
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "5-1 * * * *", "*/0 * * * *", "@every -1s"} {
+		if _, err := ParseCron(expr, time.UTC); err == nil {
+			t.Errorf("Expected error for %q", expr)
+		}
+	}
+}
+
+func TestScheduler_IntervalJob(t *testing.T) {
+	var runs atomic.Int32
+	s := New()
+
+	err := s.AddInterval("tick", 10*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("AddInterval failed: %v", err)
+	}
+
+	s.Start()
+	time.Sleep(55 * time.Millisecond)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	if runs.Load() < 3 {
+		t.Errorf("Expected at least 3 runs, got %d", runs.Load())
+	}
+
+	stats, ok := s.Stats("tick")
+	if !ok || stats.Runs != int64(runs.Load()) {
+		t.Errorf("Expected stats to record %d runs, got %+v", runs.Load(), stats)
+	}
+}
+
+func TestScheduler_PreventsOverlap(t *testing.T) {
+	var active, maxActive atomic.Int32
+	s := New()
+
+	s.AddInterval("slow", 5*time.Millisecond, func(ctx context.Context) error {
+		n := active.Add(1)
+		if n > maxActive.Load() {
+			maxActive.Store(n)
+		}
+		time.Sleep(30 * time.Millisecond)
+		active.Add(-1)
+		return nil
+	})
+
+	s.Start()
+	time.Sleep(60 * time.Millisecond)
+	s.Stop(context.Background())
+
+	if maxActive.Load() != 1 {
+		t.Errorf("Expected at most 1 concurrent run, got %d", maxActive.Load())
+	}
+	if stats, _ := s.Stats("slow"); stats.Skipped == 0 {
+		t.Error("Expected overlapping activations to be skipped")
+	}
+}
+
+func TestScheduler_DuplicateJob(t *testing.T) {
+	s := New()
+	job := func(ctx context.Context) error { return nil }
+
+	if err := s.AddInterval("job", time.Second, job); err != nil {
+		t.Fatalf("AddInterval failed: %v", err)
+	}
+	if err := s.AddInterval("job", time.Second, job); err == nil {
+		t.Error("Expected error when adding duplicate job")
+	}
+}
+
+func TestScheduler_RunOnStart(t *testing.T) {
+	runs := make(chan RunResult, 1)
+	var requestID string
+	s := New(WithRunHook(func(result RunResult) { runs <- result }))
+
+	s.AddInterval("refresh-token", time.Hour, func(ctx context.Context) error {
+		requestID = logger.RequestIDFromContext(ctx)
+		return nil
+	}, WithRunOnStart())
+
+	s.Start()
+	defer s.Stop(context.Background())
+
+	select {
+	case result := <-runs:
+		if result.Outcome != OutcomeSuccess || result.RunID == "" || result.RunID != requestID {
+			t.Errorf("Expected a successful run with its ID in the context, got %+v (context ID %q)", result, requestID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the job to run on start")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for stats, _ := s.Stats("refresh-token"); time.Until(stats.NextRun) < 59*time.Minute; stats, _ = s.Stats("refresh-token") {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the next run after one interval, got %v", stats.NextRun)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// AWSSecretsManagerProvider reads secrets from AWS Secrets Manager. Names
+// are a secret ID or ARN and an optional field, "prod/payments#api_key";
+// with a field the secret is parsed as a JSON object.
+//
+// Requests are signed by Sign, typically client.AWSSigV4Signer.Sign for
+// the "secretsmanager" service, which keeps this package free of the AWS
+// SDK.
+type AWSSecretsManagerProvider struct {
+	Region     string
+	Endpoint   string                                     // Defaults to https://secretsmanager.<region>.amazonaws.com
+	Sign       func(req *http.Request, body []byte) error // Signs each request
+	HTTPClient *http.Client                               // Defaults to http.DefaultClient
+}
+
+// NewAWSSecretsManagerProvider creates a provider for region signing
+// requests with sign
+//
+// Example:
+//
+//	signer := client.NewAWSSigV4Signer(keyID, secretKey, "eu-west-1", "secretsmanager")
+//	provider := secrets.NewAWSSecretsManagerProvider("eu-west-1", signer.Sign)
+func NewAWSSecretsManagerProvider(region string, sign func(req *http.Request, body []byte) error) *AWSSecretsManagerProvider {
+	return &AWSSecretsManagerProvider{Region: region, Sign: sign}
+}
+
+// Get returns the secret string, or the field of it
+func (p *AWSSecretsManagerProvider) Get(ctx context.Context, name string) (string, error) {
+	id, field := splitField(name)
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = "https://secretsmanager." + p.Region + ".amazonaws.com"
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": id})
+	if err != nil {
+		return "", fmt.Errorf("secrets: failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("secrets: failed to create Secrets Manager request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	if p.Sign != nil {
+		if err := p.Sign(req, body); err != nil {
+			return "", fmt.Errorf("secrets: failed to sign request: %w", err)
+		}
+	}
+
+	resp, err := httpClient(p.HTTPClient).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("secrets: Secrets Manager request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("secrets: failed to read Secrets Manager response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(content, &failure)
+		if strings.HasSuffix(failure.Type, "ResourceNotFoundException") {
+			return "", fmt.Errorf("%w: AWS secret %s", ErrNotFound, id)
+		}
+		return "", fmt.Errorf("secrets: Secrets Manager returned status %d for %s: %s %s",
+			resp.StatusCode, id, failure.Type, failure.Message)
+	}
+
+	var payload struct {
+		SecretString *string `json:"SecretString"`
+		SecretBinary string  `json:"SecretBinary"`
+	}
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return "", fmt.Errorf("secrets: invalid Secrets Manager response: %w", err)
+	}
+	value := ""
+	if payload.SecretString != nil {
+		value = *payload.SecretString
+	} else {
+		decoded, err := base64.StdEncoding.DecodeString(payload.SecretBinary)
+		if err != nil {
+			return "", fmt.Errorf("secrets: invalid binary secret %s: %w", id, err)
+		}
+		value = string(decoded)
+	}
+
+	if field == "" {
+		return value, nil
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return "", fmt.Errorf("secrets: secret %s is not a JSON object: %w", id, err)
+	}
+	return fieldValue(data, id, field)
+}
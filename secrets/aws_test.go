@@ -0,0 +1,65 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAWSSecretsManagerProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" || r.Header.Get("Authorization") != "signed" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var input struct{ SecretId string }
+		json.NewDecoder(r.Body).Decode(&input)
+		switch input.SecretId {
+		case "prod/payments":
+			w.Write([]byte(`{"Name": "prod/payments", "SecretString": "{\"api_key\": \"k-123\", \"retries\": 3}"}`))
+		case "prod/cert":
+			w.Write([]byte(`{"Name": "prod/cert", "SecretBinary": "cGVt"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type": "ResourceNotFoundException", "message": "Secrets Manager can't find the specified secret."}`))
+		}
+	}))
+	defer server.Close()
+
+	var signedBody string
+	provider := NewAWSSecretsManagerProvider("eu-west-1", func(req *http.Request, body []byte) error {
+		signedBody = string(body)
+		req.Header.Set("Authorization", "signed")
+		return nil
+	})
+	provider.Endpoint = server.URL
+	ctx := context.Background()
+
+	for _, tc := range []struct{ name, want string }{
+		{"prod/payments#api_key", "k-123"},
+		{"prod/payments#retries", "3"},
+		{"prod/payments", `{"api_key": "k-123", "retries": 3}`},
+		{"prod/cert", "pem"},
+	} {
+		if value, err := provider.Get(ctx, tc.name); err != nil || value != tc.want {
+			t.Errorf("Get(%q) = %q, %v; want %q", tc.name, value, err, tc.want)
+		}
+	}
+	if signedBody != `{"SecretId":"prod/cert"}` {
+		t.Errorf("Expected the signer to see the body, got %s", signedBody)
+	}
+
+	for _, name := range []string{"prod/missing", "prod/payments#password"} {
+		if _, err := provider.Get(ctx, name); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for %q, got %v", name, err)
+		}
+	}
+
+	provider.Sign = func(*http.Request, []byte) error { return nil }
+	if _, err := provider.Get(ctx, "prod/payments"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a status error, got %v", err)
+	}
+}
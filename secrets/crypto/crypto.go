@@ -0,0 +1,288 @@
+// Package crypto provides authenticated encryption and signing helpers for
+// protecting sensitive data at rest.
+//
+// This package offers AES-GCM encryption with key IDs embedded in the
+// ciphertext so keys can be rotated without re-encrypting existing data,
+// envelope encryption with per-message data keys, HMAC signing shared with
+// the client's request signer, and struct-tag driven field encryption for
+// PII stored in databases or caches.
+//
+// Example usage:
+//
+//	keyring, err := crypto.NewKeyring(
+//		crypto.Key{ID: "2024-06", Secret: newKey}, // primary, used for encryption
+//		crypto.Key{ID: "2023-01", Secret: oldKey}, // still accepted for decryption
+//	)
+//
+//	ciphertext, err := keyring.EncryptString("4111 1111 1111 1111")
+//	plaintext, err := keyring.DecryptString(ciphertext)
+//
+//	type Customer struct {
+//		ID    string
+//		Email string `encrypt:"true"`
+//	}
+//	err = keyring.EncryptFields(&customer)
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// formatVersion is the first byte of every ciphertext produced by this package
+const formatVersion byte = 1
+
+// Encryption errors
+var (
+	ErrUnknownKey        = errors.New("crypto: unknown key ID")
+	ErrInvalidCiphertext = errors.New("crypto: invalid ciphertext")
+	ErrInvalidKey        = errors.New("crypto: key must be 16, 24, or 32 bytes")
+)
+
+// Key is a symmetric AES key identified by ID
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// Keyring encrypts with its primary key and decrypts with any of its keys
+type Keyring struct {
+	primary string
+	aeads   map[string]cipher.AEAD
+}
+
+// NewKeyring creates a keyring whose first key is used for encryption.
+// Additional keys are only used to decrypt data written before a rotation.
+func NewKeyring(primary Key, others ...Key) (*Keyring, error) {
+	k := &Keyring{primary: primary.ID, aeads: make(map[string]cipher.AEAD)}
+
+	for _, key := range append([]Key{primary}, others...) {
+		if key.ID == "" || len(key.ID) > 255 {
+			return nil, fmt.Errorf("crypto: key ID must be 1-255 bytes")
+		}
+		aead, err := newAEAD(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key %s: %w", key.ID, err)
+		}
+		k.aeads[key.ID] = aead
+	}
+	return k, nil
+}
+
+// newAEAD creates an AES-GCM cipher for the key
+func newAEAD(secret []byte) (cipher.AEAD, error) {
+	switch len(secret) {
+	case 16, 24, 32:
+	default:
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// GenerateKey returns a random 256-bit key
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return key, nil
+}
+
+// PrimaryKeyID returns the ID of the key used for encryption
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primary
+}
+
+// Encrypt seals plaintext with the primary key. additionalData is
+// authenticated but not encrypted, and must be passed again to Decrypt;
+// binding ciphertext to e.g. a row ID prevents it from being moved between rows.
+//
+// The output layout is: version | key ID length | key ID | nonce | sealed data.
+func (k *Keyring) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	aead := k.aeads[k.primary]
+
+	header := make([]byte, 0, 2+len(k.primary)+aead.NonceSize())
+	header = append(header, formatVersion, byte(len(k.primary)))
+	header = append(header, k.primary...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	header = append(header, nonce...)
+
+	return aead.Seal(header, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt opens ciphertext produced by Encrypt with any key in the keyring
+func (k *Keyring) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	kid, rest, err := splitKeyID(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, ok := k.aeads[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce, sealed := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, additionalData)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
+
+// KeyID returns the ID of the key that encrypted ciphertext, which can be
+// used to find records that still need re-encryption after a rotation
+func KeyID(ciphertext []byte) (string, error) {
+	kid, _, err := splitKeyID(ciphertext)
+	return kid, err
+}
+
+// NeedsRotation reports whether ciphertext was encrypted with a key other than the primary
+func (k *Keyring) NeedsRotation(ciphertext []byte) bool {
+	kid, err := KeyID(ciphertext)
+	return err == nil && kid != k.primary
+}
+
+// splitKeyID parses the header of a ciphertext
+func splitKeyID(ciphertext []byte) (string, []byte, error) {
+	if len(ciphertext) < 2 || ciphertext[0] != formatVersion {
+		return "", nil, ErrInvalidCiphertext
+	}
+	n := int(ciphertext[1])
+	if len(ciphertext) < 2+n {
+		return "", nil, ErrInvalidCiphertext
+	}
+	return string(ciphertext[2 : 2+n]), ciphertext[2+n:], nil
+}
+
+// EncryptString encrypts a string and returns it base64 encoded
+func (k *Keyring) EncryptString(plaintext string) (string, error) {
+	ciphertext, err := k.Encrypt([]byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptString decrypts a base64 encoded ciphertext produced by EncryptString
+func (k *Keyring) DecryptString(ciphertext string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	plaintext, err := k.Decrypt(raw, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// KeyEncrypter wraps and unwraps data keys for envelope encryption.
+// Keyring implements it locally; a KMS client can implement it remotely.
+type KeyEncrypter interface {
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error)
+}
+
+// WrapKey encrypts a data key with the primary key
+func (k *Keyring) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	return k.Encrypt(dataKey, nil)
+}
+
+// UnwrapKey decrypts a data key wrapped by WrapKey
+func (k *Keyring) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	return k.Decrypt(wrappedKey, nil)
+}
+
+// Envelope is data encrypted with a one-time data key, stored alongside the
+// data key wrapped by a key-encryption key
+type Envelope struct {
+	WrappedKey []byte `json:"wrapped_key"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// SealEnvelope encrypts plaintext with a fresh data key and wraps the data
+// key with kek, so large payloads never leave the process and the kek
+// (e.g. in a KMS) only ever sees 32-byte keys
+func SealEnvelope(ctx context.Context, kek KeyEncrypter, plaintext, additionalData []byte) (*Envelope, error) {
+	dataKey, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := kek.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	keyring, err := NewKeyring(Key{ID: "dek", Secret: dataKey})
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := keyring.Encrypt(plaintext, additionalData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{WrappedKey: wrapped, Ciphertext: ciphertext}, nil
+}
+
+// OpenEnvelope unwraps the data key with kek and decrypts the envelope
+func OpenEnvelope(ctx context.Context, kek KeyEncrypter, envelope *Envelope, additionalData []byte) ([]byte, error) {
+	dataKey, err := kek.UnwrapKey(ctx, envelope.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	keyring, err := NewKeyring(Key{ID: "dek", Secret: dataKey})
+	if err != nil {
+		return nil, err
+	}
+	return keyring.Decrypt(envelope.Ciphertext, additionalData)
+}
+
+// Sign returns the HMAC-SHA256 of message
+func Sign(key, message []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// Verify checks an HMAC-SHA256 signature in constant time
+func Verify(key, message, signature []byte) bool {
+	return hmac.Equal(Sign(key, message), signature)
+}
+
+// SignHex returns the hex encoded HMAC-SHA256 of message
+func SignHex(key, message []byte) string {
+	return hex.EncodeToString(Sign(key, message))
+}
+
+// VerifyHex checks a hex encoded HMAC-SHA256 signature in constant time
+func VerifyHex(key, message []byte, signature string) bool {
+	decoded, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return Verify(key, message, decoded)
+}
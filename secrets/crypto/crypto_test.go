@@ -0,0 +1,111 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func newTestKeyring(t *testing.T, primary string, others ...string) *Keyring {
+	t.Helper()
+	keys := make([]Key, 0, len(others))
+	for _, id := range others {
+		keys = append(keys, Key{ID: id, Secret: bytes.Repeat([]byte(id[:1]), 32)})
+	}
+	keyring, err := NewKeyring(Key{ID: primary, Secret: bytes.Repeat([]byte(primary[:1]), 32)}, keys...)
+	if err != nil {
+		t.Fatalf("NewKeyring failed: %v", err)
+	}
+	return keyring
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	keyring := newTestKeyring(t, "a")
+
+	ciphertext, err := keyring.Encrypt([]byte("secret"), []byte("row-1"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	plaintext, err := keyring.Decrypt(ciphertext, []byte("row-1"))
+	if err != nil || string(plaintext) != "secret" {
+		t.Fatalf("Expected secret, got %q (%v)", plaintext, err)
+	}
+
+	if _, err := keyring.Decrypt(ciphertext, []byte("row-2")); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Errorf("Expected ErrInvalidCiphertext for wrong additional data, got %v", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	old := newTestKeyring(t, "old")
+	ciphertext, _ := old.EncryptString("card")
+
+	rotated := newTestKeyring(t, "new", "old")
+	plaintext, err := rotated.DecryptString(ciphertext)
+	if err != nil || plaintext != "card" {
+		t.Fatalf("Expected card, got %q (%v)", plaintext, err)
+	}
+
+	raw, _ := old.Encrypt([]byte("x"), nil)
+	if !rotated.NeedsRotation(raw) {
+		t.Error("Expected ciphertext from old key to need rotation")
+	}
+
+	if _, err := newTestKeyring(t, "new").DecryptString(ciphertext); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	kek := newTestKeyring(t, "kek")
+
+	envelope, err := SealEnvelope(context.Background(), kek, []byte("payload"), nil)
+	if err != nil {
+		t.Fatalf("SealEnvelope failed: %v", err)
+	}
+	plaintext, err := OpenEnvelope(context.Background(), kek, envelope, nil)
+	if err != nil || string(plaintext) != "payload" {
+		t.Fatalf("Expected payload, got %q (%v)", plaintext, err)
+	}
+}
+
+func TestFieldEncryption(t *testing.T) {
+	type address struct {
+		Street string `encrypt:"true"`
+	}
+	type customer struct {
+		ID      string
+		Email   string `encrypt:"true"`
+		Notes   []byte `encrypt:"true"`
+		Address *address
+	}
+
+	keyring := newTestKeyring(t, "a")
+	c := customer{ID: "c1", Email: "a@example.com", Notes: []byte("vip"), Address: &address{Street: "Main St"}}
+
+	if err := keyring.EncryptFields(&c); err != nil {
+		t.Fatalf("EncryptFields failed: %v", err)
+	}
+	if c.ID != "c1" || c.Email == "a@example.com" || c.Address.Street == "Main St" || string(c.Notes) == "vip" {
+		t.Fatalf("Expected tagged fields to be encrypted: %+v", c)
+	}
+
+	if err := keyring.DecryptFields(&c); err != nil {
+		t.Fatalf("DecryptFields failed: %v", err)
+	}
+	if c.Email != "a@example.com" || c.Address.Street != "Main St" || string(c.Notes) != "vip" {
+		t.Errorf("Unexpected decrypted values: %+v", c)
+	}
+}
+
+func TestHMAC(t *testing.T) {
+	signature := SignHex([]byte("key"), []byte("message"))
+	if !VerifyHex([]byte("key"), []byte("message"), signature) {
+		t.Error("Expected signature to verify")
+	}
+	if VerifyHex([]byte("other"), []byte("message"), signature) {
+		t.Error("Expected signature with wrong key to fail")
+	}
+}
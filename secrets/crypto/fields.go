@@ -0,0 +1,104 @@
+package crypto
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// fieldTag marks struct fields for encryption: `encrypt:"true"`
+const fieldTag = "encrypt"
+
+// EncryptFields encrypts, in place, every string and []byte field of the
+// struct pointed to by v that is tagged `encrypt:"true"`. Strings are
+// replaced by base64 ciphertext; nested structs and pointers to structs are
+// traversed. Empty values are left untouched.
+func (k *Keyring) EncryptFields(v any) error {
+	return walkFields(v, func(name string, field reflect.Value) error {
+		if field.Kind() == reflect.String {
+			ciphertext, err := k.EncryptString(field.String())
+			if err != nil {
+				return fmt.Errorf("failed to encrypt field %s: %w", name, err)
+			}
+			field.SetString(ciphertext)
+			return nil
+		}
+
+		ciphertext, err := k.Encrypt(field.Bytes(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt field %s: %w", name, err)
+		}
+		field.SetBytes(ciphertext)
+		return nil
+	})
+}
+
+// DecryptFields reverses EncryptFields
+func (k *Keyring) DecryptFields(v any) error {
+	return walkFields(v, func(name string, field reflect.Value) error {
+		if field.Kind() == reflect.String {
+			plaintext, err := k.DecryptString(field.String())
+			if err != nil {
+				return fmt.Errorf("failed to decrypt field %s: %w", name, err)
+			}
+			field.SetString(plaintext)
+			return nil
+		}
+
+		plaintext, err := k.Decrypt(field.Bytes(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt field %s: %w", name, err)
+		}
+		field.SetBytes(plaintext)
+		return nil
+	})
+}
+
+// walkFields calls fn for every non-empty tagged field of the struct pointed to by v
+func walkFields(v any, fn func(name string, field reflect.Value) error) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("crypto: expected a non-nil pointer to a struct, got %T", v)
+	}
+	return walkStruct(rv.Elem(), fn)
+}
+
+// walkStruct visits tagged fields and recurses into nested structs
+func walkStruct(rv reflect.Value, fn func(name string, field reflect.Value) error) error {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		value := rv.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Tag.Get(fieldTag) == "true" {
+			isBytes := value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8
+			if value.Kind() != reflect.String && !isBytes {
+				return fmt.Errorf("crypto: field %s must be a string or []byte to be encrypted", field.Name)
+			}
+			if value.Len() == 0 {
+				continue
+			}
+			if err := fn(field.Name, value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch value.Kind() {
+		case reflect.Struct:
+			if err := walkStruct(value, fn); err != nil {
+				return err
+			}
+		case reflect.Pointer:
+			if !value.IsNil() && value.Elem().Kind() == reflect.Struct {
+				if err := walkStruct(value.Elem(), fn); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvProvider reads secrets from environment variables named by the prefix
+// and the upper-case name, with other characters than letters and digits
+// as underscores, e.g. APP_DB_PASSWORD for "db-password" with prefix "APP_"
+type EnvProvider struct {
+	prefix string
+}
+
+// NewEnvProvider creates a provider reading variables starting with prefix
+func NewEnvProvider(prefix string) *EnvProvider {
+	return &EnvProvider{prefix: prefix}
+}
+
+// Get returns the environment variable for name
+func (p *EnvProvider) Get(ctx context.Context, name string) (string, error) {
+	variable := p.prefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+	value, ok := os.LookupEnv(variable)
+	if !ok {
+		return "", fmt.Errorf("%w: environment variable %s", ErrNotFound, variable)
+	}
+	return value, nil
+}
+
+// FileProvider reads secrets from files in a directory, such as a mounted
+// Kubernetes secret volume where each key is a file. A trailing newline is
+// removed. Files are read on every Get, so wrap the provider in a Cache.
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider creates a provider reading files in dir
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+// Get returns the content of the file name in the directory. Names must be
+// local paths; ".." and absolute paths are rejected.
+func (p *FileProvider) Get(ctx context.Context, name string) (string, error) {
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("secrets: invalid secret file name %q", name)
+	}
+	content, err := os.ReadFile(filepath.Join(p.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%w: file %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(content), "\n"), "\r"), nil
+}
+
+// chain looks secrets up in several providers
+type chain struct {
+	providers []Provider
+}
+
+// Chain returns a provider that asks providers in order and returns the
+// first secret found, e.g. environment overrides for local development
+// before Vault. Errors other than ErrNotFound stop the lookup, so an
+// unavailable backend is not masked by a later provider.
+func Chain(providers ...Provider) Provider {
+	return &chain{providers: providers}
+}
+
+// Get returns the secret from the first provider that has it
+func (c *chain) Get(ctx context.Context, name string) (string, error) {
+	for _, provider := range c.providers {
+		value, err := provider.Get(ctx, name)
+		if err == nil || !errors.Is(err, ErrNotFound) {
+			return value, err
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+}
@@ -0,0 +1,64 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("APP_DB_PASSWORD", "hunter2")
+
+	provider := NewEnvProvider("APP_")
+	if value, err := provider.Get(context.Background(), "db-password"); err != nil || value != "hunter2" {
+		t.Errorf("Expected hunter2, got %q (%v)", value, err)
+	}
+	if _, err := provider.Get(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "api-key"), []byte("k-123\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewFileProvider(dir)
+	ctx := context.Background()
+	if value, err := provider.Get(ctx, "api-key"); err != nil || value != "k-123" {
+		t.Errorf("Expected k-123, got %q (%v)", value, err)
+	}
+	if _, err := provider.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	for _, name := range []string{"../etc/passwd", "/etc/passwd"} {
+		if _, err := provider.Get(ctx, name); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected %q to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	t.Setenv("CHAIN_TOKEN", "from-env")
+	fallback := &fakeProvider{values: map[string]string{"token": "from-vault", "other": "x"}}
+	provider := Chain(NewEnvProvider("CHAIN_"), fallback)
+	ctx := context.Background()
+
+	if value, _ := provider.Get(ctx, "token"); value != "from-env" {
+		t.Errorf("Expected the first provider to win, got %q", value)
+	}
+	if value, _ := provider.Get(ctx, "other"); value != "x" {
+		t.Errorf("Expected to fall through to the next provider, got %q", value)
+	}
+	if _, err := provider.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	failing := Chain(&fakeProvider{err: errors.New("unavailable")}, fallback)
+	if _, err := failing.Get(ctx, "other"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the failure not to be masked, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+// Package secrets loads credentials from environment variables, mounted
+// files, Vault, or AWS Secrets Manager, so they never sit in plain config.
+//
+// Providers share one interface; Cache wraps any provider with a TTL, keeps
+// serving the last known value when the backend is briefly unavailable, and
+// calls rotation callbacks when a value changes. The client's auth providers
+// and TLS options accept a Provider directly.
+//
+// Example usage:
+//
+//	vault := secrets.NewVaultProvider("https://vault:8200", os.Getenv("VAULT_TOKEN"))
+//	store := secrets.NewCache(secrets.Chain(secrets.NewEnvProvider(""), vault),
+//		secrets.WithTTL(10*time.Minute))
+//	go store.Watch(ctx, time.Minute)
+//
+//	password, err := store.Get(ctx, "payments/db#password")
+//
+//	store.OnRotate("payments/db#password", func(name, value string) {
+//		pool.Reconnect(value)
+//	})
+package secrets
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/khekrn/core/cache"
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+)
+
+// ErrNotFound is returned when a provider has no secret with the given name
+var ErrNotFound = errors.New("secrets: secret not found")
+
+// Provider looks up secrets by name. Names are provider specific, e.g. an
+// environment variable, a file name, or a Vault path. Missing secrets are
+// reported with an error wrapping ErrNotFound.
+type Provider interface {
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// ProviderFunc adapts a function to the Provider interface, e.g. to wrap a
+// cloud SDK client
+type ProviderFunc func(ctx context.Context, name string) (string, error)
+
+// Get calls f
+func (f ProviderFunc) Get(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+// DefaultTTL is how long Cache keeps a secret before reading it again
+const DefaultTTL = 5 * time.Minute
+
+// Config holds configuration for a Cache
+type Config struct {
+	TTL        time.Duration // Defaults to DefaultTTL
+	MaxEntries int           // Zero means unbounded
+}
+
+// Option is a function type for configuring a Cache
+type Option func(*Config)
+
+// WithTTL sets how long secrets are cached
+func WithTTL(ttl time.Duration) Option {
+	return func(config *Config) {
+		config.TTL = ttl
+	}
+}
+
+// WithMaxEntries bounds the number of cached secrets
+func WithMaxEntries(maxEntries int) Option {
+	return func(config *Config) {
+		config.MaxEntries = maxEntries
+	}
+}
+
+// Cache caches the secrets of a provider and reports rotations. It is a
+// Provider itself, so it can be passed wherever one is accepted.
+type Cache struct {
+	provider Provider
+	cache    *cache.Cache[string, string]
+
+	mu        sync.Mutex
+	last      map[string]string // Last value read for each name, for rotation and fallback
+	listeners map[string][]func(name, value string)
+}
+
+// NewCache creates a cache in front of provider
+func NewCache(provider Provider, options ...Option) *Cache {
+	config := Config{TTL: DefaultTTL}
+	for _, opt := range options {
+		opt(&config)
+	}
+	return &Cache{
+		provider:  provider,
+		cache:     cache.New[string, string](cache.WithTTL(config.TTL), cache.WithMaxEntries(config.MaxEntries)),
+		last:      make(map[string]string),
+		listeners: make(map[string][]func(string, string)),
+	}
+}
+
+// Get returns the secret, reading it from the provider when it is not
+// cached. If the provider fails for a reason other than ErrNotFound, the
+// last known value is returned and the failure logged.
+func (c *Cache) Get(ctx context.Context, name string) (string, error) {
+	return c.cache.GetOrLoad(ctx, name, c.load)
+}
+
+// Refresh reads the secret from the provider, bypassing the cache, and
+// reports whether its value changed
+func (c *Cache) Refresh(ctx context.Context, name string) (bool, error) {
+	c.mu.Lock()
+	previous, known := c.last[name]
+	c.mu.Unlock()
+
+	value, err := c.load(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	c.cache.Set(name, value)
+	return !known || value != previous, nil
+}
+
+// OnRotate calls fn when the value of the named secret changes; an empty
+// name receives every rotation. Callbacks run synchronously in the Get,
+// Refresh, or Watch that observed the change.
+func (c *Cache) OnRotate(name string, fn func(name, value string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners[name] = append(c.listeners[name], fn)
+}
+
+// Watch refreshes every secret read so far each interval, until ctx is
+// done, so rotations are noticed before cached values expire. Errors are
+// logged and the previous values kept.
+func (c *Cache) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.Lock()
+		names := make([]string, 0, len(c.last))
+		for name := range c.last {
+			names = append(names, name)
+		}
+		c.mu.Unlock()
+
+		for _, name := range names {
+			if _, err := c.Refresh(ctx, name); err != nil {
+				logger.FromContext(ctx).Warn("Failed to refresh secret", zap.String("secret", name), zap.Error(err))
+			}
+		}
+	}
+}
+
+// Invalidate drops the cached value of the named secret so the next Get
+// reads it again
+func (c *Cache) Invalidate(name string) {
+	c.cache.Delete(name)
+}
+
+// load reads the secret from the provider and records it, notifying
+// listeners when it changed
+func (c *Cache) load(ctx context.Context, name string) (string, error) {
+	value, err := c.provider.Get(ctx, name)
+	if err != nil {
+		c.mu.Lock()
+		previous, known := c.last[name]
+		if errors.Is(err, ErrNotFound) {
+			delete(c.last, name)
+		}
+		c.mu.Unlock()
+
+		if !known || errors.Is(err, ErrNotFound) {
+			return "", err
+		}
+		logger.FromContext(ctx).Warn("Failed to read secret; using the last known value",
+			zap.String("secret", name), zap.Error(err))
+		return previous, nil
+	}
+
+	c.mu.Lock()
+	previous, known := c.last[name]
+	c.last[name] = value
+	var listeners []func(string, string)
+	if known && previous != value {
+		listeners = append(listeners, c.listeners[name]...)
+		listeners = append(listeners, c.listeners[""]...)
+	}
+	c.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(name, value)
+	}
+	return value, nil
+}
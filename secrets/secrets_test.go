@@ -0,0 +1,138 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeProvider serves values from a map, counting reads
+type fakeProvider struct {
+	mu     sync.Mutex
+	values map[string]string
+	err    error
+	reads  int
+}
+
+func (p *fakeProvider) Get(ctx context.Context, name string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reads++
+	if p.err != nil {
+		return "", p.err
+	}
+	value, ok := p.values[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+func (p *fakeProvider) set(name, value string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.values[name] = value
+}
+
+func TestCache_Get(t *testing.T) {
+	provider := &fakeProvider{values: map[string]string{"db-password": "v1"}}
+	store := NewCache(provider, WithTTL(time.Hour))
+	ctx := context.Background()
+
+	for range 3 {
+		if value, err := store.Get(ctx, "db-password"); err != nil || value != "v1" {
+			t.Fatalf("Expected v1, got %q (%v)", value, err)
+		}
+	}
+	if provider.reads != 1 {
+		t.Errorf("Expected one provider read, got %d", provider.reads)
+	}
+
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	provider.set("db-password", "v2")
+	store.Invalidate("db-password")
+	if value, _ := store.Get(ctx, "db-password"); value != "v2" {
+		t.Errorf("Expected v2 after invalidation, got %q", value)
+	}
+}
+
+func TestCache_Rotation(t *testing.T) {
+	provider := &fakeProvider{values: map[string]string{"api-key": "old", "other": "x"}}
+	store := NewCache(provider, WithTTL(time.Hour))
+	ctx := context.Background()
+
+	var rotated, all []string
+	store.OnRotate("api-key", func(name, value string) { rotated = append(rotated, value) })
+	store.OnRotate("", func(name, value string) { all = append(all, name) })
+
+	store.Get(ctx, "api-key")
+	store.Get(ctx, "other")
+	if len(rotated) != 0 {
+		t.Fatal("Expected no rotation on the first read")
+	}
+
+	if changed, err := store.Refresh(ctx, "api-key"); err != nil || changed {
+		t.Errorf("Expected an unchanged refresh, got %v (%v)", changed, err)
+	}
+
+	provider.set("api-key", "new")
+	if changed, err := store.Refresh(ctx, "api-key"); err != nil || !changed {
+		t.Errorf("Expected a changed refresh, got %v (%v)", changed, err)
+	}
+	if value, _ := store.Get(ctx, "api-key"); value != "new" {
+		t.Errorf("Expected the refreshed value, got %q", value)
+	}
+	if len(rotated) != 1 || rotated[0] != "new" || len(all) != 1 || all[0] != "api-key" {
+		t.Errorf("Unexpected rotations %v %v", rotated, all)
+	}
+}
+
+func TestCache_FallbackToLastKnown(t *testing.T) {
+	provider := &fakeProvider{values: map[string]string{"token": "t1"}}
+	store := NewCache(provider, WithTTL(time.Millisecond))
+	ctx := context.Background()
+
+	store.Get(ctx, "token")
+	time.Sleep(5 * time.Millisecond)
+
+	provider.err = errors.New("vault unavailable")
+	if value, err := store.Get(ctx, "token"); err != nil || value != "t1" {
+		t.Errorf("Expected the last known value, got %q (%v)", value, err)
+	}
+	if _, err := store.Get(ctx, "never-read"); err == nil {
+		t.Error("Expected the error for a secret never read")
+	}
+
+	provider.err = ErrNotFound
+	if _, err := store.Refresh(ctx, "token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected deleted secrets to be reported, got %v", err)
+	}
+}
+
+func TestCache_Watch(t *testing.T) {
+	provider := &fakeProvider{values: map[string]string{"cert": "a"}}
+	store := NewCache(provider, WithTTL(time.Hour))
+
+	rotated := make(chan string, 1)
+	store.OnRotate("cert", func(name, value string) { rotated <- value })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store.Get(ctx, "cert")
+	go store.Watch(ctx, 5*time.Millisecond)
+
+	provider.set("cert", "b")
+	select {
+	case value := <-rotated:
+		if value != "b" {
+			t.Errorf("Expected b, got %q", value)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Watch to notice the rotation")
+	}
+}
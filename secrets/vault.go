@@ -0,0 +1,109 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// VaultProvider reads secrets from a HashiCorp Vault KV version 2 engine.
+// Names are a path and an optional field, "payments/db#password"; the
+// field defaults to "value".
+type VaultProvider struct {
+	Address    string       // e.g. "https://vault.internal:8200"
+	Token      string       // Sent as X-Vault-Token
+	Mount      string       // KV engine mount; defaults to "secret"
+	Namespace  string       // Optional Vault Enterprise namespace
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+}
+
+// NewVaultProvider creates a provider for the KV engine mounted at "secret"
+func NewVaultProvider(address, token string) *VaultProvider {
+	return &VaultProvider{Address: address, Token: token, Mount: "secret"}
+}
+
+// Get reads the field of the secret at the path in name
+func (p *VaultProvider) Get(ctx context.Context, name string) (string, error) {
+	path, field := splitField(name)
+	if field == "" {
+		field = "value"
+	}
+	mount := p.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+
+	url := strings.TrimSuffix(p.Address, "/") + "/v1/" + strings.Trim(mount, "/") + "/data/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("secrets: failed to create Vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+	if p.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.Namespace)
+	}
+
+	resp, err := httpClient(p.HTTPClient).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("secrets: Vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("secrets: failed to read Vault response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("%w: Vault path %s", ErrNotFound, path)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("secrets: Vault returned status %d for %s", resp.StatusCode, path)
+	}
+
+	var payload struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("secrets: invalid Vault response: %w", err)
+	}
+	return fieldValue(payload.Data.Data, path, field)
+}
+
+// maxResponseSize bounds the responses read from secret backends
+const maxResponseSize = 1 << 20
+
+// httpClient returns client, or http.DefaultClient when it is nil
+func httpClient(client *http.Client) *http.Client {
+	if client == nil {
+		return http.DefaultClient
+	}
+	return client
+}
+
+// splitField splits "path#field" into the path and field
+func splitField(name string) (string, string) {
+	path, field, _ := strings.Cut(name, "#")
+	return path, field
+}
+
+// fieldValue returns the field of a JSON object as a string, encoding
+// non-string values as JSON
+func fieldValue(data map[string]any, path, field string) (string, error) {
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("%w: field %s of %s", ErrNotFound, field, path)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("secrets: failed to encode field %s of %s: %w", field, path, err)
+	}
+	return string(encoded), nil
+}
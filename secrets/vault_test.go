@@ -0,0 +1,53 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" || r.Header.Get("X-Vault-Namespace") != "team" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/data/payments/db":
+			w.Write([]byte(`{"data": {"data": {"password": "hunter2", "port": 5432}, "metadata": {"version": 3}}}`))
+		case "/v1/kv/data/payments/api":
+			w.Write([]byte(`{"data": {"data": {"value": "k-123"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors": []}`))
+		}
+	}))
+	defer server.Close()
+
+	provider := NewVaultProvider(server.URL, "root")
+	provider.Mount = "kv"
+	provider.Namespace = "team"
+	ctx := context.Background()
+
+	for name, want := range map[string]string{
+		"payments/db#password": "hunter2",
+		"payments/db#port":     "5432",
+		"payments/api":         "k-123",
+	} {
+		if value, err := provider.Get(ctx, name); err != nil || value != want {
+			t.Errorf("Get(%q) = %q, %v; want %q", name, value, err, want)
+		}
+	}
+	for _, name := range []string{"payments/db#user", "payments/missing"} {
+		if _, err := provider.Get(ctx, name); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for %q, got %v", name, err)
+		}
+	}
+
+	provider.Token = "wrong"
+	if _, err := provider.Get(ctx, "payments/api"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a status error, got %v", err)
+	}
+}
@@ -0,0 +1,322 @@
+// Package server provides a preconfigured HTTP server builder, the
+// server-side counterpart of the client builder.
+//
+// This package assembles an *http.Server with timeouts set by default, the
+// standard inbound middleware (request IDs, access logs, and panic
+// recovery), a health check endpoint that fails while shutting down so load
+// balancers drain traffic, optional TLS or unencrypted HTTP/2 (h2c), and
+// graceful shutdown through the lifecycle package.
+//
+// Example usage:
+//
+//	app := lifecycle.New()
+//	srv := server.NewBuilder().
+//		WithAddr(":8080").
+//		WithHandler(mux).
+//		WithMiddleware(middleware.Timeout(10 * time.Second)).
+//		WithHealthcheck("/healthz", db.PingContext).
+//		WithLifecycle(app).
+//		Build()
+//
+//	err := app.Run(context.Background())
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/khekrn/core/lifecycle"
+	"github.com/khekrn/core/logger"
+	"github.com/khekrn/core/middleware"
+	"github.com/khekrn/core/response"
+	"go.uber.org/zap"
+)
+
+// Default server settings. WriteTimeout bounds the whole response, so
+// servers streaming SSE or long downloads should raise or disable it.
+const (
+	DefaultAddr               = ":8080"
+	DefaultReadHeaderTimeout  = 5 * time.Second
+	DefaultReadTimeout        = 30 * time.Second
+	DefaultWriteTimeout       = 30 * time.Second
+	DefaultIdleTimeout        = 120 * time.Second
+	DefaultMaxHeaderBytes     = 1 << 20
+	DefaultHealthcheckTimeout = 5 * time.Second
+)
+
+// HealthCheck reports whether a dependency is usable, e.g. db.PingContext
+type HealthCheck func(ctx context.Context) error
+
+// Config is the declarative form of the server settings, e.g. loaded with
+// config.Load. Zero fields keep the builder's settings.
+type Config struct {
+	Addr              string        `yaml:"addr"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes"`
+	H2C               bool          `yaml:"h2c"`
+	TLSCertFile       string        `yaml:"tls_cert_file"`
+	TLSKeyFile        string        `yaml:"tls_key_file"`
+}
+
+// Builder provides a fluent interface for building HTTP servers
+type Builder struct {
+	config            Config
+	handler           http.Handler
+	middleware        []middleware.Middleware
+	defaultMiddleware bool
+	healthPath        string
+	healthChecks      []HealthCheck
+	tlsConfig         *tls.Config
+	tlsErr            error // Certificate load failure, reported on start
+	app               *lifecycle.App
+}
+
+// NewBuilder creates a server builder with the default timeouts and middleware
+func NewBuilder() *Builder {
+	return &Builder{
+		config: Config{
+			Addr:              DefaultAddr,
+			ReadHeaderTimeout: DefaultReadHeaderTimeout,
+			ReadTimeout:       DefaultReadTimeout,
+			WriteTimeout:      DefaultWriteTimeout,
+			IdleTimeout:       DefaultIdleTimeout,
+			MaxHeaderBytes:    DefaultMaxHeaderBytes,
+		},
+		defaultMiddleware: true,
+	}
+}
+
+// WithConfig applies the non-zero settings of cfg
+//
+// Example:
+//
+//	type Settings struct {
+//		HTTP server.Config `yaml:"http"`
+//	}
+//
+//	settings, err := config.Load[Settings](config.WithFiles("config.yaml"), config.WithEnvPrefix("APP"))
+//	srv := server.NewBuilder().WithConfig(settings.HTTP).WithHandler(mux).Build()
+func (b *Builder) WithConfig(cfg Config) *Builder {
+	if cfg.Addr != "" {
+		b.config.Addr = cfg.Addr
+	}
+	if cfg.ReadHeaderTimeout > 0 {
+		b.config.ReadHeaderTimeout = cfg.ReadHeaderTimeout
+	}
+	if cfg.ReadTimeout > 0 {
+		b.config.ReadTimeout = cfg.ReadTimeout
+	}
+	if cfg.WriteTimeout > 0 {
+		b.config.WriteTimeout = cfg.WriteTimeout
+	}
+	if cfg.IdleTimeout > 0 {
+		b.config.IdleTimeout = cfg.IdleTimeout
+	}
+	if cfg.MaxHeaderBytes > 0 {
+		b.config.MaxHeaderBytes = cfg.MaxHeaderBytes
+	}
+	if cfg.H2C {
+		b.config.H2C = true
+	}
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		b.WithTLSCertificate(cfg.TLSCertFile, cfg.TLSKeyFile)
+	}
+	return b
+}
+
+// WithAddr sets the listen address
+func (b *Builder) WithAddr(addr string) *Builder {
+	b.config.Addr = addr
+	return b
+}
+
+// WithHandler sets the handler serving requests. Defaults to http.DefaultServeMux.
+func (b *Builder) WithHandler(handler http.Handler) *Builder {
+	b.handler = handler
+	return b
+}
+
+// WithReadHeaderTimeout sets how long reading the request headers may take
+func (b *Builder) WithReadHeaderTimeout(timeout time.Duration) *Builder {
+	b.config.ReadHeaderTimeout = timeout
+	return b
+}
+
+// WithReadTimeout sets how long reading the whole request may take
+func (b *Builder) WithReadTimeout(timeout time.Duration) *Builder {
+	b.config.ReadTimeout = timeout
+	return b
+}
+
+// WithWriteTimeout sets how long writing the response may take. Zero
+// disables it, e.g. for streaming responses.
+func (b *Builder) WithWriteTimeout(timeout time.Duration) *Builder {
+	b.config.WriteTimeout = timeout
+	return b
+}
+
+// WithIdleTimeout sets how long keep-alive connections wait for the next request
+func (b *Builder) WithIdleTimeout(timeout time.Duration) *Builder {
+	b.config.IdleTimeout = timeout
+	return b
+}
+
+// WithMaxHeaderBytes sets the maximum size of the request headers
+func (b *Builder) WithMaxHeaderBytes(size int) *Builder {
+	b.config.MaxHeaderBytes = size
+	return b
+}
+
+// WithMiddleware appends middleware. They run inside the default middleware,
+// in order, so the first one sees the request first.
+func (b *Builder) WithMiddleware(middlewares ...middleware.Middleware) *Builder {
+	b.middleware = append(b.middleware, middlewares...)
+	return b
+}
+
+// WithoutDefaultMiddleware removes the default request ID, access log, and
+// recovery middleware
+func (b *Builder) WithoutDefaultMiddleware() *Builder {
+	b.defaultMiddleware = false
+	return b
+}
+
+// WithHealthcheck serves a health check at path, answering 200 when every
+// check passes and 503 when one fails or the server is shutting down. The
+// endpoint bypasses the middleware, so probes are not access logged.
+func (b *Builder) WithHealthcheck(path string, checks ...HealthCheck) *Builder {
+	b.healthPath = path
+	b.healthChecks = append(b.healthChecks, checks...)
+	return b
+}
+
+// WithH2C enables or disables HTTP/2 without TLS, e.g. behind a proxy that
+// terminates TLS and speaks HTTP/2 to backends. It has no effect with TLS,
+// where HTTP/2 is always negotiated.
+func (b *Builder) WithH2C(enable bool) *Builder {
+	b.config.H2C = enable
+	return b
+}
+
+// WithTLSConfig sets the TLS configuration. WithTLSCertificate is applied on top of it.
+func (b *Builder) WithTLSConfig(config *tls.Config) *Builder {
+	b.tlsConfig = config.Clone()
+	return b
+}
+
+// WithTLSCertificate serves HTTPS with the certificate in certFile and keyFile
+// (PEM). If the files cannot be loaded the error is logged and, with
+// WithLifecycle, fails the app's start; without a certificate the server
+// cannot serve TLS.
+func (b *Builder) WithTLSCertificate(certFile, keyFile string) *Builder {
+	config := b.ensureTLSConfig()
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		logger.Error("Failed to load server certificate", zap.String("cert_file", certFile), zap.Error(err))
+		if b.tlsErr == nil {
+			b.tlsErr = fmt.Errorf("failed to load server certificate: %w", err)
+		}
+		return b
+	}
+	config.Certificates = append(config.Certificates, cert)
+	return b
+}
+
+// WithLifecycle registers the built server with app, which opens the
+// listener on start and shuts the server down gracefully on stop
+func (b *Builder) WithLifecycle(app *lifecycle.App) *Builder {
+	b.app = app
+	return b
+}
+
+// Build creates the server
+func (b *Builder) Build() *http.Server {
+	handler := b.handler
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+	var middlewares []middleware.Middleware
+	if b.defaultMiddleware {
+		middlewares = append(middlewares, middleware.RequestID, middleware.AccessLog, middleware.Recover)
+	}
+	handler = middleware.Chain(append(middlewares, b.middleware...)...)(handler)
+
+	var draining atomic.Bool
+	if b.healthPath != "" {
+		handler = healthHandler(b.healthPath, b.healthChecks, &draining, handler)
+	}
+
+	server := &http.Server{
+		Addr:              b.config.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: b.config.ReadHeaderTimeout,
+		ReadTimeout:       b.config.ReadTimeout,
+		WriteTimeout:      b.config.WriteTimeout,
+		IdleTimeout:       b.config.IdleTimeout,
+		MaxHeaderBytes:    b.config.MaxHeaderBytes,
+		ErrorLog:          zap.NewStdLog(logger.FromContext(context.Background()).Named("http-server")),
+	}
+	server.RegisterOnShutdown(func() { draining.Store(true) })
+
+	if b.tlsConfig != nil {
+		server.TLSConfig = b.tlsConfig.Clone()
+	} else if b.config.H2C {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		protocols.SetUnencryptedHTTP2(true)
+		server.Protocols = protocols
+	}
+
+	if b.app != nil && b.tlsErr != nil {
+		// Fail fast instead of listening with a server that cannot handshake
+		tlsErr := b.tlsErr
+		b.app.Append(lifecycle.Hook{
+			Name:    "http-server " + server.Addr,
+			OnStart: func(ctx context.Context) error { return tlsErr },
+		})
+	} else if b.app != nil {
+		b.app.AppendHTTPServer(server)
+	}
+	return server
+}
+
+// ensureTLSConfig returns the builder's TLS configuration, creating it if needed
+func (b *Builder) ensureTLSConfig() *tls.Config {
+	if b.tlsConfig == nil {
+		b.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return b.tlsConfig
+}
+
+// healthHandler answers requests for path with the result of checks and
+// passes other requests to next
+func healthHandler(path string, checks []HealthCheck, draining *atomic.Bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if draining.Load() {
+			response.WriteError(w, r, fmt.Errorf("%w: shutting down", response.ErrUnavailable))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), DefaultHealthcheckTimeout)
+		defer cancel()
+		for _, check := range checks {
+			if err := check(ctx); err != nil {
+				logger.FromContext(ctx).Warn("Health check failed", zap.Error(err))
+				response.WriteError(w, r, fmt.Errorf("%w: %w", response.ErrUnavailable, err))
+				return
+			}
+		}
+		response.Write(w, r, http.StatusOK, response.NewSuccessResponse("OK", nil))
+	})
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/lifecycle"
+	"github.com/khekrn/core/logger"
+)
+
+func TestBuilder_Defaults(t *testing.T) {
+	server := NewBuilder().Build()
+	if server.Addr != DefaultAddr || server.ReadHeaderTimeout != DefaultReadHeaderTimeout || server.ReadTimeout != DefaultReadTimeout ||
+		server.WriteTimeout != DefaultWriteTimeout || server.IdleTimeout != DefaultIdleTimeout || server.MaxHeaderBytes != DefaultMaxHeaderBytes {
+		t.Errorf("Unexpected defaults %+v", server)
+	}
+	if server.TLSConfig != nil || server.Protocols != nil || server.ErrorLog == nil {
+		t.Error("Expected plain HTTP/1 with an error logger by default")
+	}
+
+	server = NewBuilder().
+		WithReadTimeout(time.Second).
+		WithConfig(Config{Addr: ":9000", WriteTimeout: time.Minute, H2C: true}).
+		Build()
+	if server.Addr != ":9000" || server.ReadTimeout != time.Second || server.WriteTimeout != time.Minute ||
+		server.IdleTimeout != DefaultIdleTimeout || server.Protocols == nil || !server.Protocols.UnencryptedHTTP2() {
+		t.Errorf("Expected non-zero config fields to apply, got %+v", server)
+	}
+}
+
+func TestBuilder_Middleware(t *testing.T) {
+	var order []string
+	trace := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		if logger.RequestIDFromContext(r.Context()) == "" {
+			t.Error("Expected a request ID in the context")
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) { panic("boom") })
+
+	handler := NewBuilder().WithHandler(mux).WithMiddleware(trace("first"), trace("second")).Build().Handler
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/orders", nil))
+	if recorder.Code != http.StatusCreated || recorder.Header().Get(logger.RequestIDHeader) == "" {
+		t.Errorf("Expected the default middleware to run, got %d %v", recorder.Code, recorder.Header())
+	}
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("Expected middleware in order, got %v", order)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Expected panics to be recovered, got %d", recorder.Code)
+	}
+
+	bare := NewBuilder().WithHandler(mux).WithoutDefaultMiddleware().Build().Handler
+	recorder = httptest.NewRecorder()
+	bare.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if recorder.Header().Get(logger.RequestIDHeader) != "" {
+		t.Error("Expected no default middleware")
+	}
+}
+
+func TestBuilder_Healthcheck(t *testing.T) {
+	var failure error
+	server := NewBuilder().
+		WithHandler(http.NotFoundHandler()).
+		WithHealthcheck("/healthz", func(ctx context.Context) error { return failure }).
+		Build()
+
+	check := func() int {
+		recorder := httptest.NewRecorder()
+		server.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		return recorder.Code
+	}
+	if code := check(); code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", code)
+	}
+	failure = errors.New("database unreachable")
+	if code := check(); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 for a failing check, got %d", code)
+	}
+
+	failure = nil
+	server.Shutdown(context.Background())
+	deadline := time.Now().Add(time.Second)
+	for check() != http.StatusServiceUnavailable {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected 503 while shutting down")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBuilder_H2C(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewBuilder().
+		WithH2C(true).
+		WithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.Proto))
+		})).
+		Build()
+	go server.Serve(listener)
+	defer server.Close()
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	httpClient := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+	resp, err := httpClient.Get("http://" + listener.Addr().String())
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.ProtoMajor != 2 {
+		t.Errorf("Expected HTTP/2, got %s", resp.Proto)
+	}
+}
+
+func TestBuilder_TLSWithLifecycle(t *testing.T) {
+	// Borrow a certificate trusted by the test server's client
+	certSource := httptest.NewTLSServer(http.NotFoundHandler())
+	defer certSource.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	app := lifecycle.New()
+	NewBuilder().
+		WithAddr(addr).
+		WithTLSConfig(&tls.Config{Certificates: certSource.TLS.Certificates}).
+		WithHealthcheck("/healthz").
+		WithLifecycle(app).
+		Build()
+
+	if err := app.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	resp, err := certSource.Client().Get("https://" + addr + "/healthz")
+	if err != nil {
+		t.Fatalf("GET over TLS failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200, got %d", resp.StatusCode)
+	}
+	if err := app.Stop(context.Background()); err != nil {
+		t.Errorf("Stop failed: %v", err)
+	}
+
+	failing := lifecycle.New()
+	NewBuilder().WithAddr(addr).WithTLSCertificate("missing.pem", "missing-key.pem").WithLifecycle(failing).Build()
+	if err := failing.Start(context.Background()); err == nil || !strings.Contains(err.Error(), "failed to load server certificate") {
+		t.Errorf("Expected Start to fail with the certificate load error, got %v", err)
+		failing.Stop(context.Background())
+	}
+}
@@ -0,0 +1,224 @@
+package sse
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+)
+
+// Config holds configuration for a Hub
+type Config struct {
+	BufferSize  int
+	Heartbeat   time.Duration
+	HistorySize int
+	Retry       time.Duration
+}
+
+// Option is a function type for configuring a Hub
+type Option func(*Config)
+
+// WithBufferSize sets the per-client send queue size. Clients whose queue
+// fills up are disconnected so a slow reader cannot hold up the others;
+// they reconnect and resume from their last event.
+func WithBufferSize(size int) Option {
+	return func(config *Config) {
+		config.BufferSize = size
+	}
+}
+
+// WithHeartbeat sets the interval of keep-alive comments. Zero disables heartbeats.
+func WithHeartbeat(interval time.Duration) Option {
+	return func(config *Config) {
+		config.Heartbeat = interval
+	}
+}
+
+// WithHistorySize sets how many recent events are kept for Last-Event-ID resume
+func WithHistorySize(size int) Option {
+	return func(config *Config) {
+		config.HistorySize = size
+	}
+}
+
+// WithRetry sets the reconnection delay advertised to clients
+func WithRetry(retry time.Duration) Option {
+	return func(config *Config) {
+		config.Retry = retry
+	}
+}
+
+// client is a single connected subscriber
+type client struct {
+	events chan Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+// close disconnects the client
+func (c *client) close() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// Hub broadcasts events to all connected clients
+type Hub struct {
+	config Config
+
+	mu      sync.RWMutex
+	clients map[*client]struct{}
+	history []Event
+	nextID  uint64
+	closed  bool
+}
+
+// NewHub creates a broadcast hub
+func NewHub(options ...Option) *Hub {
+	config := Config{
+		BufferSize:  64,
+		Heartbeat:   15 * time.Second,
+		HistorySize: 100,
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+	return &Hub{config: config, clients: make(map[*client]struct{})}
+}
+
+// Publish sends an event to every connected client. Events without an ID
+// are assigned a sequential one so clients can resume after reconnecting.
+func (h *Hub) Publish(event Event) {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return
+	}
+	if event.ID == "" {
+		h.nextID++
+		event.ID = strconv.FormatUint(h.nextID, 10)
+	}
+	if h.config.HistorySize > 0 {
+		h.history = append(h.history, event)
+		if len(h.history) > h.config.HistorySize {
+			h.history = h.history[len(h.history)-h.config.HistorySize:]
+		}
+	}
+	clients := make([]*client, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range clients {
+		select {
+		case c.events <- event:
+		default:
+			// Slow consumer: disconnect rather than block the publisher
+			h.remove(c)
+			logger.Warn("Disconnecting slow SSE client", zap.String("event_id", event.ID))
+		}
+	}
+}
+
+// Clients returns the number of connected clients
+func (h *Hub) Clients() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// Close disconnects all clients and stops accepting new ones
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for c := range h.clients {
+		c.close()
+		delete(h.clients, c)
+	}
+}
+
+// ServeHTTP streams events to the client until it disconnects or the hub closes
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := &client{
+		events: make(chan Event, h.config.BufferSize),
+		done:   make(chan struct{}),
+	}
+
+	// Register and snapshot missed events atomically so none are lost or duplicated
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		http.Error(w, "event stream closed", http.StatusServiceUnavailable)
+		return
+	}
+	missed := h.since(r.Header.Get("Last-Event-ID"))
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+	defer h.remove(c)
+
+	stream, err := NewStream(w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if h.config.Retry > 0 {
+		if err := stream.Send(Event{Retry: h.config.Retry}); err != nil {
+			return
+		}
+	}
+	for _, event := range missed {
+		if err := stream.Send(event); err != nil {
+			return
+		}
+	}
+
+	var heartbeat <-chan time.Time
+	if h.config.Heartbeat > 0 {
+		ticker := time.NewTicker(h.config.Heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case event := <-c.events:
+			if err := stream.Send(event); err != nil {
+				return
+			}
+		case <-heartbeat:
+			if err := stream.Comment("ping"); err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// since returns the events published after lastID. Callers must hold h.mu.
+func (h *Hub) since(lastID string) []Event {
+	if lastID == "" {
+		return nil
+	}
+	for i, event := range h.history {
+		if event.ID == lastID {
+			return append([]Event(nil), h.history[i+1:]...)
+		}
+	}
+	// Unknown ID: the client is too far behind, so replay everything we still have
+	return append([]Event(nil), h.history...)
+}
+
+// remove unregisters a client
+func (h *Hub) remove(c *client) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+	c.close()
+}
@@ -0,0 +1,126 @@
+// Package sse provides Server-Sent Events helpers for producing event streams
+// over plain net/http.
+//
+// This package offers a Stream writer for a single response, and a Hub that
+// broadcasts events to many clients with per-client send queues, periodic
+// heartbeats to keep proxies from closing idle connections, and replay of
+// missed events when a client reconnects with a Last-Event-ID header.
+//
+// Example usage:
+//
+//	hub := sse.NewHub(sse.WithHeartbeat(15*time.Second), sse.WithHistorySize(100))
+//	defer hub.Close()
+//
+//	http.Handle("/events", hub)
+//
+//	hub.Publish(sse.Event{Event: "order.updated", Data: order})
+package sse
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khekrn/core/helpers"
+)
+
+// ErrStreamingUnsupported is returned when the ResponseWriter cannot flush
+var ErrStreamingUnsupported = errors.New("sse: streaming unsupported by response writer")
+
+// Event is a single server-sent event
+type Event struct {
+	ID    string        // Event ID, sent back by clients in Last-Event-ID
+	Event string        // Event type; clients listen with addEventListener(type)
+	Data  any           // Payload; strings and []byte are sent as-is, other values as JSON
+	Retry time.Duration // Reconnection delay hint for the client
+}
+
+// encode renders the event in the text/event-stream format
+func (e Event) encode() ([]byte, error) {
+	var data string
+	switch v := e.Data.(type) {
+	case nil:
+	case string:
+		data = v
+	case []byte:
+		data = string(v)
+	default:
+		encoded, err := helpers.ToJSON(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode event data: %w", err)
+		}
+		data = string(encoded)
+	}
+
+	var b strings.Builder
+	if e.ID != "" {
+		b.WriteString("id: " + sanitize(e.ID) + "\n")
+	}
+	if e.Event != "" {
+		b.WriteString("event: " + sanitize(e.Event) + "\n")
+	}
+	if e.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(e.Retry.Milliseconds(), 10) + "\n")
+	}
+	// Multi-line data is sent as one data field per line
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	return []byte(b.String()), nil
+}
+
+// sanitize strips newlines, which would terminate a field early
+func sanitize(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+// Stream writes events to a single HTTP response
+type Stream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// NewStream prepares w for streaming events and sends the response headers
+func NewStream(w http.ResponseWriter) (*Stream, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, ErrStreamingUnsupported
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Disable response buffering in nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &Stream{w: w, flusher: flusher}, nil
+}
+
+// Send writes an event and flushes it to the client
+func (s *Stream) Send(event Event) error {
+	data, err := event.encode()
+	if err != nil {
+		return err
+	}
+	return s.write(data)
+}
+
+// Comment writes a comment line, which clients ignore; used for heartbeats
+func (s *Stream) Comment(text string) error {
+	return s.write([]byte(": " + sanitize(text) + "\n\n"))
+}
+
+// write sends raw bytes and flushes them
+func (s *Stream) write(data []byte) error {
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
@@ -0,0 +1,125 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventEncode(t *testing.T) {
+	data, err := Event{ID: "7", Event: "update", Data: "line1\nline2", Retry: 3 * time.Second}.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "id: 7\nevent: update\nretry: 3000\ndata: line1\ndata: line2\n\n"
+	if string(data) != expected {
+		t.Errorf("Expected %q, got %q", expected, data)
+	}
+
+	data, _ = Event{Data: map[string]int{"n": 1}}.encode()
+	if string(data) != "data: {\"n\":1}\n\n" {
+		t.Errorf("Expected JSON data, got %q", data)
+	}
+}
+
+// readEvents reads n events from an SSE response body, skipping comments
+func readEvents(t *testing.T, reader *bufio.Reader, n int) []string {
+	t.Helper()
+	var events []string
+	var current strings.Builder
+	for len(events) < n {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read stream: %v", err)
+		}
+		switch {
+		case line == "\n":
+			if current.Len() > 0 {
+				events = append(events, current.String())
+				current.Reset()
+			}
+		case strings.HasPrefix(line, ":"):
+		default:
+			current.WriteString(line)
+		}
+	}
+	return events
+}
+
+func connect(t *testing.T, server *httptest.Server, lastEventID string) (*bufio.Reader, context.CancelFunc) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Unexpected content type %q", ct)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return bufio.NewReader(resp.Body), cancel
+}
+
+func waitForClients(t *testing.T, hub *Hub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for hub.Clients() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d clients, got %d", n, hub.Clients())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHub_BroadcastAndResume(t *testing.T) {
+	hub := NewHub(WithHeartbeat(0))
+	server := httptest.NewServer(hub)
+	defer server.Close()
+	defer hub.Close()
+
+	reader, cancel := connect(t, server, "")
+	defer cancel()
+	waitForClients(t, hub, 1)
+
+	hub.Publish(Event{Event: "greeting", Data: "hello"})
+	hub.Publish(Event{Data: "world"})
+
+	events := readEvents(t, reader, 2)
+	if events[0] != "id: 1\nevent: greeting\ndata: hello\n" || events[1] != "id: 2\ndata: world\n" {
+		t.Fatalf("Unexpected events %q", events)
+	}
+
+	// A reconnecting client receives only what it missed
+	resumed, cancelResumed := connect(t, server, "1")
+	defer cancelResumed()
+	events = readEvents(t, resumed, 1)
+	if events[0] != "id: 2\ndata: world\n" {
+		t.Errorf("Expected replay of event 2, got %q", events)
+	}
+}
+
+func TestHub_SlowClientDisconnected(t *testing.T) {
+	hub := NewHub(WithHeartbeat(0), WithBufferSize(1))
+	c := &client{events: make(chan Event, 1), done: make(chan struct{})}
+	hub.clients[c] = struct{}{}
+
+	hub.Publish(Event{Data: "1"})
+	hub.Publish(Event{Data: "2"})
+
+	if hub.Clients() != 0 {
+		t.Errorf("Expected slow client to be removed, got %d clients", hub.Clients())
+	}
+	select {
+	case <-c.done:
+	default:
+		t.Error("Expected slow client to be closed")
+	}
+}
@@ -0,0 +1,132 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/khekrn/core/internal/strictjson"
+	"github.com/khekrn/core/response"
+)
+
+// DefaultMaxBodySize is the largest request body Bind reads unless the
+// validator sets WithMaxBodySize
+const DefaultMaxBodySize = 1 << 20
+
+// BindJSON decodes the request body into T strictly, as
+// helpers.FromJSONStrict does, and validates it with the default validator.
+//
+// Fields the client got wrong, including unknown fields and values of the
+// wrong type, are returned as validation errors. A body that cannot be read
+// or is not JSON returns an error wrapping response.ErrBadRequest, so
+// response.WriteError answers 400. A body over DefaultMaxBodySize, or over
+// the limit set by middleware.MaxBodySize, returns an *response.APIError
+// with code ERR_PAYLOAD_TOO_LARGE, answered with 413.
+//
+// Example:
+//
+//	req, errs, err := validation.BindJSON[CreatePaymentRequest](r)
+//	if err != nil {
+//		response.WriteError(w, r, err)
+//		return
+//	}
+//	if errs != nil {
+//		response.WriteError(w, r, errs)
+//		return
+//	}
+func BindJSON[T any](r *http.Request) (*T, Errors, error) {
+	return Bind[T](defaultValidator, r)
+}
+
+// Bind is BindJSON with the given validator, reading at most the body size
+// set with WithMaxBodySize
+func Bind[T any](v *Validator, r *http.Request) (*T, Errors, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil, fmt.Errorf("%w: request body is empty", response.ErrBadRequest)
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, v.maxBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, nil, response.NewAPIError(response.CodePayloadTooLarge, "Request body too large", err)
+		}
+		return nil, nil, fmt.Errorf("%w: failed to read request body: %w", response.ErrBadRequest, err)
+	}
+
+	var result T
+	if err := strictjson.Decode(data, &result); err != nil {
+		if errs := decodeErrors(err); errs != nil {
+			return nil, errs, nil
+		}
+		return nil, nil, fmt.Errorf("%w: invalid JSON: %w", response.ErrBadRequest, err)
+	}
+
+	if errs := v.Struct(&result); errs != nil {
+		return &result, errs, nil
+	}
+	return &result, nil, nil
+}
+
+// decodeErrors converts a field-level decode error into validation errors,
+// or returns nil for malformed JSON
+func decodeErrors(err error) Errors {
+	var decodeErr *strictjson.DecodeError
+	if !errors.As(err, &decodeErr) {
+		return nil
+	}
+	path := indexPath(decodeErr.Path)
+	if errors.Is(decodeErr, strictjson.ErrUnknownField) {
+		return Errors{{Field: path, Reason: path + " is not a known field"}}
+	}
+
+	reason := path + " has an invalid type"
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(decodeErr, &typeErr) && typeErr.Type != nil {
+		reason = path + " must be " + typeName(typeErr.Type)
+	}
+	return Errors{{Field: path, Reason: reason}}
+}
+
+// indexPath rewrites slice indexes in encoding/json paths, "items.0.sku",
+// as "items[0].sku" to match the paths of Struct
+func indexPath(path string) string {
+	segments := strings.Split(path, ".")
+	var b strings.Builder
+	for i, segment := range segments {
+		switch {
+		case i > 0 && segment != "" && strings.Trim(segment, "0123456789") == "":
+			b.WriteString("[" + segment + "]")
+		case i > 0:
+			b.WriteString("." + segment)
+		default:
+			b.WriteString(segment)
+		}
+	}
+	return b.String()
+}
+
+// typeName describes a Go type in JSON terms
+func typeName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/khekrn/core/response"
+)
+
+type orderItem struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"gte=1"`
+}
+
+type createOrderRequest struct {
+	Currency string      `json:"currency" validate:"required,currency"`
+	Items    []orderItem `json:"items" validate:"required,dive"`
+}
+
+func bindBody(body string) (*createOrderRequest, Errors, error) {
+	r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	return BindJSON[createOrderRequest](r)
+}
+
+func TestBindJSON(t *testing.T) {
+	req, errs, err := bindBody(`{"currency": "EUR", "items": [{"sku": "A-1", "quantity": 2}]}`)
+	if err != nil || errs != nil || req.Currency != "EUR" || len(req.Items) != 1 {
+		t.Fatalf("Expected a valid request, got %+v %v %v", req, errs, err)
+	}
+
+	req, errs, err = bindBody(`{"currency": "EUR", "items": [{"sku": "", "quantity": 0}]}`)
+	if err != nil || req == nil || len(errs) != 2 || errs[0].Field != "items[0].sku" || errs[1].Field != "items[0].quantity" {
+		t.Errorf("Expected nested validation errors with the decoded request, got %v %v", errs, err)
+	}
+}
+
+func TestBindJSON_DecodeErrors(t *testing.T) {
+	tests := map[string]response.ValidationError{
+		`{"currency": "EUR", "items": [{"sku": "A-1", "qty": 2}]}`: {Field: "items[0].qty", Reason: "items[0].qty is not a known field"},
+		`{"currency": 978, "items": []}`:                           {Field: "currency", Reason: "currency must be a string"},
+		`{"currency": "EUR", "items": [{"quantity": "two"}]}`:      {Field: "items[0].quantity", Reason: "items[0].quantity must be an integer"},
+	}
+	for body, want := range tests {
+		req, errs, err := bindBody(body)
+		if err != nil || req != nil || len(errs) != 1 || errs[0] != want {
+			t.Errorf("Bind(%s) = %v, %v; want %v", body, errs, err, want)
+		}
+	}
+
+	for _, body := range []string{`{"currency":`, `{} {}`, ""} {
+		if _, errs, err := bindBody(body); errs != nil || !errors.Is(err, response.ErrBadRequest) {
+			t.Errorf("Expected a bad request error for %q, got %v %v", body, errs, err)
+		}
+	}
+}
+
+func TestBind_BodyTooLarge(t *testing.T) {
+	v, err := New(WithMaxBodySize(32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := `{"currency": "EUR", "items": [{"sku": "A-1", "quantity": 2}]}`
+
+	_, _, err = Bind[createOrderRequest](v, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body)))
+	if apiErr := response.FromError(err); apiErr == nil || apiErr.HTTPStatus != http.StatusRequestEntityTooLarge || apiErr.Code != response.CodePayloadTooLarge {
+		t.Errorf("Expected a 413 error, got %v", err)
+	}
+
+	// Limits set by middleware.MaxBodySize are mapped the same way
+	r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	r.Body = http.MaxBytesReader(httptest.NewRecorder(), r.Body, 16)
+	_, _, err = BindJSON[createOrderRequest](r)
+	if apiErr := response.FromError(err); apiErr == nil || apiErr.HTTPStatus != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected a 413 error, got %v", err)
+	}
+
+	if _, err := New(WithMaxBodySize(0)); err == nil {
+		t.Error("Expected a non-positive limit to be rejected")
+	}
+}
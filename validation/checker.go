@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/khekrn/core/response"
+)
+
+// Checker collects validation failures fluently, for rules that do not fit
+// in struct tags: patterns, conditions across fields, or checks that depend
+// on request state. Messages match the tag translations' style.
+//
+// Example:
+//
+//	errs := validation.NewChecker().
+//		Required("name", req.Name).
+//		Length("name", req.Name, 2, 100).
+//		Range("quantity", float64(req.Quantity), 1, 100).
+//		Match("sku", req.SKU, skuPattern).
+//		Check("end_date", req.End.After(req.Start), "end_date must be after start_date").
+//		Nested("address", validation.Struct(req.Address)).
+//		Errors()
+type Checker struct {
+	errs Errors
+}
+
+// NewChecker creates an empty checker
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Required fails when value is the zero value of its type, or an empty
+// slice or map
+func (c *Checker) Required(field string, value any) *Checker {
+	rv := reflect.ValueOf(value)
+	empty := !rv.IsValid() || rv.IsZero()
+	if rv.IsValid() && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map) {
+		empty = rv.Len() == 0
+	}
+	return c.Check(field, !empty, field+" is a required field")
+}
+
+// Length fails when s has fewer than min or more than max characters. A
+// negative max means no upper bound. Empty strings pass, so combine with
+// Required for mandatory fields.
+func (c *Checker) Length(field, s string, min, max int) *Checker {
+	if s == "" {
+		return c
+	}
+	n := utf8.RuneCountInString(s)
+	switch {
+	case n < min:
+		return c.Check(field, false, fmt.Sprintf("%s must be at least %d characters in length", field, min))
+	case max >= 0 && n > max:
+		return c.Check(field, false, fmt.Sprintf("%s must be a maximum of %d characters in length", field, max))
+	}
+	return c
+}
+
+// Range fails when value is outside [min, max]
+func (c *Checker) Range(field string, value, min, max float64) *Checker {
+	return c.Check(field, value >= min && value <= max,
+		fmt.Sprintf("%s must be between %s and %s", field, formatNumber(min), formatNumber(max)))
+}
+
+// Match fails when s does not match pattern. Empty strings pass.
+func (c *Checker) Match(field, s string, pattern *regexp.Regexp) *Checker {
+	return c.Check(field, s == "" || pattern.MatchString(s), field+" has an invalid format")
+}
+
+// Check fails with reason when ok is false
+func (c *Checker) Check(field string, ok bool, reason string) *Checker {
+	if !ok {
+		c.errs = append(c.errs, response.ValidationError{Field: field, Reason: reason})
+	}
+	return c
+}
+
+// Nested adds errs, e.g. from Struct or another Checker, under prefix, so
+// "city" becomes "address.city". Use an indexed prefix such as "items[2]"
+// for slice elements.
+func (c *Checker) Nested(prefix string, errs Errors) *Checker {
+	for _, err := range errs {
+		if prefix != "" {
+			err.Field = prefix + "." + err.Field
+		}
+		c.errs = append(c.errs, err)
+	}
+	return c
+}
+
+// Errors returns the failures, or nil when every check passed
+func (c *Checker) Errors() Errors {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs
+}
+
+// formatNumber formats a range bound without trailing zeros
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
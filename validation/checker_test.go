@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestChecker(t *testing.T) {
+	skuPattern := regexp.MustCompile(`^[A-Z]+-\d+$`)
+
+	errs := NewChecker().
+		Required("name", "").
+		Required("tags", []string{}).
+		Length("code", "ab", 3, 10).
+		Length("note", "", 3, 10).
+		Range("quantity", 0, 1, 100).
+		Range("discount", 0.5, 0, 1).
+		Match("sku", "a1", skuPattern).
+		Match("parent_sku", "AB-12", skuPattern).
+		Check("end_date", false, "end_date must be after start_date").
+		Nested("address", Errors{{Field: "city", Reason: "city is a required field"}}).
+		Errors()
+
+	want := map[string]string{
+		"name":         "name is a required field",
+		"tags":         "tags is a required field",
+		"code":         "code must be at least 3 characters in length",
+		"quantity":     "quantity must be between 1 and 100",
+		"sku":          "sku has an invalid format",
+		"end_date":     "end_date must be after start_date",
+		"address.city": "city is a required field",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("Expected %d errors, got %v", len(want), errs)
+	}
+	for _, err := range errs {
+		if want[err.Field] != err.Reason {
+			t.Errorf("Unexpected error %+v", err)
+		}
+	}
+
+	if errs := NewChecker().Required("count", 3).Length("name", "Ann", 1, -1).Errors(); errs != nil {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// e164 matches international phone numbers in E.164 format
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// ibanFormat matches the structure of an IBAN without spaces
+var ibanFormat = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+
+// currencies holds ISO 4217 codes in active use
+var currencies = map[string]struct{}{}
+
+func init() {
+	for _, code := range strings.Fields(`
+		AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB BRL
+		BSD BTN BWP BYN BZD CAD CDF CHF CLP CNY COP CRC CUP CVE CZK DJF DKK DOP DZD EGP
+		ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR
+		IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW KRW KWD KYD KZT LAK LBP LKR LRD LSL
+		LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR MZN NAD NGN NIO NOK NPR
+		NZD OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD
+		SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH UGX
+		USD UYU UZS VES VND VUV WST XAF XCD XOF XPF YER ZAR ZMW ZWL`) {
+		currencies[code] = struct{}{}
+	}
+}
+
+// sharedRules are registered on every Validator
+var sharedRules = []Rule{
+	{Tag: "phone", Func: validatePhone, Message: "{0} must be a phone number in international format (e.g. +14155550123)"},
+	{Tag: "iban", Func: validateIBAN, Message: "{0} must be a valid IBAN"},
+	{Tag: "currency", Func: validateCurrency, Message: "{0} must be a valid ISO 4217 currency code"},
+}
+
+// validatePhone checks for an E.164 phone number, ignoring spaces and dashes
+func validatePhone(fl validator.FieldLevel) bool {
+	phone := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(fl.Field().String())
+	return e164.MatchString(phone)
+}
+
+// validateIBAN checks the IBAN structure and its mod-97 check digits
+func validateIBAN(fl validator.FieldLevel) bool {
+	return IsIBAN(fl.Field().String())
+}
+
+// validateCurrency checks for an upper-case ISO 4217 currency code
+func validateCurrency(fl validator.FieldLevel) bool {
+	_, ok := currencies[fl.Field().String()]
+	return ok
+}
+
+// IsIBAN reports whether s is a valid IBAN. Spaces are ignored.
+func IsIBAN(s string) bool {
+	iban := strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	if !ibanFormat.MatchString(iban) {
+		return false
+	}
+
+	// Move the country code and check digits to the end and convert
+	// letters to numbers (A=10 ... Z=35); a valid IBAN leaves remainder 1
+	rearranged := iban[4:] + iban[:4]
+	var digits strings.Builder
+	for _, r := range rearranged {
+		if r >= 'A' && r <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(r-'A') + 10))
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
@@ -0,0 +1,217 @@
+// Package validation provides request validation built on go-playground/validator
+// with shared custom rules and output in the response package's format.
+//
+// This package offers a preconfigured validator that reports fields by their
+// JSON names, registers rules common across services (phone, iban, currency),
+// translates failures into readable English messages, and returns them as
+// response.ValidationError values ready to be sent to clients.
+//
+// Example usage:
+//
+//	type CreatePaymentRequest struct {
+//		Amount   int64  `json:"amount" validate:"required,gt=0"`
+//		Currency string `json:"currency" validate:"required,currency"`
+//		IBAN     string `json:"iban" validate:"required,iban"`
+//		Phone    string `json:"phone" validate:"omitempty,phone"`
+//	}
+//
+//	if errs := validation.Struct(req); errs != nil {
+//		json.NewEncoder(w).Encode(errs.Response("Validation failed"))
+//		return
+//	}
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/khekrn/core/internal/validationmsg"
+	"github.com/khekrn/core/response"
+)
+
+// Rule is a custom validation tag with its message. The message may use {0}
+// for the field name and {1} for the tag parameter.
+type Rule struct {
+	Tag     string
+	Func    validator.Func
+	Message string
+}
+
+// Errors is a list of field validation failures
+type Errors []response.ValidationError
+
+// Error implements the error interface
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, err := range e {
+		parts[i] = err.Field + ": " + err.Reason
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// FieldErrors returns the failures, so response.FromError maps Errors to
+// ERR_VALIDATION
+func (e Errors) FieldErrors() []response.ValidationError {
+	return e
+}
+
+// Response wraps the errors in a standard error response
+func (e Errors) Response(message string) response.Response {
+	return response.NewErrorResponseWithValidationErrors(message, e...)
+}
+
+// Validator validates structs and variables and translates failures
+type Validator struct {
+	validate    *validator.Validate
+	messages    map[string]string // Messages of the registered rules per tag
+	maxBodySize int64             // Largest body Bind reads
+}
+
+// Option is a function type for configuring a Validator
+type Option func(*Validator) error
+
+// WithRules registers additional custom rules
+func WithRules(rules ...Rule) Option {
+	return func(v *Validator) error {
+		for _, rule := range rules {
+			if err := v.RegisterRule(rule); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WithStructValidation registers a struct-level validation function for the given types
+func WithStructValidation(fn validator.StructLevelFunc, types ...any) Option {
+	return func(v *Validator) error {
+		v.validate.RegisterStructValidation(fn, types...)
+		return nil
+	}
+}
+
+// WithMaxBodySize sets the largest request body Bind reads, in bytes.
+// Defaults to DefaultMaxBodySize.
+func WithMaxBodySize(maxBytes int64) Option {
+	return func(v *Validator) error {
+		if maxBytes <= 0 {
+			return fmt.Errorf("max body size must be positive, got %d", maxBytes)
+		}
+		v.maxBodySize = maxBytes
+		return nil
+	}
+}
+
+// New creates a validator with the shared rules. Failures use the same
+// English messages as response.FromValidationErrors, including any set with
+// response.SetValidationMessage.
+func New(options ...Option) (*Validator, error) {
+	v := &Validator{
+		validate:    validator.New(validator.WithRequiredStructEnabled()),
+		messages:    map[string]string{},
+		maxBodySize: DefaultMaxBodySize,
+	}
+
+	// Report fields by their JSON names, as clients see them
+	v.validate.RegisterTagNameFunc(response.JSONTagName)
+
+	for _, rule := range sharedRules {
+		if err := v.RegisterRule(rule); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, opt := range options {
+		if err := opt(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+// RegisterRule adds a custom validation tag and its message
+func (v *Validator) RegisterRule(rule Rule) error {
+	if err := v.validate.RegisterValidation(rule.Tag, rule.Func); err != nil {
+		return fmt.Errorf("failed to register rule %s: %w", rule.Tag, err)
+	}
+
+	message := rule.Message
+	if message == "" {
+		message = "{0} is invalid"
+	}
+	v.messages[rule.Tag] = message
+	return nil
+}
+
+// Struct validates a struct and returns the failures, or nil when valid
+func (v *Validator) Struct(s any) Errors {
+	return v.convert(v.validate.Struct(s), "")
+}
+
+// Var validates a single value against tag, reporting failures under field
+func (v *Validator) Var(field string, value any, tag string) Errors {
+	return v.convert(v.validate.Var(value, tag), field)
+}
+
+// Engine returns the underlying validator for advanced configuration
+func (v *Validator) Engine() *validator.Validate {
+	return v.validate
+}
+
+// convert translates validator errors into response validation errors
+func (v *Validator) convert(err error, field string) Errors {
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return Errors{{Field: field, Reason: err.Error()}}
+	}
+
+	result := make(Errors, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		name := field
+		if name == "" {
+			name = validationmsg.Path(fe.Namespace())
+		}
+		reason := validationmsg.Reason(fe, v.messages)
+		if field != "" && fe.Field() == "" {
+			// Var has no field name, so the message starts with an empty name
+			reason = field + " " + strings.TrimSpace(reason)
+		}
+		result = append(result, response.ValidationError{Field: name, Reason: reason})
+	}
+	return result
+}
+
+// defaultValidator is used by the package-level functions
+var defaultValidator = mustNew()
+
+// mustNew creates the default validator, panicking on misconfiguration
+func mustNew() *Validator {
+	v, err := New()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Struct validates a struct with the default validator
+func Struct(s any) Errors {
+	return defaultValidator.Struct(s)
+}
+
+// Var validates a single value with the default validator
+func Var(field string, value any, tag string) Errors {
+	return defaultValidator.Var(field, value, tag)
+}
+
+// RegisterRule adds a custom rule to the default validator. Call it during
+// initialization, before validating concurrently.
+func RegisterRule(rule Rule) error {
+	return defaultValidator.RegisterRule(rule)
+}
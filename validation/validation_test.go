@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/khekrn/core/response"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type paymentRequest struct {
+	Amount   int64   `json:"amount" validate:"required,gt=0"`
+	Currency string  `json:"currency" validate:"required,currency"`
+	IBAN     string  `json:"iban" validate:"required,iban"`
+	Phone    string  `json:"phone" validate:"omitempty,phone"`
+	Address  address `json:"address"`
+}
+
+func TestStruct(t *testing.T) {
+	valid := paymentRequest{
+		Amount:   100,
+		Currency: "EUR",
+		IBAN:     "DE89 3704 0044 0532 0130 00",
+		Phone:    "+44 20 7946 0958",
+		Address:  address{City: "Berlin"},
+	}
+	if errs := Struct(valid); errs != nil {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	errs := Struct(paymentRequest{Currency: "XXX", IBAN: "DE00370400440532013000", Phone: "12345"})
+	expected := map[string]bool{"amount": true, "currency": true, "iban": true, "phone": true, "address.city": true}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %v", len(expected), errs)
+	}
+	for _, err := range errs {
+		if !expected[err.Field] {
+			t.Errorf("Unexpected field %q", err.Field)
+		}
+		if err.Reason == "" {
+			t.Errorf("Expected translated reason for %q", err.Field)
+		}
+	}
+}
+
+func TestVar(t *testing.T) {
+	errs := Var("email", "not-an-email", "required,email")
+	if len(errs) != 1 || errs[0].Field != "email" {
+		t.Fatalf("Expected one email error, got %v", errs)
+	}
+	if errs[0].Reason != "email must be a valid email address" {
+		t.Errorf("Unexpected reason %q", errs[0].Reason)
+	}
+}
+
+func TestStruct_MatchesFromValidationErrors(t *testing.T) {
+	type signup struct {
+		Email   string   `json:"email" validate:"required,email"`
+		Name    string   `json:"name" validate:"min=2"`
+		Tags    []string `json:"tags" validate:"max=1"`
+		Address address  `json:"address"`
+	}
+	req := signup{Name: "A", Tags: []string{"a", "b"}}
+
+	engine := validator.New(validator.WithRequiredStructEnabled())
+	engine.RegisterTagNameFunc(response.JSONTagName)
+	want := response.FromValidationErrors(engine.Struct(req))
+
+	got := Struct(req)
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], got[i])
+		}
+	}
+}
+
+func TestCustomRule(t *testing.T) {
+	v, err := New(WithRules(Rule{
+		Tag:     "even",
+		Func:    func(fl validator.FieldLevel) bool { return fl.Field().Int()%2 == 0 },
+		Message: "{0} must be even",
+	}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	errs := v.Var("count", 3, "even")
+	if len(errs) != 1 || errs[0].Reason != "count must be even" {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	resp := errs.Response("Validation failed")
+	if resp.Data == nil {
+		t.Error("Expected validation errors in response data")
+	}
+}
+
+func TestIsIBAN(t *testing.T) {
+	tests := map[string]bool{
+		"GB82 WEST 1234 5698 7654 32": true,
+		"DE89370400440532013000":      true,
+		"DE89370400440532013001":      false,
+		"XX":                          false,
+	}
+	for iban, expected := range tests {
+		if IsIBAN(iban) != expected {
+			t.Errorf("IsIBAN(%q) = %v, want %v", iban, !expected, expected)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NonceStore records delivery IDs that have already been accepted
+type NonceStore interface {
+	// Reserve records nonce for ttl and reports whether it was unseen
+	Reserve(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+	// Release forgets nonce so a failed delivery can be retried
+	Release(ctx context.Context, nonce string) error
+}
+
+// MemoryNonceStore is an in-process NonceStore, suitable for tests and single-instance services
+type MemoryNonceStore struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+// NewMemoryNonceStore creates a new in-memory nonce store
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{entries: make(map[string]time.Time)}
+}
+
+// Reserve records nonce if it is unseen or expired
+func (s *MemoryNonceStore) Reserve(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, ok := s.entries[nonce]; ok && now.Before(expiresAt) {
+		return false, nil
+	}
+
+	// Drop expired entries so the map does not grow without bound
+	for key, expiresAt := range s.entries {
+		if !now.Before(expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+
+	s.entries[nonce] = now.Add(ttl)
+	return true, nil
+}
+
+// Release removes nonce from the store
+func (s *MemoryNonceStore) Release(ctx context.Context, nonce string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, nonce)
+	return nil
+}
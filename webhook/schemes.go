@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khekrn/core/secrets/crypto"
+)
+
+// Signature is the verified metadata of a delivery
+type Signature struct {
+	Timestamp time.Time // Signing time, zero when the scheme does not sign one
+	ID        string    // Delivery ID used for replay protection, empty when the scheme has none
+}
+
+// Scheme verifies the signature of a delivery using one provider's format
+type Scheme interface {
+	// Verify checks the signature in header against body and returns the
+	// signed metadata. It returns ErrMissingSignature or ErrInvalidSignature.
+	Verify(header http.Header, body, secret []byte) (Signature, error)
+}
+
+// SchemeFunc adapts a function to the Scheme interface
+type SchemeFunc func(header http.Header, body, secret []byte) (Signature, error)
+
+// Verify calls f
+func (f SchemeFunc) Verify(header http.Header, body, secret []byte) (Signature, error) {
+	return f(header, body, secret)
+}
+
+// Stripe verifies the Stripe-Signature header ("t=<unix>,v1=<hex>[,v1=<hex>]"),
+// an HMAC-SHA256 of "<t>.<body>"
+func Stripe() Scheme {
+	return SchemeFunc(func(header http.Header, body, secret []byte) (Signature, error) {
+		value := header.Get("Stripe-Signature")
+		if value == "" {
+			return Signature{}, ErrMissingSignature
+		}
+
+		var timestamp string
+		var signatures []string
+		for _, part := range strings.Split(value, ",") {
+			key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "t":
+				timestamp = val
+			case "v1":
+				signatures = append(signatures, val)
+			}
+		}
+		if timestamp == "" || len(signatures) == 0 {
+			return Signature{}, ErrMissingSignature
+		}
+
+		signedAt, err := parseUnix(timestamp)
+		if err != nil {
+			return Signature{}, ErrInvalidSignature
+		}
+
+		payload := append([]byte(timestamp+"."), body...)
+		for _, signature := range signatures {
+			if crypto.VerifyHex(secret, payload, signature) {
+				return Signature{Timestamp: signedAt, ID: signature}, nil
+			}
+		}
+		return Signature{}, ErrInvalidSignature
+	})
+}
+
+// GitHub verifies the X-Hub-Signature-256 header ("sha256=<hex>"), an
+// HMAC-SHA256 of the body. GitHub does not sign a timestamp, so replay
+// protection relies on the X-GitHub-Delivery ID.
+func GitHub() Scheme {
+	return SchemeFunc(func(header http.Header, body, secret []byte) (Signature, error) {
+		value := header.Get("X-Hub-Signature-256")
+		if value == "" {
+			return Signature{}, ErrMissingSignature
+		}
+		signature, ok := strings.CutPrefix(value, "sha256=")
+		if !ok || !crypto.VerifyHex(secret, body, signature) {
+			return Signature{}, ErrInvalidSignature
+		}
+		return Signature{ID: header.Get("X-GitHub-Delivery")}, nil
+	})
+}
+
+// Slack verifies the X-Slack-Signature header ("v0=<hex>"), an HMAC-SHA256
+// of "v0:<X-Slack-Request-Timestamp>:<body>"
+func Slack() Scheme {
+	return SchemeFunc(func(header http.Header, body, secret []byte) (Signature, error) {
+		value := header.Get("X-Slack-Signature")
+		timestamp := header.Get("X-Slack-Request-Timestamp")
+		if value == "" || timestamp == "" {
+			return Signature{}, ErrMissingSignature
+		}
+
+		signedAt, err := parseUnix(timestamp)
+		if err != nil {
+			return Signature{}, ErrInvalidSignature
+		}
+
+		signature, ok := strings.CutPrefix(value, "v0=")
+		payload := append([]byte("v0:"+timestamp+":"), body...)
+		if !ok || !crypto.VerifyHex(secret, payload, signature) {
+			return Signature{}, ErrInvalidSignature
+		}
+		return Signature{Timestamp: signedAt, ID: signature}, nil
+	})
+}
+
+// parseUnix parses a Unix timestamp in seconds
+func parseUnix(value string) (time.Time, error) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, 0), nil
+}
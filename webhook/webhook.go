@@ -0,0 +1,270 @@
+// Package webhook provides verification helpers for receiving webhooks.
+//
+// This package offers HMAC signature verification for the Stripe, GitHub,
+// and Slack signing schemes, timestamp tolerance checks, replay protection
+// backed by a nonce store, and an http.Handler wrapper that decodes verified
+// payloads into typed handlers via the helpers package.
+//
+// Example usage:
+//
+//	verifier := webhook.NewVerifier(webhook.Stripe(), []byte(os.Getenv("STRIPE_WEBHOOK_SECRET")),
+//		webhook.WithTolerance(5*time.Minute),
+//		webhook.WithNonceStore(webhook.NewMemoryNonceStore()),
+//	)
+//
+//	http.Handle("/webhooks/stripe", webhook.Handler(verifier,
+//		func(ctx context.Context, event webhook.Event[StripeEvent]) error {
+//			return billing.Apply(ctx, event.Payload)
+//		}))
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/khekrn/core/helpers"
+	"github.com/khekrn/core/logger"
+	"github.com/khekrn/core/response"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrMissingSignature is returned when a request carries no signature
+	ErrMissingSignature = errors.New("webhook: missing signature")
+	// ErrInvalidSignature is returned when no secret matches the signature
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	// ErrTimestampOutOfRange is returned when the signed timestamp is outside the tolerance
+	ErrTimestampOutOfRange = errors.New("webhook: timestamp outside tolerance")
+	// ErrReplayed is returned when a delivery has already been accepted
+	ErrReplayed = errors.New("webhook: delivery already processed")
+	// ErrBodyTooLarge is returned when the body exceeds the configured limit
+	ErrBodyTooLarge = errors.New("webhook: body too large")
+)
+
+// Config holds configuration for a Verifier
+type Config struct {
+	Tolerance   time.Duration
+	MaxBodySize int64
+	Nonces      NonceStore
+	NonceTTL    time.Duration
+	Secrets     [][]byte
+}
+
+// Option is a function type for configuring a Verifier
+type Option func(*Config)
+
+// WithTolerance sets how far the signed timestamp may drift from the current
+// time. Zero disables the check.
+func WithTolerance(tolerance time.Duration) Option {
+	return func(config *Config) {
+		config.Tolerance = tolerance
+	}
+}
+
+// WithMaxBodySize sets the largest accepted request body
+func WithMaxBodySize(size int64) Option {
+	return func(config *Config) {
+		config.MaxBodySize = size
+	}
+}
+
+// WithNonceStore enables replay protection. Delivery IDs are remembered for
+// the nonce TTL, which defaults to twice the tolerance.
+func WithNonceStore(store NonceStore) Option {
+	return func(config *Config) {
+		config.Nonces = store
+	}
+}
+
+// WithNonceTTL sets how long delivery IDs are remembered
+func WithNonceTTL(ttl time.Duration) Option {
+	return func(config *Config) {
+		config.NonceTTL = ttl
+	}
+}
+
+// WithSecrets adds secrets that are also accepted, for rotating signing secrets
+func WithSecrets(secrets ...[]byte) Option {
+	return func(config *Config) {
+		config.Secrets = append(config.Secrets, secrets...)
+	}
+}
+
+// Delivery is a verified webhook request
+type Delivery struct {
+	Body      []byte
+	Header    http.Header
+	Signature Signature
+	nonce     string
+}
+
+// Event is a verified webhook delivery decoded into T
+type Event[T any] struct {
+	Payload   T
+	Header    http.Header
+	Signature Signature
+}
+
+// Verifier verifies webhook requests for one provider
+type Verifier struct {
+	scheme Scheme
+	config Config
+}
+
+// NewVerifier creates a verifier for scheme signed with secret
+func NewVerifier(scheme Scheme, secret []byte, options ...Option) *Verifier {
+	config := Config{
+		Tolerance:   5 * time.Minute,
+		MaxBodySize: 1 << 20,
+		Secrets:     [][]byte{secret},
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+	if config.NonceTTL <= 0 {
+		config.NonceTTL = 2 * config.Tolerance
+		if config.NonceTTL <= 0 {
+			config.NonceTTL = 24 * time.Hour
+		}
+	}
+
+	return &Verifier{scheme: scheme, config: config}
+}
+
+// Verify reads the request body and checks its signature, timestamp, and
+// delivery ID. When replay protection is enabled the delivery is reserved;
+// call Release if processing fails so the provider's retry is accepted.
+func (v *Verifier) Verify(r *http.Request) (*Delivery, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, v.config.MaxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook body: %w", err)
+	}
+	if int64(len(body)) > v.config.MaxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+
+	signature, err := v.verifySignature(r.Header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.config.Tolerance > 0 && !signature.Timestamp.IsZero() {
+		drift := time.Since(signature.Timestamp)
+		if drift > v.config.Tolerance || drift < -v.config.Tolerance {
+			return nil, ErrTimestampOutOfRange
+		}
+	}
+
+	delivery := &Delivery{Body: body, Header: r.Header, Signature: signature}
+	if v.config.Nonces != nil && signature.ID != "" {
+		fresh, err := v.config.Nonces.Reserve(r.Context(), signature.ID, v.config.NonceTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check webhook nonce: %w", err)
+		}
+		if !fresh {
+			return nil, ErrReplayed
+		}
+		delivery.nonce = signature.ID
+	}
+
+	return delivery, nil
+}
+
+// Release forgets a delivery's ID so a redelivery is accepted
+func (v *Verifier) Release(ctx context.Context, delivery *Delivery) error {
+	if v.config.Nonces == nil || delivery.nonce == "" {
+		return nil
+	}
+	return v.config.Nonces.Release(ctx, delivery.nonce)
+}
+
+// verifySignature tries every configured secret
+func (v *Verifier) verifySignature(header http.Header, body []byte) (Signature, error) {
+	err := ErrInvalidSignature
+	for _, secret := range v.config.Secrets {
+		var signature Signature
+		signature, err = v.scheme.Verify(header, body, secret)
+		if err == nil {
+			return signature, nil
+		}
+		if errors.Is(err, ErrMissingSignature) {
+			return Signature{}, err
+		}
+	}
+	return Signature{}, err
+}
+
+// Handler returns an http.Handler that verifies each request and passes the
+// payload, decoded into T, to fn.
+//
+// Responses: 401 for missing or invalid signatures, 400 for stale timestamps
+// or undecodable payloads, 413 for oversized bodies, 200 for replays (the
+// delivery was already handled), 500 when fn fails, and 200 otherwise.
+func Handler[T any](v *Verifier, fn func(ctx context.Context, event Event[T]) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		delivery, err := v.Verify(r)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrReplayed):
+			writeJSON(w, http.StatusOK, response.NewSuccessResponse("Already processed", nil))
+			return
+		case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignature):
+			writeError(w, http.StatusUnauthorized, "Invalid webhook signature")
+			return
+		case errors.Is(err, ErrTimestampOutOfRange):
+			writeError(w, http.StatusBadRequest, "Webhook timestamp outside tolerance")
+			return
+		case errors.Is(err, ErrBodyTooLarge):
+			writeError(w, http.StatusRequestEntityTooLarge, "Webhook body too large")
+			return
+		default:
+			logger.FromContext(r.Context()).Error("Failed to verify webhook", zap.Error(err))
+			writeError(w, http.StatusInternalServerError, "Failed to verify webhook")
+			return
+		}
+
+		payload, err := helpers.FromJSONValue[T](delivery.Body)
+		if err != nil {
+			v.Release(r.Context(), delivery)
+			writeError(w, http.StatusBadRequest, "Invalid webhook payload")
+			return
+		}
+
+		event := Event[T]{Payload: payload, Header: delivery.Header, Signature: delivery.Signature}
+		if err := fn(r.Context(), event); err != nil {
+			if releaseErr := v.Release(r.Context(), delivery); releaseErr != nil {
+				logger.FromContext(r.Context()).Warn("Failed to release webhook nonce", zap.Error(releaseErr))
+			}
+			logger.FromContext(r.Context()).Error("Webhook handler failed",
+				zap.String("delivery_id", delivery.Signature.ID),
+				zap.Error(err),
+			)
+			writeError(w, http.StatusInternalServerError, "Failed to process webhook")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, response.NewSuccessResponse("Processed", nil))
+	})
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, response.NewErrorResponse(message))
+}
+
+// writeJSON writes a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, code int, body response.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
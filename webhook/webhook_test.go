@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/secrets/crypto"
+)
+
+var secret = []byte("whsec_test")
+
+type invoice struct {
+	ID     string `json:"id"`
+	Amount int    `json:"amount"`
+}
+
+func stripeRequest(body string, signedAt time.Time, key []byte) *http.Request {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	signature := crypto.SignHex(key, []byte(timestamp+"."+body))
+	r := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	r.Header.Set("Stripe-Signature", "t="+timestamp+",v1="+signature)
+	return r
+}
+
+func TestSchemes(t *testing.T) {
+	body := `{"id":"in_1"}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	github := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	github.Header.Set("X-Hub-Signature-256", "sha256="+crypto.SignHex(secret, []byte(body)))
+	github.Header.Set("X-GitHub-Delivery", "delivery-1")
+
+	slack := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	slack.Header.Set("X-Slack-Request-Timestamp", now)
+	slack.Header.Set("X-Slack-Signature", "v0="+crypto.SignHex(secret, []byte("v0:"+now+":"+body)))
+
+	tests := []struct {
+		name    string
+		scheme  Scheme
+		request *http.Request
+	}{
+		{"stripe", Stripe(), stripeRequest(body, time.Now(), secret)},
+		{"github", GitHub(), github},
+		{"slack", Slack(), slack},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewVerifier(tt.scheme, secret).Verify(tt.request); err != nil {
+				t.Fatalf("Expected valid signature, got %v", err)
+			}
+
+			tampered := tt.request.Clone(context.Background())
+			tampered.Body = http.NoBody
+			if _, err := NewVerifier(tt.scheme, secret).Verify(tampered); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Expected ErrInvalidSignature for tampered body, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	body := `{"id":"in_1","amount":100}`
+
+	t.Run("missing signature", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		if _, err := NewVerifier(Stripe(), secret).Verify(r); !errors.Is(err, ErrMissingSignature) {
+			t.Errorf("Expected ErrMissingSignature, got %v", err)
+		}
+	})
+
+	t.Run("stale timestamp", func(t *testing.T) {
+		r := stripeRequest(body, time.Now().Add(-time.Hour), secret)
+		if _, err := NewVerifier(Stripe(), secret).Verify(r); !errors.Is(err, ErrTimestampOutOfRange) {
+			t.Errorf("Expected ErrTimestampOutOfRange, got %v", err)
+		}
+	})
+
+	t.Run("rotated secret", func(t *testing.T) {
+		r := stripeRequest(body, time.Now(), []byte("whsec_old"))
+		verifier := NewVerifier(Stripe(), secret, WithSecrets([]byte("whsec_old")))
+		if _, err := verifier.Verify(r); err != nil {
+			t.Errorf("Expected old secret to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		verifier := NewVerifier(Stripe(), secret, WithNonceStore(NewMemoryNonceStore()))
+		signedAt := time.Now()
+		if _, err := verifier.Verify(stripeRequest(body, signedAt, secret)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := verifier.Verify(stripeRequest(body, signedAt, secret)); !errors.Is(err, ErrReplayed) {
+			t.Errorf("Expected ErrReplayed, got %v", err)
+		}
+	})
+}
+
+func TestHandler(t *testing.T) {
+	body := `{"id":"in_1","amount":100}`
+	verifier := NewVerifier(Stripe(), secret, WithNonceStore(NewMemoryNonceStore()))
+
+	var calls int
+	fail := true
+	handler := Handler(verifier, func(ctx context.Context, event Event[invoice]) error {
+		calls++
+		if event.Payload.ID != "in_1" || event.Payload.Amount != 100 {
+			t.Errorf("Unexpected payload %+v", event.Payload)
+		}
+		if fail {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	})
+
+	signedAt := time.Now()
+	send := func(r *http.Request) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// A failed delivery releases its nonce so the provider's retry is processed
+	if code := send(stripeRequest(body, signedAt, secret)); code != http.StatusInternalServerError {
+		t.Fatalf("Expected 500, got %d", code)
+	}
+	fail = false
+	if code := send(stripeRequest(body, signedAt, secret)); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+
+	// A replay of a processed delivery is acknowledged without calling the handler
+	if code := send(stripeRequest(body, signedAt, secret)); code != http.StatusOK || calls != 2 {
+		t.Errorf("Expected acknowledged replay, got %d with %d calls", code, calls)
+	}
+
+	if code := send(stripeRequest(body, time.Now(), []byte("wrong"))); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401, got %d", code)
+	}
+}
@@ -0,0 +1,453 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	ws "github.com/gorilla/websocket"
+	"github.com/khekrn/core/logger"
+	"github.com/khekrn/core/response"
+	"go.uber.org/zap"
+)
+
+// ErrHubClosed is returned when sending through a hub that has been shut down
+var ErrHubClosed = errors.New("websocket: hub closed")
+
+// Authenticator authenticates the upgrade request and returns the principal
+// stored on the connection. Returning an error rejects the request with 401.
+type Authenticator func(r *http.Request) (any, error)
+
+// Handler handles application messages received from a connection
+type Handler func(ctx context.Context, conn *Conn, msg Message)
+
+// Authorizer decides whether a connection may subscribe to a topic
+type Authorizer func(conn *Conn, topic string) bool
+
+// Config holds configuration for a Hub
+type Config struct {
+	SendBuffer     int
+	WriteTimeout   time.Duration
+	PongTimeout    time.Duration
+	PingInterval   time.Duration
+	MaxMessageSize int64
+	Authenticate   Authenticator
+	Authorize      Authorizer
+	OnMessage      Handler
+	CheckOrigin    func(r *http.Request) bool
+}
+
+// Option is a function type for configuring a Hub
+type Option func(*Config)
+
+// WithSendBuffer sets the number of messages queued per connection before
+// the connection is considered too slow and closed
+func WithSendBuffer(size int) Option {
+	return func(config *Config) {
+		config.SendBuffer = size
+	}
+}
+
+// WithWriteTimeout sets the deadline for writing a single message
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.WriteTimeout = timeout
+	}
+}
+
+// WithPingInterval sets how often pings are sent; connections that do not
+// answer within the pong timeout are closed
+func WithPingInterval(interval, pongTimeout time.Duration) Option {
+	return func(config *Config) {
+		config.PingInterval = interval
+		config.PongTimeout = pongTimeout
+	}
+}
+
+// WithMaxMessageSize sets the largest message accepted from clients
+func WithMaxMessageSize(size int64) Option {
+	return func(config *Config) {
+		config.MaxMessageSize = size
+	}
+}
+
+// WithAuthenticator sets the hook that authenticates upgrade requests
+func WithAuthenticator(fn Authenticator) Option {
+	return func(config *Config) {
+		config.Authenticate = fn
+	}
+}
+
+// WithAuthorizer sets the hook that authorizes topic subscriptions
+func WithAuthorizer(fn Authorizer) Option {
+	return func(config *Config) {
+		config.Authorize = fn
+	}
+}
+
+// WithMessageHandler sets the handler for application messages from clients
+func WithMessageHandler(fn Handler) Option {
+	return func(config *Config) {
+		config.OnMessage = fn
+	}
+}
+
+// WithCheckOrigin sets the origin check for upgrade requests.
+// By default only same-origin requests are accepted.
+func WithCheckOrigin(fn func(r *http.Request) bool) Option {
+	return func(config *Config) {
+		config.CheckOrigin = fn
+	}
+}
+
+// Conn is a single client connection
+type Conn struct {
+	ID        string
+	Principal any
+
+	conn   *ws.Conn
+	send   chan []byte
+	topics map[string]struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Send queues a message for this connection. Slow connections whose
+// buffer is full are closed rather than blocking the caller.
+func (c *Conn) Send(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.enqueue(data)
+	return nil
+}
+
+// Close closes the connection
+func (c *Conn) Close() {
+	c.once.Do(func() {
+		close(c.done)
+	})
+}
+
+// enqueue queues encoded data without blocking
+func (c *Conn) enqueue(data []byte) {
+	select {
+	case <-c.done:
+	case c.send <- data:
+	default:
+		if logger.Logger != nil {
+			logger.Warn("Closing slow websocket connection", zap.String("conn_id", c.ID))
+		}
+		c.Close()
+	}
+}
+
+// Hub manages connections and topic subscriptions
+type Hub struct {
+	config   Config
+	upgrader ws.Upgrader
+
+	mu     sync.RWMutex
+	conns  map[*Conn]struct{}
+	topics map[string]map[*Conn]struct{}
+	closed bool
+	nextID atomic.Uint64
+	wg     sync.WaitGroup
+}
+
+// NewHub creates a WebSocket hub
+func NewHub(options ...Option) *Hub {
+	config := Config{
+		SendBuffer:     256,
+		WriteTimeout:   10 * time.Second,
+		PongTimeout:    60 * time.Second,
+		PingInterval:   50 * time.Second,
+		MaxMessageSize: 64 << 10,
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	return &Hub{
+		config:   config,
+		upgrader: ws.Upgrader{CheckOrigin: config.CheckOrigin},
+		conns:    make(map[*Conn]struct{}),
+		topics:   make(map[string]map[*Conn]struct{}),
+	}
+}
+
+// ServeHTTP authenticates and upgrades the request, then serves the connection
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var principal any
+	if h.config.Authenticate != nil {
+		p, err := h.config.Authenticate(r)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(response.NewErrorResponse("Unauthorized"))
+			return
+		}
+		principal = p
+	}
+
+	h.mu.RLock()
+	closed := h.closed
+	h.mu.RUnlock()
+	if closed {
+		http.Error(w, "server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an HTTP error response
+		return
+	}
+
+	c := &Conn{
+		ID:        strconv.FormatUint(h.nextID.Add(1), 10),
+		Principal: principal,
+		conn:      conn,
+		send:      make(chan []byte, h.config.SendBuffer),
+		topics:    make(map[string]struct{}),
+		done:      make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		conn.Close()
+		return
+	}
+	h.conns[c] = struct{}{}
+	h.wg.Add(1)
+	h.mu.Unlock()
+
+	go h.writePump(c)
+	h.readPump(r.Context(), c)
+}
+
+// Subscribe adds the connection to a topic
+func (h *Hub) Subscribe(c *Conn, topic string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.conns[c]; !ok {
+		return
+	}
+	if h.topics[topic] == nil {
+		h.topics[topic] = make(map[*Conn]struct{})
+	}
+	h.topics[topic][c] = struct{}{}
+	c.topics[topic] = struct{}{}
+}
+
+// Unsubscribe removes the connection from a topic
+func (h *Hub) Unsubscribe(c *Conn, topic string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.unsubscribeLocked(c, topic)
+}
+
+// unsubscribeLocked removes a subscription. Callers must hold h.mu.
+func (h *Hub) unsubscribeLocked(c *Conn, topic string) {
+	delete(c.topics, topic)
+	if subscribers := h.topics[topic]; subscribers != nil {
+		delete(subscribers, c)
+		if len(subscribers) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+}
+
+// Publish sends a message to every connection subscribed to topic
+func (h *Hub) Publish(topic string, msg Message) error {
+	msg.Topic = topic
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	if h.closed {
+		h.mu.RUnlock()
+		return ErrHubClosed
+	}
+	targets := make([]*Conn, 0, len(h.topics[topic]))
+	for c := range h.topics[topic] {
+		targets = append(targets, c)
+	}
+	h.mu.RUnlock()
+
+	for _, c := range targets {
+		c.enqueue(data)
+	}
+	return nil
+}
+
+// Broadcast sends a message to every connection
+func (h *Hub) Broadcast(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	if h.closed {
+		h.mu.RUnlock()
+		return ErrHubClosed
+	}
+	targets := make([]*Conn, 0, len(h.conns))
+	for c := range h.conns {
+		targets = append(targets, c)
+	}
+	h.mu.RUnlock()
+
+	for _, c := range targets {
+		c.enqueue(data)
+	}
+	return nil
+}
+
+// Connections returns the number of open connections
+func (h *Hub) Connections() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.conns)
+}
+
+// Shutdown stops accepting connections, sends a going-away close frame to
+// every client, and waits for connections to finish or ctx to expire.
+// Its signature matches the shutdown hooks used by the service lifecycle.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	for c := range h.conns {
+		c.Close()
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		// Force remaining connections closed
+		h.mu.RLock()
+		for c := range h.conns {
+			c.conn.Close()
+		}
+		h.mu.RUnlock()
+		return ctx.Err()
+	}
+}
+
+// remove unregisters a connection and its subscriptions
+func (h *Hub) remove(c *Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.conns[c]; !ok {
+		return
+	}
+	for topic := range c.topics {
+		h.unsubscribeLocked(c, topic)
+	}
+	delete(h.conns, c)
+	h.wg.Done()
+}
+
+// readPump reads messages until the connection fails or is closed
+func (h *Hub) readPump(ctx context.Context, c *Conn) {
+	defer func() {
+		c.Close()
+		h.remove(c)
+	}()
+
+	c.conn.SetReadLimit(h.config.MaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(h.config.PongTimeout))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(h.config.PongTimeout))
+	})
+
+	// The request context is cancelled once the handler returns, so keep
+	// connection-scoped values but detach cancellation
+	ctx = context.WithoutCancel(ctx)
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if ws.IsUnexpectedCloseError(err, ws.CloseGoingAway, ws.CloseNormalClosure) && logger.Logger != nil {
+				logger.Debug("Websocket connection closed", zap.String("conn_id", c.ID), zap.Error(err))
+			}
+			return
+		}
+
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.Send(Message{Type: TypeError, Data: json.RawMessage(`"invalid message"`)})
+			continue
+		}
+
+		switch msg.Type {
+		case TypeSubscribe:
+			if h.config.Authorize != nil && !h.config.Authorize(c, msg.Topic) {
+				c.Send(Message{Type: TypeError, Topic: msg.Topic, Data: json.RawMessage(`"subscription not allowed"`)})
+				continue
+			}
+			h.Subscribe(c, msg.Topic)
+		case TypeUnsubscribe:
+			h.Unsubscribe(c, msg.Topic)
+		default:
+			if h.config.OnMessage != nil {
+				h.config.OnMessage(ctx, c, msg)
+			}
+		}
+	}
+}
+
+// writePump writes queued messages and pings until the connection closes
+func (h *Hub) writePump(c *Conn) {
+	ticker := time.NewTicker(h.config.PingInterval)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(h.config.WriteTimeout))
+			if err := c.conn.WriteMessage(ws.TextMessage, data); err != nil {
+				c.Close()
+				return
+			}
+
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(h.config.WriteTimeout))
+			if err := c.conn.WriteMessage(ws.PingMessage, nil); err != nil {
+				c.Close()
+				return
+			}
+
+		case <-c.done:
+			code := ws.CloseNormalClosure
+			h.mu.RLock()
+			if h.closed {
+				code = ws.CloseGoingAway
+			}
+			h.mu.RUnlock()
+			c.conn.WriteControl(ws.CloseMessage, ws.FormatCloseMessage(code, ""), time.Now().Add(h.config.WriteTimeout))
+			return
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ws "github.com/gorilla/websocket"
+)
+
+type orderUpdate struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func dial(t *testing.T, server *httptest.Server, query string) *ws.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + query
+	conn, resp, err := ws.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		t.Fatalf("Dial failed (status %d): %v", status, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHub_PublishToSubscribers(t *testing.T) {
+	hub := NewHub()
+	server := httptest.NewServer(hub)
+	defer server.Close()
+
+	subscriber := dial(t, server, "")
+	other := dial(t, server, "")
+	waitFor(t, func() bool { return hub.Connections() == 2 })
+
+	if err := subscriber.WriteJSON(Message{Type: TypeSubscribe, Topic: "orders:1"}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		hub.mu.RLock()
+		defer hub.mu.RUnlock()
+		return len(hub.topics["orders:1"]) == 1
+	})
+
+	msg, err := NewMessage("order.updated", orderUpdate{ID: "1", Status: "shipped"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := hub.Publish("orders:1", msg); err != nil {
+		t.Fatal(err)
+	}
+
+	var received Message
+	subscriber.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if err := subscriber.ReadJSON(&received); err != nil {
+		t.Fatalf("ReadJSON failed: %v", err)
+	}
+	update, err := Decode[orderUpdate](received)
+	if err != nil || received.Topic != "orders:1" || update.Status != "shipped" {
+		t.Fatalf("Unexpected message %+v (%v)", received, err)
+	}
+
+	// The unsubscribed connection receives nothing
+	other.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, _, err := other.ReadMessage(); err == nil {
+		t.Error("Expected no message for unsubscribed connection")
+	}
+}
+
+func TestHub_Authentication(t *testing.T) {
+	hub := NewHub(WithAuthenticator(func(r *http.Request) (any, error) {
+		if r.URL.Query().Get("token") != "valid" {
+			return nil, errors.New("invalid token")
+		}
+		return "user-1", nil
+	}))
+	server := httptest.NewServer(hub)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	if _, resp, err := ws.DefaultDialer.Dial(url+"?token=bad", nil); err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 for bad token, got %v", err)
+	}
+
+	dial(t, server, "?token=valid")
+	waitFor(t, func() bool { return hub.Connections() == 1 })
+
+	hub.mu.RLock()
+	for c := range hub.conns {
+		if c.Principal != "user-1" {
+			t.Errorf("Expected principal user-1, got %v", c.Principal)
+		}
+	}
+	hub.mu.RUnlock()
+}
+
+func TestHub_Shutdown(t *testing.T) {
+	hub := NewHub()
+	server := httptest.NewServer(hub)
+	defer server.Close()
+
+	client := dial(t, server, "")
+	waitFor(t, func() bool { return hub.Connections() == 1 })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := hub.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := client.ReadMessage()
+	if !ws.IsCloseError(err, ws.CloseGoingAway) {
+		t.Errorf("Expected going-away close, got %v", err)
+	}
+	if err := hub.Broadcast(Message{Type: "late"}); !errors.Is(err, ErrHubClosed) {
+		t.Errorf("Expected ErrHubClosed, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+// Package websocket provides a WebSocket hub for pushing messages from
+// services to connected clients.
+//
+// This package offers a connection hub with topic subscriptions, a typed
+// JSON message envelope encoded via the helpers package, per-connection send
+// buffers that disconnect slow consumers instead of blocking publishers, an
+// authentication hook run before the upgrade, and a Shutdown method for
+// graceful close during service shutdown.
+//
+// Example usage:
+//
+//	hub := websocket.NewHub(
+//		websocket.WithAuthenticator(func(r *http.Request) (any, error) {
+//			return verifier.Verify(r.Context(), r.URL.Query().Get("token"), nil)
+//		}),
+//	)
+//	http.Handle("/ws", hub)
+//
+//	msg, err := websocket.NewMessage("order.updated", order)
+//	hub.Publish("orders:"+order.ID, msg)
+//
+//	// During shutdown
+//	hub.Shutdown(ctx)
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/khekrn/core/helpers"
+)
+
+// Control message types handled by the hub itself
+const (
+	TypeSubscribe   = "subscribe"
+	TypeUnsubscribe = "unsubscribe"
+	TypeError       = "error"
+)
+
+// Message is the JSON envelope exchanged with clients
+type Message struct {
+	Type  string          `json:"type"`            // Message type, e.g. "order.updated"
+	Topic string          `json:"topic,omitempty"` // Topic the message was published to
+	Data  json.RawMessage `json:"data,omitempty"`  // Typed payload
+}
+
+// NewMessage creates a message with data encoded as JSON
+func NewMessage[T any](messageType string, data T) (Message, error) {
+	payload, err := helpers.ToJSON(data)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to encode %s message: %w", messageType, err)
+	}
+	return Message{Type: messageType, Data: payload}, nil
+}
+
+// Decode decodes the message payload into T
+func Decode[T any](msg Message) (T, error) {
+	value, err := helpers.FromJSONValue[T](msg.Data)
+	if err != nil {
+		return value, fmt.Errorf("failed to decode %s message: %w", msg.Type, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,93 @@
+package workerpool
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+)
+
+// Task is a unit of work for a task pool
+type Task func(ctx context.Context) error
+
+// NewTaskPool creates a pool that runs arbitrary tasks, for work that does
+// not share a job type
+//
+// Example:
+//
+//	pool := workerpool.NewTaskPool(4, workerpool.WithJobTimeout(30*time.Second))
+//	pool.Submit(ctx, func(ctx context.Context) error {
+//		return reports.Generate(ctx, reportID)
+//	})
+func NewTaskPool(workers int, options ...Option) *Pool[Task] {
+	return New(workers, func(ctx context.Context, task Task) error {
+		return task(ctx)
+	}, options...)
+}
+
+// Map applies fn to every item with at most workers running at once and
+// returns the results in the order of items. The first error, including a
+// recovered panic as *PanicError, cancels the context of the remaining calls
+// and is returned.
+//
+// Example:
+//
+//	users, err := workerpool.Map(ctx, 8, ids, func(ctx context.Context, id string) (User, error) {
+//		return userService.Get(ctx, id)
+//	})
+func Map[T, R any](ctx context.Context, workers int, items []T, fn func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]R, len(items))
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	slots := make(chan struct{}, workers)
+	for i, item := range items {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			fail(ctx.Err())
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			defer func() {
+				if r := recover(); r != nil {
+					fail(&PanicError{Value: r, Stack: debug.Stack()})
+				}
+			}()
+
+			result, err := fn(ctx, item)
+			if err != nil {
+				fail(err)
+				return
+			}
+			results[i] = result
+		}()
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
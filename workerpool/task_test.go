@@ -0,0 +1,86 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTaskPool(t *testing.T) {
+	var ran atomic.Int64
+	pool := NewTaskPool(2, WithJobTimeout(20*time.Millisecond))
+
+	pool.Submit(context.Background(), func(ctx context.Context) error {
+		ran.Add(1)
+		return nil
+	})
+	timedOut := make(chan error, 1)
+	pool.Submit(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		timedOut <- ctx.Err()
+		return ctx.Err()
+	})
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if ran.Load() != 1 {
+		t.Errorf("Expected the task to run, got %d", ran.Load())
+	}
+	if err := <-timedOut; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the job timeout to cancel the task, got %v", err)
+	}
+	if stats := pool.Stats(); stats.Completed != 1 || stats.Failed != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestMap(t *testing.T) {
+	var running, maxRunning atomic.Int64
+	results, err := Map(context.Background(), 3, []int{1, 2, 3, 4, 5, 6}, func(ctx context.Context, n int) (int, error) {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			seen := maxRunning.Load()
+			if current <= seen || maxRunning.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return n * n, nil
+	})
+	if err != nil {
+		t.Fatalf("Map failed: %v", err)
+	}
+	if !slices.Equal(results, []int{1, 4, 9, 16, 25, 36}) {
+		t.Errorf("Unexpected results %v", results)
+	}
+	if maxRunning.Load() > 3 {
+		t.Errorf("Expected at most 3 concurrent calls, got %d", maxRunning.Load())
+	}
+}
+
+func TestMap_Errors(t *testing.T) {
+	failure := errors.New("lookup failed")
+	_, err := Map(context.Background(), 2, []int{1, 2, 3, 4}, func(ctx context.Context, n int) (int, error) {
+		if n == 2 {
+			return 0, failure
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected the first error, got %v", err)
+	}
+
+	_, err = Map(context.Background(), 2, []string{"a"}, func(ctx context.Context, s string) (int, error) {
+		panic("boom")
+	})
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Expected a PanicError, got %v", err)
+	}
+}
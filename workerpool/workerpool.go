@@ -0,0 +1,354 @@
+// Package workerpool provides a generic, bounded worker pool with per-job
+// retries, panic recovery, graceful draining, and basic metrics.
+//
+// It replaces the ad-hoc goroutine + sync.WaitGroup patterns used to fan out
+// background work, and takes a helpers.RetryPolicy so retry semantics are
+// the same for HTTP calls and queued jobs.
+//
+// Example usage:
+//
+//	pool := workerpool.New(8, func(ctx context.Context, email Email) error {
+//		return mailer.Send(ctx, email)
+//	}, workerpool.WithQueueSize(100), workerpool.WithRetry(helpers.RetryPolicy{
+//		MaxAttempts:    3,
+//		InitialBackoff: 200 * time.Millisecond,
+//		MaxBackoff:     2 * time.Second,
+//		BackoffFactor:  2.0,
+//	}))
+//
+//	for _, email := range emails {
+//		if err := pool.Submit(ctx, email); err != nil {
+//			return err
+//		}
+//	}
+//
+//	// Wait for queued jobs to finish
+//	err := pool.Shutdown(shutdownCtx)
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/khekrn/core/helpers"
+	"github.com/khekrn/core/logger"
+	"go.uber.org/zap"
+)
+
+// ErrPoolClosed is returned when submitting to a pool that is shutting down
+var ErrPoolClosed = errors.New("workerpool: pool is closed")
+
+// ErrQueueFull is returned by TrySubmit when the queue has no free slot
+var ErrQueueFull = errors.New("workerpool: queue is full")
+
+// Handler processes a single job
+type Handler[T any] func(ctx context.Context, job T) error
+
+// Config holds configuration for a worker pool
+type Config struct {
+	Name       string
+	QueueSize  int
+	Retry      *helpers.RetryPolicy
+	OnError    func(err error)
+	JobTimeout time.Duration
+}
+
+// Option is a function type for configuring a pool
+type Option func(*Config)
+
+// WithName sets the pool name used in log entries
+func WithName(name string) Option {
+	return func(config *Config) {
+		config.Name = name
+	}
+}
+
+// WithQueueSize sets the number of jobs that can wait for a free worker
+func WithQueueSize(size int) Option {
+	return func(config *Config) {
+		config.QueueSize = size
+	}
+}
+
+// WithRetry retries failed jobs according to the given policy. Its
+// ShouldRetry classifies errors as for helpers.Retry, so errors marked with
+// helpers.Permanent are not retried, and a job that uses up its attempts
+// fails with helpers.ErrMaxRetriesExceeded wrapping the last error.
+func WithRetry(retry helpers.RetryPolicy) Option {
+	return func(config *Config) {
+		config.Retry = &retry
+	}
+}
+
+// WithErrorHandler sets a callback invoked when a job fails after all attempts
+func WithErrorHandler(onError func(err error)) Option {
+	return func(config *Config) {
+		config.OnError = onError
+	}
+}
+
+// WithJobTimeout limits each attempt of a job to timeout. The handler's
+// context is cancelled when it expires.
+func WithJobTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.JobTimeout = timeout
+	}
+}
+
+// Stats holds a snapshot of pool metrics
+type Stats struct {
+	Submitted int64 // Jobs accepted by the pool
+	Completed int64 // Jobs that finished successfully
+	Failed    int64 // Jobs that failed after all attempts
+	Retries   int64 // Retry attempts made across all jobs
+	Panics    int64 // Panics recovered from handlers
+	InFlight  int64 // Jobs currently being processed
+	Queued    int   // Jobs waiting for a worker
+}
+
+// PanicError wraps a value recovered from a panicking handler
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// Error implements the error interface
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("workerpool: job panicked: %v", e.Value)
+}
+
+// Pool runs jobs of type T on a fixed number of workers
+type Pool[T any] struct {
+	config  Config
+	handler Handler[T]
+	jobs    chan T
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.RWMutex
+	closed  bool
+	closing chan struct{}  // Closed by Shutdown to release blocked submitters
+	senders sync.WaitGroup // Submit calls that may still send to jobs
+
+	submitted atomic.Int64
+	completed atomic.Int64
+	failed    atomic.Int64
+	retries   atomic.Int64
+	panics    atomic.Int64
+	inFlight  atomic.Int64
+}
+
+// New creates a pool with the given number of workers and starts them
+func New[T any](workers int, handler Handler[T], options ...Option) *Pool[T] {
+	if workers < 1 {
+		workers = 1
+	}
+
+	config := Config{
+		Name:      "workerpool",
+		QueueSize: workers,
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool[T]{
+		config:  config,
+		handler: handler,
+		jobs:    make(chan T, config.QueueSize),
+		closing: make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.worker()
+	}
+
+	return p
+}
+
+// Submit queues a job, blocking until a slot is free, the context is done,
+// or the pool shuts down
+func (p *Pool[T]) Submit(ctx context.Context, job T) error {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return ErrPoolClosed
+	}
+	p.senders.Add(1)
+	p.mu.RUnlock()
+	defer p.senders.Done()
+
+	select {
+	case p.jobs <- job:
+		p.submitted.Add(1)
+		return nil
+	case <-p.closing:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TrySubmit queues a job without blocking, returning ErrQueueFull when no slot is free
+func (p *Pool[T]) TrySubmit(job T) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	select {
+	case p.jobs <- job:
+		p.submitted.Add(1)
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Shutdown stops accepting jobs, failing blocked Submit calls with
+// ErrPoolClosed, and waits for queued jobs to drain.
+// If the context expires first, in-flight jobs are cancelled and the
+// context error is returned.
+func (p *Pool[T]) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	first := !p.closed
+	if first {
+		p.closed = true
+		close(p.closing)
+	}
+	p.mu.Unlock()
+
+	if first {
+		// Blocked submitters return once closing is closed, after which
+		// nothing can send to the queue
+		p.senders.Wait()
+		close(p.jobs)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+// Stats returns a snapshot of the pool metrics
+func (p *Pool[T]) Stats() Stats {
+	return Stats{
+		Submitted: p.submitted.Load(),
+		Completed: p.completed.Load(),
+		Failed:    p.failed.Load(),
+		Retries:   p.retries.Load(),
+		Panics:    p.panics.Load(),
+		InFlight:  p.inFlight.Load(),
+		Queued:    len(p.jobs),
+	}
+}
+
+// worker processes jobs until the queue is closed
+func (p *Pool[T]) worker() {
+	defer p.wg.Done()
+
+	for job := range p.jobs {
+		p.inFlight.Add(1)
+		err := p.process(job)
+		p.inFlight.Add(-1)
+
+		if err != nil {
+			p.failed.Add(1)
+			logError("Job failed", zap.String("pool", p.config.Name), zap.Error(err))
+			if p.config.OnError != nil {
+				p.config.OnError(err)
+			}
+			continue
+		}
+		p.completed.Add(1)
+	}
+}
+
+// process runs a job, retrying according to the configured policy
+func (p *Pool[T]) process(job T) error {
+	policy := helpers.RetryPolicy{MaxAttempts: 1}
+	if p.config.Retry != nil {
+		policy = *p.config.Retry
+	}
+	classify := policy.ShouldRetry
+	policy.ShouldRetry = func(err error, attempt int) bool {
+		return p.shouldRetry(classify, err, attempt)
+	}
+
+	attempt := 0
+	_, err := helpers.Retry(p.ctx, policy, func() (struct{}, error) {
+		if attempt++; attempt > 1 {
+			p.retries.Add(1)
+		}
+		return struct{}{}, p.run(job)
+	})
+	return err
+}
+
+// shouldRetry classifies a failed attempt with the policy's classifier,
+// defaulting to helpers.DefaultShouldRetry. Panics are never retried.
+func (p *Pool[T]) shouldRetry(classify func(err error, attempt int) bool, err error, attempt int) bool {
+	// Panics indicate a bug rather than a transient failure
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		return false
+	}
+	if classify != nil {
+		return classify(err, attempt)
+	}
+	// An expired job timeout ends the attempt, not the job
+	if p.config.JobTimeout > 0 && p.ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) && !helpers.IsPermanent(err) {
+		return true
+	}
+	return helpers.DefaultShouldRetry(err, attempt)
+}
+
+// run invokes the handler, converting panics into errors
+func (p *Pool[T]) run(job T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.panics.Add(1)
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+			logError("Recovered from job panic",
+				zap.String("pool", p.config.Name),
+				zap.Any("panic", r),
+				zap.ByteString("stack", err.(*PanicError).Stack),
+			)
+		}
+	}()
+
+	ctx := p.ctx
+	if p.config.JobTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.config.JobTimeout)
+		defer cancel()
+	}
+	return p.handler(ctx, job)
+}
+
+// logError logs through the global logger
+func logError(message string, fields ...zap.Field) {
+	logger.Error(message, fields...)
+}
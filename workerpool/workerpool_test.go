@@ -0,0 +1,190 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/khekrn/core/coretest"
+	"github.com/khekrn/core/helpers"
+)
+
+func TestPool_ProcessesAllJobs(t *testing.T) {
+	var sum atomic.Int64
+	pool := New(4, func(ctx context.Context, n int) error {
+		sum.Add(int64(n))
+		return nil
+	}, WithQueueSize(10))
+
+	for i := 1; i <= 100; i++ {
+		if err := pool.Submit(context.Background(), i); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	if sum.Load() != 5050 {
+		t.Errorf("Expected sum 5050, got %d", sum.Load())
+	}
+
+	stats := pool.Stats()
+	if stats.Completed != 100 {
+		t.Errorf("Expected 100 completed jobs, got %d", stats.Completed)
+	}
+}
+
+func TestPool_RetriesFailedJobs(t *testing.T) {
+	var attempts atomic.Int32
+	var failures atomic.Int32
+	pool := New(1, func(ctx context.Context, _ string) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	}, WithRetry(helpers.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		BackoffFactor:  2.0,
+	}), WithErrorHandler(func(err error) {
+		failures.Add(1)
+	}))
+
+	pool.Submit(context.Background(), "job")
+	pool.Shutdown(context.Background())
+
+	if attempts.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts.Load())
+	}
+	if failures.Load() != 0 {
+		t.Errorf("Expected no failures, got %d", failures.Load())
+	}
+	if pool.Stats().Retries != 2 {
+		t.Errorf("Expected 2 retries, got %d", pool.Stats().Retries)
+	}
+}
+
+func TestPool_DoesNotRetryPermanentErrors(t *testing.T) {
+	invalid := errors.New("invalid job")
+	var attempts atomic.Int32
+	var failed error
+	pool := New(1, func(ctx context.Context, _ string) error {
+		attempts.Add(1)
+		return helpers.Permanent(invalid)
+	}, WithRetry(helpers.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		BackoffFactor:  2.0,
+	}), WithErrorHandler(func(err error) {
+		failed = err
+	}))
+
+	pool.Submit(context.Background(), "job")
+	pool.Shutdown(context.Background())
+
+	if attempts.Load() != 1 {
+		t.Errorf("Expected a single attempt, got %d", attempts.Load())
+	}
+	if !errors.Is(failed, invalid) {
+		t.Errorf("Expected the permanent error, got %v", failed)
+	}
+}
+
+func TestPool_RetryWaitsOnPolicyClock(t *testing.T) {
+	var attempts atomic.Int32
+	clk := coretest.NewClock(time.Time{})
+	pool := New(1, func(ctx context.Context, _ string) error {
+		if attempts.Add(1) < 2 {
+			return errors.New("transient")
+		}
+		return nil
+	}, WithRetry(helpers.RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Hour,
+		BackoffFactor:  2.0,
+		Clock:          clk,
+	}))
+
+	pool.Submit(context.Background(), "job")
+	clk.BlockUntil(1)
+	if attempts.Load() != 1 {
+		t.Fatalf("Expected the retry to wait for the clock, got %d attempts", attempts.Load())
+	}
+	clk.Advance(time.Hour)
+	pool.Shutdown(context.Background())
+
+	if attempts.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestPool_RecoversPanics(t *testing.T) {
+	var failed error
+	pool := New(1, func(ctx context.Context, _ int) error {
+		panic("boom")
+	}, WithErrorHandler(func(err error) {
+		failed = err
+	}))
+
+	pool.Submit(context.Background(), 1)
+	pool.Shutdown(context.Background())
+
+	var panicErr *PanicError
+	if !errors.As(failed, &panicErr) {
+		t.Fatalf("Expected PanicError, got %v", failed)
+	}
+	if pool.Stats().Panics != 1 {
+		t.Errorf("Expected 1 recovered panic, got %d", pool.Stats().Panics)
+	}
+}
+
+func TestPool_SubmitAfterShutdown(t *testing.T) {
+	pool := New(1, func(ctx context.Context, _ int) error { return nil })
+	pool.Shutdown(context.Background())
+
+	if err := pool.Submit(context.Background(), 1); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+}
+
+func TestPool_ShutdownReleasesBlockedSubmit(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	pool := New(1, func(ctx context.Context, _ int) error {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return nil
+	}, WithQueueSize(1))
+
+	// One job occupies the worker and one fills the queue
+	pool.Submit(context.Background(), 1)
+	pool.Submit(context.Background(), 2)
+
+	submitted := make(chan error, 1)
+	go func() {
+		submitted <- pool.Submit(context.Background(), 3)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Shutdown to honour its context, got %v", err)
+	}
+
+	select {
+	case err := <-submitted:
+		if !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Expected ErrPoolClosed for the blocked Submit, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Shutdown to release the blocked Submit")
+	}
+}